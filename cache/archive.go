@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Namespaces validates the names of cached namespaces, the top-level
+// directories of cacheDir such as "npm" or "python", and returns them
+// sorted. No names returns every namespace; hidden directories such as the
+// trash are not namespaces.
+func Namespaces(cacheDir string, names []string) ([]string, error) {
+	if len(names) == 0 {
+		entries, err := os.ReadDir(cacheDir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				names = append(names, e.Name())
+			}
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	namespaces := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("invalid namespace %q", name)
+		}
+		info, err := os.Stat(filepath.Join(cacheDir, name))
+		if err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("namespace %q is not cached: %w", name, ErrNotExist)
+		}
+		namespaces = append(namespaces, name)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// WriteArchive writes every entry of the given namespaces to w as a gzipped
+// tarball of paths relative to cacheDir, keeping their modification times.
+// It returns the number of entries written.
+func WriteArchive(w io.Writer, cacheDir string, namespaces []string) (int, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	count := 0
+	for _, ns := range namespaces {
+		root := filepath.Join(cacheDir, ns)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(cacheDir, path)
+			if err != nil {
+				return err
+			}
+
+			hdr := &tar.Header{
+				Name:    filepath.ToSlash(rel),
+				Mode:    0644,
+				Size:    info.Size(),
+				ModTime: info.ModTime(),
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			if _, err := io.Copy(tw, f); err != nil {
+				return err
+			}
+			count++
+			return nil
+		})
+		if err != nil {
+			return count, fmt.Errorf("failed to archive %s: %w", ns, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return count, err
+	}
+	return count, gz.Close()
+}
+
+// ReadArchive extracts a tarball written by WriteArchive into cacheDir.
+// Entries keep their modification times, so they expire when they would
+// have on the machine they came from, and a local entry at least as new as
+// the archived one is kept. Paths outside a namespace are refused. It
+// returns the number of entries written and kept.
+func ReadArchive(r io.Reader, cacheDir string) (written, kept int, err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return written, kept, nil
+		}
+		if err != nil {
+			return written, kept, fmt.Errorf("invalid archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) || !strings.Contains(hdr.Name, "/") || strings.HasPrefix(hdr.Name, ".") {
+			return written, kept, fmt.Errorf("invalid archive entry %q", hdr.Name)
+		}
+		path := filepath.Join(cacheDir, name)
+
+		if info, err := os.Stat(path); err == nil && !info.ModTime().Before(hdr.ModTime) {
+			kept++
+			continue
+		}
+		if err := writeArchiveEntry(path, tr, hdr.ModTime); err != nil {
+			return written, kept, fmt.Errorf("failed to write %s: %w", hdr.Name, err)
+		}
+		written++
+	}
+}
+
+// writeArchiveEntry writes r to path through a temporary file, so a failed
+// download never leaves a truncated entry behind
+func writeArchiveEntry(path string, r io.Reader, modTime time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".pull-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp.Name(), modTime, modTime); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -2,23 +2,48 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 )
 
-// Manager handles caching operations with TTL support
+const (
+	// lockPollInterval is how often a replica waiting on another's fetch
+	// checks whether its lock has been released
+	lockPollInterval = 250 * time.Millisecond
+)
+
+// Manager handles caching operations with TTL support. Entries are addressed
+// by file paths under the cache directory and stored in a Store, which is the
+// local directory itself unless a shared backend is configured.
 type Manager struct {
 	cacheDir string
 	ttl      time.Duration
+	store    Store
+
+	// trashRetention, when set, moves removed entries to the trash for
+	// this long instead of deleting them
+	trashRetention time.Duration
+
+	// locker, when set, makes replicas run a fetch only once: the first
+	// to claim it holds its lock until the fetch ends
+	locker      Locker
+	lockTimeout time.Duration
 }
 
-// NewManager creates a new cache manager
+// NewManager creates a new cache manager backed by the local cache directory
 func NewManager(cacheDir string, ttl time.Duration) *Manager {
+	return NewManagerWithStore(cacheDir, ttl, NewDiskStore(cacheDir))
+}
+
+// NewManagerWithStore creates a cache manager that keeps entries in store
+func NewManagerWithStore(cacheDir string, ttl time.Duration, store Store) *Manager {
 	return &Manager{
 		cacheDir: cacheDir,
 		ttl:      ttl,
+		store:    store,
 	}
 }
 
@@ -32,29 +57,124 @@ func (m *Manager) GetTTL() time.Duration {
 	return m.ttl
 }
 
+// GetStore returns the storage backend
+func (m *Manager) GetStore() Store {
+	return m.store
+}
+
+// SetLocker enables fetch deduplication across replicas with Claim. A
+// replica waits up to timeout for another's fetch to end before running its
+// own.
+func (m *Manager) SetLocker(locker Locker, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = defaultLockTimeout
+	}
+	m.locker = locker
+	m.lockTimeout = timeout
+}
+
+// key converts a path under the cache directory to a store key
+func (m *Manager) key(filePath string) string {
+	rel, err := filepath.Rel(m.cacheDir, filePath)
+	if err != nil {
+		rel = filepath.Base(filePath)
+	}
+	return filepath.ToSlash(rel)
+}
+
 // IsExpired checks if a file at the given path has expired based on cache TTL
 func (m *Manager) IsExpired(filePath string) (bool, error) {
-	// If TTL is 0, cache never expires
-	if m.ttl == 0 {
+	return m.ExpiredAfter(filePath, m.ttl)
+}
+
+// ExpiredAfter checks if a file at the given path is missing or older than
+// ttl, for entries kept shorter than the cache TTL. A ttl of 0 never expires.
+func (m *Manager) ExpiredAfter(filePath string, ttl time.Duration) (bool, error) {
+	if ttl == 0 {
 		return false, nil
 	}
 
-	// Get file info
-	info, err := os.Stat(filePath)
+	modTime, err := m.store.ModTime(m.key(filePath))
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, ErrNotExist) {
 			return true, nil // File doesn't exist, treat as expired
 		}
 		return false, fmt.Errorf("failed to stat file: %w", err)
 	}
 
 	// Check if file is older than TTL
-	age := time.Since(info.ModTime())
-	return age > m.ttl, nil
+	age := time.Since(modTime)
+	return age > ttl, nil
+}
+
+// ModTime returns when the file at the given path was last written
+func (m *Manager) ModTime(filePath string) (time.Time, error) {
+	return m.store.ModTime(m.key(filePath))
+}
+
+// Fresh reports whether the file at the given path is cached and younger
+// than the cache TTL; with a TTL of 0, whether it is cached at all
+func (m *Manager) Fresh(filePath string) bool {
+	modTime, err := m.store.ModTime(m.key(filePath))
+	if err != nil {
+		return false
+	}
+	return m.ttl == 0 || time.Since(modTime) <= m.ttl
+}
+
+// Claim takes the fetch lock named name for a caller about to run a fetch,
+// so that replicas asked for the same thing run it once. While another
+// replica holds the lock, Claim waits for it to be released, after which the
+// caller usually finds the result in the cache; it stops waiting after the
+// lock timeout. The caller must call release when its fetch ends, whether or
+// not it succeeded. Without a locker, Claim returns at once.
+func (m *Manager) Claim(name string) (release func()) {
+	release = func() {}
+	if m.locker == nil {
+		return release
+	}
+
+	deadline := time.Now().Add(m.lockTimeout)
+	for {
+		token, err := m.locker.TryLock(name, m.lockTimeout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to acquire fetch lock: %v\n", err)
+			return release
+		}
+		if token != "" {
+			return func() {
+				if err := m.locker.Unlock(name, token); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to release fetch lock: %v\n", err)
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return release
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// ReadFile returns the cached contents of a file
+func (m *Manager) ReadFile(filePath string) ([]byte, error) {
+	return m.store.Get(m.key(filePath))
+}
+
+// WriteFile caches the contents of a file
+func (m *Manager) WriteFile(filePath string, data []byte) error {
+	return m.store.Put(m.key(filePath), data)
 }
 
 // Load attempts to load data from cache. Returns true if loaded successfully, false if expired/not found
 func (m *Manager) Load(filePath string, v interface{}) (bool, error) {
+	key := m.key(filePath)
+
+	// Nothing to load if the file was never cached
+	if _, err := m.store.ModTime(key); errors.Is(err, ErrNotExist) {
+		return false, nil
+	}
+
 	// Check if cache exists and is not expired
 	expired, err := m.IsExpired(filePath)
 	if err != nil {
@@ -64,18 +184,18 @@ func (m *Manager) Load(filePath string, v interface{}) (bool, error) {
 	if expired {
 		// Cache is expired, remove it
 		if m.ttl > 0 {
-			fmt.Printf("Cache expired (TTL: %v), removing: %s\n", m.ttl, filepath.Base(filePath))
-			if err := os.Remove(filePath); err != nil {
-				fmt.Printf("Warning: failed to remove expired cache file: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Cache expired (TTL: %v), removing: %s\n", m.ttl, filepath.Base(filePath))
+			if err := m.discard(key); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to remove expired cache file: %v\n", err)
 			}
 		}
 		return false, nil
 	}
 
 	// Try to load from cache
-	data, err := os.ReadFile(filePath)
+	data, err := m.store.Get(key)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, ErrNotExist) {
 			return false, nil
 		}
 		return false, fmt.Errorf("failed to read cache file: %w", err)
@@ -90,38 +210,41 @@ func (m *Manager) Load(filePath string, v interface{}) (bool, error) {
 
 // Save saves data to cache
 func (m *Manager) Save(filePath string, v interface{}) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
-	}
-
 	// Marshal data to JSON
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	// Write to store
+	if err := m.store.Put(m.key(filePath), data); err != nil {
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
 
 	return nil
 }
 
-// Remove removes a cache file
+// Remove removes a cache file, moving it to the trash when one is set
 func (m *Manager) Remove(filePath string) error {
-	return os.Remove(filePath)
+	return m.discard(m.key(filePath))
 }
 
 // Clear removes all cache files in a directory
 func (m *Manager) Clear(dirPath string) error {
-	return os.RemoveAll(dirPath)
+	key := m.key(dirPath)
+	if key == "." {
+		key = ""
+	}
+	return m.store.DeletePrefix(key)
 }
 
-// GetFilePath builds a cache file path within the cache directory
+// GetFilePath builds a cache file path within the cache directory. Each
+// element is sanitized as a single path segment, so the result never
+// escapes the cache directory.
 func (m *Manager) GetFilePath(subpath ...string) string {
-	parts := append([]string{m.cacheDir}, subpath...)
+	parts := []string{m.cacheDir}
+	for _, p := range subpath {
+		parts = append(parts, SanitizeSegment(p))
+	}
 	return filepath.Join(parts...)
 }
@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLocker is an in-memory Locker counting its calls
+type fakeLocker struct {
+	mu     sync.Mutex
+	held   map[string]string
+	next   int
+	tries  int
+	unlock int
+}
+
+func newFakeLocker() *fakeLocker {
+	return &fakeLocker{held: make(map[string]string)}
+}
+
+func (l *fakeLocker) TryLock(key string, ttl time.Duration) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tries++
+	if _, ok := l.held[key]; ok {
+		return "", nil
+	}
+	l.next++
+	token := fmt.Sprintf("token-%d", l.next)
+	l.held[key] = token
+	return token, nil
+}
+
+func (l *fakeLocker) Unlock(key, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.unlock++
+	if l.held[key] == token {
+		delete(l.held, key)
+	}
+	return nil
+}
+
+func (l *fakeLocker) isHeld(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.held[key]
+	return ok
+}
+
+// TestIsExpiredTakesNoLock checks that checking a missing entry leaves the
+// fetch locks alone
+func TestIsExpiredTakesNoLock(t *testing.T) {
+	dir := t.TempDir()
+	locker := newFakeLocker()
+	m := NewManager(dir, time.Hour)
+	m.SetLocker(locker, time.Second)
+
+	expired, err := m.IsExpired(filepath.Join(dir, "npm", "missing.md"))
+	if err != nil || !expired {
+		t.Fatalf("IsExpired(missing) = %v, %v; want true, nil", expired, err)
+	}
+	if locker.tries != 0 {
+		t.Errorf("IsExpired called TryLock %d times", locker.tries)
+	}
+}
+
+// TestFresh checks that only a cached entry within the TTL is fresh, and that
+// the check takes no lock
+func TestFresh(t *testing.T) {
+	dir := t.TempDir()
+	locker := newFakeLocker()
+	m := NewManager(dir, time.Hour)
+	m.SetLocker(locker, time.Second)
+
+	path := filepath.Join(dir, "npm", "express.md")
+	if m.Fresh(path) {
+		t.Error("missing entry is fresh")
+	}
+	if err := m.WriteFile(path, []byte("express")); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Fresh(path) {
+		t.Error("entry written now is not fresh")
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if m.Fresh(path) {
+		t.Error("entry older than the TTL is fresh")
+	}
+	if !NewManager(dir, 0).Fresh(path) {
+		t.Error("entry is not fresh with a TTL of 0")
+	}
+	if locker.tries != 0 {
+		t.Errorf("Fresh called TryLock %d times", locker.tries)
+	}
+}
+
+// TestClaimWaitsForRelease checks that a second claim waits for the first
+// fetch to end, failed or not, rather than for the lock timeout
+func TestClaimWaitsForRelease(t *testing.T) {
+	locker := newFakeLocker()
+	m := NewManager(t.TempDir(), time.Hour)
+	m.SetLocker(locker, 10*time.Second)
+
+	release := m.Claim("fetch:a")
+	if !locker.isHeld("fetch:a") {
+		t.Fatal("Claim did not take the lock")
+	}
+
+	claimed := make(chan func())
+	go func() { claimed <- m.Claim("fetch:a") }()
+
+	select {
+	case <-claimed:
+		t.Fatal("second Claim returned while the lock was held")
+	case <-time.After(2 * lockPollInterval):
+	}
+
+	// The first fetch fails without writing anything
+	release()
+	select {
+	case second := <-claimed:
+		if !locker.isHeld("fetch:a") {
+			t.Error("second Claim returned without the lock")
+		}
+		second()
+	case <-time.After(5 * time.Second):
+		t.Fatal("second Claim still waiting after the lock was released")
+	}
+	if locker.isHeld("fetch:a") {
+		t.Error("lock still held after both releases")
+	}
+}
+
+// TestClaimTimeout checks that a claim stops waiting after the lock timeout
+// and that its release leaves the other holder's lock alone
+func TestClaimTimeout(t *testing.T) {
+	locker := newFakeLocker()
+	m := NewManager(t.TempDir(), time.Hour)
+	m.SetLocker(locker, 300*time.Millisecond)
+
+	if token, _ := locker.TryLock("fetch:b", time.Minute); token == "" {
+		t.Fatal("failed to take the lock for another replica")
+	}
+
+	start := time.Now()
+	release := m.Claim("fetch:b")
+	if waited := time.Since(start); waited < 300*time.Millisecond {
+		t.Errorf("Claim returned after %v, before the lock timeout", waited)
+	}
+	release()
+	if !locker.isHeld("fetch:b") {
+		t.Error("release dropped another replica's lock")
+	}
+}
+
+// TestClaimWithoutLocker checks that Claim is a no-op without a locker
+func TestClaimWithoutLocker(t *testing.T) {
+	m := NewManager(t.TempDir(), time.Hour)
+	m.Claim("fetch:c")()
+}
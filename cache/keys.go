@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// maxNamePrefix bounds the readable part of an entry name, keeping long
+	// Go import paths well under filesystem name limits
+	maxNamePrefix = 80
+
+	// entryHashLen is the number of hex digits of the hash in entry names
+	entryHashLen = 12
+
+	// maxSegment bounds a single path segment; most filesystems allow 255
+	// bytes, and shorter segments leave room under the Windows path limit
+	maxSegment = 120
+)
+
+// windowsReserved are device names Windows refuses as file names, with or
+// without an extension
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// EntryName returns the file name (without extension) for the entry
+// identified by parts, such as a package name and version. It is a readable
+// prefix built from the sanitized parts followed by a hash of the exact
+// parts, so "@types/node" and "_types_node", which sanitize alike, still get
+// different files. The hash is case-sensitive, so names differing only by
+// case do not share a file on case-insensitive filesystems (Windows, macOS).
+// Empty parts are ignored.
+func EntryName(parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+
+	hash := shortHash(strings.Join(kept, "\x00"))
+
+	prefix := sanitize(strings.Join(kept, "_"))
+	prefix = strings.Trim(prefix, "._")
+	if len(prefix) > maxNamePrefix {
+		prefix = strings.TrimRight(prefix[:maxNamePrefix], "._")
+	}
+	if prefix == "" {
+		return hash
+	}
+	return prefix + "-" + hash
+}
+
+// SanitizeSegment makes s safe to use as a single path segment: characters
+// other than letters, digits, '.', '_', '+', and '-' become '_', and names
+// that would escape the directory or that Windows reserves are altered.
+// Overlong segments are shortened and given a hash of the original.
+func SanitizeSegment(s string) string {
+	orig := s
+	s = strings.TrimRight(sanitize(s), ".")
+	if s == "" {
+		return "_"
+	}
+	if len(s) > maxSegment {
+		s = s[:maxSegment-entryHashLen-1] + "-" + shortHash(orig)
+	}
+	base, _, _ := strings.Cut(s, ".")
+	if windowsReserved[strings.ToUpper(base)] {
+		s = "_" + s
+	}
+	return s
+}
+
+func shortHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:entryHashLen]
+}
+
+func sanitize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '.', r == '_', r == '+', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
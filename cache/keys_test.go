@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestEntryNameDistinct checks that names which sanitize alike, or differ
+// only by case, never share a cache file
+func TestEntryNameDistinct(t *testing.T) {
+	groups := [][][]string{
+		{{"@types/node", "20.1.0"}, {"_types_node", "20.1.0"}, {"types/node", "20.1.0"}},
+		{{"React"}, {"react"}, {"REACT"}},
+		{{"a_b", "c"}, {"a", "b_c"}},
+	}
+
+	for _, group := range groups {
+		seen := make(map[string][]string)
+		for _, parts := range group {
+			name := strings.ToLower(EntryName(parts...))
+			if prev, ok := seen[name]; ok {
+				t.Errorf("EntryName(%q) and EntryName(%q) collide as %s", prev, parts, name)
+			}
+			seen[name] = parts
+		}
+	}
+
+	if EntryName("react", "18.2.0") != EntryName("react", "18.2.0") {
+		t.Error("EntryName is not deterministic")
+	}
+}
+
+// TestEntryNameSafe checks that entry names are valid file names everywhere
+func TestEntryNameSafe(t *testing.T) {
+	inputs := [][]string{
+		{"../../etc/passwd"},
+		{"con"},
+		{"NUL", ""},
+		{"name:with*bad?chars<>|\""},
+		{strings.Repeat("github.com/org/very-long-module-path/", 20)},
+		{""},
+	}
+
+	for _, parts := range inputs {
+		name := EntryName(parts...)
+		if name == "" || len(name) > maxSegment {
+			t.Errorf("EntryName(%q) = %q has invalid length %d", parts, name, len(name))
+		}
+		if strings.ContainsAny(name, `/\:*?"<>|`) || strings.HasPrefix(name, ".") {
+			t.Errorf("EntryName(%q) = %q contains unsafe characters", parts, name)
+		}
+		base, _, _ := strings.Cut(name, ".")
+		if windowsReserved[strings.ToUpper(base)] {
+			t.Errorf("EntryName(%q) = %q is a reserved Windows name", parts, name)
+		}
+	}
+}
+
+// TestSanitizeSegment checks segment sanitization
+func TestSanitizeSegment(t *testing.T) {
+	tests := map[string]string{
+		"packages":      "packages",
+		"features.json": "features.json",
+		"..":            "_",
+		".":             "_",
+		"":              "_",
+		"a/b":           "a_b",
+		`a\b`:           "a_b",
+		"CON":           "_CON",
+		"aux.md":        "_aux.md",
+		"com1":          "_com1",
+		"trailing.":     "trailing",
+		"v14.0.0":       "v14.0.0",
+	}
+	for in, want := range tests {
+		if got := SanitizeSegment(in); got != want {
+			t.Errorf("SanitizeSegment(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	long := SanitizeSegment(strings.Repeat("x", 300))
+	if len(long) > maxSegment {
+		t.Errorf("SanitizeSegment did not shorten a %d byte segment (got %d bytes)", 300, len(long))
+	}
+	if long == SanitizeSegment(strings.Repeat("x", 301)) {
+		t.Error("shortened segments of different inputs collide")
+	}
+}
+
+// TestGetFilePathStaysInCacheDir checks that hostile path elements cannot
+// escape the cache directory
+func TestGetFilePathStaysInCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(dir, 0)
+
+	path := m.GetFilePath("npm", "..", "..", "../../etc", "passwd")
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		t.Errorf("GetFilePath escaped the cache directory: %s", path)
+	}
+}
+
+// TestDiskStoreLongPaths writes entries whose full path exceeds the Windows
+// MAX_PATH limit of 260 characters, and entries differing only by case
+func TestDiskStoreLongPaths(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(dir, 0)
+
+	var subpath []string
+	for i := 0; i < 4; i++ {
+		subpath = append(subpath, strings.Repeat("d", 60))
+	}
+	subpath = append(subpath, EntryName(strings.Repeat("github.com/org/module/", 10), "v1.0.0")+".md")
+	long := m.GetFilePath(subpath...)
+	if len(long) <= 260 {
+		t.Fatalf("test path is only %d characters", len(long))
+	}
+
+	if err := m.WriteFile(long, []byte("long")); err != nil {
+		t.Fatalf("WriteFile(long path): %v", err)
+	}
+	data, err := m.ReadFile(long)
+	if err != nil || string(data) != "long" {
+		t.Fatalf("ReadFile(long path) = %q, %v", data, err)
+	}
+	if expired, err := m.IsExpired(long); err != nil || expired {
+		t.Errorf("IsExpired(long path) = %v, %v", expired, err)
+	}
+
+	upper := m.GetFilePath("npm", "packages", EntryName("React")+".md")
+	lower := m.GetFilePath("npm", "packages", EntryName("react")+".md")
+	if err := m.WriteFile(upper, []byte("upper")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile(lower, []byte("lower")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := m.ReadFile(upper); string(data) != "upper" {
+		t.Errorf("entry for %q was overwritten by one differing only by case", "React")
+	}
+
+	if err := m.Clear(dir); err != nil {
+		t.Errorf("Clear: %v", err)
+	}
+}
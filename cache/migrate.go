@@ -0,0 +1,240 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the cache layout this build reads and writes. Bump it and
+// append to migrations whenever the layout or entry format changes.
+const SchemaVersion = 1
+
+// indexFileName is the file at the cache root recording the schema version
+const indexFileName = "index.json"
+
+// Index describes the local cache directory
+type Index struct {
+	SchemaVersion int       `json:"schema_version"`
+	MigratedAt    time.Time `json:"migrated_at,omitempty"`
+}
+
+// Migration upgrades the cache directory from Version-1 to Version
+type Migration struct {
+	Version     int
+	Description string
+	Run         func(cacheDir string) error
+}
+
+// migrations are applied in order to caches older than SchemaVersion
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "rename version entries to hashed cache names and remove unreachable legacy entries",
+		Run:         migrateHashedNames,
+	},
+}
+
+// ReadIndex reads the cache index. A cache without one predates schema
+// versioning and reports version 0.
+func ReadIndex(cacheDir string) (Index, error) {
+	var index Index
+	data, err := os.ReadFile(filepath.Join(cacheDir, indexFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return index, err
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return index, fmt.Errorf("invalid cache index: %w", err)
+	}
+	return index, nil
+}
+
+func writeIndex(cacheDir string, index Index) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(cacheDir, indexFileName), data, 0644)
+}
+
+// Migrate brings the local cache directory up to SchemaVersion. Before the
+// first migration runs, the directory is copied to a sibling backup
+// ("cache.backup-v0-20250101-120000"); if a migration fails, the index keeps
+// the last version that succeeded and the error names the backup.
+func Migrate(cacheDir string) error {
+	index, err := ReadIndex(cacheDir)
+	if err != nil {
+		return err
+	}
+	if index.SchemaVersion > SchemaVersion {
+		return fmt.Errorf("cache schema version %d is newer than this build supports (%d)", index.SchemaVersion, SchemaVersion)
+	}
+	if index.SchemaVersion == SchemaVersion {
+		return nil
+	}
+
+	// A new or empty cache has nothing to upgrade
+	empty, err := isEmptyDir(cacheDir)
+	if err != nil {
+		return err
+	}
+	if empty {
+		return writeIndex(cacheDir, Index{SchemaVersion: SchemaVersion, MigratedAt: time.Now().UTC()})
+	}
+
+	backupDir := fmt.Sprintf("%s.backup-v%d-%s", cacheDir, index.SchemaVersion, time.Now().Format("20060102-150405"))
+	if err := copyDir(cacheDir, backupDir); err != nil {
+		return fmt.Errorf("failed to back up cache to %s: %w", backupDir, err)
+	}
+	fmt.Fprintf(os.Stderr, "Info: Backed up cache to %s before migrating\n", backupDir)
+
+	for _, m := range migrations {
+		if m.Version <= index.SchemaVersion {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Info: Migrating cache to version %d: %s\n", m.Version, m.Description)
+		if err := m.Run(cacheDir); err != nil {
+			return fmt.Errorf("cache migration to version %d failed (backup at %s): %w", m.Version, backupDir, err)
+		}
+		index = Index{SchemaVersion: m.Version, MigratedAt: time.Now().UTC()}
+		if err := writeIndex(cacheDir, index); err != nil {
+			return fmt.Errorf("failed to write cache index: %w", err)
+		}
+	}
+	return nil
+}
+
+func isEmptyDir(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, e := range entries {
+		if e.Name() != indexFileName {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// hashedName matches entry file names produced by EntryName
+var hashedName = regexp.MustCompile(`(^|-)[0-9a-f]{12}\.md$`)
+
+// legacyEntryDirs hold entries whose names were built from package names and
+// versions by replacing "/" with "_". The original name cannot be recovered
+// (and "latest" entries were keyed without a version), so these are removed
+// and refetched on next use.
+var legacyEntryDirs = []string{
+	"ansible/collections",
+	"cocoapods/pods",
+	"conan/recipes",
+	"docker/images",
+	"github-actions/actions",
+	"go/libraries",
+	"helm/charts",
+	"hex/packages",
+	"jenkins/plugins",
+	"nextjs/docs",
+	"npm/packages",
+	"python/packages",
+	"react/api",
+	"ruby/gems",
+	"rust/crates",
+}
+
+// migrateHashedNames moves a pre-versioning cache to EntryName file names.
+// Version entries were named after the exact version, so they are renamed.
+func migrateHashedNames(cacheDir string) error {
+	versionDirs, err := filepath.Glob(filepath.Join(cacheDir, "*", "versions"))
+	if err != nil {
+		return err
+	}
+	for _, dir := range versionDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || !strings.HasSuffix(name, ".md") || hashedName.MatchString(name) {
+				continue
+			}
+			target := filepath.Join(dir, EntryName(strings.TrimSuffix(name, ".md"))+".md")
+			if err := os.Rename(filepath.Join(dir, name), target); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, rel := range legacyEntryDirs {
+		dir := filepath.Join(cacheDir, filepath.FromSlash(rel))
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") || hashedName.MatchString(d.Name()) {
+				return nil
+			}
+			return os.Remove(path)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/incu6us/open-context/config"
+)
+
+const (
+	objectStoreTimeout = 30 * time.Second
+
+	gcsEndpoint = "https://storage.googleapis.com"
+)
+
+// ObjectStore keeps cache entries in an S3-compatible bucket, so several
+// server replicas can share one cache. It speaks the S3 REST API with
+// Signature Version 4, which AWS S3, MinIO, Cloudflare R2, and Google Cloud
+// Storage (through its XML API with HMAC keys) all accept.
+type ObjectStore struct {
+	client    *http.Client
+	endpoint  *url.URL
+	bucket    string
+	region    string
+	prefix    string
+	pathStyle bool
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// NewObjectStore creates an object store from the cache section of config.yaml.
+// Credentials fall back to the standard AWS_* environment variables.
+func NewObjectStore(cfg config.CacheStorage) (*ObjectStore, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("cache bucket is required for the %s backend", cfg.Backend)
+	}
+
+	s := &ObjectStore{
+		client:          &http.Client{Timeout: objectStoreTimeout},
+		bucket:          cfg.Bucket,
+		region:          cfg.Region,
+		prefix:          strings.Trim(cfg.Prefix, "/"),
+		pathStyle:       true,
+		accessKeyID:     firstNonEmpty(cfg.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID")),
+		secretAccessKey: firstNonEmpty(cfg.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY")),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+
+	endpoint := cfg.Endpoint
+	switch {
+	case endpoint == "" && cfg.Backend == "gcs":
+		endpoint = gcsEndpoint
+		if s.region == "" {
+			s.region = "auto"
+		}
+	case endpoint == "":
+		// AWS itself: virtual-hosted style, which new buckets require
+		if s.region == "" {
+			s.region = "us-east-1"
+		}
+		endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", s.bucket, s.region)
+		s.pathStyle = false
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid cache endpoint: %s", endpoint)
+	}
+	s.endpoint = u
+
+	if s.accessKeyID == "" || s.secretAccessKey == "" {
+		return nil, fmt.Errorf("cache credentials are required for the %s backend (set access_key_id and secret_access_key or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)", cfg.Backend)
+	}
+
+	return s, nil
+}
+
+func (s *ObjectStore) objectKey(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+func (s *ObjectStore) Get(key string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, s.objectKey(key), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return io.ReadAll(resp.Body)
+}
+
+func (s *ObjectStore) Put(key string, data []byte) error {
+	resp, err := s.do(http.MethodPut, s.objectKey(key), nil, data)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *ObjectStore) ModTime(key string) (time.Time, error) {
+	resp, err := s.do(http.MethodHead, s.objectKey(key), nil, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	_ = resp.Body.Close()
+
+	modTime, err := http.ParseTime(resp.Header.Get("Last-Modified"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid Last-Modified for %s: %w", key, err)
+	}
+	return modTime, nil
+}
+
+func (s *ObjectStore) Delete(key string) error {
+	resp, err := s.do(http.MethodDelete, s.objectKey(key), nil, nil)
+	if err != nil {
+		if errors.Is(err, ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *ObjectStore) DeletePrefix(prefix string) error {
+	listPrefix := s.objectKey(prefix)
+	if prefix != "" && !strings.HasSuffix(listPrefix, "/") {
+		listPrefix += "/"
+	}
+
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if listPrefix != "" {
+			query.Set("prefix", listPrefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return fmt.Errorf("failed to list cache objects: %w", err)
+		}
+
+		var result struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse cache object list: %w", err)
+		}
+
+		for _, obj := range result.Contents {
+			resp, err := s.do(http.MethodDelete, obj.Key, nil, nil)
+			if err != nil && !errors.Is(err, ErrNotExist) {
+				return err
+			}
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a signed request for an object (or the bucket when objectKey is
+// empty). A 404 is reported as ErrNotExist; other non-2xx statuses as errors.
+func (s *ObjectStore) do(method, objectKey string, query url.Values, body []byte) (*http.Response, error) {
+	u := *s.endpoint
+	path := u.Path
+	if s.pathStyle {
+		path += "/" + s.bucket
+	}
+	if objectKey != "" {
+		path += "/" + objectKey
+	}
+	if path == "" {
+		path = "/"
+	}
+	u.Path = path
+	u.RawPath = escapePath(path)
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body == nil {
+		req.Body = http.NoBody
+	}
+	req.ContentLength = int64(len(body))
+	if method == http.MethodPut {
+		req.Header.Set("Content-Type", "text/markdown; charset=utf-8")
+	}
+
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil, ErrNotExist
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("object store returned status %d for %s %s: %s", resp.StatusCode, method, objectKey, strings.TrimSpace(string(msg)))
+	}
+
+	return resp, nil
+}
+
+// sign adds AWS Signature Version 4 headers to req
+func (s *ObjectStore) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	var names []string
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, strings.TrimSpace(req.Header.Get(name)))
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+// escapePath percent-encodes each path segment as SigV4 requires (RFC 3986
+// unreserved characters are kept)
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery encodes query parameters sorted by name, as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,364 @@
+package cache
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/incu6us/open-context/config"
+)
+
+const (
+	redisKeyPrefix    = "open-context:"
+	redisTimeout      = 5 * time.Second
+	redisMaxIdleConns = 8
+
+	// defaultHotTTL is how long entries stay in Redis when hot_ttl is unset
+	defaultHotTTL = time.Hour
+
+	// defaultLockTimeout bounds how long a replica waits for another one's
+	// fetch; it matches the fetchers' HTTP timeout
+	defaultLockTimeout = 30 * time.Second
+)
+
+// RedisClient is a minimal Redis client speaking RESP over a small pool of
+// connections. It supports just the commands the cache needs.
+type RedisClient struct {
+	addr     string
+	password string
+	db       int
+	idle     chan *redisConn
+}
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// NewRedisClient creates a client for the redis section of config.yaml
+func NewRedisClient(cfg config.RedisConfig) *RedisClient {
+	return &RedisClient{
+		addr:     cfg.Addr,
+		password: cfg.Password,
+		db:       cfg.DB,
+		idle:     make(chan *redisConn, redisMaxIdleConns),
+	}
+}
+
+func (c *RedisClient) conn() (*redisConn, error) {
+	select {
+	case conn := <-c.idle:
+		return conn, nil
+	default:
+	}
+
+	nc, err := net.DialTimeout("tcp", c.addr, redisTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	conn := &redisConn{Conn: nc, r: bufio.NewReader(nc)}
+
+	if c.password != "" {
+		if _, err := conn.do("AUTH", c.password); err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+	}
+	if c.db != 0 {
+		if _, err := conn.do("SELECT", strconv.Itoa(c.db)); err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// do runs a command and returns its reply: string, int64, []byte, nil, or []interface{}
+func (c *RedisClient) do(args ...string) (interface{}, error) {
+	conn, err := c.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.do(args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		// The connection state is unknown after an I/O error
+		_ = conn.Close()
+		return nil, err
+	}
+
+	select {
+	case c.idle <- conn:
+	default:
+		_ = conn.Close()
+	}
+	return reply, err
+}
+
+func (conn *redisConn) do(args ...string) (interface{}, error) {
+	if err := conn.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	return conn.readReply()
+}
+
+func (conn *redisConn) readReply() (interface{}, error) {
+	line, err := conn.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(conn.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid array length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = conn.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+// Get returns the value of key, or nil if it does not exist
+func (c *RedisClient) Get(key string) ([]byte, error) {
+	reply, err := c.do("GET", redisKeyPrefix+key)
+	if err != nil {
+		return nil, err
+	}
+	value, _ := reply.([]byte)
+	return value, nil
+}
+
+// Set stores value under key for ttl
+func (c *RedisClient) Set(key string, value []byte, ttl time.Duration) error {
+	_, err := c.do("SET", redisKeyPrefix+key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// Del removes keys
+func (c *RedisClient) Del(keys ...string) error {
+	args := []string{"DEL"}
+	for _, k := range keys {
+		args = append(args, redisKeyPrefix+k)
+	}
+	_, err := c.do(args...)
+	return err
+}
+
+// DelPrefix removes every key starting with prefix
+func (c *RedisClient) DelPrefix(prefix string) error {
+	cursor := "0"
+	for {
+		reply, err := c.do("SCAN", cursor, "MATCH", redisKeyPrefix+prefix+"*", "COUNT", "500")
+		if err != nil {
+			return err
+		}
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return fmt.Errorf("redis: unexpected SCAN reply")
+		}
+		next, _ := parts[0].([]byte)
+		keys, _ := parts[1].([]interface{})
+
+		if len(keys) > 0 {
+			args := []string{"DEL"}
+			for _, k := range keys {
+				if key, ok := k.([]byte); ok {
+					args = append(args, string(key))
+				}
+			}
+			if _, err := c.do(args...); err != nil {
+				return err
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// RedisStore keeps recently used entries in Redis in front of another Store,
+// so replicas read hot documentation from memory instead of the object store.
+// Redis failures fall back to the underlying store.
+type RedisStore struct {
+	Store
+	client *RedisClient
+	ttl    time.Duration
+}
+
+// NewRedisStore wraps store with a Redis layer holding entries for ttl
+func NewRedisStore(store Store, client *RedisClient, ttl time.Duration) *RedisStore {
+	if ttl <= 0 {
+		ttl = defaultHotTTL
+	}
+	return &RedisStore{Store: store, client: client, ttl: ttl}
+}
+
+// Entries are stored as an 8-byte modification time followed by the data
+func entryKey(key string) string {
+	return "entry:" + key
+}
+
+func (s *RedisStore) cache(key string, data []byte, modTime time.Time) {
+	value := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(data)), uint64(modTime.UnixNano()))
+	if err := s.client.Set(entryKey(key), append(value, data...), s.ttl); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache entry in redis: %v\n", err)
+	}
+}
+
+func (s *RedisStore) Get(key string) ([]byte, error) {
+	if value, err := s.client.Get(entryKey(key)); err == nil && len(value) >= 8 {
+		return value[8:], nil
+	}
+
+	data, err := s.Store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if modTime, err := s.Store.ModTime(key); err == nil {
+		s.cache(key, data, modTime)
+	}
+	return data, nil
+}
+
+func (s *RedisStore) Put(key string, data []byte) error {
+	if err := s.Store.Put(key, data); err != nil {
+		return err
+	}
+	s.cache(key, data, time.Now())
+	return nil
+}
+
+func (s *RedisStore) ModTime(key string) (time.Time, error) {
+	// GETRANGE reads only the timestamp, not the whole entry
+	reply, err := s.client.do("GETRANGE", redisKeyPrefix+entryKey(key), "0", "7")
+	if value, ok := reply.([]byte); err == nil && ok && len(value) == 8 {
+		return time.Unix(0, int64(binary.BigEndian.Uint64(value))), nil
+	}
+	return s.Store.ModTime(key)
+}
+
+func (s *RedisStore) Delete(key string) error {
+	if err := s.client.Del(entryKey(key)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove entry from redis: %v\n", err)
+	}
+	return s.Store.Delete(key)
+}
+
+func (s *RedisStore) DeletePrefix(prefix string) error {
+	match := prefix
+	if match != "" {
+		match += "/"
+	}
+	if err := s.client.DelPrefix(entryKey(match)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove entries from redis: %v\n", err)
+	}
+	return s.Store.DeletePrefix(prefix)
+}
+
+// Locker provides locks shared by all server replicas
+type Locker interface {
+	// TryLock acquires the lock for key, held until Unlock or ttl passes.
+	// It returns an empty token when another holder has the lock.
+	TryLock(key string, ttl time.Duration) (token string, err error)
+	// Unlock releases the lock if it is still held with token
+	Unlock(key, token string) error
+}
+
+// unlockScript deletes the lock only if it still holds our token, so a lock
+// that expired and was taken by another replica is left alone
+const unlockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+// RedisLocker implements Locker with SET NX locks
+type RedisLocker struct {
+	client *RedisClient
+}
+
+// NewRedisLocker creates a Locker backed by client
+func NewRedisLocker(client *RedisClient) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+func (l *RedisLocker) TryLock(key string, ttl time.Duration) (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf[:])
+
+	reply, err := l.client.do("SET", redisKeyPrefix+"lock:"+key, token, "NX", "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", nil
+	}
+	return token, nil
+}
+
+func (l *RedisLocker) Unlock(key, token string) error {
+	_, err := l.client.do("EVAL", unlockScript, "1", redisKeyPrefix+"lock:"+key, token)
+	return err
+}
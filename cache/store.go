@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/incu6us/open-context/config"
+)
+
+// ErrNotExist is returned by a Store for keys that have not been cached
+var ErrNotExist = fs.ErrNotExist
+
+// Store is the storage backend behind a cache Manager. Keys are
+// slash-separated paths relative to the cache root ("npm/packages/react.md").
+type Store interface {
+	// Get returns the data stored under key
+	Get(key string) ([]byte, error)
+	// Put stores data under key, replacing any previous value
+	Put(key string, data []byte) error
+	// ModTime returns when key was last written
+	ModTime(key string) (time.Time, error)
+	// Delete removes key; deleting a missing key is not an error
+	Delete(key string) error
+	// DeletePrefix removes every key under prefix ("" removes everything)
+	DeletePrefix(prefix string) error
+}
+
+// NewStore creates the Store selected by the cache section of config.yaml.
+// The disk store, rooted at cacheDir, is used when no backend is configured.
+func NewStore(cfg config.CacheStorage, cacheDir string) (Store, error) {
+	switch cfg.Backend {
+	case "", "disk":
+		return NewDiskStore(cacheDir), nil
+	case "s3", "gcs":
+		return NewObjectStore(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported cache backend: %s (must be 'disk', 's3', or 'gcs')", cfg.Backend)
+	}
+}
+
+// DiskStore keeps cache entries as files under a local directory
+type DiskStore struct {
+	root string
+}
+
+// NewDiskStore creates a store rooted at dir. The root is made absolute
+// because Go only lifts the Windows MAX_PATH limit for absolute paths, and
+// deep entries under a long home directory can exceed 260 characters.
+func NewDiskStore(dir string) *DiskStore {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return &DiskStore{root: dir}
+}
+
+func (s *DiskStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+func (s *DiskStore) Get(key string) ([]byte, error) {
+	return os.ReadFile(s.path(key))
+}
+
+func (s *DiskStore) Put(key string, data []byte) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (s *DiskStore) ModTime(key string) (time.Time, error) {
+	info, err := os.Stat(s.path(key))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+func (s *DiskStore) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *DiskStore) DeletePrefix(prefix string) error {
+	return os.RemoveAll(s.path(prefix))
+}
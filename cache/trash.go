@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrashDir is the directory under the cache root holding entries removed on
+// TTL expiry while a trash retention is configured. Trashed entries keep
+// their key below it, and their modification time is when they were trashed.
+const TrashDir = ".trash"
+
+// TrashEntry is an entry waiting in the trash to be restored or purged
+type TrashEntry struct {
+	Key       string
+	Size      int64
+	TrashedAt time.Time
+}
+
+// SetTrash keeps the entries the manager removes in the trash for retention
+// instead of deleting them, so they can be restored. Only the local disk
+// cache has a trash; entries of other backends are still deleted.
+func (m *Manager) SetTrash(retention time.Duration) {
+	m.trashRetention = retention
+}
+
+// discard removes key, moving it to the trash when one is configured
+func (m *Manager) discard(key string) error {
+	if m.trashRetention > 0 {
+		if _, ok := m.store.(*DiskStore); ok {
+			return MoveToTrash(m.cacheDir, key)
+		}
+	}
+	return m.store.Delete(key)
+}
+
+// MoveToTrash moves the entry at key into the trash of cacheDir, replacing
+// an earlier trashed copy. Moving a missing entry is not an error.
+func MoveToTrash(cacheDir, key string) error {
+	src := filepath.Join(cacheDir, filepath.FromSlash(key))
+	dst := filepath.Join(cacheDir, TrashDir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to move %s to the trash: %w", key, err)
+	}
+	now := time.Now()
+	return os.Chtimes(dst, now, now)
+}
+
+// ListTrash returns the entries in the trash of cacheDir, oldest first
+func ListTrash(cacheDir string) ([]TrashEntry, error) {
+	root := filepath.Join(cacheDir, TrashDir)
+	var entries []TrashEntry
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, TrashEntry{Key: filepath.ToSlash(rel), Size: info.Size(), TrashedAt: info.ModTime()})
+		return nil
+	})
+	sort.Slice(entries, func(i, j int) bool { return entries[i].TrashedAt.Before(entries[j].TrashedAt) })
+	return entries, err
+}
+
+// RestoreTrash moves the trashed entries whose keys start with one of
+// prefixes, or every trashed entry when none are given, back into the cache.
+// Restored entries count as freshly fetched, so they are served until the
+// cache TTL passes again. An entry fetched again since it was trashed is
+// kept and its trashed copy left in place. It returns the restored keys.
+func RestoreTrash(cacheDir string, prefixes []string) ([]string, error) {
+	entries, err := ListTrash(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored []string
+	for _, e := range entries {
+		if !hasAnyPrefix(e.Key, prefixes) {
+			continue
+		}
+		src := filepath.Join(cacheDir, TrashDir, filepath.FromSlash(e.Key))
+		dst := filepath.Join(cacheDir, filepath.FromSlash(e.Key))
+		if _, err := os.Stat(dst); err == nil {
+			fmt.Fprintf(os.Stderr, "Skipping %s: fetched again since it was trashed\n", e.Key)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return restored, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+		if err := os.Rename(src, dst); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", e.Key, err)
+		}
+		now := time.Now()
+		if err := os.Chtimes(dst, now, now); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", e.Key, err)
+		}
+		removeEmptyParents(filepath.Dir(src), filepath.Join(cacheDir, TrashDir))
+		restored = append(restored, e.Key)
+	}
+	return restored, nil
+}
+
+// PurgeTrash deletes the entries trashed more than retention ago and
+// returns how many it deleted
+func PurgeTrash(cacheDir string, retention time.Duration) (int, error) {
+	entries, err := ListTrash(cacheDir)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, e := range entries {
+		if time.Since(e.TrashedAt) <= retention {
+			continue
+		}
+		path := filepath.Join(cacheDir, TrashDir, filepath.FromSlash(e.Key))
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return purged, err
+		}
+		removeEmptyParents(filepath.Dir(path), filepath.Join(cacheDir, TrashDir))
+		purged++
+	}
+	return purged, nil
+}
+
+// hasAnyPrefix reports whether key starts with one of prefixes; no prefixes
+// match every key
+func hasAnyPrefix(key string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(key, strings.TrimPrefix(filepath.ToSlash(p), "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeEmptyParents removes dir and its parents up to, but not including,
+// root for as long as they are empty
+func removeEmptyParents(dir, root string) {
+	for dir != root && strings.HasPrefix(dir, root) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -16,11 +16,248 @@ import (
 var (
 	globalConfig *Config
 	configOnce   sync.Once
+	// loadedPath is the config file Load read, empty when defaults are used
+	loadedPath string
 )
 
 // Config represents the application configuration
 type Config struct {
 	CacheTTL Duration `yaml:"cache_ttl"`
+
+	// CacheTrash keeps cache entries removed on TTL expiry in a trash
+	// directory for this long, restorable with open-context cache restore,
+	// instead of deleting them (default 0, delete at once)
+	CacheTrash Duration `yaml:"cache_trash"`
+
+	// CacheDir is where fetched documentation is stored (default
+	// ~/.open-context/cache)
+	CacheDir string `yaml:"cache_dir"`
+
+	// GitHubToken and GitLabToken authenticate API requests, raising rate
+	// limits and reaching private projects. They fall back to GITHUB_TOKEN
+	// and GITLAB_TOKEN.
+	GitHubToken string `yaml:"github_token"`
+	GitLabToken string `yaml:"gitlab_token"`
+
+	// BufToken authenticates Buf Schema Registry requests, reaching private
+	// modules. It falls back to BUF_TOKEN.
+	BufToken string `yaml:"buf_token"`
+
+	// StackExchangeKey is a Stack Apps key raising the Stack Exchange API
+	// quota from 300 to 10,000 requests a day. It falls back to
+	// STACKEXCHANGE_KEY.
+	StackExchangeKey string `yaml:"stackexchange_key"`
+
+	// EnabledTools limits the tools offered to MCP clients and the REST API
+	// to those listed (e.g., open-context_search_docs); empty enables all
+	EnabledTools []string `yaml:"enabled_tools"`
+
+	// HideDeprecatedTools leaves deprecated tools out of tool listings.
+	// They can still be called, so client configurations naming them keep
+	// working until the tools are removed.
+	HideDeprecatedTools bool `yaml:"hide_deprecated_tools"`
+
+	// WatchManifests lists dependency manifests, or project directories containing
+	// them, to watch so docs for newly added dependencies are prefetched
+	WatchManifests []string `yaml:"watch_manifests"`
+
+	// DevDocs lists DevDocs docsets (e.g., python~3.12, react) indexed in
+	// the background at startup, so they are searchable without a
+	// get_devdocs call, and refreshed when DevDocs publishes a newer build
+	DevDocs []string `yaml:"devdocs"`
+
+	// GoWorkspace is a local Go module or go.work root whose symbols
+	// get_local_symbol documents via gopls
+	GoWorkspace string `yaml:"go_workspace"`
+
+	// ImportRoots are the directories, with their subdirectories, that the
+	// import_docs tool may import. When empty, the tool imports nothing;
+	// the open-context import command is not limited.
+	ImportRoots []string `yaml:"import_roots"`
+
+	// Cache selects where fetched documentation is stored
+	Cache CacheStorage `yaml:"cache"`
+
+	// Redis optionally adds a shared hot cache and fetch locks
+	Redis RedisConfig `yaml:"redis"`
+
+	// SecurityAdvisories adds known GitHub security advisories to Go, npm,
+	// Python, and Rust package info
+	SecurityAdvisories bool `yaml:"security_advisories"`
+
+	// GoDocsSource selects where get_go_info library docs come from:
+	// "pkgsite" (default) scrapes pkg.go.dev, "module_zip" renders them
+	// locally from the module source on the Go module proxy
+	GoDocsSource string `yaml:"go_docs_source"`
+
+	// SemanticSearch optionally ranks search_docs results by embedding
+	// similarity as well as keywords
+	SemanticSearch SemanticSearch `yaml:"semantic_search"`
+
+	// Summarizer optionally has an LLM write the summaries get_docs
+	// returns for summary=true instead of extracting them
+	Summarizer Summarizer `yaml:"summarizer"`
+
+	// SearchWeights multiplies the search_docs scores of documentations,
+	// keyed by documentation name or prefix pattern (e.g., local-*: 2), so
+	// internal docs can outrank public ones; unlisted documentations keep 1
+	SearchWeights map[string]float64 `yaml:"search_weights"`
+
+	// DisableRecencyBoost ranks the versions of a product in search_docs by
+	// their match alone, instead of raising newer versions above older ones
+	DisableRecencyBoost bool `yaml:"disable_recency_boost"`
+
+	// SearchVersionRetention is how many of the newest versions of each
+	// product stay searchable (default 5); older release notes remain
+	// cached and indexed for get_docs and the version tools. A negative
+	// value keeps every version.
+	SearchVersionRetention int `yaml:"search_version_retention"`
+
+	// GitLabURL is the GitLab instance get_gitlab_project uses for project
+	// paths without a host (default https://gitlab.com)
+	GitLabURL string `yaml:"gitlab_url"`
+
+	// Mirrors lists fallback base URLs for upstreams, keyed by the upstream's
+	// host or URL prefix and tried in order when it fails (e.g.,
+	// proxy.golang.org: [https://goproxy.cn])
+	Mirrors map[string][]string `yaml:"mirrors"`
+
+	// SSE bounds the events the HTTP transport queues for each SSE client
+	SSE SSEConfig `yaml:"sse"`
+
+	// CORS sets which browser origins may call the HTTP transport
+	CORS CORSConfig `yaml:"cors"`
+
+	// AllowedHosts lists the Host headers the HTTP transport answers
+	// besides loopback names (e.g., docs.example.com, *.example.com). When
+	// empty, a server listening on localhost answers only loopback names
+	// and one listening on another address answers any.
+	AllowedHosts []string `yaml:"allowed_hosts"`
+
+	// AdminToken enables the admin API of the HTTP transport, which other
+	// machines seed their cache from with open-context cache pull, and is
+	// the bearer token it requires. It falls back to
+	// OPEN_CONTEXT_ADMIN_TOKEN.
+	AdminToken string `yaml:"admin_token"`
+
+	// CustomSources adds an open-context_get_<name>_info tool for each
+	// documentation source listed, such as an internal product's releases,
+	// package registry, or docs site
+	CustomSources []CustomSource `yaml:"custom_sources"`
+}
+
+// CustomSource is a documentation source declared in config.yaml instead
+// of code
+type CustomSource struct {
+	// Name makes the tool open-context_get_<name>_info; lowercase letters,
+	// digits, and underscores, starting with a letter
+	Name string `yaml:"name"`
+	// Description is the tool description shown to clients
+	Description string `yaml:"description"`
+	// Type is "github-release", "registry", or "sitemap"
+	Type string `yaml:"type"`
+	// BaseURL is the GitHub repository (github-release), the JSON package
+	// API URL with {name} and {version} placeholders (registry), or the
+	// docs site to crawl (sitemap)
+	BaseURL string `yaml:"base_url"`
+	// Selectors pick a registry response's fields by dotted JSON path
+	// (e.g., version: info.version), or a sitemap page's title and content
+	// by CSS selector (e.g., content: div.docs-body)
+	Selectors map[string]string `yaml:"selectors"`
+	// Headers are sent with registry and sitemap requests; $VAR and ${VAR}
+	// are expanded from the environment (e.g., Authorization: Bearer $TOKEN)
+	Headers map[string]string `yaml:"headers"`
+	// MaxPages caps the pages crawled from a sitemap source (default 100)
+	MaxPages int `yaml:"max_pages"`
+}
+
+// CORSConfig configures the CORS headers of the HTTP transport. The
+// default allows any origin without credentials on the MCP endpoints and
+// none on the REST API.
+type CORSConfig struct {
+	// AllowedOrigins lists origins such as "https://app.example.com";
+	// "https://*.example.com" allows its subdomains and "*" any origin
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	// AllowCredentials lets allowed origins send cookies and
+	// Authorization headers
+	AllowCredentials bool `yaml:"allow_credentials"`
+	// AllowedHeaders adds request headers browsers may send, besides
+	// Content-Type, Mcp-Session-Id, and Last-Event-ID
+	AllowedHeaders []string `yaml:"allowed_headers"`
+}
+
+// SSEConfig bounds the events queued for an SSE client that has not
+// received them yet, so a stalled client cannot hold unbounded memory
+type SSEConfig struct {
+	// QueueEvents caps the undelivered events of a client (default 1024)
+	QueueEvents int `yaml:"queue_events"`
+	// QueueBytes caps their total size (default 16 MiB)
+	QueueBytes int `yaml:"queue_bytes"`
+	// DropPolicy is what happens to an event that does not fit:
+	// "drop_oldest" (default) drops the oldest undelivered events,
+	// "drop_newest" drops the new event, and "disconnect" closes the client
+	DropPolicy string `yaml:"drop_policy"`
+}
+
+// SemanticSearch configures the remote embeddings API used for semantic
+// search. Leaving Provider empty disables it.
+type SemanticSearch struct {
+	// Provider is "openai" or "voyage"
+	Provider string `yaml:"provider"`
+	// Model defaults to text-embedding-3-small (openai) or voyage-3-lite (voyage)
+	Model string `yaml:"model"`
+	// APIKey falls back to OPENAI_API_KEY or VOYAGE_API_KEY
+	APIKey string `yaml:"api_key"`
+	// Endpoint overrides the API URL for OpenAI-compatible services
+	Endpoint string `yaml:"endpoint"`
+	// BatchSize is how many texts are embedded per request (default 64)
+	BatchSize int `yaml:"batch_size"`
+}
+
+// Summarizer configures the remote LLM API used for topic summaries.
+// Leaving Provider empty uses the built-in extractive summary.
+type Summarizer struct {
+	// Provider is "anthropic" or "openai"
+	Provider string `yaml:"provider"`
+	// Model defaults to claude-haiku-4-5 (anthropic) or gpt-4o-mini (openai)
+	Model string `yaml:"model"`
+	// APIKey falls back to ANTHROPIC_API_KEY or OPENAI_API_KEY
+	APIKey string `yaml:"api_key"`
+	// Endpoint overrides the API URL for OpenAI-compatible services
+	Endpoint string `yaml:"endpoint"`
+	// MaxTokens bounds the length of a summary (default 512)
+	MaxTokens int `yaml:"max_tokens"`
+}
+
+// RedisConfig configures the optional Redis layer. It keeps hot cache
+// entries and search results in memory and makes replicas fetch an uncached
+// package once between them. Leaving Addr empty disables it.
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+	// HotTTL is how long entries and search results stay in Redis (default 1h)
+	HotTTL Duration `yaml:"hot_ttl"`
+	// LockTimeout is how long a replica waits for another's fetch (default 30s)
+	LockTimeout Duration `yaml:"lock_timeout"`
+}
+
+// CacheStorage configures the cache backend. The default local disk cache
+// suits a single server; an object store lets replicas behind a load
+// balancer share one cache.
+type CacheStorage struct {
+	// Backend is "disk" (default), "s3" for any S3-compatible store, or "gcs"
+	Backend string `yaml:"backend"`
+	// Endpoint overrides the service URL (e.g., MinIO or R2); empty uses
+	// AWS S3 or storage.googleapis.com
+	Endpoint string `yaml:"endpoint"`
+	Bucket   string `yaml:"bucket"`
+	Region   string `yaml:"region"`
+	// Prefix is prepended to every object key
+	Prefix string `yaml:"prefix"`
+	// Credentials fall back to AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
 }
 
 // Duration is a custom type that supports parsing durations like "7d", "1w", etc.
@@ -92,6 +329,17 @@ func Load() (*Config, error) {
 	return globalConfig, err
 }
 
+// DefaultPath returns ~/.open-context/config.yaml, the config file read
+// when the current directory has no config.yaml and the one open-context
+// init writes
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".open-context", "config.yaml"), nil
+}
+
 // loadConfig reads and parses the config.yaml file
 func loadConfig() (*Config, error) {
 	// Default configuration
@@ -104,26 +352,14 @@ func loadConfig() (*Config, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		// Try ~/.open-context/config.yaml
-		homeDir, homeErr := os.UserHomeDir()
-		if homeErr == nil {
-			configPath = filepath.Join(homeDir, ".open-context", "config.yaml")
+		if defaultPath, pathErr := DefaultPath(); pathErr == nil {
+			configPath = defaultPath
 			data, err = os.ReadFile(configPath)
-
-			// If not found in home directory, create a default one
-			if err != nil && os.IsNotExist(err) {
-				if createErr := createDefaultConfig(configPath); createErr != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to create default config: %v\n", createErr)
-					fmt.Fprintf(os.Stderr, "Info: Using default configuration (cache_ttl: %v)\n", cfg.CacheTTL.Duration)
-					return cfg, nil
-				}
-				// Try reading the newly created config
-				data, err = os.ReadFile(configPath)
-			}
 		}
 
 		// If still not found, use defaults
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Info: Using default configuration (cache_ttl: %v)\n", cfg.CacheTTL.Duration)
+			fmt.Fprintf(os.Stderr, "Info: Using default configuration (cache_ttl: %v); run 'open-context init' to create a config file\n", cfg.CacheTTL.Duration)
 			return cfg, nil
 		}
 	}
@@ -134,58 +370,21 @@ func loadConfig() (*Config, error) {
 	}
 
 	fmt.Fprintf(os.Stderr, "Info: Loaded configuration from %s (cache_ttl: %v)\n", configPath, cfg.CacheTTL.Duration)
+	loadedPath = configPath
 	return cfg, nil
 }
 
-// createDefaultConfig creates a default config.yaml file at the specified path
-func createDefaultConfig(configPath string) error {
-	// Ensure directory exists
-	dir := filepath.Dir(configPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
-	// Check if config already exists
-	if _, err := os.Stat(configPath); err == nil {
-		return nil // Config already exists, don't overwrite
-	}
-
-	// Default config content
-	defaultConfig := `# Open Context Configuration File
-# Generated automatically on first run
-
-# Cache TTL (Time To Live) - How long cached data should be kept
-# Supported formats:
-#   - "0" or empty: No expiration (cache never expires)
-#   - "7d": 7 days (default)
-#   - "24h": 24 hours
-#   - "30m": 30 minutes
-#   - "1w": 1 week (same as 7d)
-#
-# If a cached file is older than cache_ttl, it will be automatically
-# removed and fresh data will be fetched from the source.
-#
-# Examples:
-#   cache_ttl: 0      # Never expire
-#   cache_ttl: 7d     # Expire after 7 days (recommended)
-#   cache_ttl: 24h    # Expire after 24 hours
-#   cache_ttl: 1w     # Expire after 1 week
-
-cache_ttl: 7d
-`
-
-	// Write config file
-	if err := os.WriteFile(configPath, []byte(defaultConfig), 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
-	}
-
-	fmt.Fprintf(os.Stderr, "Info: Created default config at %s\n", configPath)
-	return nil
+// LoadedPath returns the config file Load read, or "" when no config file
+// was found and the defaults are in use
+func LoadedPath() string {
+	_, _ = Load()
+	return loadedPath
 }
 
 // GetCacheDir returns the cache directory path for open-context.
 // It creates the directory if it doesn't exist.
-// The cache directory is located at ~/.open-context/cache on all platforms.
+// The cache directory is cache_dir from config.yaml, or else
+// ~/.open-context/cache on all platforms.
 func GetCacheDir() (string, error) {
 	// Get user's home directory (works on macOS, Windows, Linux)
 	homeDir, err := os.UserHomeDir()
@@ -195,6 +394,12 @@ func GetCacheDir() (string, error) {
 
 	// Build cache directory path: ~/.open-context/cache
 	cacheDir := filepath.Join(homeDir, ".open-context", "cache")
+	if cfg, err := Load(); err == nil && cfg.CacheDir != "" {
+		cacheDir = cfg.CacheDir
+		if rest, ok := strings.CutPrefix(cacheDir, "~/"); ok {
+			cacheDir = filepath.Join(homeDir, rest)
+		}
+	}
 
 	// Create the directory if it doesn't exist
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
@@ -204,6 +409,23 @@ func GetCacheDir() (string, error) {
 	return cacheDir, nil
 }
 
+// GetLogPath returns ~/.open-context/logs/open-context.log, the log file
+// the server writes and open-context report reads. It creates the directory
+// if it doesn't exist.
+func GetLogPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	logDir := filepath.Join(homeDir, ".open-context", "logs")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	return filepath.Join(logDir, "open-context.log"), nil
+}
+
 // GetDataDir returns the data directory path within the cache.
 // This is an alias for GetCacheDir for backward compatibility.
 func GetDataDir() (string, error) {
@@ -0,0 +1,387 @@
+// Package crawler reads documentation sites through their sitemaps: it
+// lists the pages under a base URL, fetches them politely, and converts
+// each page's main content to markdown.
+package crawler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// userAgent identifies the crawler to sites and their robots.txt
+	userAgent = "open-context-mcp-server"
+
+	// DefaultMaxPages is the page limit when none is given
+	DefaultMaxPages = 100
+
+	// maxPageBytes bounds one page
+	maxPageBytes = 8 << 20
+
+	// workers is how many pages are fetched at once
+	workers = 4
+)
+
+var errNotFound = errors.New("not found")
+
+// Page is a crawled page converted to markdown
+type Page struct {
+	URL         string
+	Title       string
+	Description string
+	Content     string
+	// Section is the first path segment below the base URL, e.g. "guides"
+	// for https://example.com/docs/guides/install under /docs
+	Section string
+}
+
+// Result is what a crawl found
+type Result struct {
+	// Base is the normalized base URL; only pages under it are crawled
+	Base  string
+	Pages []Page
+	// Listed is how many pages under the base the sitemaps named, up to
+	// the page limit
+	Listed int
+	// Failed counts pages that could not be fetched or had no content
+	Failed int
+}
+
+// Selectors override where pages keep their title and content. Nil
+// selectors, and selectors matching nothing on a page, keep the defaults.
+type Selectors struct {
+	// Title picks the element whose text is the page title instead of the
+	// first h1 or the <title>
+	Title *Selector
+	// Content picks the element holding the page content instead of its
+	// <main>, <article>, or body
+	Content *Selector
+}
+
+// Crawler crawls one documentation site
+type Crawler struct {
+	// Selectors customize how pages are read
+	Selectors Selectors
+
+	client *http.Client
+	base   *url.URL
+	limit  int
+}
+
+// New returns a crawler for the pages under baseURL, fetching at most
+// maxPages of them (DefaultMaxPages when maxPages is not positive)
+func New(client *http.Client, baseURL string, maxPages int) (*Crawler, error) {
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+	base, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil || base.Host == "" || (base.Scheme != "https" && base.Scheme != "http") {
+		return nil, fmt.Errorf("invalid site URL %q", baseURL)
+	}
+	base.RawQuery, base.Fragment = "", ""
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+	return &Crawler{client: client, base: base, limit: maxPages}, nil
+}
+
+// Crawl lists the site's pages from the sitemaps named in robots.txt, or
+// sitemap.xml under the base URL or at the root, and fetches those under
+// the base URL that robots.txt allows
+func (c *Crawler) Crawl() (*Result, error) {
+	root := &url.URL{Scheme: c.base.Scheme, Host: c.base.Host, Path: "/"}
+
+	rules := &robots{}
+	if body, _, err := c.get(root.JoinPath("robots.txt").String(), maxPageBytes); err == nil {
+		rules = parseRobots(string(body), userAgent)
+	}
+
+	sitemaps := rules.sitemaps
+	if len(sitemaps) == 0 {
+		if c.base.Path != "/" {
+			sitemaps = append(sitemaps, c.base.JoinPath("sitemap.xml").String())
+		}
+		sitemaps = append(sitemaps, root.JoinPath("sitemap.xml").String())
+	}
+
+	keep := func(u *url.URL) bool {
+		return (u.Scheme == "https" || u.Scheme == "http") &&
+			strings.EqualFold(u.Host, c.base.Host) &&
+			(strings.HasPrefix(u.Path, c.base.Path) || u.Path+"/" == c.base.Path) &&
+			rules.allowed(u.Path)
+	}
+	urls, err := c.sitemapURLs(sitemaps, keep, c.limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("the sitemap of %s lists no pages under %s", c.base.Host, c.base.Path)
+	}
+
+	result := &Result{Base: c.base.String(), Listed: len(urls)}
+	pages := make([]*Page, len(urls))
+	var wg sync.WaitGroup
+	work := make(chan int)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				page, err := c.fetchPage(urls[i])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to crawl %s: %v\n", urls[i], err)
+					continue
+				}
+				pages[i] = page
+			}
+		}()
+	}
+	for i := range urls {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	// Keep sitemap order
+	for _, page := range pages {
+		if page == nil {
+			result.Failed++
+			continue
+		}
+		result.Pages = append(result.Pages, *page)
+	}
+	return result, nil
+}
+
+func (c *Crawler) fetchPage(u *url.URL) (page *Page, err error) {
+	// Pages are fetched on worker goroutines, where a panic converting one
+	// would take down the server
+	defer func() {
+		if p := recover(); p != nil {
+			fmt.Fprintf(os.Stderr, "Panic reading %s: %v\n%s", u, p, debug.Stack())
+			err = fmt.Errorf("failed to read page: %v", p)
+		}
+	}()
+
+	body, contentType, err := c.get(u.String(), maxPageBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	page = &Page{URL: u.String(), Section: c.section(u)}
+	if !strings.Contains(contentType, "html") {
+		// Some docs sites list their markdown sources
+		page.Content = strings.TrimSpace(string(body))
+		page.Title = markdownTitle(page.Content)
+	} else {
+		doc, err := html.Parse(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse page: %w", err)
+		}
+		page.Title, page.Description = pageMeta(doc)
+		if c.Selectors.Title != nil {
+			if n := find(doc, c.Selectors.Title.match); n != nil {
+				page.Title = collapse(text(n))
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := html.Render(&buf, mainContent(doc, c.Selectors.Content)); err != nil {
+			return nil, fmt.Errorf("failed to render page: %w", err)
+		}
+		page.Content = strings.TrimSpace(markdown.FromHTML(buf.String(), u.String()))
+	}
+
+	if page.Content == "" {
+		return nil, fmt.Errorf("page has no content")
+	}
+	if page.Title == "" {
+		page.Title = markdownTitle(page.Content)
+	}
+	if page.Title == "" {
+		page.Title = strings.Trim(u.Path, "/")
+	}
+	return page, nil
+}
+
+// section names the part of the site a page belongs to
+func (c *Crawler) section(u *url.URL) string {
+	rest := strings.TrimPrefix(u.Path, c.base.Path)
+	section, _, ok := strings.Cut(rest, "/")
+	if !ok {
+		return ""
+	}
+	return section
+}
+
+// pageMeta reads a page's title, preferring its first h1 over the <title>,
+// which usually carries the site name too, and its meta description
+func pageMeta(doc *html.Node) (title, description string) {
+	var titleTag string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Title:
+				if titleTag == "" {
+					titleTag = collapse(text(n))
+				}
+			case atom.H1:
+				if title == "" {
+					title = collapse(text(n))
+				}
+			case atom.Meta:
+				if strings.EqualFold(attr(n, "name"), "description") && description == "" {
+					description = collapse(attr(n, "content"))
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	if title == "" {
+		title = titleTag
+	}
+	return title, description
+}
+
+// mainContent returns the element holding a page's content: the first one
+// matching selector, its <main>, role="main" element, or <article>, else its
+// body. Navigation, headers, footers, and sidebars are removed from it.
+func mainContent(doc *html.Node, selector *Selector) *html.Node {
+	var content *html.Node
+	if selector != nil {
+		content = find(doc, selector.match)
+	}
+	if content == nil {
+		content = find(doc, func(n *html.Node) bool { return n.DataAtom == atom.Main || attr(n, "role") == "main" })
+	}
+	if content == nil {
+		content = find(doc, func(n *html.Node) bool { return n.DataAtom == atom.Article })
+	}
+	if content == nil {
+		content = find(doc, func(n *html.Node) bool { return n.DataAtom == atom.Body })
+	}
+	if content == nil {
+		content = doc
+	}
+
+	var strip func(*html.Node)
+	strip = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; {
+			next := child.NextSibling
+			if child.Type == html.ElementNode {
+				switch child.DataAtom {
+				case atom.Nav, atom.Header, atom.Footer, atom.Aside:
+					n.RemoveChild(child)
+				default:
+					if role := attr(child, "role"); role == "navigation" || role == "banner" || role == "contentinfo" {
+						n.RemoveChild(child)
+					} else {
+						strip(child)
+					}
+				}
+			}
+			child = next
+		}
+	}
+	strip(content)
+	return content
+}
+
+func find(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if n.Type == html.ElementNode && match(n) {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := find(child, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func text(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(text(child))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// markdownTitle returns the first H1 of a markdown page
+func markdownTitle(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if title, ok := strings.CutPrefix(line, "# "); ok {
+			return strings.TrimSpace(title)
+		}
+	}
+	return ""
+}
+
+// get reads a resource of at most limit bytes, returning its Content-Type
+func (c *Crawler) get(rawURL string, limit int64) ([]byte, string, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, "", errNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > limit {
+		return nil, "", fmt.Errorf("response exceeds %d MB", limit>>20)
+	}
+	return body, resp.Header.Get("Content-Type"), nil
+}
@@ -0,0 +1,175 @@
+package crawler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Selector matches HTML elements by a subset of CSS: type, universal,
+// #id, .class, [attr], and [attr=value] selectors, compounds of them,
+// descendant combinators, and comma-separated lists
+type Selector struct {
+	// alternatives are the comma-separated selectors; each is a chain of
+	// compounds where every compound is a descendant of the one before
+	alternatives [][]compound
+	source       string
+}
+
+// compound is a sequence of simple selectors all matching one element
+type compound struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   []attrSelector
+}
+
+type attrSelector struct {
+	key   string
+	value string
+	// exists matches [key] regardless of its value
+	exists bool
+}
+
+// ParseSelector parses a selector such as "main .content", "div#docs",
+// or "article, [role=main]"
+func ParseSelector(s string) (*Selector, error) {
+	sel := &Selector{source: s}
+	for _, alternative := range strings.Split(s, ",") {
+		var chain []compound
+		for _, part := range strings.Fields(alternative) {
+			c, err := parseCompound(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector %q: %w", s, err)
+			}
+			chain = append(chain, c)
+		}
+		if len(chain) == 0 {
+			return nil, fmt.Errorf("invalid selector %q: empty selector", s)
+		}
+		sel.alternatives = append(sel.alternatives, chain)
+	}
+	return sel, nil
+}
+
+// String returns the selector as it was written
+func (s *Selector) String() string {
+	return s.source
+}
+
+func parseCompound(s string) (compound, error) {
+	var c compound
+	i := 0
+	ident := func() string {
+		start := i
+		for i < len(s) && isIdentChar(s[i]) {
+			i++
+		}
+		return s[start:i]
+	}
+
+	if s[0] == '*' {
+		i++
+	} else {
+		c.tag = strings.ToLower(ident())
+	}
+
+	for i < len(s) {
+		switch s[i] {
+		case '#':
+			i++
+			if c.id = ident(); c.id == "" {
+				return c, fmt.Errorf("missing id after #")
+			}
+		case '.':
+			i++
+			class := ident()
+			if class == "" {
+				return c, fmt.Errorf("missing class after .")
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return c, fmt.Errorf("unterminated [")
+			}
+			key, value, hasValue := strings.Cut(s[i+1:i+end], "=")
+			key = strings.ToLower(strings.TrimSpace(key))
+			if key == "" {
+				return c, fmt.Errorf("missing attribute name")
+			}
+			c.attrs = append(c.attrs, attrSelector{key: key, value: strings.Trim(value, `"'`), exists: !hasValue})
+			i += end + 1
+		default:
+			return c, fmt.Errorf("unsupported %q", s[i:])
+		}
+	}
+	return c, nil
+}
+
+func isIdentChar(b byte) bool {
+	return b == '-' || b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// match reports whether an element matches any of the alternatives
+func (s *Selector) match(n *html.Node) bool {
+	for _, chain := range s.alternatives {
+		if matchChain(n, chain) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchChain matches the last compound against n and the ones before it
+// against its ancestors, nearest first
+func matchChain(n *html.Node, chain []compound) bool {
+	last := len(chain) - 1
+	if !chain[last].match(n) {
+		return false
+	}
+	for ancestor := n.Parent; last > 0 && ancestor != nil; ancestor = ancestor.Parent {
+		if chain[last-1].match(ancestor) {
+			last--
+		}
+	}
+	return last == 0
+}
+
+func (c compound) match(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if c.tag != "" && n.Data != c.tag {
+		return false
+	}
+	if c.id != "" && attr(n, "id") != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(attr(n, "class"))
+		for _, want := range c.classes {
+			if !slices.Contains(classes, want) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		value, ok := attrValue(n, a.key)
+		if !ok || !a.exists && value != a.value {
+			return false
+		}
+	}
+	return true
+}
+
+func attrValue(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
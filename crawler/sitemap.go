@@ -0,0 +1,178 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+const (
+	// maxSitemaps caps the sitemaps read through sitemap indexes
+	maxSitemaps = 50
+
+	// maxSitemapBytes bounds one sitemap, after decompression
+	maxSitemapBytes = 50 << 20
+)
+
+// sitemap is a urlset or a sitemap index, per sitemaps.org
+type sitemap struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// sitemapURLs reads the page URLs of the site's sitemaps, following sitemap
+// indexes, until limit URLs accepted by keep are found
+func (c *Crawler) sitemapURLs(sitemaps []string, keep func(*url.URL) bool, limit int) ([]*url.URL, error) {
+	var pages []*url.URL
+	seen := make(map[string]bool)
+	queue := append([]string{}, sitemaps...)
+	read := 0
+	var lastErr error
+
+	for len(queue) > 0 && read < maxSitemaps && len(pages) < limit {
+		loc := queue[0]
+		queue = queue[1:]
+		if seen[loc] {
+			continue
+		}
+		seen[loc] = true
+		read++
+
+		sm, err := c.readSitemap(loc)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, s := range sm.Sitemaps {
+			queue = append(queue, strings.TrimSpace(s.Loc))
+		}
+		for _, entry := range sm.URLs {
+			u, err := url.Parse(strings.TrimSpace(entry.Loc))
+			if err != nil || seen[u.String()] || !keep(u) {
+				continue
+			}
+			seen[u.String()] = true
+			pages = append(pages, u)
+			if len(pages) == limit {
+				break
+			}
+		}
+	}
+
+	if len(pages) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return pages, nil
+}
+
+func (c *Crawler) readSitemap(loc string) (*sitemap, error) {
+	body, _, err := c.get(loc, maxSitemapBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sitemap %s: %w", loc, err)
+	}
+
+	// Sitemaps may be gzipped, whatever their Content-Encoding says
+	if bytes.HasPrefix(body, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap %s: %w", loc, err)
+		}
+		body, err = io.ReadAll(io.LimitReader(zr, maxSitemapBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap %s: %w", loc, err)
+		}
+	}
+
+	var sm sitemap
+	if err := xml.Unmarshal(body, &sm); err != nil {
+		return nil, fmt.Errorf("failed to parse sitemap %s: %w", loc, err)
+	}
+	return &sm, nil
+}
+
+// robots holds what robots.txt says about the crawler: the path prefixes it
+// may not fetch and the sitemaps it lists
+type robots struct {
+	disallow []string
+	allow    []string
+	sitemaps []string
+}
+
+// parseRobots reads the rules of the groups for every user agent ("*") or
+// the crawler's own, which replace the former when present
+func parseRobots(text, agent string) *robots {
+	r := &robots{}
+	var general, own robots
+	var agents []string
+	inRules := false
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "sitemap":
+			if value != "" {
+				r.sitemaps = append(r.sitemaps, value)
+			}
+		case "user-agent":
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			for _, a := range agents {
+				target := &general
+				if a == agent {
+					target = &own
+				} else if a != "*" {
+					continue
+				}
+				if key == "allow" {
+					target.allow = append(target.allow, value)
+				} else if value != "" {
+					target.disallow = append(target.disallow, value)
+				}
+			}
+		}
+	}
+
+	rules := general
+	if len(own.allow) > 0 || len(own.disallow) > 0 {
+		rules = own
+	}
+	r.allow, r.disallow = rules.allow, rules.disallow
+	return r
+}
+
+// allowed reports whether a path may be fetched: the longest matching rule
+// wins, and Allow wins ties
+func (r *robots) allowed(path string) bool {
+	longest := func(rules []string) int {
+		n := -1
+		for _, rule := range rules {
+			if strings.HasPrefix(path, rule) && len(rule) > n {
+				n = len(rule)
+			}
+		}
+		return n
+	}
+	disallow := longest(r.disallow)
+	return disallow < 0 || longest(r.allow) >= disallow
+}
@@ -0,0 +1,261 @@
+// Package embeddings computes text embeddings with a remote API and caches
+// them on disk by content hash, so re-indexing only embeds changed text.
+package embeddings
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/incu6us/open-context/config"
+)
+
+const (
+	requestTimeout = 60 * time.Second
+
+	// defaultBatchSize is how many texts go into one request; both APIs
+	// accept more, but smaller batches keep request bodies modest
+	defaultBatchSize = 64
+
+	// maxInputChars truncates long documents; embedding models read only
+	// the first few thousand tokens anyway
+	maxInputChars = 8000
+)
+
+// providers are the supported remote APIs. Both speak the same request and
+// response format. Voyage AI is the embedding provider Anthropic recommends.
+var providers = map[string]struct {
+	endpoint string
+	model    string
+	keyEnv   string
+}{
+	"openai": {"https://api.openai.com/v1/embeddings", "text-embedding-3-small", "OPENAI_API_KEY"},
+	"voyage": {"https://api.voyageai.com/v1/embeddings", "voyage-3-lite", "VOYAGE_API_KEY"},
+}
+
+// Embedder turns texts into vectors
+type Embedder interface {
+	Embed(texts []string) ([][]float32, error)
+}
+
+// Client calls a remote embeddings API
+type Client struct {
+	client   *http.Client
+	endpoint string
+	model    string
+	apiKey   string
+}
+
+// NewClient creates a client from the semantic_search section of config.yaml
+func NewClient(cfg config.SemanticSearch) (*Client, error) {
+	p, ok := providers[cfg.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported embeddings provider: %s (must be 'openai' or 'voyage')", cfg.Provider)
+	}
+
+	c := &Client{
+		client:   &http.Client{Timeout: requestTimeout},
+		endpoint: firstNonEmpty(cfg.Endpoint, p.endpoint),
+		model:    firstNonEmpty(cfg.Model, p.model),
+		apiKey:   firstNonEmpty(cfg.APIKey, os.Getenv(p.keyEnv)),
+	}
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("an API key is required for %s embeddings (set api_key or %s)", cfg.Provider, p.keyEnv)
+	}
+	return c, nil
+}
+
+// Model returns the embedding model name
+func (c *Client) Model() string {
+	return c.model
+}
+
+// Embed returns one vector per text, in order
+func (c *Client) Embed(texts []string) ([][]float32, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model": c.model,
+		"input": texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request embeddings: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("embeddings API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse embeddings: %w", err)
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range result.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("embeddings API returned no vector for input %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// Cache stores vectors on disk under the hash of the model and text, and
+// sends the texts it has not seen to the remote API in batches
+type Cache struct {
+	embedder  Embedder
+	dir       string
+	model     string
+	batchSize int
+}
+
+// NewCache wraps embedder with a cache under dir. Vectors from different
+// models are kept apart.
+func NewCache(embedder Embedder, dir, model string, batchSize int) *Cache {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &Cache{embedder: embedder, dir: dir, model: model, batchSize: batchSize}
+}
+
+// Embed returns one vector per text, embedding only those not cached
+func (c *Cache) Embed(texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	truncated := make([]string, len(texts))
+	var missing []int
+
+	for i, text := range texts {
+		truncated[i] = truncate(text)
+		if v, err := c.load(truncated[i]); err == nil {
+			vectors[i] = v
+		} else {
+			if !errors.Is(err, fs.ErrNotExist) {
+				fmt.Fprintf(os.Stderr, "Warning: ignoring unreadable cached embedding: %v\n", err)
+			}
+			missing = append(missing, i)
+		}
+	}
+
+	for start := 0; start < len(missing); start += c.batchSize {
+		batch := missing[start:min(start+c.batchSize, len(missing))]
+		inputs := make([]string, len(batch))
+		for j, i := range batch {
+			inputs[j] = truncated[i]
+		}
+
+		embedded, err := c.embedder.Embed(inputs)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range batch {
+			vectors[i] = embedded[j]
+			if err := c.store(truncated[i], embedded[j]); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache embedding: %v\n", err)
+			}
+		}
+	}
+
+	return vectors, nil
+}
+
+func (c *Cache) path(text string) string {
+	sum := sha256.Sum256([]byte(c.model + "\x00" + text))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key[:2], key+".bin")
+}
+
+// Vectors are stored as little-endian float32s
+func (c *Cache) load(text string) ([]float32, error) {
+	data, err := os.ReadFile(c.path(text))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(data)%4 != 0 {
+		return nil, fmt.Errorf("invalid embedding file %s", c.path(text))
+	}
+	v := make([]float32, len(data)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return v, nil
+}
+
+func (c *Cache) store(text string, v []float32) error {
+	data := make([]byte, 0, len(v)*4)
+	for _, f := range v {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(f))
+	}
+	path := c.path(text)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Cosine returns the cosine similarity of two vectors
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func truncate(text string) string {
+	if len(text) <= maxInputChars {
+		return text
+	}
+	return strings.ToValidUTF8(text[:maxInputChars], "")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const githubAdvisoriesAPI = "https://api.github.com/advisories"
+
+// githubAdvisory is an entry from the GitHub Advisory Database
+type githubAdvisory struct {
+	GHSAID      string `json:"ghsa_id"`
+	CVEID       string `json:"cve_id"`
+	Summary     string `json:"summary"`
+	Severity    string `json:"severity"`
+	HTMLURL     string `json:"html_url"`
+	PublishedAt string `json:"published_at"`
+	WithdrawnAt string `json:"withdrawn_at"`
+
+	Vulnerabilities []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		VulnerableVersionRange string `json:"vulnerable_version_range"`
+		// A string in the global advisories API, an object elsewhere
+		FirstPatchedVersion interface{} `json:"first_patched_version"`
+	} `json:"vulnerabilities"`
+}
+
+// advisoriesSection returns a "Known Advisories" markdown section for a
+// package when security_advisories is enabled in config.yaml, or "" when it
+// is disabled or the lookup fails. ecosystem is the GitHub name: go, npm,
+// pip, or rust.
+func (b *BaseFetcher) advisoriesSection(ecosystem, name, version string) string {
+	if !b.advisories {
+		return ""
+	}
+
+	advisories, err := b.fetchAdvisories(ecosystem, name, version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch security advisories for %s: %v\n", name, err)
+		return ""
+	}
+
+	return buildAdvisoriesSection(name, version, advisories)
+}
+
+func (b *BaseFetcher) fetchAdvisories(ecosystem, name, version string) ([]githubAdvisory, error) {
+	affects := name
+	if version != "" {
+		affects = name + "@" + version
+	}
+	query := url.Values{
+		"ecosystem": {ecosystem},
+		"affects":   {affects},
+		"per_page":  {"100"},
+	}
+
+	req, err := newRequest(githubAdvisoriesAPI + "?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.getClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub advisories API returned status %d", resp.StatusCode)
+	}
+
+	var advisories []githubAdvisory
+	if err := json.NewDecoder(resp.Body).Decode(&advisories); err != nil {
+		return nil, fmt.Errorf("failed to parse advisories: %w", err)
+	}
+	return advisories, nil
+}
+
+func buildAdvisoriesSection(name, version string, advisories []githubAdvisory) string {
+	var content strings.Builder
+
+	content.WriteString("\n## Known Advisories\n\n")
+
+	subject := name
+	if version != "" {
+		subject = name + "@" + version
+	}
+
+	count := 0
+	for _, adv := range advisories {
+		if adv.WithdrawnAt != "" {
+			continue
+		}
+		count++
+
+		id := adv.GHSAID
+		if adv.HTMLURL != "" {
+			id = fmt.Sprintf("[%s](%s)", adv.GHSAID, adv.HTMLURL)
+		}
+		var tags []string
+		if adv.Severity != "" {
+			tags = append(tags, adv.Severity)
+		}
+		if adv.CVEID != "" {
+			tags = append(tags, adv.CVEID)
+		}
+		if len(adv.PublishedAt) >= 10 {
+			tags = append(tags, "published "+adv.PublishedAt[:10])
+		}
+
+		fmt.Fprintf(&content, "- **%s**", id)
+		if len(tags) > 0 {
+			fmt.Fprintf(&content, " (%s)", strings.Join(tags, ", "))
+		}
+		fmt.Fprintf(&content, ": %s\n", adv.Summary)
+
+		for _, vuln := range adv.Vulnerabilities {
+			if !strings.EqualFold(vuln.Package.Name, name) || vuln.VulnerableVersionRange == "" {
+				continue
+			}
+			fmt.Fprintf(&content, "  - Affected: `%s`", vuln.VulnerableVersionRange)
+			if patched := patchedVersion(vuln.FirstPatchedVersion); patched != "" {
+				fmt.Fprintf(&content, "; patched in `%s`", patched)
+			} else {
+				content.WriteString("; no patched version")
+			}
+			content.WriteString("\n")
+		}
+	}
+
+	if count == 0 {
+		fmt.Fprintf(&content, "No known advisories for %s in the GitHub Advisory Database.\n", subject)
+	} else {
+		fmt.Fprintf(&content, "\nSource: [GitHub Advisory Database](https://github.com/advisories) (%d for %s)\n", count, subject)
+	}
+
+	return content.String()
+}
+
+func patchedVersion(v interface{}) string {
+	switch p := v.(type) {
+	case string:
+		return p
+	case map[string]interface{}:
+		if id, ok := p["identifier"].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
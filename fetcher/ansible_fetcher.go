@@ -6,19 +6,17 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/semver"
 )
 
-type AnsibleVersionInfo struct {
-	Version     string `yaml:"version"`
-	ReleaseDate string `yaml:"releaseDate"`
-	ReleaseURL  string `yaml:"releaseURL"`
-	Content     string `yaml:"-"`
-}
+type AnsibleVersionInfo = ReleaseVersionInfo
 
 type AnsibleFetcher struct {
 	*BaseFetcher
@@ -32,6 +30,17 @@ func NewAnsibleFetcher(cacheDir string) *AnsibleFetcher {
 
 // FetchAnsibleVersion fetches information about a specific Ansible version
 func (f *AnsibleFetcher) FetchAnsibleVersion(version string) (*AnsibleVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchAnsibleVersion", version), f.versionPath(version), func() (*AnsibleVersionInfo, error) {
+		return f.fetchAnsibleVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on an Ansible version
+func (f *AnsibleFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("ansible", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *AnsibleFetcher) fetchAnsibleVersion(version string) (*AnsibleVersionInfo, error) {
 	// Normalize version (add 'v' prefix if missing for GitHub API)
 	githubVersion := version
 	if !strings.HasPrefix(version, "v") {
@@ -39,7 +48,7 @@ func (f *AnsibleFetcher) FetchAnsibleVersion(version string) (*AnsibleVersionInf
 	}
 
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("ansible", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
 	if err == nil && versionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Ansible version '%s' from cache\n", version)
@@ -79,42 +88,13 @@ func (f *AnsibleFetcher) FetchAnsibleVersion(version string) (*AnsibleVersionInf
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse GitHub release data
-	var releaseData map[string]interface{}
-	if err := json.Unmarshal(body, &releaseData); err != nil {
-		return nil, fmt.Errorf("failed to parse release data: %w", err)
-	}
-
-	// Extract version information
-	versionInfo = &AnsibleVersionInfo{
-		Version: version,
-	}
-
-	// Extract release URL
-	if htmlURL, ok := releaseData["html_url"].(string); ok {
-		versionInfo.ReleaseURL = htmlURL
-	}
-
-	// Extract release date
-	if publishedAt, ok := releaseData["published_at"].(string); ok {
-		// Parse and format the date
-		if t, err := time.Parse(time.RFC3339, publishedAt); err == nil {
-			versionInfo.ReleaseDate = t.Format("2006-01-02")
-		} else {
-			versionInfo.ReleaseDate = publishedAt
-		}
-	}
-
-	// Extract release notes from body
-	releaseNotes := ""
-	if body, ok := releaseData["body"].(string); ok {
-		releaseNotes = body
+	versionInfo, err = renderReleaseVersion(version, body, f.buildVersionContent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build content
-	versionInfo.Content = f.buildVersionContent(versionInfo, releaseNotes)
-
-	// Cache the result
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
 	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
 	}
@@ -180,17 +160,12 @@ func (f *AnsibleFetcher) buildVersionContent(info *AnsibleVersionInfo, releaseNo
 }
 
 func (f *AnsibleFetcher) saveVersionInfoAsMarkdown(filePath string, info *AnsibleVersionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
 	if info.ReleaseDate != "" {
 		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
 	}
@@ -202,16 +177,16 @@ func (f *AnsibleFetcher) saveVersionInfoAsMarkdown(filePath string, info *Ansibl
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *AnsibleFetcher) loadVersionInfoFromMarkdown(filePath string) (*AnsibleVersionInfo, error) {
-	expired, err := f.getCache().IsExpired(filePath)
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
 	if err != nil || expired {
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -226,12 +201,23 @@ func (f *AnsibleFetcher) loadVersionInfoFromMarkdown(filePath string) (*AnsibleV
 		Version     string `yaml:"version"`
 		ReleaseDate string `yaml:"releaseDate"`
 		ReleaseURL  string `yaml:"releaseURL"`
+		Template    int    `yaml:"template"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if info, err := renderReleaseVersion(meta.Version, payload, f.buildVersionContent); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered Ansible version '%s' with current templates\n", meta.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
 	return &AnsibleVersionInfo{
 		Version:     meta.Version,
 		ReleaseDate: meta.ReleaseDate,
@@ -239,3 +225,345 @@ func (f *AnsibleFetcher) loadVersionInfoFromMarkdown(filePath string) (*AnsibleV
 		Content:     strings.TrimSpace(parts[2]),
 	}, nil
 }
+
+const galaxyCollectionsURL = "https://galaxy.ansible.com/api/v3/plugin/ansible/content/published/collections/index"
+
+type AnsibleCollectionInfo struct {
+	Name            string            `yaml:"name"`
+	Version         string            `yaml:"version"`
+	Description     string            `yaml:"description"`
+	License         string            `yaml:"license"`
+	Repository      string            `yaml:"repository"`
+	Documentation   string            `yaml:"documentation"`
+	RequiresAnsible string            `yaml:"requiresAnsible"`
+	ReleaseDate     string            `yaml:"releaseDate"`
+	Versions        []string          `yaml:"-"`
+	Dependencies    map[string]string `yaml:"-"`
+	Contents        []AnsibleContent  `yaml:"-"`
+	Content         string            `yaml:"-"`
+}
+
+// AnsibleContent is a module, plugin, or role shipped in a collection
+type AnsibleContent struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// FetchCollection fetches information about an Ansible collection ("namespace.name") from Ansible Galaxy
+func (f *AnsibleFetcher) FetchCollection(collection, version string) (*AnsibleCollectionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchCollection", collection, version), f.collectionPath(collection, version), func() (*AnsibleCollectionInfo, error) {
+		return f.fetchCollection(collection, version)
+	})
+}
+
+// collectionPath returns the cache path of the info on a collection
+func (f *AnsibleFetcher) collectionPath(collection, version string) string {
+	return f.getCache().GetFilePath("ansible", "collections", fmt.Sprintf("%s.md", cache.EntryName(collection, version)))
+}
+
+func (f *AnsibleFetcher) fetchCollection(collection, version string) (*AnsibleCollectionInfo, error) {
+	namespace, name, ok := strings.Cut(collection, ".")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid collection name %s (expected 'namespace.name', e.g., 'community.general')", collection)
+	}
+
+	// Check cache first
+	cachedPath := f.collectionPath(collection, version)
+	collectionInfo, err := f.loadCollectionFromMarkdown(cachedPath)
+	if err == nil && collectionInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Ansible collection '%s' from cache\n", collection)
+		return collectionInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Ansible collection '%s' from galaxy.ansible.com...\n", collection)
+
+	baseURL := fmt.Sprintf("%s/%s/%s", galaxyCollectionsURL, namespace, name)
+
+	var versionList struct {
+		Data []struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+	found, err := f.getGalaxyJSON(baseURL+"/versions/?limit=100", &versionList)
+	if err != nil {
+		return nil, err
+	}
+	if !found || len(versionList.Data) == 0 {
+		return nil, fmt.Errorf("ansible collection %s not found", collection)
+	}
+
+	var versions []string
+	for _, v := range versionList.Data {
+		versions = append(versions, v.Version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare(versions[i], versions[j]) > 0
+	})
+
+	if version == "" {
+		version = versions[0]
+		for _, v := range versions {
+			if !semver.IsPrerelease(v) {
+				version = v
+				break
+			}
+		}
+	}
+
+	var release struct {
+		Version         string `json:"version"`
+		CreatedAt       string `json:"created_at"`
+		RequiresAnsible string `json:"requires_ansible"`
+		Metadata        struct {
+			Description   string            `json:"description"`
+			License       []string          `json:"license"`
+			Repository    string            `json:"repository"`
+			Documentation string            `json:"documentation"`
+			Dependencies  map[string]string `json:"dependencies"`
+			Contents      []struct {
+				Name        string `json:"name"`
+				ContentType string `json:"content_type"`
+				Description string `json:"description"`
+			} `json:"contents"`
+		} `json:"metadata"`
+	}
+	found, err = f.getGalaxyJSON(fmt.Sprintf("%s/versions/%s/", baseURL, version), &release)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("ansible collection %s version %s not found", collection, version)
+	}
+
+	collectionInfo = &AnsibleCollectionInfo{
+		Name:            collection,
+		Version:         release.Version,
+		Description:     release.Metadata.Description,
+		License:         strings.Join(release.Metadata.License, ", "),
+		Repository:      release.Metadata.Repository,
+		Documentation:   release.Metadata.Documentation,
+		RequiresAnsible: release.RequiresAnsible,
+		Versions:        versions,
+		Dependencies:    release.Metadata.Dependencies,
+	}
+
+	if t, err := time.Parse(time.RFC3339, release.CreatedAt); err == nil {
+		collectionInfo.ReleaseDate = t.Format("2006-01-02")
+	}
+
+	for _, c := range release.Metadata.Contents {
+		collectionInfo.Contents = append(collectionInfo.Contents, AnsibleContent{
+			Name:        c.Name,
+			Type:        c.ContentType,
+			Description: c.Description,
+		})
+	}
+	sort.Slice(collectionInfo.Contents, func(i, j int) bool {
+		a, b := collectionInfo.Contents[i], collectionInfo.Contents[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Name < b.Name
+	})
+
+	// Build content
+	collectionInfo.Content = f.buildCollectionContent(collectionInfo)
+
+	// Cache the result
+	if err := f.saveCollectionAsMarkdown(cachedPath, collectionInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache collection info: %v\n", err)
+	}
+
+	return collectionInfo, nil
+}
+
+// getGalaxyJSON fetches a Galaxy API resource, reporting false without error when it does not exist
+func (f *AnsibleFetcher) getGalaxyJSON(url string, v interface{}) (bool, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch Ansible collection: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("galaxy API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse Galaxy data: %w", err)
+	}
+
+	return true, nil
+}
+
+func (f *AnsibleFetcher) buildCollectionContent(info *AnsibleCollectionInfo) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Name)
+
+	if info.Description != "" {
+		fmt.Fprintf(&content, "**Description:** %s\n\n", info.Description)
+	}
+
+	fmt.Fprintf(&content, "**Version:** %s\n\n", info.Version)
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+
+	if info.RequiresAnsible != "" {
+		fmt.Fprintf(&content, "**Requires Ansible:** %s\n\n", info.RequiresAnsible)
+	}
+
+	if info.License != "" {
+		fmt.Fprintf(&content, "**License:** %s\n\n", info.License)
+	}
+
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "**Repository:** %s\n\n", info.Repository)
+	}
+
+	if len(info.Versions) > 0 {
+		shown := info.Versions
+		if len(shown) > 10 {
+			shown = shown[:10]
+		}
+		fmt.Fprintf(&content, "**Recent Versions:** %s\n\n", strings.Join(shown, ", "))
+	}
+
+	if len(info.Contents) > 0 {
+		content.WriteString("## Included Content\n\n")
+		currentType := ""
+		for _, c := range info.Contents {
+			if c.Type != currentType {
+				if currentType != "" {
+					content.WriteString("\n")
+				}
+				currentType = c.Type
+				fmt.Fprintf(&content, "### %s\n\n", c.Type)
+			}
+			fmt.Fprintf(&content, "- `%s.%s`", info.Name, c.Name)
+			if c.Description != "" {
+				fmt.Fprintf(&content, " - %s", c.Description)
+			}
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	if len(info.Dependencies) > 0 {
+		deps := make([]string, 0, len(info.Dependencies))
+		for dep := range info.Dependencies {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+
+		content.WriteString("## Dependencies\n\n")
+		for _, dep := range deps {
+			fmt.Fprintf(&content, "- `%s` %s\n", dep, info.Dependencies[dep])
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "ansible-galaxy collection install %s:==%s\n", info.Name, info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using requirements.yml\n\n")
+	content.WriteString("```yaml\n")
+	content.WriteString("collections:\n")
+	fmt.Fprintf(&content, "  - name: %s\n", info.Name)
+	fmt.Fprintf(&content, "    version: \"%s\"\n", info.Version)
+	content.WriteString("```\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("ansible-galaxy collection install -r requirements.yml\n")
+	content.WriteString("```\n\n")
+
+	namespace, name, _ := strings.Cut(info.Name, ".")
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [Ansible Galaxy](https://galaxy.ansible.com/ui/repo/published/%s/%s/)\n", namespace, name)
+	if info.Documentation != "" {
+		fmt.Fprintf(&content, "- [Documentation](%s)\n", info.Documentation)
+	}
+	fmt.Fprintf(&content, "- [Collection Index](https://docs.ansible.com/ansible/latest/collections/%s/%s/)\n", namespace, name)
+
+	return content.String()
+}
+
+func (f *AnsibleFetcher) saveCollectionAsMarkdown(filePath string, info *AnsibleCollectionInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "name: \"%s\"\n", info.Name)
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	if info.Description != "" {
+		fmt.Fprintf(&content, "description: \"%s\"\n", escapeYAML(info.Description))
+	}
+	if info.License != "" {
+		fmt.Fprintf(&content, "license: \"%s\"\n", escapeYAML(info.License))
+	}
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "repository: \"%s\"\n", info.Repository)
+	}
+	if info.Documentation != "" {
+		fmt.Fprintf(&content, "documentation: \"%s\"\n", info.Documentation)
+	}
+	if info.RequiresAnsible != "" {
+		fmt.Fprintf(&content, "requiresAnsible: \"%s\"\n", escapeYAML(info.RequiresAnsible))
+	}
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *AnsibleFetcher) loadCollectionFromMarkdown(filePath string) (*AnsibleCollectionInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta AnsibleCollectionInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	meta.Content = strings.TrimSpace(parts[2])
+	return &meta, nil
+}
@@ -0,0 +1,220 @@
+package fetcher
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TopicOptions describes a topic written by AddTopic. Fields left empty are
+// taken from the file's front matter and content.
+type TopicOptions struct {
+	// File is the markdown file holding the topic's content
+	File        string
+	ID          string
+	Title       string
+	Description string
+	// Keywords are added to the front matter tags and keywords, the title,
+	// and the page's headings
+	Keywords []string
+}
+
+// AuthoredTopic is a topic of a custom documentation
+type AuthoredTopic struct {
+	Documentation string
+	ID            string
+	Title         string
+	Description   string
+	Keywords      []string
+	// Path is the topic's JSON file
+	Path string
+	// Replaced is true if AddTopic overwrote a topic with the same ID
+	Replaced bool
+}
+
+// AddTopic writes the markdown file of opts as a topic of the custom
+// documentation language in docsDir, creating the documentation's
+// metadata.json if it has none. The topic's ID defaults to a slug of its
+// title, and adding a topic with an existing ID replaces it.
+func AddTopic(docsDir, language string, opts TopicOptions) (*AuthoredTopic, error) {
+	if !localDocsNamePattern.MatchString(language) {
+		return nil, fmt.Errorf("invalid language %q: use lowercase letters, digits, '-', and '_'", language)
+	}
+
+	data, err := os.ReadFile(opts.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", opts.File, err)
+	}
+	meta, body := splitFrontMatter(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("%s has no content", opts.File)
+	}
+
+	title := cmp.Or(opts.Title, meta.Title)
+	if title == "" && strings.HasPrefix(body, "# ") {
+		line, _, _ := strings.Cut(body, "\n")
+		title = strings.TrimSpace(strings.Trim(line, "#"))
+	}
+	if title == "" {
+		title = localTitle(strings.TrimSuffix(filepath.Base(opts.File), filepath.Ext(opts.File)))
+	}
+
+	id := opts.ID
+	if id == "" {
+		id = localDocsName(title)
+	}
+	if !localDocsNamePattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid topic ID %q: use lowercase letters, digits, '-', and '_'", id)
+	}
+
+	content := body
+	if !strings.HasPrefix(content, "# ") {
+		content = "# " + title + "\n\n" + content
+	}
+
+	keywords := append([]string{title}, opts.Keywords...)
+	keywords = append(keywords, meta.Tags...)
+	keywords = append(keywords, meta.Keywords...)
+	for _, m := range localHeadingPattern.FindAllStringSubmatch(body, -1) {
+		keywords = append(keywords, strings.Trim(m[1], "`*_"))
+	}
+
+	topic := &indexedTopic{
+		ID:          id,
+		Title:       title,
+		Description: cmp.Or(opts.Description, meta.Description, firstParagraph(body)),
+		Content:     content + "\n",
+		Keywords:    uniqueStrings(keywords),
+		Version:     meta.Version,
+	}
+
+	docDir := filepath.Join(docsDir, language)
+	if err := os.MkdirAll(filepath.Join(docDir, "topics"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create topics directory: %w", err)
+	}
+	metadataPath := filepath.Join(docDir, "metadata.json")
+	if _, err := os.Stat(metadataPath); errors.Is(err, fs.ErrNotExist) {
+		metadata := map[string]string{
+			"name":        language,
+			"displayName": localTitle(language),
+			"description": fmt.Sprintf("Custom %s documentation", localTitle(language)),
+		}
+		if err := writeJSON(metadataPath, metadata); err != nil {
+			return nil, fmt.Errorf("failed to write metadata: %w", err)
+		}
+	}
+
+	// Replace a topic with the same ID, whatever its file is named
+	existing, err := readAuthoredTopics(docsDir, language)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(docDir, "topics", id+".json")
+	replaced := false
+	for _, t := range existing {
+		if t.ID != id {
+			continue
+		}
+		replaced = true
+		if t.Path != path {
+			if err := os.Remove(t.Path); err != nil {
+				return nil, fmt.Errorf("failed to replace topic %s: %w", id, err)
+			}
+		}
+	}
+
+	if err := writeJSON(path, topic); err != nil {
+		return nil, fmt.Errorf("failed to write topic: %w", err)
+	}
+	return &AuthoredTopic{
+		Documentation: language,
+		ID:            topic.ID,
+		Title:         topic.Title,
+		Description:   topic.Description,
+		Keywords:      topic.Keywords,
+		Path:          path,
+		Replaced:      replaced,
+	}, nil
+}
+
+// RemoveTopic deletes the topic with ID id from the documentation language
+// in docsDir
+func RemoveTopic(docsDir, language, id string) (*AuthoredTopic, error) {
+	topics, err := readAuthoredTopics(docsDir, language)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range topics {
+		if t.ID != id {
+			continue
+		}
+		if err := os.Remove(t.Path); err != nil {
+			return nil, fmt.Errorf("failed to remove topic %s: %w", id, err)
+		}
+		return &t, nil
+	}
+	return nil, fmt.Errorf("topic %s not found in %s", id, language)
+}
+
+// ListTopics returns the topics of the documentation language in docsDir,
+// sorted by ID
+func ListTopics(docsDir, language string) ([]AuthoredTopic, error) {
+	topics, err := readAuthoredTopics(docsDir, language)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].ID < topics[j].ID })
+	return topics, nil
+}
+
+// readAuthoredTopics reads the topic files of a documentation directory
+func readAuthoredTopics(docsDir, language string) ([]AuthoredTopic, error) {
+	if !localDocsNamePattern.MatchString(language) {
+		return nil, fmt.Errorf("invalid language %q: use lowercase letters, digits, '-', and '_'", language)
+	}
+	topicsDir := filepath.Join(docsDir, language, "topics")
+	entries, err := os.ReadDir(topicsDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, err := os.Stat(filepath.Join(docsDir, language)); err != nil {
+			return nil, fmt.Errorf("documentation %s not found in %s", language, docsDir)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topics: %w", err)
+	}
+
+	var topics []AuthoredTopic
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(topicsDir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read %s: %v\n", path, err)
+			continue
+		}
+		var topic indexedTopic
+		if err := json.Unmarshal(data, &topic); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", path, err)
+			continue
+		}
+		topics = append(topics, AuthoredTopic{
+			Documentation: language,
+			ID:            topic.ID,
+			Title:         topic.Title,
+			Description:   topic.Description,
+			Keywords:      topic.Keywords,
+			Path:          path,
+		})
+	}
+	return topics, nil
+}
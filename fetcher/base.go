@@ -1,9 +1,13 @@
 package fetcher
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/incu6us/open-context/cache"
@@ -16,8 +20,11 @@ const (
 
 // BaseFetcher provides common functionality for all fetchers
 type BaseFetcher struct {
-	client *http.Client
-	cache  *cache.Manager
+	client  *http.Client
+	cache   *cache.Manager
+	flights *flightGroup
+	// advisories appends GitHub security advisories to package info
+	advisories bool
 }
 
 // NewBaseFetcher creates a new base fetcher with common configuration
@@ -32,16 +39,74 @@ func NewBaseFetcher(cacheDir string) *BaseFetcher {
 	}
 
 	// Create cache manager
-	cacheManager := cache.NewManager(cacheDir, cfg.CacheTTL.Duration)
+	initCacheStorage(cfg, cacheDir)
+	initUpstreamTransport(cfg.Mirrors)
+	cacheManager := newCacheManager(cacheDir, cfg.CacheTTL.Duration)
 
 	return &BaseFetcher{
-		client: &http.Client{
-			Timeout: defaultHTTPTimeout,
-		},
-		cache: cacheManager,
+		client:     upstreamClient,
+		cache:      cacheManager,
+		flights:    newFlightGroup(cacheManager),
+		advisories: cfg.SecurityAdvisories,
 	}
 }
 
+// Cache storage shared by all fetchers, set up once from config.yaml
+var (
+	cacheStore       cache.Store
+	cacheLocker      cache.Locker
+	cacheLockTimeout time.Duration
+	cacheTrash       time.Duration
+	cacheStorageOnce sync.Once
+)
+
+// initCacheStorage creates the cache storage backend and, when Redis is
+// configured, its hot layer and fetch locks. A misconfigured backend falls
+// back to the local disk cache. Entries trashed longer than cache_trash ago
+// are purged.
+func initCacheStorage(cfg *config.Config, cacheDir string) {
+	cacheStorageOnce.Do(func() {
+		store, err := cache.NewStore(cfg.Cache, cacheDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; using local disk cache\n", err)
+			store = cache.NewDiskStore(cacheDir)
+		} else if cfg.Cache.Backend != "" && cfg.Cache.Backend != "disk" {
+			fmt.Fprintf(os.Stderr, "Info: Using %s cache backend (bucket: %s)\n", cfg.Cache.Backend, cfg.Cache.Bucket)
+		}
+
+		if cfg.Redis.Addr != "" {
+			client := cache.NewRedisClient(cfg.Redis)
+			store = cache.NewRedisStore(store, client, cfg.Redis.HotTTL.Duration)
+			cacheLocker = cache.NewRedisLocker(client)
+			cacheLockTimeout = cfg.Redis.LockTimeout.Duration
+			fmt.Fprintf(os.Stderr, "Info: Using redis at %s for hot cache entries and fetch locks\n", cfg.Redis.Addr)
+		}
+
+		cacheStore = store
+
+		if cfg.CacheTrash.Duration > 0 {
+			cacheTrash = cfg.CacheTrash.Duration
+			if n, err := cache.PurgeTrash(cacheDir, cacheTrash); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to purge the cache trash: %v\n", err)
+			} else if n > 0 {
+				fmt.Fprintf(os.Stderr, "Info: Purged %d cache entries trashed more than %v ago\n", n, cacheTrash)
+			}
+		}
+	})
+}
+
+// newCacheManager creates a cache manager on the shared storage
+func newCacheManager(cacheDir string, ttl time.Duration) *cache.Manager {
+	m := cache.NewManagerWithStore(cacheDir, ttl, cacheStore)
+	if cacheLocker != nil {
+		m.SetLocker(cacheLocker, cacheLockTimeout)
+	}
+	if cacheTrash > 0 {
+		m.SetTrash(cacheTrash)
+	}
+	return m
+}
+
 // getClient returns the HTTP client
 func (b *BaseFetcher) getClient() *http.Client {
 	return b.client
@@ -51,3 +116,115 @@ func (b *BaseFetcher) getClient() *http.Client {
 func (b *BaseFetcher) getCache() *cache.Manager {
 	return b.cache
 }
+
+// newRequest creates a GET request for url. Requests to the GitHub API ask
+// for its JSON media type and carry the GitHub token, if one is configured,
+// since unauthenticated requests are limited to 60 per hour.
+func newRequest(url string) (*http.Request, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	if strings.HasPrefix(url, "https://api.github.com/") {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token := githubToken(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	return req, nil
+}
+
+// get returns the body of url. found is false when there is no such
+// document.
+func (b *BaseFetcher) get(url string) (body []byte, found bool, err error) {
+	req, err := newRequest(url)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := b.getClient().Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, true, nil
+}
+
+// fetchCached returns the body of url, cached at cachedPath. found is false
+// when there is no such document.
+func (b *BaseFetcher) fetchCached(cachedPath, url string) (body []byte, found bool, err error) {
+	if expired, err := b.getCache().IsExpired(cachedPath); err == nil && !expired {
+		if body, err := b.getCache().ReadFile(cachedPath); err == nil {
+			return body, true, nil
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching %s...\n", url)
+	body, found, err = b.get(url)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	if err := b.getCache().WriteFile(cachedPath, body); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache %s: %v\n", url, err)
+	}
+	return body, true, nil
+}
+
+// getJSON decodes the JSON document at url into v. found is false when there
+// is no such document.
+func (b *BaseFetcher) getJSON(url string, v any) (found bool, err error) {
+	body, found, err := b.get(url)
+	if err != nil || !found {
+		return false, err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", url, err)
+	}
+	return true, nil
+}
+
+// githubToken returns github_token from config.yaml, else GITHUB_TOKEN
+func githubToken() string {
+	if cfg, err := config.Load(); err == nil && cfg.GitHubToken != "" {
+		return cfg.GitHubToken
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
+// gitlabToken returns gitlab_token from config.yaml, else GITLAB_TOKEN
+func gitlabToken() string {
+	if cfg, err := config.Load(); err == nil && cfg.GitLabToken != "" {
+		return cfg.GitLabToken
+	}
+	return os.Getenv("GITLAB_TOKEN")
+}
+
+// bufToken returns buf_token from config.yaml, else BUF_TOKEN
+func bufToken() string {
+	if cfg, err := config.Load(); err == nil && cfg.BufToken != "" {
+		return cfg.BufToken
+	}
+	return os.Getenv("BUF_TOKEN")
+}
+
+// stackExchangeKey returns stackexchange_key from config.yaml, else
+// STACKEXCHANGE_KEY
+func stackExchangeKey() string {
+	if cfg, err := config.Load(); err == nil && cfg.StackExchangeKey != "" {
+		return cfg.StackExchangeKey
+	}
+	return os.Getenv("STACKEXCHANGE_KEY")
+}
@@ -0,0 +1,32 @@
+package fetcher
+
+import "testing"
+
+// TestNewRequestGitHubToken checks that the GitHub token goes to the GitHub
+// API and nowhere else
+func TestNewRequestGitHubToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	req, err := newRequest("https://api.github.com/repos/pulumi/pulumi/releases/latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("GitHub API request Authorization = %q", got)
+	}
+	if got := req.Header.Get("Accept"); got != "application/vnd.github+json" {
+		t.Errorf("GitHub API request Accept = %q", got)
+	}
+
+	req, err = newRequest("https://registry.npmjs.org/@pulumi/pulumi/3.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("Authorization"); got != "" {
+		t.Errorf("npm request Authorization = %q, want none", got)
+	}
+	if got := req.Header.Get("User-Agent"); got != "open-context-mcp-server" {
+		t.Errorf("npm request User-Agent = %q", got)
+	}
+}
@@ -0,0 +1,470 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// bufRegistryURL serves the Buf Schema Registry's Connect API, whose
+	// procedures take and return JSON over POST
+	bufRegistryURL = "https://buf.build"
+
+	// maxBufModuleChars keeps modules with hundreds of messages, such as
+	// googleapis, to a reference-sized answer
+	maxBufModuleChars = 40000
+	// maxBufReadmeChars leaves room for the services and types after a
+	// long README
+	maxBufReadmeChars = 8000
+	// maxBufSummaryChars shortens the comment of a definition to its gist
+	maxBufSummaryChars = 160
+)
+
+var (
+	bufNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	// bufRefPattern matches labels ("main", "v1.2.0") and commit IDs
+	bufRefPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+)
+
+var errBufNotFound = errors.New("not found")
+
+// BufModuleInfo is a module of the Buf Schema Registry at a label or commit
+type BufModuleInfo struct {
+	// Module is the full name, e.g. "buf.build/bufbuild/protovalidate"
+	Module      string `yaml:"module"`
+	Ref         string `yaml:"ref"`
+	Commit      string `yaml:"commit"`
+	CommitDate  string `yaml:"commitDate"`
+	Description string `yaml:"description"`
+	// Source is the source control URL of the commit or the module
+	Source  string `yaml:"source"`
+	Content string `yaml:"-"`
+}
+
+type BufFetcher struct {
+	*BaseFetcher
+}
+
+func NewBufFetcher(cacheDir string) *BufFetcher {
+	return &BufFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// bufFile is a file of a module's download; Content is base64 in JSON
+type bufFile struct {
+	Path    string `json:"path"`
+	Content []byte `json:"content"`
+}
+
+// FetchBufModule fetches a module ("bufbuild/protovalidate",
+// "buf.build/googleapis/googleapis", or with its ref as
+// "bufbuild/protovalidate:v0.8.0") at a label or commit (the default label
+// when empty): its README, services with their RPCs, and messages and enums
+// by package
+func (f *BufFetcher) FetchBufModule(module, ref string) (*BufModuleInfo, error) {
+	module = strings.Trim(strings.TrimSpace(module), "/")
+	module = strings.TrimPrefix(strings.TrimPrefix(module, "https://"), "buf.build/")
+	if name, r, ok := strings.Cut(module, ":"); ok {
+		if ref != "" && ref != r {
+			return nil, fmt.Errorf("%s names ref %s, not %s", module, r, ref)
+		}
+		module, ref = name, r
+	}
+	owner, name, ok := strings.Cut(module, "/")
+	if !ok || !bufNamePattern.MatchString(owner) || !bufNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid module %q (expected 'owner/module' on buf.build, e.g. 'bufbuild/protovalidate')", module)
+	}
+	ref = strings.TrimSpace(ref)
+	if ref != "" && !bufRefPattern.MatchString(ref) {
+		return nil, fmt.Errorf("invalid ref %q (expected a label such as 'main' or 'v1.0.0', or a commit ID)", ref)
+	}
+
+	return shareFetch(f.flights, flightKey("FetchBufModule", owner, name, ref), f.modulePath(owner, name, ref), func() (*BufModuleInfo, error) {
+		return f.fetchBufModule(owner, name, ref)
+	})
+}
+
+// modulePath returns the cache path of the info on a module
+func (f *BufFetcher) modulePath(owner, name, ref string) string {
+	return f.getCache().GetFilePath("buf", "modules", fmt.Sprintf("%s.md", cache.EntryName(owner, name, ref)))
+}
+
+func (f *BufFetcher) fetchBufModule(owner, name, ref string) (*BufModuleInfo, error) {
+	fullName := fmt.Sprintf("buf.build/%s/%s", owner, name)
+
+	// Check cache first
+	cachedPath := f.modulePath(owner, name, ref)
+	info, err := f.loadBufModuleFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Buf module '%s' from cache\n", fullName)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Buf module '%s' from the Buf Schema Registry...\n", fullName)
+
+	moduleName := map[string]string{"owner": owner, "module": name}
+	var modules struct {
+		Modules []struct {
+			Description      string `json:"description"`
+			URL              string `json:"url"`
+			DefaultLabelName string `json:"defaultLabelName"`
+		} `json:"modules"`
+	}
+	err = f.call("buf.registry.module.v1.ModuleService/GetModules",
+		map[string]interface{}{"moduleRefs": []interface{}{map[string]interface{}{"name": moduleName}}}, &modules)
+	if errors.Is(err, errBufNotFound) || (err == nil && len(modules.Modules) == 0) {
+		return nil, fmt.Errorf("module %s not found", fullName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch module %s: %w", fullName, err)
+	}
+	module := modules.Modules[0]
+
+	info = &BufModuleInfo{Module: fullName, Ref: ref, Description: module.Description, Source: module.URL}
+	if info.Ref == "" {
+		info.Ref = module.DefaultLabelName
+	}
+
+	// A resource name with a ref resolves it as a label, else as a commit
+	resourceName := map[string]string{"owner": owner, "module": name}
+	if info.Ref != "" {
+		resourceName["ref"] = info.Ref
+	}
+	var download struct {
+		Contents []struct {
+			Commit struct {
+				ID               string `json:"id"`
+				CreateTime       string `json:"createTime"`
+				SourceControlURL string `json:"sourceControlUrl"`
+			} `json:"commit"`
+			Files []bufFile `json:"files"`
+		} `json:"contents"`
+	}
+	err = f.call("buf.registry.module.v1.DownloadService/Download",
+		map[string]interface{}{"values": []interface{}{map[string]interface{}{"resourceRef": map[string]interface{}{"name": resourceName}}}}, &download)
+	if errors.Is(err, errBufNotFound) || (err == nil && len(download.Contents) == 0) {
+		return nil, fmt.Errorf("module %s has no label or commit %s", fullName, info.Ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download module %s: %w", fullName, err)
+	}
+	contents := download.Contents[0]
+
+	info.Commit = contents.Commit.ID
+	if t, err := time.Parse(time.RFC3339, contents.Commit.CreateTime); err == nil {
+		info.CommitDate = t.Format("2006-01-02")
+	}
+	if contents.Commit.SourceControlURL != "" {
+		info.Source = contents.Commit.SourceControlURL
+	}
+
+	info.Content = f.buildBufModuleContent(info, contents.Files)
+
+	if err := f.saveBufModuleAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache Buf module: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// call invokes a procedure of the registry's Connect API with a JSON
+// request, decoding its JSON response into v
+func (f *BufFetcher) call(procedure string, request, v interface{}) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequest("POST", bufRegistryURL+"/"+procedure, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connect-Protocol-Version", "1")
+	if token := bufToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Connect errors carry a code and a message
+		var connectErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &connectErr)
+		if connectErr.Code == "not_found" || resp.StatusCode == http.StatusNotFound {
+			return errBufNotFound
+		}
+		if connectErr.Message != "" {
+			return fmt.Errorf("Buf Schema Registry returned status %d: %s", resp.StatusCode, connectErr.Message)
+		}
+		return fmt.Errorf("Buf Schema Registry returned status %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse Buf Schema Registry data: %w", err)
+	}
+	return nil
+}
+
+func (f *BufFetcher) buildBufModuleContent(info *BufModuleInfo, files []bufFile) string {
+	var readme, readmeFile string
+	var protos []*protoFile
+	for _, file := range files {
+		switch {
+		case strings.HasSuffix(file.Path, ".proto"):
+			protos = append(protos, parseProtoFile(string(file.Content)))
+		case readme == "" && (file.Path == "README.md" || file.Path == "buf.md"):
+			readme, readmeFile = string(file.Content), file.Path
+		}
+	}
+
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Module)
+	if info.Description != "" {
+		fmt.Fprintf(&content, "**Description:** %s\n\n", info.Description)
+	}
+	if info.Ref != "" {
+		fmt.Fprintf(&content, "**Ref:** %s\n\n", info.Ref)
+	}
+	if info.Commit != "" {
+		fmt.Fprintf(&content, "**Commit:** %s", info.Commit)
+		if info.CommitDate != "" {
+			fmt.Fprintf(&content, " (%s)", info.CommitDate)
+		}
+		content.WriteString("\n\n")
+	}
+	if info.Source != "" {
+		fmt.Fprintf(&content, "**Source:** %s\n\n", info.Source)
+	}
+
+	packages := protoPackages(protos)
+	if len(packages) > 0 {
+		names := make([]string, 0, len(packages))
+		for _, pkg := range packages {
+			names = append(names, pkg.name)
+		}
+		fmt.Fprintf(&content, "**Packages:** %s\n\n", strings.Join(names, ", "))
+	}
+
+	var body strings.Builder
+	if readme != "" {
+		text := markdown.DemoteHeadings(strings.TrimSpace(readmeMarkdown(readme, readmeFile, "")), 1)
+		body.WriteString(strings.TrimSpace(truncateMarkdown(text, maxBufReadmeChars, "The README is truncated; see the module's page for the rest.")))
+		body.WriteString("\n\n")
+	}
+	writeProtoServices(&body, packages)
+	writeProtoTypes(&body, packages)
+	content.WriteString(strings.TrimSpace(truncateMarkdown(body.String(), maxBufModuleChars, "The listing is truncated; see the module's documentation for the rest.")))
+	content.WriteString("\n\n")
+
+	_, modulePath, _ := strings.Cut(info.Module, "/")
+	content.WriteString("## Usage\n\n")
+	content.WriteString("Add the module to the deps of `buf.yaml`, then run `buf dep update` to pin it in `buf.lock`:\n\n")
+	content.WriteString("```yaml\n")
+	content.WriteString("version: v2\n")
+	content.WriteString("deps:\n")
+	fmt.Fprintf(&content, "  - %s\n", info.Module)
+	content.WriteString("```\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	if info.Ref != "" {
+		fmt.Fprintf(&content, "- [Generated documentation](%s/%s/docs/%s)\n", bufRegistryURL, modulePath, info.Ref)
+	} else {
+		fmt.Fprintf(&content, "- [Generated documentation](%s/%s/docs)\n", bufRegistryURL, modulePath)
+	}
+	fmt.Fprintf(&content, "- [Buf Schema Registry](%s/%s)\n", bufRegistryURL, modulePath)
+
+	return content.String()
+}
+
+// protoPackage gathers the definitions of the files of one package
+type protoPackage struct {
+	name     string
+	services []protoService
+	messages []protoType
+	enums    []protoType
+}
+
+// protoPackages merges files by package, in package order
+func protoPackages(files []*protoFile) []*protoPackage {
+	byName := make(map[string]*protoPackage)
+	for _, file := range files {
+		pkg := byName[file.pkg]
+		if pkg == nil {
+			pkg = &protoPackage{name: file.pkg}
+			byName[file.pkg] = pkg
+		}
+		pkg.services = append(pkg.services, file.services...)
+		pkg.messages = append(pkg.messages, file.messages...)
+		pkg.enums = append(pkg.enums, file.enums...)
+	}
+
+	packages := make([]*protoPackage, 0, len(byName))
+	for _, name := range sortedKeys(byName) {
+		packages = append(packages, byName[name])
+	}
+	return packages
+}
+
+func writeProtoServices(w *strings.Builder, packages []*protoPackage) {
+	var any bool
+	for _, pkg := range packages {
+		for _, svc := range pkg.services {
+			if !any {
+				w.WriteString("## Services\n\n")
+				any = true
+			}
+			fmt.Fprintf(w, "### `%s`\n\n", qualifiedProtoName(pkg.name, svc.name))
+			if svc.comment != "" {
+				w.WriteString(collapseSpace(svc.comment) + "\n\n")
+			}
+			for _, m := range svc.methods {
+				fmt.Fprintf(w, "- `%s`", m.signature())
+				if s := protoSummary(m.comment); s != "" {
+					w.WriteString(": " + s)
+				}
+				w.WriteString("\n")
+			}
+			if len(svc.methods) > 0 {
+				w.WriteString("\n")
+			}
+		}
+	}
+}
+
+func writeProtoTypes(w *strings.Builder, packages []*protoPackage) {
+	var any bool
+	for _, pkg := range packages {
+		if len(pkg.messages) == 0 && len(pkg.enums) == 0 {
+			continue
+		}
+		if !any {
+			w.WriteString("## Types\n\n")
+			any = true
+		}
+		name := pkg.name
+		if name == "" {
+			name = "(no package)"
+		}
+		fmt.Fprintf(w, "### `%s`\n\n", name)
+		for _, group := range []struct {
+			title string
+			types []protoType
+		}{{"Messages", pkg.messages}, {"Enums", pkg.enums}} {
+			if len(group.types) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "**%s:**\n\n", group.title)
+			for _, t := range group.types {
+				fmt.Fprintf(w, "- `%s`", t.name)
+				if s := protoSummary(t.comment); s != "" {
+					w.WriteString(": " + s)
+				}
+				w.WriteString("\n")
+			}
+			w.WriteString("\n")
+		}
+	}
+}
+
+func qualifiedProtoName(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// protoSummary is the first sentence of a comment, shortened
+func protoSummary(comment string) string {
+	s := collapseSpace(comment)
+	if i := strings.Index(s, ". "); i >= 0 {
+		s = s[:i+1]
+	}
+	if len(s) > maxBufSummaryChars {
+		cut := strings.LastIndex(s[:maxBufSummaryChars], " ")
+		if cut <= 0 {
+			cut = maxBufSummaryChars
+		}
+		s = s[:cut] + "..."
+	}
+	return s
+}
+
+func (f *BufFetcher) saveBufModuleAsMarkdown(filePath string, info *BufModuleInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "module: \"%s\"\n", info.Module)
+	if info.Ref != "" {
+		fmt.Fprintf(&content, "ref: \"%s\"\n", escapeYAML(info.Ref))
+	}
+	if info.Commit != "" {
+		fmt.Fprintf(&content, "commit: \"%s\"\n", info.Commit)
+	}
+	if info.CommitDate != "" {
+		fmt.Fprintf(&content, "commitDate: \"%s\"\n", info.CommitDate)
+	}
+	if info.Description != "" {
+		fmt.Fprintf(&content, "description: \"%s\"\n", escapeYAML(info.Description))
+	}
+	if info.Source != "" {
+		fmt.Fprintf(&content, "source: \"%s\"\n", escapeYAML(info.Source))
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *BufFetcher) loadBufModuleFromMarkdown(filePath string) (*BufModuleInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info BufModuleInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
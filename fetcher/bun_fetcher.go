@@ -0,0 +1,360 @@
+package fetcher
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/net/html"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	bunReleasesAPI = "https://api.github.com/repos/oven-sh/bun/releases"
+	bunBlogURL     = "https://bun.sh/blog"
+
+	// maxBunHighlights caps the blog post sections listed as highlights
+	maxBunHighlights = 25
+)
+
+type BunVersionInfo struct {
+	Version     string         `yaml:"version"`
+	ReleaseDate string         `yaml:"releaseDate"`
+	ReleaseURL  string         `yaml:"releaseURL"`
+	BlogURL     string         `yaml:"blogURL"`
+	Highlights  []BunHighlight `yaml:"highlights"`
+	// Degraded lists the content checks the blog post failed
+	Degraded []string `yaml:"degraded"`
+	Content  string   `yaml:"-"`
+}
+
+// BunHighlight is a section of the blog post announcing a Bun release
+type BunHighlight struct {
+	Title string `yaml:"title"`
+	URL   string `yaml:"url"`
+}
+
+type BunFetcher struct {
+	*BaseFetcher
+}
+
+func NewBunFetcher(cacheDir string) *BunFetcher {
+	return &BunFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchBunVersion fetches the release notes, blog post highlights, and
+// install commands of a Bun version ("1.1.38", "v1.1.38", or "bun-v1.1.38"),
+// or of the latest release when version is empty or "latest"
+func (f *BunFetcher) FetchBunVersion(version string) (*BunVersionInfo, error) {
+	version = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(version), "bun-"), "v")
+	if version == "latest" {
+		version = ""
+	}
+	return shareFetch(f.flights, flightKey("FetchBunVersion", version), f.versionPath(version), func() (*BunVersionInfo, error) {
+		return f.fetchBunVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Bun version
+func (f *BunFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("bun", "versions", fmt.Sprintf("%s.md", cache.EntryName(cmp.Or(version, "latest"))))
+}
+
+func (f *BunFetcher) fetchBunVersion(version string) (*BunVersionInfo, error) {
+	// Check cache first
+	cachedPath := f.versionPath(version)
+	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
+	if err == nil && versionInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Bun version '%s' from cache\n", versionInfo.Version)
+		return versionInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Bun version '%s' from GitHub...\n", cmp.Or(version, "latest"))
+
+	// Releases are tagged bun-v1.1.38
+	apiURL := bunReleasesAPI + "/latest"
+	if version != "" {
+		apiURL = fmt.Sprintf("%s/tags/bun-v%s", bunReleasesAPI, version)
+	}
+	req, err := newRequest(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Bun release: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("bun version %s not found", version)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	release, err := parseRelease(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The highlights are optional; the release notes stand on their own
+	blogURL := fmt.Sprintf("%s/bun-v%s", bunBlogURL, bunVersion(release.TagName))
+	highlights, err := f.fetchHighlights(blogURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch Bun blog post: %v\n", err)
+		blogURL = ""
+	}
+	var degraded []string
+	if blogURL != "" {
+		degraded = checkShape("bun", blogURL, shapeCheck{"highlights", len(highlights) > 0})
+	}
+
+	versionInfo = f.renderVersion(release, blogURL, highlights)
+	versionInfo.Degraded = degraded
+
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
+	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+	}
+
+	return versionInfo, nil
+}
+
+// fetchHighlights lists the sections of the blog post announcing a release
+func (f *BunFetcher) fetchHighlights(blogURL string) ([]BunHighlight, error) {
+	req, err := http.NewRequest("GET", blogURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bun.sh returned status %d for %s", resp.StatusCode, blogURL)
+	}
+
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return parseBunHighlights(page, blogURL)
+}
+
+// parseBunHighlights reads the second-level headings of a blog post, which
+// name its features, linked to their anchors
+func parseBunHighlights(page []byte, blogURL string) ([]BunHighlight, error) {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	root := doc
+	if article := findElement(doc, "article"); article != nil {
+		root = article
+	}
+
+	var highlights []BunHighlight
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if len(highlights) == maxBunHighlights {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "h2" {
+			if title := collapseSpace(getText(n)); title != "" {
+				highlight := BunHighlight{Title: title, URL: blogURL}
+				for _, attr := range n.Attr {
+					if attr.Key == "id" && attr.Val != "" {
+						highlight.URL = blogURL + "#" + attr.Val
+					}
+				}
+				highlights = append(highlights, highlight)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return highlights, nil
+}
+
+// bunVersion strips the "bun-v" prefix of a release tag
+func bunVersion(tag string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(tag, "bun-"), "v")
+}
+
+// renderVersion renders a release of the GitHub API
+func (f *BunFetcher) renderVersion(release *githubRelease, blogURL string, highlights []BunHighlight) *BunVersionInfo {
+	info := &BunVersionInfo{
+		Version:     bunVersion(release.TagName),
+		ReleaseDate: releaseDate(release.PublishedAt),
+		ReleaseURL:  release.HTMLURL,
+		BlogURL:     blogURL,
+		Highlights:  highlights,
+	}
+	info.Content = f.buildVersionContent(info, release.Body)
+	return info
+}
+
+func (f *BunFetcher) buildVersionContent(info *BunVersionInfo, releaseNotes string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Bun %s\n\n", info.Version)
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "**Release Notes:** [bun-v%s](%s)\n\n", info.Version, info.ReleaseURL)
+	}
+	if info.BlogURL != "" {
+		fmt.Fprintf(&content, "**Blog Post:** [Bun v%s](%s)\n\n", info.Version, info.BlogURL)
+	}
+
+	if len(info.Highlights) > 0 {
+		content.WriteString("## Highlights\n\n")
+		for _, h := range info.Highlights {
+			fmt.Fprintf(&content, "- [%s](%s)\n", h.Title, h.URL)
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("### Using the install script (macOS, Linux)\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "curl -fsSL https://bun.sh/install | bash -s \"bun-v%s\"\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using the install script (Windows)\n\n")
+	content.WriteString("```powershell\n")
+	fmt.Fprintf(&content, "iex \"& {$(irm https://bun.sh/install.ps1)} -Version %s\"\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using npm\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "npm install -g bun@%s\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using Docker\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "docker run --rm oven/bun:%s bun --version\n", info.Version)
+	content.WriteString("```\n\n")
+
+	notes := strings.TrimSpace(strings.ReplaceAll(releaseNotes, "\r\n", "\n"))
+	if notes != "" {
+		content.WriteString("## Release Notes\n\n")
+		notes = markdown.DemoteHeadings(notes, 1)
+		content.WriteString(strings.TrimSpace(truncateMarkdown(notes, maxReleaseNotesChars, "The release notes are truncated; see GitHub for the rest.")))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("For detailed documentation, visit:\n\n")
+	content.WriteString("- [Bun Documentation](https://bun.sh/docs)\n")
+	content.WriteString("- [Bun Blog](https://bun.sh/blog)\n")
+	content.WriteString("- [Bun GitHub Repository](https://github.com/oven-sh/bun)\n")
+	content.WriteString("- [Bun Release Notes](https://github.com/oven-sh/bun/releases)\n")
+
+	return content.String()
+}
+
+func (f *BunFetcher) saveVersionInfoAsMarkdown(filePath string, info *BunVersionInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "releaseURL: \"%s\"\n", escapeYAML(info.ReleaseURL))
+	}
+	if info.BlogURL != "" {
+		fmt.Fprintf(&content, "blogURL: \"%s\"\n", escapeYAML(info.BlogURL))
+	}
+	// Kept so stale entries re-render without fetching the blog again
+	if len(info.Highlights) > 0 {
+		content.WriteString("highlights:\n")
+		for _, h := range info.Highlights {
+			fmt.Fprintf(&content, "  - title: \"%s\"\n", escapeYAML(h.Title))
+			fmt.Fprintf(&content, "    url: \"%s\"\n", escapeYAML(h.URL))
+		}
+	}
+	writeDegraded(&content, info.Degraded)
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *BunFetcher) loadVersionInfoFromMarkdown(filePath string) (*BunVersionInfo, error) {
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta struct {
+		BunVersionInfo `yaml:",inline"`
+		Template       int `yaml:"template"`
+	}
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if degradedExpired(f.getCache(), f.agePath(filePath), meta.Degraded) {
+		return nil, fmt.Errorf("degraded entry expired")
+	}
+
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if release, err := parseRelease(payload); err == nil {
+			info := f.renderVersion(release, meta.BlogURL, meta.Highlights)
+			info.Degraded = meta.Degraded
+			fmt.Fprintf(os.Stderr, "Re-rendered Bun version '%s' with current templates\n", info.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
+	info := meta.BunVersionInfo
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -0,0 +1,258 @@
+package fetcher
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	cdkReleasesAPI = "https://api.github.com/repos/aws/aws-cdk/releases"
+
+	// cdkLibPackage is the construct library, published under one name to
+	// npm and PyPI
+	cdkLibPackage = "aws-cdk-lib"
+)
+
+// CDKVersionInfo is a release of the AWS CDK construct library, with the
+// versions of constructs, Node.js, and Python it requires
+type CDKVersionInfo struct {
+	Version        string `yaml:"version"`
+	ReleaseDate    string `yaml:"releaseDate"`
+	ReleaseURL     string `yaml:"releaseURL"`
+	Constructs     string `yaml:"constructs"`
+	NodeEngine     string `yaml:"nodeEngine"`
+	PyPIVersion    string `yaml:"pypiVersion"`
+	RequiresPython string `yaml:"requiresPython"`
+	Content        string `yaml:"-"`
+}
+
+// cdkNPMVersion is the part of the npm version document of aws-cdk-lib
+// read here
+type cdkNPMVersion struct {
+	Version          string            `json:"version"`
+	PeerDependencies map[string]string `json:"peerDependencies"`
+	Engines          map[string]string `json:"engines"`
+}
+
+// cdkPyPIRelease is the part of the PyPI JSON document of aws-cdk-lib read
+// here
+type cdkPyPIRelease struct {
+	Info struct {
+		Version        string `json:"version"`
+		RequiresPython string `json:"requires_python"`
+	} `json:"info"`
+}
+
+type CDKFetcher struct {
+	*BaseFetcher
+}
+
+func NewCDKFetcher(cacheDir string) *CDKFetcher {
+	return &CDKFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchCDKVersion fetches the release notes of an AWS CDK version ("2.170.0"
+// or "v2.170.0"), or of the latest release when version is empty or
+// "latest", with the aws-cdk-lib versions on npm and PyPI and install
+// commands
+func (f *CDKFetcher) FetchCDKVersion(version string) (*CDKVersionInfo, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "latest" {
+		version = ""
+	}
+	return shareFetch(f.flights, flightKey("FetchCDKVersion", version), f.versionPath(version), func() (*CDKVersionInfo, error) {
+		return f.fetchCDKVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a AWS CDK version
+func (f *CDKFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("cdk", "versions", fmt.Sprintf("%s.md", cache.EntryName(cmp.Or(version, "latest"))))
+}
+
+func (f *CDKFetcher) fetchCDKVersion(version string) (*CDKVersionInfo, error) {
+	// Check cache first
+	cachedPath := f.versionPath(version)
+	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
+	if err == nil && versionInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded AWS CDK version '%s' from cache\n", versionInfo.Version)
+		return versionInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching AWS CDK version '%s' from GitHub...\n", cmp.Or(version, "latest"))
+
+	apiURL := cdkReleasesAPI + "/latest"
+	if version != "" {
+		apiURL = fmt.Sprintf("%s/tags/v%s", cdkReleasesAPI, url.PathEscape(version))
+	}
+	var release githubRelease
+	found, err := f.getJSON(apiURL, &release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch AWS CDK release: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("AWS CDK version %s not found", version)
+	}
+	version = strings.TrimPrefix(release.TagName, "v")
+
+	// The registries are optional; the release notes stand on their own
+	var npmVersion cdkNPMVersion
+	if _, err := f.getJSON(fmt.Sprintf("https://registry.npmjs.org/%s/%s", cdkLibPackage, url.PathEscape(version)), &npmVersion); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s from npm: %v\n", cdkLibPackage, err)
+	}
+	var pypiRelease cdkPyPIRelease
+	if _, err := f.getJSON(fmt.Sprintf("https://pypi.org/pypi/%s/%s/json", cdkLibPackage, url.PathEscape(version)), &pypiRelease); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s from PyPI: %v\n", cdkLibPackage, err)
+	}
+
+	versionInfo = &CDKVersionInfo{
+		Version:        version,
+		ReleaseDate:    releaseDate(release.PublishedAt),
+		ReleaseURL:     release.HTMLURL,
+		Constructs:     npmVersion.PeerDependencies["constructs"],
+		NodeEngine:     npmVersion.Engines["node"],
+		PyPIVersion:    pypiRelease.Info.Version,
+		RequiresPython: pypiRelease.Info.RequiresPython,
+	}
+	versionInfo.Content = f.buildVersionContent(versionInfo, release.Body)
+
+	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+	}
+
+	return versionInfo, nil
+}
+
+func (f *CDKFetcher) buildVersionContent(info *CDKVersionInfo, releaseNotes string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# AWS CDK %s\n\n", info.Version)
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "**Release Notes:** [v%s](%s)\n\n", info.Version, info.ReleaseURL)
+	}
+
+	content.WriteString("## Construct Library\n\n")
+	content.WriteString("| Package | Registry | Version |\n")
+	content.WriteString("|---------|----------|---------|\n")
+	fmt.Fprintf(&content, "| [aws-cdk-lib](https://www.npmjs.com/package/aws-cdk-lib/v/%s) | npm | %s |\n", info.Version, info.Version)
+	fmt.Fprintf(&content, "| [aws-cdk-lib](https://pypi.org/project/aws-cdk-lib/%s/) | PyPI | %s |\n", info.Version, cmp.Or(info.PyPIVersion, "not published"))
+	fmt.Fprintf(&content, "| Experimental (alpha) modules | npm, PyPI | %s-alpha.0 |\n\n", info.Version)
+	if info.Constructs != "" {
+		fmt.Fprintf(&content, "**Requires constructs:** %s\n\n", info.Constructs)
+	}
+	if info.NodeEngine != "" {
+		fmt.Fprintf(&content, "**Requires Node.js:** %s\n\n", info.NodeEngine)
+	}
+	if info.RequiresPython != "" {
+		fmt.Fprintf(&content, "**Requires Python:** %s\n\n", info.RequiresPython)
+	}
+
+	constructs := cmp.Or(info.Constructs, "^10.0.0")
+	content.WriteString("## Installation\n\n")
+	content.WriteString("### TypeScript / JavaScript (npm)\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "npm install aws-cdk-lib@%s constructs@%s\n", info.Version, constructs)
+	content.WriteString("# Experimental modules are versioned with an -alpha suffix\n")
+	fmt.Fprintf(&content, "npm install @aws-cdk/aws-apprunner-alpha@%s-alpha.0\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Python (pip)\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "pip install aws-cdk-lib==%s \"constructs>=10.0.0,<11.0.0\"\n", info.Version)
+	content.WriteString("# Experimental modules are versioned with an a0 suffix\n")
+	fmt.Fprintf(&content, "pip install aws-cdk.aws-apprunner-alpha==%sa0\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### CDK CLI\n\n")
+	content.WriteString("The `cdk` CLI is released separately (versions 2.1000.0 and later) and works with any aws-cdk-lib 2.x.\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("npm install -g aws-cdk\n")
+	content.WriteString("```\n\n")
+
+	notes := strings.TrimSpace(strings.ReplaceAll(releaseNotes, "\r\n", "\n"))
+	if notes != "" {
+		content.WriteString("## Release Notes\n\n")
+		notes = markdown.DemoteHeadings(notes, 1)
+		content.WriteString(strings.TrimSpace(truncateMarkdown(notes, maxReleaseNotesChars, "The release notes are truncated; see GitHub for the rest.")))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("For detailed documentation, visit:\n\n")
+	content.WriteString("- [AWS CDK Developer Guide](https://docs.aws.amazon.com/cdk/v2/guide/home.html)\n")
+	content.WriteString("- [AWS CDK API Reference](https://docs.aws.amazon.com/cdk/api/v2/)\n")
+	content.WriteString("- [Construct Hub](https://constructs.dev)\n")
+	content.WriteString("- [AWS CDK Release Notes](https://github.com/aws/aws-cdk/releases)\n")
+
+	return content.String()
+}
+
+func (f *CDKFetcher) saveVersionInfoAsMarkdown(filePath string, info *CDKVersionInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "version: \"%s\"\n", escapeYAML(info.Version))
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "releaseURL: \"%s\"\n", escapeYAML(info.ReleaseURL))
+	}
+	if info.Constructs != "" {
+		fmt.Fprintf(&content, "constructs: \"%s\"\n", escapeYAML(info.Constructs))
+	}
+	if info.NodeEngine != "" {
+		fmt.Fprintf(&content, "nodeEngine: \"%s\"\n", escapeYAML(info.NodeEngine))
+	}
+	if info.PyPIVersion != "" {
+		fmt.Fprintf(&content, "pypiVersion: \"%s\"\n", escapeYAML(info.PyPIVersion))
+	}
+	if info.RequiresPython != "" {
+		fmt.Fprintf(&content, "requiresPython: \"%s\"\n", escapeYAML(info.RequiresPython))
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *CDKFetcher) loadVersionInfoFromMarkdown(filePath string) (*CDKVersionInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info CDKVersionInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -0,0 +1,309 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+	"github.com/incu6us/open-context/semver"
+)
+
+const (
+	// maxChangelogPages bounds the release list pages read while looking for
+	// the older version, at 100 releases a page
+	maxChangelogPages = 10
+
+	// maxChangelogReleases caps the releases one changelog includes
+	maxChangelogReleases = 60
+
+	// maxChangelogChars bounds the combined release notes
+	maxChangelogChars = 120000
+)
+
+// releaseRepo is a product whose releases are published on GitHub
+type releaseRepo struct {
+	name       string
+	repository string
+	// tagPrefix precedes the version in release tags, e.g. "v"
+	tagPrefix string
+}
+
+// releaseRepos are the products with release tools, by the names the tools
+// and smart_docs accept
+var releaseRepos = map[string]releaseRepo{
+	"terraform":  {"Terraform", "hashicorp/terraform", "v"},
+	"helm":       {"Helm", "helm/helm", "v"},
+	"kubernetes": {"Kubernetes", "kubernetes/kubernetes", "v"},
+	"k8s":        {"Kubernetes", "kubernetes/kubernetes", "v"},
+	"react":      {"React", "facebook/react", "v"},
+	"nextjs":     {"Next.js", "vercel/next.js", "v"},
+	"next":       {"Next.js", "vercel/next.js", "v"},
+	"typescript": {"TypeScript", "microsoft/TypeScript", "v"},
+	"ansible":    {"Ansible", "ansible/ansible", "v"},
+	"jenkins":    {"Jenkins", "jenkinsci/jenkins", "jenkins-"},
+	"node":       {"Node.js", "nodejs/node", "v"},
+	"nodejs":     {"Node.js", "nodejs/node", "v"},
+}
+
+// Changelog is the release notes of every release between two versions
+type Changelog struct {
+	Product    string `yaml:"product"`
+	Repository string `yaml:"repository"`
+	From       string `yaml:"from"`
+	To         string `yaml:"to"`
+	Releases   int    `yaml:"releases"`
+	Content    string `yaml:"-"`
+}
+
+type ChangelogFetcher struct {
+	*BaseFetcher
+}
+
+func NewChangelogFetcher(cacheDir string) *ChangelogFetcher {
+	return &ChangelogFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchChangelog collects the GitHub release notes of a product after from
+// up to and including to, oldest first. product is a name from
+// releaseRepos or any "owner/repo"; an empty to means the latest release.
+func (f *ChangelogFetcher) FetchChangelog(product, from, to string) (*Changelog, error) {
+	return shareFetch(f.flights, flightKey("FetchChangelog", product, from, to), "", func() (*Changelog, error) {
+		return f.fetchChangelog(product, from, to)
+	})
+}
+
+func (f *ChangelogFetcher) fetchChangelog(product, from, to string) (*Changelog, error) {
+	repo, ok := releaseRepos[strings.ToLower(product)]
+	if !ok {
+		if strings.Count(product, "/") != 1 {
+			return nil, fmt.Errorf("unknown product %q: use one of %s, or a GitHub repository as owner/repo", product, strings.Join(releaseProducts(), ", "))
+		}
+		repo = releaseRepo{name: product, repository: product}
+	}
+	from = strings.TrimPrefix(strings.TrimPrefix(from, repo.tagPrefix), "v")
+	to = strings.TrimPrefix(strings.TrimPrefix(to, repo.tagPrefix), "v")
+	if from == "" {
+		return nil, fmt.Errorf("from version is required")
+	}
+	if to != "" && semver.Compare(from, to) >= 0 {
+		return nil, fmt.Errorf("from version %s must be older than to version %s", from, to)
+	}
+
+	// Check cache first
+	cachedPath := f.getCache().GetFilePath("changelogs", cache.EntryName(repo.repository), fmt.Sprintf("%s.md", cache.EntryName(from, to)))
+	changelog, err := f.loadChangelogFromMarkdown(cachedPath)
+	if err == nil && changelog != nil {
+		fmt.Fprintf(os.Stderr, "Loaded %s changelog %s..%s from cache\n", repo.name, from, to)
+		return changelog, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching %s releases from GitHub...\n", repo.name)
+
+	// Pre-releases are only of interest when upgrading from or to one
+	withPrereleases := semver.IsPrerelease(from) || semver.IsPrerelease(to)
+
+	var releases []githubRelease
+	complete := false
+	for page := 1; page <= maxChangelogPages && !complete; page++ {
+		batch, err := f.listReleases(repo.repository, page)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		allOlder := true
+		for _, r := range batch {
+			version, ok := strings.CutPrefix(r.TagName, repo.tagPrefix)
+			if repo.tagPrefix == "" {
+				version, ok = strings.TrimPrefix(r.TagName, "v"), true
+			}
+			if !ok || version == "" || version[0] < '0' || version[0] > '9' || r.Draft {
+				continue
+			}
+			// Releases are listed newest first, but patch releases of older
+			// lines are interleaved, so the search ends at the from release
+			// itself or at a page with nothing newer than it
+			if cmp := semver.Compare(version, from); cmp <= 0 {
+				if cmp == 0 {
+					complete = true
+				}
+				continue
+			}
+			allOlder = false
+			if to != "" && semver.Compare(version, to) > 0 {
+				continue
+			}
+			if (r.Prerelease || semver.IsPrerelease(version)) && !withPrereleases {
+				continue
+			}
+			r.version = version
+			releases = append(releases, r)
+		}
+		// A short page is the last one
+		if allOlder || len(batch) < 100 {
+			complete = true
+		}
+	}
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no %s releases found after %s", repo.name, from)
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return semver.Compare(releases[i].version, releases[j].version) < 0
+	})
+	if to == "" {
+		to = releases[len(releases)-1].version
+	}
+
+	changelog = &Changelog{
+		Product:    repo.name,
+		Repository: repo.repository,
+		From:       from,
+		To:         to,
+		Releases:   len(releases),
+	}
+	changelog.Content = f.buildChangelogContent(changelog, releases, complete)
+
+	if err := f.saveChangelogAsMarkdown(cachedPath, changelog); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache changelog: %v\n", err)
+	}
+
+	return changelog, nil
+}
+
+func releaseProducts() []string {
+	products := make([]string, 0, len(releaseRepos))
+	for name := range releaseRepos {
+		products = append(products, name)
+	}
+	sort.Strings(products)
+	return products
+}
+
+func (f *ChangelogFetcher) listReleases(repository string, page int) ([]githubRelease, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/releases?per_page=100&page=%d", repository, page)
+	req, err := newRequest(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("repository %s not found", repository)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var releases []githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse release data: %w", err)
+	}
+	return releases, nil
+}
+
+func (f *ChangelogFetcher) buildChangelogContent(changelog *Changelog, releases []githubRelease, complete bool) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s Changes: %s to %s\n\n", changelog.Product, changelog.From, changelog.To)
+
+	fmt.Fprintf(&content, "**Repository:** [%s](https://github.com/%s)\n\n", changelog.Repository, changelog.Repository)
+	fmt.Fprintf(&content, "**Releases:** %d\n\n", len(releases))
+	if !complete {
+		fmt.Fprintf(&content, "The release list ended before %s was reached, so older releases may be missing.\n\n", changelog.From)
+	}
+
+	shown := releases
+	if len(shown) > maxChangelogReleases {
+		// Keep the newest, which are the closest to the target
+		shown = shown[len(shown)-maxChangelogReleases:]
+		fmt.Fprintf(&content, "Only the %d newest releases are included; the %d before them are listed in the table.\n\n", maxChangelogReleases, len(releases)-maxChangelogReleases)
+	}
+
+	content.WriteString("| Version | Released |\n")
+	content.WriteString("|---------|----------|\n")
+	for _, r := range releases {
+		fmt.Fprintf(&content, "| [%s](%s) | %s |\n", r.version, r.HTMLURL, releaseDate(r.PublishedAt))
+	}
+	content.WriteString("\n")
+
+	var notes strings.Builder
+	for _, r := range shown {
+		fmt.Fprintf(&notes, "## %s", r.version)
+		if date := releaseDate(r.PublishedAt); date != "" {
+			fmt.Fprintf(&notes, " (%s)", date)
+		}
+		notes.WriteString("\n\n")
+
+		body := strings.TrimSpace(strings.ReplaceAll(r.Body, "\r\n", "\n"))
+		if body == "" {
+			fmt.Fprintf(&notes, "No release notes; see [%s](%s).\n\n", r.TagName, r.HTMLURL)
+			continue
+		}
+		notes.WriteString(markdown.DemoteHeadings(body, 2))
+		notes.WriteString("\n\n")
+	}
+	content.WriteString(truncateMarkdown(notes.String(), maxChangelogChars, "The release notes are truncated; see the releases on GitHub for the rest."))
+
+	return content.String()
+}
+
+func (f *ChangelogFetcher) saveChangelogAsMarkdown(filePath string, changelog *Changelog) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "product: \"%s\"\n", escapeYAML(changelog.Product))
+	fmt.Fprintf(&content, "repository: \"%s\"\n", escapeYAML(changelog.Repository))
+	fmt.Fprintf(&content, "from: \"%s\"\n", escapeYAML(changelog.From))
+	fmt.Fprintf(&content, "to: \"%s\"\n", escapeYAML(changelog.To))
+	fmt.Fprintf(&content, "releases: %d\n", changelog.Releases)
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(changelog.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *ChangelogFetcher) loadChangelogFromMarkdown(filePath string) (*Changelog, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var changelog Changelog
+	if err := yaml.Unmarshal([]byte(parts[1]), &changelog); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	changelog.Content = strings.TrimSpace(parts[2])
+	return &changelog, nil
+}
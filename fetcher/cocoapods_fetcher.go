@@ -0,0 +1,393 @@
+package fetcher
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/semver"
+)
+
+const cocoaPodsCDNURL = "https://cdn.cocoapods.org"
+
+type CocoaPodInfo struct {
+	Name         string            `yaml:"name"`
+	Version      string            `yaml:"version"`
+	Summary      string            `yaml:"summary"`
+	Homepage     string            `yaml:"homepage"`
+	Repository   string            `yaml:"repository"`
+	License      string            `yaml:"license"`
+	Authors      string            `yaml:"authors"`
+	Platforms    map[string]string `yaml:"-"`
+	Dependencies map[string]string `yaml:"-"`
+	Subspecs     []string          `yaml:"-"`
+	Content      string            `yaml:"-"`
+}
+
+type CocoaPodsFetcher struct {
+	*BaseFetcher
+}
+
+func NewCocoaPodsFetcher(cacheDir string) *CocoaPodsFetcher {
+	return &CocoaPodsFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchPodInfo fetches information about a CocoaPods pod from the CocoaPods CDN
+func (f *CocoaPodsFetcher) FetchPodInfo(podName, version string) (*CocoaPodInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchPodInfo", podName, version), f.podPath(podName, version), func() (*CocoaPodInfo, error) {
+		return f.fetchPodInfo(podName, version)
+	})
+}
+
+// podPath returns the cache path of the info on a pod
+func (f *CocoaPodsFetcher) podPath(podName, version string) string {
+	return f.getCache().GetFilePath("cocoapods", "pods", fmt.Sprintf("%s.md", cache.EntryName(podName, version)))
+}
+
+func (f *CocoaPodsFetcher) fetchPodInfo(podName, version string) (*CocoaPodInfo, error) {
+	// Subspecs ("Firebase/Auth") are published as part of their root pod
+	rootName := strings.SplitN(podName, "/", 2)[0]
+
+	// Check cache first
+	cachedPath := f.podPath(podName, version)
+	podInfo, err := f.loadPodInfoFromMarkdown(cachedPath)
+	if err == nil && podInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded CocoaPod '%s' from cache\n", podName)
+		return podInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching CocoaPod '%s' from cdn.cocoapods.org...\n", podName)
+
+	shard := cocoaPodsShard(rootName)
+
+	if version == "" {
+		version, err = f.fetchLatestVersion(rootName, shard)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	specURL := fmt.Sprintf("%s/Specs/%s/%s/%s/%s.podspec.json",
+		cocoaPodsCDNURL, strings.Join(shard, "/"), rootName, version, rootName)
+	body, err := f.get(specURL)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("cocoapod %s version %s not found", rootName, version)
+	}
+
+	var spec struct {
+		Name         string                 `json:"name"`
+		Version      string                 `json:"version"`
+		Summary      string                 `json:"summary"`
+		Homepage     string                 `json:"homepage"`
+		License      interface{}            `json:"license"`
+		Authors      interface{}            `json:"authors"`
+		Source       map[string]interface{} `json:"source"`
+		Platforms    map[string]interface{} `json:"platforms"`
+		Dependencies map[string][]string    `json:"dependencies"`
+		Subspecs     []struct {
+			Name string `json:"name"`
+		} `json:"subspecs"`
+	}
+	if err := json.Unmarshal(body, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse podspec: %w", err)
+	}
+
+	podInfo = &CocoaPodInfo{
+		Name:         podName,
+		Version:      spec.Version,
+		Summary:      spec.Summary,
+		Homepage:     spec.Homepage,
+		License:      podspecLicense(spec.License),
+		Authors:      podspecAuthors(spec.Authors),
+		Platforms:    make(map[string]string),
+		Dependencies: make(map[string]string),
+	}
+
+	if git, ok := spec.Source["git"].(string); ok {
+		podInfo.Repository = strings.TrimSuffix(git, ".git")
+	}
+
+	for platform, target := range spec.Platforms {
+		if s, ok := target.(string); ok {
+			podInfo.Platforms[platform] = s
+		} else {
+			podInfo.Platforms[platform] = ""
+		}
+	}
+
+	for dep, requirements := range spec.Dependencies {
+		podInfo.Dependencies[dep] = strings.Join(requirements, ", ")
+	}
+
+	for _, subspec := range spec.Subspecs {
+		podInfo.Subspecs = append(podInfo.Subspecs, fmt.Sprintf("%s/%s", spec.Name, subspec.Name))
+	}
+
+	// Build content
+	podInfo.Content = f.buildPodContent(podInfo)
+
+	// Cache the result
+	if err := f.savePodInfoAsMarkdown(cachedPath, podInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache pod info: %v\n", err)
+	}
+
+	return podInfo, nil
+}
+
+// cocoaPodsShard returns the Specs repo shard prefix for a pod: the first three
+// hex characters of the MD5 of its name
+func cocoaPodsShard(name string) []string {
+	sum := md5.Sum([]byte(name))
+	prefix := hex.EncodeToString(sum[:])[:3]
+	return []string{prefix[0:1], prefix[1:2], prefix[2:3]}
+}
+
+// fetchLatestVersion picks the newest stable version from the CDN version index
+func (f *CocoaPodsFetcher) fetchLatestVersion(name string, shard []string) (string, error) {
+	indexURL := fmt.Sprintf("%s/all_pods_versions_%s.txt", cocoaPodsCDNURL, strings.Join(shard, "_"))
+	body, err := f.get(indexURL)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(body), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "/")
+		if len(fields) < 2 || fields[0] != name {
+			continue
+		}
+
+		versions := fields[1:]
+		sort.Slice(versions, func(i, j int) bool {
+			return semver.Compare(versions[i], versions[j]) > 0
+		})
+		for _, v := range versions {
+			if !semver.IsPrerelease(v) {
+				return v, nil
+			}
+		}
+		return versions[0], nil
+	}
+
+	return "", fmt.Errorf("cocoapod %s not found", name)
+}
+
+// get fetches a CDN resource, returning nil without error when it does not exist
+func (f *CocoaPodsFetcher) get(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pod info: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CocoaPods CDN returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return body, nil
+}
+
+// podspecLicense handles both the string and {"type": ...} license forms
+func podspecLicense(v interface{}) string {
+	switch l := v.(type) {
+	case string:
+		return l
+	case map[string]interface{}:
+		if t, ok := l["type"].(string); ok {
+			return t
+		}
+	}
+	return ""
+}
+
+// podspecAuthors handles the string, list, and name->email map author forms
+func podspecAuthors(v interface{}) string {
+	switch a := v.(type) {
+	case string:
+		return a
+	case []interface{}:
+		var names []string
+		for _, name := range a {
+			if s, ok := name.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return strings.Join(names, ", ")
+	case map[string]interface{}:
+		names := make([]string, 0, len(a))
+		for name := range a {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func (f *CocoaPodsFetcher) buildPodContent(info *CocoaPodInfo) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Name)
+
+	if info.Summary != "" {
+		fmt.Fprintf(&content, "**Summary:** %s\n\n", info.Summary)
+	}
+
+	fmt.Fprintf(&content, "**Version:** %s\n\n", info.Version)
+
+	if info.Authors != "" {
+		fmt.Fprintf(&content, "**Authors:** %s\n\n", info.Authors)
+	}
+
+	if info.License != "" {
+		fmt.Fprintf(&content, "**License:** %s\n\n", info.License)
+	}
+
+	if info.Homepage != "" {
+		fmt.Fprintf(&content, "**Homepage:** %s\n\n", info.Homepage)
+	}
+
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "**Repository:** %s\n\n", info.Repository)
+	}
+
+	if len(info.Platforms) > 0 {
+		platforms := make([]string, 0, len(info.Platforms))
+		for platform, target := range info.Platforms {
+			if target != "" {
+				platforms = append(platforms, fmt.Sprintf("%s %s+", platform, target))
+			} else {
+				platforms = append(platforms, platform)
+			}
+		}
+		sort.Strings(platforms)
+		fmt.Fprintf(&content, "**Platforms:** %s\n\n", strings.Join(platforms, ", "))
+	}
+
+	if len(info.Dependencies) > 0 {
+		deps := make([]string, 0, len(info.Dependencies))
+		for dep := range info.Dependencies {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+
+		content.WriteString("## Dependencies\n\n")
+		for _, dep := range deps {
+			fmt.Fprintf(&content, "- `%s` %s\n", dep, info.Dependencies[dep])
+		}
+		content.WriteString("\n")
+	}
+
+	if len(info.Subspecs) > 0 {
+		content.WriteString("## Subspecs\n\n")
+		for _, subspec := range info.Subspecs {
+			fmt.Fprintf(&content, "- `%s`\n", subspec)
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("### Using CocoaPods (Podfile)\n\n")
+	content.WriteString("```ruby\n")
+	content.WriteString("target 'MyApp' do\n")
+	fmt.Fprintf(&content, "  %s\n", podfileLine(info.Name, info.Version))
+	content.WriteString("end\n")
+	content.WriteString("```\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("pod install\n")
+	content.WriteString("```\n\n")
+
+	rootName := strings.SplitN(info.Name, "/", 2)[0]
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [cocoapods.org](https://cocoapods.org/pods/%s)\n", rootName)
+	if info.Homepage != "" {
+		fmt.Fprintf(&content, "- [Homepage](%s)\n", info.Homepage)
+	}
+
+	return content.String()
+}
+
+func (f *CocoaPodsFetcher) savePodInfoAsMarkdown(filePath string, info *CocoaPodInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "name: \"%s\"\n", info.Name)
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	if info.Summary != "" {
+		fmt.Fprintf(&content, "summary: \"%s\"\n", escapeYAML(info.Summary))
+	}
+	if info.Homepage != "" {
+		fmt.Fprintf(&content, "homepage: \"%s\"\n", info.Homepage)
+	}
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "repository: \"%s\"\n", info.Repository)
+	}
+	if info.License != "" {
+		fmt.Fprintf(&content, "license: \"%s\"\n", escapeYAML(info.License))
+	}
+	if info.Authors != "" {
+		fmt.Fprintf(&content, "authors: \"%s\"\n", escapeYAML(info.Authors))
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *CocoaPodsFetcher) loadPodInfoFromMarkdown(filePath string) (*CocoaPodInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta CocoaPodInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	meta.Content = strings.TrimSpace(parts[2])
+	return &meta, nil
+}
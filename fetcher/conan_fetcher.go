@@ -0,0 +1,388 @@
+package fetcher
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/semver"
+)
+
+// conanCenterIndexURL serves the Conan Center recipes straight from the conan-center-index repository
+const conanCenterIndexURL = "https://raw.githubusercontent.com/conan-io/conan-center-index/master/recipes"
+
+type ConanPackageInfo struct {
+	Name            string   `yaml:"name"`
+	Version         string   `yaml:"version"`
+	Description     string   `yaml:"description"`
+	License         string   `yaml:"license"`
+	Homepage        string   `yaml:"homepage"`
+	CMakeFileName   string   `yaml:"cmakeFileName"`
+	CMakeTargetName string   `yaml:"cmakeTargetName"`
+	Versions        []string `yaml:"-"`
+	Topics          []string `yaml:"-"`
+	Options         []string `yaml:"-"`
+	Requires        []string `yaml:"-"`
+	Content         string   `yaml:"-"`
+}
+
+type ConanFetcher struct {
+	*BaseFetcher
+}
+
+func NewConanFetcher(cacheDir string) *ConanFetcher {
+	return &ConanFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchPackageInfo fetches information about a C/C++ package from the Conan Center recipe index
+func (f *ConanFetcher) FetchPackageInfo(packageName, version string) (*ConanPackageInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchPackageInfo", packageName, version), f.packagePath(packageName, version), func() (*ConanPackageInfo, error) {
+		return f.fetchPackageInfo(packageName, version)
+	})
+}
+
+// packagePath returns the cache path of the info on a package
+func (f *ConanFetcher) packagePath(packageName, version string) string {
+	return f.getCache().GetFilePath("conan", "recipes", fmt.Sprintf("%s.md", cache.EntryName(strings.ToLower(packageName), version)))
+}
+
+func (f *ConanFetcher) fetchPackageInfo(packageName, version string) (*ConanPackageInfo, error) {
+	packageName = strings.ToLower(packageName)
+
+	// Check cache first
+	cachedPath := f.packagePath(packageName, version)
+	pkgInfo, err := f.loadPackageInfoFromMarkdown(cachedPath)
+	if err == nil && pkgInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Conan package '%s' from cache\n", packageName)
+		return pkgInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Conan package '%s' from Conan Center...\n", packageName)
+
+	body, err := f.get(fmt.Sprintf("%s/%s/config.yml", conanCenterIndexURL, packageName))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("conan package %s not found", packageName)
+	}
+
+	var config struct {
+		Versions map[string]struct {
+			Folder string `yaml:"folder"`
+		} `yaml:"versions"`
+	}
+	if err := yaml.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse recipe config: %w", err)
+	}
+
+	versions := make([]string, 0, len(config.Versions))
+	for v := range config.Versions {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare(versions[i], versions[j]) > 0
+	})
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("conan package %s has no versions", packageName)
+	}
+
+	if version == "" {
+		version = versions[0]
+		for _, v := range versions {
+			if !semver.IsPrerelease(v) {
+				version = v
+				break
+			}
+		}
+	}
+
+	entry, ok := config.Versions[version]
+	if !ok {
+		return nil, fmt.Errorf("conan package %s version %s not found", packageName, version)
+	}
+
+	recipe, err := f.get(fmt.Sprintf("%s/%s/%s/conanfile.py", conanCenterIndexURL, packageName, entry.Folder))
+	if err != nil {
+		return nil, err
+	}
+	if recipe == nil {
+		return nil, fmt.Errorf("conan recipe for %s %s not found", packageName, version)
+	}
+
+	pkgInfo = parseConanfile(string(recipe))
+	pkgInfo.Name = packageName
+	pkgInfo.Version = version
+	pkgInfo.Versions = versions
+
+	// Build content
+	pkgInfo.Content = f.buildPackageContent(pkgInfo)
+
+	// Cache the result
+	if err := f.savePackageInfoAsMarkdown(cachedPath, pkgInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache package info: %v\n", err)
+	}
+
+	return pkgInfo, nil
+}
+
+var (
+	conanAttrRe     = regexp.MustCompile(`(?m)^\s{4}(description|license|homepage|topics)\s*=\s*([(\[](?:\s*(?:"[^"]*"|'[^']*')\s*,?)*\s*[)\]]|"[^"]*"|'[^']*')`)
+	conanOptionsRe  = regexp.MustCompile(`(?ms)^\s{4}options\s*=\s*\{(.*?)^\s{4}\}`)
+	conanOptionRe   = regexp.MustCompile(`["'](\w+)["']\s*:`)
+	conanRequiresRe = regexp.MustCompile(`self\.requires\(\s*["']([^"']+)["']`)
+	conanCMakeRe    = regexp.MustCompile(`self\.cpp_info\.set_property\(\s*["'](cmake_file_name|cmake_target_name)["']\s*,\s*["']([^"']+)["']`)
+	pyStringRe      = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+)
+
+// parseConanfile extracts recipe metadata from the class attributes of a conanfile.py
+func parseConanfile(recipe string) *ConanPackageInfo {
+	info := &ConanPackageInfo{}
+
+	for _, m := range conanAttrRe.FindAllStringSubmatch(recipe, -1) {
+		values := pyStrings(m[2])
+		switch m[1] {
+		case "description":
+			// Adjacent string literals are concatenated
+			info.Description = strings.Join(values, "")
+		case "license":
+			info.License = strings.Join(values, ", ")
+		case "homepage":
+			info.Homepage = strings.Join(values, "")
+		case "topics":
+			info.Topics = values
+		}
+	}
+
+	if m := conanOptionsRe.FindStringSubmatch(recipe); m != nil {
+		for _, opt := range conanOptionRe.FindAllStringSubmatch(m[1], -1) {
+			info.Options = append(info.Options, opt[1])
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range conanRequiresRe.FindAllStringSubmatch(recipe, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			info.Requires = append(info.Requires, m[1])
+		}
+	}
+
+	// The first declaration is the package-level one; components come later
+	for _, m := range conanCMakeRe.FindAllStringSubmatch(recipe, -1) {
+		if m[1] == "cmake_file_name" && info.CMakeFileName == "" {
+			info.CMakeFileName = m[2]
+		}
+		if m[1] == "cmake_target_name" && info.CMakeTargetName == "" {
+			info.CMakeTargetName = m[2]
+		}
+	}
+
+	return info
+}
+
+func pyStrings(literal string) []string {
+	var values []string
+	for _, m := range pyStringRe.FindAllStringSubmatch(literal, -1) {
+		values = append(values, m[1]+m[2])
+	}
+	return values
+}
+
+// get fetches a recipe file, returning nil without error when it does not exist
+func (f *ConanFetcher) get(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch conan recipe: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("conan-center-index returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return body, nil
+}
+
+func (f *ConanFetcher) buildPackageContent(info *ConanPackageInfo) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Name)
+
+	if info.Description != "" {
+		fmt.Fprintf(&content, "**Description:** %s\n\n", info.Description)
+	}
+
+	fmt.Fprintf(&content, "**Version:** %s\n\n", info.Version)
+
+	if info.License != "" {
+		fmt.Fprintf(&content, "**License:** %s\n\n", info.License)
+	}
+
+	if info.Homepage != "" {
+		fmt.Fprintf(&content, "**Homepage:** %s\n\n", info.Homepage)
+	}
+
+	if len(info.Topics) > 0 {
+		fmt.Fprintf(&content, "**Topics:** %s\n\n", strings.Join(info.Topics, ", "))
+	}
+
+	if len(info.Versions) > 0 {
+		shown := info.Versions
+		if len(shown) > 10 {
+			shown = shown[:10]
+		}
+		fmt.Fprintf(&content, "**Available Versions:** %s\n\n", strings.Join(shown, ", "))
+	}
+
+	if len(info.Options) > 0 {
+		content.WriteString("## Options\n\n")
+		for _, opt := range info.Options {
+			fmt.Fprintf(&content, "- `%s`\n", opt)
+		}
+		content.WriteString("\n")
+	}
+
+	if len(info.Requires) > 0 {
+		content.WriteString("## Dependencies\n\n")
+		for _, req := range info.Requires {
+			fmt.Fprintf(&content, "- `%s`\n", req)
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("### Using conanfile.txt\n\n")
+	content.WriteString("```ini\n")
+	content.WriteString("[requires]\n")
+	fmt.Fprintf(&content, "%s\n\n", conanReference(info.Name, info.Version))
+	content.WriteString("[generators]\n")
+	content.WriteString("CMakeDeps\n")
+	content.WriteString("CMakeToolchain\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using conanfile.py\n\n")
+	content.WriteString("```python\n")
+	content.WriteString("def requirements(self):\n")
+	fmt.Fprintf(&content, "    self.requires(\"%s\")\n", conanReference(info.Name, info.Version))
+	content.WriteString("```\n\n")
+
+	content.WriteString("```bash\n")
+	content.WriteString("conan install . --output-folder=build --build=missing\n")
+	content.WriteString("```\n\n")
+
+	fileName, target := conanCMakeNames(info)
+	content.WriteString("### CMake Integration (CMakeLists.txt)\n\n")
+	content.WriteString("```cmake\n")
+	fmt.Fprintf(&content, "find_package(%s REQUIRED)\n", fileName)
+	fmt.Fprintf(&content, "target_link_libraries(myapp PRIVATE %s)\n", target)
+	content.WriteString("```\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("cmake -B build -DCMAKE_TOOLCHAIN_FILE=build/conan_toolchain.cmake -DCMAKE_BUILD_TYPE=Release\n")
+	content.WriteString("cmake --build build\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [Conan Center](https://conan.io/center/recipes/%s)\n", info.Name)
+	fmt.Fprintf(&content, "- [Recipe](https://github.com/conan-io/conan-center-index/tree/master/recipes/%s)\n", info.Name)
+	if info.Homepage != "" {
+		fmt.Fprintf(&content, "- [Homepage](%s)\n", info.Homepage)
+	}
+
+	return content.String()
+}
+
+// conanCMakeNames returns the find_package name and link target of a recipe,
+// falling back to the CMakeDeps defaults (<name> and <name>::<name>)
+func conanCMakeNames(info *ConanPackageInfo) (string, string) {
+	fileName := info.CMakeFileName
+	if fileName == "" {
+		fileName = info.Name
+	}
+	target := info.CMakeTargetName
+	if target == "" {
+		target = fmt.Sprintf("%s::%s", info.Name, info.Name)
+	}
+	return fileName, target
+}
+
+func (f *ConanFetcher) savePackageInfoAsMarkdown(filePath string, info *ConanPackageInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "name: \"%s\"\n", info.Name)
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	if info.Description != "" {
+		fmt.Fprintf(&content, "description: \"%s\"\n", escapeYAML(info.Description))
+	}
+	if info.License != "" {
+		fmt.Fprintf(&content, "license: \"%s\"\n", escapeYAML(info.License))
+	}
+	if info.Homepage != "" {
+		fmt.Fprintf(&content, "homepage: \"%s\"\n", info.Homepage)
+	}
+	if info.CMakeFileName != "" {
+		fmt.Fprintf(&content, "cmakeFileName: \"%s\"\n", info.CMakeFileName)
+	}
+	if info.CMakeTargetName != "" {
+		fmt.Fprintf(&content, "cmakeTargetName: \"%s\"\n", info.CMakeTargetName)
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *ConanFetcher) loadPackageInfoFromMarkdown(filePath string) (*ConanPackageInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta ConanPackageInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	meta.Content = strings.TrimSpace(parts[2])
+	return &meta, nil
+}
@@ -0,0 +1,417 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/crawler"
+	"github.com/incu6us/open-context/semver"
+)
+
+// Custom source types
+const (
+	CustomGitHubRelease = "github-release"
+	CustomRegistry      = "registry"
+	CustomSitemap       = "sitemap"
+)
+
+const (
+	// customPrefix names the documentation a sitemap source is indexed as
+	customPrefix = "custom-"
+
+	// maxRegistryBytes bounds one registry response
+	maxRegistryBytes = 16 << 20
+
+	// maxRegistryVersions caps the versions listed for a registry package
+	maxRegistryVersions = 20
+
+	// maxRegistryReadmeChars bounds the documentation of a registry package
+	maxRegistryReadmeChars = 60000
+)
+
+var customSourceNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// registryFields are the fields read from a registry response, each by the
+// selector of the same name or else the top-level key of that name
+var registryFields = []string{"name", "version", "description", "homepage", "repository", "license", "published", "versions", "readme"}
+
+var errCustomSourceNotFound = errors.New("not found")
+
+// CustomSourceInfo is what a custom source returned
+type CustomSourceInfo struct {
+	Source string `json:"source"`
+	// Documentation is the name a sitemap source is searchable under
+	Documentation string `json:"documentation,omitempty"`
+	// Updated is false when a sitemap crawl was still fresh
+	Updated bool   `json:"-"`
+	Content string `json:"content"`
+}
+
+// CustomSourceFetcher fetches a documentation source declared under
+// custom_sources in config.yaml
+type CustomSourceFetcher struct {
+	*BaseFetcher
+	source   config.CustomSource
+	releases *GitHubReleaseFetcher
+	site     *SiteFetcher
+}
+
+// NewCustomSourceFetcher validates a custom source and returns its fetcher.
+// Registry and sitemap requests to the host of base_url carry the source's
+// headers.
+func NewCustomSourceFetcher(cacheDir string, source config.CustomSource) (*CustomSourceFetcher, error) {
+	if !customSourceNamePattern.MatchString(source.Name) {
+		return nil, fmt.Errorf("invalid custom source name %q: use lowercase letters, digits, and underscores", source.Name)
+	}
+	if source.BaseURL == "" {
+		return nil, fmt.Errorf("custom source %s has no base_url", source.Name)
+	}
+
+	f := &CustomSourceFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+		source:      source,
+	}
+	// Headers often hold tokens, so they only go to base_url's origin, not
+	// to hosts it redirects or links to
+	if base, err := url.Parse(source.BaseURL); err == nil && base.Host != "" && len(source.Headers) > 0 {
+		headers := make(http.Header)
+		for key, value := range source.Headers {
+			headers.Set(key, os.ExpandEnv(value))
+		}
+		next := f.client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		f.client = &http.Client{
+			Timeout:   f.client.Timeout,
+			Transport: &headerTransport{next: next, headers: headers, scheme: base.Scheme, host: base.Host},
+		}
+	}
+
+	switch source.Type {
+	case CustomGitHubRelease:
+		if repo := normalizeGitHubRepository(source.BaseURL); strings.Count(repo, "/") != 1 {
+			return nil, fmt.Errorf("custom source %s: base_url must be a GitHub repository, got %q", source.Name, source.BaseURL)
+		}
+		f.releases = NewGitHubReleaseFetcher(cacheDir)
+	case CustomRegistry:
+		if u, err := url.Parse(source.BaseURL); err != nil || u.Host == "" {
+			return nil, fmt.Errorf("custom source %s: invalid base_url %q", source.Name, source.BaseURL)
+		}
+		for key := range source.Selectors {
+			if !slices.Contains(registryFields, key) {
+				return nil, fmt.Errorf("custom source %s: unknown selector %q (must be one of %s)", source.Name, key, strings.Join(registryFields, ", "))
+			}
+		}
+	case CustomSitemap:
+		if _, err := crawler.New(nil, source.BaseURL, source.MaxPages); err != nil {
+			return nil, fmt.Errorf("custom source %s: %w", source.Name, err)
+		}
+		if _, err := crawlSelectors(source.Selectors); err != nil {
+			return nil, fmt.Errorf("custom source %s: %w", source.Name, err)
+		}
+		f.site = &SiteFetcher{BaseFetcher: f.BaseFetcher}
+	default:
+		return nil, fmt.Errorf("custom source %s: unknown type %q (must be %s, %s, or %s)", source.Name, source.Type, CustomGitHubRelease, CustomRegistry, CustomSitemap)
+	}
+	return f, nil
+}
+
+// Source returns the source's configuration
+func (f *CustomSourceFetcher) Source() config.CustomSource {
+	return f.source
+}
+
+// NeedsName reports whether the source serves several packages, whose
+// name each call must give
+func (f *CustomSourceFetcher) NeedsName() bool {
+	return f.source.Type == CustomRegistry && strings.Contains(f.source.BaseURL, "{name}")
+}
+
+// Documentation returns the documentation name a sitemap source is
+// indexed as, e.g. "custom-acme_docs"
+func (f *CustomSourceFetcher) Documentation() string {
+	return customPrefix + f.source.Name
+}
+
+// Fetch reads the source: the release tagged version (the latest when
+// empty) of a github-release source, the package name at version of a
+// registry source, or a crawl of up to maxPages pages of a sitemap source,
+// indexed as a documentation set
+func (f *CustomSourceFetcher) Fetch(name, version string, maxPages int) (*CustomSourceInfo, error) {
+	switch f.source.Type {
+	case CustomGitHubRelease:
+		release, err := f.releases.FetchRelease(normalizeGitHubRepository(f.source.BaseURL), version)
+		if err != nil {
+			return nil, err
+		}
+		return &CustomSourceInfo{Source: f.source.Name, Content: release.Content}, nil
+
+	case CustomSitemap:
+		if maxPages <= 0 {
+			maxPages = f.source.MaxPages
+		}
+		if maxPages <= 0 {
+			maxPages = crawler.DefaultMaxPages
+		}
+		maxPages = min(maxPages, maxSitePages)
+		site, err := shareFetch(f.flights, flightKey("FetchCustomSite", f.source.Name, fmt.Sprint(maxPages)), "", func() (*SiteInfo, error) {
+			return f.site.crawl(siteCrawl{
+				url:         f.source.BaseURL,
+				maxPages:    maxPages,
+				docName:     f.Documentation(),
+				displayName: f.displayName(),
+				selectors:   f.source.Selectors,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &CustomSourceInfo{Source: f.source.Name, Documentation: site.Documentation, Updated: site.Updated, Content: site.Content}, nil
+	}
+
+	if f.NeedsName() && name == "" {
+		return nil, fmt.Errorf("name parameter is required")
+	}
+	return shareFetch(f.flights, flightKey("FetchRegistry", name, version), f.registryPath(name, version), func() (*CustomSourceInfo, error) {
+		return f.fetchRegistry(name, version)
+	})
+}
+
+func (f *CustomSourceFetcher) displayName() string {
+	if f.source.Description != "" {
+		return f.source.Description
+	}
+	return f.source.Name
+}
+
+// registryPath returns the cache path of a registry response
+func (f *CustomSourceFetcher) registryPath(name, version string) string {
+	return f.getCache().GetFilePath("custom", f.source.Name, fmt.Sprintf("%s.json", cache.EntryName(name, version)))
+}
+
+// fetchRegistry reads a package from the source's JSON API. {version}
+// becomes "latest" when no version is given.
+func (f *CustomSourceFetcher) fetchRegistry(name, version string) (*CustomSourceInfo, error) {
+	cachedPath := f.registryPath(name, version)
+	var info CustomSourceInfo
+	if ok, err := f.getCache().Load(cachedPath, &info); err == nil && ok {
+		fmt.Fprintf(os.Stderr, "Loaded '%s' from %s cache\n", name, f.source.Name)
+		return &info, nil
+	}
+
+	urlVersion := version
+	if urlVersion == "" {
+		urlVersion = "latest"
+	}
+	apiURL := strings.NewReplacer("{name}", url.PathEscape(name), "{version}", url.PathEscape(urlVersion)).Replace(f.source.BaseURL)
+
+	fmt.Fprintf(os.Stderr, "Fetching '%s' from %s...\n", name, apiURL)
+	var payload interface{}
+	if err := f.getJSON(apiURL, &payload); err != nil {
+		if errors.Is(err, errCustomSourceNotFound) {
+			if version != "" {
+				return nil, fmt.Errorf("%s %s version %s not found", f.source.Name, name, version)
+			}
+			return nil, fmt.Errorf("%s %s not found", f.source.Name, name)
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %w", f.source.Name, err)
+	}
+
+	fields := make(map[string]interface{}, len(registryFields))
+	for _, field := range registryFields {
+		path := field
+		if selector, ok := f.source.Selectors[field]; ok {
+			path = selector
+		}
+		if value, ok := jsonPath(payload, path); ok {
+			fields[field] = value
+		}
+	}
+
+	info = CustomSourceInfo{Source: f.source.Name, Content: f.buildRegistryContent(name, version, fields)}
+	if err := f.getCache().Save(cachedPath, &info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache %s: %v\n", f.source.Name, err)
+	}
+	return &info, nil
+}
+
+func (f *CustomSourceFetcher) buildRegistryContent(name, version string, fields map[string]interface{}) string {
+	if v := scalarString(fields["name"]); v != "" {
+		name = v
+	}
+	if v := scalarString(fields["version"]); v != "" {
+		version = v
+	}
+
+	var b strings.Builder
+	title := f.displayName()
+	if name != "" {
+		title += ": " + name
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if version != "" {
+		fmt.Fprintf(&b, "**Version:** %s\n", version)
+	}
+	for _, field := range []struct{ key, label string }{
+		{"description", "Description"},
+		{"homepage", "Homepage"},
+		{"repository", "Repository"},
+		{"license", "License"},
+		{"published", "Published"},
+	} {
+		if v := scalarString(fields[field.key]); v != "" {
+			fmt.Fprintf(&b, "**%s:** %s\n", field.label, v)
+		}
+	}
+
+	if versions := versionList(fields["versions"]); len(versions) > 0 {
+		b.WriteString("\n## Versions\n\n")
+		for i, v := range versions {
+			if i == maxRegistryVersions {
+				fmt.Fprintf(&b, "- ... and %d more\n", len(versions)-i)
+				break
+			}
+			fmt.Fprintf(&b, "- %s\n", v)
+		}
+	}
+
+	if readme := strings.TrimSpace(scalarString(fields["readme"])); readme != "" {
+		b.WriteString("\n## Documentation\n\n")
+		b.WriteString(truncateMarkdown(readme, maxRegistryReadmeChars, "Documentation truncated"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// jsonPath follows a dotted path such as "info.version" or
+// "releases.0.tag" through decoded JSON
+func jsonPath(v interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+// scalarString formats a JSON string, number, or boolean, and joins a list
+// of them
+func scalarString(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case []interface{}:
+		var parts []string
+		for _, item := range value {
+			if s := scalarString(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+// versionList reads versions given as a list, or as an object keyed by
+// version as npm-style registries do
+func versionList(v interface{}) []string {
+	var versions []string
+	switch value := v.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if s := scalarString(item); s != "" {
+				versions = append(versions, s)
+			}
+		}
+	case map[string]interface{}:
+		for version := range value {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return semver.Compare(versions[i], versions[j]) > 0
+		})
+	}
+	return versions
+}
+
+func (f *CustomSourceFetcher) getJSON(apiURL string, v interface{}) error {
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errCustomSourceNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRegistryBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse registry response: %w", err)
+	}
+	return nil
+}
+
+// headerTransport adds a custom source's headers to its requests to the
+// origin of its base_url
+type headerTransport struct {
+	next    http.RoundTripper
+	headers http.Header
+	// scheme and host are the origin of base_url
+	scheme, host string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.EqualFold(req.URL.Scheme, t.scheme) || !strings.EqualFold(req.URL.Host, t.host) {
+		return t.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	for key, values := range t.headers {
+		req.Header[key] = values
+	}
+	return t.next.RoundTrip(req)
+}
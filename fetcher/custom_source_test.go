@@ -0,0 +1,50 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestHeaderTransportOrigin checks that a custom source's headers reach
+// its base_url's host but not a host it redirects to
+func TestHeaderTransportOrigin(t *testing.T) {
+	var leaked string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer other.Close()
+
+	var sent string
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = r.Header.Get("Authorization")
+		http.Redirect(w, r, other.URL+"/elsewhere", http.StatusFound)
+	}))
+	defer source.Close()
+
+	base, err := url.Parse(source.URL + "/api/{name}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: &headerTransport{
+		next:    http.DefaultTransport,
+		headers: http.Header{"Authorization": {"Bearer secret"}},
+		scheme:  base.Scheme,
+		host:    base.Host,
+	}}
+
+	resp, err := client.Get(source.URL + "/api/widget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if sent != "Bearer secret" {
+		t.Errorf("base_url host got Authorization %q, want the configured header", sent)
+	}
+	if leaked != "" {
+		t.Errorf("redirect target got Authorization %q, want none", leaked)
+	}
+}
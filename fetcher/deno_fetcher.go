@@ -0,0 +1,223 @@
+package fetcher
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const denoReleasesAPI = "https://api.github.com/repos/denoland/deno/releases"
+
+type DenoVersionInfo = ReleaseVersionInfo
+
+type DenoFetcher struct {
+	*BaseFetcher
+}
+
+func NewDenoFetcher(cacheDir string) *DenoFetcher {
+	return &DenoFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchDenoVersion fetches the release notes and install commands of a Deno
+// version ("2.1.4" or "v2.1.4"), or of the latest release when version is
+// empty or "latest"
+func (f *DenoFetcher) FetchDenoVersion(version string) (*DenoVersionInfo, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "latest" {
+		version = ""
+	}
+	return shareFetch(f.flights, flightKey("FetchDenoVersion", version), f.versionPath(version), func() (*DenoVersionInfo, error) {
+		return f.fetchDenoVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Deno version
+func (f *DenoFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("deno", "versions", fmt.Sprintf("%s.md", cache.EntryName(cmp.Or(version, "latest"))))
+}
+
+func (f *DenoFetcher) fetchDenoVersion(version string) (*DenoVersionInfo, error) {
+	// Check cache first
+	cachedPath := f.versionPath(version)
+	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
+	if err == nil && versionInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Deno version '%s' from cache\n", versionInfo.Version)
+		return versionInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Deno version '%s' from GitHub...\n", cmp.Or(version, "latest"))
+
+	apiURL := denoReleasesAPI + "/latest"
+	if version != "" {
+		apiURL = fmt.Sprintf("%s/tags/v%s", denoReleasesAPI, version)
+	}
+	req, err := newRequest(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Deno release: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("deno version %s not found", version)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	versionInfo, err = renderReleaseVersion("", body, f.buildVersionContent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
+	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+	}
+
+	return versionInfo, nil
+}
+
+func (f *DenoFetcher) buildVersionContent(info *DenoVersionInfo, releaseNotes string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Deno %s\n\n", info.Version)
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+	if info.Prerelease {
+		content.WriteString("**Pre-release:** yes\n\n")
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "**Release Notes:** [v%s](%s)\n\n", info.Version, info.ReleaseURL)
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("### Using the install script (macOS, Linux)\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "curl -fsSL https://deno.land/install.sh | sh -s v%s\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using the install script (Windows)\n\n")
+	content.WriteString("```powershell\n")
+	fmt.Fprintf(&content, "$v=\"%s\"; irm https://deno.land/install.ps1 | iex\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Upgrading an existing install\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "deno upgrade --version %s\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using Cargo\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "cargo install deno --version %s --locked\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using Docker\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "docker run --rm denoland/deno:%s --version\n", info.Version)
+	content.WriteString("```\n\n")
+
+	notes := strings.TrimSpace(strings.ReplaceAll(releaseNotes, "\r\n", "\n"))
+	if notes != "" {
+		content.WriteString("## Release Notes\n\n")
+		notes = markdown.DemoteHeadings(notes, 1)
+		content.WriteString(strings.TrimSpace(truncateMarkdown(notes, maxReleaseNotesChars, "The release notes are truncated; see GitHub for the rest.")))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("For detailed documentation, visit:\n\n")
+	content.WriteString("- [Deno Documentation](https://docs.deno.com)\n")
+	content.WriteString("- [Deno API Reference](https://docs.deno.com/api/deno/)\n")
+	content.WriteString("- [Deno GitHub Repository](https://github.com/denoland/deno)\n")
+	content.WriteString("- [Deno Release Notes](https://github.com/denoland/deno/releases)\n")
+
+	return content.String()
+}
+
+func (f *DenoFetcher) saveVersionInfoAsMarkdown(filePath string, info *DenoVersionInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "releaseURL: \"%s\"\n", info.ReleaseURL)
+	}
+	if info.Prerelease {
+		content.WriteString("prerelease: true\n")
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *DenoFetcher) loadVersionInfoFromMarkdown(filePath string) (*DenoVersionInfo, error) {
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta struct {
+		DenoVersionInfo `yaml:",inline"`
+		Template        int `yaml:"template"`
+	}
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if info, err := renderReleaseVersion("", payload, f.buildVersionContent); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered Deno version '%s' with current templates\n", info.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
+	info := meta.DenoVersionInfo
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -0,0 +1,381 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	devDocsURL          = "https://devdocs.io"
+	devDocsDocumentsURL = "https://documents.devdocs.io"
+
+	// devDocsPrefix names the documentation a docset is indexed as
+	devDocsPrefix = "devdocs-"
+
+	// maxDevDocsDBSize bounds the page database downloaded for a docset;
+	// the largest docsets are a few hundred megabytes
+	maxDevDocsDBSize = 128 << 20
+
+	// maxDevDocsTopicChars bounds the content of one page
+	maxDevDocsTopicChars = 60000
+
+	// maxDevDocsKeywords caps the entry names kept as keywords of a page
+	maxDevDocsKeywords = 50
+)
+
+// DevDocsDocset is a docset in the DevDocs catalog
+type DevDocsDocset struct {
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+	Release string `json:"release"`
+	Mtime   int64  `json:"mtime"`
+	DBSize  int64  `json:"db_size"`
+	Links   struct {
+		Home string `json:"home"`
+		Code string `json:"code"`
+	} `json:"links"`
+}
+
+// DisplayName is the docset name with its version, e.g. "Python 3.12"
+func (d *DevDocsDocset) DisplayName() string {
+	if d.Version != "" {
+		return d.Name + " " + d.Version
+	}
+	return d.Name
+}
+
+// DevDocsInfo describes a docset indexed into the cache
+type DevDocsInfo struct {
+	Slug    string
+	Name    string
+	Release string
+	// Documentation is the name the docset is searchable under
+	Documentation string
+	Topics        int
+	// Updated is false when the cached copy was already current
+	Updated bool
+	Content string
+}
+
+// devDocsIndex lists the entries of a docset. Several entries may point
+// into one page, at different fragments.
+type devDocsIndex struct {
+	Entries []struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Type string `json:"type"`
+	} `json:"entries"`
+}
+
+// devDocsMetadata is the metadata.json of an indexed docset. The provider
+// reads the documentation fields; DevDocs records what was downloaded.
+type devDocsMetadata struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+	DevDocs     struct {
+		Slug    string         `json:"slug"`
+		Release string         `json:"release"`
+		Mtime   int64          `json:"mtime"`
+		Topics  int            `json:"topics"`
+		Types   map[string]int `json:"types"`
+	} `json:"devdocs"`
+}
+
+type DevDocsFetcher struct {
+	*BaseFetcher
+}
+
+func NewDevDocsFetcher(cacheDir string) *DevDocsFetcher {
+	return &DevDocsFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// DevDocsDocumentation returns the documentation name a docset is indexed
+// as, e.g. "devdocs-python_3.12" for "python~3.12"
+func DevDocsDocumentation(slug string) string {
+	return devDocsPrefix + cache.SanitizeSegment(slug)
+}
+
+// FetchDocset downloads a DevDocs docset ("python~3.12", "react", "rust")
+// and indexes each of its pages as a topic in the cache directory, where
+// the documentation provider loads it. A slug without a version picks the
+// newest version DevDocs has. A docset already indexed at the current
+// DevDocs release is not downloaded again.
+func (f *DevDocsFetcher) FetchDocset(slug string) (*DevDocsInfo, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	return shareFetch(f.flights, flightKey("FetchDocset", slug), "", func() (*DevDocsInfo, error) {
+		return f.fetchDocset(slug)
+	})
+}
+
+func (f *DevDocsFetcher) fetchDocset(slug string) (*DevDocsInfo, error) {
+	catalog, err := f.catalog()
+	if err != nil {
+		return nil, err
+	}
+	docset, err := findDocset(catalog, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	docName := DevDocsDocumentation(docset.Slug)
+	docDir := filepath.Join(f.getCache().GetCacheDir(), docName)
+	metadataPath := filepath.Join(docDir, "metadata.json")
+
+	if existing, err := readDevDocsMetadata(metadataPath); err == nil && existing.DevDocs.Mtime == docset.Mtime {
+		fmt.Fprintf(os.Stderr, "DevDocs docset '%s' is up to date\n", docset.Slug)
+		return devDocsInfo(docset, existing, false), nil
+	}
+
+	if docset.DBSize > maxDevDocsDBSize {
+		return nil, fmt.Errorf("DevDocs docset %s is too large to index (%d MB)", docset.Slug, docset.DBSize>>20)
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching DevDocs docset '%s'...\n", docset.Slug)
+
+	var index devDocsIndex
+	if err := f.getJSON(fmt.Sprintf("%s/%s/index.json?%d", devDocsDocumentsURL, docset.Slug, docset.Mtime), &index); err != nil {
+		return nil, fmt.Errorf("failed to fetch docset index: %w", err)
+	}
+	var db map[string]string
+	if err := f.getJSON(fmt.Sprintf("%s/%s/db.json?%d", devDocsDocumentsURL, docset.Slug, docset.Mtime), &db); err != nil {
+		return nil, fmt.Errorf("failed to fetch docset pages: %w", err)
+	}
+
+	topics := buildDevDocsTopics(docset, &index, db)
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("DevDocs docset %s has no pages", docset.Slug)
+	}
+
+	kinds, err := writeTopics(docDir, topics)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &devDocsMetadata{
+		Name:        docName,
+		DisplayName: docset.DisplayName(),
+		Description: fmt.Sprintf("%s documentation from DevDocs", docset.DisplayName()),
+	}
+	metadata.DevDocs.Slug = docset.Slug
+	metadata.DevDocs.Release = docset.Release
+	metadata.DevDocs.Mtime = docset.Mtime
+	metadata.DevDocs.Topics = countTopics(kinds)
+	delete(kinds, "")
+	metadata.DevDocs.Types = kinds
+
+	// Metadata goes last, so an interrupted download is fetched again
+	if err := writeJSON(metadataPath, metadata); err != nil {
+		return nil, fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	return devDocsInfo(docset, metadata, true), nil
+}
+
+// catalog returns the docsets DevDocs offers, cached for the cache TTL
+func (f *DevDocsFetcher) catalog() ([]DevDocsDocset, error) {
+	cachedPath := f.getCache().GetFilePath("devdocs", "docs.json")
+	var docsets []DevDocsDocset
+	if ok, err := f.getCache().Load(cachedPath, &docsets); err == nil && ok {
+		return docsets, nil
+	}
+
+	if err := f.getJSON(devDocsURL+"/docs.json", &docsets); err != nil {
+		return nil, fmt.Errorf("failed to fetch DevDocs catalog: %w", err)
+	}
+	if err := f.getCache().Save(cachedPath, docsets); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache DevDocs catalog: %v\n", err)
+	}
+	return docsets, nil
+}
+
+// findDocset looks up a slug in the catalog. A slug without a version
+// ("python") matches the first versioned docset, which DevDocs lists
+// newest first.
+func findDocset(catalog []DevDocsDocset, slug string) (*DevDocsDocset, error) {
+	for i := range catalog {
+		if catalog[i].Slug == slug {
+			return &catalog[i], nil
+		}
+	}
+	if !strings.Contains(slug, "~") {
+		for i := range catalog {
+			if strings.HasPrefix(catalog[i].Slug, slug+"~") {
+				return &catalog[i], nil
+			}
+		}
+	}
+
+	var similar []string
+	for _, docset := range catalog {
+		if len(similar) < 10 && slug != "" && (strings.Contains(docset.Slug, slug) || strings.Contains(strings.ToLower(docset.Name), slug)) {
+			similar = append(similar, docset.Slug)
+		}
+	}
+	if len(similar) > 0 {
+		return nil, fmt.Errorf("unknown DevDocs docset %q; similar docsets: %s", slug, strings.Join(similar, ", "))
+	}
+	return nil, fmt.Errorf("unknown DevDocs docset %q; see %s for the available docsets", slug, devDocsURL)
+}
+
+// buildDevDocsTopics converts each page of a docset into a topic, keyed by
+// page path. Pages are titled after the index entry pointing at them, and
+// every entry pointing into a page becomes one of its keywords, so
+// "Array.prototype.map" finds the Array page.
+func buildDevDocsTopics(docset *DevDocsDocset, index *devDocsIndex, db map[string]string) map[string]*indexedTopic {
+	type page struct {
+		title, kind string
+		names       []string
+		// root is set once an entry for the page itself, not a fragment of
+		// it, was seen
+		root bool
+	}
+	pages := make(map[string]*page)
+	for _, entry := range index.Entries {
+		path, fragment, _ := strings.Cut(entry.Path, "#")
+		p := pages[path]
+		if p == nil {
+			p = &page{}
+			pages[path] = p
+		}
+		if (fragment == "" && !p.root) || p.title == "" {
+			p.title, p.kind = entry.Name, entry.Type
+			p.root = fragment == ""
+		}
+		p.names = append(p.names, entry.Name)
+	}
+
+	base := strings.SplitN(docset.Slug, "~", 2)[0]
+	topics := make(map[string]*indexedTopic, len(db))
+	for path, body := range db {
+		p := pages[path]
+		if p == nil {
+			p = &page{title: path}
+		}
+
+		pageURL := fmt.Sprintf("%s/%s/%s", devDocsURL, docset.Slug, path)
+		content := markdown.FromHTML(body, pageURL)
+		if !strings.HasPrefix(content, "# ") {
+			content = "# " + p.title + "\n\n" + content
+		}
+		content = truncateMarkdown(content, maxDevDocsTopicChars, "Page truncated; see "+pageURL)
+
+		description := docset.DisplayName()
+		if p.kind != "" {
+			description += ": " + p.kind
+		}
+
+		names := uniqueStrings(p.names)
+		sort.Strings(names)
+		if len(names) > maxDevDocsKeywords {
+			names = names[:maxDevDocsKeywords]
+		}
+		keywords := append([]string{base, docset.Name, "devdocs"}, names...)
+		if p.kind != "" {
+			keywords = append(keywords, p.kind)
+		}
+
+		topics[path] = &indexedTopic{
+			ID:          docset.Slug + "/" + path,
+			Title:       p.title,
+			Description: description,
+			Content:     content,
+			Keywords:    uniqueStrings(keywords),
+			kind:        p.kind,
+		}
+	}
+	return topics
+}
+
+// devDocsInfo summarizes an indexed docset
+func devDocsInfo(docset *DevDocsDocset, metadata *devDocsMetadata, updated bool) *DevDocsInfo {
+	info := &DevDocsInfo{
+		Slug:          docset.Slug,
+		Name:          docset.DisplayName(),
+		Release:       docset.Release,
+		Documentation: metadata.Name,
+		Topics:        metadata.DevDocs.Topics,
+		Updated:       updated,
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# DevDocs: %s\n\n", info.Name)
+	fmt.Fprintf(&b, "**Docset:** %s\n", docset.Slug)
+	if docset.Release != "" {
+		fmt.Fprintf(&b, "**Release:** %s\n", docset.Release)
+	}
+	fmt.Fprintf(&b, "**Documentation:** %s\n", info.Documentation)
+	fmt.Fprintf(&b, "**Topics:** %d\n\n", info.Topics)
+
+	writeSections(&b, metadata.DevDocs.Types)
+
+	b.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&b, "- [DevDocs](%s/%s/)\n", devDocsURL, docset.Slug)
+	if docset.Links.Home != "" {
+		fmt.Fprintf(&b, "- [Home](%s)\n", docset.Links.Home)
+	}
+	if docset.Links.Code != "" {
+		fmt.Fprintf(&b, "- [Source](%s)\n", docset.Links.Code)
+	}
+
+	info.Content = b.String()
+	return info
+}
+
+func readDevDocsMetadata(path string) (*devDocsMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var metadata devDocsMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+func (f *DevDocsFetcher) getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("DevDocs returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDevDocsDBSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(body) > maxDevDocsDBSize {
+		return fmt.Errorf("DevDocs response exceeds %d MB", maxDevDocsDBSize>>20)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse DevDocs data: %w", err)
+	}
+	return nil
+}
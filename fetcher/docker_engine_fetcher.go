@@ -0,0 +1,299 @@
+package fetcher
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	mobyReleasesAPI      = "https://api.github.com/repos/moby/moby/releases"
+	dockerCLIReleasesAPI = "https://api.github.com/repos/docker/cli/releases"
+	dockerReleaseNotes   = "https://docs.docker.com/engine/release-notes"
+	dockerAPIReference   = "https://docs.docker.com/reference/api/engine/version"
+)
+
+// dockerAPIVersions maps each Docker Engine release line to the newest API
+// version it serves, from the Engine release notes and API changelog
+var dockerAPIVersions = []struct {
+	Engine string
+	API    string
+}{
+	{"17.03", "1.26"}, {"17.06", "1.30"}, {"17.09", "1.32"}, {"17.12", "1.35"},
+	{"18.03", "1.37"}, {"18.06", "1.38"}, {"18.09", "1.39"}, {"19.03", "1.40"},
+	{"20.10", "1.41"}, {"23.0", "1.42"}, {"24.0", "1.43"}, {"25.0", "1.44"},
+	{"26.0", "1.45"}, {"26.1", "1.45"}, {"27.0", "1.46"}, {"27.1", "1.46"},
+	{"27.2", "1.47"}, {"27.3", "1.47"}, {"27.4", "1.47"}, {"27.5", "1.47"},
+	{"28.0", "1.48"}, {"28.1", "1.49"}, {"28.2", "1.50"}, {"28.3", "1.51"},
+	{"28.4", "1.51"}, {"28.5", "1.51"}, {"29.0", "1.52"},
+}
+
+// DockerEngineInfo is a release of Docker Engine (moby/moby) and the Docker
+// CLI (docker/cli), which share version numbers
+type DockerEngineInfo struct {
+	Version       string `yaml:"version"`
+	ReleaseDate   string `yaml:"releaseDate"`
+	ReleaseURL    string `yaml:"releaseURL"`
+	CLIReleaseURL string `yaml:"cliReleaseURL"`
+	APIVersion    string `yaml:"apiVersion"`
+	Content       string `yaml:"-"`
+}
+
+type DockerEngineFetcher struct {
+	*BaseFetcher
+}
+
+func NewDockerEngineFetcher(cacheDir string) *DockerEngineFetcher {
+	return &DockerEngineFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchDockerEngineVersion fetches the release notes of a Docker Engine and
+// CLI version ("27.3.1", "v27.3.1", or "docker-v29.0.0"), or of the latest
+// release when version is empty or "latest", with the Engine API version it
+// serves and install commands
+func (f *DockerEngineFetcher) FetchDockerEngineVersion(version string) (*DockerEngineInfo, error) {
+	version = dockerEngineVersion(strings.TrimSpace(version))
+	if version == "latest" {
+		version = ""
+	}
+	return shareFetch(f.flights, flightKey("FetchDockerEngineVersion", version), f.versionPath(version), func() (*DockerEngineInfo, error) {
+		return f.fetchDockerEngineVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Docker Engine version
+func (f *DockerEngineFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("docker", "versions", fmt.Sprintf("%s.md", cache.EntryName(cmp.Or(version, "latest"))))
+}
+
+func (f *DockerEngineFetcher) fetchDockerEngineVersion(version string) (*DockerEngineInfo, error) {
+	// Check cache first
+	cachedPath := f.versionPath(version)
+	info, err := f.loadDockerEngineInfoFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Docker Engine version '%s' from cache\n", info.Version)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Docker Engine version '%s' from GitHub...\n", cmp.Or(version, "latest"))
+
+	// moby/moby tags releases v27.3.1, and docker-v29.0.0 since it split
+	// into Go modules
+	var release githubRelease
+	found := false
+	if version == "" {
+		found, err = f.getJSON(mobyReleasesAPI+"/latest", &release)
+	} else {
+		for _, tag := range []string{"v" + version, "docker-v" + version} {
+			if found, err = f.getJSON(fmt.Sprintf("%s/tags/%s", mobyReleasesAPI, url.PathEscape(tag)), &release); err != nil || found {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Docker Engine release: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("docker engine version %s not found", version)
+	}
+	version = dockerEngineVersion(release.TagName)
+
+	// docker/cli publishes few GitHub releases; the Engine's notes cover
+	// the client too
+	var cliRelease githubRelease
+	if _, err := f.getJSON(fmt.Sprintf("%s/tags/v%s", dockerCLIReleasesAPI, url.PathEscape(version)), &cliRelease); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch docker/cli release: %v\n", err)
+	}
+
+	info = &DockerEngineInfo{
+		Version:       version,
+		ReleaseDate:   releaseDate(release.PublishedAt),
+		ReleaseURL:    release.HTMLURL,
+		CLIReleaseURL: cliRelease.HTMLURL,
+		APIVersion:    dockerAPIVersion(version),
+	}
+	info.Content = f.buildDockerEngineContent(info, release.Body, cliRelease.Body)
+
+	if err := f.saveDockerEngineInfoAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// dockerEngineVersion strips the "docker-" and "v" prefixes of a release tag
+func dockerEngineVersion(tag string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(tag, "docker-"), "v")
+}
+
+// dockerReleaseLine returns the major.minor release line of an Engine
+// version ("27.3" for "27.3.1")
+func dockerReleaseLine(version string) string {
+	major, rest, _ := strings.Cut(version, ".")
+	minor, _, _ := strings.Cut(rest, ".")
+	minor, _, _ = strings.Cut(minor, "-")
+	return major + "." + minor
+}
+
+// dockerAPIVersion returns the API version the release line of an Engine
+// version serves, or "" for lines missing from dockerAPIVersions
+func dockerAPIVersion(version string) string {
+	line := dockerReleaseLine(version)
+	for _, v := range dockerAPIVersions {
+		if v.Engine == line {
+			return v.API
+		}
+	}
+	return ""
+}
+
+func (f *DockerEngineFetcher) buildDockerEngineContent(info *DockerEngineInfo, releaseNotes, cliNotes string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Docker Engine %s\n\n", info.Version)
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "**Engine Release:** [moby/moby %s](%s)\n\n", info.Version, info.ReleaseURL)
+	}
+	cliURL := cmp.Or(info.CLIReleaseURL, fmt.Sprintf("https://github.com/docker/cli/tree/v%s", info.Version))
+	fmt.Fprintf(&content, "**CLI Release:** [docker/cli v%s](%s)\n\n", info.Version, cliURL)
+	major, _, _ := strings.Cut(info.Version, ".")
+	fmt.Fprintf(&content, "**Release Notes:** [Docker Engine %s release notes](%s/%s/#%s)\n\n", major, dockerReleaseNotes, major, strings.ReplaceAll(info.Version, ".", ""))
+
+	content.WriteString("## API Version\n\n")
+	if info.APIVersion != "" {
+		fmt.Fprintf(&content, "Docker Engine %s serves Engine API **v%s** ([reference](%s/v%s/)). ", info.Version, info.APIVersion, dockerAPIReference, info.APIVersion)
+		content.WriteString("Clients negotiate down to older API versions; set `DOCKER_API_VERSION` to pin one.\n\n")
+	} else {
+		content.WriteString("The API version of this release line is not in the table below; run `docker version` to see it.\n\n")
+	}
+	content.WriteString("| Engine | API |\n")
+	content.WriteString("|--------|-----|\n")
+	line := dockerReleaseLine(info.Version)
+	// The newest lines are the ones worth comparing against
+	start := max(0, len(dockerAPIVersions)-12)
+	for _, v := range dockerAPIVersions[start:] {
+		if v.Engine == line {
+			fmt.Fprintf(&content, "| **%s** | **%s** |\n", v.Engine, v.API)
+			continue
+		}
+		fmt.Fprintf(&content, "| %s | %s |\n", v.Engine, v.API)
+	}
+	content.WriteString("\n")
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("### Using the convenience script (Linux, test environments)\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "curl -fsSL https://get.docker.com | sh -s -- --version %s\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using apt (Debian, Ubuntu)\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("# After adding the Docker apt repository\n")
+	fmt.Fprintf(&content, "VERSION_STRING=$(apt-cache madison docker-ce | awk '{ print $3 }' | grep '^5:%s-' | head -n1)\n", info.Version)
+	content.WriteString("sudo apt-get install docker-ce=$VERSION_STRING docker-ce-cli=$VERSION_STRING containerd.io docker-buildx-plugin docker-compose-plugin\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using dnf (Fedora, RHEL)\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("# After adding the Docker dnf repository\n")
+	fmt.Fprintf(&content, "VERSION_STRING=$(dnf list docker-ce --showduplicates -q | awk '{ print $2 }' | grep '^3:%s-' | head -n1)\n", info.Version)
+	content.WriteString("sudo dnf install docker-ce-$VERSION_STRING docker-ce-cli-$VERSION_STRING containerd.io docker-buildx-plugin docker-compose-plugin\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Static binaries\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "curl -fsSLO https://download.docker.com/linux/static/stable/x86_64/docker-%s.tgz\n", info.Version)
+	fmt.Fprintf(&content, "tar xzf docker-%s.tgz && sudo cp docker/* /usr/bin/\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Docker in Docker\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "docker run --privileged -d docker:%s-dind\n", info.Version)
+	content.WriteString("```\n\n")
+
+	for _, notes := range []struct{ title, body string }{
+		{"Engine Release Notes", releaseNotes},
+		{"CLI Release Notes", cliNotes},
+	} {
+		body := strings.TrimSpace(strings.ReplaceAll(notes.body, "\r\n", "\n"))
+		if body == "" {
+			continue
+		}
+		fmt.Fprintf(&content, "## %s\n\n", notes.title)
+		body = markdown.DemoteHeadings(body, 1)
+		content.WriteString(strings.TrimSpace(truncateMarkdown(body, maxReleaseNotesChars, "The release notes are truncated; see GitHub for the rest.")))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("For detailed documentation, visit:\n\n")
+	content.WriteString("- [Docker Engine Installation](https://docs.docker.com/engine/install/)\n")
+	content.WriteString("- [Docker Engine Release Notes](https://docs.docker.com/engine/release-notes/)\n")
+	content.WriteString("- [Docker Engine API](https://docs.docker.com/reference/api/engine/)\n")
+	content.WriteString("- [moby/moby Releases](https://github.com/moby/moby/releases)\n")
+
+	return content.String()
+}
+
+func (f *DockerEngineFetcher) saveDockerEngineInfoAsMarkdown(filePath string, info *DockerEngineInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "version: \"%s\"\n", escapeYAML(info.Version))
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "releaseURL: \"%s\"\n", escapeYAML(info.ReleaseURL))
+	}
+	if info.CLIReleaseURL != "" {
+		fmt.Fprintf(&content, "cliReleaseURL: \"%s\"\n", escapeYAML(info.CLIReleaseURL))
+	}
+	if info.APIVersion != "" {
+		fmt.Fprintf(&content, "apiVersion: \"%s\"\n", info.APIVersion)
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *DockerEngineFetcher) loadDockerEngineInfoFromMarkdown(filePath string) (*DockerEngineInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info DockerEngineInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
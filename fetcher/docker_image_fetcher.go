@@ -2,26 +2,50 @@ package fetcher
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// dockerLibraryDocsURL serves the docker-library/docs repository, which
+	// maintains the documentation of the official images
+	dockerLibraryDocsURL = "https://raw.githubusercontent.com/docker-library/docs/master"
+
+	// maxOfficialDocsChars bounds the official documentation of one image
+	maxOfficialDocsChars = 40000
+
+	// maxLayerHistory caps the build steps listed for an image
+	maxLayerHistory = 100
+
+	// maxHistoryInstructionChars bounds the instruction shown for a step
+	maxHistoryInstructionChars = 200
 )
 
 type DockerImageInfo struct {
 	Image       string   `yaml:"image"`
 	Tag         string   `yaml:"tag"`
+	Registry    string   `yaml:"registry"`
 	Digest      string   `yaml:"digest"`
 	LastUpdated string   `yaml:"lastUpdated"`
 	FullImage   string   `yaml:"fullImage"`
 	Content     string   `yaml:"-"`
 	Tags        []string `yaml:"-"`
+	// OfficialDocs is the docker-library/docs documentation of an official
+	// image
+	OfficialDocs string `yaml:"-"`
 }
 
 type DockerHubTagResponse struct {
@@ -39,6 +63,20 @@ type DockerHubTagResponse struct {
 	} `json:"results"`
 }
 
+// dockerImageDetails is what the image page shows about a tag, whichever
+// registry it comes from
+type dockerImageDetails struct {
+	// Size is the compressed size, of one platform for multi-platform images
+	Size      int64
+	Platforms []string
+
+	// Config and Layers are those of ConfigPlatform for multi-platform
+	// images. Config is nil when it could not be read.
+	Config         *ociImageConfig
+	ConfigPlatform string
+	Layers         []ociDescriptor
+}
+
 type DockerImageFetcher struct {
 	*BaseFetcher
 }
@@ -49,14 +87,35 @@ func NewDockerImageFetcher(cacheDir string) *DockerImageFetcher {
 	}
 }
 
-// FetchDockerImage fetches information about a specific Docker image and tag
+// FetchDockerImage fetches information about a specific Docker image and
+// tag. Images are read from Docker Hub unless the reference names another
+// registry ("ghcr.io/owner/image", "quay.io/org/image",
+// "registry.k8s.io/pause"), which is then read over the OCI distribution API.
 func (f *DockerImageFetcher) FetchDockerImage(image, tag string) (*DockerImageInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchDockerImage", image, tag), f.imagePath(image, tag), func() (*DockerImageInfo, error) {
+		return f.fetchDockerImage(image, tag)
+	})
+}
+
+// imagePath returns the cache path of the info on an image tag
+func (f *DockerImageFetcher) imagePath(image, tag string) string {
+	if registry, repository := parseImageReference(image); registry != dockerHubRegistry {
+		return f.getCache().GetFilePath("docker", "images", fmt.Sprintf("%s.md", cache.EntryName(registry, repository, tag)))
+	}
+	namespace, repository := parseImageName(image)
+	return f.getCache().GetFilePath("docker", "images", fmt.Sprintf("%s.md", cache.EntryName(namespace, repository, tag)))
+}
+
+func (f *DockerImageFetcher) fetchDockerImage(image, tag string) (*DockerImageInfo, error) {
+	if registry, repository := parseImageReference(image); registry != dockerHubRegistry {
+		return f.fetchRegistryImage(image, registry, repository, tag)
+	}
+
 	// Normalize image name (handle official images)
 	namespace, repository := parseImageName(image)
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("%s_%s_%s", namespace, repository, tag)
-	cachedPath := f.getCache().GetFilePath("docker", "images", fmt.Sprintf("%s.md", cacheKey))
+	cachedPath := f.imagePath(image, tag)
 	imageInfo, err := f.loadImageInfoFromMarkdown(cachedPath)
 	if err == nil && imageInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Docker image '%s:%s' from cache\n", image, tag)
@@ -83,14 +142,85 @@ func (f *DockerImageFetcher) FetchDockerImage(image, tag string) (*DockerImageIn
 	imageInfo = &DockerImageInfo{
 		Image:       image,
 		Tag:         tag,
+		Registry:    dockerHubRegistry,
 		Digest:      tagInfo.Results[0].Digest,
 		LastUpdated: tagInfo.Results[0].LastUpdated.Format("2006-01-02"),
 		FullImage:   fmt.Sprintf("%s:%s", image, tag),
 		Tags:        tags,
 	}
 
+	// Docker Hub's API has no image config; read it from the registry
+	details := hubImageDetails(tagInfo)
+	client := f.newRegistryClient(dockerHubRegistry, namespace+"/"+repository)
+	if manifest, _, err := client.manifest(tag); err == nil {
+		registryDetails := f.registryDetails(client, manifest)
+		details.Config = registryDetails.Config
+		details.ConfigPlatform = registryDetails.ConfigPlatform
+		details.Layers = registryDetails.Layers
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch image manifest: %v\n", err)
+	}
+
+	// Official images are documented in docker-library/docs
+	if namespace == "library" {
+		docs, err := f.fetchOfficialDocs(repository)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch official image docs: %v\n", err)
+		}
+		imageInfo.OfficialDocs = docs
+	}
+
 	// Build content
-	imageInfo.Content = f.buildImageContent(imageInfo, tagInfo)
+	imageInfo.Content = f.buildImageContent(imageInfo, details)
+
+	// Cache the result
+	if err := f.saveImageInfoAsMarkdown(cachedPath, imageInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache image info: %v\n", err)
+	}
+
+	return imageInfo, nil
+}
+
+// fetchRegistryImage reads an image from a registry other than Docker Hub
+func (f *DockerImageFetcher) fetchRegistryImage(image, registry, repository, tag string) (*DockerImageInfo, error) {
+	// Check cache first
+	cachedPath := f.imagePath(image, tag)
+	imageInfo, err := f.loadImageInfoFromMarkdown(cachedPath)
+	if err == nil && imageInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Docker image '%s:%s' from cache\n", image, tag)
+		return imageInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Docker image '%s:%s' from %s...\n", image, tag, registry)
+
+	client := f.newRegistryClient(registry, repository)
+	manifest, digest, err := client.manifest(tag)
+	if errors.Is(err, errRegistryNotFound) {
+		return nil, fmt.Errorf("docker image %s:%s not found", image, tag)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image manifest: %w", err)
+	}
+
+	details := f.registryDetails(client, manifest)
+
+	tags, err := client.tags(1000)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch available tags: %v\n", err)
+	}
+
+	imageInfo = &DockerImageInfo{
+		Image:     image,
+		Tag:       tag,
+		Registry:  registry,
+		Digest:    digest,
+		FullImage: fmt.Sprintf("%s:%s", image, tag),
+		Tags:      recentRegistryTags(tags, 20),
+	}
+	if details.Config != nil {
+		imageInfo.LastUpdated = releaseDate(details.Config.Created)
+	}
+	imageInfo.Content = f.buildImageContent(imageInfo, details)
 
 	// Cache the result
 	if err := f.saveImageInfoAsMarkdown(cachedPath, imageInfo); err != nil {
@@ -100,6 +230,156 @@ func (f *DockerImageFetcher) FetchDockerImage(image, tag string) (*DockerImageIn
 	return imageInfo, nil
 }
 
+// registryDetails reads the platforms, size, and config of an image from
+// its manifest. The size and config of a multi-platform image are those of
+// linux/amd64, or of its first platform without that.
+func (f *DockerImageFetcher) registryDetails(client *registryClient, manifest *ociManifest) *dockerImageDetails {
+	details := &dockerImageDetails{}
+	if manifest.isIndex() {
+		var chosen *ociDescriptor
+		for i, m := range manifest.Manifests {
+			// Attestation manifests are listed as unknown/unknown
+			if m.Platform == nil || m.Platform.OS == "unknown" {
+				continue
+			}
+			details.Platforms = append(details.Platforms, m.Platform.String())
+			if chosen == nil || (m.Platform.OS == "linux" && m.Platform.Architecture == "amd64") {
+				chosen = &manifest.Manifests[i]
+			}
+		}
+		if chosen == nil {
+			return details
+		}
+		platformManifest, _, err := client.manifest(chosen.Digest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s manifest: %v\n", chosen.Platform, err)
+			return details
+		}
+		manifest = platformManifest
+		details.ConfigPlatform = chosen.Platform.String()
+	}
+
+	details.Size = manifest.imageSize()
+	details.Layers = manifest.Layers
+	config, err := client.config(manifest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch image config: %v\n", err)
+		return details
+	}
+	details.Config = config
+	return details
+}
+
+// recentRegistryTags picks up to limit tags from a registry tag list, which
+// has no dates, in reverse order as the Docker Hub tags are. Signature and
+// attestation tags ("sha256-<digest>.sig") are left out.
+func recentRegistryTags(tags []string, limit int) []string {
+	kept := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, "sha256-") {
+			kept = append(kept, tag)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(kept)))
+	if len(kept) > limit {
+		kept = kept[:limit]
+	}
+	return kept
+}
+
+// hubImageDetails takes the details of a tag from the Docker Hub API
+func hubImageDetails(tagData *DockerHubTagResponse) *dockerImageDetails {
+	details := &dockerImageDetails{}
+	if len(tagData.Results) == 0 {
+		return details
+	}
+
+	tag := tagData.Results[0]
+	details.Size = tag.FullSize
+	seen := make(map[string]bool)
+	for _, img := range tag.Images {
+		platform := fmt.Sprintf("%s/%s", img.OS, img.Architecture)
+		if !seen[platform] {
+			seen[platform] = true
+			details.Platforms = append(details.Platforms, platform)
+		}
+	}
+	return details
+}
+
+// fetchOfficialDocs reads the documentation of an official image from
+// docker-library/docs and fills in its template placeholders. It returns
+// an empty string for images without documentation there.
+func (f *DockerImageFetcher) fetchOfficialDocs(repository string) (string, error) {
+	content, err := f.fetchLibraryDocsFile(repository, "content.md")
+	if err != nil || content == "" {
+		return "", err
+	}
+
+	var lines []string
+	skipBlank := false
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		// The logo is an image on Docker Hub only; drop it with the blank
+		// line after it
+		if strings.TrimSpace(line) == "%%LOGO%%" {
+			skipBlank = true
+			continue
+		}
+		if skipBlank && strings.TrimSpace(line) == "" {
+			skipBlank = false
+			continue
+		}
+		skipBlank = false
+		lines = append(lines, line)
+	}
+	content = strings.Join(lines, "\n")
+
+	content = strings.ReplaceAll(content, "%%IMAGE%%", repository)
+	content = strings.ReplaceAll(content, "%%REPO%%", repository)
+	// Example stacks are kept beside content.md
+	for placeholder, file := range map[string]string{"%%STACK%%": "stack.yml", "%%COMPOSE%%": "compose.yaml"} {
+		if !strings.Contains(content, placeholder) {
+			continue
+		}
+		example, err := f.fetchLibraryDocsFile(repository, file)
+		if err != nil || example == "" {
+			example = fmt.Sprintf("# See https://github.com/docker-library/docs/blob/master/%s/%s", repository, file)
+		}
+		content = strings.ReplaceAll(content, placeholder, strings.TrimSpace(example))
+	}
+
+	return strings.TrimSpace(content), nil
+}
+
+// fetchLibraryDocsFile reads one file of an image's docker-library/docs
+// directory, returning an empty string if it does not exist
+func (f *DockerImageFetcher) fetchLibraryDocsFile(repository, file string) (string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", dockerLibraryDocsURL, repository, file), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", file, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("docker-library/docs returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return string(body), nil
+}
+
 func (f *DockerImageFetcher) fetchTagInfo(namespace, repository, tag string) (*DockerHubTagResponse, error) {
 	apiURL := fmt.Sprintf("https://hub.docker.com/v2/repositories/%s/%s/tags/%s", namespace, repository, tag)
 	req, err := http.NewRequest("GET", apiURL, nil)
@@ -203,40 +483,40 @@ func (f *DockerImageFetcher) fetchAvailableTags(namespace, repository string, li
 	return tags, nil
 }
 
-func (f *DockerImageFetcher) buildImageContent(info *DockerImageInfo, tagData *DockerHubTagResponse) string {
+func (f *DockerImageFetcher) buildImageContent(info *DockerImageInfo, details *dockerImageDetails) string {
 	var content strings.Builder
 
 	fmt.Fprintf(&content, "# Docker Image: %s\n\n", info.FullImage)
 
-	if len(tagData.Results) > 0 {
-		tag := tagData.Results[0]
-
-		content.WriteString("## Image Information\n\n")
-		fmt.Fprintf(&content, "**Tag:** %s\n\n", info.Tag)
+	content.WriteString("## Image Information\n\n")
+	fmt.Fprintf(&content, "**Tag:** %s\n\n", info.Tag)
+	if info.Registry != "" {
+		fmt.Fprintf(&content, "**Registry:** %s\n\n", info.Registry)
+	}
+	if info.LastUpdated != "" {
 		fmt.Fprintf(&content, "**Last Updated:** %s\n\n", info.LastUpdated)
+	}
 
-		if tag.FullSize > 0 {
-			sizeMB := float64(tag.FullSize) / (1024 * 1024)
-			fmt.Fprintf(&content, "**Size:** %.2f MB\n\n", sizeMB)
-		}
+	if details.Size > 0 {
+		sizeMB := float64(details.Size) / (1024 * 1024)
+		fmt.Fprintf(&content, "**Size:** %.2f MB\n\n", sizeMB)
+	}
 
-		if info.Digest != "" {
-			fmt.Fprintf(&content, "**Digest:** `%s`\n\n", info.Digest)
-		}
+	if info.Digest != "" {
+		fmt.Fprintf(&content, "**Digest:** `%s`\n\n", info.Digest)
+	}
 
-		// Architecture information
-		if len(tag.Images) > 0 {
-			content.WriteString("## Available Architectures\n\n")
-			archMap := make(map[string]bool)
-			for _, img := range tag.Images {
-				arch := fmt.Sprintf("%s/%s", img.OS, img.Architecture)
-				archMap[arch] = true
-			}
-			for arch := range archMap {
-				fmt.Fprintf(&content, "- %s\n", arch)
-			}
-			content.WriteString("\n")
+	// Architecture information
+	if len(details.Platforms) > 0 {
+		content.WriteString("## Available Architectures\n\n")
+		for _, platform := range details.Platforms {
+			fmt.Fprintf(&content, "- %s\n", platform)
 		}
+		content.WriteString("\n")
+	}
+
+	if details.Config != nil {
+		writeImageConfig(&content, details)
 	}
 
 	// Usage examples
@@ -270,26 +550,161 @@ func (f *DockerImageFetcher) buildImageContent(info *DockerImageInfo, tagData *D
 		content.WriteString("\n")
 	}
 
+	if info.OfficialDocs != "" {
+		content.WriteString("## Official Image Documentation\n\n")
+		_, repository := parseImageReference(info.Image)
+		fmt.Fprintf(&content, "From [docker-library/docs](https://github.com/docker-library/docs/tree/master/%s), maintained with the official image.\n\n", strings.TrimPrefix(repository, "library/"))
+		docs := markdown.DemoteHeadings(info.OfficialDocs, 2)
+		content.WriteString(strings.TrimSpace(truncateMarkdown(docs, maxOfficialDocsChars, "The documentation is truncated; see Docker Hub for the rest.")))
+		content.WriteString("\n\n")
+	}
+
 	content.WriteString("## Documentation\n\n")
-	fmt.Fprintf(&content, "- [Docker Hub Repository](https://hub.docker.com/r/%s)\n", info.Image)
+	switch info.Registry {
+	case "", dockerHubRegistry:
+		fmt.Fprintf(&content, "- [Docker Hub Repository](https://hub.docker.com/r/%s)\n", info.Image)
+	case "quay.io":
+		_, repository := parseImageReference(info.Image)
+		fmt.Fprintf(&content, "- [Quay Repository](https://quay.io/repository/%s)\n", repository)
+	}
 	content.WriteString("- [Docker Documentation](https://docs.docker.com/)\n")
 
 	return content.String()
 }
 
-func (f *DockerImageFetcher) saveImageInfoAsMarkdown(filePath string, info *DockerImageInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+// writeImageConfig writes how containers of an image run and the build
+// steps of its layers
+func writeImageConfig(content *strings.Builder, details *dockerImageDetails) {
+	config := details.Config
+
+	content.WriteString("## Configuration\n\n")
+	if details.ConfigPlatform != "" {
+		fmt.Fprintf(content, "Configuration of the %s image.\n\n", details.ConfigPlatform)
+	}
+	if len(config.Config.Entrypoint) > 0 {
+		fmt.Fprintf(content, "**Entrypoint:** `%s`\n\n", execForm(config.Config.Entrypoint))
+	}
+	if len(config.Config.Cmd) > 0 {
+		fmt.Fprintf(content, "**Cmd:** `%s`\n\n", execForm(config.Config.Cmd))
+	}
+	if config.Config.WorkingDir != "" {
+		fmt.Fprintf(content, "**Working Directory:** `%s`\n\n", config.Config.WorkingDir)
+	}
+	if config.Config.User != "" {
+		fmt.Fprintf(content, "**User:** `%s`\n\n", config.Config.User)
+	}
+	if len(config.Config.ExposedPorts) > 0 {
+		ports := make([]string, 0, len(config.Config.ExposedPorts))
+		for port := range config.Config.ExposedPorts {
+			ports = append(ports, port)
+		}
+		// Ports are "<number>/<protocol>"; order them by number
+		sort.Slice(ports, func(i, j int) bool {
+			pi, _ := strconv.Atoi(strings.Split(ports[i], "/")[0])
+			pj, _ := strconv.Atoi(strings.Split(ports[j], "/")[0])
+			if pi != pj {
+				return pi < pj
+			}
+			return ports[i] < ports[j]
+		})
+		fmt.Fprintf(content, "**Exposed Ports:** `%s`\n\n", strings.Join(ports, "`, `"))
+	}
+	if len(config.Config.Env) > 0 {
+		content.WriteString("### Environment\n\n")
+		content.WriteString("```\n")
+		for _, env := range config.Config.Env {
+			content.WriteString(env + "\n")
+		}
+		content.WriteString("```\n\n")
+	}
+
+	if len(config.History) == 0 {
+		return
 	}
 
+	// Steps that created a layer match the manifest layers in order
+	var layerSteps int
+	for _, step := range config.History {
+		if !step.EmptyLayer {
+			layerSteps++
+		}
+	}
+	withSizes := layerSteps == len(details.Layers)
+
+	content.WriteString("## Layer History\n\n")
+	content.WriteString("| # | Created | Size | Instruction |\n")
+	content.WriteString("|---|---------|------|-------------|\n")
+	layer := 0
+	for i, step := range config.History {
+		size := "-"
+		if !step.EmptyLayer {
+			if withSizes {
+				size = formatAssetSize(details.Layers[layer].Size)
+			}
+			layer++
+		}
+		if i < maxLayerHistory {
+			fmt.Fprintf(content, "| %d | %s | %s | %s |\n", i+1, releaseDate(step.Created), size, historyInstruction(step))
+		}
+	}
+	content.WriteString("\n")
+	if len(config.History) > maxLayerHistory {
+		fmt.Fprintf(content, "Only the first %d of %d build steps are listed.\n\n", maxLayerHistory, len(config.History))
+	}
+}
+
+// execForm formats a command as the JSON array of a Dockerfile exec form
+func execForm(args []string) string {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return strings.Join(args, " ")
+	}
+	return string(data)
+}
+
+// historyInstruction formats the command a build step was recorded with as
+// a Dockerfile-like instruction for a table cell
+func historyInstruction(step ociHistory) string {
+	instruction := step.CreatedBy
+	if instruction == "" {
+		instruction = step.Comment
+	}
+	instruction = strings.TrimSuffix(strings.TrimSpace(instruction), "# buildkit")
+	// The legacy builder records "/bin/sh -c #(nop) CMD ..." and
+	// "/bin/sh -c apt-get ..."; BuildKit records "RUN /bin/sh -c apt-get ..."
+	if rest, ok := strings.CutPrefix(instruction, "/bin/sh -c #(nop) "); ok {
+		instruction = rest
+	} else if rest, ok := strings.CutPrefix(instruction, "/bin/sh -c "); ok {
+		instruction = "RUN " + rest
+	} else if rest, ok := strings.CutPrefix(instruction, "RUN /bin/sh -c "); ok {
+		instruction = "RUN " + rest
+	}
+
+	instruction = strings.Join(strings.Fields(instruction), " ")
+	if len(instruction) > maxHistoryInstructionChars {
+		cut := maxHistoryInstructionChars
+		for cut > 0 && !utf8.RuneStart(instruction[cut]) {
+			cut--
+		}
+		instruction = instruction[:cut] + "…"
+	}
+	if instruction == "" {
+		return ""
+	}
+	instruction = strings.ReplaceAll(instruction, "`", "'")
+	return "`" + strings.ReplaceAll(instruction, "|", "\\|") + "`"
+}
+
+func (f *DockerImageFetcher) saveImageInfoAsMarkdown(filePath string, info *DockerImageInfo) error {
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "image: \"%s\"\n", info.Image)
 	fmt.Fprintf(&content, "tag: \"%s\"\n", info.Tag)
+	if info.Registry != "" {
+		fmt.Fprintf(&content, "registry: \"%s\"\n", info.Registry)
+	}
 	if info.Digest != "" {
 		fmt.Fprintf(&content, "digest: \"%s\"\n", info.Digest)
 	}
@@ -302,7 +717,7 @@ func (f *DockerImageFetcher) saveImageInfoAsMarkdown(filePath string, info *Dock
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *DockerImageFetcher) loadImageInfoFromMarkdown(filePath string) (*DockerImageInfo, error) {
@@ -311,7 +726,7 @@ func (f *DockerImageFetcher) loadImageInfoFromMarkdown(filePath string) (*Docker
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -325,6 +740,7 @@ func (f *DockerImageFetcher) loadImageInfoFromMarkdown(filePath string) (*Docker
 	var meta struct {
 		Image       string `yaml:"image"`
 		Tag         string `yaml:"tag"`
+		Registry    string `yaml:"registry"`
 		Digest      string `yaml:"digest"`
 		LastUpdated string `yaml:"lastUpdated"`
 		FullImage   string `yaml:"fullImage"`
@@ -337,6 +753,7 @@ func (f *DockerImageFetcher) loadImageInfoFromMarkdown(filePath string) (*Docker
 	return &DockerImageInfo{
 		Image:       meta.Image,
 		Tag:         meta.Tag,
+		Registry:    meta.Registry,
 		Digest:      meta.Digest,
 		LastUpdated: meta.LastUpdated,
 		FullImage:   meta.FullImage,
@@ -347,12 +764,9 @@ func (f *DockerImageFetcher) loadImageInfoFromMarkdown(filePath string) (*Docker
 // parseImageName parses a Docker image name into namespace and repository
 // For official images like "golang", it returns ("library", "golang")
 // For user images like "myuser/myapp", it returns ("myuser", "myapp")
+// A "docker.io/" prefix is dropped.
 func parseImageName(image string) (namespace, repository string) {
-	parts := strings.Split(image, "/")
-	if len(parts) == 1 {
-		// Official image
-		return "library", parts[0]
-	}
-	// User/org image
-	return parts[0], parts[1]
+	_, name := parseImageReference(image)
+	namespace, repository, _ = strings.Cut(name, "/")
+	return namespace, repository
 }
@@ -0,0 +1,496 @@
+package fetcher
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/incu6us/open-context/markdown"
+	"github.com/incu6us/open-context/sqlite"
+)
+
+const (
+	// docsetPrefix names the documentation a Dash docset is imported as
+	docsetPrefix = "docset-"
+
+	// maxDocsetTopics caps the pages imported from one docset
+	maxDocsetTopics = 10000
+
+	// maxDocsetPageBytes skips pages too large to be one reference page
+	maxDocsetPageBytes = 8 << 20
+
+	// maxDocsetTopicChars bounds the content of one page
+	maxDocsetTopicChars = 60000
+
+	// maxDocsetKeywords caps the entry names kept as keywords of a page
+	maxDocsetKeywords = 50
+)
+
+// dashEntryPattern matches the metadata Dash prefixes entry paths with, e.g.
+// "<dash_entry_name=map><dash_entry_menuDescription=Array>"
+var dashEntryPattern = regexp.MustCompile(`<dash_entry_[^>]*>`)
+
+// DocsetInfo describes a Dash or Zeal docset imported into the cache
+type DocsetInfo struct {
+	Path string
+	Name string
+	// Documentation is the name the docset is searchable under
+	Documentation string
+	Entries       int
+	Topics        int
+	// Updated is always true: an import reads the docset again
+	Updated bool
+	Content string
+}
+
+// docsetEntry is a row of a docset's search index
+type docsetEntry struct {
+	name, kind, path string
+}
+
+// docsetPage is an HTML page of a docset and the index entries pointing
+// into it
+type docsetPage struct {
+	title, kind string
+	names       []string
+	// root is set once an entry for the page itself, not a fragment of it,
+	// was seen
+	root bool
+}
+
+// docsetMetadata is the metadata.json of an imported docset. The provider
+// reads the documentation fields; Docset records the import.
+type docsetMetadata struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+	Docset      struct {
+		Path       string         `json:"path"`
+		Identifier string         `json:"identifier,omitempty"`
+		Entries    int            `json:"entries"`
+		Topics     int            `json:"topics"`
+		Missing    int            `json:"missing"`
+		Types      map[string]int `json:"types"`
+	} `json:"docset"`
+}
+
+type DocsetFetcher struct {
+	*BaseFetcher
+}
+
+func NewDocsetFetcher(cacheDir string) *DocsetFetcher {
+	return &DocsetFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// DocsetDocumentation returns the documentation name a docset imported as
+// name is searchable under, e.g. "docset-postgresql"
+func DocsetDocumentation(name string) string {
+	return docsetPrefix + name
+}
+
+// ImportDocset indexes a Dash or Zeal docset (a .docset directory) in the
+// cache directory, where the documentation provider loads it. Each HTML
+// page the docset's SQLite index points at becomes a topic, titled after
+// its index entry, with the names of every entry pointing into it as
+// keywords. The documentation is named after name, defaulting to the
+// docset's bundle name. Importing a docset again replaces the topics of the
+// previous import.
+func (f *DocsetFetcher) ImportDocset(docsetPath, name string) (*DocsetInfo, error) {
+	docsetPath, err := filepath.Abs(docsetPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", docsetPath, err)
+	}
+	if stat, err := os.Stat(docsetPath); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", docsetPath, err)
+	} else if !stat.IsDir() {
+		return nil, fmt.Errorf("%s is not a docset directory", docsetPath)
+	}
+
+	plist := readPlist(filepath.Join(docsetPath, "Contents", "Info.plist"))
+	displayName := plist["CFBundleName"]
+	if displayName == "" {
+		displayName = strings.TrimSuffix(filepath.Base(docsetPath), filepath.Ext(docsetPath))
+	}
+	if name == "" {
+		name = localDocsName(displayName)
+	}
+	if !localDocsNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid name %q: use lowercase letters, digits, '-', and '_'", name)
+	}
+	docName := DocsetDocumentation(name)
+
+	return shareFetch(f.flights, flightKey("ImportDocset", docName), "", func() (*DocsetInfo, error) {
+		return f.importDocset(docsetPath, displayName, plist["CFBundleIdentifier"], name, docName)
+	})
+}
+
+func (f *DocsetFetcher) importDocset(docsetPath, displayName, identifier, name, docName string) (*DocsetInfo, error) {
+	fmt.Fprintf(os.Stderr, "Importing docset '%s'...\n", docsetPath)
+
+	resources := filepath.Join(docsetPath, "Contents", "Resources")
+	entries, err := readDocsetIndex(filepath.Join(resources, "docSet.dsidx"))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("the index of %s has no entries", docsetPath)
+	}
+
+	pages := make(map[string]*docsetPage)
+	var order []string
+	for _, entry := range entries {
+		pagePath, fragment, ok := docsetPagePath(entry.path)
+		if !ok {
+			continue
+		}
+		p := pages[pagePath]
+		if p == nil {
+			if len(pages) == maxDocsetTopics {
+				continue
+			}
+			p = &docsetPage{}
+			pages[pagePath] = p
+			order = append(order, pagePath)
+		}
+		if (fragment == "" && !p.root) || p.title == "" {
+			p.title, p.kind = entry.name, entry.kind
+			p.root = fragment == ""
+		}
+		p.names = append(p.names, entry.name)
+	}
+	if len(pages) == maxDocsetTopics {
+		fmt.Fprintf(os.Stderr, "Warning: importing the first %d pages of the docset\n", maxDocsetTopics)
+	}
+
+	bodies, err := readDocsetPages(resources, pages)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := make(map[string]*indexedTopic, len(bodies))
+	for _, pagePath := range order {
+		body, ok := bodies[pagePath]
+		if !ok {
+			continue
+		}
+		p := pages[pagePath]
+
+		content := markdown.FromHTML(body, "")
+		if !strings.HasPrefix(content, "# ") {
+			content = "# " + p.title + "\n\n" + content
+		}
+		content = truncateMarkdown(content, maxDocsetTopicChars, "Page truncated; see "+pagePath+" in the docset")
+
+		description := displayName
+		if p.kind != "" {
+			description += ": " + p.kind
+		}
+
+		names := uniqueStrings(p.names)
+		sort.Strings(names)
+		if len(names) > maxDocsetKeywords {
+			names = names[:maxDocsetKeywords]
+		}
+		keywords := append([]string{displayName, "docset"}, names...)
+		if p.kind != "" {
+			keywords = append(keywords, p.kind)
+		}
+
+		topics[pagePath] = &indexedTopic{
+			ID:          name + "/" + strings.TrimSuffix(pagePath, path.Ext(pagePath)),
+			Title:       p.title,
+			Description: description,
+			Content:     content,
+			Keywords:    uniqueStrings(keywords),
+			kind:        p.kind,
+		}
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("none of the %d pages indexed by %s could be read", len(pages), docsetPath)
+	}
+
+	docDir := filepath.Join(f.getCache().GetCacheDir(), docName)
+	kinds, err := writeTopics(docDir, topics)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &docsetMetadata{
+		Name:        docName,
+		DisplayName: displayName,
+		Description: fmt.Sprintf("%s docset imported from %s", displayName, docsetPath),
+	}
+	metadata.Docset.Path = docsetPath
+	metadata.Docset.Identifier = identifier
+	metadata.Docset.Entries = len(entries)
+	metadata.Docset.Topics = countTopics(kinds)
+	metadata.Docset.Missing = len(pages) - len(topics)
+	delete(kinds, "")
+	metadata.Docset.Types = kinds
+
+	if err := writeJSON(filepath.Join(docDir, "metadata.json"), metadata); err != nil {
+		return nil, fmt.Errorf("failed to write metadata: %w", err)
+	}
+	return docsetInfo(metadata), nil
+}
+
+// readDocsetIndex reads the entries of a docset's SQLite index: the
+// searchIndex table of Dash docsets, or the Core Data tables of docsets
+// generated by Apple's docsetutil
+func readDocsetIndex(indexPath string) ([]docsetEntry, error) {
+	db, err := sqlite.Open(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docset index: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	tables, err := db.Tables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docset index: %w", err)
+	}
+	for _, table := range tables {
+		if strings.EqualFold(table, "searchIndex") {
+			return readDashIndex(db)
+		}
+	}
+	for _, table := range tables {
+		if strings.EqualFold(table, "ZTOKEN") {
+			return readCoreDataIndex(db)
+		}
+	}
+	return nil, errors.New("docset index has neither a searchIndex nor a ZTOKEN table")
+}
+
+func readDashIndex(db *sqlite.DB) ([]docsetEntry, error) {
+	var entries []docsetEntry
+	err := db.Scan("searchIndex", func(row sqlite.Row) error {
+		entry := docsetEntry{name: rowString(row, "name"), kind: rowString(row, "type"), path: rowString(row, "path")}
+		if entry.name != "" && entry.path != "" {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docset index: %w", err)
+	}
+	return entries, nil
+}
+
+// readCoreDataIndex joins the tokens of a docsetutil index with their types,
+// files, and anchors
+func readCoreDataIndex(db *sqlite.DB) ([]docsetEntry, error) {
+	lookup := func(table, column string) (map[int64]string, error) {
+		values := make(map[int64]string)
+		err := db.Scan(table, func(row sqlite.Row) error {
+			if pk, ok := row["Z_PK"].(int64); ok {
+				values[pk] = rowString(row, column)
+			}
+			return nil
+		})
+		return values, err
+	}
+
+	types, err := lookup("ZTOKENTYPE", "ZTYPENAME")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docset token types: %w", err)
+	}
+	files, err := lookup("ZFILEPATH", "ZPATH")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docset file paths: %w", err)
+	}
+
+	type location struct{ file, anchor string }
+	locations := make(map[int64]location)
+	err = db.Scan("ZTOKENMETAINFORMATION", func(row sqlite.Row) error {
+		pk, _ := row["Z_PK"].(int64)
+		file, _ := row["ZFILE"].(int64)
+		locations[pk] = location{files[file], rowString(row, "ZANCHOR")}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docset token locations: %w", err)
+	}
+
+	var entries []docsetEntry
+	err = db.Scan("ZTOKEN", func(row sqlite.Row) error {
+		kind, _ := row["ZTOKENTYPE"].(int64)
+		meta, _ := row["ZMETAINFORMATION"].(int64)
+		loc := locations[meta]
+		entry := docsetEntry{name: rowString(row, "ZTOKENNAME"), kind: types[kind], path: loc.file}
+		if loc.anchor != "" {
+			entry.path += "#" + loc.anchor
+		}
+		if entry.name != "" && loc.file != "" {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docset tokens: %w", err)
+	}
+	return entries, nil
+}
+
+func rowString(row sqlite.Row, column string) string {
+	s, _ := row[column].(string)
+	return s
+}
+
+// docsetPagePath reduces an index entry path to the page it points into,
+// relative to the docset's Documents directory, and the fragment within
+// it. Entries linking to websites rather than bundled pages are skipped.
+func docsetPagePath(entryPath string) (string, string, bool) {
+	entryPath = dashEntryPattern.ReplaceAllString(entryPath, "")
+	if strings.Contains(entryPath, "://") {
+		return "", "", false
+	}
+	pagePath, fragment, _ := strings.Cut(entryPath, "#")
+	pagePath, _, _ = strings.Cut(pagePath, "?")
+	if unescaped, err := url.PathUnescape(pagePath); err == nil {
+		pagePath = unescaped
+	}
+	pagePath = path.Clean("/" + pagePath)[1:]
+	if pagePath == "" {
+		return "", "", false
+	}
+	return pagePath, fragment, true
+}
+
+// readDocsetPages reads the HTML of the pages of a docset from its
+// Documents directory, or from tarix.tgz for docsets that keep their pages
+// compressed
+func readDocsetPages(resources string, pages map[string]*docsetPage) (map[string]string, error) {
+	bodies := make(map[string]string, len(pages))
+
+	documents := filepath.Join(resources, "Documents")
+	if _, err := os.Stat(documents); err == nil {
+		for pagePath := range pages {
+			file := filepath.Join(documents, filepath.FromSlash(pagePath))
+			if stat, err := os.Stat(file); err != nil || stat.Size() > maxDocsetPageBytes {
+				continue
+			}
+			if data, err := os.ReadFile(file); err == nil {
+				bodies[pagePath] = string(data)
+			}
+		}
+		return bodies, nil
+	}
+
+	archive, err := os.Open(filepath.Join(resources, "tarix.tgz"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("docset has neither a Documents directory nor tarix.tgz in %s", resources)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docset archive: %w", err)
+	}
+	defer func() { _ = archive.Close() }()
+
+	gz, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docset archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read docset archive: %w", err)
+		}
+		_, pagePath, ok := strings.Cut(header.Name, "/Contents/Resources/Documents/")
+		if !ok || header.Typeflag != tar.TypeReg || header.Size > maxDocsetPageBytes {
+			continue
+		}
+		if _, wanted := pages[pagePath]; !wanted {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from docset archive: %w", pagePath, err)
+		}
+		bodies[pagePath] = string(data)
+	}
+	return bodies, nil
+}
+
+// readPlist reads the string values of a docset's Info.plist, such as
+// CFBundleName. A missing or malformed plist yields no values.
+func readPlist(plistPath string) map[string]string {
+	values := make(map[string]string)
+	data, err := os.ReadFile(plistPath)
+	if err != nil {
+		return values
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	var element, key string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return values
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element = t.Name.Local
+		case xml.EndElement:
+			element = ""
+		case xml.CharData:
+			switch element {
+			case "key":
+				key = strings.TrimSpace(string(t))
+			case "string":
+				if key != "" {
+					values[key] = strings.TrimSpace(string(t))
+					key = ""
+				}
+			}
+		}
+	}
+}
+
+// docsetInfo summarizes an imported docset
+func docsetInfo(metadata *docsetMetadata) *DocsetInfo {
+	info := &DocsetInfo{
+		Path:          metadata.Docset.Path,
+		Name:          metadata.DisplayName,
+		Documentation: metadata.Name,
+		Entries:       metadata.Docset.Entries,
+		Topics:        metadata.Docset.Topics,
+		Updated:       true,
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Docset: %s\n\n", info.Name)
+	fmt.Fprintf(&b, "**Source:** %s\n", info.Path)
+	if metadata.Docset.Identifier != "" {
+		fmt.Fprintf(&b, "**Identifier:** %s\n", metadata.Docset.Identifier)
+	}
+	fmt.Fprintf(&b, "**Documentation:** %s\n", info.Documentation)
+	fmt.Fprintf(&b, "**Index entries:** %d\n", info.Entries)
+	fmt.Fprintf(&b, "**Topics:** %d\n", info.Topics)
+	if metadata.Docset.Missing > 0 {
+		fmt.Fprintf(&b, "**Pages not found:** %d\n", metadata.Docset.Missing)
+	}
+	b.WriteString("\n")
+
+	writeSections(&b, metadata.Docset.Types)
+
+	info.Content = b.String()
+	return info
+}
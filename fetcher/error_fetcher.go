@@ -0,0 +1,127 @@
+package fetcher
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// errorTools maps the tool names explain_error accepts to the catalog that
+// explains their diagnostics
+var errorTools = map[string]string{
+	"go":         "go",
+	"golang":     "go",
+	"gc":         "go",
+	"go build":   "go",
+	"gopls":      "go",
+	"vet":        "vet",
+	"go vet":     "vet",
+	"typescript": "typescript",
+	"ts":         "typescript",
+	"tsc":        "typescript",
+	"rust":       "rust",
+	"rustc":      "rust",
+	"cargo":      "rust",
+	"kubectl":    "kubectl",
+	"kubernetes": "kubectl",
+	"k8s":        "kubectl",
+}
+
+// ErrorExplanation is the official explanation of a diagnostic
+type ErrorExplanation struct {
+	// Tool is the catalog that explained the error: go, vet, typescript,
+	// rust, or kubectl
+	Tool string
+	// Code is the diagnostic code (e.g. UnusedVar, copylocks, TS2322,
+	// E0382) or the name of the kubectl error pattern
+	Code    string
+	Content string
+}
+
+type ErrorFetcher struct {
+	*BaseFetcher
+}
+
+func NewErrorFetcher(cacheDir string) *ErrorFetcher {
+	return &ErrorFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// ExplainError explains an error message or diagnostic code of a toolchain
+// from its official catalog: the Go type checker's error codes, the go vet
+// analyzers, TypeScript's diagnostic messages, the rustc error index, or
+// common kubectl errors. Without a tool, it is told from the message.
+func (f *ErrorFetcher) ExplainError(tool, message string) (*ErrorExplanation, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, fmt.Errorf("message is required")
+	}
+
+	key := strings.ToLower(strings.TrimSpace(tool))
+	catalog := errorTools[key]
+	if key != "" && catalog == "" {
+		return nil, fmt.Errorf("unsupported tool %q (supported: go, go vet, typescript, rust, kubectl)", tool)
+	}
+	if catalog == "" {
+		catalog = detectErrorTool(message)
+		if catalog == "" {
+			return nil, fmt.Errorf("could not tell which tool printed %q; pass the tool (go, go vet, typescript, rust, or kubectl)", message)
+		}
+	}
+
+	return shareFetch(f.flights, flightKey("ExplainError", catalog, message), "", func() (*ErrorExplanation, error) {
+		switch catalog {
+		case "go":
+			return f.explainGoError(message)
+		case "vet":
+			return f.explainGoVetError(message)
+		case "typescript":
+			return f.explainTypeScriptError(message)
+		case "rust":
+			return f.explainRustError(message)
+		default:
+			return explainKubectlError(message)
+		}
+	})
+}
+
+// detectErrorTool tells which toolchain printed a message from its codes
+// and wording, or returns ""
+func detectErrorTool(message string) string {
+	switch {
+	case typeScriptCodePattern.MatchString(message):
+		return "typescript"
+	case rustErrorCodePattern.MatchString(message):
+		return "rust"
+	case matchGoVetAnalyzer(message) != nil:
+		return "vet"
+	case len(matchGoErrorPatterns(message)) > 0:
+		return "go"
+	// Last, since patterns such as "not found" are the most generic
+	case matchKubectlError(message) != nil:
+		return "kubectl"
+	}
+	return ""
+}
+
+func (f *ErrorFetcher) get(url string) ([]byte, int, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	return body, resp.StatusCode, err
+}
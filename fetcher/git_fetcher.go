@@ -0,0 +1,288 @@
+package fetcher
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/semver"
+)
+
+const (
+	// gitRelNotesURL holds the release notes of every Git version, named
+	// 2.47.0.txt (or 2.47.0.adoc)
+	gitRelNotesURL = "https://raw.githubusercontent.com/git/git/master/Documentation/RelNotes"
+	gitTarballsURL = "https://mirrors.edge.kernel.org/pub/software/scm/git"
+)
+
+// gitTarballPattern matches the release tarballs of the kernel.org listing
+// with the date they were published, e.g.
+// <a href="git-2.47.0.tar.xz">git-2.47.0.tar.xz</a>    07-Oct-2024 17:29  7M
+var gitTarballPattern = regexp.MustCompile(`>git-(\d+\.\d+\.\d+)\.tar\.xz</a>\s+(\d{2}-[A-Za-z]{3}-\d{4})`)
+
+// gitVersionPattern matches the versions accepted by FetchGitVersion
+var gitVersionPattern = regexp.MustCompile(`^\d+\.\d+(\.\d+)?$`)
+
+type GitVersionInfo struct {
+	Version     string `yaml:"version"`
+	ReleaseDate string `yaml:"releaseDate"`
+	NotesURL    string `yaml:"notesURL"`
+	Content     string `yaml:"-"`
+}
+
+type GitFetcher struct {
+	*BaseFetcher
+}
+
+func NewGitFetcher(cacheDir string) *GitFetcher {
+	return &GitFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchGitVersion fetches the release notes of a Git version ("2.47.0",
+// "v2.47.0", or "2.47" for its .0 release) from the git/git repository, with
+// install commands for each platform, or of the latest release when version
+// is empty or "latest"
+func (f *GitFetcher) FetchGitVersion(version string) (*GitVersionInfo, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	switch {
+	case version == "latest":
+		version = ""
+	case version == "":
+	case !gitVersionPattern.MatchString(version):
+		return nil, fmt.Errorf("invalid Git version %q (expected e.g. '2.47.0')", version)
+	case strings.Count(version, ".") == 1:
+		version += ".0"
+	}
+	return shareFetch(f.flights, flightKey("FetchGitVersion", version), f.versionPath(version), func() (*GitVersionInfo, error) {
+		return f.fetchGitVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Git version
+func (f *GitFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("git", "versions", fmt.Sprintf("%s.md", cache.EntryName(cmp.Or(version, "latest"))))
+}
+
+func (f *GitFetcher) fetchGitVersion(version string) (*GitVersionInfo, error) {
+	// Check cache first
+	cachedPath := f.versionPath(version)
+	info, err := f.loadVersionInfoFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Git version '%s' from cache\n", info.Version)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Git version '%s' from git/git...\n", cmp.Or(version, "latest"))
+
+	// The tarball listing names the latest release and dates each one;
+	// a given version's notes stand on their own without it
+	releases, err := f.fetchReleases()
+	if err != nil {
+		if version == "" {
+			return nil, fmt.Errorf("failed to find the latest Git release: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: failed to list Git releases: %v\n", err)
+	}
+	if version == "" {
+		for v := range releases {
+			if version == "" || semver.Compare(v, version) > 0 {
+				version = v
+			}
+		}
+		if version == "" {
+			return nil, fmt.Errorf("no Git releases found at %s", gitTarballsURL)
+		}
+	}
+
+	notes, notesURL, err := f.fetchReleaseNotes(version)
+	if err != nil {
+		return nil, err
+	}
+
+	info = &GitVersionInfo{
+		Version:     version,
+		ReleaseDate: releases[version],
+		NotesURL:    notesURL,
+	}
+	info.Content = f.buildVersionContent(info, notes)
+
+	if err := f.saveVersionInfoAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// fetchReleases maps each version with a tarball on kernel.org to the date
+// it was published
+func (f *GitFetcher) fetchReleases() (map[string]string, error) {
+	page, found, err := f.get(gitTarballsURL + "/")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s not found", gitTarballsURL)
+	}
+
+	releases := make(map[string]string)
+	for _, m := range gitTarballPattern.FindAllStringSubmatch(string(page), -1) {
+		date := m[2]
+		if t, err := time.Parse("02-Jan-2006", date); err == nil {
+			date = t.Format("2006-01-02")
+		}
+		releases[m[1]] = date
+	}
+	return releases, nil
+}
+
+// fetchReleaseNotes returns the release notes of a version and their URL on
+// GitHub
+func (f *GitFetcher) fetchReleaseNotes(version string) (notes, notesURL string, err error) {
+	for _, ext := range []string{".txt", ".adoc"} {
+		body, found, err := f.get(fmt.Sprintf("%s/%s%s", gitRelNotesURL, version, ext))
+		if err != nil {
+			return "", "", fmt.Errorf("failed to fetch Git %s release notes: %w", version, err)
+		}
+		if found {
+			return string(body), fmt.Sprintf("https://github.com/git/git/blob/master/Documentation/RelNotes/%s%s", version, ext), nil
+		}
+	}
+	return "", "", fmt.Errorf("git version %s not found", version)
+}
+
+// gitRelNotesMarkdown turns the plain text release notes into markdown:
+// the "Git v2.47 Release Notes" title is dropped for the page's own, and
+// headings underlined with dashes become headings under the page's
+// Release Notes section
+func gitRelNotesMarkdown(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		if i+1 < len(lines) {
+			underline := strings.TrimSpace(lines[i+1])
+			if line != "" && len(underline) >= 3 && (strings.Trim(underline, "=") == "" || strings.Trim(underline, "-") == "") {
+				if underline[0] == '-' {
+					out = append(out, "### "+strings.TrimSpace(line))
+				}
+				i++
+				continue
+			}
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+func (f *GitFetcher) buildVersionContent(info *GitVersionInfo, notes string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Git %s\n\n", info.Version)
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+	if info.NotesURL != "" {
+		fmt.Fprintf(&content, "**Release Notes:** [%s](%s)\n\n", info.Version, info.NotesURL)
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("Package managers install their current Git; build from source for this exact version.\n\n")
+
+	content.WriteString("### From source (Linux, macOS)\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "curl -fsSLO %s/git-%s.tar.xz\n", gitTarballsURL, info.Version)
+	fmt.Fprintf(&content, "tar xf git-%s.tar.xz && cd git-%s\n", info.Version, info.Version)
+	content.WriteString("make prefix=/usr/local all\n")
+	content.WriteString("sudo make prefix=/usr/local install\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("### macOS (Homebrew)\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("brew install git\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Debian, Ubuntu\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("# The git-core PPA follows upstream releases on Ubuntu\n")
+	content.WriteString("sudo add-apt-repository ppa:git-core/ppa\n")
+	content.WriteString("sudo apt-get update && sudo apt-get install git\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Fedora, RHEL\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("sudo dnf install git\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Windows (Git for Windows)\n\n")
+	content.WriteString("```powershell\n")
+	fmt.Fprintf(&content, "winget install --id Git.Git -e --version %s\n", info.Version)
+	content.WriteString("```\n\n")
+	fmt.Fprintf(&content, "Installers: [Git for Windows v%s.windows.1](https://github.com/git-for-windows/git/releases/tag/v%s.windows.1)\n\n", info.Version, info.Version)
+
+	if body := gitRelNotesMarkdown(notes); body != "" {
+		content.WriteString("## Release Notes\n\n")
+		content.WriteString(strings.TrimSpace(truncateMarkdown(body, maxReleaseNotesChars, "The release notes are truncated; see GitHub for the rest.")))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("For detailed documentation, visit:\n\n")
+	content.WriteString("- [Git Documentation](https://git-scm.com/doc)\n")
+	content.WriteString("- [Git Downloads](https://git-scm.com/downloads)\n")
+	content.WriteString("- [Git Release Notes](https://github.com/git/git/tree/master/Documentation/RelNotes)\n")
+
+	return content.String()
+}
+
+func (f *GitFetcher) saveVersionInfoAsMarkdown(filePath string, info *GitVersionInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	if info.NotesURL != "" {
+		fmt.Fprintf(&content, "notesURL: \"%s\"\n", escapeYAML(info.NotesURL))
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *GitFetcher) loadVersionInfoFromMarkdown(filePath string) (*GitVersionInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info GitVersionInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -6,10 +6,11 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
 )
 
 type GitHubActionInfo struct {
@@ -37,14 +38,19 @@ func NewGitHubActionsFetcher(cacheDir string) *GitHubActionsFetcher {
 // FetchActionInfo fetches information about a GitHub Action
 // repository should be in format "owner/repo" (e.g., "actions/checkout")
 func (f *GitHubActionsFetcher) FetchActionInfo(repository, version string) (*GitHubActionInfo, error) {
-	// Sanitize repository name for file system
-	safeName := strings.ReplaceAll(repository, "/", "_")
-	if version != "" {
-		safeName = fmt.Sprintf("%s_%s", safeName, version)
-	}
+	return shareFetch(f.flights, flightKey("FetchActionInfo", repository, version), f.actionPath(repository, version), func() (*GitHubActionInfo, error) {
+		return f.fetchActionInfo(repository, version)
+	})
+}
 
+// actionPath returns the cache path of the info on an action
+func (f *GitHubActionsFetcher) actionPath(repository, version string) string {
+	return f.getCache().GetFilePath("github-actions", "actions", fmt.Sprintf("%s.md", cache.EntryName(repository, version)))
+}
+
+func (f *GitHubActionsFetcher) fetchActionInfo(repository, version string) (*GitHubActionInfo, error) {
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("github-actions", "actions", fmt.Sprintf("%s.md", safeName))
+	cachedPath := f.actionPath(repository, version)
 	actionInfo, err := f.loadActionInfoFromMarkdown(cachedPath)
 	if err == nil && actionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded GitHub Action '%s' from cache\n", repository)
@@ -316,12 +322,6 @@ func (f *GitHubActionsFetcher) buildActionContent(info *GitHubActionInfo, action
 }
 
 func (f *GitHubActionsFetcher) saveActionInfoAsMarkdown(filePath string, info *GitHubActionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
@@ -351,7 +351,7 @@ func (f *GitHubActionsFetcher) saveActionInfoAsMarkdown(filePath string, info *G
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *GitHubActionsFetcher) loadActionInfoFromMarkdown(filePath string) (*GitHubActionInfo, error) {
@@ -360,7 +360,7 @@ func (f *GitHubActionsFetcher) loadActionInfoFromMarkdown(filePath string) (*Git
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,296 @@
+package fetcher
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+// maxReadmeChars bounds the README kept in the cache; the rest stays a
+// link away on GitHub
+const maxReadmeChars = 60000
+
+var (
+	htmlCommentPattern  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlImagePattern    = regexp.MustCompile(`(?i)</?(?:img|picture|source)\b[^>]*>`)
+	relativeLinkPattern = regexp.MustCompile(`(!?)\[([^\]]*)\]\(([^)\s#][^)\s]*)([^)]*)\)`)
+)
+
+// GitHubReadmeInfo is the README of a GitHub repository at one ref
+type GitHubReadmeInfo struct {
+	Repository string `yaml:"repository"`
+	Ref        string `yaml:"ref"`
+	// File is the README's path in the repository, e.g. "README.rst"
+	File    string `yaml:"file"`
+	HTMLURL string `yaml:"htmlUrl"`
+	Content string `yaml:"-"`
+}
+
+type GitHubReadmeFetcher struct {
+	*BaseFetcher
+}
+
+func NewGitHubReadmeFetcher(cacheDir string) *GitHubReadmeFetcher {
+	return &GitHubReadmeFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchReadme fetches the README of a repository ("owner/repo") at a branch,
+// tag, or commit, or at the default branch when ref is empty
+func (f *GitHubReadmeFetcher) FetchReadme(repository, ref string) (*GitHubReadmeInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchReadme", repository, ref), f.readmePath(repository, ref), func() (*GitHubReadmeInfo, error) {
+		return f.fetchReadme(repository, ref)
+	})
+}
+
+// readmePath returns the cache path of a README
+func (f *GitHubReadmeFetcher) readmePath(repository, ref string) string {
+	return f.getCache().GetFilePath("github", "readmes", fmt.Sprintf("%s.md", cache.EntryName(normalizeGitHubRepository(repository), ref)))
+}
+
+func (f *GitHubReadmeFetcher) fetchReadme(repository, ref string) (*GitHubReadmeInfo, error) {
+	repository = normalizeGitHubRepository(repository)
+	if strings.Count(repository, "/") != 1 {
+		return nil, fmt.Errorf("repository must be in the form owner/repo, got %q", repository)
+	}
+
+	// Check cache first
+	cachedPath := f.readmePath(repository, ref)
+	info, err := f.loadReadmeFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded README of '%s' from cache\n", repository)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching README of '%s' from GitHub API...\n", repository)
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/readme", repository)
+	if ref != "" {
+		apiURL += "?ref=" + url.QueryEscape(ref)
+	}
+	req, err := newRequest(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch README: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		if ref != "" {
+			return nil, fmt.Errorf("no README found for %s at %s", repository, ref)
+		}
+		return nil, fmt.Errorf("no README found for %s", repository)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("github API returned status %d for repository %s", resp.StatusCode, repository)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	info, err = f.renderReadme(repository, ref, body)
+	if err != nil {
+		return nil, err
+	}
+
+	f.saveRawPayload(cachedPath, body)
+	if err := f.saveReadmeAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache README: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// renderReadme renders a README payload of the GitHub API
+func (f *GitHubReadmeFetcher) renderReadme(repository, ref string, payload []byte) (*GitHubReadmeInfo, error) {
+	var data struct {
+		Path     string `json:"path"`
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+		HTMLURL  string `json:"html_url"`
+	}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub API data: %w", err)
+	}
+	if data.Encoding != "base64" {
+		return nil, fmt.Errorf("unsupported README encoding %q", data.Encoding)
+	}
+	// The content is base64 wrapped at 60 columns
+	raw, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(data.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode README: %w", err)
+	}
+
+	info := &GitHubReadmeInfo{
+		Repository: repository,
+		Ref:        ref,
+		File:       data.Path,
+		HTMLURL:    data.HTMLURL,
+	}
+	info.Content = f.buildReadmeContent(info, readmeMarkdown(string(raw), data.Path, data.HTMLURL))
+	return info, nil
+}
+
+// normalizeGitHubRepository accepts "owner/repo" as well as repository URLs
+// such as "https://github.com/owner/repo.git"
+func normalizeGitHubRepository(repository string) string {
+	repository = strings.TrimSpace(repository)
+	for _, prefix := range []string{"https://", "http://", "github.com/", "www.github.com/"} {
+		repository = strings.TrimPrefix(repository, prefix)
+	}
+	repository = strings.TrimSuffix(strings.Trim(repository, "/"), ".git")
+	// Drop deeper paths such as /tree/main
+	if parts := strings.SplitN(repository, "/", 3); len(parts) == 3 {
+		repository = parts[0] + "/" + parts[1]
+	}
+	return repository
+}
+
+// readmeMarkdown converts a README to markdown by its file extension and
+// makes relative links and images absolute, against the README's location
+func readmeMarkdown(raw, file, htmlURL string) string {
+	var text string
+	switch strings.ToLower(path.Ext(file)) {
+	case ".rst", ".rest":
+		text = markdown.FromRST(raw)
+	case ".md", ".markdown", ".mdown", ".mkd":
+		text = htmlCommentPattern.ReplaceAllString(raw, "")
+		text = htmlImagePattern.ReplaceAllString(text, "")
+	default:
+		// Plain text, or a format with no converter (AsciiDoc, Org)
+		return "```\n" + strings.TrimSpace(raw) + "\n```\n"
+	}
+
+	// https://github.com/owner/repo/blob/main/docs/README.md less the file
+	// path "docs/README.md" is the repository root at the ref
+	if root, ok := strings.CutSuffix(htmlURL, file); ok && file != "" {
+		dir := root
+		if d := path.Dir(file); d != "." {
+			dir += d + "/"
+		}
+		text = relativeLinkPattern.ReplaceAllStringFunc(text, func(m string) string {
+			parts := relativeLinkPattern.FindStringSubmatch(m)
+			target := parts[3]
+			if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") {
+				return m
+			}
+			absolute := dir + strings.TrimPrefix(target, "./")
+			if strings.HasPrefix(target, "/") {
+				absolute = root + target[1:]
+			}
+			if parts[1] == "!" {
+				// Images are served from raw, not the blob page
+				absolute = strings.Replace(absolute, "/blob/", "/raw/", 1)
+			}
+			return fmt.Sprintf("%s[%s](%s%s)", parts[1], parts[2], absolute, parts[4])
+		})
+	}
+
+	return text
+}
+
+func (f *GitHubReadmeFetcher) buildReadmeContent(info *GitHubReadmeInfo, readme string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s README\n\n", info.Repository)
+
+	fmt.Fprintf(&content, "**Repository:** [%s](https://github.com/%s)\n\n", info.Repository, info.Repository)
+	if info.Ref != "" {
+		fmt.Fprintf(&content, "**Ref:** %s\n\n", info.Ref)
+	} else {
+		content.WriteString("**Ref:** default branch\n\n")
+	}
+	if info.File != "" {
+		fmt.Fprintf(&content, "**File:** %s\n\n", info.File)
+	}
+
+	readme = markdown.DemoteHeadings(strings.TrimSpace(readme), 1)
+	content.WriteString(strings.TrimSpace(truncateMarkdown(readme, maxReadmeChars, "The README is truncated; see GitHub for the rest.")))
+	content.WriteString("\n\n")
+
+	if info.HTMLURL != "" {
+		content.WriteString("## Source\n\n")
+		fmt.Fprintf(&content, "- [%s](%s)\n", info.File, info.HTMLURL)
+	}
+
+	return content.String()
+}
+
+func (f *GitHubReadmeFetcher) saveReadmeAsMarkdown(filePath string, info *GitHubReadmeInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "repository: \"%s\"\n", escapeYAML(info.Repository))
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
+	if info.Ref != "" {
+		fmt.Fprintf(&content, "ref: \"%s\"\n", escapeYAML(info.Ref))
+	}
+	fmt.Fprintf(&content, "file: \"%s\"\n", escapeYAML(info.File))
+	fmt.Fprintf(&content, "htmlUrl: \"%s\"\n", escapeYAML(info.HTMLURL))
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *GitHubReadmeFetcher) loadReadmeFromMarkdown(filePath string) (*GitHubReadmeInfo, error) {
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta struct {
+		GitHubReadmeInfo `yaml:",inline"`
+		Template         int `yaml:"template"`
+	}
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	info := meta.GitHubReadmeInfo
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if rendered, err := f.renderReadme(info.Repository, info.Ref, payload); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered README of '%s' with current templates\n", info.Repository)
+			if err := f.saveReadmeAsMarkdown(filePath, rendered); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache README: %v\n", err)
+			}
+			return rendered, nil
+		}
+	}
+
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
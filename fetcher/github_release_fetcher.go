@@ -0,0 +1,332 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// maxReleaseNotesChars bounds the notes of one release
+	maxReleaseNotesChars = 60000
+
+	// maxReleaseAssets caps the assets listed for one release
+	maxReleaseAssets = 50
+)
+
+// githubRelease is one entry of the GitHub releases API
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	PublishedAt string `json:"published_at"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	Author      struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Assets []githubReleaseAsset `json:"assets"`
+
+	version string
+}
+
+// githubReleaseAsset is a file attached to a release
+type githubReleaseAsset struct {
+	Name          string `json:"name"`
+	Size          int64  `json:"size"`
+	DownloadCount int64  `json:"download_count"`
+	URL           string `json:"browser_download_url"`
+}
+
+// GitHubReleaseInfo is one release of any GitHub repository
+type GitHubReleaseInfo struct {
+	Repository  string `yaml:"repository"`
+	Tag         string `yaml:"tag"`
+	Name        string `yaml:"name"`
+	ReleaseDate string `yaml:"releaseDate"`
+	ReleaseURL  string `yaml:"releaseURL"`
+	Prerelease  bool   `yaml:"prerelease"`
+	Content     string `yaml:"-"`
+}
+
+type GitHubReleaseFetcher struct {
+	*BaseFetcher
+}
+
+func NewGitHubReleaseFetcher(cacheDir string) *GitHubReleaseFetcher {
+	return &GitHubReleaseFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchRelease fetches a release of a repository ("owner/repo") by tag, or
+// its latest release when tag is empty or "latest". A tag given without its
+// "v" prefix is found as well.
+func (f *GitHubReleaseFetcher) FetchRelease(repository, tag string) (*GitHubReleaseInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchRelease", repository, tag), f.releasePath(repository, tag), func() (*GitHubReleaseInfo, error) {
+		return f.fetchRelease(repository, tag)
+	})
+}
+
+// releasePath returns the cache path of a release
+func (f *GitHubReleaseFetcher) releasePath(repository, tag string) string {
+	if tag == "latest" {
+		tag = ""
+	}
+	return f.getCache().GetFilePath("github", "releases", fmt.Sprintf("%s.md", cache.EntryName(normalizeGitHubRepository(repository), tag)))
+}
+
+func (f *GitHubReleaseFetcher) fetchRelease(repository, tag string) (*GitHubReleaseInfo, error) {
+	repository = normalizeGitHubRepository(repository)
+	if strings.Count(repository, "/") != 1 {
+		return nil, fmt.Errorf("repository must be in the form owner/repo, got %q", repository)
+	}
+	if tag == "latest" {
+		tag = ""
+	}
+
+	// Check cache first
+	cachedPath := f.releasePath(repository, tag)
+	info, err := f.loadReleaseFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded release of '%s' from cache\n", repository)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching release of '%s' from GitHub API...\n", repository)
+
+	var body []byte
+	if tag == "" {
+		body, err = f.getRelease(repository, "latest")
+	} else {
+		body, err = f.getRelease(repository, "tags/"+url.PathEscape(tag))
+		if body == nil && err == nil && !strings.HasPrefix(tag, "v") {
+			body, err = f.getRelease(repository, "tags/"+url.PathEscape("v"+tag))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		if tag == "" {
+			return nil, fmt.Errorf("no published release found for %s", repository)
+		}
+		return nil, fmt.Errorf("release %s not found for %s", tag, repository)
+	}
+
+	info, err = f.renderRelease(repository, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
+	if err := f.saveReleaseAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache release: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// getRelease reads one release of the GitHub API at releases/{selector}. It
+// returns nil and no error when there is no such release.
+func (f *GitHubReleaseFetcher) getRelease(repository, selector string) ([]byte, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/releases/%s", repository, selector)
+	req, err := newRequest(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("github API returned status %d for repository %s", resp.StatusCode, repository)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// parseRelease decodes one release of the GitHub releases API
+func parseRelease(payload []byte) (*githubRelease, error) {
+	var release githubRelease
+	if err := json.Unmarshal(payload, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse release data: %w", err)
+	}
+	return &release, nil
+}
+
+func releaseDate(publishedAt string) string {
+	if t, err := time.Parse(time.RFC3339, publishedAt); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return publishedAt
+}
+
+// renderRelease renders a release payload of the GitHub API
+func (f *GitHubReleaseFetcher) renderRelease(repository string, payload []byte) (*GitHubReleaseInfo, error) {
+	release, err := parseRelease(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &GitHubReleaseInfo{
+		Repository:  repository,
+		Tag:         release.TagName,
+		Name:        release.Name,
+		ReleaseDate: releaseDate(release.PublishedAt),
+		ReleaseURL:  release.HTMLURL,
+		Prerelease:  release.Prerelease,
+	}
+	info.Content = f.buildReleaseContent(info, release)
+	return info, nil
+}
+
+func (f *GitHubReleaseFetcher) buildReleaseContent(info *GitHubReleaseInfo, release *githubRelease) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s %s\n\n", info.Repository, info.Tag)
+
+	fmt.Fprintf(&content, "**Repository:** [%s](https://github.com/%s)\n\n", info.Repository, info.Repository)
+	if info.Name != "" && info.Name != info.Tag {
+		fmt.Fprintf(&content, "**Release:** %s\n\n", info.Name)
+	}
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+	if release.Author.Login != "" {
+		fmt.Fprintf(&content, "**Published by:** %s\n\n", release.Author.Login)
+	}
+	if info.Prerelease {
+		content.WriteString("**Pre-release:** yes\n\n")
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "**Release Notes:** [%s](%s)\n\n", info.Tag, info.ReleaseURL)
+	}
+
+	content.WriteString("## Release Notes\n\n")
+	notes := strings.TrimSpace(strings.ReplaceAll(release.Body, "\r\n", "\n"))
+	if notes == "" {
+		content.WriteString("This release has no notes.\n\n")
+	} else {
+		notes = markdown.DemoteHeadings(notes, 1)
+		content.WriteString(strings.TrimSpace(truncateMarkdown(notes, maxReleaseNotesChars, "The release notes are truncated; see GitHub for the rest.")))
+		content.WriteString("\n\n")
+	}
+
+	if len(release.Assets) > 0 {
+		content.WriteString("## Assets\n\n")
+		content.WriteString("| File | Size | Downloads |\n")
+		content.WriteString("|------|------|-----------|\n")
+		for i, asset := range release.Assets {
+			if i == maxReleaseAssets {
+				break
+			}
+			fmt.Fprintf(&content, "| [%s](%s) | %s | %d |\n", asset.Name, asset.URL, formatAssetSize(asset.Size), asset.DownloadCount)
+		}
+		content.WriteString("\n")
+		if len(release.Assets) > maxReleaseAssets {
+			fmt.Fprintf(&content, "Only the first %d of %d assets are listed.\n\n", maxReleaseAssets, len(release.Assets))
+		}
+	}
+
+	return content.String()
+}
+
+func formatAssetSize(size int64) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
+func (f *GitHubReleaseFetcher) saveReleaseAsMarkdown(filePath string, info *GitHubReleaseInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "repository: \"%s\"\n", escapeYAML(info.Repository))
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
+	fmt.Fprintf(&content, "tag: \"%s\"\n", escapeYAML(info.Tag))
+	if info.Name != "" {
+		fmt.Fprintf(&content, "name: \"%s\"\n", escapeYAML(info.Name))
+	}
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	fmt.Fprintf(&content, "releaseURL: \"%s\"\n", escapeYAML(info.ReleaseURL))
+	if info.Prerelease {
+		content.WriteString("prerelease: true\n")
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *GitHubReleaseFetcher) loadReleaseFromMarkdown(filePath string) (*GitHubReleaseInfo, error) {
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta struct {
+		GitHubReleaseInfo `yaml:",inline"`
+		Template          int `yaml:"template"`
+	}
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	info := meta.GitHubReleaseInfo
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if rendered, err := f.renderRelease(info.Repository, payload); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered release %s of '%s' with current templates\n", info.Tag, info.Repository)
+			if err := f.saveReleaseAsMarkdown(filePath, rendered); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache release: %v\n", err)
+			}
+			return rendered, nil
+		}
+	}
+
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -0,0 +1,403 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	defaultGitLabURL = "https://gitlab.com"
+
+	// maxGitLabTags caps the tags listed for a project
+	maxGitLabTags = 20
+)
+
+// GitLabProjectInfo is a GitLab project with its latest release and tags
+type GitLabProjectInfo struct {
+	Project       string `yaml:"project"`
+	Host          string `yaml:"host"`
+	Name          string `yaml:"name"`
+	Description   string `yaml:"description"`
+	WebURL        string `yaml:"webUrl"`
+	DefaultBranch string `yaml:"defaultBranch"`
+	LatestRelease string `yaml:"latestRelease"`
+	Content       string `yaml:"-"`
+}
+
+// gitlabPayload is the GitLab API responses a project page is rendered from
+type gitlabPayload struct {
+	Project  json.RawMessage `json:"project"`
+	Releases json.RawMessage `json:"releases"`
+	Tags     json.RawMessage `json:"tags"`
+}
+
+type gitlabProject struct {
+	Name              string   `json:"name"`
+	PathWithNamespace string   `json:"path_with_namespace"`
+	Description       string   `json:"description"`
+	WebURL            string   `json:"web_url"`
+	DefaultBranch     string   `json:"default_branch"`
+	StarCount         int      `json:"star_count"`
+	ForksCount        int      `json:"forks_count"`
+	Topics            []string `json:"topics"`
+	LastActivityAt    string   `json:"last_activity_at"`
+	HTTPURLToRepo     string   `json:"http_url_to_repo"`
+	License           *struct {
+		Name string `json:"name"`
+	} `json:"license"`
+}
+
+type gitlabRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ReleasedAt  string `json:"released_at"`
+	Links       struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+type gitlabTag struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Commit  struct {
+		ShortID       string `json:"short_id"`
+		CommittedDate string `json:"committed_date"`
+	} `json:"commit"`
+}
+
+type GitLabFetcher struct {
+	*BaseFetcher
+	// baseURL is the GitLab instance used for project paths without a host
+	baseURL string
+}
+
+func NewGitLabFetcher(cacheDir string) *GitLabFetcher {
+	f := &GitLabFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+		baseURL:     defaultGitLabURL,
+	}
+	if cfg, err := config.Load(); err == nil && cfg != nil && cfg.GitLabURL != "" {
+		f.baseURL = strings.TrimRight(cfg.GitLabURL, "/")
+	}
+	return f
+}
+
+// FetchProject fetches a GitLab project ("group/subgroup/project" or its
+// URL) with its latest release and most recent tags
+func (f *GitLabFetcher) FetchProject(project string) (*GitLabProjectInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchProject", project), f.projectPath(project), func() (*GitLabProjectInfo, error) {
+		return f.fetchProject(project)
+	})
+}
+
+// projectPath returns the cache path of the info on a project, or "" for an
+// invalid project
+func (f *GitLabFetcher) projectPath(project string) string {
+	baseURL, path, err := f.resolveProject(project)
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(baseURL, "https://"), "http://")
+	return f.getCache().GetFilePath("gitlab", "projects", fmt.Sprintf("%s.md", cache.EntryName(host, path)))
+}
+
+func (f *GitLabFetcher) fetchProject(project string) (*GitLabProjectInfo, error) {
+	baseURL, path, err := f.resolveProject(project)
+	if err != nil {
+		return nil, err
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(baseURL, "https://"), "http://")
+
+	// Check cache first
+	cachedPath := f.projectPath(project)
+	info, err := f.loadProjectFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded GitLab project '%s' from cache\n", path)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching GitLab project '%s' from %s...\n", path, host)
+
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s", baseURL, url.PathEscape(path))
+	var payload gitlabPayload
+	if payload.Project, err = f.get(apiURL + "?license=true"); err != nil {
+		return nil, err
+	}
+	if payload.Project == nil {
+		return nil, fmt.Errorf("gitlab project %s not found on %s", path, host)
+	}
+	// Projects may have releases or tags disabled; the page shows what exists
+	if payload.Releases, err = f.get(apiURL + "/releases?per_page=1"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch releases of %s: %v\n", path, err)
+	}
+	if payload.Tags, err = f.get(fmt.Sprintf("%s/repository/tags?per_page=%d", apiURL, maxGitLabTags)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch tags of %s: %v\n", path, err)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GitLab payload: %w", err)
+	}
+	info, err = f.renderProject(host, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
+	if err := f.saveProjectAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache GitLab project: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// resolveProject splits a project reference into its instance base URL and
+// project path. Full URLs name their own instance; bare paths use the
+// configured gitlab_url.
+func (f *GitLabFetcher) resolveProject(project string) (string, string, error) {
+	project = strings.TrimSpace(project)
+	baseURL := f.baseURL
+	if strings.Contains(project, "://") {
+		u, err := url.Parse(project)
+		if err != nil || u.Host == "" {
+			return "", "", fmt.Errorf("invalid GitLab project URL %q", project)
+		}
+		baseURL = u.Scheme + "://" + u.Host
+		project = u.Path
+	} else if rest, ok := strings.CutPrefix(project, "gitlab.com/"); ok {
+		baseURL, project = defaultGitLabURL, rest
+	}
+
+	project = strings.TrimSuffix(strings.Trim(project, "/"), ".git")
+	// Drop page paths such as /-/tree/main
+	if before, _, ok := strings.Cut(project, "/-/"); ok {
+		project = before
+	}
+	if !strings.Contains(project, "/") {
+		return "", "", fmt.Errorf("project must be in the form group/project, got %q", project)
+	}
+	return baseURL, project, nil
+}
+
+// get reads a GitLab API response. It returns nil and no error for 404.
+func (f *GitLabFetcher) get(apiURL string) ([]byte, error) {
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	// Private projects and self-hosted instances may need a token, which is
+	// only sent to the configured instance, never to a host named in a URL
+	if token := gitlabToken(); token != "" && strings.HasPrefix(apiURL, f.baseURL+"/") {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GitLab data: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitLab API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// renderProject renders the GitLab API responses of a project
+func (f *GitLabFetcher) renderProject(host string, body []byte) (*GitLabProjectInfo, error) {
+	var payload gitlabPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse GitLab payload: %w", err)
+	}
+
+	var project gitlabProject
+	if err := json.Unmarshal(payload.Project, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse GitLab project: %w", err)
+	}
+	var releases []gitlabRelease
+	if len(payload.Releases) > 0 {
+		if err := json.Unmarshal(payload.Releases, &releases); err != nil {
+			return nil, fmt.Errorf("failed to parse GitLab releases: %w", err)
+		}
+	}
+	var tags []gitlabTag
+	if len(payload.Tags) > 0 {
+		if err := json.Unmarshal(payload.Tags, &tags); err != nil {
+			return nil, fmt.Errorf("failed to parse GitLab tags: %w", err)
+		}
+	}
+
+	info := &GitLabProjectInfo{
+		Project:       project.PathWithNamespace,
+		Host:          host,
+		Name:          project.Name,
+		Description:   project.Description,
+		WebURL:        project.WebURL,
+		DefaultBranch: project.DefaultBranch,
+	}
+	var latest *gitlabRelease
+	if len(releases) > 0 {
+		latest = &releases[0]
+		info.LatestRelease = latest.TagName
+	}
+	info.Content = f.buildProjectContent(info, &project, latest, tags)
+	return info, nil
+}
+
+func (f *GitLabFetcher) buildProjectContent(info *GitLabProjectInfo, project *gitlabProject, latest *gitlabRelease, tags []gitlabTag) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Project)
+
+	if info.Description != "" {
+		fmt.Fprintf(&content, "**Description:** %s\n\n", strings.TrimSpace(info.Description))
+	}
+	fmt.Fprintf(&content, "**Project:** [%s](%s)\n\n", info.Project, info.WebURL)
+	if info.DefaultBranch != "" {
+		fmt.Fprintf(&content, "**Default Branch:** %s\n\n", info.DefaultBranch)
+	}
+	if project.License != nil && project.License.Name != "" {
+		fmt.Fprintf(&content, "**License:** %s\n\n", project.License.Name)
+	}
+	fmt.Fprintf(&content, "**Stars:** %d | **Forks:** %d\n\n", project.StarCount, project.ForksCount)
+	if len(project.Topics) > 0 {
+		fmt.Fprintf(&content, "**Topics:** %s\n\n", strings.Join(project.Topics, ", "))
+	}
+	if project.LastActivityAt != "" {
+		fmt.Fprintf(&content, "**Last Activity:** %s\n\n", releaseDate(project.LastActivityAt))
+	}
+
+	if project.HTTPURLToRepo != "" {
+		content.WriteString("## Clone\n\n")
+		content.WriteString("```bash\n")
+		fmt.Fprintf(&content, "git clone %s\n", project.HTTPURLToRepo)
+		content.WriteString("```\n\n")
+	}
+
+	if latest != nil {
+		fmt.Fprintf(&content, "## Latest Release: %s\n\n", latest.TagName)
+		if latest.Name != "" && latest.Name != latest.TagName {
+			fmt.Fprintf(&content, "**Release:** %s\n\n", latest.Name)
+		}
+		if date := releaseDate(latest.ReleasedAt); date != "" {
+			fmt.Fprintf(&content, "**Release Date:** %s\n\n", date)
+		}
+		notes := strings.TrimSpace(strings.ReplaceAll(latest.Description, "\r\n", "\n"))
+		if notes != "" {
+			notes = markdown.DemoteHeadings(notes, 1)
+			content.WriteString(strings.TrimSpace(truncateMarkdown(notes, maxReleaseNotesChars, "The release notes are truncated; see GitLab for the rest.")))
+			content.WriteString("\n\n")
+		}
+		if latest.Links.Self != "" {
+			fmt.Fprintf(&content, "- [Release page](%s)\n\n", latest.Links.Self)
+		}
+	}
+
+	if len(tags) > 0 {
+		content.WriteString("## Tags\n\n")
+		content.WriteString("| Tag | Commit | Date |\n")
+		content.WriteString("|-----|--------|------|\n")
+		for _, tag := range tags {
+			fmt.Fprintf(&content, "| %s | %s | %s |\n", tag.Name, tag.Commit.ShortID, releaseDate(tag.Commit.CommittedDate))
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Links\n\n")
+	fmt.Fprintf(&content, "- [Project](%s)\n", info.WebURL)
+	fmt.Fprintf(&content, "- [Releases](%s/-/releases)\n", info.WebURL)
+	fmt.Fprintf(&content, "- [Tags](%s/-/tags)\n", info.WebURL)
+
+	return content.String()
+}
+
+func (f *GitLabFetcher) saveProjectAsMarkdown(filePath string, info *GitLabProjectInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "project: \"%s\"\n", escapeYAML(info.Project))
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
+	fmt.Fprintf(&content, "host: \"%s\"\n", escapeYAML(info.Host))
+	fmt.Fprintf(&content, "name: \"%s\"\n", escapeYAML(info.Name))
+	if info.Description != "" {
+		fmt.Fprintf(&content, "description: \"%s\"\n", escapeYAML(info.Description))
+	}
+	fmt.Fprintf(&content, "webUrl: \"%s\"\n", escapeYAML(info.WebURL))
+	if info.DefaultBranch != "" {
+		fmt.Fprintf(&content, "defaultBranch: \"%s\"\n", escapeYAML(info.DefaultBranch))
+	}
+	if info.LatestRelease != "" {
+		fmt.Fprintf(&content, "latestRelease: \"%s\"\n", escapeYAML(info.LatestRelease))
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *GitLabFetcher) loadProjectFromMarkdown(filePath string) (*GitLabProjectInfo, error) {
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta struct {
+		GitLabProjectInfo `yaml:",inline"`
+		Template          int `yaml:"template"`
+	}
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	info := meta.GitLabProjectInfo
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if rendered, err := f.renderProject(info.Host, payload); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered GitLab project '%s' with current templates\n", info.Project)
+			if err := f.saveProjectAsMarkdown(filePath, rendered); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache GitLab project: %v\n", err)
+			}
+			return rendered, nil
+		}
+	}
+
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
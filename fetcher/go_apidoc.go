@@ -0,0 +1,291 @@
+package fetcher
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// maxAPIDocChars bounds the rendered API reference; packages like the cloud
+// SDKs document thousands of symbols, and the rest stays a link away
+const maxAPIDocChars = 60000
+
+// extractAPIDoc renders the documentation section of a pkg.go.dev page as
+// markdown: the package overview, constants, variables, functions, and types
+// with their methods, each with its declaration, doc text, and examples.
+// It returns "" when the page has no documentation section.
+func extractAPIDoc(doc *html.Node) string {
+	var content strings.Builder
+
+	if overview := findByClass(doc, "Documentation-overview"); overview != nil {
+		content.WriteString("## Overview\n\n")
+		renderDocBlock(&content, overview, 3)
+	}
+
+	sections := []struct {
+		class, title string
+	}{
+		{"Documentation-constants", "Constants"},
+		{"Documentation-variables", "Variables"},
+		{"Documentation-functions", "Functions"},
+		{"Documentation-types", "Types"},
+	}
+	for _, s := range sections {
+		section := findByClass(doc, s.class)
+		if section == nil {
+			continue
+		}
+		var body strings.Builder
+		renderAPISection(&body, section)
+		if strings.TrimSpace(body.String()) == "" {
+			continue
+		}
+		fmt.Fprintf(&content, "## %s\n\n", s.title)
+		content.WriteString(body.String())
+	}
+
+	return truncateAPIDoc(content.String())
+}
+
+// truncateAPIDoc cuts an API reference longer than maxAPIDocChars
+func truncateAPIDoc(text string) string {
+	return truncateMarkdown(text, maxAPIDocChars, "The API reference is truncated; see pkg.go.dev for the rest.")
+}
+
+// truncateMarkdown cuts markdown longer than limit at a heading, so no code
+// fence is left open, and ends it with note in italics
+func truncateMarkdown(text string, limit int, note string) string {
+	if len(text) > limit {
+		cut := strings.LastIndex(text[:limit], "\n### ")
+		if cut < 0 {
+			cut = strings.LastIndex(text[:limit], "\n## ")
+		}
+		if cut < 0 {
+			cut = 0
+		}
+		text = text[:cut] + "\n\n*" + note + "*\n\n"
+	}
+	return text
+}
+
+// renderAPISection renders the symbols of a constants, variables, functions,
+// or types section. Functions and types are headed by their declaration kind
+// and name; methods and constructors of a type sit one level below it.
+func renderAPISection(content *strings.Builder, section *html.Node) {
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch {
+			case hasClassToken(n, "Documentation-function"), hasClassToken(n, "Documentation-type"):
+				renderSymbol(content, n, 3)
+				return
+			case hasClassToken(n, "Documentation-typeFunc"), hasClassToken(n, "Documentation-typeMethod"):
+				renderSymbol(content, n, 4)
+				return
+			case hasClassToken(n, "Documentation-declaration"):
+				// Constants and variables are a declaration followed by its doc
+				writeCode(content, "go", getText(n))
+				return
+			case n.Data == "p":
+				writeParagraph(content, n)
+				return
+			case n.Data == "h3":
+				// The section header itself
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(section)
+}
+
+// renderSymbol renders one function, type, or method
+func renderSymbol(content *strings.Builder, n *html.Node, level int) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch {
+		case c.Data == "h4" || c.Data == "h3":
+			fmt.Fprintf(content, "%s %s\n\n", strings.Repeat("#", min(level, 6)), headerText(c))
+		case hasClassToken(c, "Documentation-typeFunc"), hasClassToken(c, "Documentation-typeMethod"):
+			renderSymbol(content, c, level+1)
+		default:
+			renderDocBlock(content, c, level+1)
+		}
+	}
+}
+
+// renderDocBlock renders declarations, doc paragraphs, headings, lists, code,
+// and examples found under n
+func renderDocBlock(content *strings.Builder, n *html.Node, headingLevel int) {
+	if n.Type != html.ElementNode {
+		return
+	}
+
+	switch {
+	case hasClassToken(n, "Documentation-declaration"):
+		writeCode(content, "go", getText(n))
+		return
+	case hasClassToken(n, "Documentation-exampleDetails"):
+		renderExample(content, n)
+		return
+	case hasClassToken(n, "Documentation-overviewHeader"), hasClassToken(n, "Documentation-sinceVersion"):
+		return
+	}
+
+	switch n.Data {
+	case "p":
+		writeParagraph(content, n)
+		return
+	case "pre":
+		// Code in doc text is often shell commands or output, not Go
+		writeCode(content, "", getText(n))
+		return
+	case "ul", "ol":
+		for li := n.FirstChild; li != nil; li = li.NextSibling {
+			if li.Type == html.ElementNode && li.Data == "li" {
+				if text := collapseSpace(getText(li)); text != "" {
+					fmt.Fprintf(content, "- %s\n", text)
+				}
+			}
+		}
+		content.WriteString("\n")
+		return
+	case "h3", "h4", "h5":
+		if text := headerText(n); text != "" {
+			fmt.Fprintf(content, "%s %s\n\n", strings.Repeat("#", min(headingLevel, 6)), text)
+		}
+		return
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		renderDocBlock(content, c, headingLevel)
+	}
+}
+
+// renderExample renders an example's name, code, and expected output.
+// Playable examples keep their code in a textarea, others in a pre.
+func renderExample(content *strings.Builder, n *html.Node) {
+	name := "Example"
+	if summary := findElement(n, "summary"); summary != nil {
+		name = headerText(summary)
+	}
+
+	var code, output string
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.ElementNode {
+			switch {
+			case hasClassToken(c, "Documentation-exampleCode") && code == "":
+				code = getText(c)
+				return
+			case hasClassToken(c, "Documentation-exampleOutput"):
+				output = getText(c)
+				return
+			}
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	if strings.TrimSpace(code) == "" {
+		return
+	}
+	fmt.Fprintf(content, "**%s:**\n\n", name)
+	writeCode(content, "go", code)
+	if strings.TrimSpace(output) != "" {
+		fmt.Fprintf(content, "Output:\n\n```\n%s\n```\n\n", strings.Trim(output, "\n"))
+	}
+}
+
+// headerText returns a heading without its anchor link, noting the Go
+// version that added the symbol when the page gives one
+func headerText(n *html.Node) string {
+	var since string
+	var text strings.Builder
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.ElementNode && hasClassToken(c, "Documentation-sinceVersion") {
+			since = collapseSpace(getText(c))
+			return
+		}
+		if c.Type == html.TextNode {
+			text.WriteString(c.Data)
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	title := collapseSpace(strings.ReplaceAll(text.String(), "¶", ""))
+	if since != "" {
+		title += " (" + since + ")"
+	}
+	return title
+}
+
+func writeParagraph(content *strings.Builder, n *html.Node) {
+	if text := collapseSpace(getText(n)); text != "" {
+		fmt.Fprintf(content, "%s\n\n", text)
+	}
+}
+
+func writeCode(content *strings.Builder, lang, code string) {
+	code = strings.Trim(code, "\n")
+	if strings.TrimSpace(code) == "" {
+		return
+	}
+	fmt.Fprintf(content, "```%s\n%s\n```\n\n", lang, code)
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// findByClass returns the first element carrying class, depth first
+func findByClass(n *html.Node, class string) *html.Node {
+	if n.Type == html.ElementNode && hasClassToken(n, class) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findByClass(c, class); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func findElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// hasClassToken reports whether n has exactly class among its classes;
+// unlike hasClass, "Documentation-type" does not match
+// "Documentation-typeHeader"
+func hasClassToken(n *html.Node, class string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "class" {
+			for _, c := range strings.Fields(attr.Val) {
+				if c == class {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,444 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+const (
+	// maxGoDepsDepth bounds how many levels of requirements are expanded
+	maxGoDepsDepth = 3
+
+	// maxGoDepsModules bounds how many go.mod files one report fetches;
+	// deeper requirements beyond it are listed but not expanded
+	maxGoDepsModules = 100
+
+	// goDepsWorkers is how many go.mod files are fetched at once
+	goDepsWorkers = 8
+)
+
+// GoDepsFetcher reports the requirements of Go modules from the go.mod files
+// served by the module proxy
+type GoDepsFetcher struct {
+	*BaseFetcher
+}
+
+// GoDepsReport is the requirement tree of one module version
+type GoDepsReport struct {
+	Module   string `yaml:"module"`
+	Version  string `yaml:"version"`
+	Depth    int    `yaml:"depth"`
+	Direct   int    `yaml:"direct"`
+	Indirect int    `yaml:"indirect"`
+	Content  string `yaml:"-"`
+}
+
+// GoModFile is the part of a go.mod file that describes requirements
+type GoModFile struct {
+	Module    string
+	Go        string
+	Toolchain string
+	Require   []GoModRequirement
+	// Replace, Exclude, and Retract keep the directives as written,
+	// e.g. "golang.org/x/net => ../net"
+	Replace []string
+	Exclude []string
+	Retract []string
+}
+
+// GoModRequirement is one require directive
+type GoModRequirement struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+// NewGoDepsFetcher creates a new Go module dependency fetcher
+func NewGoDepsFetcher(cacheDir string) *GoDepsFetcher {
+	return &GoDepsFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchModuleDeps fetches the requirements of a module version, the latest
+// when version is empty. Direct requirements are expanded with their own
+// requirements down to depth levels (1 to maxGoDepsDepth).
+func (f *GoDepsFetcher) FetchModuleDeps(module, version string, depth int) (*GoDepsReport, error) {
+	module = strings.TrimSpace(module)
+	if version != "" && !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	depth = max(1, min(depth, maxGoDepsDepth))
+
+	return shareFetch(f.flights, flightKey("FetchModuleDeps", module, version, strconv.Itoa(depth)), f.reportPath(module, version, depth), func() (*GoDepsReport, error) {
+		return f.fetchModuleDeps(module, version, depth)
+	})
+}
+
+// reportPath returns the cache path of the report on a module version
+func (f *GoDepsFetcher) reportPath(module, version string, depth int) string {
+	return f.getCache().GetFilePath("go", "deps", fmt.Sprintf("%s.md", cache.EntryName(module, version, strconv.Itoa(depth))))
+}
+
+func (f *GoDepsFetcher) fetchModuleDeps(module, version string, depth int) (*GoDepsReport, error) {
+	// Check cache first
+	cachedPath := f.reportPath(module, version, depth)
+	report, err := f.loadReportFromMarkdown(cachedPath)
+	if err == nil && report != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Go dependencies for '%s' from cache\n", module)
+		return report, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Go dependencies for '%s' from the module proxy...\n", module)
+
+	if version == "" {
+		version, err = f.latestVersion(module)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	root, err := f.fetchGoMod(module, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expand level by level; each module version is fetched once
+	mods := map[string]*GoModFile{module + "@" + version: root}
+	level := []*GoModFile{root}
+	for d := 1; d < depth; d++ {
+		var next []GoModRequirement
+		for _, mod := range level {
+			for _, req := range mod.Require {
+				key := req.Path + "@" + req.Version
+				if _, ok := mods[key]; ok || req.Indirect || len(mods) >= maxGoDepsModules {
+					continue
+				}
+				mods[key] = nil
+				next = append(next, req)
+			}
+		}
+
+		fetched := make([]*GoModFile, len(next))
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, goDepsWorkers)
+		for i, req := range next {
+			wg.Add(1)
+			go func(i int, req GoModRequirement) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				mod, err := f.fetchGoMod(req.Path, req.Version)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to fetch go.mod of %s@%s: %v\n", req.Path, req.Version, err)
+					return
+				}
+				fetched[i] = mod
+			}(i, req)
+		}
+		wg.Wait()
+
+		level = nil
+		for i, req := range next {
+			mods[req.Path+"@"+req.Version] = fetched[i]
+			if fetched[i] != nil {
+				level = append(level, fetched[i])
+			}
+		}
+	}
+
+	report = &GoDepsReport{Module: module, Version: version, Depth: depth}
+	for _, req := range root.Require {
+		if req.Indirect {
+			report.Indirect++
+		} else {
+			report.Direct++
+		}
+	}
+	report.Content = f.buildReportContent(report, root, mods)
+
+	if err := f.saveReportAsMarkdown(cachedPath, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache Go dependencies for %s: %v\n", module, err)
+	}
+
+	return report, nil
+}
+
+func (f *GoDepsFetcher) latestVersion(module string) (string, error) {
+	body, err := f.getProxy(fmt.Sprintf("%s/%s/@latest", goProxyBaseURL, escapeModulePath(module)))
+	if err != nil {
+		return "", err
+	}
+
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("failed to parse module info: %w", err)
+	}
+	if info.Version == "" {
+		return "", fmt.Errorf("no version found for %s", module)
+	}
+	return info.Version, nil
+}
+
+func (f *GoDepsFetcher) fetchGoMod(module, version string) (*GoModFile, error) {
+	body, err := f.getProxy(fmt.Sprintf("%s/%s/@v/%s.mod", goProxyBaseURL, escapeModulePath(module), escapeModulePath(version)))
+	if err != nil {
+		return nil, err
+	}
+
+	mod := parseGoModFile(string(body))
+	if mod.Module == "" {
+		mod.Module = module
+	}
+	return mod, nil
+}
+
+func (f *GoDepsFetcher) getProxy(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query module proxy: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("module or version not found on the module proxy")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("module proxy returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// parseGoModFile reads the module, go, toolchain, require, replace, exclude,
+// and retract directives of a go.mod file, in both their single-line and
+// block forms. Unlike the manifest parser it keeps indirect requirements.
+func parseGoModFile(data string) *GoModFile {
+	mod := &GoModFile{}
+	block := ""
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		indirect := false
+		if i := strings.Index(line, "//"); i >= 0 {
+			indirect = strings.HasPrefix(strings.TrimSpace(line[i+2:]), "indirect")
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+
+		if block != "" {
+			if line == ")" {
+				block = ""
+				continue
+			}
+			mod.addDirective(block, line, indirect)
+			continue
+		}
+
+		verb, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+		if rest == "(" {
+			block = verb
+			continue
+		}
+		mod.addDirective(verb, rest, indirect)
+	}
+
+	return mod
+}
+
+func (mod *GoModFile) addDirective(verb, args string, indirect bool) {
+	switch verb {
+	case "module":
+		mod.Module = strings.Trim(args, `"`)
+	case "go":
+		mod.Go = args
+	case "toolchain":
+		mod.Toolchain = args
+	case "require":
+		fields := strings.Fields(args)
+		if len(fields) >= 2 {
+			mod.Require = append(mod.Require, GoModRequirement{
+				Path:     strings.Trim(fields[0], `"`),
+				Version:  fields[1],
+				Indirect: indirect,
+			})
+		}
+	case "replace":
+		mod.Replace = append(mod.Replace, strings.Join(strings.Fields(args), " "))
+	case "exclude":
+		mod.Exclude = append(mod.Exclude, strings.Join(strings.Fields(args), " "))
+	case "retract":
+		mod.Retract = append(mod.Retract, strings.Join(strings.Fields(args), " "))
+	}
+}
+
+func (f *GoDepsFetcher) buildReportContent(report *GoDepsReport, root *GoModFile, mods map[string]*GoModFile) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Go Module Dependencies: %s@%s\n\n", report.Module, report.Version)
+
+	fmt.Fprintf(&content, "**Module:** %s\n\n", report.Module)
+	fmt.Fprintf(&content, "**Version:** %s\n\n", report.Version)
+	if root.Go != "" {
+		fmt.Fprintf(&content, "**Go:** %s\n\n", root.Go)
+	}
+	if root.Toolchain != "" {
+		fmt.Fprintf(&content, "**Toolchain:** %s\n\n", root.Toolchain)
+	}
+	fmt.Fprintf(&content, "**Requirements:** %d direct, %d indirect\n\n", report.Direct, report.Indirect)
+
+	content.WriteString("## Direct Requirements\n\n")
+	if report.Direct == 0 {
+		content.WriteString("None.\n\n")
+	} else {
+		if report.Depth > 1 {
+			fmt.Fprintf(&content, "Each requirement lists its own direct requirements, %d levels deep.\n\n", report.Depth)
+		}
+		expanded := make(map[string]bool)
+		for _, req := range root.Require {
+			if !req.Indirect {
+				writeGoDepsTree(&content, req, mods, expanded, 0, report.Depth)
+			}
+		}
+		content.WriteString("\n")
+	}
+
+	if report.Indirect > 0 {
+		content.WriteString("## Indirect Requirements\n\n")
+		for _, req := range root.Require {
+			if req.Indirect {
+				fmt.Fprintf(&content, "- %s\n", goDepsLink(req))
+			}
+		}
+		content.WriteString("\n")
+	}
+
+	directives := []struct {
+		title string
+		lines []string
+	}{
+		{"Replacements", root.Replace},
+		{"Exclusions", root.Exclude},
+		{"Retractions", root.Retract},
+	}
+	for _, d := range directives {
+		if len(d.lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&content, "## %s\n\n", d.title)
+		for _, line := range d.lines {
+			fmt.Fprintf(&content, "- `%s`\n", line)
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("Fetch the documentation of a requirement with `open-context_get_go_info` (`type: library`, `importPath`, `version`), or over REST with `GET /api/v1/go/{module}@{version}`.\n\n")
+	fmt.Fprintf(&content, "- [pkg.go.dev/%s@%s](https://pkg.go.dev/%s@%s)\n", report.Module, report.Version, report.Module, report.Version)
+	fmt.Fprintf(&content, "- [go.mod](%s/%s/@v/%s.mod)\n", goProxyBaseURL, escapeModulePath(report.Module), escapeModulePath(report.Version))
+
+	return content.String()
+}
+
+// writeGoDepsTree writes a requirement and, below depth, its own direct
+// requirements. A module expanded earlier in the tree is not expanded again.
+func writeGoDepsTree(content *strings.Builder, req GoModRequirement, mods map[string]*GoModFile, expanded map[string]bool, level, depth int) {
+	indent := strings.Repeat("  ", level)
+	key := req.Path + "@" + req.Version
+	mod := mods[key]
+
+	switch {
+	case level+1 >= depth:
+		fmt.Fprintf(content, "%s- %s\n", indent, goDepsLink(req))
+		return
+	case expanded[key]:
+		fmt.Fprintf(content, "%s- %s (expanded above)\n", indent, goDepsLink(req))
+		return
+	case mod == nil:
+		fmt.Fprintf(content, "%s- %s (not expanded)\n", indent, goDepsLink(req))
+		return
+	}
+
+	fmt.Fprintf(content, "%s- %s\n", indent, goDepsLink(req))
+	expanded[key] = true
+	for _, child := range mod.Require {
+		if !child.Indirect {
+			writeGoDepsTree(content, child, mods, expanded, level+1, depth)
+		}
+	}
+}
+
+func goDepsLink(req GoModRequirement) string {
+	return fmt.Sprintf("[%s](https://pkg.go.dev/%s@%s) %s", req.Path, req.Path, req.Version, req.Version)
+}
+
+func (f *GoDepsFetcher) saveReportAsMarkdown(filePath string, report *GoDepsReport) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "module: \"%s\"\n", escapeYAML(report.Module))
+	fmt.Fprintf(&content, "version: \"%s\"\n", escapeYAML(report.Version))
+	fmt.Fprintf(&content, "depth: %d\n", report.Depth)
+	fmt.Fprintf(&content, "direct: %d\n", report.Direct)
+	fmt.Fprintf(&content, "indirect: %d\n", report.Indirect)
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(report.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *GoDepsFetcher) loadReportFromMarkdown(filePath string) (*GoDepsReport, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var report GoDepsReport
+	if err := yaml.Unmarshal([]byte(parts[1]), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	report.Content = strings.TrimSpace(parts[2])
+	return &report, nil
+}
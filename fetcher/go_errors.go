@@ -0,0 +1,288 @@
+package fetcher
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	// goErrorCodesURL is the source of the type checker's error codes,
+	// each documented with when it occurs and an example
+	goErrorCodesURL   = "https://raw.githubusercontent.com/golang/go/master/src/internal/types/errors/codes.go"
+	goErrorCodesDocs  = "https://pkg.go.dev/internal/types/errors"
+	goVetAnalyzersURL = "https://raw.githubusercontent.com/golang/tools/master/go/analysis/passes"
+	goVetAnalyzerDocs = "https://pkg.go.dev/golang.org/x/tools/go/analysis/passes"
+)
+
+// goErrorCodeNamePattern matches code names such as UnusedVar, which
+// gopls links to in its diagnostics
+var goErrorCodeNamePattern = regexp.MustCompile(`\b[A-Z][a-z]+(?:[A-Z][a-z]*)+\b`)
+
+// goErrorPatterns maps the wording of common compiler errors to the type
+// checker error code that explains them, most specific first
+var goErrorPatterns = []struct {
+	pattern *regexp.Regexp
+	code    string
+}{
+	{regexp.MustCompile(`declared and not used|declared but not used`), "UnusedVar"},
+	{regexp.MustCompile(`imported and not used`), "UnusedImport"},
+	{regexp.MustCompile(`label \w+ defined and not used`), "UnusedLabel"},
+	{regexp.MustCompile(`undefined: \w+\.\w+`), "UndeclaredImportedName"},
+	{regexp.MustCompile(`undefined: `), "UndeclaredName"},
+	{regexp.MustCompile(`has no field or method`), "MissingFieldOrMethod"},
+	{regexp.MustCompile(`unknown field .* in struct literal`), "MissingLitField"},
+	{regexp.MustCompile(`too (?:few|many) values in struct literal`), "InvalidStructLit"},
+	{regexp.MustCompile(`mixture of field:value and value elements`), "MixedStructLit"},
+	{regexp.MustCompile(`missing return`), "MissingReturn"},
+	{regexp.MustCompile(`(?:too many|not enough) arguments in call`), "WrongArgCount"},
+	{regexp.MustCompile(`(?:too many|not enough) return values`), "WrongResultCount"},
+	{regexp.MustCompile(`assignment mismatch`), "WrongAssignCount"},
+	{regexp.MustCompile(`no new variables on left side of :=`), "NoNewVar"},
+	{regexp.MustCompile(`does not satisfy`), "InvalidTypeArg"},
+	{regexp.MustCompile(`does not implement`), "InvalidIfaceAssign"},
+	{regexp.MustCompile(`cannot use .* as .* value in`), "IncompatibleAssign"},
+	{regexp.MustCompile(`mismatched types`), "MismatchedTypes"},
+	{regexp.MustCompile(`operator .* not defined on`), "UndefinedOp"},
+	{regexp.MustCompile(`cannot convert`), "InvalidConversion"},
+	{regexp.MustCompile(`cannot assign to`), "UnassignableOperand"},
+	{regexp.MustCompile(`cannot call non-function`), "InvalidCall"},
+	{regexp.MustCompile(`cannot range over`), "InvalidRangeExpr"},
+	{regexp.MustCompile(`cannot infer`), "CannotInferTypeArgs"},
+	{regexp.MustCompile(`(?:not enough|too many) type arguments|got \d+ type arguments`), "WrongTypeArgCount"},
+	{regexp.MustCompile(`redeclared in this block|already declared`), "DuplicateDecl"},
+	{regexp.MustCompile(`invalid recursive type`), "InvalidDeclCycle"},
+	{regexp.MustCompile(`is not a type`), "NotAType"},
+	{regexp.MustCompile(`break is not in a loop`), "MisplacedBreak"},
+	{regexp.MustCompile(`continue is not in a loop`), "MisplacedContinue"},
+	{regexp.MustCompile(`is not used`), "UnusedExpr"},
+}
+
+// goVetAnalyzer is a go vet analyzer and the wording of its reports
+type goVetAnalyzer struct {
+	name string
+	// dir is the analyzer's package under go/analysis/passes
+	dir     string
+	pattern *regexp.Regexp
+	summary string
+}
+
+var goVetAnalyzers = []goVetAnalyzer{
+	{"appends", "appends", regexp.MustCompile(`append with no values`), "check for missing values after append"},
+	{"assign", "assign", regexp.MustCompile(`self-assignment of`), "check for useless assignments"},
+	{"atomic", "atomic", regexp.MustCompile(`direct assignment to atomic value`), "check for common mistakes using the sync/atomic package"},
+	{"bools", "bools", regexp.MustCompile(`(?:redundant|suspect) (?:and|or):`), "check for common mistakes involving boolean operators"},
+	{"composites", "composite", regexp.MustCompile(`composite literal uses unkeyed fields`), "check for unkeyed composite literals"},
+	{"copylocks", "copylock", regexp.MustCompile(`lock (?:by )?value|copies lock`), "check for locks erroneously passed by value"},
+	{"defers", "defers", regexp.MustCompile(`call to time\.Since is not deferred`), "report common mistakes in defer statements"},
+	{"errorsas", "errorsas", regexp.MustCompile(`second argument to errors\.As`), "report passing non-pointer or non-error values to errors.As"},
+	{"httpresponse", "httpresponse", regexp.MustCompile(`using \w+ before checking for errors`), "check for mistakes using HTTP responses"},
+	{"ifaceassert", "ifaceassert", regexp.MustCompile(`impossible type assertion`), "detect impossible interface-to-interface type assertions"},
+	{"loopclosure", "loopclosure", regexp.MustCompile(`loop variable \w+ captured by func literal`), "check references to loop variables from within nested functions"},
+	{"lostcancel", "lostcancel", regexp.MustCompile(`the cancel function`), "check cancel func returned by context.WithCancel is called"},
+	{"nilfunc", "nilfunc", regexp.MustCompile(`comparison of function \w+ (?:==|!=) nil`), "check for useless comparisons between functions and nil"},
+	{"printf", "printf", regexp.MustCompile(`formatting directive|format %|call needs \d+ args?|non-constant format string`), "check consistency of Printf format strings and arguments"},
+	{"shift", "shift", regexp.MustCompile(`too small for shift of`), "check for shifts that equal or exceed the width of the integer"},
+	{"sigchanyzer", "sigchanyzer", regexp.MustCompile(`misuse of unbuffered os\.Signal channel`), "check for unbuffered channel of os.Signal"},
+	{"slog", "slog", regexp.MustCompile(`should be a string or a slog\.Attr`), "check for invalid structured logging calls"},
+	{"stdmethods", "stdmethods", regexp.MustCompile(`method \w+\(.*\) should have signature`), "check signature of methods of well-known interfaces"},
+	{"stringintconv", "stringintconv", regexp.MustCompile(`conversion from \w+ to string yields a string of one rune`), "check for string(int) conversions"},
+	{"structtag", "structtag", regexp.MustCompile(`struct field tag|repeats \w+ tag`), "check that struct field tags conform to reflect.StructTag.Get"},
+	{"testinggoroutine", "testinggoroutine", regexp.MustCompile(`from a non-test goroutine`), "report calls to (*testing.T).Fatal from goroutines started by a test"},
+	{"tests", "tests", regexp.MustCompile(`has malformed (?:name|example suffix)`), "check for common mistaken usages of tests and examples"},
+	{"timeformat", "timeformat", regexp.MustCompile(`2006-02-01`), "check for calls of (time.Time).Format or time.Parse with 2006-02-01"},
+	{"unmarshal", "unmarshal", regexp.MustCompile(`passes non-pointer`), "report passing non-pointer or non-interface values to unmarshal"},
+	{"unreachable", "unreachable", regexp.MustCompile(`unreachable code`), "check for unreachable code"},
+	{"unsafeptr", "unsafeptr", regexp.MustCompile(`possible misuse of unsafe\.Pointer`), "check for invalid conversions of uintptr to unsafe.Pointer"},
+	{"unusedresult", "unusedresult", regexp.MustCompile(`result of .* call not used`), "check for unused results of calls to some functions"},
+}
+
+// goErrorCode is an error code of the type checker and its doc comment
+type goErrorCode struct {
+	Name string `json:"name"`
+	Doc  string `json:"doc"`
+}
+
+// matchGoErrorPatterns returns the error codes whose wording the message
+// matches, most specific first
+func matchGoErrorPatterns(message string) []string {
+	var codes []string
+	for _, p := range goErrorPatterns {
+		if p.pattern.MatchString(message) {
+			codes = append(codes, p.code)
+		}
+	}
+	return codes
+}
+
+// matchGoVetAnalyzer finds the analyzer named by a message ("copylocks" or
+// "copylocks: ...") or whose reports it reads like
+func matchGoVetAnalyzer(message string) *goVetAnalyzer {
+	name := strings.ToLower(strings.TrimSpace(message))
+	name, _, _ = strings.Cut(name, ":")
+	for i, a := range goVetAnalyzers {
+		if name == a.name {
+			return &goVetAnalyzers[i]
+		}
+	}
+	for i, a := range goVetAnalyzers {
+		if a.pattern.MatchString(message) {
+			return &goVetAnalyzers[i]
+		}
+	}
+	return nil
+}
+
+func (f *ErrorFetcher) explainGoError(message string) (*ErrorExplanation, error) {
+	codes, err := f.fetchGoErrorCodes()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]goErrorCode, len(codes))
+	for _, c := range codes {
+		byName[c.Name] = c
+	}
+
+	// A code name given outright wins over the wording
+	candidates := goErrorCodeNamePattern.FindAllString(message, -1)
+	candidates = append(candidates, matchGoErrorPatterns(message)...)
+	for _, name := range candidates {
+		code, ok := byName[name]
+		if !ok {
+			continue
+		}
+
+		var content strings.Builder
+		fmt.Fprintf(&content, "# Go compiler error: %s\n\n", code.Name)
+		if !strings.EqualFold(strings.TrimSpace(message), code.Name) {
+			fmt.Fprintf(&content, "**Message:** `%s`\n\n", strings.TrimSpace(message))
+		}
+		content.WriteString(goDocMarkdown(code.Doc))
+		content.WriteString("\n")
+		fmt.Fprintf(&content, "**Reference:** [internal/types/errors.%s](%s#%s)\n", code.Name, goErrorCodesDocs, code.Name)
+
+		return &ErrorExplanation{Tool: "go", Code: code.Name, Content: content.String()}, nil
+	}
+
+	return nil, fmt.Errorf("no Go compiler error code matches %q; pass the message as the compiler printed it, or a code name such as UnusedVar", message)
+}
+
+// fetchGoErrorCodes reads the error codes and their doc comments from the
+// type checker's source
+func (f *ErrorFetcher) fetchGoErrorCodes() ([]goErrorCode, error) {
+	indexPath := f.getCache().GetFilePath("go", "errors", "codes.json")
+
+	var codes []goErrorCode
+	if ok, err := f.getCache().Load(indexPath, &codes); err == nil && ok && len(codes) > 0 {
+		return codes, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Go type checker error codes...\n")
+	body, status, err := f.get(goErrorCodesURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Go error codes: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch Go error codes: status %d", status)
+	}
+
+	codes, err = parseGoErrorCodes(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.getCache().Save(indexPath, codes); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache Go error codes: %v\n", err)
+	}
+	return codes, nil
+}
+
+// parseGoErrorCodes reads the documented constants of codes.go
+func parseGoErrorCodes(src []byte) ([]goErrorCode, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "codes.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Go error codes: %w", err)
+	}
+
+	var codes []goErrorCode
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			if value.Doc == nil || len(value.Names) == 0 || value.Names[0].Name == "_" {
+				continue
+			}
+			codes = append(codes, goErrorCode{Name: value.Names[0].Name, Doc: value.Doc.Text()})
+		}
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("no error codes found in codes.go")
+	}
+	return codes, nil
+}
+
+func (f *ErrorFetcher) explainGoVetError(message string) (*ErrorExplanation, error) {
+	analyzer := matchGoVetAnalyzer(message)
+	if analyzer == nil {
+		return nil, fmt.Errorf("no go vet analyzer matches %q; pass the message as go vet printed it, or an analyzer name such as copylocks", message)
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# go vet: %s\n\n", analyzer.name)
+	if !strings.EqualFold(strings.TrimSpace(message), analyzer.name) {
+		fmt.Fprintf(&content, "**Message:** `%s`\n\n", strings.TrimSpace(message))
+	}
+
+	// The analyzer's package doc is its full explanation; the summary
+	// stands in when it cannot be fetched
+	cachedPath := f.getCache().GetFilePath("go", "vet", analyzer.dir+".go")
+	src, found, err := f.fetchCached(cachedPath, fmt.Sprintf("%s/%s/doc.go", goVetAnalyzersURL, analyzer.dir))
+	doc := ""
+	if err == nil && found {
+		if file, err := parser.ParseFile(token.NewFileSet(), "doc.go", src, parser.ParseComments|parser.PackageClauseOnly); err == nil && file.Doc != nil {
+			doc = file.Doc.Text()
+		}
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch the %s analyzer docs: %v\n", analyzer.name, err)
+	}
+	if doc != "" {
+		content.WriteString(goDocMarkdown(doc))
+		content.WriteString("\n")
+	} else {
+		fmt.Fprintf(&content, "%s: %s.\n\n", analyzer.name, analyzer.summary)
+	}
+
+	fmt.Fprintf(&content, "**Reference:** [%s](%s/%s)\n", analyzer.dir, goVetAnalyzerDocs, analyzer.dir)
+
+	return &ErrorExplanation{Tool: "vet", Code: analyzer.name, Content: content.String()}, nil
+}
+
+// goDocMarkdown renders a doc comment as markdown, with its examples in
+// fenced Go code blocks rather than indented ones
+func goDocMarkdown(text string) string {
+	var parser comment.Parser
+	printer := comment.Printer{
+		HeadingLevel: 2,
+		HeadingID:    func(*comment.Heading) string { return "" },
+	}
+
+	var content strings.Builder
+	for _, block := range parser.Parse(text).Content {
+		if code, ok := block.(*comment.Code); ok {
+			writeCode(&content, "go", code.Text)
+			continue
+		}
+		content.Write(printer.Markdown(&comment.Doc{Content: []comment.Block{block}}))
+		content.WriteString("\n")
+	}
+	return strings.TrimSpace(content.String()) + "\n"
+}
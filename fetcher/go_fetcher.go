@@ -12,6 +12,9 @@ import (
 
 	"golang.org/x/net/html"
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/config"
 )
 
 const (
@@ -28,6 +31,8 @@ type PackageDoc struct {
 	Synopsis    string   `json:"synopsis"`
 	Description string   `json:"description"`
 	Examples    []string `json:"examples,omitempty"`
+	// Degraded lists the content checks the pkg.go.dev page failed
+	Degraded []string `json:"degraded,omitempty"`
 }
 
 type GoVersionInfo struct {
@@ -35,6 +40,8 @@ type GoVersionInfo struct {
 	ReleaseDate string `json:"releaseDate"`
 	ReleaseURL  string `json:"releaseURL"`
 	Content     string `json:"content"`
+	// Degraded lists the content checks the release notes failed
+	Degraded []string `json:"degraded,omitempty"`
 }
 
 type LibraryInfo struct {
@@ -44,19 +51,27 @@ type LibraryInfo struct {
 	Description string `json:"description"`
 	Repository  string `json:"repository,omitempty"`
 	License     string `json:"license,omitempty"`
+	// Degraded lists the content checks the pkg.go.dev page failed
+	Degraded []string `json:"degraded,omitempty"`
 }
 
 type GoFetcher struct {
 	*BaseFetcher
 	cacheDir    string
 	packageList []string
+	// moduleZipDocs renders library docs from module zips instead of pkg.go.dev
+	moduleZipDocs bool
 }
 
 func NewGoFetcher(cacheDir string) *GoFetcher {
-	return &GoFetcher{
+	f := &GoFetcher{
 		BaseFetcher: NewBaseFetcher(cacheDir),
 		cacheDir:    cacheDir,
 	}
+	if cfg, err := config.Load(); err == nil && cfg != nil {
+		f.moduleZipDocs = cfg.GoDocsSource == "module_zip"
+	}
+	return f
 }
 
 // FetchStdLib fetches documentation for all Go standard library packages
@@ -103,7 +118,7 @@ func (f *GoFetcher) FetchStdLib() error {
 		}
 
 		// Convert package name to safe filename
-		filename := strings.ReplaceAll(pkg, "/", "_") + ".json"
+		filename := cache.EntryName(pkg) + ".json"
 		outputPath := filepath.Join(outputDir, filename)
 
 		// Convert to topic format
@@ -226,8 +241,13 @@ func (f *GoFetcher) fetchPackageDoc(pkgPath string) (*PackageDoc, error) {
 	}
 
 	// Extract documentation
+	apiDoc := extractAPIDoc(doc)
 	pkgDoc.Synopsis = f.extractSynopsis(doc)
-	pkgDoc.Description = f.extractDescription(doc, pkgPath)
+	pkgDoc.Description = f.extractDescription(doc, pkgPath, apiDoc)
+	pkgDoc.Degraded = checkShape("go", url,
+		shapeCheck{"synopsis", pkgDoc.Synopsis != ""},
+		shapeCheck{"api-doc", hasMarkdownHeading(apiDoc)},
+	)
 
 	return pkgDoc, nil
 }
@@ -268,8 +288,9 @@ func (f *GoFetcher) extractSynopsis(doc *html.Node) string {
 	return synopsis
 }
 
-// extractDescription extracts the package description and creates markdown content
-func (f *GoFetcher) extractDescription(doc *html.Node, pkgPath string) string {
+// extractDescription extracts the package description and creates markdown
+// content around apiDoc, the rendered API reference
+func (f *GoFetcher) extractDescription(doc *html.Node, pkgPath, apiDoc string) string {
 	synopsis := f.extractSynopsis(doc)
 
 	// Build markdown documentation
@@ -282,20 +303,28 @@ func (f *GoFetcher) extractDescription(doc *html.Node, pkgPath string) string {
 		fmt.Fprintf(&content, "%s\n\n", synopsis)
 	}
 
-	content.WriteString("## Overview\n\n")
-	fmt.Fprintf(&content, "The `%s` package provides functionality as documented at [pkg.go.dev/%s](%s/%s).\n\n",
-		filepath.Base(pkgPath), pkgPath, pkgGoDevBaseURL, pkgPath)
-
 	content.WriteString("## Import\n\n")
 	content.WriteString("```go\n")
 	fmt.Fprintf(&content, "import \"%s\"\n", pkgPath)
 	content.WriteString("```\n\n")
 
+	if apiDoc != "" {
+		content.WriteString(apiDoc)
+	} else {
+		content.WriteString("## Overview\n\n")
+		fmt.Fprintf(&content, "The `%s` package provides functionality as documented at [pkg.go.dev/%s](%s/%s).\n\n",
+			filepath.Base(pkgPath), pkgPath, pkgGoDevBaseURL, pkgPath)
+	}
+
 	content.WriteString("## Documentation\n\n")
 	content.WriteString("For detailed documentation, examples, and API reference, visit:\n\n")
 	fmt.Fprintf(&content, "- [pkg.go.dev/%s](%s/%s)\n", pkgPath, pkgGoDevBaseURL, pkgPath)
 	fmt.Fprintf(&content, "- [Go Standard Library Documentation](https://golang.org/pkg/%s/)\n\n", pkgPath)
 
+	if apiDoc != "" {
+		return content.String()
+	}
+
 	// Add common usage note
 	content.WriteString("## Usage\n\n")
 	content.WriteString("This package is part of the Go standard library. ")
@@ -320,13 +349,17 @@ func (f *GoFetcher) packageDocToTopic(doc *PackageDoc) map[string]interface{} {
 	parts := strings.Split(doc.ImportPath, "/")
 	keywords = append(keywords, parts...)
 
-	return map[string]interface{}{
+	topic := map[string]interface{}{
 		"id":          strings.ReplaceAll(doc.ImportPath, "/", "_"),
 		"title":       fmt.Sprintf("Go Package: %s", doc.ImportPath),
 		"description": doc.Synopsis,
 		"keywords":    uniqueStrings(keywords),
 		"content":     doc.Description,
 	}
+	if len(doc.Degraded) > 0 {
+		topic["degraded"] = doc.Degraded
+	}
+	return topic
 }
 
 // Helper functions
@@ -380,8 +413,19 @@ func getText(n *html.Node) string {
 
 // FetchGoVersion fetches and caches information about a specific Go version
 func (f *GoFetcher) FetchGoVersion(version string) (*GoVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchGoVersion", version), f.versionPath(version), func() (*GoVersionInfo, error) {
+		return f.fetchGoVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Go version
+func (f *GoFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("go", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *GoFetcher) fetchGoVersion(version string) (*GoVersionInfo, error) {
 	// Build cache path
-	cachedPath := f.getCache().GetFilePath("go", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 
 	// Try to load from cache
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
@@ -419,6 +463,11 @@ func (f *GoFetcher) FetchGoVersion(version string) (*GoVersionInfo, error) {
 		ReleaseDate: f.extractReleaseDate(doc),
 		Content:     content,
 	}
+	article := findNode(doc, func(n *html.Node) bool { return n.Data == "article" || (n.Data == "div" && hasClass(n, "Article")) })
+	versionInfo.Degraded = checkShape("go", releaseURL,
+		shapeCheck{"article", article != nil},
+		shapeCheck{"heading", article != nil && hasMarkdownHeading(content)},
+	)
 
 	// Cache the result
 	if err := f.cacheVersionInfo(versionInfo); err != nil {
@@ -430,6 +479,12 @@ func (f *GoFetcher) FetchGoVersion(version string) (*GoVersionInfo, error) {
 
 // FetchLibraryInfo fetches and caches information about a Go library/package
 func (f *GoFetcher) FetchLibraryInfo(importPath, version string) (*LibraryInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchLibraryInfo", importPath, version), "", func() (*LibraryInfo, error) {
+		return f.fetchLibraryInfo(importPath, version)
+	})
+}
+
+func (f *GoFetcher) fetchLibraryInfo(importPath, version string) (*LibraryInfo, error) {
 	// If no version specified, query the Go proxy to get the latest version
 	if version == "" {
 		latestVersion, err := f.getLatestVersion(importPath)
@@ -451,10 +506,7 @@ func (f *GoFetcher) FetchLibraryInfo(importPath, version string) (*LibraryInfo,
 	}
 
 	// Build cache path
-	cacheKey := strings.ReplaceAll(importPath, "/", "_")
-	if version != "" {
-		cacheKey = fmt.Sprintf("%s_%s", cacheKey, version)
-	}
+	cacheKey := cache.EntryName(importPath, version)
 	cachedPath := f.getCache().GetFilePath("go", "libraries", fmt.Sprintf("%s.md", cacheKey))
 
 	// Try to load from cache
@@ -464,6 +516,20 @@ func (f *GoFetcher) FetchLibraryInfo(importPath, version string) (*LibraryInfo,
 		return libInfo, nil
 	}
 
+	if f.moduleZipDocs {
+		libInfo, err := f.fetchLibraryInfoFromModuleZip(importPath, version)
+		if err == nil {
+			libInfo.Description += f.advisoriesSection("go", importPath, libInfo.Version)
+			// Cache under the requested version, which may be empty when the
+			// latest could only be resolved for the containing module
+			if err := f.saveLibraryInfoAsMarkdown(cachedPath, libInfo); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache library info: %v\n", err)
+			}
+			return libInfo, nil
+		}
+		fmt.Fprintf(os.Stderr, "Warning: failed to render %s docs from the module zip, using pkg.go.dev: %v\n", importPath, err)
+	}
+
 	// Fetch from pkg.go.dev
 	fmt.Printf("Fetching %s information from pkg.go.dev...\n", importPath)
 
@@ -488,14 +554,20 @@ func (f *GoFetcher) FetchLibraryInfo(importPath, version string) (*LibraryInfo,
 	}
 
 	// Extract library information
+	apiDoc := extractAPIDoc(doc)
 	libInfo = &LibraryInfo{
-		ImportPath:  importPath,
-		Version:     version,
-		Synopsis:    f.extractSynopsis(doc),
-		Description: f.extractLibraryDescription(doc, importPath, version),
-		Repository:  f.extractRepository(doc),
-		License:     f.extractLicense(doc),
-	}
+		ImportPath: importPath,
+		Version:    version,
+		Synopsis:   f.extractSynopsis(doc),
+		Repository: f.extractRepository(doc),
+		License:    f.extractLicense(doc),
+	}
+	libInfo.Description = libraryDescription(importPath, version, libInfo.Synopsis, libInfo.Repository, libInfo.License, apiDoc)
+	libInfo.Degraded = checkShape("go", url,
+		shapeCheck{"synopsis", libInfo.Synopsis != ""},
+		shapeCheck{"api-doc", hasMarkdownHeading(apiDoc)},
+	)
+	libInfo.Description += f.advisoriesSection("go", importPath, version)
 
 	// Cache the result
 	if err := f.cacheLibraryInfo(libInfo); err != nil {
@@ -565,7 +637,9 @@ func (f *GoFetcher) extractReleaseNotes(doc *html.Node, version string) string {
 	return content.String()
 }
 
-func (f *GoFetcher) extractLibraryDescription(doc *html.Node, importPath, version string) string {
+// libraryDescription renders library info as markdown; apiDoc is the API
+// reference, or "" to only link to pkg.go.dev
+func libraryDescription(importPath, version, synopsis, repo, license, apiDoc string) string {
 	var content strings.Builder
 
 	versionStr := "latest"
@@ -578,18 +652,15 @@ func (f *GoFetcher) extractLibraryDescription(doc *html.Node, importPath, versio
 	fmt.Fprintf(&content, "**Import path:** `%s`\n\n", importPath)
 
 	// Add synopsis
-	synopsis := f.extractSynopsis(doc)
 	if synopsis != "" {
 		fmt.Fprintf(&content, "%s\n\n", synopsis)
 	}
 
 	// Add repository and license info
-	repo := f.extractRepository(doc)
 	if repo != "" {
 		fmt.Fprintf(&content, "**Repository:** %s\n\n", repo)
 	}
 
-	license := f.extractLicense(doc)
 	if license != "" {
 		fmt.Fprintf(&content, "**License:** %s\n\n", license)
 	}
@@ -607,6 +678,8 @@ func (f *GoFetcher) extractLibraryDescription(doc *html.Node, importPath, versio
 	fmt.Fprintf(&content, "import \"%s\"\n", importPath)
 	content.WriteString("```\n\n")
 
+	content.WriteString(apiDoc)
+
 	content.WriteString("## Documentation\n\n")
 	url := fmt.Sprintf("%s/%s", pkgGoDevBaseURL, importPath)
 	if version != "" {
@@ -730,16 +803,12 @@ func (f *GoFetcher) extractList(n *html.Node, content *strings.Builder) {
 }
 
 func (f *GoFetcher) cacheVersionInfo(info *GoVersionInfo) error {
-	outputPath := f.getCache().GetFilePath("go", "versions", fmt.Sprintf("%s.md", info.Version))
+	outputPath := f.getCache().GetFilePath("go", "versions", fmt.Sprintf("%s.md", cache.EntryName(info.Version)))
 	return f.saveVersionInfoAsMarkdown(outputPath, info)
 }
 
 func (f *GoFetcher) cacheLibraryInfo(info *LibraryInfo) error {
-	filename := strings.ReplaceAll(info.ImportPath, "/", "_")
-	if info.Version != "" {
-		filename = fmt.Sprintf("%s_%s", filename, info.Version)
-	}
-
+	filename := cache.EntryName(info.ImportPath, info.Version)
 	outputPath := f.getCache().GetFilePath("go", "libraries", fmt.Sprintf("%s.md", filename))
 	return f.saveLibraryInfoAsMarkdown(outputPath, info)
 }
@@ -841,31 +910,22 @@ func (f *GoFetcher) queryProxyLatest(importPath string) (string, error) {
 // Markdown conversion helpers
 
 func (f *GoFetcher) saveVersionInfoAsMarkdown(filePath string, info *GoVersionInfo) error {
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
 	fmt.Fprintf(&content, "releaseURL: \"%s\"\n", info.ReleaseURL)
+	writeDegraded(&content, info.Degraded)
 	content.WriteString("---\n\n")
 
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *GoFetcher) saveLibraryInfoAsMarkdown(filePath string, info *LibraryInfo) error {
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
@@ -883,12 +943,13 @@ func (f *GoFetcher) saveLibraryInfoAsMarkdown(filePath string, info *LibraryInfo
 	if info.License != "" {
 		fmt.Fprintf(&content, "license: \"%s\"\n", info.License)
 	}
+	writeDegraded(&content, info.Degraded)
 	content.WriteString("---\n\n")
 
 	// Markdown content
 	content.WriteString(info.Description)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *GoFetcher) loadVersionInfoFromMarkdown(filePath string) (*GoVersionInfo, error) {
@@ -898,7 +959,7 @@ func (f *GoFetcher) loadVersionInfoFromMarkdown(filePath string) (*GoVersionInfo
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -913,18 +974,23 @@ func (f *GoFetcher) loadVersionInfoFromMarkdown(filePath string) (*GoVersionInfo
 
 	// Parse YAML frontmatter
 	var meta struct {
-		Version    string `yaml:"version"`
-		ReleaseURL string `yaml:"releaseURL"`
+		Version    string   `yaml:"version"`
+		ReleaseURL string   `yaml:"releaseURL"`
+		Degraded   []string `yaml:"degraded"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
+	if degradedExpired(f.getCache(), filePath, meta.Degraded) {
+		return nil, fmt.Errorf("degraded entry expired")
+	}
 
 	return &GoVersionInfo{
 		Version:    meta.Version,
 		ReleaseURL: meta.ReleaseURL,
 		Content:    strings.TrimSpace(parts[2]),
+		Degraded:   meta.Degraded,
 	}, nil
 }
 
@@ -935,7 +1001,7 @@ func (f *GoFetcher) loadLibraryInfoFromMarkdown(filePath string) (*LibraryInfo,
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -950,16 +1016,20 @@ func (f *GoFetcher) loadLibraryInfoFromMarkdown(filePath string) (*LibraryInfo,
 
 	// Parse YAML frontmatter
 	var meta struct {
-		ImportPath string `yaml:"importPath"`
-		Version    string `yaml:"version"`
-		Synopsis   string `yaml:"synopsis"`
-		Repository string `yaml:"repository"`
-		License    string `yaml:"license"`
+		ImportPath string   `yaml:"importPath"`
+		Version    string   `yaml:"version"`
+		Synopsis   string   `yaml:"synopsis"`
+		Repository string   `yaml:"repository"`
+		License    string   `yaml:"license"`
+		Degraded   []string `yaml:"degraded"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
+	if degradedExpired(f.getCache(), filePath, meta.Degraded) {
+		return nil, fmt.Errorf("degraded entry expired")
+	}
 
 	return &LibraryInfo{
 		ImportPath:  meta.ImportPath,
@@ -968,5 +1038,6 @@ func (f *GoFetcher) loadLibraryInfoFromMarkdown(filePath string) (*LibraryInfo,
 		Description: strings.TrimSpace(parts[2]),
 		Repository:  meta.Repository,
 		License:     meta.License,
+		Degraded:    meta.Degraded,
 	}, nil
 }
@@ -0,0 +1,412 @@
+package fetcher
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/doc/comment"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// maxModuleZipSize bounds module zip downloads; the proxy allows up to 500MB
+// but libraries worth documenting are far smaller
+const maxModuleZipSize = 64 << 20
+
+// moduleLicenses maps phrases found in license files to their SPDX names
+var moduleLicenses = []struct {
+	phrase, name string
+}{
+	{"Apache License", "Apache-2.0"},
+	{"Mozilla Public License", "MPL-2.0"},
+	{"GNU LESSER GENERAL PUBLIC LICENSE", "LGPL"},
+	{"GNU GENERAL PUBLIC LICENSE", "GPL"},
+	{"Permission is hereby granted, free of charge", "MIT"},
+	{"Redistribution and use in source and binary forms", "BSD"},
+	{"ISC License", "ISC"},
+	{"This is free and unencumbered software", "Unlicense"},
+}
+
+// fetchLibraryInfoFromModuleZip renders library info from the module source:
+// the module zip is downloaded from the Go module proxy and go/doc extracts
+// the package documentation, so nothing depends on pkg.go.dev's HTML
+func (f *GoFetcher) fetchLibraryInfoFromModuleZip(importPath, version string) (*LibraryInfo, error) {
+	if !strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".") {
+		return nil, fmt.Errorf("%s is a standard library package, which the module proxy does not serve", importPath)
+	}
+
+	modulePath, resolved, err := f.resolveModule(importPath, version)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := f.downloadModuleZip(modulePath, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgDir := strings.TrimPrefix(strings.TrimPrefix(importPath, modulePath), "/")
+	pkg, fset, err := parsePackageDocs(files, pkgDir, importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &LibraryInfo{
+		ImportPath: importPath,
+		Version:    resolved,
+		Synopsis:   pkg.Synopsis(pkg.Doc),
+		Repository: moduleRepository(modulePath),
+		License:    moduleLicense(files, pkgDir),
+	}
+	info.Description = libraryDescription(importPath, resolved, info.Synopsis, info.Repository, info.License, renderPackageDoc(pkg, fset))
+	return info, nil
+}
+
+// resolveModule finds the module containing importPath by asking the proxy
+// about each path prefix, longest first, and returns the module path and
+// the resolved version
+func (f *GoFetcher) resolveModule(importPath, version string) (string, string, error) {
+	query := "@latest"
+	if version != "" {
+		query = "@v/" + escapeModulePath(version) + ".info"
+	}
+
+	for candidate := importPath; strings.Contains(candidate, "/"); candidate = path.Dir(candidate) {
+		url := fmt.Sprintf("%s/%s/%s", goProxyBaseURL, escapeModulePath(candidate), query)
+		resp, err := f.getClient().Get(url)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to query module proxy: %w", err)
+		}
+
+		var result struct {
+			Version string `json:"Version"`
+		}
+		if resp.StatusCode == http.StatusOK {
+			err = json.NewDecoder(resp.Body).Decode(&result)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK && err == nil && result.Version != "" {
+			return candidate, result.Version, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("no module found for %s on the module proxy", importPath)
+}
+
+// downloadModuleZip returns the files of a module version keyed by their
+// path relative to the module root
+func (f *GoFetcher) downloadModuleZip(modulePath, version string) (map[string]*zip.File, error) {
+	fmt.Fprintf(os.Stderr, "Downloading %s@%s from the module proxy...\n", modulePath, version)
+
+	url := fmt.Sprintf("%s/%s/@v/%s.zip", goProxyBaseURL, escapeModulePath(modulePath), escapeModulePath(version))
+	resp, err := f.getClient().Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download module zip: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d for %s@%s zip", resp.StatusCode, modulePath, version)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxModuleZipSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module zip: %w", err)
+	}
+	if len(data) > maxModuleZipSize {
+		return nil, fmt.Errorf("module zip for %s@%s is larger than %d MB", modulePath, version, maxModuleZipSize>>20)
+	}
+
+	return moduleZipFiles(data, modulePath, version)
+}
+
+// moduleZipFiles indexes a module zip. Every file sits under
+// "<module>@<version>/".
+func moduleZipFiles(data []byte, modulePath, version string) (map[string]*zip.File, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open module zip: %w", err)
+	}
+
+	prefix := modulePath + "@" + version + "/"
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, file := range zr.File {
+		if rel, ok := strings.CutPrefix(file.Name, prefix); ok {
+			files[rel] = file
+		}
+	}
+	return files, nil
+}
+
+// parsePackageDocs parses the Go files of one package directory, tests
+// included so examples are found, and computes its documentation
+func parsePackageDocs(files map[string]*zip.File, pkgDir, importPath string) (*doc.Package, *token.FileSet, error) {
+	fset := token.NewFileSet()
+	byPackage := make(map[string][]*ast.File)
+
+	var names []string
+	for name := range files {
+		if path.Dir(name) == pkgDir || (pkgDir == "" && !strings.Contains(name, "/")) {
+			if strings.HasSuffix(name, ".go") {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		src, err := readZipFile(files[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			// A file that does not parse is usually generated or ignored
+			continue
+		}
+		if isIgnoredGoFile(file) {
+			continue
+		}
+		byPackage[file.Name.Name] = append(byPackage[file.Name.Name], file)
+	}
+
+	// The package is the one with the most non-test files; its external
+	// test package contributes examples
+	var pkgName string
+	for name, parsed := range byPackage {
+		if strings.HasSuffix(name, "_test") || name == "main" && len(byPackage) > 1 {
+			continue
+		}
+		if pkgName == "" || len(parsed) > len(byPackage[pkgName]) || len(parsed) == len(byPackage[pkgName]) && name < pkgName {
+			pkgName = name
+		}
+	}
+	if pkgName == "" {
+		return nil, nil, fmt.Errorf("no Go package found at %s", importPath)
+	}
+
+	parsed := append(byPackage[pkgName], byPackage[pkgName+"_test"]...)
+	pkg, err := doc.NewFromFiles(fset, parsed, importPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute package docs: %w", err)
+	}
+	return pkg, fset, nil
+}
+
+// isIgnoredGoFile reports whether a file is excluded from every build,
+// like code generators marked "//go:build ignore"
+func isIgnoredGoFile(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, "//go:build") && strings.Contains(c.Text, "ignore") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", file.Name, err)
+	}
+	defer func() { _ = rc.Close() }()
+	return io.ReadAll(rc)
+}
+
+// renderPackageDoc renders package documentation in the layout
+// extractAPIDoc produces from pkg.go.dev
+func renderPackageDoc(pkg *doc.Package, fset *token.FileSet) string {
+	var content strings.Builder
+
+	if pkg.Doc != "" {
+		content.WriteString("## Overview\n\n")
+		writeDocText(&content, pkg, pkg.Doc)
+	}
+	renderExamples(&content, pkg.Examples, fset)
+
+	renderValues := func(title string, values []*doc.Value) {
+		if len(values) == 0 {
+			return
+		}
+		fmt.Fprintf(&content, "## %s\n\n", title)
+		for _, v := range values {
+			writeCode(&content, "go", formatDecl(fset, v.Decl))
+			writeDocText(&content, pkg, v.Doc)
+		}
+	}
+	renderValues("Constants", pkg.Consts)
+	renderValues("Variables", pkg.Vars)
+
+	if len(pkg.Funcs) > 0 {
+		content.WriteString("## Functions\n\n")
+		for _, fn := range pkg.Funcs {
+			renderFunc(&content, pkg, fset, fn, 3)
+		}
+	}
+
+	if len(pkg.Types) > 0 {
+		content.WriteString("## Types\n\n")
+		for _, t := range pkg.Types {
+			fmt.Fprintf(&content, "### type %s\n\n", t.Name)
+			writeCode(&content, "go", formatDecl(fset, t.Decl))
+			writeDocText(&content, pkg, t.Doc)
+			renderExamples(&content, t.Examples, fset)
+
+			for _, v := range append(t.Consts, t.Vars...) {
+				writeCode(&content, "go", formatDecl(fset, v.Decl))
+				writeDocText(&content, pkg, v.Doc)
+			}
+			for _, fn := range t.Funcs {
+				renderFunc(&content, pkg, fset, fn, 4)
+			}
+			for _, fn := range t.Methods {
+				renderFunc(&content, pkg, fset, fn, 4)
+			}
+		}
+	}
+
+	return truncateAPIDoc(content.String())
+}
+
+func renderFunc(content *strings.Builder, pkg *doc.Package, fset *token.FileSet, fn *doc.Func, level int) {
+	title := "func " + fn.Name
+	if fn.Recv != "" {
+		title = fmt.Sprintf("func (%s) %s", fn.Recv, fn.Name)
+	}
+	fmt.Fprintf(content, "%s %s\n\n", strings.Repeat("#", level), title)
+	writeCode(content, "go", formatDecl(fset, fn.Decl))
+	writeDocText(content, pkg, fn.Doc)
+	renderExamples(content, fn.Examples, fset)
+}
+
+func renderExamples(content *strings.Builder, examples []*doc.Example, fset *token.FileSet) {
+	for _, ex := range examples {
+		name := "Example"
+		if ex.Suffix != "" {
+			name += " (" + ex.Suffix + ")"
+		}
+
+		// Play is the example as a runnable program, when it can be one
+		var code string
+		if ex.Play != nil {
+			code = formatDecl(fset, ex.Play)
+		} else if block, ok := ex.Code.(*ast.BlockStmt); ok {
+			code = formatDecl(fset, block)
+			code = strings.TrimSuffix(strings.TrimPrefix(code, "{"), "}")
+			code = dedent(strings.Trim(code, "\n"))
+		} else {
+			code = formatDecl(fset, ex.Code)
+		}
+		if strings.TrimSpace(code) == "" {
+			continue
+		}
+
+		fmt.Fprintf(content, "**%s:**\n\n", name)
+		writeCode(content, "go", code)
+		if ex.Output != "" {
+			fmt.Fprintf(content, "Output:\n\n```\n%s\n```\n\n", strings.Trim(ex.Output, "\n"))
+		}
+	}
+}
+
+func writeDocText(content *strings.Builder, pkg *doc.Package, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	// Heading IDs ({#hdr-Name}) are not markdown every reader understands,
+	// and links to other packages should work outside pkg.go.dev
+	printer := pkg.Printer()
+	printer.HeadingID = func(*comment.Heading) string { return "" }
+	printer.DocLinkBaseURL = pkgGoDevBaseURL
+	content.Write(printer.Markdown(pkg.Parser().Parse(text)))
+	content.WriteString("\n")
+}
+
+// formatDecl prints a declaration or example the way gofmt would
+func formatDecl(fset *token.FileSet, node interface{}) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// dedent removes the indentation shared by all non-blank lines
+func dedent(code string) string {
+	lines := strings.Split(code, "\n")
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, "\t "))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent <= 0 {
+		return code
+	}
+	for i, line := range lines {
+		if len(line) >= indent {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// moduleRepository links modules hosted on well-known forges to their repository
+func moduleRepository(modulePath string) string {
+	parts := strings.Split(modulePath, "/")
+	switch parts[0] {
+	case "github.com", "gitlab.com", "bitbucket.org":
+		if len(parts) >= 3 {
+			return "https://" + strings.Join(parts[:3], "/")
+		}
+	}
+	return ""
+}
+
+// moduleLicense identifies the license file nearest the package, looking in
+// the package directory and then its parents up to the module root
+func moduleLicense(files map[string]*zip.File, pkgDir string) string {
+	for dir := pkgDir; ; dir = path.Dir(dir) {
+		if dir == "." {
+			dir = ""
+		}
+		for _, name := range []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING", "LICENCE"} {
+			file, ok := files[path.Join(dir, name)]
+			if !ok {
+				continue
+			}
+			data, err := readZipFile(file)
+			if err != nil {
+				continue
+			}
+			for _, l := range moduleLicenses {
+				if bytes.Contains(data, []byte(l.phrase)) {
+					return l.name
+				}
+			}
+		}
+		if dir == "" {
+			return ""
+		}
+	}
+}
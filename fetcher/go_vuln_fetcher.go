@@ -0,0 +1,507 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/semver"
+)
+
+const (
+	goVulnDBURL = "https://vuln.go.dev"
+
+	// maxGoVulnEntries bounds how many reports are fetched for one module;
+	// the standard library alone has hundreds
+	maxGoVulnEntries = 100
+
+	// goVulnWorkers is how many reports are fetched at once
+	goVulnWorkers = 8
+)
+
+// GoVulnFetcher reports known vulnerabilities in Go modules from the official
+// Go vulnerability database
+type GoVulnFetcher struct {
+	*BaseFetcher
+}
+
+// GoVulnReport is the list of vulnerabilities affecting a module
+type GoVulnReport struct {
+	Module    string        `yaml:"module"`
+	Version   string        `yaml:"version"`
+	Count     int           `yaml:"count"`
+	Vulns     []GoVulnEntry `yaml:"-"`
+	Truncated bool          `yaml:"-"`
+	Content   string        `yaml:"-"`
+}
+
+// GoVulnEntry is one vulndb report as it applies to a module
+type GoVulnEntry struct {
+	ID        string
+	Summary   string
+	Details   string
+	Aliases   []string
+	Published string
+	// Ranges are the affected version ranges, e.g. ">= 1.2.0, < 1.2.5"
+	Ranges []string
+	// Fixed is the earliest fixed version at or above the queried version,
+	// or the latest fixed version when no version was given
+	Fixed      string
+	Symbols    []GoVulnSymbols
+	References []string
+}
+
+// GoVulnSymbols lists the vulnerable symbols of one package
+type GoVulnSymbols struct {
+	Package string
+	Symbols []string
+	GOOS    []string
+	GOARCH  []string
+}
+
+// osvEntry is a report in the OSV format served by vuln.go.dev
+type osvEntry struct {
+	ID        string   `json:"id"`
+	Summary   string   `json:"summary"`
+	Details   string   `json:"details"`
+	Aliases   []string `json:"aliases"`
+	Published string   `json:"published"`
+	Withdrawn string   `json:"withdrawn"`
+	Affected  []struct {
+		Package struct {
+			Name string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string `json:"type"`
+			Events []struct {
+				Introduced string `json:"introduced"`
+				Fixed      string `json:"fixed"`
+			} `json:"events"`
+		} `json:"ranges"`
+		EcosystemSpecific struct {
+			Imports []struct {
+				Path    string   `json:"path"`
+				Symbols []string `json:"symbols"`
+				GOOS    []string `json:"goos"`
+				GOARCH  []string `json:"goarch"`
+			} `json:"imports"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"references"`
+}
+
+// NewGoVulnFetcher creates a new Go vulnerability fetcher
+func NewGoVulnFetcher(cacheDir string) *GoVulnFetcher {
+	return &GoVulnFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchModuleVulns fetches the vulnerabilities of a module. With a version,
+// only vulnerabilities affecting that version are reported. The standard
+// library is "stdlib" and the go command is "toolchain".
+func (f *GoVulnFetcher) FetchModuleVulns(module, version string) (*GoVulnReport, error) {
+	return shareFetch(f.flights, flightKey("FetchModuleVulns", module, version), f.reportPath(module, version), func() (*GoVulnReport, error) {
+		return f.fetchModuleVulns(module, version)
+	})
+}
+
+// reportPath returns the cache path of the report on a module
+func (f *GoVulnFetcher) reportPath(module, version string) string {
+	module, version = normalizeGoVulnQuery(module, version)
+	return f.getCache().GetFilePath("go", "vulns", fmt.Sprintf("%s.md", cache.EntryName(module, version)))
+}
+
+func (f *GoVulnFetcher) fetchModuleVulns(module, version string) (*GoVulnReport, error) {
+	module, version = normalizeGoVulnQuery(module, version)
+
+	// Check cache first
+	cachedPath := f.reportPath(module, version)
+	report, err := f.loadReportFromMarkdown(cachedPath)
+	if err == nil && report != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Go vulnerabilities for '%s' from cache\n", module)
+		return report, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Go vulnerabilities for '%s' from vuln.go.dev...\n", module)
+
+	var index []struct {
+		Path  string `json:"path"`
+		Vulns []struct {
+			ID       string `json:"id"`
+			Modified string `json:"modified"`
+			Fixed    string `json:"fixed"`
+		} `json:"vulns"`
+	}
+	if err := f.getVulnJSON(goVulnDBURL+"/index/modules.json", &index); err != nil {
+		return nil, err
+	}
+
+	report = &GoVulnReport{Module: module, Version: version}
+
+	var ids []string
+	for _, m := range index {
+		if m.Path != module {
+			continue
+		}
+		// Newest reports first, so truncation drops the oldest
+		sort.Slice(m.Vulns, func(i, j int) bool { return m.Vulns[i].Modified > m.Vulns[j].Modified })
+		for _, v := range m.Vulns {
+			// The index's fixed version is the latest fix, so a version at or
+			// above it is not affected and the report need not be fetched
+			if version != "" && v.Fixed != "" && semver.Compare(version, v.Fixed) >= 0 {
+				continue
+			}
+			ids = append(ids, v.ID)
+		}
+	}
+	if len(ids) > maxGoVulnEntries {
+		ids = ids[:maxGoVulnEntries]
+		report.Truncated = true
+	}
+
+	entries := make([]*osvEntry, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, goVulnWorkers)
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var entry osvEntry
+			if err := f.getVulnJSON(fmt.Sprintf("%s/ID/%s.json", goVulnDBURL, id), &entry); err != nil {
+				errs[i] = err
+				return
+			}
+			entries[i] = &entry
+		}(i, id)
+	}
+	wg.Wait()
+
+	for i, entry := range entries {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", ids[i], errs[i])
+		}
+		if entry.Withdrawn != "" {
+			continue
+		}
+		if vuln, ok := goVulnEntryFor(entry, module, version); ok {
+			report.Vulns = append(report.Vulns, vuln)
+		}
+	}
+	report.Count = len(report.Vulns)
+
+	report.Content = f.buildReportContent(report)
+
+	// Cache the result
+	if err := f.saveReportAsMarkdown(cachedPath, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache Go vulnerabilities: %v\n", err)
+	}
+
+	return report, nil
+}
+
+// normalizeGoVulnQuery maps the names people use for the standard library and
+// Go releases ("std", "go1.22.1") to the ones vulndb uses ("stdlib", "1.22.1")
+func normalizeGoVulnQuery(module, version string) (string, string) {
+	module = strings.TrimSpace(module)
+	if module == "std" || module == "go" {
+		module = "stdlib"
+	}
+	version = strings.TrimSpace(version)
+	if module == "stdlib" || module == "toolchain" {
+		version = strings.TrimPrefix(version, "go")
+	}
+	if version == "latest" {
+		version = ""
+	}
+	return module, version
+}
+
+// goVulnEntryFor extracts what a report says about module, reporting false
+// when it does not affect version
+func goVulnEntryFor(entry *osvEntry, module, version string) (GoVulnEntry, bool) {
+	vuln := GoVulnEntry{
+		ID:        entry.ID,
+		Summary:   entry.Summary,
+		Details:   entry.Details,
+		Aliases:   entry.Aliases,
+		Published: entry.Published,
+	}
+	if len(vuln.Published) >= 10 {
+		vuln.Published = vuln.Published[:10]
+	}
+
+	affected := version == ""
+	for _, a := range entry.Affected {
+		if a.Package.Name != module {
+			continue
+		}
+
+		for _, r := range a.Ranges {
+			if r.Type != "SEMVER" {
+				continue
+			}
+			introduced := ""
+			for _, ev := range r.Events {
+				switch {
+				case ev.Introduced != "":
+					introduced = ev.Introduced
+				case ev.Fixed != "":
+					vuln.Ranges = append(vuln.Ranges, formatGoVulnRange(module, introduced, ev.Fixed))
+					if version != "" && inGoVulnRange(version, introduced, ev.Fixed) {
+						affected = true
+						vuln.Fixed = ev.Fixed
+					} else if version == "" && (vuln.Fixed == "" || semver.Compare(ev.Fixed, vuln.Fixed) > 0) {
+						vuln.Fixed = ev.Fixed
+					}
+					introduced = ""
+				}
+			}
+			// An introduction without a fix leaves every later version affected
+			if introduced != "" {
+				vuln.Ranges = append(vuln.Ranges, formatGoVulnRange(module, introduced, ""))
+				if version != "" && inGoVulnRange(version, introduced, "") {
+					affected = true
+				}
+			}
+		}
+
+		for _, imp := range a.EcosystemSpecific.Imports {
+			vuln.Symbols = append(vuln.Symbols, GoVulnSymbols{
+				Package: imp.Path,
+				Symbols: imp.Symbols,
+				GOOS:    imp.GOOS,
+				GOARCH:  imp.GOARCH,
+			})
+		}
+	}
+
+	for _, ref := range entry.References {
+		vuln.References = append(vuln.References, ref.URL)
+	}
+
+	return vuln, affected
+}
+
+func inGoVulnRange(version, introduced, fixed string) bool {
+	if introduced != "" && introduced != "0" && semver.Compare(version, introduced) < 0 {
+		return false
+	}
+	return fixed == "" || semver.Compare(version, fixed) < 0
+}
+
+func formatGoVulnRange(module, introduced, fixed string) string {
+	var parts []string
+	if introduced != "" && introduced != "0" {
+		parts = append(parts, ">= "+goVulnVersion(module, introduced))
+	}
+	if fixed != "" {
+		parts = append(parts, "< "+goVulnVersion(module, fixed))
+	}
+	if len(parts) == 0 {
+		return "all versions"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// goVulnVersion restores the prefix vulndb strips from versions: "v" for
+// modules and "go" for the standard library and toolchain
+func goVulnVersion(module, version string) string {
+	switch {
+	case version == "":
+		return version
+	case module == "stdlib" || module == "toolchain":
+		return "go" + version
+	case strings.HasPrefix(version, "v"):
+		return version
+	default:
+		return "v" + version
+	}
+}
+
+func (f *GoVulnFetcher) getVulnJSON(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch vulnerability data: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vuln.go.dev returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse vulnerability data: %w", err)
+	}
+
+	return nil
+}
+
+func (f *GoVulnFetcher) buildReportContent(report *GoVulnReport) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Go Vulnerabilities: %s\n\n", report.Module)
+
+	fmt.Fprintf(&content, "**Module:** %s\n\n", report.Module)
+	if report.Version != "" {
+		fmt.Fprintf(&content, "**Version:** %s\n\n", goVulnVersion(report.Module, report.Version))
+	} else {
+		content.WriteString("**Version:** all versions\n\n")
+	}
+	fmt.Fprintf(&content, "**Vulnerabilities:** %d\n\n", report.Count)
+
+	if report.Count == 0 {
+		if report.Version != "" {
+			fmt.Fprintf(&content, "No known vulnerabilities affect %s@%s.\n\n", report.Module, goVulnVersion(report.Module, report.Version))
+		} else {
+			fmt.Fprintf(&content, "No known vulnerabilities in %s.\n\n", report.Module)
+		}
+	} else if report.Version != "" {
+		// The lowest version fixing every reported vulnerability
+		upgrade := ""
+		for _, v := range report.Vulns {
+			if v.Fixed == "" {
+				upgrade = ""
+				break
+			}
+			if upgrade == "" || semver.Compare(v.Fixed, upgrade) > 0 {
+				upgrade = v.Fixed
+			}
+		}
+		if upgrade != "" {
+			fmt.Fprintf(&content, "**Upgrade to:** %s or later fixes all of them\n\n", goVulnVersion(report.Module, upgrade))
+		}
+	}
+
+	if report.Truncated {
+		fmt.Fprintf(&content, "Only the %d most recently updated reports are shown.\n\n", maxGoVulnEntries)
+	}
+
+	for _, v := range report.Vulns {
+		fmt.Fprintf(&content, "## %s", v.ID)
+		if v.Summary != "" {
+			fmt.Fprintf(&content, ": %s", v.Summary)
+		}
+		content.WriteString("\n\n")
+
+		if len(v.Aliases) > 0 {
+			fmt.Fprintf(&content, "**Aliases:** %s\n\n", strings.Join(v.Aliases, ", "))
+		}
+		if v.Published != "" {
+			fmt.Fprintf(&content, "**Published:** %s\n\n", v.Published)
+		}
+		if len(v.Ranges) > 0 {
+			fmt.Fprintf(&content, "**Affected:** %s\n\n", strings.Join(v.Ranges, "; "))
+		}
+		if v.Fixed != "" {
+			fmt.Fprintf(&content, "**Fixed in:** %s\n\n", goVulnVersion(report.Module, v.Fixed))
+		} else {
+			content.WriteString("**Fixed in:** no fix available\n\n")
+		}
+
+		if len(v.Symbols) > 0 {
+			content.WriteString("**Affected symbols:**\n\n")
+			for _, s := range v.Symbols {
+				fmt.Fprintf(&content, "- `%s`", s.Package)
+				if len(s.Symbols) > 0 {
+					fmt.Fprintf(&content, ": `%s`", strings.Join(s.Symbols, "`, `"))
+				} else {
+					content.WriteString(": all symbols")
+				}
+				if len(s.GOOS) > 0 || len(s.GOARCH) > 0 {
+					fmt.Fprintf(&content, " (%s)", strings.Join(append(append([]string{}, s.GOOS...), s.GOARCH...), ", "))
+				}
+				content.WriteString("\n")
+			}
+			content.WriteString("\n")
+		}
+
+		if v.Details != "" {
+			fmt.Fprintf(&content, "%s\n\n", strings.TrimSpace(v.Details))
+		}
+
+		fmt.Fprintf(&content, "- [pkg.go.dev/vuln/%s](https://pkg.go.dev/vuln/%s)\n", v.ID, v.ID)
+		for _, ref := range v.References {
+			fmt.Fprintf(&content, "- %s\n", ref)
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("- [Go Vulnerability Database](https://pkg.go.dev/vuln/)\n")
+	content.WriteString("- Check your code's actual exposure with `govulncheck ./...` (`go install golang.org/x/vuln/cmd/govulncheck@latest`)\n")
+
+	return content.String()
+}
+
+func (f *GoVulnFetcher) saveReportAsMarkdown(filePath string, report *GoVulnReport) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "module: \"%s\"\n", escapeYAML(report.Module))
+	if report.Version != "" {
+		fmt.Fprintf(&content, "version: \"%s\"\n", escapeYAML(report.Version))
+	}
+	fmt.Fprintf(&content, "count: %d\n", report.Count)
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(report.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *GoVulnFetcher) loadReportFromMarkdown(filePath string) (*GoVulnReport, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var report GoVulnReport
+	if err := yaml.Unmarshal([]byte(parts[1]), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	report.Content = strings.TrimSpace(parts[2])
+	return &report, nil
+}
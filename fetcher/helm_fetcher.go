@@ -5,20 +5,18 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
-	"time"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/semver"
 )
 
-type HelmVersionInfo struct {
-	Version     string `yaml:"version"`
-	ReleaseDate string `yaml:"releaseDate"`
-	ReleaseURL  string `yaml:"releaseURL"`
-	Content     string `yaml:"-"`
-}
+type HelmVersionInfo = ReleaseVersionInfo
 
 type HelmFetcher struct {
 	*BaseFetcher
@@ -32,6 +30,17 @@ func NewHelmFetcher(cacheDir string) *HelmFetcher {
 
 // FetchHelmVersion fetches information about a specific Helm version
 func (f *HelmFetcher) FetchHelmVersion(version string) (*HelmVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchHelmVersion", version), f.versionPath(version), func() (*HelmVersionInfo, error) {
+		return f.fetchHelmVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Helm version
+func (f *HelmFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("helm", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *HelmFetcher) fetchHelmVersion(version string) (*HelmVersionInfo, error) {
 	// Normalize version (add 'v' prefix if missing for GitHub API)
 	githubVersion := version
 	if !strings.HasPrefix(version, "v") {
@@ -39,7 +48,7 @@ func (f *HelmFetcher) FetchHelmVersion(version string) (*HelmVersionInfo, error)
 	}
 
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("helm", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
 	if err == nil && versionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Helm version '%s' from cache\n", version)
@@ -79,42 +88,13 @@ func (f *HelmFetcher) FetchHelmVersion(version string) (*HelmVersionInfo, error)
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse GitHub release data
-	var releaseData map[string]interface{}
-	if err := json.Unmarshal(body, &releaseData); err != nil {
-		return nil, fmt.Errorf("failed to parse release data: %w", err)
-	}
-
-	// Extract version information
-	versionInfo = &HelmVersionInfo{
-		Version: version,
-	}
-
-	// Extract release URL
-	if htmlURL, ok := releaseData["html_url"].(string); ok {
-		versionInfo.ReleaseURL = htmlURL
-	}
-
-	// Extract release date
-	if publishedAt, ok := releaseData["published_at"].(string); ok {
-		// Parse and format the date
-		if t, err := time.Parse(time.RFC3339, publishedAt); err == nil {
-			versionInfo.ReleaseDate = t.Format("2006-01-02")
-		} else {
-			versionInfo.ReleaseDate = publishedAt
-		}
-	}
-
-	// Extract release notes from body
-	releaseNotes := ""
-	if body, ok := releaseData["body"].(string); ok {
-		releaseNotes = body
+	versionInfo, err = renderReleaseVersion(version, body, f.buildVersionContent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build content
-	versionInfo.Content = f.buildVersionContent(versionInfo, releaseNotes)
-
-	// Cache the result
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
 	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
 	}
@@ -183,17 +163,12 @@ func (f *HelmFetcher) buildVersionContent(info *HelmVersionInfo, releaseNotes st
 }
 
 func (f *HelmFetcher) saveVersionInfoAsMarkdown(filePath string, info *HelmVersionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
 	if info.ReleaseDate != "" {
 		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
 	}
@@ -205,16 +180,16 @@ func (f *HelmFetcher) saveVersionInfoAsMarkdown(filePath string, info *HelmVersi
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *HelmFetcher) loadVersionInfoFromMarkdown(filePath string) (*HelmVersionInfo, error) {
-	expired, err := f.getCache().IsExpired(filePath)
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
 	if err != nil || expired {
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -229,12 +204,23 @@ func (f *HelmFetcher) loadVersionInfoFromMarkdown(filePath string) (*HelmVersion
 		Version     string `yaml:"version"`
 		ReleaseDate string `yaml:"releaseDate"`
 		ReleaseURL  string `yaml:"releaseURL"`
+		Template    int    `yaml:"template"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if info, err := renderReleaseVersion(meta.Version, payload, f.buildVersionContent); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered Helm version '%s' with current templates\n", meta.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
 	return &HelmVersionInfo{
 		Version:     meta.Version,
 		ReleaseDate: meta.ReleaseDate,
@@ -242,3 +228,429 @@ func (f *HelmFetcher) loadVersionInfoFromMarkdown(filePath string) (*HelmVersion
 		Content:     strings.TrimSpace(parts[2]),
 	}, nil
 }
+
+const artifactHubURL = "https://artifacthub.io/api/v1"
+
+const (
+	// maxValuesKeys caps the number of top-level values listed in a chart summary
+	maxValuesKeys = 40
+)
+
+type HelmChartInfo struct {
+	Name          string           `yaml:"name"`
+	Repository    string           `yaml:"repository"`
+	RepositoryURL string           `yaml:"repositoryURL"`
+	Version       string           `yaml:"version"`
+	AppVersion    string           `yaml:"appVersion"`
+	Description   string           `yaml:"description"`
+	License       string           `yaml:"license"`
+	HomeURL       string           `yaml:"homeURL"`
+	Deprecated    bool             `yaml:"deprecated"`
+	Maintainers   []HelmMaintainer `yaml:"-"`
+	Versions      []string         `yaml:"-"`
+	Values        []HelmValue      `yaml:"-"`
+	Content       string           `yaml:"-"`
+}
+
+// HelmMaintainer is a chart maintainer listed in Chart.yaml
+type HelmMaintainer struct {
+	Name  string
+	Email string
+}
+
+// HelmValue summarizes a top-level key of a chart's default values.yaml
+type HelmValue struct {
+	Key         string
+	Default     string
+	Description string
+}
+
+// FetchChart fetches information about a Helm chart ("repo/chart") from Artifact Hub.
+// A bare chart name is resolved to the most starred chart with that name.
+func (f *HelmFetcher) FetchChart(chart, version string) (*HelmChartInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchChart", chart, version), "", func() (*HelmChartInfo, error) {
+		return f.fetchChart(chart, version)
+	})
+}
+
+func (f *HelmFetcher) fetchChart(chart, version string) (*HelmChartInfo, error) {
+	repo, name, ok := strings.Cut(chart, "/")
+	if !ok {
+		resolved, err := f.searchChart(chart)
+		if err != nil {
+			return nil, err
+		}
+		repo, name = resolved, chart
+	}
+
+	safeName := cache.EntryName(repo, name, version)
+
+	// Check cache first
+	cachedPath := f.getCache().GetFilePath("helm", "charts", fmt.Sprintf("%s.md", safeName))
+	chartInfo, err := f.loadChartFromMarkdown(cachedPath)
+	if err == nil && chartInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Helm chart '%s/%s' from cache\n", repo, name)
+		return chartInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Helm chart '%s/%s' from artifacthub.io...\n", repo, name)
+
+	packageURL := fmt.Sprintf("%s/packages/helm/%s/%s", artifactHubURL, url.PathEscape(repo), url.PathEscape(name))
+	if version != "" {
+		packageURL += "/" + url.PathEscape(version)
+	}
+
+	var pkg struct {
+		PackageID   string `json:"package_id"`
+		Name        string `json:"name"`
+		Version     string `json:"version"`
+		AppVersion  string `json:"app_version"`
+		Description string `json:"description"`
+		License     string `json:"license"`
+		HomeURL     string `json:"home_url"`
+		Deprecated  bool   `json:"deprecated"`
+		Maintainers []struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"maintainers"`
+		Repository struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"repository"`
+		AvailableVersions []struct {
+			Version string `json:"version"`
+		} `json:"available_versions"`
+	}
+	body, err := f.getArtifactHub(packageURL)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		if version != "" {
+			return nil, fmt.Errorf("helm chart %s/%s version %s not found", repo, name, version)
+		}
+		return nil, fmt.Errorf("helm chart %s/%s not found", repo, name)
+	}
+	if err := json.Unmarshal(body, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse chart data: %w", err)
+	}
+
+	chartInfo = &HelmChartInfo{
+		Name:          pkg.Name,
+		Repository:    pkg.Repository.Name,
+		RepositoryURL: pkg.Repository.URL,
+		Version:       pkg.Version,
+		AppVersion:    pkg.AppVersion,
+		Description:   pkg.Description,
+		License:       pkg.License,
+		HomeURL:       pkg.HomeURL,
+		Deprecated:    pkg.Deprecated,
+	}
+
+	for _, m := range pkg.Maintainers {
+		chartInfo.Maintainers = append(chartInfo.Maintainers, HelmMaintainer{Name: m.Name, Email: m.Email})
+	}
+
+	for _, v := range pkg.AvailableVersions {
+		chartInfo.Versions = append(chartInfo.Versions, v.Version)
+	}
+	sort.Slice(chartInfo.Versions, func(i, j int) bool {
+		return semver.Compare(chartInfo.Versions[i], chartInfo.Versions[j]) > 0
+	})
+
+	// The default values are served separately as raw YAML; a chart without
+	// them is still useful, so failures only drop the summary
+	if pkg.PackageID != "" {
+		values, err := f.getArtifactHub(fmt.Sprintf("%s/packages/%s/%s/values", artifactHubURL, pkg.PackageID, url.PathEscape(pkg.Version)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch default values: %v\n", err)
+		} else if values != nil {
+			chartInfo.Values = summarizeHelmValues(values)
+		}
+	}
+
+	// Build content
+	chartInfo.Content = f.buildChartContent(chartInfo)
+
+	// Cache the result
+	if err := f.saveChartAsMarkdown(cachedPath, chartInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache chart info: %v\n", err)
+	}
+
+	return chartInfo, nil
+}
+
+// searchChart returns the repository of the most starred chart named name
+func (f *HelmFetcher) searchChart(name string) (string, error) {
+	// kind=0 restricts the search to Helm charts
+	searchURL := fmt.Sprintf("%s/packages/search?ts_query_web=%s&kind=0&limit=20", artifactHubURL, url.QueryEscape(name))
+	body, err := f.getArtifactHub(searchURL)
+	if err != nil {
+		return "", err
+	}
+
+	var results struct {
+		Packages []struct {
+			Name       string `json:"name"`
+			Stars      int    `json:"stars"`
+			Repository struct {
+				Name     string `json:"name"`
+				Official bool   `json:"official"`
+			} `json:"repository"`
+		} `json:"packages"`
+	}
+	if body != nil {
+		if err := json.Unmarshal(body, &results); err != nil {
+			return "", fmt.Errorf("failed to parse search results: %w", err)
+		}
+	}
+
+	repo, bestStars, bestOfficial := "", -1, false
+	for _, p := range results.Packages {
+		if p.Name != name {
+			continue
+		}
+		// Prefer charts from official repositories, then by popularity
+		if (p.Repository.Official && !bestOfficial) || (p.Repository.Official == bestOfficial && p.Stars > bestStars) {
+			repo, bestStars, bestOfficial = p.Repository.Name, p.Stars, p.Repository.Official
+		}
+	}
+	if repo == "" {
+		return "", fmt.Errorf("helm chart %s not found", name)
+	}
+	return repo, nil
+}
+
+// getArtifactHub fetches an Artifact Hub API resource, returning nil without error when it does not exist
+func (f *HelmFetcher) getArtifactHub(apiURL string) ([]byte, error) {
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Helm chart: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("artifact Hub API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return body, nil
+}
+
+// summarizeHelmValues lists the top-level keys of a values.yaml with their
+// scalar defaults and the first line of their comment
+func summarizeHelmValues(data []byte) []HelmValue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var values []HelmValue
+	for i := 0; i+1 < len(root.Content) && len(values) < maxValuesKeys; i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		v := HelmValue{Key: key.Value, Description: helmValueComment(key.HeadComment)}
+		switch value.Kind {
+		case yaml.ScalarNode:
+			v.Default = value.Value
+			if v.Default == "" {
+				v.Default = `""`
+			}
+		case yaml.MappingNode:
+			if len(value.Content) == 0 {
+				v.Default = "{}"
+			} else {
+				v.Default = fmt.Sprintf("{...} (%d keys)", len(value.Content)/2)
+			}
+		case yaml.SequenceNode:
+			if len(value.Content) == 0 {
+				v.Default = "[]"
+			} else {
+				v.Default = fmt.Sprintf("[...] (%d items)", len(value.Content))
+			}
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// helmValueComment returns the last descriptive line of a key's head comment,
+// skipping helm-docs annotations and commented-out YAML
+func helmValueComment(comment string) string {
+	lines := strings.Split(comment, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(lines[i]), "#"))
+		line = strings.TrimSpace(strings.TrimPrefix(line, "--"))
+		if line == "" || strings.HasPrefix(line, "@") || strings.HasSuffix(line, ":") || strings.Contains(line, ": ") {
+			continue
+		}
+		return line
+	}
+	return ""
+}
+
+func (f *HelmFetcher) buildChartContent(info *HelmChartInfo) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s/%s\n\n", info.Repository, info.Name)
+
+	if info.Deprecated {
+		content.WriteString("> **Deprecated:** this chart is no longer maintained.\n\n")
+	}
+
+	if info.Description != "" {
+		fmt.Fprintf(&content, "**Description:** %s\n\n", info.Description)
+	}
+
+	fmt.Fprintf(&content, "**Chart Version:** %s\n\n", info.Version)
+
+	if info.AppVersion != "" {
+		fmt.Fprintf(&content, "**App Version:** %s\n\n", info.AppVersion)
+	}
+
+	if info.License != "" {
+		fmt.Fprintf(&content, "**License:** %s\n\n", info.License)
+	}
+
+	if info.HomeURL != "" {
+		fmt.Fprintf(&content, "**Homepage:** %s\n\n", info.HomeURL)
+	}
+
+	if info.RepositoryURL != "" {
+		fmt.Fprintf(&content, "**Chart Repository:** %s\n\n", info.RepositoryURL)
+	}
+
+	if len(info.Versions) > 0 {
+		shown := info.Versions
+		if len(shown) > 10 {
+			shown = shown[:10]
+		}
+		fmt.Fprintf(&content, "**Recent Versions:** %s\n\n", strings.Join(shown, ", "))
+	}
+
+	if len(info.Maintainers) > 0 {
+		content.WriteString("## Maintainers\n\n")
+		for _, m := range info.Maintainers {
+			if m.Email != "" {
+				fmt.Fprintf(&content, "- %s <%s>\n", m.Name, m.Email)
+			} else {
+				fmt.Fprintf(&content, "- %s\n", m.Name)
+			}
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("```bash\n")
+	if strings.HasPrefix(info.RepositoryURL, "oci://") {
+		// OCI registries need no repo add; install straight from the reference
+		fmt.Fprintf(&content, "helm install my-%s %s/%s --version %s\n", info.Name, strings.TrimSuffix(info.RepositoryURL, "/"), info.Name, info.Version)
+	} else {
+		if info.RepositoryURL != "" {
+			fmt.Fprintf(&content, "helm repo add %s %s\n", info.Repository, info.RepositoryURL)
+			content.WriteString("helm repo update\n")
+		}
+		fmt.Fprintf(&content, "helm install my-%s %s/%s --version %s\n", info.Name, info.Repository, info.Name, info.Version)
+	}
+	content.WriteString("```\n\n")
+
+	if len(info.Values) > 0 {
+		content.WriteString("## Default Values\n\n")
+		content.WriteString("Top-level keys of the chart's `values.yaml`:\n\n")
+		content.WriteString("| Key | Default | Description |\n")
+		content.WriteString("|-----|---------|-------------|\n")
+		for _, v := range info.Values {
+			fmt.Fprintf(&content, "| `%s` | `%s` | %s |\n", v.Key, strings.ReplaceAll(v.Default, "|", "\\|"), strings.ReplaceAll(v.Description, "|", "\\|"))
+		}
+		content.WriteString("\n")
+		content.WriteString("Show the full defaults with:\n\n")
+		content.WriteString("```bash\n")
+		fmt.Fprintf(&content, "helm show values %s/%s --version %s\n", info.Repository, info.Name, info.Version)
+		content.WriteString("```\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [Artifact Hub](https://artifacthub.io/packages/helm/%s/%s)\n", info.Repository, info.Name)
+	content.WriteString("- [Helm Documentation](https://helm.sh/docs/)\n")
+
+	return content.String()
+}
+
+func (f *HelmFetcher) saveChartAsMarkdown(filePath string, info *HelmChartInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "name: \"%s\"\n", info.Name)
+	fmt.Fprintf(&content, "repository: \"%s\"\n", info.Repository)
+	if info.RepositoryURL != "" {
+		fmt.Fprintf(&content, "repositoryURL: \"%s\"\n", info.RepositoryURL)
+	}
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	if info.AppVersion != "" {
+		fmt.Fprintf(&content, "appVersion: \"%s\"\n", escapeYAML(info.AppVersion))
+	}
+	if info.Description != "" {
+		fmt.Fprintf(&content, "description: \"%s\"\n", escapeYAML(info.Description))
+	}
+	if info.License != "" {
+		fmt.Fprintf(&content, "license: \"%s\"\n", escapeYAML(info.License))
+	}
+	if info.HomeURL != "" {
+		fmt.Fprintf(&content, "homeURL: \"%s\"\n", info.HomeURL)
+	}
+	if info.Deprecated {
+		content.WriteString("deprecated: true\n")
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *HelmFetcher) loadChartFromMarkdown(filePath string) (*HelmChartInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info HelmChartInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -0,0 +1,321 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+type HexPackageInfo struct {
+	Name         string          `yaml:"name"`
+	Version      string          `yaml:"version"`
+	Description  string          `yaml:"description"`
+	License      string          `yaml:"license"`
+	Repository   string          `yaml:"repository"`
+	DocsURL      string          `yaml:"docsURL"`
+	ReleaseDate  string          `yaml:"releaseDate"`
+	BuildTools   []string        `yaml:"-"`
+	Dependencies []HexDependency `yaml:"-"`
+	Content      string          `yaml:"-"`
+}
+
+type HexDependency struct {
+	Name        string
+	Requirement string
+	Optional    bool
+}
+
+type HexFetcher struct {
+	*BaseFetcher
+}
+
+func NewHexFetcher(cacheDir string) *HexFetcher {
+	return &HexFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchPackageInfo fetches information about an Elixir/Erlang package from hex.pm
+func (f *HexFetcher) FetchPackageInfo(packageName, version string) (*HexPackageInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchPackageInfo", packageName, version), f.packagePath(packageName, version), func() (*HexPackageInfo, error) {
+		return f.fetchPackageInfo(packageName, version)
+	})
+}
+
+// packagePath returns the cache path of the info on a package
+func (f *HexFetcher) packagePath(packageName, version string) string {
+	return f.getCache().GetFilePath("hex", "packages", fmt.Sprintf("%s.md", cache.EntryName(packageName, version)))
+}
+
+func (f *HexFetcher) fetchPackageInfo(packageName, version string) (*HexPackageInfo, error) {
+	// Check cache first
+	cachedPath := f.packagePath(packageName, version)
+	pkgInfo, err := f.loadPackageInfoFromMarkdown(cachedPath)
+	if err == nil && pkgInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Hex package '%s' from cache\n", packageName)
+		return pkgInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Hex package '%s' from hex.pm...\n", packageName)
+
+	var pkgData struct {
+		Name                string `json:"name"`
+		LatestStableVersion string `json:"latest_stable_version"`
+		LatestVersion       string `json:"latest_version"`
+		DocsHTMLURL         string `json:"docs_html_url"`
+		Meta                struct {
+			Description string            `json:"description"`
+			Licenses    []string          `json:"licenses"`
+			Links       map[string]string `json:"links"`
+		} `json:"meta"`
+	}
+	if err := f.getJSON(fmt.Sprintf("https://hex.pm/api/packages/%s", packageName), &pkgData); err != nil {
+		if errors.Is(err, errHexNotFound) {
+			return nil, fmt.Errorf("hex package %s not found", packageName)
+		}
+		return nil, fmt.Errorf("failed to fetch hex package: %w", err)
+	}
+
+	if version == "" {
+		version = pkgData.LatestStableVersion
+		if version == "" {
+			version = pkgData.LatestVersion
+		}
+	}
+
+	var releaseData struct {
+		Version      string `json:"version"`
+		InsertedAt   string `json:"inserted_at"`
+		DocsHTMLURL  string `json:"docs_html_url"`
+		Requirements map[string]struct {
+			Requirement string `json:"requirement"`
+			Optional    bool   `json:"optional"`
+		} `json:"requirements"`
+		Meta struct {
+			BuildTools []string `json:"build_tools"`
+		} `json:"meta"`
+	}
+	if err := f.getJSON(fmt.Sprintf("https://hex.pm/api/packages/%s/releases/%s", packageName, version), &releaseData); err != nil {
+		if errors.Is(err, errHexNotFound) {
+			return nil, fmt.Errorf("hex package %s version %s not found", packageName, version)
+		}
+		return nil, fmt.Errorf("failed to fetch hex release: %w", err)
+	}
+
+	pkgInfo = &HexPackageInfo{
+		Name:        pkgData.Name,
+		Version:     releaseData.Version,
+		Description: pkgData.Meta.Description,
+		License:     strings.Join(pkgData.Meta.Licenses, ", "),
+		DocsURL:     releaseData.DocsHTMLURL,
+		BuildTools:  releaseData.Meta.BuildTools,
+	}
+	if pkgInfo.Name == "" {
+		pkgInfo.Name = packageName
+	}
+	if pkgInfo.DocsURL == "" {
+		pkgInfo.DocsURL = pkgData.DocsHTMLURL
+	}
+
+	// Prefer a source repository link from the package metadata
+	for label, link := range pkgData.Meta.Links {
+		lower := strings.ToLower(label)
+		if lower == "github" || lower == "gitlab" || lower == "source" || lower == "repository" {
+			pkgInfo.Repository = link
+			break
+		}
+	}
+
+	if t, err := time.Parse(time.RFC3339, releaseData.InsertedAt); err == nil {
+		pkgInfo.ReleaseDate = t.Format("2006-01-02")
+	}
+
+	for name, req := range releaseData.Requirements {
+		pkgInfo.Dependencies = append(pkgInfo.Dependencies, HexDependency{
+			Name:        name,
+			Requirement: req.Requirement,
+			Optional:    req.Optional,
+		})
+	}
+	sort.Slice(pkgInfo.Dependencies, func(i, j int) bool {
+		return pkgInfo.Dependencies[i].Name < pkgInfo.Dependencies[j].Name
+	})
+
+	// Build content
+	pkgInfo.Content = f.buildPackageContent(pkgInfo)
+
+	// Cache the result
+	if err := f.savePackageInfoAsMarkdown(cachedPath, pkgInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache package info: %v\n", err)
+	}
+
+	return pkgInfo, nil
+}
+
+var errHexNotFound = errors.New("not found")
+
+func (f *HexFetcher) getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errHexNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("hex.pm API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse hex.pm data: %w", err)
+	}
+
+	return nil
+}
+
+func (f *HexFetcher) buildPackageContent(info *HexPackageInfo) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Name)
+
+	if info.Description != "" {
+		fmt.Fprintf(&content, "**Description:** %s\n\n", info.Description)
+	}
+
+	fmt.Fprintf(&content, "**Version:** %s\n\n", info.Version)
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+
+	if info.License != "" {
+		fmt.Fprintf(&content, "**License:** %s\n\n", info.License)
+	}
+
+	if len(info.BuildTools) > 0 {
+		fmt.Fprintf(&content, "**Build Tools:** %s\n\n", strings.Join(info.BuildTools, ", "))
+	}
+
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "**Repository:** %s\n\n", info.Repository)
+	}
+
+	if len(info.Dependencies) > 0 {
+		content.WriteString("## Dependencies\n\n")
+		for _, dep := range info.Dependencies {
+			fmt.Fprintf(&content, "- `%s` %s", dep.Name, dep.Requirement)
+			if dep.Optional {
+				content.WriteString(" (optional)")
+			}
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("### Using Mix (mix.exs)\n\n")
+	content.WriteString("```elixir\n")
+	content.WriteString("def deps do\n")
+	content.WriteString("  [\n")
+	fmt.Fprintf(&content, "    %s\n", mixDependencyLine(info.Name, info.Version, false))
+	content.WriteString("  ]\n")
+	content.WriteString("end\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using Rebar3 (rebar.config)\n\n")
+	content.WriteString("```erlang\n")
+	fmt.Fprintf(&content, "{deps, [{%s, \"%s\"}]}.\n", info.Name, info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	if info.DocsURL != "" {
+		fmt.Fprintf(&content, "- [HexDocs](%s)\n", info.DocsURL)
+	} else {
+		fmt.Fprintf(&content, "- [HexDocs](https://hexdocs.pm/%s/%s)\n", info.Name, info.Version)
+	}
+	fmt.Fprintf(&content, "- [hex.pm](https://hex.pm/packages/%s/%s)\n", info.Name, info.Version)
+
+	return content.String()
+}
+
+func (f *HexFetcher) savePackageInfoAsMarkdown(filePath string, info *HexPackageInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "name: \"%s\"\n", info.Name)
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	if info.Description != "" {
+		fmt.Fprintf(&content, "description: \"%s\"\n", escapeYAML(info.Description))
+	}
+	if info.License != "" {
+		fmt.Fprintf(&content, "license: \"%s\"\n", escapeYAML(info.License))
+	}
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "repository: \"%s\"\n", info.Repository)
+	}
+	if info.DocsURL != "" {
+		fmt.Fprintf(&content, "docsURL: \"%s\"\n", info.DocsURL)
+	}
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *HexFetcher) loadPackageInfoFromMarkdown(filePath string) (*HexPackageInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta HexPackageInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	meta.Content = strings.TrimSpace(parts[2])
+	return &meta, nil
+}
@@ -0,0 +1,387 @@
+package fetcher
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+const (
+	mdnContentRawURL = "https://raw.githubusercontent.com/mdn/content/main/files/en-us"
+	mdnDocsURL       = "https://developer.mozilla.org/en-US/docs"
+
+	// maxHTTPReferenceChars keeps long header pages, such as
+	// Content-Security-Policy, to a reference-sized answer
+	maxHTTPReferenceChars = 20000
+)
+
+// httpReferenceKinds maps the kinds get_http_reference accepts to MDN's
+// directory of that reference
+var httpReferenceKinds = map[string]string{
+	"status":  "status",
+	"code":    "status",
+	"header":  "headers",
+	"headers": "headers",
+	"method":  "methods",
+	"methods": "methods",
+}
+
+// httpReferenceDirs names each MDN directory in messages and points at
+// the IANA registry of its entries
+var httpReferenceDirs = map[string]struct{ noun, registry, registryURL string }{
+	"status":  {"status code", "IANA HTTP Status Code Registry", "https://www.iana.org/assignments/http-status-codes/http-status-codes.xhtml"},
+	"headers": {"header", "IANA HTTP Field Name Registry", "https://www.iana.org/assignments/http-fields/http-fields.xhtml"},
+	"methods": {"method", "IANA HTTP Method Registry", "https://www.iana.org/assignments/http-methods/http-methods.xhtml"},
+}
+
+// httpMethods are the methods MDN documents, to tell "GET" from a header
+var httpMethods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true, "DELETE": true,
+	"CONNECT": true, "OPTIONS": true, "TRACE": true, "PATCH": true,
+}
+
+var (
+	// mdnMacroPattern matches KumaScript macros such as {{HTTPStatus("404")}}
+	// and {{Specifications}}
+	mdnMacroPattern    = regexp.MustCompile(`\{\{\s*([\w-]+)\s*(?:\((.*?)\))?\s*\}\}`)
+	mdnMacroArgPattern = regexp.MustCompile(`"([^"]*)"|'([^']*)'|(\d+)`)
+	mdnLinkPattern     = regexp.MustCompile(`\]\(/en-US/docs/`)
+	blankLinesPattern  = regexp.MustCompile(`\n{3,}`)
+	rfcSpecURLPattern  = regexp.MustCompile(`rfc-editor\.org/rfc/rfc(\d+)(?:\.html)?(?:#(.+))?`)
+)
+
+type HTTPReferenceInfo struct {
+	// Kind is status, headers, or methods
+	Kind     string   `yaml:"kind"`
+	Name     string   `yaml:"name"`
+	Title    string   `yaml:"title"`
+	URL      string   `yaml:"url"`
+	SpecURLs []string `yaml:"specURLs"`
+	Content  string   `yaml:"-"`
+}
+
+type HTTPReferenceFetcher struct {
+	*BaseFetcher
+}
+
+func NewHTTPReferenceFetcher(cacheDir string) *HTTPReferenceFetcher {
+	return &HTTPReferenceFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchHTTPReference fetches MDN's reference page of an HTTP status code
+// ("404"), header ("Content-Type"), or method ("PATCH"), with links to the
+// RFC sections that specify it. Without a kind, it is told from the name.
+func (f *HTTPReferenceFetcher) FetchHTTPReference(kind, name string) (*HTTPReferenceInfo, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	dir := httpReferenceKinds[strings.ToLower(strings.TrimSpace(kind))]
+	switch {
+	case kind != "" && dir == "":
+		return nil, fmt.Errorf("unsupported kind %q (expected status, header, or method)", kind)
+	case dir == "":
+		dir = httpReferenceKind(name)
+	}
+
+	slug := strings.ToLower(name)
+	if dir == "status" {
+		// "404 Not Found" names the code too
+		slug, _, _ = strings.Cut(slug, " ")
+		if _, err := strconv.Atoi(slug); err != nil || len(slug) != 3 {
+			return nil, fmt.Errorf("invalid HTTP status code %q", name)
+		}
+	}
+	if strings.ContainsAny(slug, "/. ") {
+		return nil, fmt.Errorf("invalid HTTP %s %q", httpReferenceDirs[dir].noun, name)
+	}
+
+	return shareFetch(f.flights, flightKey("FetchHTTPReference", dir, slug), f.referencePath(dir, slug), func() (*HTTPReferenceInfo, error) {
+		return f.fetchHTTPReference(dir, slug)
+	})
+}
+
+// httpReferenceKind tells a status code or method from a header name
+func httpReferenceKind(name string) string {
+	code, _, _ := strings.Cut(name, " ")
+	if _, err := strconv.Atoi(code); err == nil {
+		return "status"
+	}
+	if httpMethods[name] {
+		return "methods"
+	}
+	return "headers"
+}
+
+// referencePath returns the cache path of a reference page
+func (f *HTTPReferenceFetcher) referencePath(dir, slug string) string {
+	return f.getCache().GetFilePath("web", "http", dir, fmt.Sprintf("%s.md", cache.EntryName(slug)))
+}
+
+func (f *HTTPReferenceFetcher) fetchHTTPReference(dir, slug string) (*HTTPReferenceInfo, error) {
+	// Check cache first
+	cachedPath := f.referencePath(dir, slug)
+	info, err := f.loadReferenceFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded HTTP reference '%s' from cache\n", info.Title)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching HTTP %s '%s' from MDN...\n", httpReferenceDirs[dir].noun, slug)
+
+	// MDN moved the HTTP reference under web/http/reference in 2025
+	var source string
+	for _, page := range []string{"web/http/reference/" + dir, "web/http/" + dir} {
+		source, err = f.fetchMDNSource(fmt.Sprintf("%s/%s/%s/index.md", mdnContentRawURL, page, slug))
+		if err != nil {
+			return nil, err
+		}
+		if source != "" {
+			break
+		}
+	}
+	if source == "" {
+		return nil, fmt.Errorf("HTTP %s %s not found on MDN", httpReferenceDirs[dir].noun, slug)
+	}
+
+	info = parseMDNPage(dir, slug, source)
+
+	if err := f.saveReferenceAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache HTTP reference: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// fetchMDNSource returns the markdown source of an MDN page, or "" if it
+// does not exist
+func (f *HTTPReferenceFetcher) fetchMDNSource(url string) (string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch MDN page: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("MDN source returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return string(body), nil
+}
+
+// parseMDNPage renders an MDN page from its front matter and markdown
+func parseMDNPage(dir, name, source string) *HTTPReferenceInfo {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+
+	var meta struct {
+		Title string `yaml:"title"`
+		Slug  string `yaml:"slug"`
+		// SpecURLs is a string or a list of strings
+		SpecURLs interface{} `yaml:"spec-urls"`
+	}
+	body := source
+	if strings.HasPrefix(source, "---\n") {
+		if front, rest, ok := strings.Cut(source[4:], "\n---\n"); ok {
+			_ = yaml.Unmarshal([]byte(front), &meta)
+			body = rest
+		}
+	}
+
+	info := &HTTPReferenceInfo{
+		Kind:  dir,
+		Name:  name,
+		Title: cmp.Or(meta.Title, name),
+		URL:   fmt.Sprintf("%s/%s", mdnDocsURL, meta.Slug),
+	}
+	if meta.Slug == "" {
+		info.URL = fmt.Sprintf("%s/Web/HTTP/Reference/%s/%s", mdnDocsURL, strings.ToUpper(dir[:1])+dir[1:], name)
+	}
+	switch urls := meta.SpecURLs.(type) {
+	case string:
+		info.SpecURLs = []string{urls}
+	case []interface{}:
+		for _, u := range urls {
+			if s, ok := u.(string); ok {
+				info.SpecURLs = append(info.SpecURLs, s)
+			}
+		}
+	}
+
+	info.Content = buildHTTPReferenceContent(info, mdnMarkdown(body))
+	return info
+}
+
+func buildHTTPReferenceContent(info *HTTPReferenceInfo, body string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Title)
+	fmt.Fprintf(&content, "**Reference:** [%s on MDN](%s)\n\n", info.Title, info.URL)
+
+	content.WriteString(strings.TrimSpace(truncateMarkdown(body, maxHTTPReferenceChars, "The page is truncated; see MDN for the rest.")))
+	content.WriteString("\n\n")
+
+	if len(info.SpecURLs) > 0 {
+		content.WriteString("## Specifications\n\n")
+		for _, u := range info.SpecURLs {
+			fmt.Fprintf(&content, "- [%s](%s)\n", specName(u), u)
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	dir := httpReferenceDirs[info.Kind]
+	fmt.Fprintf(&content, "- [HTTP %s reference on MDN](%s/Web/HTTP/Reference/%s)\n", dir.noun, mdnDocsURL, strings.ToUpper(info.Kind[:1])+info.Kind[1:])
+	fmt.Fprintf(&content, "- [%s](%s)\n", dir.registry, dir.registryURL)
+
+	return content.String()
+}
+
+// specName names the specification a spec URL points into, e.g.
+// "RFC 9110 (status.404)" for https://www.rfc-editor.org/rfc/rfc9110#status.404
+func specName(u string) string {
+	if m := rfcSpecURLPattern.FindStringSubmatch(u); m != nil {
+		if m[2] != "" {
+			return fmt.Sprintf("RFC %s (%s)", m[1], m[2])
+		}
+		return "RFC " + m[1]
+	}
+	return u
+}
+
+// mdnMarkdown turns MDN's markdown into plain markdown: KumaScript macros
+// become the text or links they render, site-relative links become
+// absolute, and the browser compatibility and specification sections,
+// which are tables generated from data, are dropped
+func mdnMarkdown(body string) string {
+	var out []string
+	skipping := false
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			heading := strings.ToLower(strings.TrimSpace(line[3:]))
+			skipping = heading == "browser compatibility" || heading == "specifications"
+		}
+		if skipping {
+			continue
+		}
+		out = append(out, line)
+	}
+	text := strings.Join(out, "\n")
+
+	text = mdnMacroPattern.ReplaceAllStringFunc(text, func(macro string) string {
+		m := mdnMacroPattern.FindStringSubmatch(macro)
+		var args []string
+		for _, a := range mdnMacroArgPattern.FindAllStringSubmatch(m[2], -1) {
+			args = append(args, a[1]+a[2]+a[3])
+		}
+		label := ""
+		if len(args) > 1 && args[1] != "" {
+			label = args[1]
+		} else if len(args) > 0 {
+			label = args[0]
+		}
+
+		switch strings.ToLower(m[1]) {
+		case "httpstatus", "httpheader", "httpmethod", "domxref", "jsxref", "cssxref", "htmlelement", "httpdirective":
+			if label == "" {
+				return ""
+			}
+			return "`" + label + "`"
+		case "glossary":
+			return label
+		case "rfc":
+			if len(args) == 0 {
+				return ""
+			}
+			return fmt.Sprintf("[RFC %s](https://www.rfc-editor.org/rfc/rfc%s)", args[0], args[0])
+		case "deprecated_header", "deprecated_inline":
+			return "**Deprecated.**"
+		case "non-standard_header", "non-standard_inline":
+			return "**Non-standard.**"
+		case "experimental_inline", "seecompattable":
+			return "**Experimental.**"
+		default:
+			// Sidebars, compatibility tables, and the like
+			return ""
+		}
+	})
+
+	text = mdnLinkPattern.ReplaceAllString(text, "]("+mdnDocsURL+"/")
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(text, "\n\n"))
+}
+
+func (f *HTTPReferenceFetcher) saveReferenceAsMarkdown(filePath string, info *HTTPReferenceInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "kind: \"%s\"\n", info.Kind)
+	fmt.Fprintf(&content, "name: \"%s\"\n", escapeYAML(info.Name))
+	fmt.Fprintf(&content, "title: \"%s\"\n", escapeYAML(info.Title))
+	fmt.Fprintf(&content, "url: \"%s\"\n", info.URL)
+	if len(info.SpecURLs) > 0 {
+		content.WriteString("specURLs:\n")
+		for _, u := range info.SpecURLs {
+			fmt.Fprintf(&content, "  - \"%s\"\n", escapeYAML(u))
+		}
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *HTTPReferenceFetcher) loadReferenceFromMarkdown(filePath string) (*HTTPReferenceInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta HTTPReferenceInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	meta.Content = strings.TrimSpace(parts[2])
+	return &meta, nil
+}
@@ -0,0 +1,490 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+const (
+	// javaCyclesURL lists the JDK release lines with their LTS status and
+	// support dates, as built by Eclipse Temurin
+	javaCyclesURL  = "https://endoflife.date/api/eclipse-temurin.json"
+	javaProjectURL = "https://openjdk.org/projects/jdk"
+	javaJEPBaseURL = "https://openjdk.org/jeps"
+	javaGitHubURL  = "https://github.com/openjdk"
+
+	// javaCyclesTTL keeps the release lines fresh enough to pick up
+	// quarterly updates without downloading them on every lookup
+	javaCyclesTTL = 24 * time.Hour
+)
+
+var (
+	// javaVersionPattern matches "21", "21.0.2", "jdk-17", "java 11",
+	// "1.8", and "8u392"
+	javaVersionPattern = regexp.MustCompile(`^(?i:(?:open)?jdk|java)?[\s-]*(?:1\.)?(\d+)(?:[.+u][\w.+-]*)?$`)
+	javaJEPLinkPattern = regexp.MustCompile(`/jeps/(\d+)/?$`)
+)
+
+type JavaInfo struct {
+	// Version is the release line ("21"), or "" for the overview of all
+	Version     string `yaml:"version"`
+	LTS         bool   `yaml:"lts"`
+	ReleaseDate string `yaml:"releaseDate"`
+	Latest      string `yaml:"latest"`
+	// Degraded lists the content checks the project page failed
+	Degraded []string `yaml:"degraded"`
+	Content  string   `yaml:"-"`
+}
+
+// javaCycle is a JDK release line listed by endoflife.date
+type javaCycle struct {
+	Cycle             string `json:"cycle"`
+	ReleaseDate       string `json:"releaseDate"`
+	LTS               bool   `json:"lts"`
+	Latest            string `json:"latest"`
+	LatestReleaseDate string `json:"latestReleaseDate"`
+	// EOL is the end-of-support date, or false when none is announced
+	EOL interface{} `json:"eol"`
+}
+
+// javaJEP is a JDK Enhancement Proposal delivered in a release
+type javaJEP struct {
+	Number string
+	Title  string
+}
+
+type JavaFetcher struct {
+	*BaseFetcher
+	cyclesCache *cache.Manager
+}
+
+func NewJavaFetcher(cacheDir string) *JavaFetcher {
+	return &JavaFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+		cyclesCache: newCacheManager(cacheDir, javaCyclesTTL),
+	}
+}
+
+// FetchJavaInfo describes a JDK release line ("21", "17.0.9", "1.8"): its
+// LTS status and support dates, the JEPs it delivered, and how to install
+// it. Without a version it lists every supported release line.
+func (f *JavaFetcher) FetchJavaInfo(version string) (*JavaInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchJavaInfo", version), "", func() (*JavaInfo, error) {
+		return f.fetchJavaInfo(version)
+	})
+}
+
+func (f *JavaFetcher) fetchJavaInfo(version string) (*JavaInfo, error) {
+	if strings.TrimSpace(version) == "" {
+		cycles, err := f.fetchCycles()
+		if err != nil {
+			return nil, err
+		}
+		return &JavaInfo{Content: buildJavaOverview(cycles, time.Now())}, nil
+	}
+
+	m := javaVersionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if m == nil {
+		return nil, fmt.Errorf("invalid Java version %q (expected e.g. '21', '17.0.9', or '1.8')", version)
+	}
+	releaseLine := m[1]
+
+	// Check cache first
+	cachedPath := f.getCache().GetFilePath("java", "versions", fmt.Sprintf("%s.md", cache.EntryName(releaseLine)))
+	info, err := f.loadJavaInfoFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Java %s info from cache\n", releaseLine)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Java %s information from endoflife.date and openjdk.org...\n", releaseLine)
+
+	// Either source describes the release line on its own: support data
+	// exists for released lines only, JEPs for lines from JDK 9 on
+	cycles, err := f.fetchCycles()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch Java release lines: %v\n", err)
+	}
+	var cycle *javaCycle
+	for i := range cycles {
+		if cycles[i].Cycle == releaseLine {
+			cycle = &cycles[i]
+		}
+	}
+
+	jeps, degraded, err := f.fetchJEPs(releaseLine)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch JEPs of JDK %s: %v\n", releaseLine, err)
+	}
+
+	if cycle == nil && len(jeps) == 0 {
+		return nil, fmt.Errorf("java %s not found", releaseLine)
+	}
+
+	info = &JavaInfo{Version: releaseLine, Degraded: degraded}
+	if cycle != nil {
+		info.LTS = cycle.LTS
+		info.ReleaseDate = cycle.ReleaseDate
+		info.Latest = cycle.Latest
+	}
+	info.Content = buildJavaContent(info, cycle, jeps, time.Now())
+
+	if err := f.saveJavaInfoAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// fetchCycles returns the JDK release lines, newest first
+func (f *JavaFetcher) fetchCycles() ([]javaCycle, error) {
+	cyclesPath := f.cyclesCache.GetFilePath("java", "cycles.json")
+
+	var cycles []javaCycle
+	if ok, err := f.cyclesCache.Load(cyclesPath, &cycles); err == nil && ok && len(cycles) > 0 {
+		return cycles, nil
+	}
+
+	body, status, err := f.get(javaCyclesURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Java release lines: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("endoflife.date returned status %d", status)
+	}
+	if err := json.Unmarshal(body, &cycles); err != nil {
+		return nil, fmt.Errorf("failed to parse Java release lines: %w", err)
+	}
+
+	sort.SliceStable(cycles, func(i, j int) bool {
+		a, _ := strconv.Atoi(cycles[i].Cycle)
+		b, _ := strconv.Atoi(cycles[j].Cycle)
+		return a > b
+	})
+
+	if err := f.cyclesCache.Save(cyclesPath, cycles); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache Java release lines: %v\n", err)
+	}
+	return cycles, nil
+}
+
+// fetchJEPs lists the JEPs of a release line from its OpenJDK project page,
+// along with the content checks the page failed
+func (f *JavaFetcher) fetchJEPs(releaseLine string) ([]javaJEP, []string, error) {
+	url := fmt.Sprintf("%s/%s/", javaProjectURL, releaseLine)
+	body, status, err := f.get(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, nil, nil
+	}
+	if status != http.StatusOK {
+		return nil, nil, fmt.Errorf("openjdk.org returned status %d", status)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	jeps := parseJavaJEPs(doc)
+
+	// Every release line since JDK 9 lists its JEPs on the project page
+	var degraded []string
+	if line, _ := strconv.Atoi(releaseLine); line >= 9 {
+		degraded = checkShape("java", url, shapeCheck{"jeps", len(jeps) > 0})
+	}
+	return jeps, degraded, nil
+}
+
+// parseJavaJEPs collects the links to JEPs on a project page. The feature
+// list links either the title ("430: <a>String Templates</a>") or the
+// number ("<a>430</a>: String Templates"), in table rows or list items.
+func parseJavaJEPs(doc *html.Node) []javaJEP {
+	var jeps []javaJEP
+	seen := make(map[string]bool)
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			for _, attr := range n.Attr {
+				m := javaJEPLinkPattern.FindStringSubmatch(attr.Val)
+				if attr.Key != "href" || m == nil || seen[m[1]] {
+					continue
+				}
+				// JEPs below 100 are the index and process JEPs, not features
+				if number, _ := strconv.Atoi(m[1]); number < 100 {
+					continue
+				}
+
+				title := collapseSpace(getText(n))
+				if title == m[1] || title == "JEP "+m[1] {
+					// The title follows the number in the same row or item
+					if container := javaJEPContainer(n); container != nil {
+						title = collapseSpace(getText(container))
+					}
+				}
+				if title = jepTitle(title, m[1]); title != "" {
+					seen[m[1]] = true
+					jeps = append(jeps, javaJEP{Number: m[1], Title: title})
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return jeps
+}
+
+// jepTitle strips the number from a feature line such as
+// "430: String Templates" or "JEP 430: String Templates"
+func jepTitle(text, number string) string {
+	text = strings.TrimSpace(strings.TrimPrefix(text, "JEP"))
+	text = strings.TrimSpace(strings.TrimPrefix(text, number))
+	return strings.TrimSpace(strings.TrimPrefix(text, ":"))
+}
+
+// javaJEPContainer returns the table row or list item holding a link
+func javaJEPContainer(n *html.Node) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && (p.Data == "tr" || p.Data == "li") {
+			return p
+		}
+	}
+	return nil
+}
+
+func (f *JavaFetcher) get(url string) ([]byte, int, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	return body, resp.StatusCode, err
+}
+
+// eolDate returns the end-of-support date of a release line, or "" when
+// none is announced
+func (c *javaCycle) eolDate() string {
+	date, _ := c.EOL.(string)
+	return date
+}
+
+// javaStatus derives the support phase of a release line at the given time
+func javaStatus(c *javaCycle, now time.Time) string {
+	if end, err := time.Parse("2006-01-02", c.eolDate()); err == nil && !now.Before(end) {
+		return "End of Support"
+	}
+	if eol, ok := c.EOL.(bool); ok && eol {
+		return "End of Support"
+	}
+	if c.LTS {
+		return "LTS"
+	}
+	return "Supported"
+}
+
+func buildJavaOverview(cycles []javaCycle, now time.Time) string {
+	var content strings.Builder
+
+	content.WriteString("# Java (JDK) Release Lines\n\n")
+	fmt.Fprintf(&content, "**As of:** %s\n\n", now.Format("2006-01-02"))
+
+	content.WriteString("| Release | LTS | Status | GA Date | Latest | Latest Date | End of Support |\n")
+	content.WriteString("|---------|-----|--------|---------|--------|-------------|----------------|\n")
+
+	recommended := ""
+	for i := range cycles {
+		c := &cycles[i]
+		status := javaStatus(c, now)
+		// Skip non-LTS lines past their support, which nobody should pick
+		if status == "End of Support" && !c.LTS {
+			continue
+		}
+		if status == "LTS" && recommended == "" {
+			recommended = c.Cycle
+		}
+		lts := "No"
+		if c.LTS {
+			lts = "Yes"
+		}
+		fmt.Fprintf(&content, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			c.Cycle, lts, status, orDash(c.ReleaseDate), orDash(c.Latest), orDash(c.LatestReleaseDate), orDash(c.eolDate()))
+	}
+	content.WriteString("\n")
+
+	content.WriteString("## Recommendation\n\n")
+	if recommended != "" {
+		fmt.Fprintf(&content, "Use **Java %s** (the newest LTS) for production workloads. ", recommended)
+	}
+	content.WriteString("Non-LTS releases are superseded every six months; LTS releases get updates for years. ")
+	content.WriteString("Support dates are those of the Eclipse Temurin builds; other vendors differ.\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("- [JDK Releases (openjdk.org)](https://openjdk.org/projects/jdk/)\n")
+	content.WriteString("- [Eclipse Temurin support (endoflife.date)](https://endoflife.date/eclipse-temurin)\n")
+
+	return content.String()
+}
+
+func buildJavaContent(info *JavaInfo, cycle *javaCycle, jeps []javaJEP, now time.Time) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Java %s\n\n", info.Version)
+
+	if cycle != nil {
+		if info.LTS {
+			content.WriteString("**LTS:** Yes\n\n")
+		} else {
+			content.WriteString("**LTS:** No\n\n")
+		}
+		fmt.Fprintf(&content, "**Status:** %s (as of %s)\n\n", javaStatus(cycle, now), now.Format("2006-01-02"))
+		if info.ReleaseDate != "" {
+			fmt.Fprintf(&content, "**GA Date:** %s\n\n", info.ReleaseDate)
+		}
+		if info.Latest != "" {
+			fmt.Fprintf(&content, "**Latest Update:** %s", info.Latest)
+			if cycle.LatestReleaseDate != "" {
+				fmt.Fprintf(&content, " (%s)", cycle.LatestReleaseDate)
+			}
+			content.WriteString("\n\n")
+		}
+		if eol := cycle.eolDate(); eol != "" {
+			fmt.Fprintf(&content, "**End of Support:** %s (Eclipse Temurin)\n\n", eol)
+		}
+	} else {
+		content.WriteString("**Status:** Not released yet\n\n")
+	}
+
+	if len(jeps) > 0 {
+		content.WriteString("## Features (JEPs)\n\n")
+		for _, jep := range jeps {
+			fmt.Fprintf(&content, "- [JEP %s](%s/%s): %s\n", jep.Number, javaJEPBaseURL, jep.Number, jep.Title)
+		}
+		content.WriteString("\n")
+	}
+
+	if cycle != nil {
+		content.WriteString("## Installation\n\n")
+		content.WriteString("### Using SDKMAN!\n\n")
+		content.WriteString("```bash\n")
+		if identifier := sdkmanIdentifier(info.Latest); identifier != "" {
+			fmt.Fprintf(&content, "sdk install java %s\n", identifier)
+		} else {
+			fmt.Fprintf(&content, "sdk list java | grep -- '%s\\.'\n", info.Version)
+		}
+		content.WriteString("```\n\n")
+
+		content.WriteString("### Using apt (Debian/Ubuntu)\n\n")
+		content.WriteString("```bash\n")
+		fmt.Fprintf(&content, "sudo apt install openjdk-%s-jdk\n", info.Version)
+		content.WriteString("```\n\n")
+
+		content.WriteString("### Using Homebrew\n\n")
+		content.WriteString("```bash\n")
+		fmt.Fprintf(&content, "brew install openjdk@%s\n", info.Version)
+		content.WriteString("```\n\n")
+
+		content.WriteString("### Using Docker\n\n")
+		content.WriteString("```bash\n")
+		fmt.Fprintf(&content, "docker pull eclipse-temurin:%s-jdk\n", info.Version)
+		content.WriteString("```\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [JDK %s project page](%s/%s/)\n", info.Version, javaProjectURL, info.Version)
+	if line, _ := strconv.Atoi(info.Version); line < 11 {
+		fmt.Fprintf(&content, "- [JDK %s API documentation](https://docs.oracle.com/javase/%s/docs/api/)\n", info.Version, info.Version)
+	} else {
+		fmt.Fprintf(&content, "- [JDK %s API documentation](https://docs.oracle.com/en/java/javase/%s/docs/api/)\n", info.Version, info.Version)
+	}
+	fmt.Fprintf(&content, "- [JDK %s updates repository](%s/jdk%su)\n", info.Version, javaGitHubURL, info.Version)
+
+	return content.String()
+}
+
+// sdkmanIdentifier returns the SDKMAN! identifier of a Temurin build, e.g.
+// "21.0.5-tem" for "21.0.5+11"
+func sdkmanIdentifier(latest string) string {
+	version, _, _ := strings.Cut(latest, "+")
+	if version == "" {
+		return ""
+	}
+	return version + "-tem"
+}
+
+func (f *JavaFetcher) saveJavaInfoAsMarkdown(filePath string, info *JavaInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "lts: %t\n", info.LTS)
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	if info.Latest != "" {
+		fmt.Fprintf(&content, "latest: \"%s\"\n", info.Latest)
+	}
+	writeDegraded(&content, info.Degraded)
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *JavaFetcher) loadJavaInfoFromMarkdown(filePath string) (*JavaInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info JavaInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if degradedExpired(f.getCache(), filePath, info.Degraded) {
+		return nil, fmt.Errorf("degraded entry expired")
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -6,19 +6,18 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"golang.org/x/net/html"
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/semver"
 )
 
-type JenkinsVersionInfo struct {
-	Version     string `yaml:"version"`
-	ReleaseDate string `yaml:"releaseDate"`
-	ReleaseURL  string `yaml:"releaseURL"`
-	Content     string `yaml:"-"`
-}
+type JenkinsVersionInfo = ReleaseVersionInfo
 
 type JenkinsFetcher struct {
 	*BaseFetcher
@@ -32,6 +31,17 @@ func NewJenkinsFetcher(cacheDir string) *JenkinsFetcher {
 
 // FetchJenkinsVersion fetches information about a specific Jenkins version
 func (f *JenkinsFetcher) FetchJenkinsVersion(version string) (*JenkinsVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchJenkinsVersion", version), f.versionPath(version), func() (*JenkinsVersionInfo, error) {
+		return f.fetchJenkinsVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Jenkins version
+func (f *JenkinsFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("jenkins", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *JenkinsFetcher) fetchJenkinsVersion(version string) (*JenkinsVersionInfo, error) {
 	// Normalize version (Jenkins uses format like "2.440.3" or with "jenkins-" prefix)
 	githubVersion := version
 	if !strings.HasPrefix(version, "jenkins-") {
@@ -39,7 +49,7 @@ func (f *JenkinsFetcher) FetchJenkinsVersion(version string) (*JenkinsVersionInf
 	}
 
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("jenkins", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
 	if err == nil && versionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Jenkins version '%s' from cache\n", version)
@@ -79,42 +89,13 @@ func (f *JenkinsFetcher) FetchJenkinsVersion(version string) (*JenkinsVersionInf
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse GitHub release data
-	var releaseData map[string]interface{}
-	if err := json.Unmarshal(body, &releaseData); err != nil {
-		return nil, fmt.Errorf("failed to parse release data: %w", err)
-	}
-
-	// Extract version information
-	versionInfo = &JenkinsVersionInfo{
-		Version: version,
-	}
-
-	// Extract release URL
-	if htmlURL, ok := releaseData["html_url"].(string); ok {
-		versionInfo.ReleaseURL = htmlURL
-	}
-
-	// Extract release date
-	if publishedAt, ok := releaseData["published_at"].(string); ok {
-		// Parse and format the date
-		if t, err := time.Parse(time.RFC3339, publishedAt); err == nil {
-			versionInfo.ReleaseDate = t.Format("2006-01-02")
-		} else {
-			versionInfo.ReleaseDate = publishedAt
-		}
-	}
-
-	// Extract release notes from body
-	releaseNotes := ""
-	if body, ok := releaseData["body"].(string); ok {
-		releaseNotes = body
+	versionInfo, err = renderReleaseVersion(version, body, f.buildVersionContent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build content
-	versionInfo.Content = f.buildVersionContent(versionInfo, releaseNotes)
-
-	// Cache the result
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
 	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
 	}
@@ -178,17 +159,12 @@ func (f *JenkinsFetcher) buildVersionContent(info *JenkinsVersionInfo, releaseNo
 }
 
 func (f *JenkinsFetcher) saveVersionInfoAsMarkdown(filePath string, info *JenkinsVersionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
 	if info.ReleaseDate != "" {
 		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
 	}
@@ -200,16 +176,16 @@ func (f *JenkinsFetcher) saveVersionInfoAsMarkdown(filePath string, info *Jenkin
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *JenkinsFetcher) loadVersionInfoFromMarkdown(filePath string) (*JenkinsVersionInfo, error) {
-	expired, err := f.getCache().IsExpired(filePath)
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
 	if err != nil || expired {
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -224,12 +200,23 @@ func (f *JenkinsFetcher) loadVersionInfoFromMarkdown(filePath string) (*JenkinsV
 		Version     string `yaml:"version"`
 		ReleaseDate string `yaml:"releaseDate"`
 		ReleaseURL  string `yaml:"releaseURL"`
+		Template    int    `yaml:"template"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if info, err := renderReleaseVersion(meta.Version, payload, f.buildVersionContent); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered Jenkins version '%s' with current templates\n", meta.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
 	return &JenkinsVersionInfo{
 		Version:     meta.Version,
 		ReleaseDate: meta.ReleaseDate,
@@ -237,3 +224,524 @@ func (f *JenkinsFetcher) loadVersionInfoFromMarkdown(filePath string) (*JenkinsV
 		Content:     strings.TrimSpace(parts[2]),
 	}, nil
 }
+
+const jenkinsPluginsAPIURL = "https://plugins.jenkins.io/api/plugin"
+
+type JenkinsPluginInfo struct {
+	Name         string              `yaml:"name"`
+	Title        string              `yaml:"title"`
+	Version      string              `yaml:"version"`
+	RequiredCore string              `yaml:"requiredCore"`
+	Description  string              `yaml:"description"`
+	ReleaseDate  string              `yaml:"releaseDate"`
+	Repository   string              `yaml:"repository"`
+	Installs     int                 `yaml:"installs"`
+	Versions     []string            `yaml:"-"`
+	Dependencies []JenkinsPluginDep  `yaml:"-"`
+	Steps        []JenkinsPluginStep `yaml:"-"`
+	Warnings     []string            `yaml:"-"`
+	// Degraded lists the content checks the steps page failed
+	Degraded []string `yaml:"degraded"`
+	Content  string   `yaml:"-"`
+}
+
+// JenkinsPluginDep is a plugin another plugin depends on
+type JenkinsPluginDep struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Optional bool   `json:"optional"`
+}
+
+// JenkinsPluginStep is a Pipeline step contributed by a plugin
+type JenkinsPluginStep struct {
+	Name       string
+	Title      string
+	Parameters []string
+}
+
+// FetchPlugin fetches information about a Jenkins plugin from plugins.jenkins.io
+func (f *JenkinsFetcher) FetchPlugin(name, version string) (*JenkinsPluginInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchPlugin", name, version), f.pluginPath(name, version), func() (*JenkinsPluginInfo, error) {
+		return f.fetchPlugin(name, version)
+	})
+}
+
+// pluginPath returns the cache path of the info on a plugin
+func (f *JenkinsFetcher) pluginPath(name, version string) string {
+	return f.getCache().GetFilePath("jenkins", "plugins", fmt.Sprintf("%s.md", cache.EntryName(name, version)))
+}
+
+func (f *JenkinsFetcher) fetchPlugin(name, version string) (*JenkinsPluginInfo, error) {
+	// Check cache first
+	cachedPath := f.pluginPath(name, version)
+	pluginInfo, err := f.loadPluginFromMarkdown(cachedPath)
+	if err == nil && pluginInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Jenkins plugin '%s' from cache\n", name)
+		return pluginInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Jenkins plugin '%s' from plugins.jenkins.io...\n", name)
+
+	var plugin struct {
+		Name             string             `json:"name"`
+		Title            string             `json:"title"`
+		Version          string             `json:"version"`
+		RequiredCore     string             `json:"requiredCore"`
+		Excerpt          string             `json:"excerpt"`
+		ReleaseTimestamp string             `json:"releaseTimestamp"`
+		SCM              json.RawMessage    `json:"scm"`
+		Dependencies     []JenkinsPluginDep `json:"dependencies"`
+		Stats            struct {
+			CurrentInstalls int `json:"currentInstalls"`
+		} `json:"stats"`
+		SecurityWarnings []struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+			URL     string `json:"url"`
+			Active  bool   `json:"active"`
+		} `json:"securityWarnings"`
+	}
+	found, err := f.getPluginJSON(fmt.Sprintf("%s/%s", jenkinsPluginsAPIURL, name), &plugin)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("jenkins plugin %s not found", name)
+	}
+
+	pluginInfo = &JenkinsPluginInfo{
+		Name:         plugin.Name,
+		Title:        plugin.Title,
+		Version:      plugin.Version,
+		RequiredCore: plugin.RequiredCore,
+		Description:  htmlText(plugin.Excerpt),
+		ReleaseDate:  formatPluginDate(plugin.ReleaseTimestamp),
+		Repository:   pluginSCM(plugin.SCM),
+		Installs:     plugin.Stats.CurrentInstalls,
+		Dependencies: plugin.Dependencies,
+	}
+
+	for _, w := range plugin.SecurityWarnings {
+		if w.Active {
+			pluginInfo.Warnings = append(pluginInfo.Warnings, fmt.Sprintf("[%s](%s): %s", w.ID, w.URL, w.Message))
+		}
+	}
+
+	var history struct {
+		Versions map[string]struct {
+			Version          string             `json:"version"`
+			RequiredCore     string             `json:"requiredCore"`
+			ReleaseTimestamp string             `json:"releaseTimestamp"`
+			Dependencies     []JenkinsPluginDep `json:"dependencies"`
+		} `json:"versions"`
+	}
+	if _, err := f.getPluginJSON(fmt.Sprintf("%s/%s/versions", jenkinsPluginsAPIURL, name), &history); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch plugin versions: %v\n", err)
+	}
+	for v := range history.Versions {
+		pluginInfo.Versions = append(pluginInfo.Versions, v)
+	}
+	sort.Slice(pluginInfo.Versions, func(i, j int) bool {
+		return semver.Compare(pluginInfo.Versions[i], pluginInfo.Versions[j]) > 0
+	})
+
+	// Older releases take their core requirement and dependencies from the version history
+	if version != "" && version != pluginInfo.Version {
+		release, ok := history.Versions[version]
+		if !ok {
+			return nil, fmt.Errorf("jenkins plugin %s version %s not found", name, version)
+		}
+		pluginInfo.Version = version
+		pluginInfo.RequiredCore = release.RequiredCore
+		pluginInfo.ReleaseDate = formatPluginDate(release.ReleaseTimestamp)
+		pluginInfo.Dependencies = release.Dependencies
+	}
+
+	sort.Slice(pluginInfo.Dependencies, func(i, j int) bool {
+		return pluginInfo.Dependencies[i].Name < pluginInfo.Dependencies[j].Name
+	})
+
+	// Pipeline steps are only documented on jenkins.io; plugins without steps have no page
+	steps, degraded, err := f.fetchPipelineSteps(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch pipeline steps: %v\n", err)
+	}
+	pluginInfo.Steps = steps
+	pluginInfo.Degraded = degraded
+
+	// Build content
+	pluginInfo.Content = f.buildPluginContent(pluginInfo)
+
+	// Cache the result
+	if err := f.savePluginAsMarkdown(cachedPath, pluginInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache plugin info: %v\n", err)
+	}
+
+	return pluginInfo, nil
+}
+
+// getPluginJSON fetches a plugins.jenkins.io API resource, reporting false without error when it does not exist
+func (f *JenkinsFetcher) getPluginJSON(url string, v interface{}) (bool, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch Jenkins plugin: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("plugins API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to parse plugin data: %w", err)
+	}
+
+	return true, nil
+}
+
+// fetchPipelineSteps reads the steps a plugin adds from its Pipeline Steps
+// Reference page, where each step is an <h3><code>name</code>: Title</h3>
+// followed by a list of its parameters. It also returns the content checks
+// the page failed.
+func (f *JenkinsFetcher) fetchPipelineSteps(name string) ([]JenkinsPluginStep, []string, error) {
+	url := fmt.Sprintf("https://www.jenkins.io/doc/pipeline/steps/%s/", name)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("jenkins.io returned status %d", resp.StatusCode)
+	}
+
+	doc, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse steps page: %w", err)
+	}
+
+	var steps []JenkinsPluginStep
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "h3" {
+			if code := firstElement(n, "code"); code != nil {
+				step := JenkinsPluginStep{
+					Name:  strings.TrimSpace(getText(code)),
+					Title: strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(getText(n), getText(code))), ":")),
+				}
+				step.Parameters = stepParameters(n)
+				steps = append(steps, step)
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	// The page exists only for plugins that add steps
+	return steps, checkShape("jenkins", url, shapeCheck{"steps", len(steps) > 0}), nil
+}
+
+// stepParameters returns the top-level parameters ("url : String") listed
+// after a step heading, up to the next heading
+func stepParameters(heading *html.Node) []string {
+	for n := heading.NextSibling; n != nil; n = n.NextSibling {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		if n.Data == "h2" || n.Data == "h3" {
+			return nil
+		}
+		list := n
+		if n.Data != "ul" {
+			list = firstElement(n, "ul")
+		}
+		if list == nil {
+			continue
+		}
+
+		var params []string
+		for li := list.FirstChild; li != nil; li = li.NextSibling {
+			if li.Type != html.ElementNode || li.Data != "li" {
+				continue
+			}
+			if code := firstElement(li, "code"); code != nil {
+				params = append(params, strings.Join(strings.Fields(getText(code)), " "))
+			}
+		}
+		return params
+	}
+	return nil
+}
+
+// firstElement returns the first descendant of n with the given tag
+func firstElement(n *html.Node, tag string) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag {
+			return c
+		}
+		if found := firstElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// htmlText returns the text of an HTML fragment with whitespace collapsed
+func htmlText(fragment string) string {
+	doc, err := html.Parse(strings.NewReader(fragment))
+	if err != nil {
+		return fragment
+	}
+	return strings.Join(strings.Fields(getText(doc)), " ")
+}
+
+func formatPluginDate(timestamp string) string {
+	if t, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return timestamp
+}
+
+// pluginSCM reads the scm field, which is a URL string or an object with a link
+func pluginSCM(raw json.RawMessage) string {
+	var link string
+	if err := json.Unmarshal(raw, &link); err == nil {
+		return link
+	}
+	var scm struct {
+		Link string `json:"link"`
+	}
+	if err := json.Unmarshal(raw, &scm); err == nil {
+		return scm.Link
+	}
+	return ""
+}
+
+func (f *JenkinsFetcher) buildPluginContent(info *JenkinsPluginInfo) string {
+	var content strings.Builder
+
+	title := info.Title
+	if title == "" {
+		title = info.Name
+	}
+	fmt.Fprintf(&content, "# %s (%s)\n\n", title, info.Name)
+
+	if info.Description != "" {
+		fmt.Fprintf(&content, "**Description:** %s\n\n", info.Description)
+	}
+
+	fmt.Fprintf(&content, "**Version:** %s\n\n", info.Version)
+
+	if info.RequiredCore != "" {
+		fmt.Fprintf(&content, "**Required Jenkins Core:** %s or newer\n\n", info.RequiredCore)
+	}
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+
+	if info.Installs > 0 {
+		fmt.Fprintf(&content, "**Installations:** %d\n\n", info.Installs)
+	}
+
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "**Repository:** %s\n\n", info.Repository)
+	}
+
+	if len(info.Versions) > 0 {
+		shown := info.Versions
+		if len(shown) > 10 {
+			shown = shown[:10]
+		}
+		fmt.Fprintf(&content, "**Recent Versions:** %s\n\n", strings.Join(shown, ", "))
+	}
+
+	if len(info.Warnings) > 0 {
+		content.WriteString("## Security Warnings\n\n")
+		for _, w := range info.Warnings {
+			fmt.Fprintf(&content, "- %s\n", w)
+		}
+		content.WriteString("\n")
+	}
+
+	if len(info.Dependencies) > 0 {
+		content.WriteString("## Dependencies\n\n")
+		for _, dep := range info.Dependencies {
+			fmt.Fprintf(&content, "- `%s` %s", dep.Name, dep.Version)
+			if dep.Optional {
+				content.WriteString(" (optional)")
+			}
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("### Plugin Installation Manager\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "jenkins-plugin-cli --plugins %s:%s\n", info.Name, info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### plugins.txt (Docker image)\n\n")
+	content.WriteString("```text\n")
+	fmt.Fprintf(&content, "%s:%s\n", info.Name, info.Version)
+	content.WriteString("```\n\n")
+
+	if len(info.Steps) > 0 {
+		content.WriteString("## Pipeline Steps\n\n")
+		for _, step := range info.Steps {
+			fmt.Fprintf(&content, "### `%s`", step.Name)
+			if step.Title != "" {
+				fmt.Fprintf(&content, ": %s", step.Title)
+			}
+			content.WriteString("\n\n")
+			if len(step.Parameters) > 0 {
+				for _, p := range step.Parameters {
+					fmt.Fprintf(&content, "- `%s`\n", p)
+				}
+				content.WriteString("\n")
+			}
+		}
+
+		step := info.Steps[0]
+		content.WriteString("### Usage\n\n")
+		content.WriteString("```groovy\n")
+		content.WriteString("pipeline {\n")
+		content.WriteString("    agent any\n")
+		content.WriteString("    stages {\n")
+		content.WriteString("        stage('Example') {\n")
+		content.WriteString("            steps {\n")
+		fmt.Fprintf(&content, "                %s\n", stepSnippet(step))
+		content.WriteString("            }\n")
+		content.WriteString("        }\n")
+		content.WriteString("    }\n")
+		content.WriteString("}\n")
+		content.WriteString("```\n\n")
+		content.WriteString("Generate the exact syntax for your job with the Snippet Generator at `$JENKINS_URL/pipeline-syntax/`.\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [Plugin Page](https://plugins.jenkins.io/%s/)\n", info.Name)
+	if len(info.Steps) > 0 {
+		fmt.Fprintf(&content, "- [Pipeline Steps Reference](https://www.jenkins.io/doc/pipeline/steps/%s/)\n", info.Name)
+	}
+	fmt.Fprintf(&content, "- [Release History](https://plugins.jenkins.io/%s/releases/)\n", info.Name)
+
+	return content.String()
+}
+
+// stepSnippet renders a step call with placeholders for its first parameters
+func stepSnippet(step JenkinsPluginStep) string {
+	var args []string
+	for _, p := range step.Parameters {
+		if len(args) == 2 {
+			break
+		}
+		param, kind, _ := strings.Cut(p, ":")
+		param, kind = strings.TrimSpace(param), strings.TrimSpace(kind)
+		switch {
+		case strings.HasPrefix(kind, "boolean"):
+			args = append(args, fmt.Sprintf("%s: true", param))
+		case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "long"):
+			args = append(args, fmt.Sprintf("%s: 1", param))
+		default:
+			args = append(args, fmt.Sprintf("%s: '...'", param))
+		}
+	}
+	if len(args) == 0 {
+		return step.Name + "()"
+	}
+	return fmt.Sprintf("%s(%s)", step.Name, strings.Join(args, ", "))
+}
+
+func (f *JenkinsFetcher) savePluginAsMarkdown(filePath string, info *JenkinsPluginInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "name: \"%s\"\n", info.Name)
+	if info.Title != "" {
+		fmt.Fprintf(&content, "title: \"%s\"\n", escapeYAML(info.Title))
+	}
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	if info.RequiredCore != "" {
+		fmt.Fprintf(&content, "requiredCore: \"%s\"\n", info.RequiredCore)
+	}
+	if info.Description != "" {
+		fmt.Fprintf(&content, "description: \"%s\"\n", escapeYAML(info.Description))
+	}
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "repository: \"%s\"\n", info.Repository)
+	}
+	if info.Installs > 0 {
+		fmt.Fprintf(&content, "installs: %d\n", info.Installs)
+	}
+	writeDegraded(&content, info.Degraded)
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *JenkinsFetcher) loadPluginFromMarkdown(filePath string) (*JenkinsPluginInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info JenkinsPluginInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if degradedExpired(f.getCache(), filePath, info.Degraded) {
+		return nil, fmt.Errorf("degraded entry expired")
+	}
+
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
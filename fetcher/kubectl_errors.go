@@ -0,0 +1,180 @@
+package fetcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const kubernetesDocsURL = "https://kubernetes.io/docs"
+
+// kubectlError is a common error of kubectl or a pod status it reports,
+// with its usual causes and the commands that narrow them down
+type kubectlError struct {
+	name        string
+	pattern     *regexp.Regexp
+	explanation string
+	commands    []string
+	// docs is relative to kubernetesDocsURL
+	docs string
+}
+
+// kubectlErrors is curated: Kubernetes publishes no catalog of its error
+// messages, so each entry points at the documentation page that covers it
+var kubectlErrors = []kubectlError{
+	{
+		name:        "ImagePullBackOff",
+		pattern:     regexp.MustCompile(`ImagePullBackOff|ErrImagePull|InvalidImageName|pull access denied|manifest unknown`),
+		explanation: "The kubelet cannot pull the container image and is backing off between retries. The image name or tag is misspelled or does not exist, the registry needs credentials the pod does not have (imagePullSecrets), or the node cannot reach the registry.",
+		commands:    []string{"kubectl describe pod <pod>", "kubectl get pod <pod> -o jsonpath='{.spec.containers[*].image}'", "kubectl get secret <pull-secret> -o yaml"},
+		docs:        "/concepts/containers/images/",
+	},
+	{
+		name:        "CrashLoopBackOff",
+		pattern:     regexp.MustCompile(`CrashLoopBackOff|Back-off restarting failed container`),
+		explanation: "The container starts and exits again, and the kubelet waits longer between each restart. The application fails at startup: a missing configuration or dependency, a wrong command or arguments, a failing liveness probe, or running out of memory.",
+		commands:    []string{"kubectl logs <pod> --previous", "kubectl describe pod <pod>", "kubectl get pod <pod> -o jsonpath='{.status.containerStatuses[*].lastState}'"},
+		docs:        "/tasks/debug/debug-application/debug-pods/",
+	},
+	{
+		name:        "OOMKilled",
+		pattern:     regexp.MustCompile(`OOMKilled|exit code 137`),
+		explanation: "The container used more memory than its limit and the kernel killed it. Raise resources.limits.memory, or find what makes the application use more memory than expected.",
+		commands:    []string{"kubectl describe pod <pod>", "kubectl top pod <pod> --containers"},
+		docs:        "/concepts/configuration/manage-resources-containers/",
+	},
+	{
+		name:        "CreateContainerConfigError",
+		pattern:     regexp.MustCompile(`CreateContainerConfigError|configmap .* not found|secret .* not found|couldn't find key`),
+		explanation: "The kubelet cannot build the container's configuration, usually because a ConfigMap, Secret, or key the pod references in env, envFrom, or volumes does not exist in the pod's namespace.",
+		commands:    []string{"kubectl describe pod <pod>", "kubectl get configmap,secret -n <namespace>"},
+		docs:        "/concepts/configuration/configmap/",
+	},
+	{
+		name:        "FailedScheduling",
+		pattern:     regexp.MustCompile(`FailedScheduling|Insufficient (?:cpu|memory)|didn't match Pod's node affinity|untolerated taint|nodes are available`),
+		explanation: "The scheduler found no node for the pod: the nodes lack the CPU or memory it requests, its node selector or affinity matches no node, or the nodes carry taints it does not tolerate.",
+		commands:    []string{"kubectl describe pod <pod>", "kubectl describe nodes", "kubectl get nodes --show-labels"},
+		docs:        "/concepts/scheduling-eviction/assign-pod-node/",
+	},
+	{
+		name:        "UnboundPersistentVolumeClaim",
+		pattern:     regexp.MustCompile(`unbound immediate PersistentVolumeClaims|waiting for a volume to be created|no persistent volumes available`),
+		explanation: "The pod waits for a PersistentVolumeClaim that is not bound to a volume: no PersistentVolume matches it, or its StorageClass does not exist or cannot provision one.",
+		commands:    []string{"kubectl get pvc", "kubectl describe pvc <claim>", "kubectl get storageclass"},
+		docs:        "/concepts/storage/persistent-volumes/",
+	},
+	{
+		name:        "Evicted",
+		pattern:     regexp.MustCompile(`\bEvicted\b|The node was low on resource`),
+		explanation: "The kubelet evicted the pod to reclaim memory, disk, or process IDs on a node under pressure. Pods using more than they request are evicted first.",
+		commands:    []string{"kubectl describe pod <pod>", "kubectl describe node <node>"},
+		docs:        "/concepts/scheduling-eviction/node-pressure-eviction/",
+	},
+	{
+		name:        "Forbidden",
+		pattern:     regexp.MustCompile(`Forbidden|forbidden: User|cannot (?:get|list|watch|create|update|patch|delete) resource`),
+		explanation: "The API server authenticated the request but RBAC does not allow it: no Role or ClusterRole bound to the user or service account grants the verb on the resource in that namespace.",
+		commands:    []string{"kubectl auth can-i <verb> <resource> -n <namespace>", "kubectl auth whoami", "kubectl get rolebindings,clusterrolebindings -A -o wide"},
+		docs:        "/reference/access-authn-authz/rbac/",
+	},
+	{
+		name:        "Unauthorized",
+		pattern:     regexp.MustCompile(`You must be logged in to the server|\(Unauthorized\)`),
+		explanation: "The API server rejected the credentials in the kubeconfig: the token or client certificate expired, or belongs to another cluster.",
+		commands:    []string{"kubectl config current-context", "kubectl config view --minify"},
+		docs:        "/tasks/access-application-cluster/configure-access-multiple-clusters/",
+	},
+	{
+		name:        "ConnectionRefused",
+		pattern:     regexp.MustCompile(`The connection to the server .* was refused|Unable to connect to the server|dial tcp .*: i/o timeout|no such host`),
+		explanation: "kubectl cannot reach the API server named in the current context: the cluster is stopped, the context points at the wrong cluster, or a VPN, proxy, or firewall is in the way. Without a kubeconfig, kubectl falls back to localhost:8080.",
+		commands:    []string{"kubectl config current-context", "kubectl config view --minify", "kubectl cluster-info"},
+		docs:        "/tasks/access-application-cluster/configure-access-multiple-clusters/",
+	},
+	{
+		name:        "CertificateError",
+		pattern:     regexp.MustCompile(`x509: certificate|certificate signed by unknown authority|certificate has expired`),
+		explanation: "kubectl does not trust the API server's certificate: the kubeconfig holds another cluster's certificate authority, or the certificate expired or does not name the address used.",
+		commands:    []string{"kubectl config view --minify --raw", "kubectl config current-context"},
+		docs:        "/tasks/access-application-cluster/configure-access-multiple-clusters/",
+	},
+	{
+		name:        "NoMatchesForKind",
+		pattern:     regexp.MustCompile(`no matches for kind|doesn't have a resource type|ensure CRDs are installed`),
+		explanation: "The cluster does not serve the kind in that API version: the API version was removed in this Kubernetes release, or the CustomResourceDefinition is not installed.",
+		commands:    []string{"kubectl api-resources", "kubectl api-versions", "kubectl get crd"},
+		docs:        "/reference/using-api/deprecation-guide/",
+	},
+	{
+		name:        "FieldIsImmutable",
+		pattern:     regexp.MustCompile(`field is immutable`),
+		explanation: "The change touches a field that cannot be updated once the object exists, such as a Deployment's selector or a Job's template. Delete and recreate the object, or create it under a new name.",
+		commands:    []string{"kubectl diff -f <file>", "kubectl replace --force -f <file>"},
+		docs:        "/concepts/overview/working-with-objects/",
+	},
+	{
+		name:        "ExceededQuota",
+		pattern:     regexp.MustCompile(`exceeded quota|must specify (?:limits|requests)`),
+		explanation: "A ResourceQuota in the namespace refuses the object: creating it would exceed the quota, or the quota requires requests and limits the pod does not set.",
+		commands:    []string{"kubectl describe resourcequota -n <namespace>", "kubectl get limitrange -n <namespace>"},
+		docs:        "/concepts/policy/resource-quotas/",
+	},
+	{
+		name:        "UnknownField",
+		pattern:     regexp.MustCompile(`error validating data|unknown field|strict decoding error`),
+		explanation: "The manifest does not match the schema of the kind: a field is misspelled, nested at the wrong level, or not supported by the cluster's version of the API.",
+		commands:    []string{"kubectl explain <kind>.<field>", "kubectl apply --dry-run=server -f <file>"},
+		docs:        "/reference/using-api/api-concepts/",
+	},
+	{
+		name:        "NotFound",
+		pattern:     regexp.MustCompile(`\(NotFound\)|not found`),
+		explanation: "The object does not exist in the namespace kubectl used: check the name, and pass -n or set the context's namespace.",
+		commands:    []string{"kubectl get <kind> -A | grep <name>", "kubectl config view --minify -o jsonpath='{..namespace}'"},
+		docs:        "/concepts/overview/working-with-objects/namespaces/",
+	},
+	{
+		name:        "AlreadyExists",
+		pattern:     regexp.MustCompile(`\(AlreadyExists\)|already exists`),
+		explanation: "kubectl create refuses to overwrite an existing object. Use kubectl apply to update it, or delete it first.",
+		commands:    []string{"kubectl apply -f <file>", "kubectl get <kind> <name> -o yaml"},
+		docs:        "/concepts/overview/working-with-objects/object-management/",
+	},
+}
+
+// matchKubectlError finds the first curated error the message matches
+func matchKubectlError(message string) *kubectlError {
+	for i, e := range kubectlErrors {
+		if strings.EqualFold(strings.TrimSpace(message), e.name) || e.pattern.MatchString(message) {
+			return &kubectlErrors[i]
+		}
+	}
+	return nil
+}
+
+func explainKubectlError(message string) (*ErrorExplanation, error) {
+	e := matchKubectlError(message)
+	if e == nil {
+		return nil, fmt.Errorf("no known kubectl error matches %q", message)
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# kubectl: %s\n\n", e.name)
+	if !strings.EqualFold(strings.TrimSpace(message), e.name) {
+		fmt.Fprintf(&content, "**Message:** `%s`\n\n", strings.TrimSpace(message))
+	}
+	content.WriteString(e.explanation)
+	content.WriteString("\n\n")
+
+	content.WriteString("## Diagnose\n\n")
+	content.WriteString("```bash\n")
+	for _, c := range e.commands {
+		content.WriteString(c + "\n")
+	}
+	content.WriteString("```\n\n")
+
+	fmt.Fprintf(&content, "**Reference:** [Kubernetes documentation](%s%s)\n", kubernetesDocsURL, e.docs)
+
+	return &ErrorExplanation{Tool: "kubectl", Code: e.name, Content: content.String()}, nil
+}
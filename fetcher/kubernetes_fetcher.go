@@ -1,24 +1,18 @@
 package fetcher
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
-	"time"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
 )
 
-type KubernetesVersionInfo struct {
-	Version     string `yaml:"version"`
-	ReleaseDate string `yaml:"releaseDate"`
-	ReleaseURL  string `yaml:"releaseURL"`
-	Content     string `yaml:"-"`
-}
+type KubernetesVersionInfo = ReleaseVersionInfo
 
 type KubernetesFetcher struct {
 	*BaseFetcher
@@ -32,6 +26,17 @@ func NewKubernetesFetcher(cacheDir string) *KubernetesFetcher {
 
 // FetchKubernetesVersion fetches information about a specific Kubernetes version
 func (f *KubernetesFetcher) FetchKubernetesVersion(version string) (*KubernetesVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchKubernetesVersion", version), f.versionPath(version), func() (*KubernetesVersionInfo, error) {
+		return f.fetchKubernetesVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Kubernetes version
+func (f *KubernetesFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("kubernetes", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *KubernetesFetcher) fetchKubernetesVersion(version string) (*KubernetesVersionInfo, error) {
 	// Normalize version (add 'v' prefix if missing for GitHub API)
 	githubVersion := version
 	if !strings.HasPrefix(version, "v") {
@@ -39,7 +44,7 @@ func (f *KubernetesFetcher) FetchKubernetesVersion(version string) (*KubernetesV
 	}
 
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("kubernetes", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
 	if err == nil && versionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Kubernetes version '%s' from cache\n", version)
@@ -79,42 +84,13 @@ func (f *KubernetesFetcher) FetchKubernetesVersion(version string) (*KubernetesV
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse GitHub release data
-	var releaseData map[string]interface{}
-	if err := json.Unmarshal(body, &releaseData); err != nil {
-		return nil, fmt.Errorf("failed to parse release data: %w", err)
-	}
-
-	// Extract version information
-	versionInfo = &KubernetesVersionInfo{
-		Version: version,
-	}
-
-	// Extract release URL
-	if htmlURL, ok := releaseData["html_url"].(string); ok {
-		versionInfo.ReleaseURL = htmlURL
-	}
-
-	// Extract release date
-	if publishedAt, ok := releaseData["published_at"].(string); ok {
-		// Parse and format the date
-		if t, err := time.Parse(time.RFC3339, publishedAt); err == nil {
-			versionInfo.ReleaseDate = t.Format("2006-01-02")
-		} else {
-			versionInfo.ReleaseDate = publishedAt
-		}
-	}
-
-	// Extract release notes from body
-	releaseNotes := ""
-	if body, ok := releaseData["body"].(string); ok {
-		releaseNotes = body
+	versionInfo, err = renderReleaseVersion(version, body, f.buildVersionContent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build content
-	versionInfo.Content = f.buildVersionContent(versionInfo, releaseNotes)
-
-	// Cache the result
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
 	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
 	}
@@ -191,17 +167,12 @@ func (f *KubernetesFetcher) buildVersionContent(info *KubernetesVersionInfo, rel
 }
 
 func (f *KubernetesFetcher) saveVersionInfoAsMarkdown(filePath string, info *KubernetesVersionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
 	if info.ReleaseDate != "" {
 		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
 	}
@@ -213,16 +184,16 @@ func (f *KubernetesFetcher) saveVersionInfoAsMarkdown(filePath string, info *Kub
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *KubernetesFetcher) loadVersionInfoFromMarkdown(filePath string) (*KubernetesVersionInfo, error) {
-	expired, err := f.getCache().IsExpired(filePath)
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
 	if err != nil || expired {
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -237,12 +208,23 @@ func (f *KubernetesFetcher) loadVersionInfoFromMarkdown(filePath string) (*Kuber
 		Version     string `yaml:"version"`
 		ReleaseDate string `yaml:"releaseDate"`
 		ReleaseURL  string `yaml:"releaseURL"`
+		Template    int    `yaml:"template"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if info, err := renderReleaseVersion(meta.Version, payload, f.buildVersionContent); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered Kubernetes version '%s' with current templates\n", meta.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
 	return &KubernetesVersionInfo{
 		Version:     meta.Version,
 		ReleaseDate: meta.ReleaseDate,
@@ -0,0 +1,408 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+const (
+	// spdxLicenseDataURL holds the SPDX License List as JSON: the list in
+	// json/licenses.json and each license, with its text, in
+	// json/details/<id>.json
+	spdxLicenseDataURL = "https://raw.githubusercontent.com/spdx/license-list-data/main/json"
+	// chooseALicenseURL holds the summaries of choosealicense.com, one
+	// file per common license with its permissions, conditions, and
+	// limitations as YAML front matter
+	chooseALicenseURL = "https://raw.githubusercontent.com/github/choosealicense.com/gh-pages/_licenses"
+)
+
+var (
+	spdxLicenseIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+-]*$`)
+	// spdxOperatorPattern splits license expressions such as
+	// "MIT OR Apache-2.0" and "GPL-2.0-only WITH Classpath-exception-2.0".
+	// Operators are upper case, unlike the "or" of names such as "GNU
+	// General Public License v3.0 or later".
+	spdxOperatorPattern = regexp.MustCompile(`\s+(AND|OR|WITH)\s+`)
+)
+
+// licenseRule is a permission, condition, or limitation of
+// choosealicense.com
+type licenseRule struct {
+	label       string
+	description string
+}
+
+// licenseRules are choosealicense.com's rules by kind and tag. patent-use is
+// a permission of licenses granting patent rights and a limitation of those
+// withholding them.
+var licenseRules = map[string]map[string]licenseRule{
+	"permissions": {
+		"commercial-use": {"Commercial use", "The licensed material and derivatives may be used for commercial purposes."},
+		"modifications":  {"Modification", "The licensed material may be modified."},
+		"distribution":   {"Distribution", "The licensed material may be distributed."},
+		"private-use":    {"Private use", "The licensed material may be used and modified in private."},
+		"patent-use":     {"Patent use", "This license provides an express grant of patent rights from contributors."},
+	},
+	"conditions": {
+		"include-copyright":         {"License and copyright notice", "A copy of the license and copyright notice must be included with the licensed material."},
+		"include-copyright--source": {"License and copyright notice for source", "A copy of the license and copyright notice must be included with the licensed material in source form, but is not required for binaries."},
+		"document-changes":          {"State changes", "Changes made to the licensed material must be documented."},
+		"disclose-source":           {"Disclose source", "Source code must be made available when the licensed material is distributed."},
+		"network-use-disclose":      {"Network use is distribution", "Users who interact with the licensed material via network are given the right to receive a copy of the source code."},
+		"same-license":              {"Same license", "Modifications must be released under the same license when distributing the licensed material. In some cases a similar or related license may be used."},
+		"same-license--file":        {"Same license (file)", "Modifications of existing files must be released under the same license when distributing the licensed material. In some cases a similar or related license may be used."},
+		"same-license--library":     {"Same license (library)", "Modifications must be released under the same license when distributing the licensed material. In some cases a similar or related license may be used, or this condition may not apply to works that use the licensed material as a library."},
+	},
+	"limitations": {
+		"trademark-use": {"Trademark use", "This license explicitly states that it does NOT grant trademark rights, even though licenses without such a statement probably do not grant any implicit trademark rights."},
+		"liability":     {"Liability", "This license includes a limitation of liability."},
+		"patent-use":    {"Patent use", "This license explicitly states that it does NOT grant any rights in the patents of contributors."},
+		"warranty":      {"Warranty", "This license explicitly states that it does NOT provide any warranty."},
+	},
+}
+
+// LicenseInfo is a license of the SPDX License List
+type LicenseInfo struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	OSIApproved bool   `yaml:"osiApproved"`
+	FSFLibre    bool   `yaml:"fsfLibre"`
+	Deprecated  bool   `yaml:"deprecated"`
+	URL         string `yaml:"url"`
+	Content     string `yaml:"-"`
+}
+
+// spdxLicense is an entry of the SPDX License List
+type spdxLicense struct {
+	LicenseID             string   `json:"licenseId"`
+	Name                  string   `json:"name"`
+	IsDeprecatedLicenseID bool     `json:"isDeprecatedLicenseId"`
+	IsOsiApproved         bool     `json:"isOsiApproved"`
+	IsFsfLibre            bool     `json:"isFsfLibre"`
+	SeeAlso               []string `json:"seeAlso"`
+}
+
+// licenseSummary is the front matter of a choosealicense.com license
+type licenseSummary struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	How         string   `yaml:"how"`
+	Permissions []string `yaml:"permissions"`
+	Conditions  []string `yaml:"conditions"`
+	Limitations []string `yaml:"limitations"`
+}
+
+type LicenseFetcher struct {
+	*BaseFetcher
+}
+
+func NewLicenseFetcher(cacheDir string) *LicenseFetcher {
+	return &LicenseFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchLicense fetches the full text of a license by its SPDX identifier
+// ("MIT", "Apache-2.0", "gpl-3.0-only"; case is ignored) or its name, with a
+// summary of its permissions, conditions, and limitations when
+// choosealicense.com has one. License expressions such as
+// "MIT OR Apache-2.0" are refused with the identifiers to fetch instead.
+func (f *LicenseFetcher) FetchLicense(id string) (*LicenseInfo, error) {
+	id = strings.TrimSpace(id)
+	if parts := spdxOperatorPattern.Split(id, -1); len(parts) > 1 {
+		var ids []string
+		for _, part := range parts {
+			if part = strings.Trim(part, " ()"); part != "" {
+				ids = append(ids, part)
+			}
+		}
+		return nil, fmt.Errorf("%q is a license expression; fetch each of its licenses: %s", id, strings.Join(ids, ", "))
+	}
+	if id == "" || len(id) > 100 {
+		return nil, fmt.Errorf("invalid license identifier %q", id)
+	}
+
+	return shareFetch(f.flights, flightKey("FetchLicense", strings.ToLower(id)), f.licensePath(id), func() (*LicenseInfo, error) {
+		return f.fetchLicense(id)
+	})
+}
+
+// licensePath returns the cache path of a license
+func (f *LicenseFetcher) licensePath(id string) string {
+	return f.getCache().GetFilePath("license", "licenses", fmt.Sprintf("%s.md", cache.EntryName(strings.ToLower(id))))
+}
+
+func (f *LicenseFetcher) fetchLicense(id string) (*LicenseInfo, error) {
+	// Check cache first
+	cachedPath := f.licensePath(id)
+	info, err := f.loadLicenseFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded license '%s' from cache\n", id)
+		return info, nil
+	}
+
+	license, err := f.resolveLicense(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching license '%s' from the SPDX License List...\n", license.LicenseID)
+	body, found, err := f.get(fmt.Sprintf("%s/details/%s.json", spdxLicenseDataURL, license.LicenseID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch license %s: %w", license.LicenseID, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("the SPDX License List has no text for %s", license.LicenseID)
+	}
+	var details struct {
+		LicenseText     string `json:"licenseText"`
+		LicenseComments string `json:"licenseComments"`
+	}
+	if err := json.Unmarshal(body, &details); err != nil {
+		return nil, fmt.Errorf("failed to parse license %s: %w", license.LicenseID, err)
+	}
+
+	info = &LicenseInfo{
+		ID:          license.LicenseID,
+		Name:        license.Name,
+		OSIApproved: license.IsOsiApproved,
+		FSFLibre:    license.IsFsfLibre,
+		Deprecated:  license.IsDeprecatedLicenseID,
+		URL:         fmt.Sprintf("https://spdx.org/licenses/%s.html", license.LicenseID),
+	}
+
+	// A missing summary leaves the text alone
+	summary, err := f.licenseSummary(license.LicenseID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch the summary of %s: %v\n", license.LicenseID, err)
+	}
+
+	info.Content = f.buildLicenseContent(info, license.SeeAlso, details.LicenseComments, summary, details.LicenseText)
+
+	if err := f.saveLicenseAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache license: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// resolveLicense finds a license of the SPDX License List by its
+// identifier, ignoring case, or else by its name
+func (f *LicenseFetcher) resolveLicense(id string) (*spdxLicense, error) {
+	licenses, err := f.licenseList()
+	if err != nil {
+		return nil, err
+	}
+	for i := range licenses {
+		if strings.EqualFold(licenses[i].LicenseID, id) {
+			return &licenses[i], nil
+		}
+	}
+	for i := range licenses {
+		if strings.EqualFold(licenses[i].Name, id) {
+			return &licenses[i], nil
+		}
+	}
+
+	if !spdxLicenseIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("no SPDX license is named %q", id)
+	}
+	var similar []string
+	for _, l := range licenses {
+		if strings.HasPrefix(strings.ToLower(l.LicenseID), strings.ToLower(id)) && !l.IsDeprecatedLicenseID {
+			similar = append(similar, l.LicenseID)
+		}
+	}
+	if len(similar) > 0 {
+		if len(similar) > 10 {
+			similar = append(similar[:10], "...")
+		}
+		return nil, fmt.Errorf("no SPDX license has the identifier %s; did you mean %s?", id, strings.Join(similar, ", "))
+	}
+	return nil, fmt.Errorf("no SPDX license has the identifier %s (see https://spdx.org/licenses/)", id)
+}
+
+// licenseList returns the SPDX License List, cached as it is published
+func (f *LicenseFetcher) licenseList() ([]spdxLicense, error) {
+	cachedPath := f.getCache().GetFilePath("license", "spdx", "licenses.json")
+
+	var body []byte
+	if expired, err := f.getCache().IsExpired(cachedPath); err == nil && !expired {
+		body, _ = f.getCache().ReadFile(cachedPath)
+	}
+	if body == nil {
+		fmt.Fprintf(os.Stderr, "Fetching the SPDX License List...\n")
+		var found bool
+		var err error
+		body, found, err = f.get(spdxLicenseDataURL + "/licenses.json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the SPDX License List: %w", err)
+		}
+		if !found {
+			return nil, fmt.Errorf("the SPDX License List was not found at %s", spdxLicenseDataURL)
+		}
+		if err := f.getCache().WriteFile(cachedPath, body); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache the SPDX License List: %v\n", err)
+		}
+	}
+
+	var list struct {
+		Licenses []spdxLicense `json:"licenses"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse the SPDX License List: %w", err)
+	}
+	return list.Licenses, nil
+}
+
+// chooseALicenseKey names the choosealicense.com page of a license: its
+// lowercase identifier without the -only and -or-later of the GNU licenses
+func chooseALicenseKey(id string) string {
+	key := strings.ToLower(id)
+	for _, suffix := range []string{"-only", "-or-later", "+"} {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	return key
+}
+
+// licenseSummary returns the choosealicense.com summary of a license, or
+// nil when it has none
+func (f *LicenseFetcher) licenseSummary(id string) (*licenseSummary, error) {
+	body, found, err := f.get(fmt.Sprintf("%s/%s.txt", chooseALicenseURL, chooseALicenseKey(id)))
+	if err != nil || !found {
+		return nil, err
+	}
+	parts := strings.SplitN(string(body), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid summary format: missing front matter")
+	}
+	var summary licenseSummary
+	if err := yaml.Unmarshal([]byte(parts[1]), &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse summary: %w", err)
+	}
+	return &summary, nil
+}
+
+func (f *LicenseFetcher) buildLicenseContent(info *LicenseInfo, seeAlso []string, comments string, summary *licenseSummary, text string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Name)
+	fmt.Fprintf(&content, "**SPDX Identifier:** `%s`\n\n", info.ID)
+	var approvals []string
+	if info.OSIApproved {
+		approvals = append(approvals, "OSI approved")
+	}
+	if info.FSFLibre {
+		approvals = append(approvals, "FSF free/libre")
+	}
+	if len(approvals) > 0 {
+		fmt.Fprintf(&content, "**Status:** %s\n\n", strings.Join(approvals, ", "))
+	}
+	if info.Deprecated {
+		content.WriteString("**Deprecated:** this identifier is deprecated in the SPDX License List; use its current form, such as the -only or -or-later variant of GNU licenses.\n\n")
+	}
+	if c := collapseSpace(comments); c != "" {
+		fmt.Fprintf(&content, "%s\n\n", c)
+	}
+
+	if summary != nil {
+		content.WriteString("## Summary\n\n")
+		if d := collapseSpace(summary.Description); d != "" {
+			content.WriteString(d + "\n\n")
+		}
+		for _, kind := range []struct {
+			title, key string
+			tags       []string
+		}{
+			{"Permissions", "permissions", summary.Permissions},
+			{"Conditions", "conditions", summary.Conditions},
+			{"Limitations", "limitations", summary.Limitations},
+		} {
+			if len(kind.tags) == 0 {
+				continue
+			}
+			fmt.Fprintf(&content, "### %s\n\n", kind.title)
+			for _, tag := range kind.tags {
+				rule, ok := licenseRules[kind.key][tag]
+				if !ok {
+					fmt.Fprintf(&content, "- %s\n", tag)
+					continue
+				}
+				fmt.Fprintf(&content, "- **%s:** %s\n", rule.label, rule.description)
+			}
+			content.WriteString("\n")
+		}
+		if how := collapseSpace(summary.How); how != "" {
+			fmt.Fprintf(&content, "**How to apply:** %s\n\n", how)
+		}
+		content.WriteString("_Summary from [choosealicense.com](https://choosealicense.com); it is not legal advice._\n\n")
+	}
+
+	content.WriteString("## License Text\n\n")
+	content.WriteString("```text\n")
+	content.WriteString(strings.Trim(strings.ReplaceAll(text, "\r\n", "\n"), "\n"))
+	content.WriteString("\n```\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [SPDX: %s](%s)\n", info.ID, info.URL)
+	if summary != nil {
+		fmt.Fprintf(&content, "- [choosealicense.com: %s](https://choosealicense.com/licenses/%s/)\n", summary.Title, chooseALicenseKey(info.ID))
+	}
+	for _, link := range seeAlso {
+		fmt.Fprintf(&content, "- %s\n", link)
+	}
+
+	return content.String()
+}
+
+func (f *LicenseFetcher) saveLicenseAsMarkdown(filePath string, info *LicenseInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "id: \"%s\"\n", info.ID)
+	fmt.Fprintf(&content, "name: \"%s\"\n", escapeYAML(info.Name))
+	fmt.Fprintf(&content, "osiApproved: %t\n", info.OSIApproved)
+	fmt.Fprintf(&content, "fsfLibre: %t\n", info.FSFLibre)
+	if info.Deprecated {
+		content.WriteString("deprecated: true\n")
+	}
+	fmt.Fprintf(&content, "url: \"%s\"\n", escapeYAML(info.URL))
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *LicenseFetcher) loadLicenseFromMarkdown(filePath string) (*LicenseInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info LicenseInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"net/http"
+	"sync"
+)
+
+const (
+	// maxRequestsPerHost bounds concurrent upstream requests to a single host
+	// across all fetchers, so batch lookups do not hammer one registry
+	maxRequestsPerHost = 4
+)
+
+// hostLimiter is shared by every fetcher's HTTP client
+var hostLimiter = newHostLimitedTransport(newBaseTransport(), maxRequestsPerHost)
+
+// hostLimitedTransport is an http.RoundTripper that caps in-flight requests per host
+type hostLimitedTransport struct {
+	base  http.RoundTripper
+	limit int
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+func newHostLimitedTransport(base http.RoundTripper, limit int) *hostLimitedTransport {
+	return &hostLimitedTransport{
+		base:  base,
+		limit: limit,
+		hosts: make(map[string]chan struct{}),
+	}
+}
+
+func (t *hostLimitedTransport) semaphore(host string) chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sem, ok := t.hosts[host]
+	if !ok {
+		sem = make(chan struct{}, t.limit)
+		t.hosts[host] = sem
+	}
+	return sem
+}
+
+// RoundTrip waits for a free slot for the request host before delegating to the base transport
+func (t *hostLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sem := t.semaphore(req.URL.Host)
+
+	select {
+	case sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-sem }()
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,515 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// llmsTxtPrefix names the documentation a site's llms.txt is indexed as
+	llmsTxtPrefix = "llms-"
+
+	// maxLLMsPages caps the linked pages indexed from one llms.txt
+	maxLLMsPages = 200
+
+	// maxLLMsPageBytes bounds one linked page, and maxLLMsFullBytes the
+	// llms-full.txt of a site
+	maxLLMsPageBytes = 4 << 20
+	maxLLMsFullBytes = 32 << 20
+
+	// maxLLMsTopicChars bounds the content of one topic
+	maxLLMsTopicChars = 60000
+)
+
+var (
+	llmsLinkPattern = regexp.MustCompile(`^\s*[-*+]\s*\[([^\]]+)\]\(([^)\s]+)\)\s*(?::\s*(.*))?$`)
+
+	errLLMsNotFound = errors.New("not found")
+)
+
+// LLMsTxtInfo describes a site's llms.txt indexed into the cache
+type LLMsTxtInfo struct {
+	URL   string
+	Title string
+	// Documentation is the name the site is searchable under
+	Documentation string
+	Topics        int
+	// Updated is false when the cached copy was still fresh
+	Updated bool
+	Content string
+}
+
+// llmsTxt is a parsed llms.txt: a title, a summary, and sections of links
+type llmsTxt struct {
+	Title   string
+	Summary string
+	Links   []llmsLink
+}
+
+type llmsLink struct {
+	Section string
+	Title   string
+	URL     string
+	Notes   string
+}
+
+// llmsTxtMetadata is the metadata.json of an indexed site. The provider
+// reads the documentation fields; LLMsTxt records what was downloaded.
+type llmsTxtMetadata struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+	LLMsTxt     struct {
+		URL      string         `json:"url"`
+		Title    string         `json:"title"`
+		Summary  string         `json:"summary"`
+		Topics   int            `json:"topics"`
+		Sections map[string]int `json:"sections"`
+		// Failed counts linked pages that could not be fetched
+		Failed int `json:"failed"`
+		// Full is set when the topics come from llms-full.txt
+		Full bool `json:"full"`
+	} `json:"llmsTxt"`
+}
+
+type LLMsTxtFetcher struct {
+	*BaseFetcher
+}
+
+func NewLLMsTxtFetcher(cacheDir string) *LLMsTxtFetcher {
+	return &LLMsTxtFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchLLMsTxt reads the llms.txt of a site ("svelte.dev",
+// "https://example.com/docs", or the file's own URL) and indexes each page
+// it links to as a topic in the cache directory, where the documentation
+// provider loads it. A site without llms.txt, or whose llms.txt links to no
+// pages, is indexed from its llms-full.txt, one topic per top-level
+// heading. An index younger than the cache TTL is not fetched again.
+func (f *LLMsTxtFetcher) FetchLLMsTxt(site string) (*LLMsTxtInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchLLMsTxt", site), "", func() (*LLMsTxtInfo, error) {
+		return f.fetchLLMsTxt(site)
+	})
+}
+
+func (f *LLMsTxtFetcher) fetchLLMsTxt(site string) (*LLMsTxtInfo, error) {
+	candidates, err := llmsTxtURLs(site)
+	if err != nil {
+		return nil, err
+	}
+
+	docName := LLMsTxtDocumentation(candidates[0])
+	docDir := filepath.Join(f.getCache().GetCacheDir(), docName)
+	metadataPath := filepath.Join(docDir, "metadata.json")
+	var cached llmsTxtMetadata
+	if fresh, err := readTopicsMetadata(metadataPath, f.getCache().GetTTL(), &cached); err == nil && fresh {
+		fmt.Fprintf(os.Stderr, "Loaded llms.txt of '%s' from cache\n", site)
+		return llmsTxtInfo(&cached, false), nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching llms.txt of '%s'...\n", site)
+
+	// The first candidate that has an llms.txt or llms-full.txt wins
+	var index *llmsTxt
+	var indexURL, fullURL string
+	var full []byte
+	for _, candidate := range candidates {
+		body, contentType, err := f.get(candidate, maxLLMsPageBytes)
+		if err == nil && isHTMLPage(contentType, body) {
+			// Sites that serve their app for any path have no llms.txt
+			err = errLLMsNotFound
+		}
+		if err == nil && strings.HasSuffix(candidate, "/llms-full.txt") {
+			full, fullURL = body, candidate
+			break
+		}
+		if err == nil {
+			index, indexURL = parseLLMsTxt(string(body)), candidate
+			break
+		}
+		if !errors.Is(err, errLLMsNotFound) {
+			return nil, fmt.Errorf("failed to fetch %s: %w", candidate, err)
+		}
+
+		fullCandidate := strings.TrimSuffix(candidate, "llms.txt") + "llms-full.txt"
+		if body, contentType, err := f.get(fullCandidate, maxLLMsFullBytes); err == nil && !isHTMLPage(contentType, body) {
+			full, fullURL = body, fullCandidate
+			break
+		}
+	}
+	if index == nil && full == nil {
+		return nil, fmt.Errorf("no llms.txt or llms-full.txt found for %s", site)
+	}
+
+	// An index without links is only a summary; its llms-full.txt has
+	// the content
+	if index != nil && len(index.Links) == 0 {
+		fullCandidate := strings.TrimSuffix(indexURL, "llms.txt") + "llms-full.txt"
+		body, contentType, err := f.get(fullCandidate, maxLLMsFullBytes)
+		if err != nil || isHTMLPage(contentType, body) {
+			return nil, fmt.Errorf("llms.txt at %s links to no pages and has no llms-full.txt", indexURL)
+		}
+		full, fullURL = body, fullCandidate
+	}
+
+	metadata := &llmsTxtMetadata{Name: docName}
+	var topics map[string]*indexedTopic
+	if full != nil {
+		if index == nil {
+			index = &llmsTxt{}
+		}
+		topics = buildLLMsFullTopics(fullURL, string(full))
+		metadata.LLMsTxt.URL = fullURL
+		metadata.LLMsTxt.Full = true
+	} else {
+		topics, metadata.LLMsTxt.Failed = f.fetchLinkedPages(indexURL, index)
+		metadata.LLMsTxt.URL = indexURL
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("no pages could be read from %s", metadata.LLMsTxt.URL)
+	}
+
+	u, _ := url.Parse(metadata.LLMsTxt.URL)
+	metadata.DisplayName = index.Title
+	if metadata.DisplayName == "" {
+		metadata.DisplayName = u.Host
+	}
+	metadata.Description = index.Summary
+	if metadata.Description == "" {
+		metadata.Description = fmt.Sprintf("%s documentation from its llms.txt", metadata.DisplayName)
+	}
+	metadata.LLMsTxt.Title = index.Title
+	metadata.LLMsTxt.Summary = index.Summary
+
+	kinds, err := writeTopics(docDir, topics)
+	if err != nil {
+		return nil, err
+	}
+	metadata.LLMsTxt.Topics = countTopics(kinds)
+	delete(kinds, "")
+	metadata.LLMsTxt.Sections = kinds
+
+	if err := writeJSON(metadataPath, metadata); err != nil {
+		return nil, fmt.Errorf("failed to write metadata: %w", err)
+	}
+	return llmsTxtInfo(metadata, true), nil
+}
+
+// LLMsTxtDocumentation returns the documentation name the site of an
+// llms.txt URL is indexed as, e.g. "llms-svelte.dev" or
+// "llms-example.com_docs"
+func LLMsTxtDocumentation(llmsTxtURL string) string {
+	u, err := url.Parse(llmsTxtURL)
+	if err != nil {
+		return llmsTxtPrefix + cache.SanitizeSegment(llmsTxtURL)
+	}
+	site := strings.ToLower(u.Host) + strings.TrimRight(path.Dir(u.Path), "/")
+	return llmsTxtPrefix + cache.SanitizeSegment(site)
+}
+
+// llmsTxtURLs returns where the llms.txt of a site may be, most specific
+// first: the URL itself when it names a .txt file, otherwise llms.txt
+// under the given path and then at the root of the host
+func llmsTxtURLs(site string) ([]string, error) {
+	site = strings.TrimSpace(site)
+	if !strings.Contains(site, "://") {
+		site = "https://" + site
+	}
+	u, err := url.Parse(site)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, fmt.Errorf("invalid site URL %q", site)
+	}
+	u.RawQuery, u.Fragment = "", ""
+
+	if strings.HasSuffix(u.Path, ".txt") {
+		return []string{u.String()}, nil
+	}
+
+	var urls []string
+	dir := strings.TrimRight(u.Path, "/")
+	if dir != "" {
+		urls = append(urls, fmt.Sprintf("%s://%s%s/llms.txt", u.Scheme, u.Host, dir))
+	}
+	return append(urls, fmt.Sprintf("%s://%s/llms.txt", u.Scheme, u.Host)), nil
+}
+
+// parseLLMsTxt reads the title, summary block quote, and links of an
+// llms.txt. Links are grouped by the H2 section they appear under.
+func parseLLMsTxt(text string) *llmsTxt {
+	index := &llmsTxt{}
+	var summary []string
+	section := ""
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "# ") && index.Title == "":
+			index.Title = strings.TrimSpace(trimmed[2:])
+		case strings.HasPrefix(trimmed, ">") && section == "" && len(index.Links) == 0:
+			summary = append(summary, strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+		case strings.HasPrefix(trimmed, "## "):
+			section = strings.TrimSpace(trimmed[3:])
+		default:
+			if m := llmsLinkPattern.FindStringSubmatch(line); m != nil {
+				index.Links = append(index.Links, llmsLink{
+					Section: section,
+					Title:   strings.TrimSpace(m[1]),
+					URL:     m[2],
+					Notes:   strings.TrimSpace(m[3]),
+				})
+			}
+		}
+	}
+	index.Summary = strings.TrimSpace(strings.Join(summary, " "))
+	return index
+}
+
+// fetchLinkedPages reads the pages an llms.txt links to, a few at a time,
+// and returns them as topics keyed by URL with the number that failed.
+// Relative links are resolved against the llms.txt URL.
+func (f *LLMsTxtFetcher) fetchLinkedPages(indexURL string, index *llmsTxt) (map[string]*indexedTopic, int) {
+	base, _ := url.Parse(indexURL)
+	var links []llmsLink
+	seen := make(map[string]bool)
+	for _, link := range index.Links {
+		u, err := url.Parse(link.URL)
+		if err != nil {
+			continue
+		}
+		u = base.ResolveReference(u)
+		u.Fragment = ""
+		if (u.Scheme != "https" && u.Scheme != "http") || seen[u.String()] {
+			continue
+		}
+		link.URL = u.String()
+		seen[link.URL] = true
+		links = append(links, link)
+	}
+	if len(links) > maxLLMsPages {
+		fmt.Fprintf(os.Stderr, "Warning: llms.txt links to %d pages, indexing the first %d\n", len(links), maxLLMsPages)
+		links = links[:maxLLMsPages]
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	topics := make(map[string]*indexedTopic)
+	failed := 0
+	work := make(chan llmsLink)
+	for i := 0; i < maxRequestsPerHost; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range work {
+				topic, err := f.fetchLinkedPage(index, link)
+				mu.Lock()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s: %v\n", link.URL, err)
+					failed++
+				} else {
+					topics[link.URL] = topic
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, link := range links {
+		work <- link
+	}
+	close(work)
+	wg.Wait()
+
+	return topics, failed
+}
+
+func (f *LLMsTxtFetcher) fetchLinkedPage(index *llmsTxt, link llmsLink) (topic *indexedTopic, err error) {
+	// Pages are fetched on worker goroutines, where a panic converting one
+	// would take down the server
+	defer func() {
+		if p := recover(); p != nil {
+			fmt.Fprintf(os.Stderr, "Panic reading %s: %v\n%s", link.URL, p, debug.Stack())
+			err = fmt.Errorf("failed to read page: %v", p)
+		}
+	}()
+
+	body, contentType, err := f.get(link.URL, maxLLMsPageBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(body)
+	if isHTMLPage(contentType, body) {
+		content = markdown.FromHTML(content, link.URL)
+	}
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "# ") {
+		content = "# " + link.Title + "\n\n" + content
+	}
+	content = truncateMarkdown(content, maxLLMsTopicChars, "Page truncated; see "+link.URL) + "\n"
+
+	description := link.Notes
+	if description == "" {
+		description = link.Section
+	}
+	if description == "" {
+		description = index.Title
+	}
+
+	u, _ := url.Parse(link.URL)
+	keywords := []string{link.Title, u.Host}
+	if index.Title != "" {
+		keywords = append(keywords, index.Title)
+	}
+	if link.Section != "" {
+		keywords = append(keywords, link.Section)
+	}
+
+	return &indexedTopic{
+		ID:          u.Host + u.Path,
+		Title:       link.Title,
+		Description: description,
+		Content:     content,
+		Keywords:    uniqueStrings(keywords),
+		kind:        link.Section,
+	}, nil
+}
+
+// buildLLMsFullTopics splits an llms-full.txt into one topic per H1
+// section outside code fences
+func buildLLMsFullTopics(fullURL, text string) map[string]*indexedTopic {
+	u, _ := url.Parse(fullURL)
+	site := u.Host + strings.TrimRight(path.Dir(u.Path), "/")
+
+	type section struct {
+		title string
+		lines []string
+	}
+	var sections []section
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+		}
+		if !inCode && strings.HasPrefix(line, "# ") {
+			sections = append(sections, section{title: strings.TrimSpace(line[2:])})
+		}
+		if len(sections) == 0 {
+			// Text before the first heading is the file's own preamble
+			continue
+		}
+		sections[len(sections)-1].lines = append(sections[len(sections)-1].lines, line)
+	}
+
+	topics := make(map[string]*indexedTopic)
+	used := make(map[string]int)
+	for _, s := range sections {
+		content := strings.TrimSpace(strings.Join(s.lines, "\n"))
+		if strings.Count(content, "\n") < 1 {
+			continue
+		}
+
+		slug := strings.Trim(nonSlugPattern.ReplaceAllString(strings.ToLower(s.title), "-"), "-")
+		used[slug]++
+		if used[slug] > 1 {
+			slug = fmt.Sprintf("%s-%d", slug, used[slug])
+		}
+
+		topics[slug] = &indexedTopic{
+			ID:          site + "/" + slug,
+			Title:       s.title,
+			Description: fmt.Sprintf("%s (llms-full.txt)", site),
+			Content:     truncateMarkdown(content, maxLLMsTopicChars, "Section truncated; see "+fullURL) + "\n",
+			Keywords:    []string{s.title, u.Host},
+		}
+	}
+	return topics
+}
+
+var nonSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// llmsTxtInfo summarizes an indexed site
+func llmsTxtInfo(metadata *llmsTxtMetadata, updated bool) *LLMsTxtInfo {
+	info := &LLMsTxtInfo{
+		URL:           metadata.LLMsTxt.URL,
+		Title:         metadata.DisplayName,
+		Documentation: metadata.Name,
+		Topics:        metadata.LLMsTxt.Topics,
+		Updated:       updated,
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# llms.txt: %s\n\n", info.Title)
+	if metadata.LLMsTxt.Summary != "" {
+		fmt.Fprintf(&b, "> %s\n\n", metadata.LLMsTxt.Summary)
+	}
+	fmt.Fprintf(&b, "**Source:** %s\n", info.URL)
+	fmt.Fprintf(&b, "**Documentation:** %s\n", info.Documentation)
+	fmt.Fprintf(&b, "**Topics:** %d\n", info.Topics)
+	if metadata.LLMsTxt.Failed > 0 {
+		fmt.Fprintf(&b, "**Unreachable pages:** %d\n", metadata.LLMsTxt.Failed)
+	}
+	b.WriteString("\n")
+
+	writeSections(&b, metadata.LLMsTxt.Sections)
+
+	info.Content = b.String()
+	return info
+}
+
+// isHTMLPage reports whether a linked page is HTML rather than markdown
+// or plain text
+func isHTMLPage(contentType string, body []byte) bool {
+	if strings.Contains(contentType, "html") {
+		return true
+	}
+	start := strings.ToLower(strings.TrimSpace(string(body[:min(len(body), 256)])))
+	return strings.HasPrefix(start, "<!doctype html") || strings.HasPrefix(start, "<html")
+}
+
+// get reads a text resource of at most limit bytes, returning its
+// Content-Type
+func (f *LLMsTxtFetcher) get(url string, limit int64) ([]byte, string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", "text/markdown, text/plain;q=0.9, text/html;q=0.8, */*;q=0.5")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, "", errLLMsNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > limit {
+		return nil, "", fmt.Errorf("response exceeds %d MB", limit>>20)
+	}
+	return body, resp.Header.Get("Content-Type"), nil
+}
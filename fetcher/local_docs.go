@@ -0,0 +1,394 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// localDocsPrefix names the documentation a local directory is imported as
+	localDocsPrefix = "local-"
+
+	// maxLocalDocsFiles caps the files imported from one directory
+	maxLocalDocsFiles = 5000
+
+	// maxLocalDocsFileBytes skips files too large to be a handbook page
+	maxLocalDocsFileBytes = 4 << 20
+
+	// maxLocalTopicChars bounds the content of one topic
+	maxLocalTopicChars = 60000
+
+	// maxLocalDescriptionChars bounds a description taken from a page's
+	// first paragraph
+	maxLocalDescriptionChars = 200
+)
+
+var (
+	localDocsNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	localHeadingPattern  = regexp.MustCompile(`(?m)^#{1,3}\s+(.+?)\s*#*$`)
+
+	// localDocsExtensions are the markdown files imported from a directory
+	localDocsExtensions = map[string]bool{".md": true, ".markdown": true, ".mdx": true}
+)
+
+// LocalDocsInfo describes a local directory imported into the cache
+type LocalDocsInfo struct {
+	Dir string
+	// Documentation is the name the directory is searchable under
+	Documentation string
+	Topics        int
+	// Updated is always true: an import reads the directory again
+	Updated bool
+	Content string
+}
+
+// localDocsMetadata is the metadata.json of an imported directory. The
+// provider reads the documentation fields; Local records the import.
+type localDocsMetadata struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+	Local       struct {
+		Dir      string         `json:"dir"`
+		Files    int            `json:"files"`
+		Topics   int            `json:"topics"`
+		Skipped  int            `json:"skipped"`
+		Sections map[string]int `json:"sections"`
+	} `json:"local"`
+}
+
+// localFrontMatter is the YAML front matter of a markdown page
+type localFrontMatter struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Tags        []string `yaml:"tags"`
+	Keywords    []string `yaml:"keywords"`
+	Version     string   `yaml:"version"`
+}
+
+type LocalDocsFetcher struct {
+	*BaseFetcher
+}
+
+func NewLocalDocsFetcher(cacheDir string) *LocalDocsFetcher {
+	return &LocalDocsFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// ResolveImportDir returns the absolute path of dir, with symbolic links
+// resolved, if it is one of roots or inside one of them. Callers importing
+// on behalf of a client check it before ImportDocs reads anything.
+func ResolveImportDir(dir string, roots []string) (string, error) {
+	if len(roots) == 0 {
+		return "", fmt.Errorf("no import_roots configured: list the directories that may be imported in config.yaml")
+	}
+	resolved, err := resolvePath(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	for _, root := range roots {
+		root, err := resolvePath(root)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(root, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("%s is outside import_roots", dir)
+}
+
+// resolvePath makes path absolute and resolves its symbolic links
+func resolvePath(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(path)
+}
+
+// ImportDocs indexes the markdown files (.md, .markdown, .mdx) under dir as
+// topics of a documentation set named name, defaulting to the directory's
+// name, in the cache directory, where the documentation provider loads it.
+// Each page gets keywords from its title, headings, front matter tags, and
+// the directories it is in. Importing a directory again replaces the
+// topics of the previous import.
+func (f *LocalDocsFetcher) ImportDocs(dir, name string) (*LocalDocsInfo, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	if stat, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	} else if !stat.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	if name == "" {
+		name = localDocsName(filepath.Base(dir))
+	}
+	if !localDocsNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid name %q: use lowercase letters, digits, '-', and '_'", name)
+	}
+	docName := LocalDocumentation(name)
+
+	return shareFetch(f.flights, flightKey("ImportDocs", docName), "", func() (*LocalDocsInfo, error) {
+		return f.importDocs(dir, name, docName)
+	})
+}
+
+func (f *LocalDocsFetcher) importDocs(dir, name, docName string) (*LocalDocsInfo, error) {
+	fmt.Fprintf(os.Stderr, "Importing markdown files from '%s'...\n", dir)
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read %s: %v\n", path, err)
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		// Skip .git, .github, node_modules, and the like
+		if d.IsDir() && path != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+			return fs.SkipDir
+		}
+		if d.Type().IsRegular() && localDocsExtensions[strings.ToLower(filepath.Ext(path))] {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no markdown files found in %s", dir)
+	}
+
+	skipped := 0
+	if len(files) > maxLocalDocsFiles {
+		fmt.Fprintf(os.Stderr, "Warning: importing the first %d of %d files\n", maxLocalDocsFiles, len(files))
+		skipped = len(files) - maxLocalDocsFiles
+		files = files[:maxLocalDocsFiles]
+	}
+
+	topics := make(map[string]*indexedTopic, len(files))
+	for _, path := range files {
+		rel, _ := filepath.Rel(dir, path)
+		topic, err := localTopic(path, filepath.ToSlash(rel), name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", rel, err)
+			skipped++
+			continue
+		}
+		topics[topic.ID] = topic
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("none of the %d markdown files in %s could be read", len(files), dir)
+	}
+
+	docDir := filepath.Join(f.getCache().GetCacheDir(), docName)
+	kinds, err := writeTopics(docDir, topics)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &localDocsMetadata{
+		Name:        docName,
+		DisplayName: name,
+		Description: fmt.Sprintf("Documentation imported from %s", dir),
+	}
+	metadata.Local.Dir = dir
+	metadata.Local.Files = len(files)
+	metadata.Local.Skipped = skipped
+	metadata.Local.Topics = countTopics(kinds)
+	delete(kinds, "")
+	metadata.Local.Sections = kinds
+
+	if err := writeJSON(filepath.Join(docDir, "metadata.json"), metadata); err != nil {
+		return nil, fmt.Errorf("failed to write metadata: %w", err)
+	}
+	return localDocsInfo(metadata), nil
+}
+
+// localTopic reads a markdown file, rel being its slash-separated path
+// within the imported directory
+func localTopic(path, rel, name string) (*indexedTopic, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if stat.Size() > maxLocalDocsFileBytes {
+		return nil, fmt.Errorf("larger than %d MB", maxLocalDocsFileBytes>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src := strings.ReplaceAll(string(data), "\r\n", "\n")
+	meta, body := splitFrontMatter(src)
+	if strings.EqualFold(filepath.Ext(path), ".mdx") {
+		body = markdown.FromMDX(body, markdown.MDXOptions{}).Body
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, errors.New("empty page")
+	}
+
+	stem := strings.TrimSuffix(rel, filepath.Ext(rel))
+	dirs := strings.Split(stem, "/")
+	base := dirs[len(dirs)-1]
+	dirs = dirs[:len(dirs)-1]
+
+	title := meta.Title
+	if title == "" && strings.HasPrefix(body, "# ") {
+		line, _, _ := strings.Cut(body, "\n")
+		title = strings.TrimSpace(strings.Trim(line, "#"))
+	}
+	if title == "" {
+		title = localTitle(base)
+		// A README or index page is named after its directory
+		if lower := strings.ToLower(base); (lower == "readme" || lower == "index") && len(dirs) > 0 {
+			title = localTitle(dirs[len(dirs)-1])
+		}
+	}
+
+	description := meta.Description
+	if description == "" {
+		description = firstParagraph(body)
+	}
+
+	content := body
+	if !strings.HasPrefix(content, "# ") {
+		content = "# " + title + "\n\n" + content
+	}
+
+	keywords := []string{title, base, name}
+	keywords = append(keywords, dirs...)
+	keywords = append(keywords, meta.Tags...)
+	keywords = append(keywords, meta.Keywords...)
+	for _, m := range localHeadingPattern.FindAllStringSubmatch(body, -1) {
+		keywords = append(keywords, strings.Trim(m[1], "`*_"))
+	}
+
+	kind := ""
+	if len(dirs) > 0 {
+		kind = localTitle(dirs[0])
+	}
+
+	return &indexedTopic{
+		ID:          name + "/" + stem,
+		Title:       title,
+		Description: description,
+		Content:     truncateMarkdown(content, maxLocalTopicChars, "Page truncated; see "+rel) + "\n",
+		Keywords:    uniqueStrings(keywords),
+		Version:     meta.Version,
+		kind:        kind,
+	}, nil
+}
+
+// splitFrontMatter separates the YAML front matter of a markdown page from
+// its body. Front matter that does not parse is kept in the body.
+func splitFrontMatter(src string) (localFrontMatter, string) {
+	var meta localFrontMatter
+	if !strings.HasPrefix(src, "---\n") {
+		return meta, src
+	}
+	front, body, ok := strings.Cut(src[4:], "\n---")
+	if !ok {
+		return meta, src
+	}
+	if err := yaml.Unmarshal([]byte(front), &meta); err != nil {
+		return localFrontMatter{}, src
+	}
+	return meta, strings.TrimPrefix(strings.TrimLeft(body, "-"), "\n")
+}
+
+// firstParagraph returns the first prose paragraph of a page, shortened
+// for a topic description
+func firstParagraph(body string) string {
+	for _, paragraph := range strings.Split(body, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" || strings.ContainsAny(paragraph[:1], "#`|<>-*![") {
+			continue
+		}
+		paragraph = strings.Join(strings.Fields(paragraph), " ")
+		if len(paragraph) > maxLocalDescriptionChars {
+			cut := strings.LastIndex(paragraph[:maxLocalDescriptionChars], " ")
+			if cut <= 0 {
+				cut = maxLocalDescriptionChars
+			}
+			paragraph = paragraph[:cut] + "..."
+		}
+		return paragraph
+	}
+	return ""
+}
+
+// localTitle turns a file or directory name such as "on-call_rotation"
+// into "On call rotation"
+func localTitle(name string) string {
+	title := strings.TrimSpace(strings.NewReplacer("-", " ", "_", " ").Replace(name))
+	if title == "" {
+		return name
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// localDocsName derives a documentation name from a directory name, e.g.
+// "Team Handbook" becomes "team-handbook"
+func localDocsName(dir string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(dir) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
+
+// LocalDocumentation returns the documentation name a directory imported as
+// name is searchable under, e.g. "local-handbook"
+func LocalDocumentation(name string) string {
+	return localDocsPrefix + name
+}
+
+// localDocsInfo summarizes an imported directory
+func localDocsInfo(metadata *localDocsMetadata) *LocalDocsInfo {
+	info := &LocalDocsInfo{
+		Dir:           metadata.Local.Dir,
+		Documentation: metadata.Name,
+		Topics:        metadata.Local.Topics,
+		Updated:       true,
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Imported: %s\n\n", metadata.DisplayName)
+	fmt.Fprintf(&b, "**Source:** %s\n", info.Dir)
+	fmt.Fprintf(&b, "**Documentation:** %s\n", info.Documentation)
+	fmt.Fprintf(&b, "**Topics:** %d\n", info.Topics)
+	if metadata.Local.Skipped > 0 {
+		fmt.Fprintf(&b, "**Skipped files:** %d\n", metadata.Local.Skipped)
+	}
+	b.WriteString("\n")
+
+	writeSections(&b, metadata.Local.Sections)
+
+	info.Content = b.String()
+	return info
+}
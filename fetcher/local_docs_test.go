@@ -0,0 +1,39 @@
+package fetcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestResolveImportDir checks that only directories within the roots are
+// imported, symbolic links included
+func TestResolveImportDir(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "handbook")
+	outside := filepath.Join(base, "secrets")
+	sibling := filepath.Join(base, "handbook-old")
+	for _, dir := range []string{filepath.Join(root, "guides"), outside, sibling} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	roots := []string{root}
+
+	for _, dir := range []string{root, filepath.Join(root, "guides"), filepath.Join(root, "guides", "..")} {
+		if _, err := ResolveImportDir(dir, roots); err != nil {
+			t.Errorf("ResolveImportDir(%s) = %v, want it allowed", dir, err)
+		}
+	}
+	for _, dir := range []string{outside, base, filepath.Join(root, ".."), filepath.Join(root, "link"), sibling} {
+		if _, err := ResolveImportDir(dir, roots); err == nil {
+			t.Errorf("ResolveImportDir(%s) allowed a directory outside the roots", dir)
+		}
+	}
+	if _, err := ResolveImportDir(root, nil); err == nil {
+		t.Error("ResolveImportDir allowed an import without roots")
+	}
+}
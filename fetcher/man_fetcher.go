@@ -0,0 +1,290 @@
+package fetcher
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// manpagesDebianURL renders the manual pages of every Debian package
+	// with mandoc, whose HTML keeps option lists and paragraphs apart
+	manpagesDebianURL = "https://manpages.debian.org"
+	// manpagesSuite is the Debian suite pages are read from, for the most
+	// recent upstream releases
+	manpagesSuite = "unstable"
+	man7URL       = "https://man7.org/linux/man-pages"
+
+	// maxManPageChars keeps long pages, such as bash(1)'s, to a
+	// reference-sized answer
+	maxManPageChars = 40000
+)
+
+var (
+	manPageNamePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.:+@-]*$`)
+	manSectionPattern  = regexp.MustCompile(`^[1-9][a-z]*$`)
+	// manPageRefPattern matches "open(2)"
+	manPageRefPattern = regexp.MustCompile(`^(.+)\(([^()]+)\)$`)
+	// manPagePathPattern matches the path a page resolves to, e.g.
+	// /unstable/manpages-dev/open.2.en.html
+	manPagePathPattern = regexp.MustCompile(`^/[^/]+/([^/]+)/(.+)\.([1-9][a-z]*)\.[A-Za-z_]+\.html$`)
+)
+
+// ManPageInfo is a manual page
+type ManPageInfo struct {
+	Name    string `yaml:"name"`
+	Section string `yaml:"section"`
+	// Package is the Debian package shipping the page
+	Package string `yaml:"package"`
+	URL     string `yaml:"url"`
+	// Degraded lists the content checks the page failed
+	Degraded []string `yaml:"degraded"`
+	Content  string   `yaml:"-"`
+}
+
+type ManFetcher struct {
+	*BaseFetcher
+}
+
+func NewManFetcher(cacheDir string) *ManFetcher {
+	return &ManFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchManPage fetches a manual page ("open", "open(2)", or "open" with
+// section "2") from the Debian manpages service as markdown. Without a
+// section, the page man would show first is returned.
+func (f *ManFetcher) FetchManPage(name, section string) (*ManPageInfo, error) {
+	name = strings.TrimSpace(name)
+	section = strings.ToLower(strings.TrimSpace(section))
+	if m := manPageRefPattern.FindStringSubmatch(name); m != nil {
+		if section != "" && section != strings.ToLower(m[2]) {
+			return nil, fmt.Errorf("%s names section %s, not %s", name, m[2], section)
+		}
+		name, section = m[1], strings.ToLower(m[2])
+	}
+	if !manPageNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid manual page name %q", name)
+	}
+	if section != "" && !manSectionPattern.MatchString(section) {
+		return nil, fmt.Errorf("invalid manual section %q (expected e.g. '1', '2', '3p')", section)
+	}
+
+	return shareFetch(f.flights, flightKey("FetchManPage", name, section), f.pagePath(name, section), func() (*ManPageInfo, error) {
+		return f.fetchManPage(name, section)
+	})
+}
+
+// pagePath returns the cache path of a manual page
+func (f *ManFetcher) pagePath(name, section string) string {
+	ref := name
+	if section != "" {
+		ref += "." + section
+	}
+	return f.getCache().GetFilePath("man", "pages", fmt.Sprintf("%s.md", cache.EntryName(ref)))
+}
+
+func (f *ManFetcher) fetchManPage(name, section string) (*ManPageInfo, error) {
+	ref := name
+	if section != "" {
+		ref += "." + section
+	}
+
+	// Check cache first
+	cachedPath := f.pagePath(name, section)
+	info, err := f.loadManPageFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded manual page '%s' from cache\n", ref)
+		return info, nil
+	}
+
+	pageURL := fmt.Sprintf("%s/%s/%s", manpagesDebianURL, manpagesSuite, ref)
+	fmt.Fprintf(os.Stderr, "Fetching manual page '%s' from %s...\n", ref, manpagesDebianURL)
+	page, finalURL, found, err := f.get(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manual page %s: %w", ref, err)
+	}
+	if !found {
+		if section != "" {
+			return nil, fmt.Errorf("no manual page %s(%s) in Debian %s", name, section, manpagesSuite)
+		}
+		return nil, fmt.Errorf("no manual page %s in Debian %s", name, manpagesSuite)
+	}
+
+	info = &ManPageInfo{Name: name, Section: section, URL: finalURL}
+	// The service redirects to the page it picked, which names the package
+	// and the section
+	if m := manPagePathPattern.FindStringSubmatch(strings.TrimPrefix(finalURL, manpagesDebianURL)); m != nil {
+		info.Package, info.Name, info.Section = m[1], m[2], m[3]
+	}
+
+	body, err := manPageMarkdown(page, finalURL)
+	if err != nil {
+		return nil, err
+	}
+	info.Content = f.buildManPageContent(info, body)
+	info.Degraded = checkShape("man", finalURL,
+		shapeCheck{"heading", hasMarkdownHeading(body)},
+		shapeCheck{"name-section", strings.Contains(body, "## NAME")},
+	)
+
+	if err := f.saveManPageAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache manual page: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// manPageMarkdown converts the manual text of a page, leaving out the
+// service's navigation, the page's header and footer lines, and the
+// permalinks of its headings and options
+func manPageMarkdown(page []byte, pageURL string) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", pageURL, err)
+	}
+	text := findNode(doc, func(n *html.Node) bool { return hasClassToken(n, "manual-text") })
+	if text == nil {
+		return "", fmt.Errorf("no manual text found in %s", pageURL)
+	}
+
+	unwrapPermalinks(text)
+	// Sections are h1 and subsections h2, under the page's own title
+	shiftHeadings(text, 1)
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, text); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", pageURL, err)
+	}
+	return markdown.FromHTML(buf.String(), pageURL), nil
+}
+
+// unwrapPermalinks turns the anchors mandoc links each heading and option
+// to itself with into plain text
+func unwrapPermalinks(n *html.Node) {
+	if n.DataAtom == atom.A && hasClassToken(n, "permalink") {
+		n.Data, n.DataAtom, n.Attr = "span", atom.Span, nil
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		unwrapPermalinks(c)
+	}
+}
+
+func (f *ManFetcher) buildManPageContent(info *ManPageInfo, body string) string {
+	var content strings.Builder
+
+	title := info.Name
+	if info.Section != "" {
+		title = fmt.Sprintf("%s(%s)", info.Name, info.Section)
+	}
+	fmt.Fprintf(&content, "# %s\n\n", title)
+
+	if info.Package != "" {
+		fmt.Fprintf(&content, "**Package:** %s (Debian %s)\n\n", info.Package, manpagesSuite)
+	}
+	fmt.Fprintf(&content, "**Manual Page:** [%s](%s)\n\n", title, info.URL)
+	content.WriteString("This is the page Debian ships; BSD, macOS, and BusyBox variants of a command may take other options.\n\n")
+
+	content.WriteString(strings.TrimSpace(truncateMarkdown(body, maxManPageChars, "The page is truncated; see the manual page for the rest.")))
+	content.WriteString("\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("For other versions and translations, visit:\n\n")
+	fmt.Fprintf(&content, "- [Debian manpages: %s](%s/%s)\n", info.Name, manpagesDebianURL, info.Name)
+	// The Linux man-pages project, which documents system calls and the C
+	// library, publishes its latest release on man7.org
+	if (info.Package == "manpages" || info.Package == "manpages-dev") && info.Section != "" {
+		fmt.Fprintf(&content, "- [man7.org: %s](%s/man%s/%s.%s.html)\n", title, man7URL, info.Section[:1], info.Name, info.Section)
+	}
+
+	return content.String()
+}
+
+// get returns the body of url and the URL it was served from after
+// redirects. found is false when there is no such page.
+func (f *ManFetcher) get(url string) (body []byte, finalURL string, found bool, err error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.Request.URL.String(), true, nil
+}
+
+func (f *ManFetcher) saveManPageAsMarkdown(filePath string, info *ManPageInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "name: \"%s\"\n", escapeYAML(info.Name))
+	if info.Section != "" {
+		fmt.Fprintf(&content, "section: \"%s\"\n", info.Section)
+	}
+	if info.Package != "" {
+		fmt.Fprintf(&content, "package: \"%s\"\n", escapeYAML(info.Package))
+	}
+	fmt.Fprintf(&content, "url: \"%s\"\n", escapeYAML(info.URL))
+	writeDegraded(&content, info.Degraded)
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *ManFetcher) loadManPageFromMarkdown(filePath string) (*ManPageInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info ManPageInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if degradedExpired(f.getCache(), filePath, info.Degraded) {
+		return nil, fmt.Errorf("degraded entry expired")
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -0,0 +1,201 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// mirrorHeaderTimeout is how long an upstream with mirrors has to start
+	// responding before the next mirror is tried, so a slow or silently
+	// blocked registry does not use up the whole request timeout
+	mirrorHeaderTimeout = 10 * time.Second
+)
+
+// mirrorSensitiveHeaders are removed from requests sent to a mirror on
+// another host
+var mirrorSensitiveHeaders = []string{"Authorization", "Www-Authenticate", "Cookie", "Cookie2"}
+
+// mirrorUpstream is a URL prefix and its mirrors, in the order they are tried
+type mirrorUpstream struct {
+	// prefix is host and optional path, without scheme or trailing slash
+	prefix  string
+	mirrors []*url.URL
+}
+
+// mirrorTransport is an http.RoundTripper that retries a request against
+// the mirrors of its upstream, in order, when the upstream cannot be reached
+// or answers with a server error or 429
+type mirrorTransport struct {
+	base http.RoundTripper
+	// upstreams are sorted longest prefix first
+	upstreams []mirrorUpstream
+}
+
+// newMirrorTransport returns nil when no valid mirror is configured. Keys
+// are upstream URL prefixes with or without a scheme ("proxy.golang.org",
+// "https://registry.npmjs.org"); values are the base URLs that replace them.
+func newMirrorTransport(base http.RoundTripper, mirrors map[string][]string) *mirrorTransport {
+	t := &mirrorTransport{base: base}
+	for upstream, bases := range mirrors {
+		u := mirrorUpstream{prefix: mirrorPrefix(upstream)}
+		for _, b := range bases {
+			mirror, err := url.Parse(strings.TrimRight(b, "/"))
+			if err != nil || mirror.Host == "" || (mirror.Scheme != "http" && mirror.Scheme != "https") {
+				fmt.Fprintf(os.Stderr, "Warning: ignoring mirror %q of %s: not an http(s) URL\n", b, upstream)
+				continue
+			}
+			u.mirrors = append(u.mirrors, mirror)
+		}
+		if u.prefix != "" && len(u.mirrors) > 0 {
+			t.upstreams = append(t.upstreams, u)
+		}
+	}
+	if len(t.upstreams) == 0 {
+		return nil
+	}
+	sort.Slice(t.upstreams, func(i, j int) bool {
+		return len(t.upstreams[i].prefix) > len(t.upstreams[j].prefix)
+	})
+	return t
+}
+
+func mirrorPrefix(upstream string) string {
+	upstream = strings.TrimSpace(upstream)
+	if _, rest, ok := strings.Cut(upstream, "://"); ok {
+		upstream = rest
+	}
+	return strings.ToLower(strings.TrimRight(upstream, "/"))
+}
+
+// match returns the upstream a request URL falls under and the rest of its
+// path after the upstream prefix
+func (t *mirrorTransport) match(u *url.URL) (*mirrorUpstream, string) {
+	path := u.EscapedPath()
+	target := strings.ToLower(u.Host + path)
+	for i := range t.upstreams {
+		if rest, ok := strings.CutPrefix(target, t.upstreams[i].prefix); ok && (rest == "" || rest[0] == '/') {
+			// Keep the original case of the path
+			return &t.upstreams[i], path[len(path)-len(rest):]
+		}
+	}
+	return nil, ""
+}
+
+// RoundTrip sends the request to its upstream and then to each mirror until
+// one answers. The last attempt's response or error is returned.
+func (t *mirrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	upstream, rest := t.match(req.URL)
+	// A request body can only be sent again if it can be recreated
+	if upstream == nil || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return t.base.RoundTrip(req)
+	}
+
+	attempts := []*http.Request{req}
+	for _, mirror := range upstream.mirrors {
+		r := req.Clone(req.Context())
+		u := *req.URL
+		u.Scheme, u.Host = mirror.Scheme, mirror.Host
+		u.Path, u.RawPath = "", ""
+		if p, err := url.PathUnescape(mirror.EscapedPath() + rest); err == nil {
+			u.Path = p
+			u.RawPath = mirror.EscapedPath() + rest
+		}
+		r.URL, r.Host = &u, ""
+		// Credentials for the upstream, such as a GitHub token, are not the
+		// mirror's to see, as net/http drops them on redirects to other hosts
+		if !strings.EqualFold(mirror.Host, req.URL.Host) {
+			for _, header := range mirrorSensitiveHeaders {
+				r.Header.Del(header)
+			}
+		}
+		attempts = append(attempts, r)
+	}
+
+	var lastErr error
+	for i, attempt := range attempts {
+		last := i == len(attempts)-1
+		if i > 0 && attempt.GetBody != nil {
+			body, err := attempt.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+
+		resp, err := t.try(attempt, last)
+		if err == nil && (last || !mirrorShouldRetry(resp.StatusCode)) {
+			return resp, nil
+		}
+		if req.Context().Err() != nil {
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+			return nil, req.Context().Err()
+		}
+
+		var reason string
+		if err != nil {
+			reason, lastErr = err.Error(), err
+		} else {
+			reason = resp.Status
+			_ = resp.Body.Close()
+		}
+		if !last {
+			fmt.Fprintf(os.Stderr, "Warning: %s failed (%s), trying mirror %s\n", attempt.URL.Host, reason, attempts[i+1].URL.Host)
+		}
+	}
+	return nil, lastErr
+}
+
+// try sends one attempt. Attempts with a mirror after them must start
+// responding within mirrorHeaderTimeout.
+func (t *mirrorTransport) try(req *http.Request, last bool) (*http.Response, error) {
+	if last {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(mirrorHeaderTimeout, cancel)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if !timer.Stop() {
+		// The timeout fired while or after the response started
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		cancel()
+		return nil, fmt.Errorf("no response within %v", mirrorHeaderTimeout)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The attempt's context lives until its body is read
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// mirrorShouldRetry reports whether a status means the upstream is
+// unavailable rather than answering, e.g. 404 is an answer
+func mirrorShouldRetry(status int) bool {
+	return status >= 500 || status == http.StatusTooManyRequests
+}
+
+// cancelOnClose releases a request context once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
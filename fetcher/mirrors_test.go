@@ -0,0 +1,60 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMirrorTransportStripsCredentials checks that a mirror on another host
+// never sees the credentials sent to the upstream
+func TestMirrorTransportStripsCredentials(t *testing.T) {
+	var upstreamAuth string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+
+	var mirrorHeaders http.Header
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorHeaders = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mirror.Close()
+
+	transport := newMirrorTransport(http.DefaultTransport, map[string][]string{
+		upstream.URL: {mirror.URL},
+	})
+	req, err := http.NewRequest("GET", upstream.URL+"/repos/a/b", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if upstreamAuth != "Bearer secret" {
+		t.Errorf("upstream got Authorization %q, want the token", upstreamAuth)
+	}
+	if mirrorHeaders == nil {
+		t.Fatal("the mirror was not tried")
+	}
+	for _, header := range []string{"Authorization", "Cookie"} {
+		if v := mirrorHeaders.Get(header); v != "" {
+			t.Errorf("mirror got %s %q", header, v)
+		}
+	}
+	if mirrorHeaders.Get("Accept") != "application/json" {
+		t.Error("mirror did not get the other headers")
+	}
+	if req.Header.Get("Authorization") == "" {
+		t.Error("the caller's request lost its Authorization header")
+	}
+}
@@ -6,20 +6,45 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"regexp"
 	"strings"
-	"time"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	nextJSContentsAPI = "https://api.github.com/repos/vercel/next.js/contents"
+	nextJSDocsURL     = "https://nextjs.org/docs"
 )
 
-type NextJSVersionInfo struct {
+// nextJSDocPrefix matches the ordering prefix of Next.js docs files and directories (e.g., "01-app")
+var nextJSDocPrefix = regexp.MustCompile(`^\d+-`)
+
+type NextJSVersionInfo = ReleaseVersionInfo
+
+type NextJSDocInfo struct {
+	Path        string `yaml:"path"`
+	Router      string `yaml:"router"`
 	Version     string `yaml:"version"`
-	ReleaseDate string `yaml:"releaseDate"`
-	ReleaseURL  string `yaml:"releaseURL"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	URL         string `yaml:"url"`
+	SourceURL   string `yaml:"sourceURL"`
 	Content     string `yaml:"-"`
 }
 
+// nextJSContentEntry is an item returned by the GitHub contents API
+type nextJSContentEntry struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	DownloadURL string `json:"download_url"`
+	HTMLURL     string `json:"html_url"`
+}
+
 type NextJSFetcher struct {
 	*BaseFetcher
 }
@@ -32,6 +57,17 @@ func NewNextJSFetcher(cacheDir string) *NextJSFetcher {
 
 // FetchNextJSVersion fetches information about a specific Next.js version
 func (f *NextJSFetcher) FetchNextJSVersion(version string) (*NextJSVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchNextJSVersion", version), f.versionPath(version), func() (*NextJSVersionInfo, error) {
+		return f.fetchNextJSVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Next.js version
+func (f *NextJSFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("nextjs", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *NextJSFetcher) fetchNextJSVersion(version string) (*NextJSVersionInfo, error) {
 	// Normalize version (add 'v' prefix if missing for GitHub API)
 	githubVersion := version
 	if !strings.HasPrefix(version, "v") {
@@ -39,7 +75,7 @@ func (f *NextJSFetcher) FetchNextJSVersion(version string) (*NextJSVersionInfo,
 	}
 
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("nextjs", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
 	if err == nil && versionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Next.js version '%s' from cache\n", version)
@@ -79,42 +115,13 @@ func (f *NextJSFetcher) FetchNextJSVersion(version string) (*NextJSVersionInfo,
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse GitHub release data
-	var releaseData map[string]interface{}
-	if err := json.Unmarshal(body, &releaseData); err != nil {
-		return nil, fmt.Errorf("failed to parse release data: %w", err)
-	}
-
-	// Extract version information
-	versionInfo = &NextJSVersionInfo{
-		Version: version,
-	}
-
-	// Extract release URL
-	if htmlURL, ok := releaseData["html_url"].(string); ok {
-		versionInfo.ReleaseURL = htmlURL
-	}
-
-	// Extract release date
-	if publishedAt, ok := releaseData["published_at"].(string); ok {
-		// Parse and format the date
-		if t, err := time.Parse(time.RFC3339, publishedAt); err == nil {
-			versionInfo.ReleaseDate = t.Format("2006-01-02")
-		} else {
-			versionInfo.ReleaseDate = publishedAt
-		}
-	}
-
-	// Extract release notes from body
-	releaseNotes := ""
-	if body, ok := releaseData["body"].(string); ok {
-		releaseNotes = body
+	versionInfo, err = renderReleaseVersion(version, body, f.buildVersionContent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build content
-	versionInfo.Content = f.buildVersionContent(versionInfo, releaseNotes)
-
-	// Cache the result
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
 	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
 	}
@@ -173,17 +180,12 @@ func (f *NextJSFetcher) buildVersionContent(info *NextJSVersionInfo, releaseNote
 }
 
 func (f *NextJSFetcher) saveVersionInfoAsMarkdown(filePath string, info *NextJSVersionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
 	if info.ReleaseDate != "" {
 		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
 	}
@@ -195,16 +197,16 @@ func (f *NextJSFetcher) saveVersionInfoAsMarkdown(filePath string, info *NextJSV
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *NextJSFetcher) loadVersionInfoFromMarkdown(filePath string) (*NextJSVersionInfo, error) {
-	expired, err := f.getCache().IsExpired(filePath)
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
 	if err != nil || expired {
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -219,12 +221,23 @@ func (f *NextJSFetcher) loadVersionInfoFromMarkdown(filePath string) (*NextJSVer
 		Version     string `yaml:"version"`
 		ReleaseDate string `yaml:"releaseDate"`
 		ReleaseURL  string `yaml:"releaseURL"`
+		Template    int    `yaml:"template"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if info, err := renderReleaseVersion(meta.Version, payload, f.buildVersionContent); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered Next.js version '%s' with current templates\n", meta.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
 	return &NextJSVersionInfo{
 		Version:     meta.Version,
 		ReleaseDate: meta.ReleaseDate,
@@ -232,3 +245,349 @@ func (f *NextJSFetcher) loadVersionInfoFromMarkdown(filePath string) (*NextJSVer
 		Content:     strings.TrimSpace(parts[2]),
 	}, nil
 }
+
+// FetchNextJSDocs fetches a nextjs.org docs page for the given router ("app" or "pages")
+// from the docs sources in the vercel/next.js repository. The page path is relative
+// to the router, e.g. "api-reference/functions/use-router". An empty version uses canary.
+func (f *NextJSFetcher) FetchNextJSDocs(docPath, router, version string) (*NextJSDocInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchNextJSDocs", docPath, router, version), "", func() (*NextJSDocInfo, error) {
+		return f.fetchNextJSDocs(docPath, router, version)
+	})
+}
+
+func (f *NextJSFetcher) fetchNextJSDocs(docPath, router, version string) (*NextJSDocInfo, error) {
+	docPath, router = normalizeNextJSDocPath(docPath, router)
+	if router != "app" && router != "pages" {
+		return nil, fmt.Errorf("invalid router %q: must be 'app' or 'pages'", router)
+	}
+	if docPath == "" {
+		return nil, fmt.Errorf("docs path is required")
+	}
+
+	ref := "canary"
+	if version != "" {
+		ref = version
+		if !strings.HasPrefix(ref, "v") {
+			ref = "v" + ref
+		}
+	}
+
+	// Check cache first
+	cachedPath := f.getCache().GetFilePath("nextjs", "docs", router, ref, fmt.Sprintf("%s.md", cache.EntryName(docPath)))
+	docInfo, err := f.loadDocInfoFromMarkdown(cachedPath)
+	if err == nil && docInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Next.js docs '%s/%s' from cache\n", router, docPath)
+		return docInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Next.js docs '%s/%s' (%s) from GitHub...\n", router, docPath, ref)
+
+	entry, err := f.resolveDocEntry(ref, append([]string{router}, strings.Split(docPath, "/")...))
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := f.fetchDocSource(entry.DownloadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pages Router docs often reuse an App Router page via a "source" frontmatter field
+	if shared := nextJSSharedSource(source); shared != "" {
+		sharedEntry, err := f.resolveDocEntry(ref, strings.Split(shared, "/"))
+		if err == nil {
+			if sharedSource, err := f.fetchDocSource(sharedEntry.DownloadURL); err == nil {
+				source = mergeNextJSFrontmatter(source, sharedSource)
+			}
+		}
+	}
+
+	// Keep only the content that applies to the requested router
+	dropBlocks := []string{"PagesOnly"}
+	if router == "pages" {
+		dropBlocks = []string{"AppOnly"}
+	}
+	doc := markdown.FromMDX(source, markdown.MDXOptions{
+		BaseURL:    "https://nextjs.org",
+		DropBlocks: dropBlocks,
+	})
+
+	docInfo = &NextJSDocInfo{
+		Path:        docPath,
+		Router:      router,
+		Version:     ref,
+		Title:       doc.Title,
+		Description: doc.Description,
+		URL:         fmt.Sprintf("%s/%s/%s", nextJSDocsURL, router, docPath),
+		SourceURL:   entry.HTMLURL,
+	}
+	if docInfo.Title == "" {
+		docInfo.Title = docPath
+	}
+
+	docInfo.Content = f.buildDocContent(docInfo, doc.Body)
+
+	if err := f.saveDocInfoAsMarkdown(cachedPath, docInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache docs page: %v\n", err)
+	}
+
+	return docInfo, nil
+}
+
+// normalizeNextJSDocPath accepts full URLs and router-prefixed paths, returning the
+// page path relative to the router and the router to use (defaults to "app")
+func normalizeNextJSDocPath(docPath, router string) (string, string) {
+	docPath = strings.TrimSpace(docPath)
+	docPath = strings.TrimPrefix(docPath, "https://nextjs.org")
+	docPath = strings.Trim(docPath, "/")
+	docPath = strings.TrimPrefix(docPath, "docs/")
+	docPath = strings.SplitN(docPath, "#", 2)[0]
+
+	for _, r := range []string{"app", "pages"} {
+		if docPath == r || strings.HasPrefix(docPath, r+"/") {
+			if router == "" {
+				router = r
+			}
+			docPath = strings.TrimPrefix(strings.TrimPrefix(docPath, r), "/")
+			break
+		}
+	}
+
+	if router == "" {
+		router = "app"
+	}
+	return docPath, strings.ToLower(router)
+}
+
+// resolveDocEntry walks the docs tree, matching each path segment while ignoring
+// the numeric ordering prefixes used in the repository
+func (f *NextJSFetcher) resolveDocEntry(ref string, segments []string) (*nextJSContentEntry, error) {
+	dir := "docs"
+	for i, segment := range segments {
+		entries, err := f.listDocsDir(dir, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		last := i == len(segments)-1
+		var match *nextJSContentEntry
+		for j := range entries {
+			name := nextJSDocPrefix.ReplaceAllString(strings.TrimSuffix(entries[j].Name, ".mdx"), "")
+			if name != segment {
+				continue
+			}
+			if entries[j].Type == "dir" || (last && entries[j].Type == "file") {
+				match = &entries[j]
+				break
+			}
+		}
+
+		if match == nil {
+			return nil, fmt.Errorf("next.js docs page %s not found (available under %s: %s)",
+				strings.Join(segments, "/"), strings.TrimPrefix(dir, "docs/"), strings.Join(nextJSDocNames(entries), ", "))
+		}
+
+		if match.Type == "file" {
+			return match, nil
+		}
+		dir = match.Path
+	}
+
+	// The path names a section, so use its index page
+	entries, err := f.listDocsDir(dir, ref)
+	if err != nil {
+		return nil, err
+	}
+	for j := range entries {
+		if entries[j].Type == "file" && strings.HasSuffix(entries[j].Name, "index.mdx") {
+			return &entries[j], nil
+		}
+	}
+
+	return nil, fmt.Errorf("next.js docs section %s has no index page (pages: %s)",
+		strings.Join(segments, "/"), strings.Join(nextJSDocNames(entries), ", "))
+}
+
+// nextJSDocNames returns the user-facing names of docs entries
+func nextJSDocNames(entries []nextJSContentEntry) []string {
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := nextJSDocPrefix.ReplaceAllString(strings.TrimSuffix(e.Name, ".mdx"), "")
+		if name == "index" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func (f *NextJSFetcher) listDocsDir(dir, ref string) ([]nextJSContentEntry, error) {
+	apiURL := fmt.Sprintf("%s/%s?ref=%s", nextJSContentsAPI, dir, ref)
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Next.js docs: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("next.js docs not found for ref %s", ref)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var entries []nextJSContentEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse docs listing: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (f *NextJSFetcher) fetchDocSource(url string) (string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Next.js docs page: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("docs page returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return string(body), nil
+}
+
+// nextJSSharedSource returns the "source" frontmatter field of a docs page, if any
+func nextJSSharedSource(src string) string {
+	parts := strings.SplitN(src, "---", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+
+	var meta struct {
+		Source string `yaml:"source"`
+	}
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return ""
+	}
+	return strings.Trim(meta.Source, "/")
+}
+
+// mergeNextJSFrontmatter keeps the frontmatter of the referencing page with the body of the shared page
+func mergeNextJSFrontmatter(page, shared string) string {
+	pageParts := strings.SplitN(page, "---", 3)
+	sharedParts := strings.SplitN(shared, "---", 3)
+	if len(pageParts) < 3 || len(sharedParts) < 3 {
+		return shared
+	}
+	return "---" + pageParts[1] + "---" + sharedParts[2]
+}
+
+func (f *NextJSFetcher) buildDocContent(info *NextJSDocInfo, body string) string {
+	var content strings.Builder
+
+	routerName := "App Router"
+	otherRouter := "pages"
+	if info.Router == "pages" {
+		routerName = "Pages Router"
+		otherRouter = "app"
+	}
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Title)
+
+	if info.Description != "" {
+		fmt.Fprintf(&content, "**Description:** %s\n\n", info.Description)
+	}
+
+	fmt.Fprintf(&content, "**Router:** %s\n\n", routerName)
+	fmt.Fprintf(&content, "**Docs Version:** %s\n\n", info.Version)
+	fmt.Fprintf(&content, "> This page applies to the %s only. Use router=%s for the other paradigm; do not mix APIs between routers.\n\n", routerName, otherRouter)
+
+	content.WriteString(body)
+	content.WriteString("\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [%s on nextjs.org](%s)\n", info.Title, info.URL)
+	if info.SourceURL != "" {
+		fmt.Fprintf(&content, "- [Source (%s)](%s)\n", info.Version, info.SourceURL)
+	}
+
+	return content.String()
+}
+
+func (f *NextJSFetcher) saveDocInfoAsMarkdown(filePath string, info *NextJSDocInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "path: \"%s\"\n", info.Path)
+	fmt.Fprintf(&content, "router: \"%s\"\n", info.Router)
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "title: \"%s\"\n", escapeYAML(info.Title))
+	if info.Description != "" {
+		fmt.Fprintf(&content, "description: \"%s\"\n", escapeYAML(info.Description))
+	}
+	fmt.Fprintf(&content, "url: \"%s\"\n", info.URL)
+	if info.SourceURL != "" {
+		fmt.Fprintf(&content, "sourceURL: \"%s\"\n", info.SourceURL)
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *NextJSFetcher) loadDocInfoFromMarkdown(filePath string) (*NextJSDocInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta NextJSDocInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	meta.Content = strings.TrimSpace(parts[2])
+	return &meta, nil
+}
@@ -0,0 +1,409 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+const (
+	nginxDownloadURL = "https://nginx.org/en/download.html"
+	// nginxChangesURL is the change log of the mainline branch; each stable
+	// branch has its own, at CHANGES-1.26
+	nginxChangesURL = "https://nginx.org/en/CHANGES"
+)
+
+var (
+	nginxVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+	// nginxChannelPattern finds the current versions on the download page,
+	// listed under "Mainline version" and "Stable version" headings
+	nginxChannelPattern = regexp.MustCompile(`(?s)(Mainline|Stable) version</h4>.*?nginx-(\d+\.\d+\.\d+)\.tar\.gz`)
+	// nginxChangesHeaderPattern matches the line that opens each release of
+	// a CHANGES file: "Changes with nginx 1.27.3      26 Nov 2024"
+	nginxChangesHeaderPattern = regexp.MustCompile(`(?m)^Changes with nginx (\S+)\s+(.+?)\s*$`)
+)
+
+type NginxVersionInfo struct {
+	Version     string `yaml:"version"`
+	ReleaseDate string `yaml:"releaseDate"`
+	// Channel is mainline or stable: nginx versions with an odd minor
+	// number are mainline, even ones stable
+	Channel string `yaml:"channel"`
+	// Mainline and Stable are the current versions of each channel
+	Mainline string `yaml:"mainline"`
+	Stable   string `yaml:"stable"`
+	Content  string `yaml:"-"`
+}
+
+// nginxRelease is a release of a CHANGES file
+type nginxRelease struct {
+	Version string        `json:"version"`
+	Date    string        `json:"date"`
+	Changes []nginxChange `json:"changes"`
+}
+
+// nginxChange is an entry of a release, e.g. a Feature or a Bugfix
+type nginxChange struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+type NginxFetcher struct {
+	*BaseFetcher
+}
+
+func NewNginxFetcher(cacheDir string) *NginxFetcher {
+	return &NginxFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchNginxVersion fetches the changes, channel, and install commands of
+// an nginx version ("1.27.3"), or of the current version of a channel when
+// version is "mainline" or "stable". Empty and "latest" mean mainline, which
+// nginx recommends for most deployments.
+func (f *NginxFetcher) FetchNginxVersion(version string) (*NginxVersionInfo, error) {
+	version = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(version), "nginx-"), "v"))
+	if version == "" || version == "latest" {
+		version = "mainline"
+	}
+	if version != "mainline" && version != "stable" && !nginxVersionPattern.MatchString(version) {
+		return nil, fmt.Errorf("invalid nginx version %q (expected e.g. '1.27.3', 'mainline', or 'stable')", version)
+	}
+	return shareFetch(f.flights, flightKey("FetchNginxVersion", version), f.versionPath(version), func() (*NginxVersionInfo, error) {
+		return f.fetchNginxVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a nginx version
+func (f *NginxFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("nginx", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *NginxFetcher) fetchNginxVersion(version string) (*NginxVersionInfo, error) {
+	// Check cache first
+	cachedPath := f.versionPath(version)
+	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
+	if err == nil && versionInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded nginx version '%s' from cache\n", versionInfo.Version)
+		return versionInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching nginx version '%s' from nginx.org...\n", version)
+
+	// The channels are needed to resolve one; for a given version they are
+	// only context
+	mainline, stable, err := f.fetchChannels()
+	if err != nil {
+		if version == "mainline" || version == "stable" {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch nginx channels: %v\n", err)
+	}
+	switch version {
+	case "mainline":
+		version = mainline
+	case "stable":
+		version = stable
+	}
+
+	body, status, err := f.get(nginxChangesFileURL(version))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch nginx changes: %w", err)
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("nginx version %s not found", version)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("nginx.org returned status %d", status)
+	}
+
+	release := findNginxRelease(string(body), version)
+	if release == nil {
+		return nil, fmt.Errorf("nginx version %s not found in %s", version, nginxChangesFileURL(version))
+	}
+
+	versionInfo = f.renderVersion(release, mainline, stable)
+
+	// Cache the result with the release it was rendered from
+	if payload, err := json.Marshal(release); err == nil {
+		f.saveRawPayload(cachedPath, payload)
+	}
+	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+	}
+
+	return versionInfo, nil
+}
+
+// fetchChannels reads the current mainline and stable versions from the
+// download page
+func (f *NginxFetcher) fetchChannels() (mainline, stable string, err error) {
+	body, status, err := f.get(nginxDownloadURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch nginx download page: %w", err)
+	}
+	if status != http.StatusOK {
+		return "", "", fmt.Errorf("nginx.org returned status %d for %s", status, nginxDownloadURL)
+	}
+
+	for _, m := range nginxChannelPattern.FindAllStringSubmatch(string(body), -1) {
+		switch {
+		case m[1] == "Mainline" && mainline == "":
+			mainline = m[2]
+		case m[1] == "Stable" && stable == "":
+			stable = m[2]
+		}
+	}
+	if mainline == "" || stable == "" {
+		return "", "", fmt.Errorf("no mainline and stable versions found on %s", nginxDownloadURL)
+	}
+	return mainline, stable, nil
+}
+
+func (f *NginxFetcher) get(url string) ([]byte, int, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// nginxChannel tells the channel of a version from its minor number
+func nginxChannel(version string) string {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return ""
+	}
+	if minor%2 == 1 {
+		return "mainline"
+	}
+	return "stable"
+}
+
+// nginxChangesFileURL returns the CHANGES file that lists a version: the
+// mainline one, or that of the version's stable branch
+func nginxChangesFileURL(version string) string {
+	if nginxChannel(version) != "stable" {
+		return nginxChangesURL
+	}
+	parts := strings.Split(version, ".")
+	return fmt.Sprintf("%s-%s.%s", nginxChangesURL, parts[0], parts[1])
+}
+
+// findNginxRelease extracts the changes of a version from a CHANGES file,
+// whose entries read "*) Feature: ..." and wrap onto indented lines
+func findNginxRelease(changes, version string) *nginxRelease {
+	changes = strings.ReplaceAll(changes, "\r\n", "\n")
+	headers := nginxChangesHeaderPattern.FindAllStringSubmatchIndex(changes, -1)
+	for i, h := range headers {
+		if changes[h[2]:h[3]] != version {
+			continue
+		}
+		end := len(changes)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+
+		release := &nginxRelease{Version: version, Date: changes[h[4]:h[5]]}
+		if t, err := time.Parse("2 Jan 2006", release.Date); err == nil {
+			release.Date = t.Format("2006-01-02")
+		}
+		for _, entry := range strings.Split(changes[h[1]:end], "*) ")[1:] {
+			text := collapseSpace(entry)
+			kind, rest, ok := strings.Cut(text, ": ")
+			if !ok || strings.Contains(kind, " ") {
+				kind, rest = "", text
+			}
+			release.Changes = append(release.Changes, nginxChange{Kind: kind, Text: rest})
+		}
+		return release
+	}
+	return nil
+}
+
+func (f *NginxFetcher) renderVersion(release *nginxRelease, mainline, stable string) *NginxVersionInfo {
+	info := &NginxVersionInfo{
+		Version:     release.Version,
+		ReleaseDate: release.Date,
+		Channel:     nginxChannel(release.Version),
+		Mainline:    mainline,
+		Stable:      stable,
+	}
+	info.Content = f.buildVersionContent(info, release.Changes)
+	return info
+}
+
+func (f *NginxFetcher) buildVersionContent(info *NginxVersionInfo, changes []nginxChange) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# nginx %s\n\n", info.Version)
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+	fmt.Fprintf(&content, "**Channel:** %s\n\n", info.Channel)
+	fmt.Fprintf(&content, "**Changes:** [%s](%s)\n\n", strings.TrimPrefix(nginxChangesFileURL(info.Version), "https://nginx.org/en/"), nginxChangesFileURL(info.Version))
+
+	if info.Mainline != "" && info.Stable != "" {
+		content.WriteString("## Channels\n\n")
+		fmt.Fprintf(&content, "- **Mainline:** %s — new features and all bug fixes; nginx recommends it for most deployments\n", info.Mainline)
+		fmt.Fprintf(&content, "- **Stable:** %s — only critical fixes, backported from mainline\n\n", info.Stable)
+		current := info.Mainline
+		if info.Channel == "stable" {
+			current = info.Stable
+		}
+		if info.Version == current {
+			fmt.Fprintf(&content, "This is the current %s version.\n\n", info.Channel)
+		} else {
+			fmt.Fprintf(&content, "This version is superseded; the current %s version is %s.\n\n", info.Channel, current)
+		}
+	}
+
+	content.WriteString("## Changes\n\n")
+	if len(changes) == 0 {
+		content.WriteString("No changes are listed for this version.\n\n")
+	}
+	for _, c := range changes {
+		if c.Kind != "" {
+			fmt.Fprintf(&content, "- **%s:** %s\n", c.Kind, c.Text)
+		} else {
+			fmt.Fprintf(&content, "- %s\n", c.Text)
+		}
+	}
+	if len(changes) > 0 {
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("### Using the nginx.org packages (Debian, Ubuntu)\n\n")
+	content.WriteString("Add the repository as described in the [Linux packages guide](https://nginx.org/en/linux_packages.html)")
+	if info.Channel == "mainline" {
+		content.WriteString(", using the `mainline` path")
+	}
+	content.WriteString(", then:\n\n")
+	content.WriteString("```bash\n")
+	content.WriteString("sudo apt update\n")
+	fmt.Fprintf(&content, "apt-cache madison nginx | grep %s\n", info.Version)
+	fmt.Fprintf(&content, "sudo apt install nginx=%s-1~$(lsb_release -cs)\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Using Docker\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "docker run --rm -p 8080:80 nginx:%s\n", info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### From source\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "curl -fsSLO https://nginx.org/download/nginx-%s.tar.gz\n", info.Version)
+	fmt.Fprintf(&content, "tar xzf nginx-%s.tar.gz && cd nginx-%s\n", info.Version, info.Version)
+	content.WriteString("./configure --with-http_ssl_module --with-http_v2_module\n")
+	content.WriteString("make && sudo make install\n")
+	content.WriteString("```\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("For detailed documentation, visit:\n\n")
+	content.WriteString("- [nginx Documentation](https://nginx.org/en/docs/)\n")
+	content.WriteString("- [nginx Downloads](https://nginx.org/en/download.html)\n")
+	content.WriteString("- [nginx Security Advisories](https://nginx.org/en/security_advisories.html)\n")
+	content.WriteString("- [nginx GitHub Repository](https://github.com/nginx/nginx)\n")
+
+	return content.String()
+}
+
+func (f *NginxFetcher) saveVersionInfoAsMarkdown(filePath string, info *NginxVersionInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	fmt.Fprintf(&content, "channel: \"%s\"\n", info.Channel)
+	// Kept so stale entries re-render without fetching the download page
+	if info.Mainline != "" {
+		fmt.Fprintf(&content, "mainline: \"%s\"\n", info.Mainline)
+	}
+	if info.Stable != "" {
+		fmt.Fprintf(&content, "stable: \"%s\"\n", info.Stable)
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *NginxFetcher) loadVersionInfoFromMarkdown(filePath string) (*NginxVersionInfo, error) {
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta struct {
+		NginxVersionInfo `yaml:",inline"`
+		Template         int `yaml:"template"`
+	}
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		var release nginxRelease
+		if err := json.Unmarshal(payload, &release); err == nil {
+			info := f.renderVersion(&release, meta.Mainline, meta.Stable)
+			fmt.Fprintf(os.Stderr, "Re-rendered nginx version '%s' with current templates\n", info.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
+	info := meta.NginxVersionInfo
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
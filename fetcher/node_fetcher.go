@@ -6,10 +6,23 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+const (
+	nodeIndexURL    = "https://nodejs.org/dist/index.json"
+	nodeScheduleURL = "https://raw.githubusercontent.com/nodejs/Release/main/schedule.json"
+
+	// nodeIndexTTL keeps the release index fresh enough to pick up new releases
+	// without downloading the whole list on every lookup
+	nodeIndexTTL = 1 * time.Hour
 )
 
 type NodeVersionInfo struct {
@@ -19,25 +32,55 @@ type NodeVersionInfo struct {
 	Content     string `yaml:"-"`
 }
 
+type NodeScheduleInfo struct {
+	Version string
+	Content string
+}
+
+// nodeScheduleEntry mirrors a single release line in the Node.js Release schedule.json
+type nodeScheduleEntry struct {
+	Start       string `json:"start"`
+	LTS         string `json:"lts,omitempty"`
+	Maintenance string `json:"maintenance,omitempty"`
+	End         string `json:"end"`
+	Codename    string `json:"codename,omitempty"`
+}
+
 type NodeFetcher struct {
 	*BaseFetcher
+	indexCache *cache.Manager
 }
 
 func NewNodeFetcher(cacheDir string) *NodeFetcher {
 	return &NodeFetcher{
 		BaseFetcher: NewBaseFetcher(cacheDir),
+		indexCache:  newCacheManager(cacheDir, nodeIndexTTL),
 	}
 }
 
 // FetchNodeVersion fetches information about a specific Node.js version
 func (f *NodeFetcher) FetchNodeVersion(version string) (*NodeVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchNodeVersion", version), f.versionPath(version), func() (*NodeVersionInfo, error) {
+		return f.fetchNodeVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Node.js version
+func (f *NodeFetcher) versionPath(version string) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return f.getCache().GetFilePath("node", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *NodeFetcher) fetchNodeVersion(version string) (*NodeVersionInfo, error) {
 	// Normalize version (add 'v' prefix if missing)
 	if !strings.HasPrefix(version, "v") {
 		version = "v" + version
 	}
 
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("node", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
 	if err == nil && versionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Node.js version '%s' from cache\n", version)
@@ -47,26 +90,9 @@ func (f *NodeFetcher) FetchNodeVersion(version string) (*NodeVersionInfo, error)
 	// Fetch from Node.js distribution API
 	fmt.Fprintf(os.Stderr, "Fetching Node.js version '%s' from nodejs.org...\n", version)
 
-	// First, get the version list to find details
-	resp, err := f.getClient().Get("https://nodejs.org/dist/index.json")
+	versions, err := f.fetchVersionIndex()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch Node.js version list: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("nodejs.org returned status %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Parse version list
-	var versions []map[string]interface{}
-	if err := json.Unmarshal(body, &versions); err != nil {
-		return nil, fmt.Errorf("failed to parse version data: %w", err)
+		return nil, err
 	}
 
 	// Find the requested version
@@ -110,6 +136,206 @@ func (f *NodeFetcher) FetchNodeVersion(version string) (*NodeVersionInfo, error)
 	return versionInfo, nil
 }
 
+// fetchVersionIndex returns the nodejs.org release index, cached separately with a short TTL
+func (f *NodeFetcher) fetchVersionIndex() ([]map[string]interface{}, error) {
+	indexPath := f.indexCache.GetFilePath("node", "index.json")
+
+	var versions []map[string]interface{}
+	if ok, err := f.indexCache.Load(indexPath, &versions); err == nil && ok {
+		return versions, nil
+	}
+
+	resp, err := f.getClient().Get(nodeIndexURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Node.js version list: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nodejs.org returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Parse version list
+	if err := json.Unmarshal(body, &versions); err != nil {
+		return nil, fmt.Errorf("failed to parse version data: %w", err)
+	}
+
+	if err := f.indexCache.Save(indexPath, versions); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache Node.js version index: %v\n", err)
+	}
+
+	return versions, nil
+}
+
+// FetchNodeSchedule returns the Node.js release schedule (LTS and end-of-life dates).
+// When version is set (e.g. "20" or "v20.11.0"), only that release line is described.
+func (f *NodeFetcher) FetchNodeSchedule(version string) (*NodeScheduleInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchNodeSchedule", version), "", func() (*NodeScheduleInfo, error) {
+		return f.fetchNodeSchedule(version)
+	})
+}
+
+func (f *NodeFetcher) fetchNodeSchedule(version string) (*NodeScheduleInfo, error) {
+	schedulePath := f.indexCache.GetFilePath("node", "schedule.json")
+
+	var schedule map[string]nodeScheduleEntry
+	if ok, err := f.indexCache.Load(schedulePath, &schedule); err != nil || !ok {
+		fmt.Fprintf(os.Stderr, "Fetching Node.js release schedule from GitHub...\n")
+
+		resp, err := f.getClient().Get(nodeScheduleURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch Node.js release schedule: %w", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("release schedule returned status %d", resp.StatusCode)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if err := json.Unmarshal(body, &schedule); err != nil {
+			return nil, fmt.Errorf("failed to parse release schedule: %w", err)
+		}
+
+		if err := f.indexCache.Save(schedulePath, schedule); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache Node.js release schedule: %v\n", err)
+		}
+	}
+
+	releaseLine := ""
+	if version != "" {
+		releaseLine = nodeReleaseLine(version)
+		if _, ok := schedule[releaseLine]; !ok {
+			return nil, fmt.Errorf("node.js release line %s not found in release schedule", releaseLine)
+		}
+	}
+
+	return &NodeScheduleInfo{
+		Version: releaseLine,
+		Content: f.buildScheduleContent(schedule, releaseLine, time.Now()),
+	}, nil
+}
+
+func (f *NodeFetcher) buildScheduleContent(schedule map[string]nodeScheduleEntry, releaseLine string, now time.Time) string {
+	var content strings.Builder
+
+	lines := make([]string, 0, len(schedule))
+	for line, entry := range schedule {
+		if releaseLine != "" && line != releaseLine {
+			continue
+		}
+		// Skip release lines that reached end-of-life more than a year ago
+		if releaseLine == "" {
+			if end, err := time.Parse("2006-01-02", entry.End); err == nil && now.Sub(end) > 365*24*time.Hour {
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return nodeReleaseLineLess(lines[j], lines[i])
+	})
+
+	if releaseLine != "" {
+		fmt.Fprintf(&content, "# Node.js %s Release Schedule\n\n", releaseLine)
+	} else {
+		content.WriteString("# Node.js Release Schedule\n\n")
+	}
+	fmt.Fprintf(&content, "**As of:** %s\n\n", now.Format("2006-01-02"))
+
+	content.WriteString("| Release | Codename | Status | Initial Release | Active LTS Start | Maintenance Start | End-of-Life |\n")
+	content.WriteString("|---------|----------|--------|-----------------|------------------|-------------------|-------------|\n")
+
+	recommended := ""
+	for _, line := range lines {
+		entry := schedule[line]
+		status := nodeReleaseStatus(entry, now)
+		if status == "Active LTS" && recommended == "" {
+			recommended = line
+		}
+		fmt.Fprintf(&content, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			line, orDash(entry.Codename), status, orDash(entry.Start), orDash(entry.LTS), orDash(entry.Maintenance), orDash(entry.End))
+	}
+	content.WriteString("\n")
+
+	if releaseLine == "" {
+		content.WriteString("## Recommendation\n\n")
+		if recommended != "" {
+			fmt.Fprintf(&content, "Use **Node.js %s** (Active LTS) for production workloads. ", recommended)
+		}
+		content.WriteString("Odd-numbered releases never become LTS and are supported only for a short period; ")
+		content.WriteString("avoid releases in Maintenance or End-of-Life status for new projects.\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("- [Node.js Releases](https://nodejs.org/en/about/previous-releases)\n")
+	content.WriteString("- [Release Schedule (nodejs/Release)](https://github.com/nodejs/Release#release-schedule)\n")
+
+	return content.String()
+}
+
+// nodeReleaseStatus derives the support phase of a release line at the given time
+func nodeReleaseStatus(entry nodeScheduleEntry, now time.Time) string {
+	reached := func(date string) bool {
+		t, err := time.Parse("2006-01-02", date)
+		return err == nil && !now.Before(t)
+	}
+
+	switch {
+	case entry.End != "" && reached(entry.End):
+		return "End-of-Life"
+	case !reached(entry.Start):
+		return "Planned"
+	case entry.Maintenance != "" && reached(entry.Maintenance):
+		return "Maintenance"
+	case entry.LTS != "" && reached(entry.LTS):
+		return "Active LTS"
+	default:
+		return "Current"
+	}
+}
+
+// nodeReleaseLine converts a version like "20.11.0" or "v20" to its schedule key ("v20").
+// Pre-1.0 release lines keep their minor version ("v0.12").
+func nodeReleaseLine(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	parts := strings.Split(version, ".")
+	if parts[0] == "0" && len(parts) > 1 {
+		return "v0." + parts[1]
+	}
+	return "v" + parts[0]
+}
+
+// nodeReleaseLineLess orders schedule keys numerically ("v8" < "v10")
+func nodeReleaseLineLess(a, b string) bool {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, _ := strconv.Atoi(pa[i])
+		nb, _ := strconv.Atoi(pb[i])
+		if na != nb {
+			return na < nb
+		}
+	}
+	return len(pa) < len(pb)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func (f *NodeFetcher) buildVersionContent(info *NodeVersionInfo, data map[string]interface{}) string {
 	var content strings.Builder
 
@@ -159,12 +385,6 @@ func (f *NodeFetcher) buildVersionContent(info *NodeVersionInfo, data map[string
 }
 
 func (f *NodeFetcher) saveVersionInfoAsMarkdown(filePath string, info *NodeVersionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
@@ -181,7 +401,7 @@ func (f *NodeFetcher) saveVersionInfoAsMarkdown(filePath string, info *NodeVersi
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *NodeFetcher) loadVersionInfoFromMarkdown(filePath string) (*NodeVersionInfo, error) {
@@ -190,7 +410,7 @@ func (f *NodeFetcher) loadVersionInfoFromMarkdown(filePath string) (*NodeVersion
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -6,10 +6,13 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
 )
 
 type NPMPackageInfo struct {
@@ -20,9 +23,38 @@ type NPMPackageInfo struct {
 	Repository  string `yaml:"repository"`
 	License     string `yaml:"license"`
 	Author      string `yaml:"author"`
-	Content     string `yaml:"-"`
+	// PeerDependencies maps peer package names to the version ranges they must satisfy
+	PeerDependencies map[string]string `yaml:"peerDependencies,omitempty"`
+	Content          string            `yaml:"-"`
+}
+
+// NPMVersionList is the dist-tags and most recently published versions of
+// an npm package
+type NPMVersionList struct {
+	Name     string
+	DistTags map[string]string
+	// Versions are the most recently published versions, newest first
+	Versions []NPMVersion
+	// Total is the number of published versions
+	Total   int
+	Content string
+}
+
+// NPMVersion is one published version of an npm package
+type NPMVersion struct {
+	Version    string
+	Published  time.Time
+	Deprecated string
 }
 
+const (
+	// defaultNPMVersions is how many versions a version list shows by default
+	defaultNPMVersions = 20
+
+	// maxNPMVersions caps the versions a version list shows
+	maxNPMVersions = 100
+)
+
 type NPMFetcher struct {
 	*BaseFetcher
 }
@@ -35,14 +67,19 @@ func NewNPMFetcher(cacheDir string) *NPMFetcher {
 
 // FetchPackageInfo fetches information about an npm package
 func (f *NPMFetcher) FetchPackageInfo(packageName, version string) (*NPMPackageInfo, error) {
-	// Sanitize package name for file system
-	safeName := strings.ReplaceAll(packageName, "/", "_")
-	if version != "" {
-		safeName = fmt.Sprintf("%s_%s", safeName, version)
-	}
+	return shareFetch(f.flights, flightKey("FetchPackageInfo", packageName, version), f.packagePath(packageName, version), func() (*NPMPackageInfo, error) {
+		return f.fetchPackageInfo(packageName, version)
+	})
+}
 
+// packagePath returns the cache path of the info on a package
+func (f *NPMFetcher) packagePath(packageName, version string) string {
+	return f.getCache().GetFilePath("npm", "packages", fmt.Sprintf("%s.md", cache.EntryName(packageName, version)))
+}
+
+func (f *NPMFetcher) fetchPackageInfo(packageName, version string) (*NPMPackageInfo, error) {
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("npm", "packages", fmt.Sprintf("%s.md", safeName))
+	cachedPath := f.packagePath(packageName, version)
 	pkgInfo, err := f.loadPackageInfoFromMarkdown(cachedPath)
 	if err == nil && pkgInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded npm package '%s' from cache\n", packageName)
@@ -120,8 +157,18 @@ func (f *NPMFetcher) FetchPackageInfo(packageName, version string) (*NPMPackageI
 		pkgInfo.Author = author
 	}
 
+	// Extract peer dependencies
+	if peers, ok := npmData["peerDependencies"].(map[string]interface{}); ok && len(peers) > 0 {
+		pkgInfo.PeerDependencies = make(map[string]string, len(peers))
+		for name, rng := range peers {
+			if s, ok := rng.(string); ok {
+				pkgInfo.PeerDependencies[name] = s
+			}
+		}
+	}
+
 	// Build content
-	pkgInfo.Content = f.buildPackageContent(pkgInfo)
+	pkgInfo.Content = f.buildPackageContent(pkgInfo) + f.advisoriesSection("npm", pkgInfo.Name, pkgInfo.Version)
 
 	// Cache the result
 	if err := f.savePackageInfoAsMarkdown(cachedPath, pkgInfo); err != nil {
@@ -131,6 +178,144 @@ func (f *NPMFetcher) FetchPackageInfo(packageName, version string) (*NPMPackageI
 	return pkgInfo, nil
 }
 
+// FetchVersions lists the dist-tags and the limit most recently published
+// versions of an npm package. Like the other version lists it is always
+// fetched live, since it changes with every release.
+func (f *NPMFetcher) FetchVersions(packageName string, limit int) (*NPMVersionList, error) {
+	return shareFetch(f.flights, flightKey("FetchVersions", packageName, fmt.Sprint(limit)), "", func() (*NPMVersionList, error) {
+		return f.fetchVersions(packageName, limit)
+	})
+}
+
+func (f *NPMFetcher) fetchVersions(packageName string, limit int) (*NPMVersionList, error) {
+	if limit <= 0 {
+		limit = defaultNPMVersions
+	}
+	limit = min(limit, maxNPMVersions)
+
+	fmt.Fprintf(os.Stderr, "Fetching npm versions of '%s' from registry.npmjs.org...\n", packageName)
+
+	// Publish times and deprecations are only in the full document
+	resp, err := f.getClient().Get(fmt.Sprintf("https://registry.npmjs.org/%s", packageName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch package versions: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("npm registry returned status %d for package %s", resp.StatusCode, packageName)
+	}
+
+	var data struct {
+		Name     string            `json:"name"`
+		DistTags map[string]string `json:"dist-tags"`
+		Time     map[string]string `json:"time"`
+		Versions map[string]struct {
+			Deprecated interface{} `json:"deprecated"`
+		} `json:"versions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse npm data: %w", err)
+	}
+
+	list := &NPMVersionList{
+		Name:     data.Name,
+		DistTags: data.DistTags,
+		Total:    len(data.Versions),
+	}
+	for version, meta := range data.Versions {
+		v := NPMVersion{Version: version}
+		if published, err := time.Parse(time.RFC3339, data.Time[version]); err == nil {
+			v.Published = published
+		}
+		// deprecated is a message, or rarely a boolean
+		switch d := meta.Deprecated.(type) {
+		case string:
+			v.Deprecated = d
+		case bool:
+			if d {
+				v.Deprecated = "deprecated"
+			}
+		}
+		list.Versions = append(list.Versions, v)
+	}
+	sort.Slice(list.Versions, func(i, j int) bool {
+		if !list.Versions[i].Published.Equal(list.Versions[j].Published) {
+			return list.Versions[i].Published.After(list.Versions[j].Published)
+		}
+		return list.Versions[i].Version > list.Versions[j].Version
+	})
+	if len(list.Versions) > limit {
+		list.Versions = list.Versions[:limit]
+	}
+
+	list.Content = f.buildVersionsContent(list)
+	return list, nil
+}
+
+func (f *NPMFetcher) buildVersionsContent(list *NPMVersionList) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s Versions\n\n", list.Name)
+	fmt.Fprintf(&content, "**Published versions:** %d\n\n", list.Total)
+
+	if len(list.DistTags) > 0 {
+		tags := make([]string, 0, len(list.DistTags))
+		for tag := range list.DistTags {
+			tags = append(tags, tag)
+		}
+		// latest first, then alphabetically
+		sort.Slice(tags, func(i, j int) bool {
+			if (tags[i] == "latest") != (tags[j] == "latest") {
+				return tags[i] == "latest"
+			}
+			return tags[i] < tags[j]
+		})
+
+		content.WriteString("## Dist-Tags\n\n")
+		content.WriteString("| Tag | Version |\n")
+		content.WriteString("|-----|---------|\n")
+		for _, tag := range tags {
+			fmt.Fprintf(&content, "| %s | %s |\n", tag, list.DistTags[tag])
+		}
+		content.WriteString("\n")
+	}
+
+	if len(list.Versions) < list.Total {
+		fmt.Fprintf(&content, "## Recent Versions (%d of %d)\n\n", len(list.Versions), list.Total)
+	} else {
+		content.WriteString("## Versions\n\n")
+	}
+	content.WriteString("| Version | Published | Notes |\n")
+	content.WriteString("|---------|-----------|-------|\n")
+	for _, v := range list.Versions {
+		published := "-"
+		if !v.Published.IsZero() {
+			published = v.Published.UTC().Format("2006-01-02")
+		}
+		var notes []string
+		for tag, version := range list.DistTags {
+			if version == v.Version {
+				notes = append(notes, "`"+tag+"`")
+			}
+		}
+		sort.Strings(notes)
+		if v.Deprecated != "" {
+			notes = append(notes, "deprecated: "+strings.ReplaceAll(strings.Join(strings.Fields(v.Deprecated), " "), "|", "\\|"))
+		}
+		note := strings.Join(notes, ", ")
+		if note == "" {
+			note = "-"
+		}
+		fmt.Fprintf(&content, "| %s | %s | %s |\n", v.Version, published, note)
+	}
+	content.WriteString("\n")
+
+	fmt.Fprintf(&content, "Fetch the details of a version with `open-context_get_npm_info` and `version`, e.g. `{\"packageName\": \"%s\", \"version\": \"%s\"}`.\n", list.Name, list.DistTags["latest"])
+
+	return content.String()
+}
+
 func (f *NPMFetcher) buildPackageContent(info *NPMPackageInfo) string {
 	var content strings.Builder
 
@@ -158,6 +343,20 @@ func (f *NPMFetcher) buildPackageContent(info *NPMPackageInfo) string {
 		fmt.Fprintf(&content, "**Repository:** %s\n\n", info.Repository)
 	}
 
+	if len(info.PeerDependencies) > 0 {
+		peers := make([]string, 0, len(info.PeerDependencies))
+		for name := range info.PeerDependencies {
+			peers = append(peers, name)
+		}
+		sort.Strings(peers)
+
+		content.WriteString("## Peer Dependencies\n\n")
+		for _, name := range peers {
+			fmt.Fprintf(&content, "- `%s` %s\n", name, info.PeerDependencies[name])
+		}
+		content.WriteString("\n")
+	}
+
 	content.WriteString("## Installation\n\n")
 	content.WriteString("```bash\n")
 	fmt.Fprintf(&content, "npm install %s", info.Name)
@@ -177,12 +376,6 @@ func (f *NPMFetcher) buildPackageContent(info *NPMPackageInfo) string {
 }
 
 func (f *NPMFetcher) savePackageInfoAsMarkdown(filePath string, info *NPMPackageInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
@@ -204,12 +397,18 @@ func (f *NPMFetcher) savePackageInfoAsMarkdown(filePath string, info *NPMPackage
 	if info.Author != "" {
 		fmt.Fprintf(&content, "author: \"%s\"\n", escapeYAML(info.Author))
 	}
+	if len(info.PeerDependencies) > 0 {
+		content.WriteString("peerDependencies:\n")
+		for name, rng := range info.PeerDependencies {
+			fmt.Fprintf(&content, "  \"%s\": \"%s\"\n", name, escapeYAML(rng))
+		}
+	}
 	content.WriteString("---\n\n")
 
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *NPMFetcher) loadPackageInfoFromMarkdown(filePath string) (*NPMPackageInfo, error) {
@@ -218,7 +417,7 @@ func (f *NPMFetcher) loadPackageInfoFromMarkdown(filePath string) (*NPMPackageIn
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -237,6 +436,8 @@ func (f *NPMFetcher) loadPackageInfoFromMarkdown(filePath string) (*NPMPackageIn
 		Repository  string `yaml:"repository"`
 		License     string `yaml:"license"`
 		Author      string `yaml:"author"`
+
+		PeerDependencies map[string]string `yaml:"peerDependencies"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
@@ -251,7 +452,9 @@ func (f *NPMFetcher) loadPackageInfoFromMarkdown(filePath string) (*NPMPackageIn
 		Repository:  meta.Repository,
 		License:     meta.License,
 		Author:      meta.Author,
-		Content:     strings.TrimSpace(parts[2]),
+
+		PeerDependencies: meta.PeerDependencies,
+		Content:          strings.TrimSpace(parts[2]),
 	}, nil
 }
 
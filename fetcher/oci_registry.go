@@ -0,0 +1,334 @@
+package fetcher
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Manifest media types a registry may answer a manifest request with
+const (
+	ociIndexMediaType           = "application/vnd.oci.image.index.v1+json"
+	ociManifestMediaType        = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+	dockerManifestMediaType     = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// dockerHubRegistry is the registry of image references without a host
+const dockerHubRegistry = "docker.io"
+
+// errRegistryNotFound is returned for a manifest, tag, or blob the registry
+// does not have
+var errRegistryNotFound = errors.New("not found in registry")
+
+// ociDescriptor points at a manifest, config, or layer blob
+type ociDescriptor struct {
+	MediaType string       `json:"mediaType"`
+	Digest    string       `json:"digest"`
+	Size      int64        `json:"size"`
+	Platform  *ociPlatform `json:"platform,omitempty"`
+}
+
+type ociPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+func (p *ociPlatform) String() string {
+	if p.Variant != "" {
+		return fmt.Sprintf("%s/%s/%s", p.OS, p.Architecture, p.Variant)
+	}
+	return fmt.Sprintf("%s/%s", p.OS, p.Architecture)
+}
+
+// ociManifest is an image manifest or, when Manifests is set, an index of
+// the manifests of each platform
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Manifests []ociDescriptor `json:"manifests"`
+	Config    ociDescriptor   `json:"config"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+func (m *ociManifest) isIndex() bool {
+	return m.MediaType == ociIndexMediaType || m.MediaType == dockerManifestListMediaType || len(m.Manifests) > 0
+}
+
+// imageSize is the compressed size of the config and layers of an image
+// manifest
+func (m *ociManifest) imageSize() int64 {
+	size := m.Config.Size
+	for _, layer := range m.Layers {
+		size += layer.Size
+	}
+	return size
+}
+
+// ociImageConfig is the config blob of an image: how containers of it run
+// and the steps it was built with
+type ociImageConfig struct {
+	Created string `json:"created"`
+	Config  struct {
+		User         string              `json:"User"`
+		Env          []string            `json:"Env"`
+		Entrypoint   []string            `json:"Entrypoint"`
+		Cmd          []string            `json:"Cmd"`
+		WorkingDir   string              `json:"WorkingDir"`
+		ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+	} `json:"config"`
+	History []ociHistory `json:"history"`
+}
+
+// ociHistory is one build step. Steps that only change metadata have no
+// layer.
+type ociHistory struct {
+	Created    string `json:"created"`
+	CreatedBy  string `json:"created_by"`
+	Comment    string `json:"comment"`
+	EmptyLayer bool   `json:"empty_layer"`
+}
+
+// parseImageReference splits an image reference into its registry host and
+// repository. References without a host are Docker Hub images, where
+// official images live under "library/".
+func parseImageReference(image string) (registry, repository string) {
+	registry = dockerHubRegistry
+	repository = image
+	if host, rest, ok := strings.Cut(image, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		registry, repository = strings.ToLower(host), rest
+	}
+
+	switch registry {
+	case "index.docker.io", "registry-1.docker.io":
+		registry = dockerHubRegistry
+	}
+	if registry == dockerHubRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return registry, repository
+}
+
+// registryClient reads one repository over the OCI distribution API,
+// requesting an anonymous pull token when the registry challenges for one
+type registryClient struct {
+	client     *http.Client
+	registry   string
+	repository string
+	token      string
+}
+
+func (f *DockerImageFetcher) newRegistryClient(registry, repository string) *registryClient {
+	return &registryClient{
+		client:     f.getClient(),
+		registry:   registry,
+		repository: repository,
+	}
+}
+
+// baseURL returns the API root of the registry
+func (c *registryClient) baseURL() string {
+	if c.registry == dockerHubRegistry {
+		return "https://registry-1.docker.io/v2/"
+	}
+	return "https://" + c.registry + "/v2/"
+}
+
+// manifest reads the manifest of a tag or digest, and its digest
+func (c *registryClient) manifest(reference string) (*ociManifest, string, error) {
+	accept := strings.Join([]string{ociIndexMediaType, ociManifestMediaType, dockerManifestListMediaType, dockerManifestMediaType}, ", ")
+	body, header, err := c.get(fmt.Sprintf("%s/manifests/%s", c.repository, url.PathEscape(reference)), accept)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var m ociManifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, "", fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" {
+		digest = fmt.Sprintf("sha256:%x", sha256.Sum256(body))
+	}
+	return &m, digest, nil
+}
+
+// config reads and decodes the config blob of an image manifest
+func (c *registryClient) config(m *ociManifest) (*ociImageConfig, error) {
+	if m.Config.Digest == "" {
+		return nil, fmt.Errorf("manifest has no config")
+	}
+	// Registries usually redirect blobs to a CDN, which is followed without
+	// the token
+	body, _, err := c.get(fmt.Sprintf("%s/blobs/%s", c.repository, m.Config.Digest), "application/json, */*")
+	if err != nil {
+		return nil, err
+	}
+
+	var config ociImageConfig
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse image config: %w", err)
+	}
+	return &config, nil
+}
+
+// tags lists the tags of the repository. Registries return them in lexical
+// order, and some ignore the requested page size.
+func (c *registryClient) tags(n int) ([]string, error) {
+	body, _, err := c.get(fmt.Sprintf("%s/tags/list?n=%d", c.repository, n), "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse tag list: %w", err)
+	}
+	return list.Tags, nil
+}
+
+// get reads path under the API root, authenticating and retrying once if
+// the registry answers 401
+func (c *registryClient) get(path, accept string) ([]byte, http.Header, error) {
+	resp, err := c.do(path, accept)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if err := c.authenticate(challenge); err != nil {
+			return nil, nil, err
+		}
+		if resp, err = c.do(path, accept); err != nil {
+			return nil, nil, err
+		}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, errRegistryNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("registry %s returned status %d", c.registry, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.Header, nil
+}
+
+func (c *registryClient) do(path, accept string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", c.baseURL()+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", accept)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach registry %s: %w", c.registry, err)
+	}
+	return resp, nil
+}
+
+// authenticate requests an anonymous pull token from the realm named in a
+// Bearer challenge
+func (c *registryClient) authenticate(challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("registry %s requires unsupported authentication %q", c.registry, scheme)
+	}
+	auth := parseChallengeParams(params)
+
+	realm, err := url.Parse(auth["realm"])
+	if err != nil || realm.Host == "" || (realm.Scheme != "https" && realm.Scheme != "http") {
+		return fmt.Errorf("registry %s sent an invalid token realm %q", c.registry, auth["realm"])
+	}
+	query := realm.Query()
+	if auth["service"] != "" {
+		query.Set("service", auth["service"])
+	}
+	scope := auth["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", c.repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", realm.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request registry token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry %s token service returned status %d", c.registry, resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("failed to parse registry token: %w", err)
+	}
+	c.token = token.Token
+	if c.token == "" {
+		c.token = token.AccessToken
+	}
+	if c.token == "" {
+		return fmt.Errorf("registry %s token service returned no token", c.registry)
+	}
+	return nil
+}
+
+// parseChallengeParams parses the key="value" pairs of a WWW-Authenticate
+// challenge. Values may contain commas, e.g. a scope with several actions.
+func parseChallengeParams(s string) map[string]string {
+	params := make(map[string]string)
+	for s = strings.TrimSpace(s); s != ""; {
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		params[key] = strings.TrimSpace(value)
+
+		s = strings.TrimLeft(rest, ", ")
+	}
+	return params
+}
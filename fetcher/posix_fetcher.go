@@ -0,0 +1,278 @@
+package fetcher
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// posixUtilitiesURL is the Shell and Utilities volume of POSIX.1-2024
+	posixUtilitiesURL  = "https://pubs.opengroup.org/onlinepubs/9799919799/utilities"
+	coreutilsManualURL = "https://www.gnu.org/software/coreutils/manual/html_node"
+
+	// maxPosixUtilChars keeps long pages, such as awk's, to a
+	// reference-sized answer
+	maxPosixUtilChars = 40000
+	// maxCoreutilsSubnodes caps the pages of the manual an invocation node
+	// links to and that are appended to it
+	maxCoreutilsSubnodes = 12
+)
+
+var posixUtilPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.+-]*$`)
+
+// PosixUtilInfo is the reference of a shell utility
+type PosixUtilInfo struct {
+	Name string
+	// Source is posix or gnu
+	Source  string
+	URL     string
+	Content string
+}
+
+type PosixFetcher struct {
+	*BaseFetcher
+}
+
+func NewPosixFetcher(cacheDir string) *PosixFetcher {
+	return &PosixFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchPosixUtil fetches the reference of a shell utility (awk, sed, find)
+// from the POSIX specification, or from the GNU coreutils manual when
+// source is "gnu". Without a source, utilities POSIX does not specify, such
+// as sha256sum, are looked up in the coreutils manual.
+func (f *PosixFetcher) FetchPosixUtil(name, source string) (*PosixUtilInfo, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !posixUtilPattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid utility name %q", name)
+	}
+	source = strings.ToLower(strings.TrimSpace(source))
+	switch source {
+	case "", "posix", "gnu":
+	case "coreutils":
+		source = "gnu"
+	default:
+		return nil, fmt.Errorf("unsupported source %q (supported: posix, gnu)", source)
+	}
+
+	return shareFetch(f.flights, flightKey("FetchPosixUtil", name, source), "", func() (*PosixUtilInfo, error) {
+		if source == "gnu" {
+			return f.fetchCoreutils(name)
+		}
+		info, found, err := f.fetchPosix(name)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return info, nil
+		}
+		if source == "" {
+			if gnu, err := f.fetchCoreutils(name); err == nil {
+				return gnu, nil
+			}
+			return nil, fmt.Errorf("%s is neither a POSIX utility nor a GNU coreutils one", name)
+		}
+		return nil, fmt.Errorf("%s is not a POSIX utility", name)
+	})
+}
+
+// fetchPosix returns the specification of a utility; found is false when
+// POSIX does not specify it
+func (f *PosixFetcher) fetchPosix(name string) (info *PosixUtilInfo, found bool, err error) {
+	pageURL := fmt.Sprintf("%s/%s.html", posixUtilitiesURL, name)
+	cachedPath := f.getCache().GetFilePath("posix", "utilities", name+".html")
+	page, found, err := f.fetchCached(cachedPath, pageURL)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch the POSIX page of %s: %w", name, err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	body, err := posixPageMarkdown(page, pageURL)
+	if err != nil {
+		return nil, false, err
+	}
+	degraded := checkShape("posix", pageURL, shapeCheck{"synopsis", strings.Contains(body, "## SYNOPSIS")})
+	expireDegraded(f.getCache(), cachedPath, degraded)
+
+	info = &PosixUtilInfo{Name: name, Source: "posix", URL: pageURL}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# %s (POSIX)\n\n", name)
+	fmt.Fprintf(&content, "**Specification:** [POSIX.1-2024: %s](%s)\n\n", name, pageURL)
+	content.WriteString("The options below are the portable ones. GNU, BSD, and BusyBox implementations add their own; check them before relying on a flag outside this list.\n\n")
+	content.WriteString(strings.TrimSpace(truncateMarkdown(body, maxPosixUtilChars, "The page is truncated; see the specification for the rest.")))
+	content.WriteString("\n\n")
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [POSIX utilities index](%s/contents.html)\n", posixUtilitiesURL)
+	fmt.Fprintf(&content, "- [GNU coreutils manual](%s/index.html)\n\n", coreutilsManualURL)
+	content.WriteString("_From The Open Group Base Specifications Issue 8 (IEEE Std 1003.1-2024), © IEEE and The Open Group._\n")
+
+	info.Content = content.String()
+	return info, true, nil
+}
+
+func (f *PosixFetcher) fetchCoreutils(name string) (*PosixUtilInfo, error) {
+	pageURL := fmt.Sprintf("%s/%s-invocation.html", coreutilsManualURL, name)
+	cachedPath := f.getCache().GetFilePath("gnu", "coreutils", name+"-invocation.html")
+	page, found, err := f.fetchCached(cachedPath, pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the coreutils manual of %s: %w", name, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%s is not a GNU coreutils utility", name)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pageURL, err)
+	}
+
+	// Some invocation nodes, such as ls's, only introduce the pages that
+	// describe the options
+	subnodes := coreutilsSubnodes(doc)
+	var b strings.Builder
+	node := coreutilsNodeMarkdown(doc, pageURL, 2)
+	degraded := checkShape("gnu", pageURL, shapeCheck{"heading", hasMarkdownHeading(node)})
+	expireDegraded(f.getCache(), cachedPath, degraded)
+	b.WriteString(node)
+	for i, sub := range subnodes {
+		if i == maxCoreutilsSubnodes {
+			fmt.Fprintf(os.Stderr, "Warning: %s links to %d pages; only the first %d are included\n", pageURL, len(subnodes), maxCoreutilsSubnodes)
+			break
+		}
+		subURL := coreutilsManualURL + "/" + sub
+		subPage, found, err := f.fetchCached(f.getCache().GetFilePath("gnu", "coreutils", sub), subURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s: %v\n", subURL, err)
+		}
+		if !found {
+			continue
+		}
+		subDoc, err := html.Parse(bytes.NewReader(subPage))
+		if err != nil {
+			continue
+		}
+		b.WriteString("\n\n")
+		b.WriteString(coreutilsNodeMarkdown(subDoc, subURL, 3))
+	}
+
+	info := &PosixUtilInfo{Name: name, Source: "gnu", URL: pageURL}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# %s (GNU coreutils)\n\n", name)
+	fmt.Fprintf(&content, "**Manual:** [%s invocation](%s)\n\n", name, pageURL)
+	content.WriteString("GNU options may be missing on macOS, the BSDs, and BusyBox; for portable scripts, check the POSIX page of the utility.\n\n")
+	content.WriteString(strings.TrimSpace(truncateMarkdown(b.String(), maxPosixUtilChars, "The manual is truncated; see GNU's site for the rest.")))
+	content.WriteString("\n\n")
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [GNU coreutils manual](%s/index.html)\n", coreutilsManualURL)
+	fmt.Fprintf(&content, "- [POSIX utilities index](%s/contents.html)\n\n", posixUtilitiesURL)
+	content.WriteString("_From the GNU Coreutils manual, © Free Software Foundation, [GNU Free Documentation License](https://www.gnu.org/licenses/fdl-1.3.html)._\n")
+
+	info.Content = content.String()
+	return info, nil
+}
+
+// posixPageMarkdown converts a utility page of the specification, from
+// NAME to SEE ALSO, leaving out the page's navigation and change history.
+// Each section is a heading followed by a block quote, which is unwrapped.
+func posixPageMarkdown(page []byte, pageURL string) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", pageURL, err)
+	}
+	first := findNode(doc, func(n *html.Node) bool { return headingLevel(n) > 0 })
+	if first == nil {
+		return "", fmt.Errorf("no sections found in %s", pageURL)
+	}
+
+	delta := 2 - headingLevel(first)
+	var buf bytes.Buffer
+	for n := first; n != nil; n = n.NextSibling {
+		if n.DataAtom == atom.Hr {
+			break
+		}
+		if headingLevel(n) > 0 && strings.EqualFold(collapseSpace(getText(n)), "CHANGE HISTORY") {
+			break
+		}
+		unwrapBlockquotes(n)
+		shiftHeadings(n, delta)
+		if err := html.Render(&buf, n); err != nil {
+			return "", fmt.Errorf("failed to render %s: %w", pageURL, err)
+		}
+	}
+	return markdown.FromHTML(buf.String(), pageURL), nil
+}
+
+// unwrapBlockquotes turns block quotes, which the specification indents
+// its sections with, into plain blocks
+func unwrapBlockquotes(n *html.Node) {
+	if n.DataAtom == atom.Blockquote {
+		n.Data, n.DataAtom = "div", atom.Div
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		unwrapBlockquotes(c)
+	}
+}
+
+// coreutilsNodeMarkdown converts a page of the coreutils manual without
+// its navigation, its heading at level
+func coreutilsNodeMarkdown(doc *html.Node, pageURL string, level int) string {
+	body := findElement(doc, "body")
+	if body == nil {
+		body = doc
+	}
+	removeElements(body, func(n *html.Node) bool {
+		return hasClassToken(n, "nav-panel") || hasClassToken(n, "header") || hasClassToken(n, "mini-toc") ||
+			hasClassToken(n, "copiable-link") || n.DataAtom == atom.Hr
+	})
+	if heading := findNode(body, func(n *html.Node) bool { return headingLevel(n) > 0 }); heading != nil {
+		shiftHeadings(body, level-headingLevel(heading))
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, body); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(markdown.FromHTML(buf.String(), pageURL))
+}
+
+// coreutilsSubnodes lists the pages in a node's menu
+func coreutilsSubnodes(doc *html.Node) []string {
+	menu := findNode(doc, func(n *html.Node) bool {
+		return (n.Data == "ul" && hasClassToken(n, "mini-toc")) || (n.Data == "table" && hasClassToken(n, "menu"))
+	})
+	if menu == nil {
+		return nil
+	}
+
+	var pages []string
+	seen := map[string]bool{}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			page, _, _ := strings.Cut(attrValue(n, "href"), "#")
+			if page != "" && !strings.Contains(page, "/") && strings.HasSuffix(page, ".html") && !seen[page] {
+				seen[page] = true
+				pages = append(pages, page)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(menu)
+	return pages
+}
@@ -0,0 +1,270 @@
+package fetcher
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// protoFile is the outline of a .proto file: its package and the services,
+// messages, and enums it defines, with their leading comments
+type protoFile struct {
+	pkg      string
+	services []protoService
+	messages []protoType
+	enums    []protoType
+}
+
+type protoService struct {
+	name    string
+	comment string
+	methods []protoMethod
+}
+
+type protoMethod struct {
+	name, request, response          string
+	clientStreaming, serverStreaming bool
+	comment                          string
+}
+
+// protoType is a message or enum; nested types are named by their path,
+// e.g. Outer.Inner
+type protoType struct {
+	name    string
+	comment string
+}
+
+func (m protoMethod) signature() string {
+	request, response := m.request, m.response
+	if m.clientStreaming {
+		request = "stream " + request
+	}
+	if m.serverStreaming {
+		response = "stream " + response
+	}
+	return fmt.Sprintf("%s(%s) returns (%s)", m.name, request, response)
+}
+
+// protoToken is a word, string, or punctuation of a .proto file with the
+// comment directly above it
+type protoToken struct {
+	text    string
+	comment string
+}
+
+// tokenizeProto splits a .proto file into tokens. A comment is attached to
+// the next token unless a blank line separates them, as protoc does for
+// leading comments; comments trailing a token on its line are dropped.
+func tokenizeProto(src string) []protoToken {
+	var tokens []protoToken
+	var comment []string
+	// blank is set by a blank line, trailing by a token earlier on the line
+	blank, trailing := false, false
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			// Two line breaks with only space between detach a comment
+			if j := strings.IndexByte(src[i+1:], '\n'); j >= 0 && strings.TrimSpace(src[i+1:i+1+j]) == "" {
+				blank = true
+			}
+			trailing = false
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			if blank {
+				comment, blank = nil, false
+			}
+			if !trailing {
+				comment = append(comment, strings.TrimSpace(strings.TrimPrefix(src[i:i+end], "//")))
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			if blank {
+				comment, blank = nil, false
+			}
+			if !trailing {
+				for _, line := range strings.Split(src[i+2:i+2+end], "\n") {
+					comment = append(comment, strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*")))
+				}
+			}
+			i += end + 4
+		default:
+			start := i
+			switch {
+			case c == '"' || c == '\'':
+				for i++; i < len(src) && src[i] != c; i++ {
+					if src[i] == '\\' {
+						i++
+					}
+				}
+				i++
+			case isProtoWordByte(c):
+				for i < len(src) && isProtoWordByte(src[i]) {
+					i++
+				}
+			default:
+				i++
+			}
+			if i > len(src) {
+				i = len(src)
+			}
+			token := protoToken{text: src[start:i]}
+			if !blank {
+				token.comment = strings.TrimSpace(strings.Join(comment, "\n"))
+			}
+			tokens = append(tokens, token)
+			comment, blank, trailing = nil, false, true
+		}
+	}
+	return tokens
+}
+
+func isProtoWordByte(c byte) bool {
+	return c == '_' || c == '.' || c < 0x80 && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)))
+}
+
+// parseProtoFile outlines a .proto file. It reads declarations only, so
+// files that do not compile still yield what they declare.
+func parseProtoFile(src string) *protoFile {
+	file := &protoFile{}
+	tokens := tokenizeProto(src)
+
+	// scopes holds the kind and name of each open brace: message, enum,
+	// service, or other (oneofs, options, rpc bodies)
+	type scope struct{ kind, name string }
+	var scopes []scope
+	var service *protoService
+	statementStart := true
+
+	at := func(i int) string {
+		if i < len(tokens) {
+			return tokens[i].text
+		}
+		return ""
+	}
+	messagePath := func(name string) string {
+		var parts []string
+		for _, s := range scopes {
+			if s.kind == "message" {
+				parts = append(parts, s.name)
+			}
+		}
+		return strings.Join(append(parts, name), ".")
+	}
+	inside := func(kind string) bool {
+		return len(scopes) > 0 && scopes[len(scopes)-1].kind == kind
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch t.text {
+		case "{":
+			scopes = append(scopes, scope{kind: "other"})
+			statementStart = true
+			continue
+		case "}":
+			if len(scopes) > 0 {
+				if scopes[len(scopes)-1].kind == "service" {
+					service = nil
+				}
+				scopes = scopes[:len(scopes)-1]
+			}
+			statementStart = true
+			continue
+		case ";":
+			statementStart = true
+			continue
+		}
+		if !statementStart {
+			continue
+		}
+		statementStart = false
+
+		top := len(scopes) == 0 || inside("message")
+		switch {
+		case t.text == "package" && len(scopes) == 0:
+			file.pkg = at(i + 1)
+		case (t.text == "message" || t.text == "enum") && top && at(i+2) == "{":
+			name := at(i + 1)
+			if t.text == "message" {
+				file.messages = append(file.messages, protoType{name: messagePath(name), comment: t.comment})
+			} else {
+				file.enums = append(file.enums, protoType{name: messagePath(name), comment: t.comment})
+			}
+			scopes = append(scopes, scope{kind: t.text, name: name})
+			i += 2
+			statementStart = true
+		case t.text == "service" && len(scopes) == 0 && at(i+2) == "{":
+			file.services = append(file.services, protoService{name: at(i + 1), comment: t.comment})
+			service = &file.services[len(file.services)-1]
+			scopes = append(scopes, scope{kind: "service", name: at(i + 1)})
+			i += 2
+			statementStart = true
+		case t.text == "rpc" && service != nil && inside("service"):
+			m, next, ok := parseProtoMethod(tokens, i+1)
+			if !ok {
+				continue
+			}
+			m.comment = t.comment
+			service.methods = append(service.methods, m)
+			// Leave the terminating ; or { to the loop
+			i = next - 1
+		}
+	}
+	return file
+}
+
+// parseProtoMethod reads Name ( [stream] Request ) returns ( [stream]
+// Response ) from tokens[i], returning the index after it
+func parseProtoMethod(tokens []protoToken, i int) (protoMethod, int, bool) {
+	at := func(i int) string {
+		if i < len(tokens) {
+			return tokens[i].text
+		}
+		return ""
+	}
+	m := protoMethod{name: at(i)}
+	i++
+
+	readType := func(streaming *bool) (string, bool) {
+		if at(i) != "(" {
+			return "", false
+		}
+		i++
+		if at(i) == "stream" && at(i+1) != ")" {
+			*streaming = true
+			i++
+		}
+		typ := at(i)
+		i++
+		if at(i) != ")" || typ == "" {
+			return "", false
+		}
+		i++
+		return typ, true
+	}
+
+	var ok bool
+	if m.request, ok = readType(&m.clientStreaming); !ok {
+		return m, i, false
+	}
+	if at(i) != "returns" {
+		return m, i, false
+	}
+	i++
+	if m.response, ok = readType(&m.serverStreaming); !ok {
+		return m, i, false
+	}
+	return m, i, true
+}
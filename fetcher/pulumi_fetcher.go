@@ -0,0 +1,337 @@
+package fetcher
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	githubReposAPI    = "https://api.github.com/repos"
+	pulumiRegistryURL = "https://www.pulumi.com/registry/packages"
+	npmRegistryURL    = "https://registry.npmjs.org"
+	pypiJSONURL       = "https://pypi.org/pypi"
+)
+
+// PulumiInfo is a release of the Pulumi CLI or of a provider package, with
+// the versions of its SDKs published to npm and PyPI
+type PulumiInfo struct {
+	// Package is "pulumi" for the CLI, else the provider name ("aws")
+	Package     string `yaml:"package"`
+	Version     string `yaml:"version"`
+	ReleaseDate string `yaml:"releaseDate"`
+	ReleaseURL  string `yaml:"releaseURL"`
+	NPMVersion  string `yaml:"npmVersion"`
+	PyPIVersion string `yaml:"pypiVersion"`
+	Content     string `yaml:"-"`
+}
+
+// pulumiPackage names a Pulumi project across GitHub and the package
+// registries
+type pulumiPackage struct {
+	name  string // "pulumi" or "aws"
+	repo  string // pulumi/pulumi-aws
+	npm   string // @pulumi/aws
+	pypi  string // pulumi-aws
+	nuget string // Pulumi.Aws
+}
+
+// pulumiNPMVersion is the part of an npm version document read here
+type pulumiNPMVersion struct {
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+// pulumiPyPIRelease is the part of a PyPI JSON document read here
+type pulumiPyPIRelease struct {
+	Info struct {
+		Version        string `json:"version"`
+		RequiresPython string `json:"requires_python"`
+	} `json:"info"`
+}
+
+type PulumiFetcher struct {
+	*BaseFetcher
+}
+
+func NewPulumiFetcher(cacheDir string) *PulumiFetcher {
+	return &PulumiFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchPulumiInfo fetches a release of the Pulumi CLI (pkg empty, "cli", or
+// "pulumi") or of a provider package ("aws", "@pulumi/aws", "pulumi-aws"):
+// its GitHub release notes, the matching npm and PyPI versions, and install
+// commands. An empty or "latest" version fetches the latest release.
+func (f *PulumiFetcher) FetchPulumiInfo(pkg, version string) (*PulumiInfo, error) {
+	p, err := parsePulumiPackage(pkg)
+	if err != nil {
+		return nil, err
+	}
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "latest" {
+		version = ""
+	}
+	return shareFetch(f.flights, flightKey("FetchPulumiInfo", p.name, version), f.infoPath(p, version), func() (*PulumiInfo, error) {
+		return f.fetchPulumiInfo(p, version)
+	})
+}
+
+// parsePulumiPackage resolves the package names of the CLI or a provider
+func parsePulumiPackage(pkg string) (pulumiPackage, error) {
+	name := strings.ToLower(strings.TrimSpace(pkg))
+	name = strings.TrimPrefix(name, "@pulumi/")
+	name = strings.TrimPrefix(strings.ReplaceAll(name, "_", "-"), "pulumi-")
+	switch name {
+	case "", "cli", "pulumi":
+		return pulumiPackage{name: "pulumi", repo: "pulumi/pulumi", npm: "@pulumi/pulumi", pypi: "pulumi", nuget: "Pulumi"}, nil
+	}
+	if strings.Trim(name, "abcdefghijklmnopqrstuvwxyz0123456789-") != "" {
+		return pulumiPackage{}, fmt.Errorf("invalid Pulumi package %q (expected e.g. 'aws' or '@pulumi/aws')", pkg)
+	}
+
+	// azure-native is published to NuGet as Pulumi.AzureNative
+	nuget := "Pulumi."
+	for _, part := range strings.Split(name, "-") {
+		if part != "" {
+			nuget += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return pulumiPackage{
+		name:  name,
+		repo:  "pulumi/pulumi-" + name,
+		npm:   "@pulumi/" + name,
+		pypi:  "pulumi-" + name,
+		nuget: nuget,
+	}, nil
+}
+
+// infoPath returns the cache path of the info on a release of a package.
+// The CLI joins the Pulumi documentation's versions; providers are kept
+// apart so their releases do not rank as CLI versions.
+func (f *PulumiFetcher) infoPath(p pulumiPackage, version string) string {
+	if p.name != "pulumi" {
+		return f.getCache().GetFilePath("pulumi", "providers", p.name, fmt.Sprintf("%s.md", cache.EntryName(cmp.Or(version, "latest"))))
+	}
+	return f.getCache().GetFilePath("pulumi", "versions", fmt.Sprintf("%s.md", cache.EntryName(cmp.Or(version, "latest"))))
+}
+
+func (f *PulumiFetcher) fetchPulumiInfo(p pulumiPackage, version string) (*PulumiInfo, error) {
+	cachedPath := f.infoPath(p, version)
+	info, err := f.loadPulumiInfoFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Pulumi %s %s from cache\n", p.name, info.Version)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Pulumi %s %s from GitHub, npm, and PyPI...\n", p.name, cmp.Or(version, "latest"))
+
+	releaseURL := fmt.Sprintf("%s/%s/releases/latest", githubReposAPI, p.repo)
+	if version != "" {
+		releaseURL = fmt.Sprintf("%s/%s/releases/tags/v%s", githubReposAPI, p.repo, url.PathEscape(version))
+	}
+	var release githubRelease
+	releaseFound, err := f.getJSON(releaseURL, &release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s release: %w", p.repo, err)
+	}
+
+	// The registries are optional; the release notes stand on their own
+	npmURL := fmt.Sprintf("%s/%s/%s", npmRegistryURL, p.npm, cmp.Or(version, "latest"))
+	var npmVersion pulumiNPMVersion
+	npmFound, err := f.getJSON(npmURL, &npmVersion)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s from npm: %v\n", p.npm, err)
+	}
+
+	pypiURL := fmt.Sprintf("%s/%s/json", pypiJSONURL, p.pypi)
+	if version != "" {
+		pypiURL = fmt.Sprintf("%s/%s/%s/json", pypiJSONURL, p.pypi, url.PathEscape(version))
+	}
+	var pypiRelease pulumiPyPIRelease
+	if _, err := f.getJSON(pypiURL, &pypiRelease); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s from PyPI: %v\n", p.pypi, err)
+	}
+
+	if !releaseFound && !npmFound {
+		if version != "" {
+			return nil, fmt.Errorf("pulumi package %s version %s not found", p.name, version)
+		}
+		return nil, fmt.Errorf("pulumi package %s not found (no %s repository or %s npm package)", p.name, p.repo, p.npm)
+	}
+
+	info = &PulumiInfo{
+		Package:     p.name,
+		Version:     cmp.Or(strings.TrimPrefix(release.TagName, "v"), npmVersion.Version, version),
+		ReleaseDate: releaseDate(release.PublishedAt),
+		ReleaseURL:  release.HTMLURL,
+		NPMVersion:  npmVersion.Version,
+		PyPIVersion: pypiRelease.Info.Version,
+	}
+	info.Content = f.buildPulumiContent(p, info, release.Body, npmVersion.Description, pypiRelease.Info.RequiresPython)
+
+	if err := f.savePulumiInfoAsMarkdown(cachedPath, info); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache Pulumi info: %v\n", err)
+	}
+
+	return info, nil
+}
+
+// pulumiGoModule is the Go SDK module of a release: the CLI's is versioned
+// under sdk/v3 and a provider's under sdk/v<major>
+func pulumiGoModule(p pulumiPackage, version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	if p.name == "pulumi" {
+		return fmt.Sprintf("github.com/pulumi/pulumi/sdk/v%s", cmp.Or(major, "3"))
+	}
+	module := "github.com/" + p.repo + "/sdk"
+	if major != "" && major != "0" && major != "1" {
+		module += "/v" + major
+	}
+	return module
+}
+
+func (f *PulumiFetcher) buildPulumiContent(p pulumiPackage, info *PulumiInfo, releaseNotes, description, requiresPython string) string {
+	var content strings.Builder
+
+	if p.name == "pulumi" {
+		fmt.Fprintf(&content, "# Pulumi %s\n\n", info.Version)
+	} else {
+		fmt.Fprintf(&content, "# Pulumi %s provider %s\n\n", p.name, info.Version)
+	}
+	if description != "" {
+		fmt.Fprintf(&content, "%s\n\n", description)
+	}
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "**Release Notes:** [v%s](%s)\n\n", info.Version, info.ReleaseURL)
+	}
+
+	content.WriteString("## Packages\n\n")
+	content.WriteString("| Registry | Package | Version |\n")
+	content.WriteString("|----------|---------|---------|\n")
+	fmt.Fprintf(&content, "| npm | [%s](https://www.npmjs.com/package/%s) | %s |\n", p.npm, p.npm, cmp.Or(info.NPMVersion, "not published"))
+	fmt.Fprintf(&content, "| PyPI | [%s](https://pypi.org/project/%s/) | %s |\n", p.pypi, p.pypi, cmp.Or(info.PyPIVersion, "not published"))
+	fmt.Fprintf(&content, "| Go | %s | v%s |\n", pulumiGoModule(p, info.Version), info.Version)
+	fmt.Fprintf(&content, "| NuGet | [%s](https://www.nuget.org/packages/%s) | %s |\n\n", p.nuget, p.nuget, info.Version)
+	if requiresPython != "" {
+		fmt.Fprintf(&content, "**Requires Python:** %s\n\n", requiresPython)
+	}
+
+	content.WriteString("## Installation\n\n")
+	if p.name == "pulumi" {
+		content.WriteString("### Using the install script (macOS, Linux)\n\n")
+		content.WriteString("```bash\n")
+		fmt.Fprintf(&content, "curl -fsSL https://get.pulumi.com | sh -s -- --version %s\n", info.Version)
+		content.WriteString("```\n\n")
+
+		content.WriteString("### Using Homebrew\n\n")
+		content.WriteString("```bash\n")
+		content.WriteString("brew install pulumi/tap/pulumi\n")
+		content.WriteString("```\n\n")
+	}
+
+	content.WriteString("### Node.js\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "npm install %s@%s\n", p.npm, cmp.Or(info.NPMVersion, info.Version))
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Python\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "pip install %s==%s\n", p.pypi, cmp.Or(info.PyPIVersion, info.Version))
+	content.WriteString("```\n\n")
+
+	content.WriteString("### Go\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "go get %s@v%s\n", pulumiGoModule(p, info.Version), info.Version)
+	content.WriteString("```\n\n")
+
+	content.WriteString("### .NET\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "dotnet add package %s --version %s\n", p.nuget, info.Version)
+	content.WriteString("```\n\n")
+
+	notes := strings.TrimSpace(strings.ReplaceAll(releaseNotes, "\r\n", "\n"))
+	if notes != "" {
+		content.WriteString("## Release Notes\n\n")
+		notes = markdown.DemoteHeadings(notes, 1)
+		content.WriteString(strings.TrimSpace(truncateMarkdown(notes, maxReleaseNotesChars, "The release notes are truncated; see GitHub for the rest.")))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	content.WriteString("For detailed documentation, visit:\n\n")
+	if p.name == "pulumi" {
+		content.WriteString("- [Pulumi Documentation](https://www.pulumi.com/docs/)\n")
+		content.WriteString("- [Pulumi CLI Reference](https://www.pulumi.com/docs/cli/)\n")
+		content.WriteString("- [Pulumi Changelog](https://github.com/pulumi/pulumi/blob/master/CHANGELOG.md)\n")
+	} else {
+		fmt.Fprintf(&content, "- [%s in the Pulumi Registry](%s/%s/)\n", p.name, pulumiRegistryURL, p.name)
+		fmt.Fprintf(&content, "- [API Docs](%s/%s/api-docs/)\n", pulumiRegistryURL, p.name)
+	}
+	fmt.Fprintf(&content, "- [GitHub Releases](https://github.com/%s/releases)\n", p.repo)
+
+	return content.String()
+}
+
+func (f *PulumiFetcher) savePulumiInfoAsMarkdown(filePath string, info *PulumiInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "package: \"%s\"\n", info.Package)
+	fmt.Fprintf(&content, "version: \"%s\"\n", escapeYAML(info.Version))
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	if info.ReleaseURL != "" {
+		fmt.Fprintf(&content, "releaseURL: \"%s\"\n", escapeYAML(info.ReleaseURL))
+	}
+	if info.NPMVersion != "" {
+		fmt.Fprintf(&content, "npmVersion: \"%s\"\n", escapeYAML(info.NPMVersion))
+	}
+	if info.PyPIVersion != "" {
+		fmt.Fprintf(&content, "pypiVersion: \"%s\"\n", escapeYAML(info.PyPIVersion))
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *PulumiFetcher) loadPulumiInfoFromMarkdown(filePath string) (*PulumiInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info PulumiInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -6,10 +6,12 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
 )
 
 type PythonPackageInfo struct {
@@ -20,9 +22,15 @@ type PythonPackageInfo struct {
 	Repository string `yaml:"repository"`
 	License    string `yaml:"license"`
 	Author     string `yaml:"author"`
-	Content    string `yaml:"-"`
+	// Description is the project description (usually the README) as markdown
+	Description string `yaml:"-"`
+	Content     string `yaml:"-"`
 }
 
+// maxPythonDescriptionChars bounds the project description kept in the
+// package info; the rest stays a link away on PyPI
+const maxPythonDescriptionChars = 30000
+
 type PythonFetcher struct {
 	*BaseFetcher
 }
@@ -35,14 +43,19 @@ func NewPythonFetcher(cacheDir string) *PythonFetcher {
 
 // FetchPackageInfo fetches information about a Python package from PyPI
 func (f *PythonFetcher) FetchPackageInfo(packageName, version string) (*PythonPackageInfo, error) {
-	// Sanitize package name for file system
-	safeName := strings.ReplaceAll(packageName, "/", "_")
-	if version != "" {
-		safeName = fmt.Sprintf("%s_%s", safeName, version)
-	}
+	return shareFetch(f.flights, flightKey("FetchPackageInfo", packageName, version), f.packagePath(packageName, version), func() (*PythonPackageInfo, error) {
+		return f.fetchPackageInfo(packageName, version)
+	})
+}
 
+// packagePath returns the cache path of the info on a package
+func (f *PythonFetcher) packagePath(packageName, version string) string {
+	return f.getCache().GetFilePath("python", "packages", fmt.Sprintf("%s.md", cache.EntryName(packageName, version)))
+}
+
+func (f *PythonFetcher) fetchPackageInfo(packageName, version string) (*PythonPackageInfo, error) {
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("python", "packages", fmt.Sprintf("%s.md", safeName))
+	cachedPath := f.packagePath(packageName, version)
 	pkgInfo, err := f.loadPackageInfoFromMarkdown(cachedPath)
 	if err == nil && pkgInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Python package '%s' from cache\n", packageName)
@@ -95,6 +108,7 @@ func (f *PythonFetcher) FetchPackageInfo(packageName, version string) (*PythonPa
 		pkgInfo.Homepage = getStringFromMap(info, "home_page")
 		pkgInfo.License = getStringFromMap(info, "license")
 		pkgInfo.Author = getStringFromMap(info, "author")
+		pkgInfo.Description = pythonDescription(getStringFromMap(info, "description"), getStringFromMap(info, "description_content_type"))
 
 		// Try to get repository from project_urls
 		if projectURLs, ok := info["project_urls"].(map[string]interface{}); ok {
@@ -109,7 +123,7 @@ func (f *PythonFetcher) FetchPackageInfo(packageName, version string) (*PythonPa
 	}
 
 	// Build content
-	pkgInfo.Content = f.buildPackageContent(pkgInfo)
+	pkgInfo.Content = f.buildPackageContent(pkgInfo) + f.advisoriesSection("pip", pkgInfo.Name, pkgInfo.Version)
 
 	// Cache the result
 	if err := f.savePackageInfoAsMarkdown(cachedPath, pkgInfo); err != nil {
@@ -156,10 +170,7 @@ func (f *PythonFetcher) buildPackageContent(info *PythonPackageInfo) string {
 
 	content.WriteString("### Using requirements.txt\n\n")
 	content.WriteString("```\n")
-	content.WriteString(info.Name)
-	if info.Version != "" {
-		fmt.Fprintf(&content, "==%s", info.Version)
-	}
+	content.WriteString(pythonRequirement(info.Name, info.Version, nil))
 	content.WriteString("\n```\n\n")
 
 	content.WriteString("### Using Poetry\n\n")
@@ -170,6 +181,12 @@ func (f *PythonFetcher) buildPackageContent(info *PythonPackageInfo) string {
 	}
 	content.WriteString("\n```\n\n")
 
+	if info.Description != "" {
+		content.WriteString("## Description\n\n")
+		content.WriteString(info.Description)
+		content.WriteString("\n")
+	}
+
 	content.WriteString("## Documentation\n\n")
 	fmt.Fprintf(&content, "For detailed documentation, visit [PyPI](https://pypi.org/project/%s/)\n", info.Name)
 
@@ -177,12 +194,6 @@ func (f *PythonFetcher) buildPackageContent(info *PythonPackageInfo) string {
 }
 
 func (f *PythonFetcher) savePackageInfoAsMarkdown(filePath string, info *PythonPackageInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
@@ -209,7 +220,7 @@ func (f *PythonFetcher) savePackageInfoAsMarkdown(filePath string, info *PythonP
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *PythonFetcher) loadPackageInfoFromMarkdown(filePath string) (*PythonPackageInfo, error) {
@@ -218,7 +229,7 @@ func (f *PythonFetcher) loadPackageInfoFromMarkdown(filePath string) (*PythonPac
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -255,6 +266,29 @@ func (f *PythonFetcher) loadPackageInfoFromMarkdown(filePath string) (*PythonPac
 	}, nil
 }
 
+// pythonDescription converts a PyPI project description to markdown by its
+// content type: Markdown is kept, reStructuredText (the default when no type
+// is declared) is converted, and plain text is kept as is. Headings are
+// demoted to sit under the package info's "## Description".
+func pythonDescription(description, contentType string) string {
+	description = strings.TrimSpace(strings.ReplaceAll(description, "\r\n", "\n"))
+	// Old uploads without a description say "UNKNOWN"
+	if description == "" || description == "UNKNOWN" {
+		return ""
+	}
+
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	switch strings.TrimSpace(mediaType) {
+	case "text/markdown":
+		description = markdown.DemoteHeadings(description, 2)
+	case "text/plain":
+	default:
+		description = markdown.DemoteHeadings(markdown.FromRST(description), 2)
+	}
+
+	return strings.TrimSpace(truncateMarkdown(description, maxPythonDescriptionChars, "The description is truncated; see PyPI for the rest.")) + "\n"
+}
+
 func getStringFromMap(data map[string]interface{}, key string) string {
 	if val, ok := data[key].(string); ok {
 		return val
@@ -0,0 +1,403 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	pythonDocsBaseURL    = "https://docs.python.org"
+	pythonReleasesAPIURL = "https://www.python.org/api/v2/downloads/release/?is_published=true"
+
+	// maxPythonWhatsNewChars bounds the What's New page kept in the version
+	// info; the rest stays a link away on docs.python.org
+	maxPythonWhatsNewChars = 80000
+
+	// maxPythonReleases caps the releases listed for one version
+	maxPythonReleases = 30
+)
+
+var (
+	// pythonVersionPattern matches "3.12", "3.12.1", "3.13.0rc2", and the
+	// like, optionally prefixed by "python" or "v"
+	pythonVersionPattern = regexp.MustCompile(`^(?i:python\s*|v)?((\d+)\.(\d+))(?:\.(\d+)([a-z]+\d+)?)?$`)
+	pythonReleaseName    = regexp.MustCompile(`^Python (\d+\.\d+)(\.\d+)?([a-z]+\d+)?$`)
+)
+
+type PythonVersionInfo struct {
+	// Version is the requested version: a feature release ("3.12") or a
+	// release of it ("3.12.1")
+	Version     string `yaml:"version"`
+	ReleaseDate string `yaml:"releaseDate"`
+	ReleaseURL  string `yaml:"releaseURL"`
+	// Degraded lists the content checks the What's New page failed
+	Degraded []string `yaml:"degraded"`
+	Content  string   `yaml:"-"`
+}
+
+// pythonRelease is a published CPython release listed by python.org
+type pythonRelease struct {
+	Version     string `json:"version"`
+	Date        string `json:"date"`
+	Prerelease  bool   `json:"prerelease"`
+	NotesURL    string `json:"notesURL,omitempty"`
+	FeatureLine string `json:"featureLine"`
+}
+
+// FetchPythonVersion fetches the "What's New in Python X.Y" page of a CPython
+// version from docs.python.org, with the dates of its releases
+func (f *PythonFetcher) FetchPythonVersion(version string) (*PythonVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchPythonVersion", version), f.versionPath(version), func() (*PythonVersionInfo, error) {
+		return f.fetchPythonVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Python version
+func (f *PythonFetcher) versionPath(version string) string {
+	version = strings.TrimLeft(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "python"), " v")
+	return f.getCache().GetFilePath("python", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *PythonFetcher) fetchPythonVersion(version string) (*PythonVersionInfo, error) {
+	m := pythonVersionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if m == nil {
+		return nil, fmt.Errorf("invalid Python version %q (expected e.g. '3.12' or '3.12.1')", version)
+	}
+	version = strings.TrimLeft(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "python"), " v")
+	featureLine := m[1]
+
+	// Check cache first
+	cachedPath := f.versionPath(version)
+	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
+	if err == nil && versionInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Python %s info from cache\n", version)
+		return versionInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching Python %s information from docs.python.org...\n", version)
+
+	whatsNewURL, whatsNew, err := f.fetchWhatsNew(featureLine)
+	if err != nil {
+		return nil, err
+	}
+
+	// Release dates are an addition; the release notes stand without them
+	releases, err := f.fetchPythonReleases()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch Python release dates: %v\n", err)
+	}
+
+	var lineReleases []pythonRelease
+	for _, release := range releases {
+		if release.FeatureLine == featureLine {
+			lineReleases = append(lineReleases, release)
+		}
+	}
+	if version != featureLine && len(releases) > 0 && !containsPythonRelease(lineReleases, version) {
+		return nil, fmt.Errorf("python %s has not been released", version)
+	}
+
+	versionInfo = &PythonVersionInfo{
+		Version:    version,
+		ReleaseURL: whatsNewURL,
+	}
+	for _, release := range lineReleases {
+		// The first final release of a feature line is its release date
+		if release.Version == version || version == featureLine && release.Version == featureLine+".0" {
+			versionInfo.ReleaseDate = release.Date
+		}
+	}
+	versionInfo.Content = buildPythonVersionContent(versionInfo, featureLine, lineReleases, whatsNew)
+	versionInfo.Degraded = checkShape("python", whatsNewURL, shapeCheck{"heading", hasMarkdownHeading(whatsNew)})
+
+	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+	}
+
+	return versionInfo, nil
+}
+
+// fetchWhatsNew fetches the What's New page of a feature line as markdown.
+// Versions still in development are only documented under /dev/.
+func (f *PythonFetcher) fetchWhatsNew(featureLine string) (string, string, error) {
+	var lastErr error
+	for _, docs := range []string{"3", "dev"} {
+		pageURL := fmt.Sprintf("%s/%s/whatsnew/%s.html", pythonDocsBaseURL, docs, featureLine)
+		body, status, err := f.get(pageURL)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to fetch What's New in Python %s: %w", featureLine, err)
+		}
+		if status == http.StatusNotFound {
+			lastErr = fmt.Errorf("no What's New in Python %s found on docs.python.org", featureLine)
+			continue
+		}
+		if status != http.StatusOK {
+			return "", "", fmt.Errorf("unexpected status code %d for What's New in Python %s", status, featureLine)
+		}
+
+		content, err := whatsNewMarkdown(body, pageURL)
+		if err != nil {
+			return "", "", err
+		}
+		return pageURL, content, nil
+	}
+	return "", "", lastErr
+}
+
+// fetchPythonReleases lists the published CPython releases, newest first
+func (f *PythonFetcher) fetchPythonReleases() ([]pythonRelease, error) {
+	indexPath := f.getCache().GetFilePath("python", "releases.json")
+
+	var releases []pythonRelease
+	if loaded, err := f.getCache().Load(indexPath, &releases); err == nil && loaded && len(releases) > 0 {
+		return releases, nil
+	}
+
+	body, status, err := f.get(pythonReleasesAPIURL)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", status)
+	}
+
+	var payload []struct {
+		Name            string `json:"name"`
+		ReleaseDate     string `json:"release_date"`
+		PreRelease      bool   `json:"pre_release"`
+		ReleaseNotesURL string `json:"release_notes_url"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse release list: %w", err)
+	}
+
+	for _, p := range payload {
+		m := pythonReleaseName.FindStringSubmatch(strings.TrimSpace(p.Name))
+		if m == nil {
+			continue
+		}
+		releases = append(releases, pythonRelease{
+			Version:     strings.TrimPrefix(p.Name, "Python "),
+			Date:        releaseDate(p.ReleaseDate),
+			Prerelease:  p.PreRelease || m[3] != "",
+			NotesURL:    p.ReleaseNotesURL,
+			FeatureLine: m[1],
+		})
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].Date > releases[j].Date
+	})
+
+	if err := f.getCache().Save(indexPath, releases); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache Python releases: %v\n", err)
+	}
+	return releases, nil
+}
+
+func (f *PythonFetcher) get(url string) ([]byte, int, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	return body, resp.StatusCode, err
+}
+
+// whatsNewMarkdown converts the body of a Sphinx page to markdown, without
+// its navigation or heading permalinks
+func whatsNewMarkdown(page []byte, pageURL string) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	body := findNode(doc, func(n *html.Node) bool {
+		return n.Data == "div" && (hasAttr(n, "role", "main") || hasClassToken(n, "body"))
+	})
+	if body == nil {
+		body = doc
+	}
+	removeElements(body, func(n *html.Node) bool {
+		return n.Data == "a" && hasClassToken(n, "headerlink")
+	})
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, body); err != nil {
+		return "", fmt.Errorf("failed to render page: %w", err)
+	}
+	content := strings.TrimSpace(markdown.FromHTML(buf.String(), pageURL))
+	if content == "" {
+		return "", fmt.Errorf("no content found in %s", pageURL)
+	}
+	// The page's title sits under the "## What's New" heading
+	return markdown.DemoteHeadings(content, 2), nil
+}
+
+func findNode(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if n.Type == html.ElementNode && match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findNode(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func removeElements(n *html.Node, match func(*html.Node) bool) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode && match(c) {
+			n.RemoveChild(c)
+		} else {
+			removeElements(c, match)
+		}
+		c = next
+	}
+}
+
+func hasAttr(n *html.Node, key, value string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == key && attr.Val == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPythonRelease(releases []pythonRelease, version string) bool {
+	for _, release := range releases {
+		if release.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func buildPythonVersionContent(info *PythonVersionInfo, featureLine string, releases []pythonRelease, whatsNew string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Python %s\n\n", info.Version)
+
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "**Release Date:** %s\n\n", info.ReleaseDate)
+	}
+	released := false
+	for _, release := range releases {
+		if !release.Prerelease {
+			fmt.Fprintf(&content, "**Latest Release:** %s (%s)\n\n", release.Version, release.Date)
+			released = true
+			break
+		}
+	}
+	fmt.Fprintf(&content, "**What's New:** [What's New in Python %s](%s)\n\n", featureLine, info.ReleaseURL)
+
+	if len(releases) > 0 {
+		fmt.Fprintf(&content, "## Python %s Releases\n\n", featureLine)
+		content.WriteString("| Version | Date | Notes |\n")
+		content.WriteString("|---------|------|-------|\n")
+		for i, release := range releases {
+			if i == maxPythonReleases {
+				break
+			}
+			version := release.Version
+			if release.Prerelease {
+				version += " (pre-release)"
+			}
+			notes := "-"
+			if release.NotesURL != "" {
+				notes = fmt.Sprintf("[changelog](%s)", release.NotesURL)
+			}
+			fmt.Fprintf(&content, "| %s | %s | %s |\n", version, orDash(release.Date), notes)
+		}
+		if len(releases) > maxPythonReleases {
+			fmt.Fprintf(&content, "\n%d older releases omitted.\n", len(releases)-maxPythonReleases)
+		}
+		content.WriteString("\n")
+	}
+
+	if released {
+		content.WriteString("## Installation\n\n")
+		content.WriteString("```bash\n")
+		fmt.Fprintf(&content, "uv python install %s\n", info.Version)
+		fmt.Fprintf(&content, "pyenv install %s\n", info.Version)
+		content.WriteString("```\n\n")
+	}
+
+	content.WriteString("## What's New\n\n")
+	content.WriteString(truncateMarkdown(whatsNew, maxPythonWhatsNewChars, "What's New truncated; see "+info.ReleaseURL))
+	content.WriteString("\n")
+
+	return content.String()
+}
+
+func (f *PythonFetcher) saveVersionInfoAsMarkdown(filePath string, info *PythonVersionInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	if info.ReleaseDate != "" {
+		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
+	}
+	fmt.Fprintf(&content, "releaseURL: \"%s\"\n", info.ReleaseURL)
+	writeDegraded(&content, info.Degraded)
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *PythonFetcher) loadVersionInfoFromMarkdown(filePath string) (*PythonVersionInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info PythonVersionInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if degradedExpired(f.getCache(), filePath, info.Degraded) {
+		return nil, fmt.Errorf("degraded entry expired")
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -1,22 +1,45 @@
 package fetcher
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	reactDocsRawURL = "https://raw.githubusercontent.com/reactjs/react.dev/main/src/content/reference"
+	reactDocsURL    = "https://react.dev"
 )
 
-type ReactVersionInfo struct {
-	Version     string `yaml:"version"`
-	ReleaseDate string `yaml:"releaseDate"`
-	ReleaseURL  string `yaml:"releaseURL"`
+// reactReferenceSections lists react.dev reference directories in lookup order
+var reactReferenceSections = []string{
+	"react",
+	"react-dom",
+	"react-dom/hooks",
+	"react-dom/components",
+	"react-dom/client",
+	"react-dom/server",
+	"react-dom/static",
+	"rsc",
+	"react/legacy",
+}
+
+type ReactVersionInfo = ReleaseVersionInfo
+
+type ReactAPIInfo struct {
+	Symbol      string `yaml:"symbol"`
+	Title       string `yaml:"title"`
+	Section     string `yaml:"section"`
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
 	Content     string `yaml:"-"`
 }
 
@@ -32,6 +55,17 @@ func NewReactFetcher(cacheDir string) *ReactFetcher {
 
 // FetchReactVersion fetches information about a specific React version
 func (f *ReactFetcher) FetchReactVersion(version string) (*ReactVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchReactVersion", version), f.versionPath(version), func() (*ReactVersionInfo, error) {
+		return f.fetchReactVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a React version
+func (f *ReactFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("react", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *ReactFetcher) fetchReactVersion(version string) (*ReactVersionInfo, error) {
 	// Normalize version (add 'v' prefix if missing for GitHub API)
 	githubVersion := version
 	if !strings.HasPrefix(version, "v") {
@@ -39,7 +73,7 @@ func (f *ReactFetcher) FetchReactVersion(version string) (*ReactVersionInfo, err
 	}
 
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("react", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
 	if err == nil && versionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded React version '%s' from cache\n", version)
@@ -79,42 +113,13 @@ func (f *ReactFetcher) FetchReactVersion(version string) (*ReactVersionInfo, err
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse GitHub release data
-	var releaseData map[string]interface{}
-	if err := json.Unmarshal(body, &releaseData); err != nil {
-		return nil, fmt.Errorf("failed to parse release data: %w", err)
-	}
-
-	// Extract version information
-	versionInfo = &ReactVersionInfo{
-		Version: version,
-	}
-
-	// Extract release URL
-	if htmlURL, ok := releaseData["html_url"].(string); ok {
-		versionInfo.ReleaseURL = htmlURL
-	}
-
-	// Extract release date
-	if publishedAt, ok := releaseData["published_at"].(string); ok {
-		// Parse and format the date
-		if t, err := time.Parse(time.RFC3339, publishedAt); err == nil {
-			versionInfo.ReleaseDate = t.Format("2006-01-02")
-		} else {
-			versionInfo.ReleaseDate = publishedAt
-		}
-	}
-
-	// Extract release notes from body
-	releaseNotes := ""
-	if body, ok := releaseData["body"].(string); ok {
-		releaseNotes = body
+	versionInfo, err = renderReleaseVersion(version, body, f.buildVersionContent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build content
-	versionInfo.Content = f.buildVersionContent(versionInfo, releaseNotes)
-
-	// Cache the result
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
 	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
 	}
@@ -174,17 +179,12 @@ func (f *ReactFetcher) buildVersionContent(info *ReactVersionInfo, releaseNotes
 }
 
 func (f *ReactFetcher) saveVersionInfoAsMarkdown(filePath string, info *ReactVersionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
 	if info.ReleaseDate != "" {
 		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
 	}
@@ -196,16 +196,16 @@ func (f *ReactFetcher) saveVersionInfoAsMarkdown(filePath string, info *ReactVer
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *ReactFetcher) loadVersionInfoFromMarkdown(filePath string) (*ReactVersionInfo, error) {
-	expired, err := f.getCache().IsExpired(filePath)
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
 	if err != nil || expired {
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -220,12 +220,23 @@ func (f *ReactFetcher) loadVersionInfoFromMarkdown(filePath string) (*ReactVersi
 		Version     string `yaml:"version"`
 		ReleaseDate string `yaml:"releaseDate"`
 		ReleaseURL  string `yaml:"releaseURL"`
+		Template    int    `yaml:"template"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if info, err := renderReleaseVersion(meta.Version, payload, f.buildVersionContent); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered React version '%s' with current templates\n", meta.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
 	return &ReactVersionInfo{
 		Version:     meta.Version,
 		ReleaseDate: meta.ReleaseDate,
@@ -233,3 +244,218 @@ func (f *ReactFetcher) loadVersionInfoFromMarkdown(filePath string) (*ReactVersi
 		Content:     strings.TrimSpace(parts[2]),
 	}, nil
 }
+
+// FetchReactAPI fetches a react.dev API reference page (e.g. useEffect, Suspense, "use client")
+// and converts it to markdown
+func (f *ReactFetcher) FetchReactAPI(symbol string) (*ReactAPIInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchReactAPI", symbol), f.apiPath(symbol), func() (*ReactAPIInfo, error) {
+		return f.fetchReactAPI(symbol)
+	})
+}
+
+// apiPath returns the cache path of an API reference page, or "" for an
+// invalid symbol
+func (f *ReactFetcher) apiPath(symbol string) string {
+	candidates := reactAPICandidates(symbol)
+	if len(candidates) == 0 {
+		return ""
+	}
+	return f.getCache().GetFilePath("react", "api", fmt.Sprintf("%s.md", cache.EntryName(candidates[0])))
+}
+
+func (f *ReactFetcher) fetchReactAPI(symbol string) (*ReactAPIInfo, error) {
+	candidates := reactAPICandidates(symbol)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("invalid React API symbol: %q", symbol)
+	}
+
+	// Check cache first
+	cachedPath := f.apiPath(symbol)
+	apiInfo, err := f.loadAPIInfoFromMarkdown(cachedPath)
+	if err == nil && apiInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded React API '%s' from cache\n", symbol)
+		return apiInfo, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching React API '%s' from react.dev...\n", symbol)
+
+	for _, candidate := range candidates {
+		for _, section := range reactAPISections(candidate) {
+			page, name := section, candidate
+			if strings.Contains(candidate, "/") {
+				page, name = filepath.ToSlash(filepath.Dir(candidate)), filepath.Base(candidate)
+			}
+
+			source, err := f.fetchReactReferenceSource(page, name)
+			if err != nil {
+				return nil, err
+			}
+			if source == "" {
+				continue
+			}
+
+			doc := markdown.FromMDX(source, markdown.MDXOptions{BaseURL: reactDocsURL})
+
+			apiInfo = &ReactAPIInfo{
+				Symbol:      symbol,
+				Title:       doc.Title,
+				Section:     page,
+				URL:         fmt.Sprintf("%s/reference/%s/%s", reactDocsURL, page, name),
+				Description: doc.Description,
+			}
+			if apiInfo.Title == "" {
+				apiInfo.Title = name
+			}
+			apiInfo.Content = f.buildAPIContent(apiInfo, doc.Body)
+
+			if err := f.saveAPIInfoAsMarkdown(cachedPath, apiInfo); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache React API info: %v\n", err)
+			}
+
+			return apiInfo, nil
+		}
+	}
+
+	return nil, fmt.Errorf("react API %s not found on react.dev", symbol)
+}
+
+// reactAPICandidates normalizes a symbol into reference page names,
+// e.g. "useEffect()" -> "useEffect" and "server components" -> "server-components"
+func reactAPICandidates(symbol string) []string {
+	symbol = strings.TrimSpace(symbol)
+	symbol = strings.Trim(symbol, "'\"`<>/")
+	symbol = strings.TrimSuffix(symbol, "()")
+	symbol = strings.TrimPrefix(symbol, "React.")
+	symbol = strings.TrimPrefix(symbol, "reference/")
+	if symbol == "" {
+		return nil
+	}
+
+	candidates := []string{symbol}
+	if strings.ContainsAny(symbol, " \t") {
+		candidates = append(candidates, strings.ToLower(strings.Join(strings.Fields(symbol), "-")))
+	}
+	return candidates
+}
+
+// reactAPISections returns the reference directories to probe for a candidate
+func reactAPISections(candidate string) []string {
+	if strings.Contains(candidate, "/") {
+		return []string{""}
+	}
+	return reactReferenceSections
+}
+
+// fetchReactReferenceSource returns the MDX source of a reference page, or "" if it does not exist
+func (f *ReactFetcher) fetchReactReferenceSource(section, name string) (string, error) {
+	url := fmt.Sprintf("%s/%s/%s.md", reactDocsRawURL, section, name)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch React reference: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("react.dev source returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return string(body), nil
+}
+
+func (f *ReactFetcher) buildAPIContent(info *ReactAPIInfo, body string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Title)
+
+	if info.Description != "" {
+		fmt.Fprintf(&content, "**Summary:** %s\n\n", info.Description)
+	}
+
+	fmt.Fprintf(&content, "**Package:** %s\n\n", reactAPIPackage(info.Section))
+	fmt.Fprintf(&content, "**Reference:** [%s](%s)\n\n", info.Title, info.URL)
+
+	content.WriteString(body)
+	content.WriteString("\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [%s on react.dev](%s)\n", info.Title, info.URL)
+	content.WriteString("- [React API Reference](https://react.dev/reference/react)\n")
+
+	return content.String()
+}
+
+// reactAPIPackage maps a reference section to the npm entry point that exports it
+func reactAPIPackage(section string) string {
+	switch {
+	case section == "rsc":
+		return "react (Server Components)"
+	case strings.HasPrefix(section, "react-dom/client"), strings.HasPrefix(section, "react-dom/server"), strings.HasPrefix(section, "react-dom/static"):
+		return section
+	case strings.HasPrefix(section, "react-dom"):
+		return "react-dom"
+	default:
+		return "react"
+	}
+}
+
+func (f *ReactFetcher) saveAPIInfoAsMarkdown(filePath string, info *ReactAPIInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "symbol: \"%s\"\n", escapeYAML(info.Symbol))
+	fmt.Fprintf(&content, "title: \"%s\"\n", escapeYAML(info.Title))
+	fmt.Fprintf(&content, "section: \"%s\"\n", info.Section)
+	fmt.Fprintf(&content, "url: \"%s\"\n", info.URL)
+	if info.Description != "" {
+		fmt.Fprintf(&content, "description: \"%s\"\n", escapeYAML(info.Description))
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *ReactFetcher) loadAPIInfoFromMarkdown(filePath string) (*ReactAPIInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta ReactAPIInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	meta.Content = strings.TrimSpace(parts[2])
+	return &meta, nil
+}
@@ -0,0 +1,86 @@
+package fetcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// templateVersion identifies the markdown templates of this build. Entries
+// record the version they were rendered with in their frontmatter; bump it
+// whenever a build*Content function changes its output, and entries kept
+// with their upstream payload are re-rendered on their next read instead of
+// being served in the old layout until they expire.
+const templateVersion = 1
+
+// ReleaseVersionInfo is the version document of a product released on
+// GitHub, as the release fetchers cache it
+type ReleaseVersionInfo struct {
+	Version     string `yaml:"version"`
+	ReleaseDate string `yaml:"releaseDate"`
+	ReleaseURL  string `yaml:"releaseURL"`
+	Prerelease  bool   `yaml:"prerelease"`
+	Content     string `yaml:"-"`
+}
+
+// renderReleaseVersion renders a release payload of the GitHub API as the
+// version document of a product, with build laying out its content from
+// the release notes. An empty version is taken from the release tag.
+func renderReleaseVersion(version string, payload []byte, build func(info *ReleaseVersionInfo, releaseNotes string) string) (*ReleaseVersionInfo, error) {
+	release, err := parseRelease(payload)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		version = strings.TrimPrefix(release.TagName, "v")
+	}
+
+	info := &ReleaseVersionInfo{
+		Version:     version,
+		ReleaseDate: releaseDate(release.PublishedAt),
+		ReleaseURL:  release.HTMLURL,
+		Prerelease:  release.Prerelease,
+	}
+	info.Content = build(info, release.Body)
+	return info, nil
+}
+
+// rawPayloadPath returns where the upstream payload of a rendered entry is
+// kept: beside it, as <entry>.raw.json
+func rawPayloadPath(filePath string) string {
+	return strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".raw.json"
+}
+
+// saveRawPayload keeps the upstream payload an entry was rendered from, so
+// a later build can re-render the entry without fetching it again
+func (b *BaseFetcher) saveRawPayload(filePath string, payload []byte) {
+	if err := b.getCache().WriteFile(rawPayloadPath(filePath), payload); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache raw payload: %v\n", err)
+	}
+}
+
+// agePath returns the file whose age is that of a rendered entry's upstream
+// data: the payload, when kept, since re-rendering rewrites the entry but
+// not its payload; otherwise the entry itself. Checking it keeps a
+// re-rendered entry from living a full TTL past its fetch.
+func (b *BaseFetcher) agePath(filePath string) string {
+	if _, err := b.getCache().ModTime(rawPayloadPath(filePath)); err == nil {
+		return rawPayloadPath(filePath)
+	}
+	return filePath
+}
+
+// stalePayload returns the upstream payload of an entry rendered with older
+// templates. It reports false when the entry is current or was cached
+// without its payload, in which case it is served as it is.
+func (b *BaseFetcher) stalePayload(filePath string, renderedWith int) ([]byte, bool) {
+	if renderedWith >= templateVersion {
+		return nil, false
+	}
+	payload, err := b.getCache().ReadFile(rawPayloadPath(filePath))
+	if err != nil {
+		return nil, false
+	}
+	return payload, true
+}
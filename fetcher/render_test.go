@@ -0,0 +1,48 @@
+package fetcher
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+// TestReRenderKeepsFetchAge checks that re-rendering a cached entry with
+// current templates does not restart its TTL
+func TestReRenderKeepsFetchAge(t *testing.T) {
+	dir := t.TempDir()
+	f := &HelmFetcher{BaseFetcher: &BaseFetcher{cache: cache.NewManager(dir, time.Hour)}}
+	entry := f.getCache().GetFilePath("helm", "versions", "v3.14.0.md")
+
+	// An entry rendered with older templates, fetched half an hour ago
+	if err := f.getCache().WriteFile(entry, []byte("---\nversion: \"v3.14.0\"\n---\n\nold layout\n")); err != nil {
+		t.Fatal(err)
+	}
+	f.saveRawPayload(entry, []byte(`{"tag_name":"v3.14.0","body":"Fixes","html_url":"https://github.com/helm/helm/releases/tag/v3.14.0"}`))
+	fetched := time.Now().Add(-30 * time.Minute)
+	for _, path := range []string{entry, rawPayloadPath(entry)} {
+		if err := os.Chtimes(path, fetched, fetched); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := f.loadVersionInfoFromMarkdown(entry)
+	if err != nil {
+		t.Fatalf("loading the stale entry: %v", err)
+	}
+	if !strings.Contains(info.Content, "# Helm v3.14.0") || !strings.Contains(info.Content, "Fixes") {
+		t.Errorf("entry not re-rendered: %q", info.Content)
+	}
+
+	// Past the TTL of the fetch, the re-rendered entry expires although it
+	// was written just now
+	fetched = time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(rawPayloadPath(entry), fetched, fetched); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.loadVersionInfoFromMarkdown(entry); err == nil {
+		t.Error("re-rendered entry still served past the TTL of its fetch")
+	}
+}
@@ -0,0 +1,276 @@
+package fetcher
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	rfcEditorURL      = "https://www.rfc-editor.org/rfc"
+	ietfDraftsURL     = "https://www.ietf.org/archive/id"
+	datatrackerDocURL = "https://datatracker.ietf.org/doc"
+	datatrackerDocAPI = "https://datatracker.ietf.org/api/v1/doc/document"
+
+	// maxRFCChars is the largest document returned whole; larger ones, such
+	// as RFC 9110, are summarized by their contents so a section can be
+	// asked for
+	maxRFCChars = 60000
+)
+
+var (
+	// rfcNumberPattern matches "9110", "RFC 9110", "rfc9110", and "RFC-9110"
+	rfcNumberPattern = regexp.MustCompile(`(?i)^(?:rfc)?[\s-]*(\d{1,5})$`)
+	// draftNamePattern matches an Internet-Draft name, with its revision
+	// ("-05") when one is given
+	draftNamePattern = regexp.MustCompile(`^(draft-[a-z0-9-]+?)(?:-(\d{2}))?$`)
+)
+
+type RFCInfo struct {
+	// Name is the document name: rfc9110, or draft-ietf-httpbis-cache-19
+	Name    string
+	Title   string
+	URL     string
+	Content string
+}
+
+// rfcMetadata is the rfc-editor.org record of an RFC, rfc9110.json
+type rfcMetadata struct {
+	Title       string   `json:"title"`
+	Status      string   `json:"status"`
+	PubStatus   string   `json:"pub_status"`
+	PubDate     string   `json:"pub_date"`
+	Obsoletes   []string `json:"obsoletes"`
+	ObsoletedBy []string `json:"obsoleted_by"`
+	Updates     []string `json:"updates"`
+	UpdatedBy   []string `json:"updated_by"`
+	ErrataURL   string   `json:"errata_url"`
+}
+
+// draftMetadata is the Datatracker record of an Internet-Draft
+type draftMetadata struct {
+	Title   string `json:"title"`
+	Rev     string `json:"rev"`
+	Expires string `json:"expires"`
+}
+
+type RFCFetcher struct {
+	*BaseFetcher
+}
+
+func NewRFCFetcher(cacheDir string) *RFCFetcher {
+	return &RFCFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchRFC fetches an RFC ("9110", "RFC 9110") or Internet-Draft
+// ("draft-ietf-httpbis-cache", optionally with its revision) as markdown,
+// each heading anchored as on rfc-editor.org (section-3.2, appendix-A).
+// With a section ("3.2", "appendix-A", or a title such as "Security
+// Considerations"), only that section and its subsections are returned.
+func (f *RFCFetcher) FetchRFC(document, section string) (*RFCInfo, error) {
+	document = strings.ToLower(strings.TrimSpace(document))
+	section = strings.TrimSpace(section)
+	if m := rfcNumberPattern.FindStringSubmatch(document); m != nil {
+		number, _ := strconv.Atoi(m[1])
+		return shareFetch(f.flights, flightKey("FetchRFC", strconv.Itoa(number), section), "", func() (*RFCInfo, error) {
+			return f.fetchRFC(number, section)
+		})
+	}
+	if m := draftNamePattern.FindStringSubmatch(document); m != nil {
+		return shareFetch(f.flights, flightKey("FetchDraft", m[1], m[2], section), "", func() (*RFCInfo, error) {
+			return f.fetchDraft(m[1], m[2], section)
+		})
+	}
+	return nil, fmt.Errorf("invalid document %q (expected an RFC number such as '9110' or a draft name such as 'draft-ietf-httpbis-cache')", document)
+}
+
+func (f *RFCFetcher) fetchRFC(number int, section string) (*RFCInfo, error) {
+	name := fmt.Sprintf("rfc%d", number)
+	info := &RFCInfo{
+		Name:  name,
+		Title: fmt.Sprintf("RFC %d", number),
+		URL:   fmt.Sprintf("%s/%s", rfcEditorURL, name),
+	}
+
+	body, found, err := f.fetchCached(f.getCache().GetFilePath("rfc", name+".txt"), fmt.Sprintf("%s/%s.txt", rfcEditorURL, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch RFC %d: %w", number, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("RFC %d not found", number)
+	}
+
+	// The record is optional; the text stands on its own
+	var meta rfcMetadata
+	if err := f.fetchJSON(f.getCache().GetFilePath("rfc", name+".json"), fmt.Sprintf("%s/%s.json", rfcEditorURL, name), &meta); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch the rfc-editor.org record of RFC %d: %v\n", number, err)
+	}
+
+	var header strings.Builder
+	fmt.Fprintf(&header, "**Document:** [RFC %d](%s)\n\n", number, info.URL)
+	if status := cmp.Or(meta.Status, meta.PubStatus); status != "" {
+		fmt.Fprintf(&header, "**Status:** %s\n\n", titleCase(status))
+	}
+	if meta.PubDate != "" {
+		fmt.Fprintf(&header, "**Published:** %s\n\n", meta.PubDate)
+	}
+	for _, rel := range []struct {
+		label string
+		docs  []string
+	}{
+		{"Obsoleted by", meta.ObsoletedBy},
+		{"Updated by", meta.UpdatedBy},
+		{"Obsoletes", meta.Obsoletes},
+		{"Updates", meta.Updates},
+	} {
+		if links := rfcLinks(rel.docs); links != "" {
+			fmt.Fprintf(&header, "**%s:** %s\n\n", rel.label, links)
+		}
+	}
+	if meta.ErrataURL != "" {
+		fmt.Fprintf(&header, "**Errata:** [reported errata](%s)\n\n", meta.ErrataURL)
+	}
+
+	doc := parseRFCText(string(body))
+	title := fmt.Sprintf("RFC %d", number)
+	if t := cmp.Or(meta.Title, doc.title); t != "" {
+		title += ": " + t
+	}
+	info.Title = title
+	return f.render(info, doc, header.String(), section)
+}
+
+func (f *RFCFetcher) fetchDraft(name, rev, section string) (*RFCInfo, error) {
+	// The record names the latest revision; a given one is only titled
+	var meta draftMetadata
+	if err := f.fetchJSON(f.getCache().GetFilePath("rfc", "drafts", name+".json"), fmt.Sprintf("%s/%s/?format=json", datatrackerDocAPI, name), &meta); err != nil {
+		if rev == "" {
+			return nil, fmt.Errorf("failed to look up %s on the Datatracker: %w", name, err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: failed to look up %s on the Datatracker: %v\n", name, err)
+	}
+	if rev == "" {
+		rev = meta.Rev
+	}
+	if rev == "" {
+		return nil, fmt.Errorf("no revision of %s found", name)
+	}
+
+	full := name + "-" + rev
+	info := &RFCInfo{
+		Name: full,
+		URL:  fmt.Sprintf("%s/%s/%s/", datatrackerDocURL, name, rev),
+	}
+
+	body, found, err := f.fetchCached(f.getCache().GetFilePath("rfc", "drafts", full+".txt"), fmt.Sprintf("%s/%s.txt", ietfDraftsURL, full))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", full, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("internet-draft %s not found", full)
+	}
+
+	var header strings.Builder
+	fmt.Fprintf(&header, "**Document:** [%s](%s)\n\n", full, info.URL)
+	if meta.Rev != "" && meta.Rev != rev {
+		fmt.Fprintf(&header, "**Latest revision:** [-%s](%s/%s/)\n\n", meta.Rev, datatrackerDocURL, name)
+	}
+	if meta.Expires != "" && meta.Rev == rev {
+		fmt.Fprintf(&header, "**Expires:** %s\n\n", releaseDate(meta.Expires))
+	}
+	header.WriteString("Internet-Drafts are work in progress and may be replaced or dropped; cite them as such.\n\n")
+
+	doc := parseRFCText(string(body))
+	info.Title = cmp.Or(meta.Title, doc.title, full)
+	return f.render(info, doc, header.String(), section)
+}
+
+// render builds the markdown of a whole document, of its contents when it
+// is too large, or of one section
+func (f *RFCFetcher) render(info *RFCInfo, doc *rfcDocument, header, section string) (*RFCInfo, error) {
+	var content strings.Builder
+
+	if section != "" {
+		sections := doc.find(section)
+		if sections == nil {
+			return nil, fmt.Errorf("section %q not found in %s", section, info.Name)
+		}
+		s := sections[0]
+		fmt.Fprintf(&content, "# %s, %s\n\n", info.Title, s.label())
+		fmt.Fprintf(&content, "**Section:** [%s](%s#%s)\n\n", s.label(), info.URL, s.id)
+		for _, s := range sections {
+			s.write(&content, s.level-sections[0].level+2)
+		}
+		info.Content = content.String()
+		return info, nil
+	}
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Title)
+	content.WriteString(header)
+
+	var body strings.Builder
+	for _, s := range doc.sections {
+		s.write(&body, s.level+1)
+	}
+	if body.Len() <= maxRFCChars {
+		content.WriteString(body.String())
+		info.Content = content.String()
+		return info, nil
+	}
+
+	if abstract := doc.find("abstract"); abstract != nil {
+		abstract[0].write(&content, 2)
+	}
+	content.WriteString("## Contents\n\n")
+	fmt.Fprintf(&content, "The document is too long to return whole (%d characters); ask for a section by its number or title.\n\n", body.Len())
+	for _, s := range doc.sections {
+		if s.level > 2 {
+			continue
+		}
+		fmt.Fprintf(&content, "%s- [%s](%s#%s)\n", strings.Repeat("  ", s.level-1), s.label(), info.URL, s.id)
+	}
+	info.Content = content.String()
+	return info, nil
+}
+
+// fetchJSON decodes the JSON document at url into v, cached at cachedPath
+func (f *RFCFetcher) fetchJSON(cachedPath, url string, v interface{}) error {
+	body, found, err := f.fetchCached(cachedPath, url)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s not found", url)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// rfcLinks links the RFCs of an rfc-editor.org record, listed as "RFC2616"
+func rfcLinks(docs []string) string {
+	links := make([]string, 0, len(docs))
+	for _, d := range docs {
+		m := rfcNumberPattern.FindStringSubmatch(strings.TrimSpace(d))
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		links = append(links, fmt.Sprintf("[RFC %d](%s/rfc%d)", n, rfcEditorURL, n))
+	}
+	return strings.Join(links, ", ")
+}
+
+// titleCase turns an upper-case status such as "PROPOSED STANDARD" into
+// "Proposed Standard"
+func titleCase(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
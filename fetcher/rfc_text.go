@@ -0,0 +1,363 @@
+package fetcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// rfcPageFooterPattern and rfcPageHeaderPattern match the lines
+	// paginated RFCs and drafts repeat around each form feed
+	rfcPageFooterPattern = regexp.MustCompile(`\[Page \d+\]\s*$`)
+	rfcPageHeaderPattern = regexp.MustCompile(`^(?:RFC \d+|Internet-Draft)\s`)
+
+	rfcNumberedHeadingPattern = regexp.MustCompile(`^(\d+(?:\.\d+)*)\.?\s+(\S.*)$`)
+	rfcAppendixHeadingPattern = regexp.MustCompile(`^Appendix ([A-Z])\.?\s+(\S.*)$`)
+	rfcAppendixSubheadPattern = regexp.MustCompile(`^([A-Z](?:\.\d+)+)\.?\s+(\S.*)$`)
+	rfcBulletPattern          = regexp.MustCompile(`^(o|\*|-|\+|\d+\.|\(\d+\)|[a-z]\.)\s+`)
+	rfcAlignedPattern         = regexp.MustCompile(`\S {3,}\S`)
+	rfcABNFRulePattern        = regexp.MustCompile(`^[A-Za-z][\w-]*\s+=/?\s`)
+	rfcHTTPMessagePattern     = regexp.MustCompile(`^(?:[A-Z]+ \S+ HTTP/\d|HTTP/\d(?:\.\d)? \d{3})`)
+	rfcHeaderFieldLinePattern = regexp.MustCompile(`^[A-Za-z][\w-]*:(?:\s|$)`)
+	rfcSectionPrefixPattern   = regexp.MustCompile(`(?i)^(?:section|sect\.|sec\.|§|appendix)[\s-]*`)
+)
+
+// rfcDocument is the plain text of an RFC or draft split into sections
+type rfcDocument struct {
+	title    string
+	sections []*rfcSection
+}
+
+// rfcSection is a heading of the text and the lines under it, up to the
+// next heading
+type rfcSection struct {
+	// id is the anchor rfc-editor.org gives the section: section-3.2,
+	// appendix-A, or appendix-A.1; unnumbered sections such as the
+	// abstract get a slug of their title
+	id string
+	// number is 3.2, A, or A.1, and empty for unnumbered sections
+	number string
+	title  string
+	level  int
+	lines  []string
+}
+
+// parseRFCText splits the plain text of an RFC or draft into sections. Page
+// headers and footers are dropped, and everything before the abstract,
+// except the title, is left out.
+func parseRFCText(text string) *rfcDocument {
+	lines := unpaginateRFC(strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n"))
+
+	doc := &rfcDocument{}
+	start := len(lines)
+	for i, line := range lines {
+		if strings.TrimRight(line, " ") == "Abstract" {
+			start = i
+			break
+		}
+	}
+	doc.title = rfcTitle(lines[:start])
+
+	var current *rfcSection
+	for _, line := range lines[start:] {
+		if line != "" && line[0] != ' ' {
+			current = newRFCSection(strings.TrimSpace(line))
+			// The contents are rebuilt from the headings
+			if t := strings.ToLower(current.title); t == "table of contents" || t == "contents" {
+				current = &rfcSection{}
+				continue
+			}
+			doc.sections = append(doc.sections, current)
+			continue
+		}
+		if current != nil {
+			current.lines = append(current.lines, line)
+		}
+	}
+	return doc
+}
+
+// unpaginateRFC removes the page footers and headers around form feeds,
+// rejoining paragraphs that run across a page break
+func unpaginateRFC(lines []string) []string {
+	out := make([]string, 0, len(lines))
+	afterBreak := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if strings.Contains(line, "\f") {
+			line = strings.TrimSpace(strings.ReplaceAll(line, "\f", ""))
+			afterBreak = true
+			if line == "" {
+				continue
+			}
+		}
+		if rfcPageFooterPattern.MatchString(line) && line[0] != ' ' {
+			continue
+		}
+		if afterBreak {
+			if line == "" {
+				continue
+			}
+			if rfcPageHeaderPattern.MatchString(line) {
+				continue
+			}
+			afterBreak = false
+			// Blank lines left at the foot of the page: keep one only
+			// where the page ended a paragraph
+			for len(out) > 0 && out[len(out)-1] == "" {
+				out = out[:len(out)-1]
+			}
+			if len(out) > 0 && (line[0] != ' ' || strings.HasSuffix(out[len(out)-1], ".") || strings.HasSuffix(out[len(out)-1], ":")) {
+				out = append(out, "")
+			}
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// rfcTitle finds the title, centered under the header block of the first
+// page
+func rfcTitle(lines []string) string {
+	var title []string
+	pastHeader := false
+	for _, line := range lines {
+		switch {
+		case line == "":
+			if len(title) > 0 {
+				return collapseSpace(strings.Join(title, " "))
+			}
+			pastHeader = true
+		case pastHeader && line[0] == ' ':
+			title = append(title, line)
+		}
+	}
+	return collapseSpace(strings.Join(title, " "))
+}
+
+func newRFCSection(heading string) *rfcSection {
+	if m := rfcNumberedHeadingPattern.FindStringSubmatch(heading); m != nil {
+		return &rfcSection{id: "section-" + m[1], number: m[1], title: m[2], level: strings.Count(m[1], ".") + 1}
+	}
+	if m := rfcAppendixHeadingPattern.FindStringSubmatch(heading); m != nil {
+		return &rfcSection{id: "appendix-" + m[1], number: m[1], title: m[2], level: 1}
+	}
+	if m := rfcAppendixSubheadPattern.FindStringSubmatch(heading); m != nil {
+		return &rfcSection{id: "appendix-" + m[1], number: m[1], title: m[2], level: strings.Count(m[1], ".") + 1}
+	}
+	return &rfcSection{id: headingAnchor(heading), title: heading, level: 1}
+}
+
+// label names a section as it is cited: "Section 3.2: Title"
+func (s *rfcSection) label() string {
+	switch {
+	case s.number == "":
+		return s.title
+	case s.number[0] >= 'A' && s.number[0] <= 'Z':
+		return fmt.Sprintf("Appendix %s: %s", s.number, s.title)
+	default:
+		return fmt.Sprintf("Section %s: %s", s.number, s.title)
+	}
+}
+
+// write renders the section as markdown, its heading at level and
+// anchored with its id
+func (s *rfcSection) write(b *strings.Builder, level int) {
+	heading := s.title
+	switch {
+	case s.number == "":
+	case strings.Contains(s.id, "appendix-") && !strings.Contains(s.number, "."):
+		heading = fmt.Sprintf("Appendix %s. %s", s.number, s.title)
+	default:
+		heading = fmt.Sprintf("%s. %s", s.number, s.title)
+	}
+	fmt.Fprintf(b, "<a id=\"%s\"></a>\n\n%s %s\n\n", s.id, strings.Repeat("#", min(level, 6)), heading)
+	b.WriteString(rfcMarkdown(s.lines))
+}
+
+// find returns the section a query names, by number ("3.2", "A.1"),
+// anchor ("section-3.2", "appendix-A"), or title, followed by its
+// subsections; nil when none matches
+func (d *rfcDocument) find(query string) []*rfcSection {
+	q := strings.ToLower(strings.TrimSpace(query))
+	number := strings.TrimSuffix(strings.TrimSpace(rfcSectionPrefixPattern.ReplaceAllString(q, "")), ".")
+
+	match := -1
+	for i, s := range d.sections {
+		if s.number != "" && strings.EqualFold(s.number, number) || strings.EqualFold(s.id, q) {
+			match = i
+			break
+		}
+	}
+	if match < 0 {
+		for i, s := range d.sections {
+			if strings.EqualFold(s.title, q) {
+				match = i
+				break
+			}
+		}
+	}
+	if match < 0 {
+		for i, s := range d.sections {
+			if strings.Contains(strings.ToLower(s.title), q) {
+				match = i
+				break
+			}
+		}
+	}
+	if match < 0 {
+		return nil
+	}
+
+	sections := []*rfcSection{d.sections[match]}
+	for _, s := range d.sections[match+1:] {
+		if s.level <= d.sections[match].level {
+			break
+		}
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// rfcBlock is a run of lines between blank lines
+type rfcBlock struct {
+	lines  []string
+	indent int
+}
+
+// rfcMarkdown converts the body of a section: paragraphs are unwrapped,
+// bullets become list items, and figures, ABNF, and protocol examples are
+// kept as code blocks
+func rfcMarkdown(lines []string) string {
+	var blocks []rfcBlock
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			blocks = append(blocks, rfcBlock{lines: current, indent: rfcIndent(current)})
+			current = nil
+		}
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	var b strings.Builder
+	listIndent := -1
+	for i := 0; i < len(blocks); i++ {
+		block := blocks[i]
+		first := strings.TrimSpace(block.lines[0])
+		bullet := rfcBulletPattern.FindStringSubmatch(first)
+
+		switch {
+		case bullet == nil && rfcLooksLikeCode(block):
+			// Figures are often split by blank lines; keep them whole
+			code := rfcDedent(block.lines, block.indent)
+			for i+1 < len(blocks) && rfcLooksLikeCode(blocks[i+1]) && rfcBulletPattern.FindString(strings.TrimSpace(blocks[i+1].lines[0])) == "" {
+				i++
+				code += "\n\n" + rfcDedent(blocks[i].lines, block.indent)
+			}
+			writeCode(&b, "", code)
+			listIndent = -1
+		case bullet != nil:
+			marker := bullet[1]
+			if strings.ContainsAny(marker[:1], "o*-+") {
+				marker = "-"
+			}
+			nest := ""
+			if listIndent >= 0 && block.indent > listIndent {
+				nest = "  "
+			} else {
+				listIndent = block.indent
+			}
+			text := rfcUnwrap(append([]string{strings.TrimPrefix(first, bullet[0])}, block.lines[1:]...))
+			fmt.Fprintf(&b, "%s%s %s\n\n", nest, marker, text)
+		case listIndent >= 0 && block.indent > listIndent:
+			fmt.Fprintf(&b, "  %s\n\n", rfcUnwrap(block.lines))
+		case len(block.lines) == 1 && len(first) < 72 && !strings.HasSuffix(first, ".") &&
+			i+1 < len(blocks) && blocks[i+1].indent > block.indent && !rfcLooksLikeCode(blocks[i+1]):
+			// A term, defined by the indented paragraph that follows
+			fmt.Fprintf(&b, "**%s**\n\n", first)
+			listIndent = block.indent
+		default:
+			fmt.Fprintf(&b, "%s\n\n", rfcUnwrap(block.lines))
+			listIndent = -1
+		}
+	}
+	return b.String()
+}
+
+// rfcLooksLikeCode tells figures, tables, ABNF, and protocol examples from
+// prose, which is never aligned in columns
+func rfcLooksLikeCode(block rfcBlock) bool {
+	headerLines := 0
+	for _, line := range block.lines {
+		t := strings.TrimSpace(line)
+		switch {
+		case strings.Contains(t, "+--"), strings.Contains(t, "--+"), strings.HasPrefix(t, "|"),
+			rfcAlignedPattern.MatchString(t), rfcABNFRulePattern.MatchString(t), rfcHTTPMessagePattern.MatchString(t),
+			t == "{", t == "}", strings.HasPrefix(t, "<") && strings.HasSuffix(t, ">"):
+			return true
+		case rfcHeaderFieldLinePattern.MatchString(t):
+			headerLines++
+		}
+	}
+	if block.indent > 3 && headerLines == len(block.lines) {
+		return true
+	}
+
+	// Short, deeply indented lines that do not end sentences
+	if block.indent > 3 && len(block.lines) > 1 {
+		chars := 0
+		for _, line := range block.lines {
+			chars += len(strings.TrimSpace(line))
+		}
+		last := strings.TrimSpace(block.lines[len(block.lines)-1])
+		if chars/len(block.lines) < 40 && !strings.HasSuffix(last, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// rfcUnwrap joins the lines of a paragraph. A line ending in a hyphen was
+// broken at the hyphen of a compound word, which the join keeps.
+func rfcUnwrap(lines []string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if b.Len() > 0 {
+			s := b.String()
+			if !(len(s) > 1 && s[len(s)-1] == '-' && s[len(s)-2] != '-' && s[len(s)-2] != ' ') {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(line)
+	}
+	return collapseSpace(b.String())
+}
+
+func rfcIndent(lines []string) int {
+	indent := -1
+	for _, line := range lines {
+		n := len(line) - len(strings.TrimLeft(line, " "))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	return max(indent, 0)
+}
+
+func rfcDedent(lines []string, indent int) string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		n := min(indent, len(line)-len(strings.TrimLeft(line, " ")))
+		out[i] = line[n:]
+	}
+	return strings.Join(out, "\n")
+}
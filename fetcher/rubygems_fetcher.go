@@ -0,0 +1,314 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+type GemInfo struct {
+	Name          string          `yaml:"name"`
+	Version       string          `yaml:"version"`
+	Summary       string          `yaml:"summary"`
+	Homepage      string          `yaml:"homepage"`
+	Repository    string          `yaml:"repository"`
+	Documentation string          `yaml:"documentation"`
+	License       string          `yaml:"license"`
+	Authors       string          `yaml:"authors"`
+	Dependencies  []GemDependency `yaml:"-"`
+	Content       string          `yaml:"-"`
+}
+
+type GemDependency struct {
+	Name         string `json:"name"`
+	Requirements string `json:"requirements"`
+	Development  bool   `json:"-"`
+}
+
+type RubyGemsFetcher struct {
+	*BaseFetcher
+}
+
+func NewRubyGemsFetcher(cacheDir string) *RubyGemsFetcher {
+	return &RubyGemsFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchGemInfo fetches information about a Ruby gem from rubygems.org
+func (f *RubyGemsFetcher) FetchGemInfo(gemName, version string) (*GemInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchGemInfo", gemName, version), f.gemPath(gemName, version), func() (*GemInfo, error) {
+		return f.fetchGemInfo(gemName, version)
+	})
+}
+
+// gemPath returns the cache path of the info on a gem
+func (f *RubyGemsFetcher) gemPath(gemName, version string) string {
+	return f.getCache().GetFilePath("ruby", "gems", fmt.Sprintf("%s.md", cache.EntryName(gemName, version)))
+}
+
+func (f *RubyGemsFetcher) fetchGemInfo(gemName, version string) (*GemInfo, error) {
+	// Check cache first
+	cachedPath := f.gemPath(gemName, version)
+	gemInfo, err := f.loadGemInfoFromMarkdown(cachedPath)
+	if err == nil && gemInfo != nil {
+		fmt.Fprintf(os.Stderr, "Loaded Ruby gem '%s' from cache\n", gemName)
+		return gemInfo, nil
+	}
+
+	// Fetch from rubygems.org
+	fmt.Fprintf(os.Stderr, "Fetching Ruby gem '%s' from rubygems.org...\n", gemName)
+
+	var url string
+	if version != "" {
+		url = fmt.Sprintf("https://rubygems.org/api/v2/rubygems/%s/versions/%s.json", gemName, version)
+	} else {
+		url = fmt.Sprintf("https://rubygems.org/api/v1/gems/%s.json", gemName)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch gem info: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("ruby gem %s not found", gemName)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rubygems.org API returned status %d for gem %s", resp.StatusCode, gemName)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Parse rubygems.org response
+	var gemData struct {
+		Name             string   `json:"name"`
+		Version          string   `json:"version"`
+		Info             string   `json:"info"`
+		Authors          string   `json:"authors"`
+		Licenses         []string `json:"licenses"`
+		HomepageURI      string   `json:"homepage_uri"`
+		SourceCodeURI    string   `json:"source_code_uri"`
+		DocumentationURI string   `json:"documentation_uri"`
+		Dependencies     struct {
+			Development []GemDependency `json:"development"`
+			Runtime     []GemDependency `json:"runtime"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(body, &gemData); err != nil {
+		return nil, fmt.Errorf("failed to parse rubygems.org data: %w", err)
+	}
+
+	// Extract gem information
+	gemInfo = &GemInfo{
+		Name:          gemData.Name,
+		Version:       gemData.Version,
+		Summary:       gemData.Info,
+		Homepage:      gemData.HomepageURI,
+		Repository:    gemData.SourceCodeURI,
+		Documentation: gemData.DocumentationURI,
+		License:       strings.Join(gemData.Licenses, ", "),
+		Authors:       gemData.Authors,
+	}
+	if gemInfo.Name == "" {
+		gemInfo.Name = gemName
+	}
+
+	gemInfo.Dependencies = append(gemInfo.Dependencies, gemData.Dependencies.Runtime...)
+	for _, dep := range gemData.Dependencies.Development {
+		dep.Development = true
+		gemInfo.Dependencies = append(gemInfo.Dependencies, dep)
+	}
+
+	// Build content
+	gemInfo.Content = f.buildGemContent(gemInfo)
+
+	// Cache the result
+	if err := f.saveGemInfoAsMarkdown(cachedPath, gemInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache gem info: %v\n", err)
+	}
+
+	return gemInfo, nil
+}
+
+func (f *RubyGemsFetcher) buildGemContent(info *GemInfo) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Name)
+
+	if info.Summary != "" {
+		fmt.Fprintf(&content, "**Summary:** %s\n\n", info.Summary)
+	}
+
+	fmt.Fprintf(&content, "**Version:** %s\n\n", info.Version)
+
+	if info.Authors != "" {
+		fmt.Fprintf(&content, "**Authors:** %s\n\n", info.Authors)
+	}
+
+	if info.License != "" {
+		fmt.Fprintf(&content, "**License:** %s\n\n", info.License)
+	}
+
+	if info.Homepage != "" {
+		fmt.Fprintf(&content, "**Homepage:** %s\n\n", info.Homepage)
+	}
+
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "**Source Code:** %s\n\n", info.Repository)
+	}
+
+	var runtime, development []GemDependency
+	for _, dep := range info.Dependencies {
+		if dep.Development {
+			development = append(development, dep)
+		} else {
+			runtime = append(runtime, dep)
+		}
+	}
+
+	if len(runtime) > 0 || len(development) > 0 {
+		content.WriteString("## Dependencies\n\n")
+		if len(runtime) > 0 {
+			content.WriteString("### Runtime\n\n")
+			for _, dep := range runtime {
+				fmt.Fprintf(&content, "- `%s` %s\n", dep.Name, dep.Requirements)
+			}
+			content.WriteString("\n")
+		}
+		if len(development) > 0 {
+			content.WriteString("### Development\n\n")
+			for _, dep := range development {
+				fmt.Fprintf(&content, "- `%s` %s\n", dep.Name, dep.Requirements)
+			}
+			content.WriteString("\n")
+		}
+	}
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "gem install %s", info.Name)
+	if info.Version != "" {
+		fmt.Fprintf(&content, " -v %s", info.Version)
+	}
+	content.WriteString("\n```\n\n")
+
+	content.WriteString("### Using Bundler (Gemfile)\n\n")
+	content.WriteString("```ruby\n")
+	content.WriteString(gemfileLine(info.Name, info.Version))
+	content.WriteString("\n```\n\n")
+
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "bundle add %s", info.Name)
+	if info.Version != "" {
+		fmt.Fprintf(&content, " --version \"%s\"", info.Version)
+	}
+	content.WriteString("\n```\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	if info.Documentation != "" {
+		fmt.Fprintf(&content, "- [Documentation](%s)\n", info.Documentation)
+	}
+	fmt.Fprintf(&content, "- [RubyDoc](https://www.rubydoc.info/gems/%s/%s)\n", info.Name, info.Version)
+	fmt.Fprintf(&content, "- [rubygems.org](https://rubygems.org/gems/%s/versions/%s)\n", info.Name, info.Version)
+
+	return content.String()
+}
+
+func (f *RubyGemsFetcher) saveGemInfoAsMarkdown(filePath string, info *GemInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "name: \"%s\"\n", info.Name)
+	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	if info.Summary != "" {
+		fmt.Fprintf(&content, "summary: \"%s\"\n", escapeYAML(info.Summary))
+	}
+	if info.Homepage != "" {
+		fmt.Fprintf(&content, "homepage: \"%s\"\n", info.Homepage)
+	}
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "repository: \"%s\"\n", info.Repository)
+	}
+	if info.Documentation != "" {
+		fmt.Fprintf(&content, "documentation: \"%s\"\n", info.Documentation)
+	}
+	if info.License != "" {
+		fmt.Fprintf(&content, "license: \"%s\"\n", escapeYAML(info.License))
+	}
+	if info.Authors != "" {
+		fmt.Fprintf(&content, "authors: \"%s\"\n", escapeYAML(info.Authors))
+	}
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *RubyGemsFetcher) loadGemInfoFromMarkdown(filePath string) (*GemInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var meta struct {
+		Name          string `yaml:"name"`
+		Version       string `yaml:"version"`
+		Summary       string `yaml:"summary"`
+		Homepage      string `yaml:"homepage"`
+		Repository    string `yaml:"repository"`
+		Documentation string `yaml:"documentation"`
+		License       string `yaml:"license"`
+		Authors       string `yaml:"authors"`
+	}
+
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	return &GemInfo{
+		Name:          meta.Name,
+		Version:       meta.Version,
+		Summary:       meta.Summary,
+		Homepage:      meta.Homepage,
+		Repository:    meta.Repository,
+		Documentation: meta.Documentation,
+		License:       meta.License,
+		Authors:       meta.Authors,
+		Content:       strings.TrimSpace(parts[2]),
+	}, nil
+}
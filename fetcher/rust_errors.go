@@ -0,0 +1,128 @@
+package fetcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// rustErrorCodesURL holds the explanation of each rustc error code, the
+	// source of the error index and of rustc --explain
+	rustErrorCodesURL = "https://raw.githubusercontent.com/rust-lang/rust/master/compiler/rustc_error_codes/src/error_codes"
+	rustErrorIndexURL = "https://doc.rust-lang.org/error_codes"
+)
+
+// rustErrorCodePattern matches rustc error codes such as E0382, as printed
+// in "error[E0382]: borrow of moved value"
+var rustErrorCodePattern = regexp.MustCompile(`\bE(\d{4})\b`)
+
+// rustNonRustFences are the fence languages of error index examples that
+// are not Rust; any other fence, including a bare one, is a Rust example
+var rustNonRustFences = map[string]bool{
+	"text": true, "console": true, "sh": true, "shell": true, "bash": true,
+	"toml": true, "json": true, "c": true, "cpp": true, "asm": true,
+}
+
+func (f *ErrorFetcher) explainRustError(message string) (*ErrorExplanation, error) {
+	code := rustErrorCode(message)
+	if code == "" {
+		return nil, fmt.Errorf("no rustc error code (e.g. E0382) found in %q", message)
+	}
+	return f.FetchRustError(code)
+}
+
+// FetchRustError fetches the explanation of a rustc error code ("E0382",
+// "e0382", or "382") from the error index, with its erroneous and fixed
+// examples as Rust code blocks
+func (f *ErrorFetcher) FetchRustError(code string) (*ErrorExplanation, error) {
+	code = rustErrorCode(strings.ToUpper(strings.TrimSpace(code)))
+	if code == "" {
+		return nil, fmt.Errorf("invalid Rust error code (expected e.g. 'E0382')")
+	}
+	return shareFetch(f.flights, flightKey("FetchRustError", code), f.rustErrorPath(code), func() (*ErrorExplanation, error) {
+		return f.fetchRustError(code)
+	})
+}
+
+// rustErrorPath returns the cache path of the explanation of a Rust error code
+func (f *ErrorFetcher) rustErrorPath(code string) string {
+	return f.getCache().GetFilePath("rust", "errors", code+".md")
+}
+
+func (f *ErrorFetcher) fetchRustError(code string) (*ErrorExplanation, error) {
+	cachedPath := f.rustErrorPath(code)
+	body, found, err := f.fetchCached(cachedPath, fmt.Sprintf("%s/%s.md", rustErrorCodesURL, code))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Rust error %s: %w", code, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("rust error code %s not found in the error index", code)
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# Rust error %s\n\n", code)
+	content.WriteString(strings.TrimSpace(markdown.DemoteHeadings(rustErrorMarkdown(string(body)), 1)))
+	content.WriteString("\n\n")
+	fmt.Fprintf(&content, "**Reference:** [%s](%s/%s.html)\n", code, rustErrorIndexURL, code)
+
+	return &ErrorExplanation{Tool: "rust", Code: code, Content: content.String()}, nil
+}
+
+// rustErrorMarkdown turns the rustdoc markdown of an error index entry into
+// plain markdown: examples are tagged rust instead of with rustdoc's test
+// attributes ("compile_fail,E0382", "edition2018"), and the lines rustdoc
+// hides ("# fn main() {}") are dropped. The prose already tells the
+// erroneous examples from the fixes.
+func rustErrorMarkdown(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	fence, inRust := "", false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" && strings.HasPrefix(trimmed, "```") {
+			fence = "```"
+			attrs := strings.TrimPrefix(trimmed, "```")
+			lang, _, _ := strings.Cut(attrs, ",")
+			lang = strings.TrimSpace(lang)
+			if rustNonRustFences[lang] {
+				out = append(out, line)
+				continue
+			}
+			inRust = true
+			out = append(out, "```rust")
+			continue
+		}
+		if fence != "" && trimmed == fence {
+			fence, inRust = "", false
+			out = append(out, line)
+			continue
+		}
+		if inRust {
+			switch {
+			case trimmed == "#" || strings.HasPrefix(trimmed, "# "):
+				continue
+			case strings.HasPrefix(trimmed, "##"):
+				// "##" escapes a line that starts with "#"
+				line = strings.Replace(line, "##", "#", 1)
+			}
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// rustErrorCode finds the error code in a message, accepting a bare number
+// ("382", "0382") as well
+func rustErrorCode(message string) string {
+	if m := rustErrorCodePattern.FindStringSubmatch(message); m != nil {
+		return "E" + m[1]
+	}
+	digits := strings.TrimPrefix(strings.TrimSpace(message), "E")
+	if len(digits) == 0 || len(digits) > 4 || strings.Trim(digits, "0123456789") != "" {
+		return ""
+	}
+	return "E" + strings.Repeat("0", 4-len(digits)) + digits
+}
@@ -6,10 +6,11 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
 )
 
 type RustCrateInfo struct {
@@ -36,14 +37,19 @@ func NewRustFetcher(cacheDir string) *RustFetcher {
 
 // FetchCrateInfo fetches information about a Rust crate from crates.io
 func (f *RustFetcher) FetchCrateInfo(crateName, version string) (*RustCrateInfo, error) {
-	// Sanitize crate name for file system
-	safeName := strings.ReplaceAll(crateName, "/", "_")
-	if version != "" {
-		safeName = fmt.Sprintf("%s_%s", safeName, version)
-	}
+	return shareFetch(f.flights, flightKey("FetchCrateInfo", crateName, version), f.cratePath(crateName, version), func() (*RustCrateInfo, error) {
+		return f.fetchCrateInfo(crateName, version)
+	})
+}
+
+// cratePath returns the cache path of the info on a crate
+func (f *RustFetcher) cratePath(crateName, version string) string {
+	return f.getCache().GetFilePath("rust", "crates", fmt.Sprintf("%s.md", cache.EntryName(crateName, version)))
+}
 
+func (f *RustFetcher) fetchCrateInfo(crateName, version string) (*RustCrateInfo, error) {
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("rust", "crates", fmt.Sprintf("%s.md", safeName))
+	cachedPath := f.cratePath(crateName, version)
 	crateInfo, err := f.loadCrateInfoFromMarkdown(cachedPath)
 	if err == nil && crateInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Rust crate '%s' from cache\n", crateName)
@@ -123,7 +129,7 @@ func (f *RustFetcher) FetchCrateInfo(crateName, version string) (*RustCrateInfo,
 	}
 
 	// Build content
-	crateInfo.Content = f.buildCrateContent(crateInfo)
+	crateInfo.Content = f.buildCrateContent(crateInfo) + f.advisoriesSection("rust", crateInfo.Name, crateInfo.Version)
 
 	// Cache the result
 	if err := f.saveCrateInfoAsMarkdown(cachedPath, crateInfo); err != nil {
@@ -176,10 +182,7 @@ func (f *RustFetcher) buildCrateContent(info *RustCrateInfo) string {
 	content.WriteString("### Adding to Cargo.toml\n\n")
 	content.WriteString("```toml\n")
 	content.WriteString("[dependencies]\n")
-	content.WriteString(info.Name)
-	if info.Version != "" {
-		fmt.Fprintf(&content, " = \"%s\"", info.Version)
-	}
+	content.WriteString(cargoDependencyLine(info.Name, info.Version, nil, false))
 	content.WriteString("\n```\n\n")
 
 	content.WriteString("## Links\n\n")
@@ -197,12 +200,6 @@ func (f *RustFetcher) buildCrateContent(info *RustCrateInfo) string {
 }
 
 func (f *RustFetcher) saveCrateInfoAsMarkdown(filePath string, info *RustCrateInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
@@ -232,7 +229,7 @@ func (f *RustFetcher) saveCrateInfoAsMarkdown(filePath string, info *RustCrateIn
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *RustFetcher) loadCrateInfoFromMarkdown(filePath string) (*RustCrateInfo, error) {
@@ -241,7 +238,7 @@ func (f *RustFetcher) loadCrateInfoFromMarkdown(filePath string) (*RustCrateInfo
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,124 @@
+package fetcher
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+// degradedTTL is how long an entry whose content checks failed is served
+// before it is fetched again, so that a transient layout break, such as a
+// maintenance page, is not cached for the whole cache TTL
+const degradedTTL = time.Hour
+
+// shapeCheck is an assertion about what a fetcher extracted from an upstream
+// page, such as "the synopsis is not empty". A failed check usually means the
+// page's markup changed and the extraction silently came up short.
+type shapeCheck struct {
+	name string
+	ok   bool
+}
+
+// ShapeFailure counts the failures of one check of one fetcher
+type ShapeFailure struct {
+	Fetcher string
+	Check   string
+	Count   int64
+}
+
+var (
+	shapeMu       sync.Mutex
+	shapeFailures = make(map[[2]string]int64)
+)
+
+// checkShape logs a warning for an entry whose checks failed and counts the
+// failures for /metrics. It returns the names of the failed checks, which
+// callers record as the entry's degraded frontmatter field.
+func checkShape(fetcher, entry string, checks ...shapeCheck) []string {
+	var failed []string
+	for _, c := range checks {
+		if !c.ok {
+			failed = append(failed, c.name)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	shapeMu.Lock()
+	for _, name := range failed {
+		shapeFailures[[2]string{fetcher, name}]++
+	}
+	shapeMu.Unlock()
+
+	fmt.Fprintf(os.Stderr, "Warning: degraded content fetcher=%s entry=%q checks=%s (the upstream page may have changed)\n", fetcher, entry, strings.Join(failed, ","))
+	return failed
+}
+
+// ShapeFailures returns the content checks that failed since the server
+// started, by fetcher and check
+func ShapeFailures() []ShapeFailure {
+	shapeMu.Lock()
+	defer shapeMu.Unlock()
+
+	failures := make([]ShapeFailure, 0, len(shapeFailures))
+	for key, count := range shapeFailures {
+		failures = append(failures, ShapeFailure{Fetcher: key[0], Check: key[1], Count: count})
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Fetcher != failures[j].Fetcher {
+			return failures[i].Fetcher < failures[j].Fetcher
+		}
+		return failures[i].Check < failures[j].Check
+	})
+	return failures
+}
+
+// hasMarkdownHeading reports whether markdown has a heading of level 2 or
+// deeper, that is, a section below the page's title
+func hasMarkdownHeading(md string) bool {
+	for _, line := range strings.Split(md, "\n") {
+		if strings.HasPrefix(line, "##") {
+			return true
+		}
+	}
+	return false
+}
+
+// degradedExpired reports whether a degraded entry has outlived degradedTTL
+// (or the cache TTL, when shorter) and should be fetched again. With a cache
+// TTL of 0 entries never expire, degraded or not.
+func degradedExpired(m *cache.Manager, filePath string, degraded []string) bool {
+	if len(degraded) == 0 {
+		return false
+	}
+	ttl := degradedTTL
+	if t := m.GetTTL(); t < ttl {
+		ttl = t
+	}
+	expired, err := m.ExpiredAfter(filePath, ttl)
+	return err != nil || expired
+}
+
+// expireDegraded removes a cached upstream page whose content checks failed
+// once it is older than degradedTTL, so that the next lookup fetches it again
+func expireDegraded(m *cache.Manager, filePath string, degraded []string) {
+	if degradedExpired(m, filePath, degraded) {
+		if err := m.Remove(filePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove degraded entry %s: %v\n", filePath, err)
+		}
+	}
+}
+
+// writeDegraded adds the degraded frontmatter field, a list of the failed
+// checks, of an entry whose content checks failed
+func writeDegraded(content *strings.Builder, degraded []string) {
+	if len(degraded) > 0 {
+		fmt.Fprintf(content, "degraded: [%s]\n", strings.Join(degraded, ", "))
+	}
+}
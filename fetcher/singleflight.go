@@ -0,0 +1,95 @@
+package fetcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+// flightGroup deduplicates concurrent identical fetches within the process:
+// while a fetch for a key is in flight, later callers wait for it and share
+// its result instead of issuing their own upstream request and cache write.
+// Across replicas sharing a cache, a fetch that misses the cache also holds
+// the cache's fetch lock for its key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+	cache *cache.Manager
+}
+
+type flight struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+func newFlightGroup(m *cache.Manager) *flightGroup {
+	return &flightGroup{calls: make(map[string]*flight), cache: m}
+}
+
+// shareFetch runs fn once for all concurrent callers with the same key.
+// Callers share the returned value, so they must not modify it.
+//
+// entry is the cache file fn looks up before fetching, or "" when fn has no
+// single entry to look up first. While entry is fresh, fn only reads it, so
+// it runs without the fetch lock. Otherwise fn runs under the lock, and its
+// own lookup re-checks the cache once the lock is held, finding the entry a
+// replica wrote while this one waited.
+func shareFetch[T any](g *flightGroup, key, entry string, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-call.done
+		val, _ := call.val.(T)
+		return val, call.err
+	}
+
+	call := &flight{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	// Waiters get this error if fn panics instead of returning
+	call.err = errFetchPanicked
+	if g.cache != nil && !g.fresh(entry) {
+		release := g.cache.Claim(fetchLockName[T](key))
+		defer release()
+	}
+	val, err := fn()
+	call.val, call.err = val, err
+	return val, err
+}
+
+// fresh reports whether the cache entry at path exists and has not expired.
+// An entry a content check marked degraded expires sooner, so fn may still
+// refetch it, without the lock.
+func (g *flightGroup) fresh(path string) bool {
+	return path != "" && g.cache.Fresh(path)
+}
+
+// errFetchPanicked is returned to callers waiting on a fetch that panicked
+var errFetchPanicked = errors.New("the shared fetch failed unexpectedly")
+
+// fetchLockName names the fetch lock of a flight. Fetchers reuse operation
+// names, so the result type tells their flights apart.
+func fetchLockName[T any](key string) string {
+	var zero T
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%T\x00%s", zero, key)))
+	return "fetch:" + hex.EncodeToString(sum[:16])
+}
+
+// flightKey builds a key from an operation name and its arguments
+func flightKey(op string, args ...string) string {
+	return op + "\x00" + strings.Join(args, "\x00")
+}
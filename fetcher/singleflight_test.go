@@ -0,0 +1,62 @@
+package fetcher
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+// countingLocker is an in-memory cache.Locker counting the locks taken
+type countingLocker struct {
+	mu    sync.Mutex
+	tries int
+}
+
+func (l *countingLocker) TryLock(key string, ttl time.Duration) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tries++
+	return "token", nil
+}
+
+func (l *countingLocker) Unlock(key, token string) error {
+	return nil
+}
+
+// TestShareFetchClaimsOnMiss checks that a fetch takes the fetch lock only
+// while its cache entry is missing or expired
+func TestShareFetchClaimsOnMiss(t *testing.T) {
+	dir := t.TempDir()
+	locker := &countingLocker{}
+	m := cache.NewManager(dir, time.Hour)
+	m.SetLocker(locker, time.Second)
+	g := newFlightGroup(m)
+	entry := filepath.Join(dir, "npm", "express.md")
+
+	fetch := func() (string, error) {
+		return "express", m.WriteFile(entry, []byte("express"))
+	}
+	if _, err := shareFetch(g, flightKey("package", "express"), entry, fetch); err != nil {
+		t.Fatal(err)
+	}
+	if locker.tries != 1 {
+		t.Fatalf("fetch on a miss took %d locks, want 1", locker.tries)
+	}
+
+	if _, err := shareFetch(g, flightKey("package", "express"), entry, fetch); err != nil {
+		t.Fatal(err)
+	}
+	if locker.tries != 1 {
+		t.Errorf("fetch of a fresh entry took a lock")
+	}
+
+	if _, err := shareFetch(g, flightKey("versions", "express"), "", fetch); err != nil {
+		t.Fatal(err)
+	}
+	if locker.tries != 2 {
+		t.Errorf("fetch without an entry took %d locks in all, want 2", locker.tries)
+	}
+}
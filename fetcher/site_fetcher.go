@@ -0,0 +1,238 @@
+package fetcher
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/crawler"
+)
+
+const (
+	// sitePrefix names the documentation a crawled site is indexed as
+	sitePrefix = "site-"
+
+	// maxSitePages caps the pages crawled from one site
+	maxSitePages = 1000
+
+	// maxSiteTopicChars bounds the content of one page
+	maxSiteTopicChars = 60000
+)
+
+// SiteInfo describes a crawled documentation site
+type SiteInfo struct {
+	URL string
+	// Documentation is the name the site is searchable under
+	Documentation string
+	Topics        int
+	// Updated is false when the cached crawl was still fresh
+	Updated bool
+	Content string
+}
+
+// siteMetadata is the metadata.json of a crawled site. The provider reads
+// the documentation fields; Site records the crawl.
+type siteMetadata struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+	Site        struct {
+		URL      string `json:"url"`
+		MaxPages int    `json:"maxPages"`
+		// Selectors are those of a custom source, which re-crawls when
+		// they change
+		Selectors map[string]string `json:"selectors,omitempty"`
+		Listed    int               `json:"listed"`
+		Topics    int               `json:"topics"`
+		Failed    int               `json:"failed"`
+		Sections  map[string]int    `json:"sections"`
+	} `json:"site"`
+}
+
+type SiteFetcher struct {
+	*BaseFetcher
+}
+
+// siteCrawl is a crawl of a site into a documentation set
+type siteCrawl struct {
+	url         string
+	maxPages    int
+	docName     string
+	displayName string
+	// selectors are the title and content selectors of a custom source
+	selectors map[string]string
+}
+
+func NewSiteFetcher(cacheDir string) *SiteFetcher {
+	return &SiteFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchSite crawls the pages under a documentation site's base URL
+// ("https://docs.example.com", "example.com/docs") listed in its sitemap,
+// up to maxPages, and indexes each as a topic in the cache directory,
+// where the documentation provider loads it. A crawl younger than the
+// cache TTL with the same page limit is not repeated.
+func (f *SiteFetcher) FetchSite(siteURL string, maxPages int) (*SiteInfo, error) {
+	if maxPages <= 0 {
+		maxPages = crawler.DefaultMaxPages
+	}
+	maxPages = min(maxPages, maxSitePages)
+
+	return shareFetch(f.flights, flightKey("FetchSite", siteURL, fmt.Sprint(maxPages)), "", func() (*SiteInfo, error) {
+		return f.crawl(siteCrawl{url: siteURL, maxPages: maxPages, docName: SiteDocumentation(siteURL)})
+	})
+}
+
+func (f *SiteFetcher) crawl(site siteCrawl) (*SiteInfo, error) {
+	c, err := crawler.New(f.getClient(), site.url, site.maxPages)
+	if err != nil {
+		return nil, err
+	}
+	if c.Selectors, err = crawlSelectors(site.selectors); err != nil {
+		return nil, err
+	}
+
+	docDir := filepath.Join(f.getCache().GetCacheDir(), site.docName)
+	metadataPath := filepath.Join(docDir, "metadata.json")
+
+	var cached siteMetadata
+	if fresh, err := readTopicsMetadata(metadataPath, f.getCache().GetTTL(), &cached); err == nil && fresh &&
+		cached.Site.MaxPages == site.maxPages && maps.Equal(cached.Site.Selectors, site.selectors) {
+		fmt.Fprintf(os.Stderr, "Loaded crawl of '%s' from cache\n", site.url)
+		return siteInfo(&cached, false), nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Crawling '%s' (up to %d pages)...\n", site.url, site.maxPages)
+	result, err := c.Crawl()
+	if err != nil {
+		return nil, fmt.Errorf("failed to crawl %s: %w", site.url, err)
+	}
+	if len(result.Pages) == 0 {
+		return nil, fmt.Errorf("none of the %d pages listed for %s could be read", result.Listed, site.url)
+	}
+
+	base, _ := url.Parse(result.Base)
+	topics := make(map[string]*indexedTopic, len(result.Pages))
+	for _, page := range result.Pages {
+		u, _ := url.Parse(page.URL)
+
+		content := page.Content
+		if !strings.HasPrefix(content, "# ") {
+			content = "# " + page.Title + "\n\n" + content
+		}
+
+		keywords := []string{page.Title, base.Host}
+		if page.Section != "" {
+			keywords = append(keywords, page.Section)
+		}
+
+		topics[page.URL] = &indexedTopic{
+			ID:          u.Host + u.Path,
+			Title:       page.Title,
+			Description: page.Description,
+			Content:     truncateMarkdown(content, maxSiteTopicChars, "Page truncated; see "+page.URL) + "\n",
+			Keywords:    uniqueStrings(keywords),
+			kind:        page.Section,
+		}
+	}
+
+	kinds, err := writeTopics(docDir, topics)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &siteMetadata{
+		Name:        site.docName,
+		DisplayName: site.displayName,
+		Description: fmt.Sprintf("Documentation crawled from %s", result.Base),
+	}
+	if metadata.DisplayName == "" {
+		metadata.DisplayName = base.Host + strings.TrimSuffix(base.Path, "/")
+	}
+	metadata.Site.URL = result.Base
+	metadata.Site.MaxPages = site.maxPages
+	metadata.Site.Selectors = site.selectors
+	metadata.Site.Listed = result.Listed
+	metadata.Site.Failed = result.Failed
+	metadata.Site.Topics = countTopics(kinds)
+	delete(kinds, "")
+	metadata.Site.Sections = kinds
+
+	if err := writeJSON(metadataPath, metadata); err != nil {
+		return nil, fmt.Errorf("failed to write metadata: %w", err)
+	}
+	return siteInfo(metadata, true), nil
+}
+
+// crawlSelectors parses the "title" and "content" selectors of a custom
+// source
+func crawlSelectors(selectors map[string]string) (crawler.Selectors, error) {
+	var s crawler.Selectors
+	for key, value := range selectors {
+		sel, err := crawler.ParseSelector(value)
+		if err != nil {
+			return s, err
+		}
+		switch key {
+		case "title":
+			s.Title = sel
+		case "content":
+			s.Content = sel
+		default:
+			return s, fmt.Errorf("unknown selector %q (must be 'title' or 'content')", key)
+		}
+	}
+	return s, nil
+}
+
+// SiteDocumentation returns the documentation name a site is indexed as,
+// e.g. "site-docs.example.com" or "site-example.com_docs"
+func SiteDocumentation(siteURL string) string {
+	site := strings.TrimSpace(siteURL)
+	if i := strings.Index(site, "://"); i >= 0 {
+		site = site[i+3:]
+	}
+	if i := strings.IndexAny(site, "?#"); i >= 0 {
+		site = site[:i]
+	}
+	host, path, _ := strings.Cut(strings.TrimRight(site, "/"), "/")
+	site = strings.ToLower(host)
+	if path != "" {
+		site += "/" + path
+	}
+	return sitePrefix + cache.SanitizeSegment(site)
+}
+
+// siteInfo summarizes a crawled site
+func siteInfo(metadata *siteMetadata, updated bool) *SiteInfo {
+	info := &SiteInfo{
+		URL:           metadata.Site.URL,
+		Documentation: metadata.Name,
+		Topics:        metadata.Site.Topics,
+		Updated:       updated,
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Site: %s\n\n", metadata.DisplayName)
+	fmt.Fprintf(&b, "**Source:** %s\n", info.URL)
+	fmt.Fprintf(&b, "**Documentation:** %s\n", info.Documentation)
+	fmt.Fprintf(&b, "**Topics:** %d\n", info.Topics)
+	if metadata.Site.Failed > 0 {
+		fmt.Fprintf(&b, "**Unreadable pages:** %d\n", metadata.Site.Failed)
+	}
+	if metadata.Site.Listed >= metadata.Site.MaxPages {
+		fmt.Fprintf(&b, "**Page limit reached:** %d; crawl again with a higher maxPages for more\n", metadata.Site.MaxPages)
+	}
+	b.WriteString("\n")
+
+	writeSections(&b, metadata.Site.Sections)
+
+	info.Content = b.String()
+	return info
+}
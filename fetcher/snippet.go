@@ -0,0 +1,381 @@
+package fetcher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependencyOptions tunes the manifest edit produced by DependencySnippet
+type DependencyOptions struct {
+	// Dev adds the package as a development/test dependency
+	Dev bool
+	// Features enables Cargo features
+	Features []string
+	// NoDefaultFeatures disables the default Cargo features
+	NoDefaultFeatures bool
+	// Extras selects Python optional dependency groups
+	Extras []string
+	// PeerDependencies are npm peer dependencies that must be installed alongside the package
+	PeerDependencies map[string]string
+}
+
+// Ecosystems supported by DependencySnippet, keyed by accepted aliases
+var snippetEcosystems = map[string]string{
+	"npm":        "npm",
+	"node":       "npm",
+	"javascript": "npm",
+	"python":     "python",
+	"pypi":       "python",
+	"pip":        "python",
+	"rust":       "rust",
+	"cargo":      "rust",
+	"crates":     "rust",
+	"go":         "go",
+	"golang":     "go",
+	"ruby":       "ruby",
+	"gem":        "ruby",
+	"rubygems":   "ruby",
+	"hex":        "hex",
+	"elixir":     "hex",
+	"erlang":     "hex",
+	"cocoapods":  "cocoapods",
+	"pod":        "cocoapods",
+	"conan":      "conan",
+}
+
+// NormalizeEcosystem maps an ecosystem alias (e.g. "cargo", "pypi") to its canonical name
+func NormalizeEcosystem(ecosystem string) (string, error) {
+	if canonical, ok := snippetEcosystems[strings.ToLower(strings.TrimSpace(ecosystem))]; ok {
+		return canonical, nil
+	}
+	return "", fmt.Errorf("unsupported ecosystem: %s (supported: npm, python, rust, go, ruby, hex, cocoapods, conan)", ecosystem)
+}
+
+// DependencySnippet renders the manifest edit that adds a dependency as a diff-style markdown snippet
+func DependencySnippet(ecosystem, name, version string, opts DependencyOptions) (string, error) {
+	canonical, err := NormalizeEcosystem(ecosystem)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", fmt.Errorf("package name is required")
+	}
+
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Add %s", name)
+	if version != "" {
+		fmt.Fprintf(&content, " %s", version)
+	}
+	content.WriteString("\n\n")
+
+	switch canonical {
+	case "npm":
+		writeNPMSnippet(&content, name, version, opts)
+	case "python":
+		writePythonSnippet(&content, name, version, opts)
+	case "rust":
+		writeCargoSnippet(&content, name, version, opts)
+	case "go":
+		writeGoModSnippet(&content, name, version, opts)
+	case "ruby":
+		writeGemfileSnippet(&content, name, version, opts)
+	case "hex":
+		writeMixSnippet(&content, name, version, opts)
+	case "cocoapods":
+		writePodfileSnippet(&content, name, version, opts)
+	case "conan":
+		writeConanSnippet(&content, name, version, opts)
+	}
+
+	return content.String(), nil
+}
+
+func writeDiff(content *strings.Builder, file string, lines []string) {
+	fmt.Fprintf(content, "**%s**\n\n", file)
+	content.WriteString("```diff\n")
+	for _, line := range lines {
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+	content.WriteString("```\n\n")
+}
+
+func writeCommand(content *strings.Builder, command string) {
+	content.WriteString("```bash\n")
+	content.WriteString(command)
+	content.WriteString("\n```\n\n")
+}
+
+func writeNPMSnippet(content *strings.Builder, name, version string, opts DependencyOptions) {
+	section := "dependencies"
+	flag := ""
+	if opts.Dev {
+		section = "devDependencies"
+		flag = " --save-dev"
+	}
+
+	lines := []string{fmt.Sprintf("   \"%s\": {", section)}
+	packages := []string{name + npmVersionSuffix(version)}
+	lines = append(lines, "+    "+npmDependencyLine(name, version)+",")
+
+	peers := make([]string, 0, len(opts.PeerDependencies))
+	for peer := range opts.PeerDependencies {
+		peers = append(peers, peer)
+	}
+	sort.Strings(peers)
+	for _, peer := range peers {
+		rng := opts.PeerDependencies[peer]
+		lines = append(lines, fmt.Sprintf("+    \"%s\": \"%s\",", peer, rng))
+		packages = append(packages, fmt.Sprintf("%s@\"%s\"", peer, rng))
+	}
+	lines = append(lines, "   }")
+
+	writeDiff(content, "package.json", lines)
+	writeCommand(content, fmt.Sprintf("npm install%s %s", flag, strings.Join(packages, " ")))
+
+	if len(peers) > 0 {
+		content.WriteString("Peer dependencies are listed explicitly so the installed versions satisfy the package's peer ranges.\n\n")
+	}
+}
+
+// npmDependencyLine renders a package.json dependency entry with a caret range
+func npmDependencyLine(name, version string) string {
+	if version == "" {
+		return fmt.Sprintf("\"%s\": \"latest\"", name)
+	}
+	return fmt.Sprintf("\"%s\": \"^%s\"", name, version)
+}
+
+func npmVersionSuffix(version string) string {
+	if version == "" {
+		return ""
+	}
+	return "@" + version
+}
+
+func writePythonSnippet(content *strings.Builder, name, version string, opts DependencyOptions) {
+	requirement := pythonRequirement(name, version, opts.Extras)
+
+	file := "requirements.txt"
+	if opts.Dev {
+		file = "requirements-dev.txt"
+	}
+	writeDiff(content, file, []string{"+" + requirement})
+
+	if opts.Dev {
+		writeDiff(content, "pyproject.toml", []string{
+			" [project.optional-dependencies]",
+			" dev = [",
+			fmt.Sprintf("+    \"%s\",", requirement),
+			" ]",
+		})
+	} else {
+		writeDiff(content, "pyproject.toml", []string{
+			" [project]",
+			" dependencies = [",
+			fmt.Sprintf("+    \"%s\",", requirement),
+			" ]",
+		})
+	}
+
+	writeCommand(content, fmt.Sprintf("pip install '%s'", requirement))
+}
+
+// pythonRequirement renders a PEP 508 requirement, e.g. "requests[socks]==2.31.0"
+func pythonRequirement(name, version string, extras []string) string {
+	requirement := name
+	if len(extras) > 0 {
+		requirement += "[" + strings.Join(extras, ",") + "]"
+	}
+	if version != "" {
+		requirement += "==" + version
+	}
+	return requirement
+}
+
+func writeCargoSnippet(content *strings.Builder, name, version string, opts DependencyOptions) {
+	section := "[dependencies]"
+	flag := ""
+	if opts.Dev {
+		section = "[dev-dependencies]"
+		flag = " --dev"
+	}
+
+	writeDiff(content, "Cargo.toml", []string{
+		" " + section,
+		"+" + cargoDependencyLine(name, version, opts.Features, opts.NoDefaultFeatures),
+	})
+
+	command := fmt.Sprintf("cargo add%s %s", flag, name)
+	if version != "" {
+		command += "@" + version
+	}
+	if len(opts.Features) > 0 {
+		command += " --features " + strings.Join(opts.Features, ",")
+	}
+	if opts.NoDefaultFeatures {
+		command += " --no-default-features"
+	}
+	writeCommand(content, command)
+}
+
+// cargoDependencyLine renders a Cargo.toml dependency, using an inline table when features are set
+func cargoDependencyLine(name, version string, features []string, noDefaultFeatures bool) string {
+	if version == "" {
+		version = "*"
+	}
+	if len(features) == 0 && !noDefaultFeatures {
+		return fmt.Sprintf("%s = \"%s\"", name, version)
+	}
+
+	fields := []string{fmt.Sprintf("version = \"%s\"", version)}
+	if noDefaultFeatures {
+		fields = append(fields, "default-features = false")
+	}
+	if len(features) > 0 {
+		quoted := make([]string, len(features))
+		for i, feature := range features {
+			quoted[i] = fmt.Sprintf("\"%s\"", feature)
+		}
+		fields = append(fields, fmt.Sprintf("features = [%s]", strings.Join(quoted, ", ")))
+	}
+	return fmt.Sprintf("%s = { %s }", name, strings.Join(fields, ", "))
+}
+
+func writeGoModSnippet(content *strings.Builder, name, version string, opts DependencyOptions) {
+	if version == "" {
+		version = "latest"
+	} else if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	if version != "latest" {
+		writeDiff(content, "go.mod", []string{
+			" require (",
+			fmt.Sprintf("+\t%s %s", name, version),
+			" )",
+		})
+	}
+
+	writeCommand(content, fmt.Sprintf("go get %s@%s", name, version))
+
+	if opts.Dev {
+		content.WriteString("Go has no separate dev dependencies; tools are usually pinned with a `tool` directive (`go get -tool`).\n\n")
+	}
+}
+
+func writeGemfileSnippet(content *strings.Builder, name, version string, opts DependencyOptions) {
+	line := gemfileLine(name, version)
+
+	lines := []string{"+" + line}
+	if opts.Dev {
+		lines = []string{
+			" group :development, :test do",
+			"+  " + line,
+			" end",
+		}
+	}
+	writeDiff(content, "Gemfile", lines)
+
+	command := fmt.Sprintf("bundle add %s", name)
+	if version != "" {
+		command += fmt.Sprintf(" --version \"%s\"", pessimisticConstraint(version))
+	}
+	if opts.Dev {
+		command += " --group \"development, test\""
+	}
+	writeCommand(content, command)
+}
+
+// gemfileLine renders a Gemfile entry with a pessimistic version constraint
+func gemfileLine(name, version string) string {
+	if constraint := pessimisticConstraint(version); constraint != "" {
+		return fmt.Sprintf("gem \"%s\", \"%s\"", name, constraint)
+	}
+	return fmt.Sprintf("gem \"%s\"", name)
+}
+
+// pessimisticConstraint builds a "~>" constraint (Bundler and Mix) locked to the major.minor of version
+func pessimisticConstraint(version string) string {
+	if version == "" {
+		return ""
+	}
+	parts := strings.Split(strings.SplitN(version, "-", 2)[0], ".")
+	if len(parts) < 2 {
+		return "~> " + version
+	}
+	return fmt.Sprintf("~> %s.%s", parts[0], parts[1])
+}
+
+func writeMixSnippet(content *strings.Builder, name, version string, opts DependencyOptions) {
+	writeDiff(content, "mix.exs", []string{
+		"   defp deps do",
+		"     [",
+		"+      " + mixDependencyLine(name, version, opts.Dev) + ",",
+		"     ]",
+		"   end",
+	})
+	writeCommand(content, "mix deps.get")
+}
+
+// mixDependencyLine renders a mix.exs dependency tuple
+func mixDependencyLine(name, version string, dev bool) string {
+	requirement := pessimisticConstraint(version)
+	if requirement == "" {
+		requirement = ">= 0.0.0"
+	}
+	if dev {
+		return fmt.Sprintf("{:%s, \"%s\", only: [:dev, :test], runtime: false}", name, requirement)
+	}
+	return fmt.Sprintf("{:%s, \"%s\"}", name, requirement)
+}
+
+func writePodfileSnippet(content *strings.Builder, name, version string, opts DependencyOptions) {
+	line := podfileLine(name, version)
+
+	lines := []string{
+		" target 'MyApp' do",
+		"+  " + line,
+		" end",
+	}
+	if opts.Dev {
+		lines = []string{
+			" target 'MyAppTests' do",
+			"   inherit! :search_paths",
+			"+  " + line,
+			" end",
+		}
+	}
+	writeDiff(content, "Podfile", lines)
+	writeCommand(content, "pod install")
+}
+
+// podfileLine renders a Podfile entry with a pessimistic version constraint
+func podfileLine(name, version string) string {
+	if constraint := pessimisticConstraint(version); constraint != "" {
+		return fmt.Sprintf("pod '%s', '%s'", name, constraint)
+	}
+	return fmt.Sprintf("pod '%s'", name)
+}
+
+func writeConanSnippet(content *strings.Builder, name, version string, opts DependencyOptions) {
+	section := "[requires]"
+	if opts.Dev {
+		section = "[test_requires]"
+	}
+	writeDiff(content, "conanfile.txt", []string{
+		" " + section,
+		"+" + conanReference(name, version),
+	})
+	writeCommand(content, "conan install . --output-folder=build --build=missing")
+}
+
+// conanReference renders a Conan requirement reference ("zlib/1.3.1")
+func conanReference(name, version string) string {
+	if version == "" {
+		return name + "/[*]"
+	}
+	return name + "/" + version
+}
@@ -0,0 +1,638 @@
+package fetcher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	postgresDocsURL  = "https://www.postgresql.org/docs/current"
+	mysqlDocsURL     = "https://dev.mysql.com/doc/refman/8.4/en"
+	sqliteDocsURL    = "https://www.sqlite.org"
+	sqlServerDocsURL = "https://learn.microsoft.com/en-us/sql"
+)
+
+// sqlDialects are the dialects compare_sql_feature covers, in the order
+// they are printed
+var sqlDialects = []string{"postgresql", "mysql", "sqlite", "sqlserver"}
+
+var sqlDialectNames = map[string]string{
+	"postgresql": "PostgreSQL",
+	"mysql":      "MySQL",
+	"sqlite":     "SQLite",
+	"sqlserver":  "SQL Server",
+}
+
+var sqlDialectAliases = map[string]string{
+	"postgresql": "postgresql",
+	"postgres":   "postgresql",
+	"pg":         "postgresql",
+	"pgsql":      "postgresql",
+	"mysql":      "mysql",
+	"sqlite":     "sqlite",
+	"sqlite3":    "sqlite",
+	"sqlserver":  "sqlserver",
+	"sql server": "sqlserver",
+	"mssql":      "sqlserver",
+	"tsql":       "sqlserver",
+	"t-sql":      "sqlserver",
+}
+
+// sqlDialectFeature is how one dialect supports a feature
+type sqlDialectFeature struct {
+	syntax string
+	// since is the first release with the syntax, or "" when the dialect
+	// lacks the feature
+	since string
+	notes string
+	// docs is relative to the dialect's documentation root
+	docs string
+}
+
+// sqlFeature is a feature whose syntax differs across dialects
+type sqlFeature struct {
+	name     string
+	title    string
+	aliases  []string
+	summary  string
+	dialects map[string]sqlDialectFeature
+}
+
+// sqlFeatures is curated: the manuals describe each dialect on its own, so
+// each entry points at the page of every manual that covers the feature
+var sqlFeatures = []sqlFeature{
+	{
+		name:    "upsert",
+		title:   "UPSERT (insert or update)",
+		aliases: []string{"on conflict", "on duplicate key", "insert or update", "insert or replace", "insert ignore"},
+		summary: "Insert a row, or update the existing row when it would violate a unique constraint.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "INSERT INTO t (id, name) VALUES (1, 'a')\nON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name;\n\nINSERT INTO t (id, name) VALUES (1, 'a')\nON CONFLICT (id) DO NOTHING;",
+				since:  "9.5",
+				notes:  "The conflict target must match a unique index or constraint; `ON CONFLICT ON CONSTRAINT name` names it instead. `EXCLUDED` is the row that was proposed for insertion. MERGE (15) is the standard alternative.",
+				docs:   "/sql-insert.html#SQL-ON-CONFLICT",
+			},
+			"mysql": {
+				syntax: "INSERT INTO t (id, name) VALUES (1, 'a') AS new\nON DUPLICATE KEY UPDATE name = new.name;\n\nINSERT IGNORE INTO t (id, name) VALUES (1, 'a');",
+				since:  "4.1 (row alias since 8.0.19)",
+				notes:  "There is no conflict target: any PRIMARY KEY or UNIQUE index that collides triggers the update. `VALUES(name)` in the update clause is deprecated since 8.0.20 in favour of the row alias. `REPLACE` deletes the old row and inserts a new one, firing delete triggers and cascading foreign keys. `INSERT IGNORE` also turns other errors into warnings.",
+				docs:   "/insert-on-duplicate.html",
+			},
+			"sqlite": {
+				syntax: "INSERT INTO t (id, name) VALUES (1, 'a')\nON CONFLICT (id) DO UPDATE SET name = excluded.name;\n\nINSERT OR IGNORE INTO t (id, name) VALUES (1, 'a');",
+				since:  "3.24.0",
+				notes:  "Several ON CONFLICT clauses, and omitting the target of the last one, need 3.35.0. With `INSERT ... SELECT`, add `WHERE true` to the SELECT so ON CONFLICT is not parsed as a join constraint. `INSERT OR REPLACE` deletes the conflicting row first.",
+				docs:   "/lang_upsert.html",
+			},
+			"sqlserver": {
+				syntax: "MERGE INTO t WITH (HOLDLOCK) AS target\nUSING (VALUES (1, 'a')) AS source (id, name)\n  ON target.id = source.id\nWHEN MATCHED THEN UPDATE SET name = source.name\nWHEN NOT MATCHED THEN INSERT (id, name) VALUES (source.id, source.name);",
+				since:  "2008",
+				notes:  "There is no upsert clause. MERGE is not atomic against concurrent inserts without `HOLDLOCK` (or SERIALIZABLE). The common alternative is `UPDATE`, then `INSERT` when `@@ROWCOUNT = 0`, in one transaction with `UPDLOCK, HOLDLOCK`.",
+				docs:   "/t-sql/statements/merge-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "merge",
+		title:   "MERGE",
+		aliases: []string{"merge into"},
+		summary: "Insert, update, or delete rows of a table from the rows of a source, in one statement.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "MERGE INTO t USING s ON t.id = s.id\nWHEN MATCHED THEN UPDATE SET name = s.name\nWHEN NOT MATCHED THEN INSERT (id, name) VALUES (s.id, s.name);",
+				since:  "15",
+				notes:  "`RETURNING` and `WHEN NOT MATCHED BY SOURCE` need 17. Unlike ON CONFLICT, concurrent inserts can still fail with a unique violation.",
+				docs:   "/sql-merge.html",
+			},
+			"mysql": {
+				notes: "Not supported; use `INSERT ... ON DUPLICATE KEY UPDATE` or separate statements in a transaction.",
+				docs:  "/insert-on-duplicate.html",
+			},
+			"sqlite": {
+				notes: "Not supported; use `INSERT ... ON CONFLICT DO UPDATE` or separate statements in a transaction.",
+				docs:  "/lang_upsert.html",
+			},
+			"sqlserver": {
+				syntax: "MERGE INTO t AS target USING s AS source ON target.id = source.id\nWHEN MATCHED THEN UPDATE SET name = source.name\nWHEN NOT MATCHED BY TARGET THEN INSERT (id, name) VALUES (source.id, source.name)\nWHEN NOT MATCHED BY SOURCE THEN DELETE\nOUTPUT $action, inserted.id;",
+				since:  "2008",
+				notes:  "The statement must end with a semicolon. `$action` in OUTPUT tells which branch touched each row.",
+				docs:   "/t-sql/statements/merge-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "window functions",
+		title:   "Window functions",
+		aliases: []string{"window", "over", "row_number", "rank", "lag", "lead", "partition by"},
+		summary: "Compute a value per row over a set of related rows (`OVER (PARTITION BY ... ORDER BY ...)`) without collapsing them.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "SELECT id, ROW_NUMBER() OVER w, SUM(amount) OVER (w ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)\nFROM t\nWINDOW w AS (PARTITION BY account ORDER BY created_at);",
+				since:  "8.4",
+				notes:  "RANGE frames with offsets, GROUPS frames, and EXCLUDE need 11. Aggregates take `FILTER (WHERE ...)`. `IGNORE NULLS` is not supported.",
+				docs:   "/functions-window.html",
+			},
+			"mysql": {
+				syntax: "SELECT id, ROW_NUMBER() OVER w, SUM(amount) OVER (w ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)\nFROM t\nWINDOW w AS (PARTITION BY account ORDER BY created_at);",
+				since:  "8.0",
+				notes:  "Not available in 5.7. ROWS and RANGE frames only: no GROUPS, no EXCLUDE, no FILTER clause. Window functions cannot appear in UPDATE or DELETE.",
+				docs:   "/window-functions.html",
+			},
+			"sqlite": {
+				syntax: "SELECT id, ROW_NUMBER() OVER w, SUM(amount) OVER (w ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)\nFROM t\nWINDOW w AS (PARTITION BY account ORDER BY created_at);",
+				since:  "3.25.0",
+				notes:  "GROUPS frames, EXCLUDE, and FILTER on window aggregates need 3.28.0.",
+				docs:   "/windowfunctions.html",
+			},
+			"sqlserver": {
+				syntax: "SELECT id, ROW_NUMBER() OVER (PARTITION BY account ORDER BY created_at),\n  SUM(amount) OVER (PARTITION BY account ORDER BY created_at ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)\nFROM t;",
+				since:  "2005 (frames, LAG, and LEAD since 2012)",
+				notes:  "The WINDOW clause and `IGNORE NULLS` need 2022. RANGE only accepts UNBOUNDED and CURRENT ROW bounds; there are no GROUPS frames. The default frame with ORDER BY is RANGE, which is slower than an explicit ROWS frame.",
+				docs:   "/t-sql/queries/select-over-clause-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "json",
+		title:   "JSON operators and functions",
+		aliases: []string{"json operators", "jsonb", "json_extract", "json path", "->>", "json_table"},
+		summary: "Store JSON documents, extract values from them, and index them.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "SELECT doc->'user'->>'name', doc #>> '{tags,0}'\nFROM t\nWHERE doc @> '{\"active\": true}' AND doc ? 'email';",
+				since:  "9.2 (json), 9.4 (jsonb)",
+				notes:  "`->` returns json and `->>` returns text. Containment (`@>`) and key existence (`?`) are jsonb-only and use a GIN index. SQL/JSON path (`@?`, `jsonb_path_query`) needs 12, subscripting (`doc['user']`) 14, and JSON_TABLE, JSON_VALUE, and JSON_QUERY 17.",
+				docs:   "/functions-json.html",
+			},
+			"mysql": {
+				syntax: "SELECT doc->'$.user.name', doc->>'$.user.name'\nFROM t\nWHERE JSON_CONTAINS(doc, 'true', '$.active');",
+				since:  "5.7.8 (->> since 5.7.13)",
+				notes:  "Operators take a JSON path (`'$.a.b'`), not a key. `->` is JSON_EXTRACT and returns a JSON value, quotes included; `->>` unquotes it. JSON columns cannot be indexed directly: index a generated column, a functional index (8.0.13), or a multi-valued index for arrays (8.0.17, with `MEMBER OF`). JSON_TABLE needs 8.0.4.",
+				docs:   "/json.html",
+			},
+			"sqlite": {
+				syntax: "SELECT doc->'$.user', doc->>'$.user.name', value\nFROM t, json_each(t.doc, '$.tags')\nWHERE doc->>'active' = 1;",
+				since:  "3.38.0 (built in, with -> and ->>)",
+				notes:  "There is no JSON type: documents are TEXT, or the binary JSONB format from 3.45.0. `->` returns JSON text and `->>` an SQL value; both accept a path or a plain key. Before 3.38.0 the JSON1 extension had to be compiled in and only the json_extract functions existed. Index an expression such as `json_extract(doc, '$.email')`.",
+				docs:   "/json1.html",
+			},
+			"sqlserver": {
+				syntax: "SELECT JSON_VALUE(doc, '$.user.name'), JSON_QUERY(doc, '$.tags')\nFROM t\nCROSS APPLY OPENJSON(doc, '$.tags') AS tag\nWHERE ISJSON(doc) = 1;",
+				since:  "2016",
+				notes:  "There are no JSON operators. Before 2025 there is no JSON type either: documents are NVARCHAR. JSON_VALUE returns scalars and JSON_QUERY objects or arrays; each returns NULL for the other kind in lax mode. `FOR JSON PATH` builds JSON from rows. Index a computed column over JSON_VALUE.",
+				docs:   "/relational-databases/json/json-data-sql-server",
+			},
+		},
+	},
+	{
+		name:    "cte",
+		title:   "Common table expressions (WITH)",
+		aliases: []string{"with", "recursive", "with recursive", "common table expression", "recursive cte"},
+		summary: "Name subqueries with WITH, and query hierarchies with recursive ones.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "WITH RECURSIVE tree AS (\n  SELECT id, parent_id FROM nodes WHERE id = 1\n  UNION ALL\n  SELECT n.id, n.parent_id FROM nodes n JOIN tree ON n.parent_id = tree.id\n)\nSELECT * FROM tree;",
+				since:  "8.4",
+				notes:  "Before 12, every CTE is materialized and blocks predicate pushdown; from 12 on, `MATERIALIZED` and `NOT MATERIALIZED` choose. INSERT, UPDATE, and DELETE with RETURNING can be CTEs (9.1). SEARCH and CYCLE clauses need 14.",
+				docs:   "/queries-with.html",
+			},
+			"mysql": {
+				syntax: "WITH RECURSIVE tree AS (\n  SELECT id, parent_id FROM nodes WHERE id = 1\n  UNION ALL\n  SELECT n.id, n.parent_id FROM nodes n JOIN tree ON n.parent_id = tree.id\n)\nSELECT * FROM tree;",
+				since:  "8.0",
+				notes:  "Not available in 5.7. Recursion stops with an error after `cte_max_recursion_depth` (1000) levels. The column types of a recursive CTE come from its first SELECT, so widen them with CAST there.",
+				docs:   "/with.html",
+			},
+			"sqlite": {
+				syntax: "WITH RECURSIVE tree AS (\n  SELECT id, parent_id FROM nodes WHERE id = 1\n  UNION ALL\n  SELECT n.id, n.parent_id FROM nodes n JOIN tree ON n.parent_id = tree.id\n)\nSELECT * FROM tree;",
+				since:  "3.8.3",
+				notes:  "`MATERIALIZED` and `NOT MATERIALIZED` hints need 3.35.0. A recursive CTE may use LIMIT to stop recursion.",
+				docs:   "/lang_with.html",
+			},
+			"sqlserver": {
+				syntax: "WITH tree AS (\n  SELECT id, parent_id FROM nodes WHERE id = 1\n  UNION ALL\n  SELECT n.id, n.parent_id FROM nodes n JOIN tree ON n.parent_id = tree.id\n)\nSELECT * FROM tree\nOPTION (MAXRECURSION 1000);",
+				since:  "2005",
+				notes:  "There is no RECURSIVE keyword: a CTE referencing itself is recursive. Recursion stops with an error after 100 levels unless `OPTION (MAXRECURSION n)` raises it (0 is unlimited). The statement before WITH must end with a semicolon.",
+				docs:   "/t-sql/queries/with-common-table-expression-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "returning",
+		title:   "Returning modified rows",
+		aliases: []string{"output", "output inserted", "last insert id", "inserted id"},
+		summary: "Get the rows, such as generated ids, that an INSERT, UPDATE, or DELETE changed.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "INSERT INTO t (name) VALUES ('a') RETURNING id;\nDELETE FROM t WHERE expired RETURNING *;",
+				since:  "8.2",
+				notes:  "MERGE accepts RETURNING from 17. `OLD` and `NEW` in RETURNING, for the values before and after an UPDATE, need 18.",
+				docs:   "/dml-returning.html",
+			},
+			"mysql": {
+				syntax: "INSERT INTO t (name) VALUES ('a');\nSELECT LAST_INSERT_ID();",
+				notes:  "Not supported. `LAST_INSERT_ID()` returns the first AUTO_INCREMENT value of the last insert on the connection; a multi-row insert's ids are consecutive only with `innodb_autoinc_lock_mode` 0 or 1. MariaDB, unlike MySQL, supports RETURNING on INSERT and DELETE.",
+				docs:   "/information-functions.html#function_last-insert-id",
+			},
+			"sqlite": {
+				syntax: "INSERT INTO t (name) VALUES ('a') RETURNING id;\nDELETE FROM t WHERE expired RETURNING *;",
+				since:  "3.35.0",
+				notes:  "Not available inside triggers. Before 3.35.0, use `last_insert_rowid()`.",
+				docs:   "/lang_returning.html",
+			},
+			"sqlserver": {
+				syntax: "INSERT INTO t (name) OUTPUT inserted.id VALUES ('a');\nDELETE FROM t OUTPUT deleted.* WHERE expired = 1;\nUPDATE t SET name = 'b' OUTPUT deleted.name, inserted.name WHERE id = 1;",
+				since:  "2005",
+				notes:  "The OUTPUT clause goes before VALUES or WHERE. On a table with enabled triggers, OUTPUT must write `INTO` a table variable. `SCOPE_IDENTITY()` returns the last identity value in the scope.",
+				docs:   "/t-sql/queries/output-clause-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "pagination",
+		title:   "Limiting and paging results",
+		aliases: []string{"limit", "offset", "top", "fetch first", "paging"},
+		summary: "Return the first n rows, or a page of rows, of a query.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "SELECT * FROM t ORDER BY id LIMIT 10 OFFSET 20;\nSELECT * FROM t ORDER BY id OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY;",
+				since:  "all supported versions",
+				notes:  "`FETCH FIRST ... WITH TIES` needs 13. Large offsets still scan the skipped rows; page with `WHERE id > last_id` instead.",
+				docs:   "/queries-limit.html",
+			},
+			"mysql": {
+				syntax: "SELECT * FROM t ORDER BY id LIMIT 10 OFFSET 20;\nSELECT * FROM t ORDER BY id LIMIT 20, 10;",
+				since:  "all supported versions",
+				notes:  "There is no FETCH FIRST. In `LIMIT 20, 10` the offset comes first. LIMIT does not accept expressions, only literals and placeholders.",
+				docs:   "/select.html",
+			},
+			"sqlite": {
+				syntax: "SELECT * FROM t ORDER BY id LIMIT 10 OFFSET 20;\nSELECT * FROM t ORDER BY id LIMIT 20, 10;",
+				since:  "all supported versions",
+				notes:  "There is no FETCH FIRST. In `LIMIT 20, 10` the offset comes first. UPDATE and DELETE accept LIMIT only when built with SQLITE_ENABLE_UPDATE_DELETE_LIMIT.",
+				docs:   "/lang_select.html#limitoffset",
+			},
+			"sqlserver": {
+				syntax: "SELECT TOP (10) * FROM t ORDER BY id;\nSELECT * FROM t ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY;",
+				since:  "2012 (OFFSET ... FETCH)",
+				notes:  "There is no LIMIT. OFFSET ... FETCH requires ORDER BY; `ORDER BY (SELECT NULL)` opts out of an order. `TOP (n) WITH TIES` keeps rows tied with the last one.",
+				docs:   "/t-sql/queries/select-order-by-clause-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "string aggregation",
+		title:   "String aggregation",
+		aliases: []string{"string_agg", "group_concat", "listagg", "concatenate rows"},
+		summary: "Concatenate the values of a group into one string.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "SELECT account, string_agg(name, ', ' ORDER BY name)\nFROM t GROUP BY account;",
+				since:  "9.0",
+				notes:  "`array_agg` collects the values into an array instead.",
+				docs:   "/functions-aggregate.html",
+			},
+			"mysql": {
+				syntax: "SELECT account, GROUP_CONCAT(name ORDER BY name SEPARATOR ', ')\nFROM t GROUP BY account;",
+				since:  "4.1",
+				notes:  "The result is silently cut at `group_concat_max_len` bytes, 1024 by default. The default separator is a comma without a space.",
+				docs:   "/aggregate-functions.html#function_group-concat",
+			},
+			"sqlite": {
+				syntax: "SELECT account, group_concat(name, ', ')\nFROM t GROUP BY account;\nSELECT account, string_agg(name, ', ' ORDER BY name)\nFROM t GROUP BY account;",
+				since:  "all supported versions (string_agg and ORDER BY since 3.44.0)",
+				notes:  "Before 3.44.0 the order of the values is undefined; sort them in a subquery and hope, or aggregate in the application.",
+				docs:   "/lang_aggfunc.html#group_concat",
+			},
+			"sqlserver": {
+				syntax: "SELECT account, STRING_AGG(name, ', ') WITHIN GROUP (ORDER BY name)\nFROM t GROUP BY account;",
+				since:  "2017",
+				notes:  "Cast the value to NVARCHAR(MAX) when the result may exceed 8000 bytes. Before 2017, the usual workaround is `STUFF((SELECT ', ' + name ... FOR XML PATH('')), 1, 2, '')`.",
+				docs:   "/t-sql/functions/string-agg-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "auto increment",
+		title:   "Auto-incrementing keys",
+		aliases: []string{"identity", "autoincrement", "auto_increment", "serial", "sequence", "generated as identity"},
+		summary: "Generate the primary key of new rows.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "CREATE TABLE t (\n  id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,\n  name text\n);",
+				since:  "10 (identity columns)",
+				notes:  "`serial` and `bigserial` are the older shorthand for a column defaulting to a sequence. `GENERATED ALWAYS` rejects explicit ids unless `OVERRIDING SYSTEM VALUE`; `BY DEFAULT` accepts them. Ids are not gap-free.",
+				docs:   "/ddl-identity-columns.html",
+			},
+			"mysql": {
+				syntax: "CREATE TABLE t (\n  id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,\n  name VARCHAR(255)\n);",
+				since:  "all supported versions",
+				notes:  "A table has at most one AUTO_INCREMENT column and it must be indexed. Before 8.0 the counter was not persisted and could move back after a restart.",
+				docs:   "/example-auto-increment.html",
+			},
+			"sqlite": {
+				syntax: "CREATE TABLE t (\n  id INTEGER PRIMARY KEY,\n  name TEXT\n);",
+				since:  "all supported versions",
+				notes:  "A column declared exactly `INTEGER PRIMARY KEY` is the rowid and is assigned automatically; `INT PRIMARY KEY` is not. `AUTOINCREMENT` only prevents reusing the ids of deleted rows, at a cost.",
+				docs:   "/autoinc.html",
+			},
+			"sqlserver": {
+				syntax: "CREATE TABLE t (\n  id BIGINT IDENTITY(1, 1) PRIMARY KEY,\n  name NVARCHAR(255)\n);",
+				since:  "all supported versions (SEQUENCE since 2012)",
+				notes:  "Inserting explicit ids needs `SET IDENTITY_INSERT t ON`. Read the new id with `SCOPE_IDENTITY()` or OUTPUT; `@@IDENTITY` can return an id inserted by a trigger.",
+				docs:   "/t-sql/statements/create-table-transact-sql-identity-property",
+			},
+		},
+	},
+	{
+		name:    "boolean",
+		title:   "Boolean type",
+		aliases: []string{"bool", "bit", "true false"},
+		summary: "Store and compare true and false values.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "CREATE TABLE t (active boolean NOT NULL DEFAULT true);\nSELECT * FROM t WHERE active;",
+				since:  "all supported versions",
+				notes:  "A real type: `'yes'`, `'on'`, and `'t'` are accepted as input, integers are not without a cast.",
+				docs:   "/datatype-boolean.html",
+			},
+			"mysql": {
+				syntax: "CREATE TABLE t (active BOOLEAN NOT NULL DEFAULT TRUE);\nSELECT * FROM t WHERE active;",
+				since:  "all supported versions",
+				notes:  "BOOLEAN is an alias of TINYINT(1): TRUE is 1, FALSE is 0, and the column accepts any other integer, such as 2, which is neither `= TRUE` nor `= FALSE`.",
+				docs:   "/numeric-type-syntax.html",
+			},
+			"sqlite": {
+				syntax: "CREATE TABLE t (active INTEGER NOT NULL DEFAULT 1 CHECK (active IN (0, 1)));\nSELECT * FROM t WHERE active;",
+				since:  "TRUE and FALSE keywords since 3.23.0",
+				notes:  "There is no boolean storage class: values are the integers 0 and 1. A CHECK constraint keeps other values out.",
+				docs:   "/datatype3.html#boolean_datatype",
+			},
+			"sqlserver": {
+				syntax: "CREATE TABLE t (active BIT NOT NULL DEFAULT 1);\nSELECT * FROM t WHERE active = 1;",
+				since:  "all supported versions",
+				notes:  "BIT is 0, 1, or NULL. There are no TRUE and FALSE literals, and a BIT column is not a predicate: write `WHERE active = 1`. The strings 'TRUE' and 'FALSE' convert to 1 and 0.",
+				docs:   "/t-sql/data-types/bit-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "regex",
+		title:   "Regular expressions",
+		aliases: []string{"regexp", "regular expression", "regexp_replace", "rlike", "regexp_like"},
+		summary: "Match and replace text with regular expressions.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "SELECT * FROM t WHERE email ~* '^[a-z]+@example\\.com$';\nSELECT regexp_replace(phone, '[^0-9]', '', 'g') FROM t;",
+				since:  "all supported versions",
+				notes:  "`~` is case-sensitive, `~*` case-insensitive, and `!~` negates. regexp_replace replaces the first match unless given the `'g'` flag. regexp_like, regexp_count, regexp_instr, and regexp_substr need 15.",
+				docs:   "/functions-matching.html#FUNCTIONS-POSIX-REGEXP",
+			},
+			"mysql": {
+				syntax: "SELECT * FROM t WHERE email REGEXP '^[a-z]+@example\\\\.com$';\nSELECT REGEXP_REPLACE(phone, '[^0-9]', '') FROM t;",
+				since:  "8.0.4 (ICU engine and REGEXP_ functions)",
+				notes:  "Matching follows the column's collation, so it is usually case-insensitive. Backslashes in string literals must be doubled. REGEXP_REPLACE replaces all matches by default.",
+				docs:   "/regexp.html",
+			},
+			"sqlite": {
+				syntax: "SELECT * FROM t WHERE email REGEXP '^[a-z]+@example\\.com$';",
+				notes:  "REGEXP is parsed but not implemented: it calls a `regexp()` function that the application or an extension must provide, and fails otherwise. The sqlite3 shell bundles one since 3.36.0. GLOB and LIKE are the built-in patterns.",
+				docs:   "/lang_expr.html#the_like_glob_regexp_match_and_extract_operators",
+			},
+			"sqlserver": {
+				syntax: "SELECT * FROM t WHERE REGEXP_LIKE(email, '^[a-z]+@example\\.com$');\nSELECT * FROM t WHERE email LIKE '%[0-9]%';",
+				since:  "2025",
+				notes:  "Before 2025 there is no regular expression support; LIKE accepts character classes such as `[0-9]` and `[^a-z]` but no quantifiers. REGEXP_LIKE needs database compatibility level 170.",
+				docs:   "/t-sql/functions/regular-expressions-functions-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "full-text search",
+		title:   "Full-text search",
+		aliases: []string{"full text", "fulltext", "fts", "tsvector", "match against"},
+		summary: "Search text by words, with stemming and ranking, using an index.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "CREATE INDEX ON docs USING GIN (to_tsvector('english', body));\nSELECT * FROM docs\nWHERE to_tsvector('english', body) @@ websearch_to_tsquery('english', 'quick -slow');",
+				since:  "8.3 (websearch_to_tsquery since 11)",
+				notes:  "The query must use the same text search configuration as the index expression to use it. Rank with ts_rank.",
+				docs:   "/textsearch.html",
+			},
+			"mysql": {
+				syntax: "CREATE FULLTEXT INDEX ft_body ON docs (body);\nSELECT * FROM docs\nWHERE MATCH(body) AGAINST('+quick -slow' IN BOOLEAN MODE);",
+				since:  "5.6 (InnoDB)",
+				notes:  "MATCH must list exactly the columns of a FULLTEXT index. Words shorter than `innodb_ft_min_token_size` (3) and stopwords are not indexed. There is no stemming.",
+				docs:   "/fulltext-search.html",
+			},
+			"sqlite": {
+				syntax: "CREATE VIRTUAL TABLE docs_fts USING fts5(body, tokenize = 'porter');\nSELECT * FROM docs_fts WHERE docs_fts MATCH 'quick NOT slow' ORDER BY rank;",
+				since:  "3.9.0 (FTS5)",
+				notes:  "Full-text search lives in a separate virtual table, which an external-content table and triggers keep in sync with the source table.",
+				docs:   "/fts5.html",
+			},
+			"sqlserver": {
+				syntax: "CREATE FULLTEXT INDEX ON docs (body) KEY INDEX pk_docs;\nSELECT * FROM docs WHERE CONTAINS(body, '\"quick\" AND NOT \"slow\"');\nSELECT * FROM docs WHERE FREETEXT(body, 'quick foxes');",
+				since:  "all supported versions",
+				notes:  "Needs the Full-Text Search feature installed and a full-text catalog. The index is populated asynchronously, so new rows are not found at once.",
+				docs:   "/relational-databases/search/full-text-search",
+			},
+		},
+	},
+	{
+		name:    "lateral",
+		title:   "Lateral joins",
+		aliases: []string{"lateral join", "cross apply", "outer apply", "apply"},
+		summary: "Join each row to a subquery that references it, such as the top n related rows.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "SELECT u.id, o.*\nFROM users u\nCROSS JOIN LATERAL (\n  SELECT * FROM orders WHERE orders.user_id = u.id ORDER BY created_at DESC LIMIT 3\n) o;",
+				since:  "9.3",
+				notes:  "`LEFT JOIN LATERAL (...) o ON true` keeps rows without matches. Set-returning functions in FROM are implicitly lateral.",
+				docs:   "/queries-table-expressions.html#QUERIES-LATERAL",
+			},
+			"mysql": {
+				syntax: "SELECT u.id, o.*\nFROM users u\nJOIN LATERAL (\n  SELECT * FROM orders WHERE orders.user_id = u.id ORDER BY created_at DESC LIMIT 3\n) o ON true;",
+				since:  "8.0.14",
+				notes:  "Before 8.0.14, use a window function such as ROW_NUMBER over the joined table instead.",
+				docs:   "/lateral-derived-tables.html",
+			},
+			"sqlite": {
+				notes: "Not supported. Use a correlated subquery, or ROW_NUMBER in a subquery for the top n rows. Table-valued functions such as json_each may reference earlier tables in FROM.",
+				docs:  "/lang_select.html",
+			},
+			"sqlserver": {
+				syntax: "SELECT u.id, o.*\nFROM users u\nCROSS APPLY (\n  SELECT TOP (3) * FROM orders WHERE orders.user_id = u.id ORDER BY created_at DESC\n) o;",
+				since:  "2005",
+				notes:  "There is no LATERAL keyword: CROSS APPLY is an inner lateral join and OUTER APPLY a left one.",
+				docs:   "/t-sql/queries/from-transact-sql",
+			},
+		},
+	},
+	{
+		name:    "generated columns",
+		title:   "Generated (computed) columns",
+		aliases: []string{"generated column", "computed column", "virtual column", "stored column"},
+		summary: "Define a column whose value is computed from the other columns of the row.",
+		dialects: map[string]sqlDialectFeature{
+			"postgresql": {
+				syntax: "CREATE TABLE t (\n  price numeric,\n  qty int,\n  total numeric GENERATED ALWAYS AS (price * qty) STORED\n);",
+				since:  "12 (STORED), 18 (VIRTUAL)",
+				notes:  "From 18, columns are VIRTUAL unless STORED is given. The expression must be immutable and cannot reference other generated columns.",
+				docs:   "/ddl-generated-columns.html",
+			},
+			"mysql": {
+				syntax: "CREATE TABLE t (\n  price DECIMAL(10, 2),\n  qty INT,\n  total DECIMAL(12, 2) GENERATED ALWAYS AS (price * qty) STORED\n);",
+				since:  "5.7.6",
+				notes:  "VIRTUAL is the default. Secondary indexes on VIRTUAL columns are allowed with InnoDB, which is how JSON values are indexed.",
+				docs:   "/create-table-generated-columns.html",
+			},
+			"sqlite": {
+				syntax: "CREATE TABLE t (\n  price REAL,\n  qty INTEGER,\n  total REAL GENERATED ALWAYS AS (price * qty) STORED\n);",
+				since:  "3.31.0",
+				notes:  "VIRTUAL is the default. ALTER TABLE ADD COLUMN can add VIRTUAL columns but not STORED ones.",
+				docs:   "/gencol.html",
+			},
+			"sqlserver": {
+				syntax: "CREATE TABLE t (\n  price DECIMAL(10, 2),\n  qty INT,\n  total AS (price * qty) PERSISTED\n);",
+				since:  "all supported versions",
+				notes:  "Called computed columns, with no GENERATED ALWAYS syntax. Without PERSISTED they are computed on read; only deterministic expressions can be persisted or indexed.",
+				docs:   "/relational-databases/tables/specify-computed-columns-in-a-table",
+			},
+		},
+	},
+}
+
+// SQLFeatureComparison is how a SQL feature differs across dialects
+type SQLFeatureComparison struct {
+	Feature  string
+	Dialects []string
+	Content  string
+}
+
+// CompareSQLFeature describes how a feature, such as UPSERT, window
+// functions, or JSON operators, is written in each of PostgreSQL, MySQL,
+// SQLite, and SQL Server, or in the given dialects only
+func CompareSQLFeature(feature string, dialects []string) (*SQLFeatureComparison, error) {
+	f := matchSQLFeature(feature)
+	if f == nil {
+		return nil, fmt.Errorf("unknown SQL feature %q (supported: %s)", feature, strings.Join(sqlFeatureNames(), ", "))
+	}
+
+	selected, err := selectSQLDialects(dialects)
+	if err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# %s across SQL dialects\n\n", f.title)
+	content.WriteString(f.summary)
+	content.WriteString("\n\n")
+
+	content.WriteString("| Dialect | Supported since |\n")
+	content.WriteString("|---------|-----------------|\n")
+	for _, d := range selected {
+		since := f.dialects[d].since
+		if since == "" {
+			since = "not supported"
+		}
+		fmt.Fprintf(&content, "| %s | %s |\n", sqlDialectNames[d], since)
+	}
+	content.WriteString("\n")
+
+	for _, d := range selected {
+		df := f.dialects[d]
+		fmt.Fprintf(&content, "## %s\n\n", sqlDialectNames[d])
+		if df.syntax != "" {
+			content.WriteString("```sql\n")
+			content.WriteString(df.syntax)
+			content.WriteString("\n```\n\n")
+		}
+		content.WriteString(df.notes)
+		content.WriteString("\n\n")
+		fmt.Fprintf(&content, "**Reference:** [%s documentation](%s%s)\n\n", sqlDialectNames[d], sqlDocsURL(d), df.docs)
+	}
+
+	return &SQLFeatureComparison{
+		Feature:  f.name,
+		Dialects: selected,
+		Content:  strings.TrimRight(content.String(), "\n") + "\n",
+	}, nil
+}
+
+// matchSQLFeature finds a feature by its name or one of its aliases,
+// ignoring case, underscores, and hyphens
+func matchSQLFeature(feature string) *sqlFeature {
+	key := normalizeSQLFeature(feature)
+	if key == "" {
+		return nil
+	}
+	for i, f := range sqlFeatures {
+		if normalizeSQLFeature(f.name) == key {
+			return &sqlFeatures[i]
+		}
+		for _, alias := range f.aliases {
+			if normalizeSQLFeature(alias) == key {
+				return &sqlFeatures[i]
+			}
+		}
+	}
+	return nil
+}
+
+func normalizeSQLFeature(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer("_", " ", "-", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// selectSQLDialects resolves dialect names and aliases, keeping the order
+// of sqlDialects; none selects all of them
+func selectSQLDialects(dialects []string) ([]string, error) {
+	wanted := map[string]bool{}
+	for _, d := range dialects {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" {
+			continue
+		}
+		dialect, ok := sqlDialectAliases[d]
+		if !ok {
+			return nil, fmt.Errorf("unsupported SQL dialect %q (supported: postgresql, mysql, sqlite, sqlserver)", d)
+		}
+		wanted[dialect] = true
+	}
+	if len(wanted) == 0 {
+		return sqlDialects, nil
+	}
+
+	var selected []string
+	for _, d := range sqlDialects {
+		if wanted[d] {
+			selected = append(selected, d)
+		}
+	}
+	return selected, nil
+}
+
+func sqlDocsURL(dialect string) string {
+	switch dialect {
+	case "postgresql":
+		return postgresDocsURL
+	case "mysql":
+		return mysqlDocsURL
+	case "sqlite":
+		return sqliteDocsURL
+	default:
+		return sqlServerDocsURL
+	}
+}
+
+func sqlFeatureNames() []string {
+	names := make([]string, 0, len(sqlFeatures))
+	for _, f := range sqlFeatures {
+		names = append(names, f.name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,294 @@
+package fetcher
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	stackExchangeAPI = "https://api.stackexchange.com/2.3"
+
+	// stackExchangeTTL keeps answers long enough to serve a debugging
+	// session while picking up new answers and edits within the day
+	stackExchangeTTL = 6 * time.Hour
+
+	// maxStackExchangeQuestions caps the questions whose accepted answers
+	// are shown
+	maxStackExchangeQuestions   = 5
+	maxStackExchangeAnswerChars = 8000
+)
+
+type StackExchangeAnswers struct {
+	Query string
+	Tag   string
+	Site  string
+	// Content is the rendered markdown of the answers
+	Content string
+}
+
+// stackExchangeQuestion is a question of the search API
+type stackExchangeQuestion struct {
+	QuestionID       int64    `json:"question_id"`
+	Title            string   `json:"title"`
+	Link             string   `json:"link"`
+	Score            int      `json:"score"`
+	Tags             []string `json:"tags"`
+	AcceptedAnswerID int64    `json:"accepted_answer_id"`
+	// Answer is the question's accepted answer, filled in from the answers API
+	Answer *stackExchangeAnswer `json:"answer,omitempty"`
+}
+
+// stackExchangeAnswer is an answer of the answers API, fetched with its body
+type stackExchangeAnswer struct {
+	AnswerID       int64  `json:"answer_id"`
+	QuestionID     int64  `json:"question_id"`
+	Score          int    `json:"score"`
+	CreationDate   int64  `json:"creation_date"`
+	ContentLicense string `json:"content_license"`
+	Body           string `json:"body"`
+	Owner          struct {
+		DisplayName string `json:"display_name"`
+		Link        string `json:"link"`
+	} `json:"owner"`
+}
+
+// stackExchangeResponse is the wrapper of every Stack Exchange API response
+type stackExchangeResponse struct {
+	Items          json.RawMessage `json:"items"`
+	ErrorID        int             `json:"error_id"`
+	ErrorName      string          `json:"error_name"`
+	ErrorMessage   string          `json:"error_message"`
+	QuotaRemaining int             `json:"quota_remaining"`
+}
+
+type StackExchangeFetcher struct {
+	*BaseFetcher
+	answersCache *cache.Manager
+}
+
+func NewStackExchangeFetcher(cacheDir string) *StackExchangeFetcher {
+	return &StackExchangeFetcher{
+		BaseFetcher:  NewBaseFetcher(cacheDir),
+		answersCache: newCacheManager(cacheDir, stackExchangeTTL),
+	}
+}
+
+// FetchAnswers searches a Stack Exchange site (stackoverflow by default) for
+// questions matching query, such as an error message, optionally limited to
+// a tag, and returns the accepted answers of the most relevant ones with
+// their score, author, and license
+func (f *StackExchangeFetcher) FetchAnswers(query, tag, site string) (*StackExchangeAnswers, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	site = strings.ToLower(strings.TrimSpace(site))
+	if site == "" {
+		site = "stackoverflow"
+	}
+	return shareFetch(f.flights, flightKey("FetchAnswers", query, tag, site), "", func() (*StackExchangeAnswers, error) {
+		return f.fetchAnswers(query, tag, site)
+	})
+}
+
+func (f *StackExchangeFetcher) fetchAnswers(query, tag, site string) (*StackExchangeAnswers, error) {
+	// The questions are cached rather than the markdown, so the rendering
+	// can change without waiting out the TTL
+	cachedPath := f.answersCache.GetFilePath("stackexchange", site, fmt.Sprintf("%s.json", cache.EntryName(query, tag)))
+	var questions []stackExchangeQuestion
+	if ok, err := f.answersCache.Load(cachedPath, &questions); err == nil && ok {
+		fmt.Fprintf(os.Stderr, "Loaded Stack Exchange answers for '%s' from cache\n", query)
+		return f.render(query, tag, site, questions), nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Searching %s for '%s'...\n", site, query)
+
+	params := url.Values{
+		"q":        {query},
+		"accepted": {"True"},
+		"order":    {"desc"},
+		"sort":     {"relevance"},
+		"pagesize": {strconv.Itoa(maxStackExchangeQuestions)},
+	}
+	if tag != "" {
+		params.Set("tagged", tag)
+	}
+	if err := f.get("search/advanced", site, params, &questions); err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", site, err)
+	}
+
+	var ids []string
+	for _, q := range questions {
+		if q.AcceptedAnswerID != 0 {
+			ids = append(ids, strconv.FormatInt(q.AcceptedAnswerID, 10))
+		}
+	}
+	if len(ids) > 0 {
+		var answers []stackExchangeAnswer
+		params := url.Values{"filter": {"withbody"}, "pagesize": {strconv.Itoa(len(ids))}}
+		if err := f.get("answers/"+strings.Join(ids, ";"), site, params, &answers); err != nil {
+			return nil, fmt.Errorf("failed to fetch answers: %w", err)
+		}
+		byQuestion := make(map[int64]*stackExchangeAnswer, len(answers))
+		for i := range answers {
+			byQuestion[answers[i].QuestionID] = &answers[i]
+		}
+		for i := range questions {
+			questions[i].Answer = byQuestion[questions[i].QuestionID]
+		}
+	}
+
+	if err := f.answersCache.Save(cachedPath, questions); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache Stack Exchange answers: %v\n", err)
+	}
+	return f.render(query, tag, site, questions), nil
+}
+
+// get calls a Stack Exchange API method and decodes its items into v
+func (f *StackExchangeFetcher) get(method, site string, params url.Values, v interface{}) error {
+	params.Set("site", site)
+	if key := stackExchangeKey(); key != "" {
+		params.Set("key", key)
+	}
+
+	req, err := http.NewRequest("GET", stackExchangeAPI+"/"+method+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Errors come back as JSON with a status of 400 or more
+	var result stackExchangeResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("Stack Exchange API returned status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.ErrorID != 0 {
+		if result.ErrorName == "throttle_violation" {
+			return fmt.Errorf("Stack Exchange API quota exceeded (set STACKEXCHANGE_KEY for a higher quota): %s", result.ErrorMessage)
+		}
+		return fmt.Errorf("Stack Exchange API error %s: %s", result.ErrorName, result.ErrorMessage)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Stack Exchange API returned status %d", resp.StatusCode)
+	}
+	if result.QuotaRemaining > 0 && result.QuotaRemaining < 20 {
+		fmt.Fprintf(os.Stderr, "Warning: %d Stack Exchange API requests left today\n", result.QuotaRemaining)
+	}
+
+	if len(result.Items) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(result.Items, v); err != nil {
+		return fmt.Errorf("failed to parse items: %w", err)
+	}
+	return nil
+}
+
+func (f *StackExchangeFetcher) render(query, tag, site string, questions []stackExchangeQuestion) *StackExchangeAnswers {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# Answers for \"%s\"\n\n", query)
+	fmt.Fprintf(&content, "**Site:** %s\n\n", site)
+	if tag != "" {
+		fmt.Fprintf(&content, "**Tag:** %s\n\n", tag)
+	}
+
+	shown := 0
+	for _, q := range questions {
+		if q.Answer == nil {
+			continue
+		}
+		shown++
+		a := q.Answer
+
+		fmt.Fprintf(&content, "## %s\n\n", html.UnescapeString(q.Title))
+		fmt.Fprintf(&content, "**Question:** [%s](%s) (score %d)\n\n", q.Link, q.Link, q.Score)
+		if len(q.Tags) > 0 {
+			fmt.Fprintf(&content, "**Tags:** %s\n\n", strings.Join(q.Tags, ", "))
+		}
+
+		answerURL := stackExchangeAnswerURL(q.Link, a.AnswerID)
+		fmt.Fprintf(&content, "### Accepted Answer (score %d)\n\n", a.Score)
+		body := markdown.DemoteHeadings(markdown.FromHTML(a.Body, q.Link), 3)
+		content.WriteString(strings.TrimSpace(truncateMarkdown(body, maxStackExchangeAnswerChars, "The answer is truncated; see Stack Exchange for the rest.")))
+		content.WriteString("\n\n")
+
+		// Content is licensed CC BY-SA, which requires attributing the
+		// author and linking the source
+		author := html.UnescapeString(a.Owner.DisplayName)
+		if a.Owner.Link != "" {
+			author = fmt.Sprintf("[%s](%s)", author, a.Owner.Link)
+		}
+		fmt.Fprintf(&content, "_[Answer](%s) by %s, licensed under %s._\n\n", answerURL, cmp.Or(author, "an unknown user"), stackExchangeLicense(a))
+	}
+
+	if shown == 0 {
+		content.WriteString("No questions with accepted answers matched. Try fewer words from the error message, or drop the tag.\n")
+	}
+
+	return &StackExchangeAnswers{
+		Query:   query,
+		Tag:     tag,
+		Site:    site,
+		Content: strings.TrimSpace(content.String()) + "\n",
+	}
+}
+
+// stackExchangeAnswerURL is the short link of an answer on the site of
+// its question
+func stackExchangeAnswerURL(questionLink string, answerID int64) string {
+	u, err := url.Parse(questionLink)
+	if err != nil || u.Host == "" {
+		return questionLink
+	}
+	return fmt.Sprintf("https://%s/a/%d", u.Host, answerID)
+}
+
+// stackExchangeLicense names the license of an answer, which depends on
+// when it was posted when the API does not report it
+func stackExchangeLicense(a *stackExchangeAnswer) string {
+	license := a.ContentLicense
+	if license == "" {
+		posted := time.Unix(a.CreationDate, 0).UTC()
+		switch {
+		case posted.Before(time.Date(2011, 4, 8, 0, 0, 0, 0, time.UTC)):
+			license = "CC BY-SA 2.5"
+		case posted.Before(time.Date(2018, 5, 2, 0, 0, 0, 0, time.UTC)):
+			license = "CC BY-SA 3.0"
+		default:
+			license = "CC BY-SA 4.0"
+		}
+	}
+	version := strings.TrimPrefix(license, "CC BY-SA ")
+	if version == license {
+		return license
+	}
+	return fmt.Sprintf("[%s](https://creativecommons.org/licenses/by-sa/%s/)", license, version)
+}
@@ -0,0 +1,478 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+const (
+	terraformRegistryURL = "https://registry.terraform.io"
+
+	// maxTerraformExamples is how many snippets are returned
+	maxTerraformExamples = 6
+	// maxTerraformExampleDirs caps the example directories of the provider
+	// repository that are searched for the resource
+	maxTerraformExampleDirs = 5
+	// maxTerraformExampleLines cuts long resource blocks, such as ones with
+	// inline policies
+	maxTerraformExampleLines = 80
+)
+
+var (
+	terraformResourceTypePattern = regexp.MustCompile(`^[a-z0-9]+_[a-z0-9_]+$`)
+	terraformProviderPattern     = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*/[a-z0-9][a-z0-9-]*$`)
+	terraformFencePattern        = regexp.MustCompile("^```\\s*(terraform|hcl|tf)?\\s*$")
+	markdownHeadingPattern       = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+)
+
+// TerraformExample is an HCL snippet using a resource
+type TerraformExample struct {
+	// Source is registry, for the resource's documentation, or github, for
+	// the provider repository's examples
+	Source string
+	Title  string
+	URL    string
+	HCL    string
+}
+
+// TerraformExamplesInfo holds the usage examples of a provider's resource
+type TerraformExamplesInfo struct {
+	Resource string
+	// Provider is the registry address, e.g. "hashicorp/aws"
+	Provider   string
+	Version    string
+	Repository string
+	Examples   []TerraformExample
+	Content    string
+}
+
+// terraformProvider is the part of a registry provider that examples are
+// looked up in
+type terraformProvider struct {
+	Version string `json:"version"`
+	Source  string `json:"source"`
+	Docs    []struct {
+		ID       string `json:"id"`
+		Title    string `json:"title"`
+		Path     string `json:"path"`
+		Slug     string `json:"slug"`
+		Category string `json:"category"`
+	} `json:"docs"`
+}
+
+// FindTerraformExamples finds usage examples of a resource type, such as
+// aws_s3_bucket, in the Example Usage of its registry documentation and in
+// the examples directory of the provider's repository. The provider is
+// taken from the resource type's prefix under the hashicorp namespace,
+// unless given as "namespace/name".
+func (f *TerraformFetcher) FindTerraformExamples(resource, provider string) (*TerraformExamplesInfo, error) {
+	resource, provider, err := terraformResourceProvider(resource, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return shareFetch(f.flights, flightKey("FindTerraformExamples", resource, provider), "", func() (*TerraformExamplesInfo, error) {
+		return f.findTerraformExamples(resource, provider)
+	})
+}
+
+// terraformResourceProvider validates a resource type and returns it with
+// its registry provider: the given one, under the hashicorp namespace unless
+// it names its own, or else the resource type's prefix under hashicorp
+func terraformResourceProvider(resource, provider string) (string, string, error) {
+	resource = strings.ToLower(strings.TrimSpace(resource))
+	if !terraformResourceTypePattern.MatchString(resource) {
+		return "", "", fmt.Errorf("invalid resource type %q (expected e.g. 'aws_s3_bucket')", resource)
+	}
+
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	name, _, _ := strings.Cut(resource, "_")
+	switch {
+	case provider == "":
+		provider = "hashicorp/" + name
+	case !strings.Contains(provider, "/"):
+		provider = "hashicorp/" + provider
+	}
+	if !terraformProviderPattern.MatchString(provider) {
+		return "", "", fmt.Errorf("invalid provider %q (expected e.g. 'hashicorp/aws')", provider)
+	}
+	return resource, provider, nil
+}
+
+func (f *TerraformFetcher) findTerraformExamples(resource, provider string) (*TerraformExamplesInfo, error) {
+	namespace, name, _ := strings.Cut(provider, "/")
+
+	p, err := f.registryProvider(provider, "")
+	if err != nil {
+		return nil, err
+	}
+
+	info := &TerraformExamplesInfo{
+		Resource:   resource,
+		Provider:   provider,
+		Version:    p.Version,
+		Repository: normalizeGitHubRepository(p.Source),
+	}
+
+	// Resource pages are named without the provider prefix: s3_bucket for
+	// aws_s3_bucket
+	slug := strings.TrimPrefix(resource, name+"_")
+	for _, doc := range p.Docs {
+		if doc.Category != "resources" || (doc.Slug != slug && doc.Slug != resource) {
+			continue
+		}
+		docURL := fmt.Sprintf("%s/providers/%s/%s/latest/docs/resources/%s", terraformRegistryURL, namespace, name, doc.Slug)
+		examples, err := f.registryExamples(resource, doc.ID, docURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch the documentation of %s: %v\n", resource, err)
+		}
+		info.Examples = append(info.Examples, examples...)
+		break
+	}
+
+	if strings.Count(info.Repository, "/") == 1 && strings.Contains(p.Source, "github.com") {
+		examples, err := f.repositoryExamples(resource, info.Repository)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to search the examples of %s: %v\n", info.Repository, err)
+		}
+		info.Examples = append(info.Examples, examples...)
+	}
+
+	info.Examples = dedupeTerraformExamples(info.Examples)
+	if len(info.Examples) == 0 {
+		return nil, fmt.Errorf("no examples of %s found in the documentation or repository of %s", resource, provider)
+	}
+	if len(info.Examples) > maxTerraformExamples {
+		info.Examples = info.Examples[:maxTerraformExamples]
+	}
+
+	info.Content = buildTerraformExamplesContent(info)
+	return info, nil
+}
+
+// registryProvider returns a provider's registry entry, of the given version
+// or else the latest
+func (f *TerraformFetcher) registryProvider(provider, version string) (*terraformProvider, error) {
+	namespace, name, _ := strings.Cut(provider, "/")
+
+	providerURL := fmt.Sprintf("%s/v1/providers/%s/%s", terraformRegistryURL, namespace, name)
+	cachedName := name + ".json"
+	if version != "" {
+		providerURL += "/" + version
+		cachedName = cache.EntryName(name, version) + ".json"
+	}
+	body, found, err := f.fetchCached(f.getCache().GetFilePath("terraform", "providers", namespace, cachedName), providerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch provider %s: %w", provider, err)
+	}
+	if !found {
+		if version != "" {
+			return nil, fmt.Errorf("provider %s %s not found in the Terraform Registry", provider, version)
+		}
+		return nil, fmt.Errorf("provider %s not found in the Terraform Registry", provider)
+	}
+	var p terraformProvider
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse provider %s: %w", provider, err)
+	}
+	return &p, nil
+}
+
+// providerDoc returns the markdown of a provider documentation page
+func (f *TerraformFetcher) providerDoc(docID string) (string, error) {
+	body, found, err := f.fetchCached(f.getCache().GetFilePath("terraform", "provider-docs", docID+".json"),
+		fmt.Sprintf("%s/v2/provider-docs/%s", terraformRegistryURL, url.PathEscape(docID)))
+	if err != nil || !found {
+		return "", err
+	}
+	var doc struct {
+		Data struct {
+			Attributes struct {
+				Content string `json:"content"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return "", fmt.Errorf("failed to parse the documentation: %w", err)
+	}
+	return doc.Data.Attributes.Content, nil
+}
+
+// registryExamples returns the HCL blocks under the Example Usage headings
+// of a resource's documentation, those declaring the resource first
+func (f *TerraformFetcher) registryExamples(resource, docID, docURL string) ([]TerraformExample, error) {
+	doc, err := f.providerDoc(docID)
+	if err != nil || doc == "" {
+		return nil, err
+	}
+
+	var declaring, others []TerraformExample
+	for _, block := range exampleUsageBlocks(doc) {
+		example := TerraformExample{Source: "registry", Title: block.heading, URL: docURL, HCL: block.code}
+		if strings.Contains(block.code, fmt.Sprintf("resource %q", resource)) {
+			declaring = append(declaring, example)
+		} else {
+			others = append(others, example)
+		}
+	}
+	return append(declaring, others...), nil
+}
+
+type markdownCodeBlock struct {
+	heading string
+	code    string
+}
+
+// exampleUsageBlocks returns the Terraform code blocks of a documentation
+// page that are under a heading naming an example, with that heading
+func exampleUsageBlocks(doc string) []markdownCodeBlock {
+	var blocks []markdownCodeBlock
+	var code []string
+	heading, exampleLevel := "", 0
+	inFence, isHCL, inExample := false, false, false
+	for _, line := range strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inFence {
+			if trimmed == "```" {
+				inFence = false
+				if inExample && isHCL && len(code) > 0 {
+					blocks = append(blocks, markdownCodeBlock{heading: heading, code: strings.Join(code, "\n")})
+				}
+				code = nil
+				continue
+			}
+			code = append(code, line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			// Other fences, such as JSON policies or shell imports, are
+			// skipped with their contents
+			inFence, isHCL = true, terraformFencePattern.MatchString(trimmed)
+			continue
+		}
+		if m := markdownHeadingPattern.FindStringSubmatch(trimmed); m != nil {
+			level := len(m[1])
+			switch {
+			case strings.Contains(strings.ToLower(m[2]), "example"):
+				inExample = true
+				if exampleLevel == 0 || level <= exampleLevel {
+					exampleLevel = level
+				}
+			case inExample && level <= exampleLevel:
+				inExample, exampleLevel = false, 0
+			}
+			heading = m[2]
+		}
+	}
+	return blocks
+}
+
+// repositoryExamples searches the examples directory of a provider's
+// repository: examples/resources/<type>/ in repositories laid out for
+// tfplugindocs, else the example directories whose names share the most
+// words with the resource type
+func (f *TerraformFetcher) repositoryExamples(resource, repository string) ([]TerraformExample, error) {
+	entries, found, err := f.githubContents(repository, "examples")
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, e := range entries {
+		if e.Type == "dir" && e.Name == "resources" {
+			dirs = append(dirs, "examples/resources/"+resource)
+			break
+		}
+	}
+	if len(dirs) == 0 {
+		words := strings.Split(resource, "_")[1:]
+		type scored struct {
+			path  string
+			score int
+		}
+		var candidates []scored
+		for _, e := range entries {
+			if e.Type != "dir" {
+				continue
+			}
+			score := 0
+			for _, part := range strings.FieldsFunc(strings.ToLower(e.Name), func(r rune) bool { return r == '-' || r == '_' }) {
+				for _, w := range words {
+					if part == w || part == w+"s" {
+						score++
+					}
+				}
+			}
+			if score > 0 {
+				candidates = append(candidates, scored{e.Path, score})
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+		for i, c := range candidates {
+			if i == maxTerraformExampleDirs {
+				break
+			}
+			dirs = append(dirs, c.path)
+		}
+	}
+
+	var examples []TerraformExample
+	for _, dir := range dirs {
+		files, found, err := f.githubContents(repository, dir)
+		if err != nil {
+			return examples, err
+		}
+		if !found {
+			continue
+		}
+		for _, file := range files {
+			if file.Type != "file" || path.Ext(file.Name) != ".tf" || file.DownloadURL == "" {
+				continue
+			}
+			src, found, err := f.fetchCached(f.getCache().GetFilePath("terraform", "examples", repository, file.Path), file.DownloadURL)
+			if err != nil || !found {
+				continue
+			}
+			for _, block := range terraformResourceBlocks(string(src), resource) {
+				examples = append(examples, TerraformExample{Source: "github", Title: file.Path, URL: file.HTMLURL, HCL: block})
+			}
+		}
+	}
+	return examples, nil
+}
+
+type githubContentEntry struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	HTMLURL     string `json:"html_url"`
+	DownloadURL string `json:"download_url"`
+}
+
+// githubContents lists a directory of a repository's default branch
+func (f *TerraformFetcher) githubContents(repository, dir string) ([]githubContentEntry, bool, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/contents/%s", repository, dir)
+	body, found, err := f.fetchCached(f.getCache().GetFilePath("terraform", "examples", repository, dir, "contents.json"), apiURL)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	var entries []githubContentEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		// A file, not a directory
+		return nil, false, nil
+	}
+	return entries, true, nil
+}
+
+// terraformResourceBlocks extracts the blocks declaring a resource type from
+// a Terraform file, cut at maxTerraformExampleLines
+func terraformResourceBlocks(src, resource string) []string {
+	header := regexp.MustCompile(`^\s*resource\s+"` + regexp.QuoteMeta(resource) + `"\s+"[^"]+"\s*\{`)
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var blocks []string
+	for i := 0; i < len(lines); i++ {
+		if !header.MatchString(lines[i]) {
+			continue
+		}
+		depth, end := 0, -1
+		for j := i; j < len(lines) && end < 0; j++ {
+			depth += hclBraceDelta(lines[j])
+			if depth <= 0 {
+				end = j
+			}
+		}
+		if end < 0 {
+			continue
+		}
+		block := lines[i : end+1]
+		if len(block) > maxTerraformExampleLines {
+			block = append(append([]string{}, block[:maxTerraformExampleLines]...), "  # ...", "}")
+		}
+		blocks = append(blocks, strings.Join(block, "\n"))
+		i = end
+	}
+	return blocks
+}
+
+// hclBraceDelta counts the braces a line opens less those it closes,
+// outside strings and comments
+func hclBraceDelta(line string) int {
+	delta, inString := 0, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '#' || (c == '/' && i+1 < len(line) && line[i+1] == '/'):
+			return delta
+		case c == '{':
+			delta++
+		case c == '}':
+			delta--
+		}
+	}
+	return delta
+}
+
+func dedupeTerraformExamples(examples []TerraformExample) []TerraformExample {
+	seen := map[string]bool{}
+	out := examples[:0]
+	for _, e := range examples {
+		key := strings.Join(strings.Fields(e.HCL), " ")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, e)
+	}
+	return out
+}
+
+func buildTerraformExamplesContent(info *TerraformExamplesInfo) string {
+	var content strings.Builder
+	fmt.Fprintf(&content, "# %s examples\n\n", info.Resource)
+
+	fmt.Fprintf(&content, "**Provider:** [%s](%s/providers/%s/latest)", info.Provider, terraformRegistryURL, info.Provider)
+	if info.Version != "" {
+		fmt.Fprintf(&content, " %s", info.Version)
+	}
+	content.WriteString("\n\n")
+
+	for i, e := range info.Examples {
+		title := e.Title
+		if title == "" {
+			title = "Example Usage"
+		}
+		fmt.Fprintf(&content, "## %d. %s\n\n", i+1, title)
+		content.WriteString("```hcl\n")
+		content.WriteString(strings.TrimSpace(e.HCL))
+		content.WriteString("\n```\n\n")
+		if e.Source == "registry" {
+			fmt.Fprintf(&content, "_From the [%s documentation](%s) on the Terraform Registry._\n\n", info.Resource, e.URL)
+		} else {
+			fmt.Fprintf(&content, "_From [%s](%s) in %s._\n\n", e.Title, e.URL, info.Repository)
+		}
+	}
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [%s on the Terraform Registry](%s/providers/%s/latest/docs/resources/%s)\n",
+		info.Resource, terraformRegistryURL, info.Provider, strings.TrimPrefix(info.Resource, path.Base(info.Provider)+"_"))
+	if info.Repository != "" {
+		fmt.Fprintf(&content, "- [%s](https://github.com/%s)\n", info.Repository, info.Repository)
+	}
+	content.WriteString("\nExamples are copied from the provider's documentation and repository under its license; check arguments against the provider version you pin.\n")
+
+	return content.String()
+}
@@ -1,24 +1,18 @@
 package fetcher
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
-	"time"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
 )
 
-type TerraformVersionInfo struct {
-	Version     string `yaml:"version"`
-	ReleaseDate string `yaml:"releaseDate"`
-	ReleaseURL  string `yaml:"releaseURL"`
-	Content     string `yaml:"-"`
-}
+type TerraformVersionInfo = ReleaseVersionInfo
 
 type TerraformFetcher struct {
 	*BaseFetcher
@@ -32,6 +26,17 @@ func NewTerraformFetcher(cacheDir string) *TerraformFetcher {
 
 // FetchTerraformVersion fetches information about a specific Terraform version
 func (f *TerraformFetcher) FetchTerraformVersion(version string) (*TerraformVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchTerraformVersion", version), f.versionPath(version), func() (*TerraformVersionInfo, error) {
+		return f.fetchTerraformVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a Terraform version
+func (f *TerraformFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("terraform", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *TerraformFetcher) fetchTerraformVersion(version string) (*TerraformVersionInfo, error) {
 	// Normalize version (add 'v' prefix if missing for GitHub API)
 	githubVersion := version
 	if !strings.HasPrefix(version, "v") {
@@ -39,7 +44,7 @@ func (f *TerraformFetcher) FetchTerraformVersion(version string) (*TerraformVers
 	}
 
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("terraform", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
 	if err == nil && versionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded Terraform version '%s' from cache\n", version)
@@ -79,42 +84,13 @@ func (f *TerraformFetcher) FetchTerraformVersion(version string) (*TerraformVers
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse GitHub release data
-	var releaseData map[string]interface{}
-	if err := json.Unmarshal(body, &releaseData); err != nil {
-		return nil, fmt.Errorf("failed to parse release data: %w", err)
-	}
-
-	// Extract version information
-	versionInfo = &TerraformVersionInfo{
-		Version: version,
-	}
-
-	// Extract release URL
-	if htmlURL, ok := releaseData["html_url"].(string); ok {
-		versionInfo.ReleaseURL = htmlURL
-	}
-
-	// Extract release date
-	if publishedAt, ok := releaseData["published_at"].(string); ok {
-		// Parse and format the date
-		if t, err := time.Parse(time.RFC3339, publishedAt); err == nil {
-			versionInfo.ReleaseDate = t.Format("2006-01-02")
-		} else {
-			versionInfo.ReleaseDate = publishedAt
-		}
-	}
-
-	// Extract release notes from body
-	releaseNotes := ""
-	if body, ok := releaseData["body"].(string); ok {
-		releaseNotes = body
+	versionInfo, err = renderReleaseVersion(version, body, f.buildVersionContent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build content
-	versionInfo.Content = f.buildVersionContent(versionInfo, releaseNotes)
-
-	// Cache the result
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
 	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
 	}
@@ -172,17 +148,12 @@ func (f *TerraformFetcher) buildVersionContent(info *TerraformVersionInfo, relea
 }
 
 func (f *TerraformFetcher) saveVersionInfoAsMarkdown(filePath string, info *TerraformVersionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
 	if info.ReleaseDate != "" {
 		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
 	}
@@ -194,16 +165,16 @@ func (f *TerraformFetcher) saveVersionInfoAsMarkdown(filePath string, info *Terr
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *TerraformFetcher) loadVersionInfoFromMarkdown(filePath string) (*TerraformVersionInfo, error) {
-	expired, err := f.getCache().IsExpired(filePath)
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
 	if err != nil || expired {
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -218,12 +189,23 @@ func (f *TerraformFetcher) loadVersionInfoFromMarkdown(filePath string) (*Terraf
 		Version     string `yaml:"version"`
 		ReleaseDate string `yaml:"releaseDate"`
 		ReleaseURL  string `yaml:"releaseURL"`
+		Template    int    `yaml:"template"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if info, err := renderReleaseVersion(meta.Version, payload, f.buildVersionContent); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered Terraform version '%s' with current templates\n", meta.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
 	return &TerraformVersionInfo{
 		Version:     meta.Version,
 		ReleaseDate: meta.ReleaseDate,
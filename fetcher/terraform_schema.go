@@ -0,0 +1,561 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+const (
+	// terraformSchemaTimeout bounds terraform init, which downloads the
+	// provider, and the schema dump
+	terraformSchemaTimeout = 10 * time.Minute
+	// maxTerraformSchemaChars keeps large resources, such as aws_instance,
+	// to a reference-sized answer
+	maxTerraformSchemaChars = 40000
+)
+
+var terraformProviderVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+// errNoTerraformCLI means neither terraform nor tofu is installed
+var errNoTerraformCLI = errors.New("neither terraform nor tofu is installed")
+
+// TerraformSchemaInfo is the schema of a resource or data source
+type TerraformSchemaInfo struct {
+	Resource string
+	// Kind is resource or data
+	Kind string
+	// Provider is the registry address, e.g. "hashicorp/aws"
+	Provider string
+	Version  string
+	// FromDocs is set when the schema comes from the registry documentation
+	// because no Terraform CLI is installed
+	FromDocs bool
+	Content  string
+}
+
+// terraformProviderSchemas is the output of terraform providers schema -json
+type terraformProviderSchemas struct {
+	ProviderSchemas map[string]struct {
+		ResourceSchemas   map[string]terraformSchema `json:"resource_schemas"`
+		DataSourceSchemas map[string]terraformSchema `json:"data_source_schemas"`
+	} `json:"provider_schemas"`
+}
+
+type terraformSchema struct {
+	Block terraformBlock `json:"block"`
+}
+
+type terraformBlock struct {
+	Attributes  map[string]terraformAttribute `json:"attributes"`
+	BlockTypes  map[string]terraformBlockType `json:"block_types"`
+	Description string                        `json:"description"`
+	Deprecated  bool                          `json:"deprecated"`
+}
+
+type terraformAttribute struct {
+	// Type is a cty type in JSON: "string", ["list", "string"], ...
+	Type json.RawMessage `json:"type"`
+	// NestedType replaces Type for nested attributes (protocol 6)
+	NestedType  *terraformNestedType `json:"nested_type"`
+	Description string               `json:"description"`
+	Required    bool                 `json:"required"`
+	Optional    bool                 `json:"optional"`
+	Computed    bool                 `json:"computed"`
+	Sensitive   bool                 `json:"sensitive"`
+	Deprecated  bool                 `json:"deprecated"`
+}
+
+type terraformNestedType struct {
+	Attributes  map[string]terraformAttribute `json:"attributes"`
+	NestingMode string                        `json:"nesting_mode"`
+}
+
+type terraformBlockType struct {
+	NestingMode string         `json:"nesting_mode"`
+	Block       terraformBlock `json:"block"`
+	MinItems    int            `json:"min_items"`
+	MaxItems    int            `json:"max_items"`
+}
+
+// FetchTerraformSchema renders the arguments, attributes, and nested blocks
+// of a resource type, or of a data source when kind is "data", from its
+// provider's schema: the output of terraform providers schema -json, which
+// downloads the provider from the registry. Without terraform or tofu
+// installed, the schema sections of the registry documentation are
+// returned instead. The provider is resolved as for FindTerraformExamples;
+// version defaults to its latest release.
+func (f *TerraformFetcher) FetchTerraformSchema(resource, provider, version, kind string) (*TerraformSchemaInfo, error) {
+	resource, provider, err := terraformResourceProvider(resource, provider)
+	if err != nil {
+		return nil, err
+	}
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "latest" {
+		version = ""
+	}
+	if version != "" && !terraformProviderVersionPattern.MatchString(version) {
+		return nil, fmt.Errorf("invalid provider version %q (expected e.g. '5.80.0')", version)
+	}
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "", "resource":
+		kind = "resource"
+	case "data", "data-source", "data_source":
+		kind = "data"
+	default:
+		return nil, fmt.Errorf("unsupported kind %q (supported: resource, data)", kind)
+	}
+
+	return shareFetch(f.flights, flightKey("FetchTerraformSchema", resource, provider, version, kind), "", func() (*TerraformSchemaInfo, error) {
+		return f.fetchTerraformSchema(resource, provider, version, kind)
+	})
+}
+
+func (f *TerraformFetcher) fetchTerraformSchema(resource, provider, version, kind string) (*TerraformSchemaInfo, error) {
+	p, err := f.registryProvider(provider, version)
+	if err != nil {
+		return nil, err
+	}
+	info := &TerraformSchemaInfo{Resource: resource, Kind: kind, Provider: provider, Version: p.Version}
+
+	schemas, err := f.providerSchemas(provider, p.Version)
+	if err == nil {
+		schema, found := schemas.lookup(provider, resource, kind)
+		if !found {
+			return nil, fmt.Errorf("provider %s %s has no %s %s", provider, p.Version, terraformKindName(kind), resource)
+		}
+		info.Content = buildTerraformSchemaContent(info, schema)
+		return info, nil
+	}
+	if !errors.Is(err, errNoTerraformCLI) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to dump the schema of %s %s, using its documentation: %v\n", provider, p.Version, err)
+	}
+
+	// The documentation lists the same arguments and attributes, as prose
+	category := "resources"
+	if kind == "data" {
+		category = "data-sources"
+	}
+	_, name, _ := strings.Cut(provider, "/")
+	slug := strings.TrimPrefix(resource, name+"_")
+	for _, doc := range p.Docs {
+		if doc.Category != category || (doc.Slug != slug && doc.Slug != resource) {
+			continue
+		}
+		content, err := f.providerDoc(doc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the documentation of %s: %w", resource, err)
+		}
+		sections := terraformDocSchemaSections(content)
+		if sections == "" {
+			return nil, fmt.Errorf("the documentation of %s lists no arguments; install terraform or tofu for its schema", resource)
+		}
+		info.FromDocs = true
+		info.Content = buildTerraformDocSchemaContent(info, doc.Slug, sections)
+		return info, nil
+	}
+	return nil, fmt.Errorf("provider %s %s documents no %s %s", provider, p.Version, terraformKindName(kind), resource)
+}
+
+// providerSchemas returns the schemas of a provider version, dumped with
+// the Terraform CLI and cached. Schemas of large providers run to tens of
+// megabytes; the provider itself is downloaded to a temporary directory and
+// removed.
+func (f *TerraformFetcher) providerSchemas(provider, version string) (*terraformProviderSchemas, error) {
+	namespace, name, _ := strings.Cut(provider, "/")
+	cachedPath := f.getCache().GetFilePath("terraform", "schemas", namespace, cache.EntryName(name, version)+".json")
+
+	var data []byte
+	if expired, err := f.getCache().IsExpired(cachedPath); err == nil && !expired {
+		data, _ = f.getCache().ReadFile(cachedPath)
+	}
+	if data == nil {
+		var err error
+		if data, err = dumpTerraformSchema(provider, version); err != nil {
+			return nil, err
+		}
+		if err := f.getCache().WriteFile(cachedPath, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache the schema of %s: %v\n", provider, err)
+		}
+	}
+
+	var schemas terraformProviderSchemas
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, fmt.Errorf("failed to parse the schema of %s: %w", provider, err)
+	}
+	return &schemas, nil
+}
+
+// dumpTerraformSchema runs terraform init and terraform providers schema
+// -json (or their tofu equivalents) in a configuration requiring only the
+// provider
+func dumpTerraformSchema(provider, version string) ([]byte, error) {
+	cli := ""
+	for _, name := range []string{"terraform", "tofu"} {
+		if _, err := exec.LookPath(name); err == nil {
+			cli = name
+			break
+		}
+	}
+	if cli == "" {
+		return nil, errNoTerraformCLI
+	}
+
+	dir, err := os.MkdirTemp("", "open-context-terraform-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a working directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	_, name, _ := strings.Cut(provider, "/")
+	config := fmt.Sprintf("terraform {\n  required_providers {\n    %s = {\n      source  = %q\n      version = %q\n    }\n  }\n}\n", name, provider, version)
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write the configuration: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), terraformSchemaTimeout)
+	defer cancel()
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, cli, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "TF_IN_AUTOMATION=1", "CHECKPOINT_DISABLE=1")
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%s %s timed out after %v", cli, args[0], terraformSchemaTimeout)
+			}
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return nil, fmt.Errorf("%s %s: %s", cli, args[0], msg)
+			}
+			return nil, fmt.Errorf("%s %s: %w", cli, args[0], err)
+		}
+		return out, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Downloading %s %s with %s to read its schema...\n", provider, version, cli)
+	if _, err := run("init", "-backend=false", "-input=false", "-no-color"); err != nil {
+		return nil, err
+	}
+	return run("providers", "schema", "-json")
+}
+
+// lookup returns the schema of a resource or data source. The provider's
+// key names its registry host, which differs between terraform and tofu.
+func (s *terraformProviderSchemas) lookup(provider, resource, kind string) (terraformSchema, bool) {
+	for key, p := range s.ProviderSchemas {
+		if key != provider && !strings.HasSuffix(key, "/"+provider) {
+			continue
+		}
+		schemas := p.ResourceSchemas
+		if kind == "data" {
+			schemas = p.DataSourceSchemas
+		}
+		schema, found := schemas[resource]
+		return schema, found
+	}
+	return terraformSchema{}, false
+}
+
+func terraformKindName(kind string) string {
+	if kind == "data" {
+		return "data source"
+	}
+	return "resource"
+}
+
+func buildTerraformSchemaContent(info *TerraformSchemaInfo, schema terraformSchema) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Resource)
+	fmt.Fprintf(&content, "**Provider:** [%s](%s/providers/%s/%s) %s\n\n", info.Provider, terraformRegistryURL, info.Provider, info.Version, info.Version)
+	fmt.Fprintf(&content, "**Kind:** %s\n\n", terraformKindName(info.Kind))
+	content.WriteString("**Schema:** `terraform providers schema -json`\n\n")
+	if schema.Block.Deprecated {
+		content.WriteString("**Deprecated:** check the provider's documentation for its replacement.\n\n")
+	}
+	if d := collapseSpace(schema.Block.Description); d != "" {
+		content.WriteString(d + "\n\n")
+	}
+
+	block := "resource"
+	if info.Kind == "data" {
+		block = "data"
+	}
+	content.WriteString("## Required Configuration\n\n")
+	content.WriteString("```hcl\n")
+	fmt.Fprintf(&content, "%s %q \"example\" {\n", block, info.Resource)
+	writeTerraformSkeleton(&content, schema.Block, "  ")
+	content.WriteString("}\n```\n\n")
+
+	var body strings.Builder
+	writeTerraformBlock(&body, schema.Block, "")
+	content.WriteString(strings.TrimSpace(truncateMarkdown(body.String(), maxTerraformSchemaChars, "The schema is truncated; see the registry documentation for the rest.")))
+	content.WriteString("\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [%s on the Terraform Registry](%s/providers/%s/%s/docs/%s/%s)\n",
+		info.Resource, terraformRegistryURL, info.Provider, info.Version, terraformDocCategory(info.Kind), strings.TrimPrefix(info.Resource, filepath.Base(info.Provider)+"_"))
+
+	return content.String()
+}
+
+func buildTerraformDocSchemaContent(info *TerraformSchemaInfo, slug, sections string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Resource)
+	fmt.Fprintf(&content, "**Provider:** [%s](%s/providers/%s/%s) %s\n\n", info.Provider, terraformRegistryURL, info.Provider, info.Version, info.Version)
+	fmt.Fprintf(&content, "**Kind:** %s\n\n", terraformKindName(info.Kind))
+	content.WriteString("**Schema:** the registry documentation. Install terraform or tofu for the schema itself, with every argument's type.\n\n")
+
+	content.WriteString(strings.TrimSpace(truncateMarkdown(sections, maxTerraformSchemaChars, "The documentation is truncated; see the registry for the rest.")))
+	content.WriteString("\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [%s on the Terraform Registry](%s/providers/%s/%s/docs/%s/%s)\n",
+		info.Resource, terraformRegistryURL, info.Provider, info.Version, terraformDocCategory(info.Kind), slug)
+
+	return content.String()
+}
+
+func terraformDocCategory(kind string) string {
+	if kind == "data" {
+		return "data-sources"
+	}
+	return "resources"
+}
+
+// writeTerraformSkeleton writes the required arguments and blocks of a
+// block as HCL, with placeholder values
+func writeTerraformSkeleton(w *strings.Builder, block terraformBlock, indent string) {
+	for _, name := range sortedKeys(block.Attributes) {
+		attr := block.Attributes[name]
+		if !attr.Required {
+			continue
+		}
+		typ := terraformAttributeType(attr)
+		fmt.Fprintf(w, "%s%s = %s # %s\n", indent, name, terraformPlaceholder(typ), typ)
+	}
+	for _, name := range sortedKeys(block.BlockTypes) {
+		bt := block.BlockTypes[name]
+		if bt.MinItems == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s%s {\n", indent, name)
+		writeTerraformSkeleton(w, bt.Block, indent+"  ")
+		fmt.Fprintf(w, "%s}\n", indent)
+	}
+}
+
+// writeTerraformBlock writes the arguments, read-only attributes, and
+// nested blocks of a block. Nested blocks and nested attributes follow as
+// sections named by their path, such as rule.filter.
+func writeTerraformBlock(w *strings.Builder, block terraformBlock, path string) {
+	var args, attrs []string
+	for _, name := range sortedKeys(block.Attributes) {
+		attr := block.Attributes[name]
+		line := terraformAttributeLine(name, attr)
+		if attr.Required || attr.Optional {
+			args = append(args, line)
+		} else {
+			attrs = append(attrs, line)
+		}
+	}
+
+	level := "##"
+	if path != "" {
+		level = "####"
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(w, "%s Arguments\n\n%s\n\n", level, strings.Join(args, "\n"))
+	}
+	if len(attrs) > 0 {
+		fmt.Fprintf(w, "%s Read-Only Attributes\n\n%s\n\n", level, strings.Join(attrs, "\n"))
+	}
+
+	for _, name := range sortedKeys(block.Attributes) {
+		if nested := block.Attributes[name].NestedType; nested != nil {
+			writeTerraformBlock(w, terraformBlock{Attributes: nested.Attributes}, terraformSubsection(w, path, name, nested.NestingMode, 0, 0, ""))
+		}
+	}
+	for _, name := range sortedKeys(block.BlockTypes) {
+		bt := block.BlockTypes[name]
+		writeTerraformBlock(w, bt.Block, terraformSubsection(w, path, name, bt.NestingMode, bt.MinItems, bt.MaxItems, bt.Block.Description))
+	}
+}
+
+// terraformSubsection writes the heading of a nested block and returns its
+// path
+func terraformSubsection(w *strings.Builder, parent, name, mode string, minItems, maxItems int, description string) string {
+	path := name
+	if parent != "" {
+		path = parent + "." + name
+	}
+	fmt.Fprintf(w, "### `%s` block\n\n", path)
+
+	var shape []string
+	switch mode {
+	case "single", "group":
+		shape = append(shape, "a single block")
+	case "list", "set", "map":
+		shape = append(shape, mode+" of blocks")
+	}
+	if minItems > 0 {
+		shape = append(shape, fmt.Sprintf("at least %d required", minItems))
+	}
+	if maxItems > 0 {
+		shape = append(shape, fmt.Sprintf("at most %d", maxItems))
+	}
+	if len(shape) > 0 {
+		fmt.Fprintf(w, "_%s_\n\n", strings.Join(shape, ", "))
+	}
+	if d := collapseSpace(description); d != "" {
+		w.WriteString(d + "\n\n")
+	}
+	return path
+}
+
+func terraformAttributeLine(name string, attr terraformAttribute) string {
+	flags := []string{terraformAttributeType(attr)}
+	switch {
+	case attr.Required:
+		flags = append(flags, "required")
+	case attr.Optional && attr.Computed:
+		flags = append(flags, "optional, computed when unset")
+	case attr.Optional:
+		flags = append(flags, "optional")
+	}
+	if attr.Sensitive {
+		flags = append(flags, "sensitive")
+	}
+	if attr.Deprecated {
+		flags = append(flags, "deprecated")
+	}
+
+	line := fmt.Sprintf("- `%s` (%s)", name, strings.Join(flags, ", "))
+	if d := collapseSpace(attr.Description); d != "" {
+		line += ": " + d
+	}
+	return line
+}
+
+// terraformAttributeType renders the type of an attribute as it is written
+// in HCL type constraints, e.g. list(string) or map(object({...}))
+func terraformAttributeType(attr terraformAttribute) string {
+	if attr.NestedType != nil {
+		switch attr.NestedType.NestingMode {
+		case "list", "set", "map":
+			return attr.NestedType.NestingMode + "(object)"
+		}
+		return "object"
+	}
+	var t interface{}
+	if err := json.Unmarshal(attr.Type, &t); err != nil {
+		return "dynamic"
+	}
+	return ctyTypeString(t)
+}
+
+func ctyTypeString(t interface{}) string {
+	switch t := t.(type) {
+	case string:
+		return t
+	case []interface{}:
+		if len(t) < 2 {
+			break
+		}
+		kind, _ := t[0].(string)
+		switch kind {
+		case "list", "set", "map":
+			return fmt.Sprintf("%s(%s)", kind, ctyTypeString(t[1]))
+		case "object":
+			attrs, _ := t[1].(map[string]interface{})
+			fields := make([]string, 0, len(attrs))
+			for _, name := range sortedKeys(attrs) {
+				fields = append(fields, fmt.Sprintf("%s = %s", name, ctyTypeString(attrs[name])))
+			}
+			return fmt.Sprintf("object({%s})", strings.Join(fields, ", "))
+		case "tuple":
+			elems, _ := t[1].([]interface{})
+			types := make([]string, 0, len(elems))
+			for _, e := range elems {
+				types = append(types, ctyTypeString(e))
+			}
+			return fmt.Sprintf("tuple([%s])", strings.Join(types, ", "))
+		}
+	}
+	return "dynamic"
+}
+
+// terraformPlaceholder is a value of type to fill in
+func terraformPlaceholder(typ string) string {
+	switch {
+	case typ == "string":
+		return `""`
+	case typ == "number":
+		return "0"
+	case typ == "bool":
+		return "false"
+	case strings.HasPrefix(typ, "list"), strings.HasPrefix(typ, "set"), strings.HasPrefix(typ, "tuple"):
+		return "[]"
+	case typ == "dynamic":
+		return "null"
+	}
+	return "{}"
+}
+
+// terraformDocSchemaSections returns the sections of a resource's
+// documentation that list its arguments and attributes: Schema, for pages
+// generated by tfplugindocs, or Argument Reference and Attribute Reference
+func terraformDocSchemaSections(doc string) string {
+	var out []string
+	inFence, keepLevel := false, 0
+	for _, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if m := markdownHeadingPattern.FindStringSubmatch(line); m != nil && !inFence {
+			level := len(m[1])
+			if keepLevel > 0 && level <= keepLevel {
+				keepLevel = 0
+			}
+			if keepLevel == 0 && terraformSchemaHeading(m[2]) {
+				keepLevel = level
+				line = "## " + m[2]
+			}
+		}
+		if keepLevel > 0 {
+			out = append(out, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+func terraformSchemaHeading(title string) bool {
+	switch strings.ToLower(strings.TrimSpace(title)) {
+	case "schema", "argument reference", "arguments reference", "attribute reference", "attributes reference", "attributes":
+		return true
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
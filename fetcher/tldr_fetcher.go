@@ -0,0 +1,222 @@
+package fetcher
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+// tldrPagesURL holds the pages of every platform, e.g. pages/common/tar.md,
+// with translations under pages.<language>/
+const tldrPagesURL = "https://raw.githubusercontent.com/tldr-pages/tldr/main"
+
+// tldrPlatforms are searched in this order when no platform is given, as
+// the tldr clients do
+var tldrPlatforms = []string{"common", "linux", "osx", "windows", "freebsd", "openbsd", "netbsd", "android", "sunos", "cisco-ios"}
+
+var (
+	tldrCommandPattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9_.+-]*$`)
+	tldrLanguagePattern = regexp.MustCompile(`^[a-z]{2}(_[A-Z]{2})?$`)
+)
+
+// TldrPageInfo is the tldr page of a command
+type TldrPageInfo struct {
+	Command  string `yaml:"command"`
+	Platform string `yaml:"platform"`
+	Language string `yaml:"language"`
+	URL      string `yaml:"url"`
+	Content  string `yaml:"-"`
+}
+
+type TldrFetcher struct {
+	*BaseFetcher
+}
+
+func NewTldrFetcher(cacheDir string) *TldrFetcher {
+	return &TldrFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchTldrPage fetches the tldr page of a command ("tar", "git commit")
+// for a platform ("linux", "osx", "windows"; common pages are the
+// fallback) in a language ("en" when empty; English is the fallback)
+func (f *TldrFetcher) FetchTldrPage(command, platform, language string) (*TldrPageInfo, error) {
+	command = strings.Join(strings.Fields(strings.ToLower(command)), "-")
+	if !tldrCommandPattern.MatchString(command) {
+		return nil, fmt.Errorf("invalid command %q", command)
+	}
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	switch platform {
+	case "macos":
+		platform = "osx"
+	case "", "any":
+		platform = ""
+	default:
+		if !slices.Contains(tldrPlatforms, platform) {
+			return nil, fmt.Errorf("unsupported platform %q (supported: %s)", platform, strings.Join(tldrPlatforms, ", "))
+		}
+	}
+	language = strings.TrimSpace(language)
+	if language == "" {
+		language = "en"
+	}
+	if !tldrLanguagePattern.MatchString(language) {
+		return nil, fmt.Errorf("invalid language %q (expected e.g. 'en', 'de', 'pt_BR')", language)
+	}
+
+	return shareFetch(f.flights, flightKey("FetchTldrPage", command, platform, language), f.pagePath(command, platform, language), func() (*TldrPageInfo, error) {
+		return f.fetchTldrPage(command, platform, language)
+	})
+}
+
+// pagePath returns the cache path of a page
+func (f *TldrFetcher) pagePath(command, platform, language string) string {
+	return f.getCache().GetFilePath("tldr", "pages", fmt.Sprintf("%s.md", cache.EntryName(command, platform, language)))
+}
+
+func (f *TldrFetcher) fetchTldrPage(command, platform, language string) (*TldrPageInfo, error) {
+	// Check cache first
+	cachedPath := f.pagePath(command, platform, language)
+	info, err := f.loadTldrPageFromMarkdown(cachedPath)
+	if err == nil && info != nil {
+		fmt.Fprintf(os.Stderr, "Loaded tldr page '%s' from cache\n", command)
+		return info, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching tldr page '%s' from tldr-pages...\n", command)
+
+	// A platform's own page wins over the common one
+	platforms := tldrPlatforms
+	if platform != "" {
+		platforms = []string{platform}
+		if platform != "common" {
+			platforms = append(platforms, "common")
+		}
+	}
+	languages := []string{language}
+	if language != "en" {
+		languages = append(languages, "en")
+	}
+
+	for _, lang := range languages {
+		dir := "pages"
+		if lang != "en" {
+			dir += "." + lang
+		}
+		for _, p := range platforms {
+			page, found, err := f.get(fmt.Sprintf("%s/%s/%s/%s.md", tldrPagesURL, dir, p, command))
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch the tldr page of %s: %w", command, err)
+			}
+			if !found {
+				continue
+			}
+
+			info = &TldrPageInfo{
+				Command:  command,
+				Platform: p,
+				Language: lang,
+				URL:      fmt.Sprintf("https://github.com/tldr-pages/tldr/blob/main/%s/%s/%s.md", dir, p, command),
+			}
+			info.Content = f.buildTldrContent(info, string(page), language)
+
+			if err := f.saveTldrPageAsMarkdown(cachedPath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache tldr page: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
+	if platform != "" {
+		return nil, fmt.Errorf("no tldr page for %s on %s", command, platform)
+	}
+	return nil, fmt.Errorf("no tldr page for %s", command)
+}
+
+// tldrMarkdown turns the examples of a page, each a description followed
+// by a line of inline code, into fenced shell blocks, and drops the page's
+// title for the caller's
+func tldrMarkdown(src string) string {
+	var out []string
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "# "):
+			continue
+		case len(trimmed) > 2 && strings.HasPrefix(trimmed, "`") && strings.HasSuffix(trimmed, "`"):
+			out = append(out, "```sh", trimmed[1:len(trimmed)-1], "```")
+		default:
+			out = append(out, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+func (f *TldrFetcher) buildTldrContent(info *TldrPageInfo, page, language string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", info.Command)
+	fmt.Fprintf(&content, "**Platform:** %s\n\n", info.Platform)
+	if info.Language != language {
+		fmt.Fprintf(&content, "No %s translation exists; this is the English page.\n\n", language)
+	}
+	content.WriteString("Values in `{{braces}}` are placeholders to replace.\n\n")
+
+	content.WriteString(tldrMarkdown(page))
+	content.WriteString("\n\n")
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [tldr page source](%s)\n", info.URL)
+	content.WriteString("- [tldr-pages](https://tldr.sh)\n\n")
+	content.WriteString("_From [tldr-pages](https://github.com/tldr-pages/tldr), [CC BY 4.0](https://creativecommons.org/licenses/by/4.0/)._\n")
+
+	return content.String()
+}
+
+func (f *TldrFetcher) saveTldrPageAsMarkdown(filePath string, info *TldrPageInfo) error {
+	var content strings.Builder
+
+	// YAML frontmatter
+	content.WriteString("---\n")
+	fmt.Fprintf(&content, "command: \"%s\"\n", info.Command)
+	fmt.Fprintf(&content, "platform: \"%s\"\n", info.Platform)
+	fmt.Fprintf(&content, "language: \"%s\"\n", info.Language)
+	fmt.Fprintf(&content, "url: \"%s\"\n", escapeYAML(info.URL))
+	content.WriteString("---\n\n")
+
+	// Markdown content
+	content.WriteString(info.Content)
+
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
+}
+
+func (f *TldrFetcher) loadTldrPageFromMarkdown(filePath string) (*TldrPageInfo, error) {
+	expired, err := f.getCache().IsExpired(filePath)
+	if err != nil || expired {
+		return nil, fmt.Errorf("file not found or expired")
+	}
+
+	data, err := f.getCache().ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid markdown format: missing frontmatter")
+	}
+
+	var info TldrPageInfo
+	if err := yaml.Unmarshal([]byte(parts[1]), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	info.Content = strings.TrimSpace(parts[2])
+	return &info, nil
+}
@@ -0,0 +1,115 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/incu6us/open-context/cache"
+)
+
+// indexedTopic is a page written to a documentation directory of the cache,
+// in the format the documentation provider loads
+type indexedTopic struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Content     string   `json:"content"`
+	Keywords    []string `json:"keywords"`
+	// Version ranks the topic among other versions of its documentation in
+	// search
+	Version string `json:"version,omitempty"`
+
+	// kind groups the topic in summaries, e.g. "Built-in Functions"
+	kind string
+}
+
+// writeTopics replaces the topics of a documentation directory with topics,
+// keyed by a name unique within the documentation, rather than mixing them
+// with those of an older download. It returns how many topics of each kind
+// were written; topics without a kind are counted under "".
+func writeTopics(docDir string, topics map[string]*indexedTopic) (map[string]int, error) {
+	topicsDir := filepath.Join(docDir, "topics")
+	if err := os.RemoveAll(topicsDir); err != nil {
+		return nil, fmt.Errorf("failed to clear old topics: %w", err)
+	}
+	if err := os.MkdirAll(topicsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	kinds := make(map[string]int)
+	for name, topic := range topics {
+		path := filepath.Join(topicsDir, cache.EntryName(name)+".json")
+		if err := writeJSON(path, topic); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write %s: %v\n", name, err)
+			continue
+		}
+		kinds[topic.kind]++
+	}
+	return kinds, nil
+}
+
+// countTopics sums the counts returned by writeTopics
+func countTopics(kinds map[string]int) int {
+	var n int
+	for _, count := range kinds {
+		n += count
+	}
+	return n
+}
+
+// readTopicsMetadata reads the metadata.json of an indexed documentation into
+// metadata, reporting whether it was written less than ttl ago; a ttl of 0
+// never expires
+func readTopicsMetadata(metadataPath string, ttl time.Duration, metadata interface{}) (bool, error) {
+	stat, err := os.Stat(metadataPath)
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, metadata); err != nil {
+		return false, err
+	}
+	return ttl <= 0 || time.Since(stat.ModTime()) < ttl, nil
+}
+
+// maxSummarySections caps the sections listed in an index summary
+const maxSummarySections = 20
+
+// writeSections lists the largest sections of an indexed documentation
+// with their page counts
+func writeSections(b *strings.Builder, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	sections := make([]string, 0, len(counts))
+	for section := range counts {
+		sections = append(sections, section)
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		if counts[sections[i]] != counts[sections[j]] {
+			return counts[sections[i]] > counts[sections[j]]
+		}
+		return sections[i] < sections[j]
+	})
+	if len(sections) > maxSummarySections {
+		sections = sections[:maxSummarySections]
+	}
+
+	b.WriteString("## Sections\n\n")
+	for _, section := range sections {
+		if count := counts[section]; count == 1 {
+			fmt.Fprintf(b, "- %s (1 page)\n", section)
+		} else {
+			fmt.Fprintf(b, "- %s (%d pages)\n", section, count)
+		}
+	}
+	b.WriteString("\n")
+}
@@ -0,0 +1,232 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	dialTimeout      = 10 * time.Second
+	dialKeepAlive    = 30 * time.Second
+	handshakeTimeout = 10 * time.Second
+
+	// happyEyeballsDelay is how long a dial waits on the preferred address
+	// family before racing the other one (RFC 8305)
+	happyEyeballsDelay = 300 * time.Millisecond
+
+	// dnsCacheTTL is how long resolved addresses are reused. A failed lookup
+	// falls back to the expired addresses.
+	dnsCacheTTL = 5 * time.Minute
+
+	// maxIdleConns bounds keep-alive connections across all upstreams
+	maxIdleConns    = 100
+	idleConnTimeout = 90 * time.Second
+)
+
+var (
+	// upstreamClient is the HTTP client shared by every fetcher
+	upstreamClient        *http.Client
+	upstreamTransport     http.RoundTripper = hostLimiter
+	upstreamTransportOnce sync.Once
+)
+
+// initUpstreamTransport sets up the client shared by every fetcher: the
+// per-host limiter over the tuned transport, behind the mirror chain when
+// mirrors are configured
+func initUpstreamTransport(mirrors map[string][]string) {
+	upstreamTransportOnce.Do(func() {
+		if t := newMirrorTransport(hostLimiter, mirrors); t != nil {
+			upstreamTransport = t
+			fmt.Fprintf(os.Stderr, "Info: Using mirrors for %d upstreams\n", len(t.upstreams))
+		}
+		upstreamClient = &http.Client{
+			Timeout:   defaultHTTPTimeout,
+			Transport: upstreamTransport,
+		}
+	})
+}
+
+// newBaseTransport returns the transport upstream connections are made
+// with: cached DNS, dual-stack dialing, HTTP/2, and pooled keep-alive
+// connections sized to the per-host request limit
+func newBaseTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:       dialTimeout,
+		KeepAlive:     dialKeepAlive,
+		FallbackDelay: happyEyeballsDelay,
+	}
+	dns := &dnsCache{
+		resolver: net.DefaultResolver,
+		ttl:      dnsCacheTTL,
+		entries:  make(map[string]dnsEntry),
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dns.dialer(dialer),
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxRequestsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   handshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// dnsCache keeps resolved host addresses for ttl, so bursts of requests to
+// one registry resolve it once
+type dnsCache struct {
+	resolver *net.Resolver
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+type dnsEntry struct {
+	ips     []net.IP
+	expires time.Time
+}
+
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]net.IP, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.ips, nil
+	}
+
+	addrs, err := c.resolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		if ok {
+			return entry.ips, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("no addresses found for %s", host)
+		}
+		return nil, err
+	}
+
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		ips[i] = addr.IP
+	}
+	c.mu.Lock()
+	c.entries[host] = dnsEntry{ips: ips, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return ips, nil
+}
+
+// dialer returns a DialContext that resolves through the cache and races
+// the two address families as net.Dialer does for names it resolves itself
+func (c *dnsCache) dialer(d *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return d.DialContext(ctx, network, addr)
+		}
+
+		ips, err := c.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		// The resolver orders addresses by preference (RFC 6724); the
+		// first address's family is tried first
+		var primaries, fallbacks []net.IP
+		for _, ip := range ips {
+			if (ip.To4() != nil) == (ips[0].To4() != nil) {
+				primaries = append(primaries, ip)
+			} else {
+				fallbacks = append(fallbacks, ip)
+			}
+		}
+		return dialParallel(ctx, d, network, port, primaries, fallbacks)
+	}
+}
+
+// dialParallel dials the primary addresses and, if they have not connected
+// within the fallback delay or have all failed, the fallbacks alongside
+func dialParallel(ctx context.Context, d *net.Dialer, network, port string, primaries, fallbacks []net.IP) (net.Conn, error) {
+	if len(fallbacks) == 0 {
+		return dialSerial(ctx, d, network, port, primaries)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, 2)
+	start := func(ips []net.IP) {
+		go func() {
+			conn, err := dialSerial(ctx, d, network, port, ips)
+			results <- result{conn, err}
+		}()
+	}
+
+	start(primaries)
+	pending, fallbackStarted := 1, false
+	timer := time.NewTimer(d.FallbackDelay)
+	defer timer.Stop()
+
+	var firstErr error
+	for {
+		select {
+		case <-timer.C:
+			if !fallbackStarted {
+				fallbackStarted = true
+				pending++
+				start(fallbacks)
+			}
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				// Close a connection the other family makes too late
+				go func(n int) {
+					for ; n > 0; n-- {
+						if late := <-results; late.conn != nil {
+							_ = late.conn.Close()
+						}
+					}
+				}(pending)
+				return r.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if !fallbackStarted {
+				fallbackStarted = true
+				pending++
+				start(fallbacks)
+			} else if pending == 0 {
+				return nil, firstErr
+			}
+		}
+	}
+}
+
+// dialSerial tries each address in turn
+func dialSerial(ctx context.Context, d *net.Dialer, network, port string, ips []net.IP) (net.Conn, error) {
+	var firstErr error
+	for _, ip := range ips {
+		conn, err := d.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, firstErr
+}
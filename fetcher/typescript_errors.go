@@ -0,0 +1,204 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	// typeScriptDiagnosticsURL lists every message the compiler reports,
+	// keyed by its template, with its code and category
+	typeScriptDiagnosticsURL = "https://raw.githubusercontent.com/microsoft/TypeScript/main/src/compiler/diagnosticMessages.json"
+	// typeScriptExplanationsURL holds plain-English explanations of common
+	// diagnostics, one file per code, from Total TypeScript's error
+	// translator
+	typeScriptExplanationsURL  = "https://raw.githubusercontent.com/total-typescript/ts-error-translator/main/packages/engine/errors"
+	typeScriptExplanationsRepo = "https://github.com/total-typescript/ts-error-translator"
+)
+
+var (
+	// typeScriptCodePattern matches diagnostic codes such as TS2322, as
+	// printed in "error TS2322: Type 'string' is not assignable..."
+	typeScriptCodePattern = regexp.MustCompile(`(?i)\bTS(\d{4,5})\b`)
+	// typeScriptPlaceholderPattern matches the {0} placeholders of message
+	// templates once regexp.QuoteMeta has escaped them
+	typeScriptPlaceholderPattern = regexp.MustCompile(`\\\{\d+\\\}`)
+)
+
+// typeScriptDiagnostic is a message of diagnosticMessages.json
+type typeScriptDiagnostic struct {
+	Code     int    `json:"code"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+}
+
+func (f *ErrorFetcher) explainTypeScriptError(message string) (*ErrorExplanation, error) {
+	diagnostics, err := f.fetchTypeScriptDiagnostics()
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostic := matchTypeScriptDiagnostic(diagnostics, message)
+	if diagnostic == nil {
+		return nil, fmt.Errorf("no TypeScript diagnostic matches %q; pass a code such as TS2322 or the message as tsc printed it", message)
+	}
+
+	// A bare code has nothing to add
+	reportedAs := ""
+	if strings.Contains(strings.TrimSpace(message), " ") {
+		reportedAs = strings.TrimSpace(message)
+	}
+	return f.renderTypeScriptDiagnostic(diagnostic, reportedAs), nil
+}
+
+// FetchTypeScriptDiagnostic resolves a TypeScript diagnostic code ("TS2322"
+// or "2322") to its message template and category, with a plain-English
+// explanation where the community has written one
+func (f *ErrorFetcher) FetchTypeScriptDiagnostic(code string) (*ErrorExplanation, error) {
+	m := typeScriptCodePattern.FindStringSubmatch(code)
+	if m == nil {
+		m = typeScriptCodePattern.FindStringSubmatch("TS" + strings.TrimSpace(code))
+	}
+	if m == nil {
+		return nil, fmt.Errorf("invalid TypeScript diagnostic code %q (expected e.g. 'TS2322')", code)
+	}
+	return shareFetch(f.flights, flightKey("FetchTypeScriptDiagnostic", m[1]), "", func() (*ErrorExplanation, error) {
+		diagnostics, err := f.fetchTypeScriptDiagnostics()
+		if err != nil {
+			return nil, err
+		}
+		diagnostic := matchTypeScriptDiagnostic(diagnostics, "TS"+m[1])
+		if diagnostic == nil {
+			return nil, fmt.Errorf("TypeScript diagnostic TS%s not found", m[1])
+		}
+		return f.renderTypeScriptDiagnostic(diagnostic, ""), nil
+	})
+}
+
+func (f *ErrorFetcher) renderTypeScriptDiagnostic(diagnostic *typeScriptDiagnostic, reportedAs string) *ErrorExplanation {
+	code := fmt.Sprintf("TS%d", diagnostic.Code)
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# TypeScript %s\n\n", code)
+	fmt.Fprintf(&content, "**Category:** %s\n\n", diagnostic.Category)
+	fmt.Fprintf(&content, "**Message template:** `%s`\n\n", diagnostic.Message)
+	if reportedAs != "" {
+		fmt.Fprintf(&content, "**Reported as:** `%s`\n\n", reportedAs)
+	}
+	content.WriteString("Placeholders such as `{0}` stand for the types, names, or values the compiler fills in.\n\n")
+
+	// The explanation is optional; most codes have none
+	explanation, err := f.fetchTypeScriptExplanation(diagnostic.Code)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch the explanation of %s: %v\n", code, err)
+	}
+	if explanation != "" {
+		content.WriteString("## Explanation\n\n")
+		content.WriteString(explanation)
+		content.WriteString("\n\n")
+		fmt.Fprintf(&content, "_Explanation from [ts-error-translator](%s) by Total TypeScript (MIT)._\n\n", typeScriptExplanationsRepo)
+	}
+
+	content.WriteString("## Links\n\n")
+	fmt.Fprintf(&content, "- [%s on typescript.tv](https://typescript.tv/errors/#%s)\n", code, strings.ToLower(code))
+	fmt.Fprintf(&content, "- [TypeScript issues mentioning %s](https://github.com/microsoft/TypeScript/issues?q=%s)\n", code, code)
+	fmt.Fprintf(&content, "- [diagnosticMessages.json](%s)\n", typeScriptDiagnosticsURL)
+
+	return &ErrorExplanation{Tool: "typescript", Code: code, Content: content.String()}
+}
+
+// fetchTypeScriptExplanation returns the community explanation of a
+// diagnostic, or "" when none is written
+func (f *ErrorFetcher) fetchTypeScriptExplanation(code int) (string, error) {
+	cachedPath := f.getCache().GetFilePath("typescript", "diagnostics", fmt.Sprintf("%d.md", code))
+	body, found, err := f.fetchCached(cachedPath, fmt.Sprintf("%s/%d.md", typeScriptExplanationsURL, code))
+	if err != nil || !found {
+		return "", err
+	}
+
+	// Front matter holds the original message and a one-line excerpt;
+	// the body is the explanation
+	text := strings.ReplaceAll(string(body), "\r\n", "\n")
+	if strings.HasPrefix(text, "---\n") {
+		if parts := strings.SplitN(text, "---", 3); len(parts) == 3 {
+			text = parts[2]
+		}
+	}
+	return strings.TrimSpace(markdown.DemoteHeadings(text, 2)), nil
+}
+
+// matchTypeScriptDiagnostic finds a diagnostic by its code in the message,
+// a bare code number, or else the longest template the message fills in
+func matchTypeScriptDiagnostic(diagnostics []typeScriptDiagnostic, message string) *typeScriptDiagnostic {
+	code := 0
+	if m := typeScriptCodePattern.FindStringSubmatch(message); m != nil {
+		code, _ = strconv.Atoi(m[1])
+	} else if n, err := strconv.Atoi(strings.TrimSpace(message)); err == nil {
+		code = n
+	}
+	if code != 0 {
+		for i := range diagnostics {
+			if diagnostics[i].Code == code {
+				return &diagnostics[i]
+			}
+		}
+		return nil
+	}
+
+	var best *typeScriptDiagnostic
+	for i, d := range diagnostics {
+		if best != nil && len(d.Message) <= len(best.Message) {
+			continue
+		}
+		pattern := typeScriptPlaceholderPattern.ReplaceAllString(regexp.QuoteMeta(strings.TrimSuffix(d.Message, ".")), `.+?`)
+		if re, err := regexp.Compile(pattern); err == nil && re.MatchString(message) {
+			best = &diagnostics[i]
+		}
+	}
+	return best
+}
+
+// fetchTypeScriptDiagnostics returns the compiler's diagnostics, sorted by
+// code
+func (f *ErrorFetcher) fetchTypeScriptDiagnostics() ([]typeScriptDiagnostic, error) {
+	indexPath := f.getCache().GetFilePath("typescript", "diagnostics.json")
+
+	var diagnostics []typeScriptDiagnostic
+	if ok, err := f.getCache().Load(indexPath, &diagnostics); err == nil && ok && len(diagnostics) > 0 {
+		return diagnostics, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching TypeScript diagnostic messages...\n")
+	body, status, err := f.get(typeScriptDiagnosticsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch TypeScript diagnostics: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch TypeScript diagnostics: status %d", status)
+	}
+
+	var messages map[string]struct {
+		Category string `json:"category"`
+		Code     int    `json:"code"`
+	}
+	if err := json.Unmarshal(body, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse TypeScript diagnostics: %w", err)
+	}
+	for message, d := range messages {
+		diagnostics = append(diagnostics, typeScriptDiagnostic{Code: d.Code, Category: d.Category, Message: message})
+	}
+	sort.Slice(diagnostics, func(i, j int) bool { return diagnostics[i].Code < diagnostics[j].Code })
+
+	if err := f.getCache().Save(indexPath, diagnostics); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache TypeScript diagnostics: %v\n", err)
+	}
+	return diagnostics, nil
+}
@@ -6,18 +6,37 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
-	"time"
 
 	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/cache"
 )
 
-type TypeScriptVersionInfo struct {
-	Version     string `yaml:"version"`
-	ReleaseDate string `yaml:"releaseDate"`
-	ReleaseURL  string `yaml:"releaseURL"`
-	Content     string `yaml:"-"`
+type TypeScriptVersionInfo = ReleaseVersionInfo
+
+const (
+	typeScriptReleaseNotesAPI = "https://api.github.com/repos/microsoft/TypeScript-Website/contents/packages/documentation/copy/en/release-notes"
+	typeScriptReleaseNotesURL = "https://www.typescriptlang.org/docs/handbook/release-notes"
+)
+
+// typeScriptReleaseNotesFile matches per-version release notes such as "TypeScript 4.9.md"
+var typeScriptReleaseNotesFile = regexp.MustCompile(`^TypeScript (\d+\.\d+)\.md$`)
+
+// TypeScriptFeature is a single entry of the feature index built from the handbook release notes
+type TypeScriptFeature struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	URL     string `json:"url"`
+}
+
+type TypeScriptFeatureInfo struct {
+	Query   string
+	Matches []TypeScriptFeature
+	Content string
 }
 
 type TypeScriptFetcher struct {
@@ -32,6 +51,17 @@ func NewTypeScriptFetcher(cacheDir string) *TypeScriptFetcher {
 
 // FetchTypeScriptVersion fetches information about a specific TypeScript version
 func (f *TypeScriptFetcher) FetchTypeScriptVersion(version string) (*TypeScriptVersionInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchTypeScriptVersion", version), f.versionPath(version), func() (*TypeScriptVersionInfo, error) {
+		return f.fetchTypeScriptVersion(version)
+	})
+}
+
+// versionPath returns the cache path of the info on a TypeScript version
+func (f *TypeScriptFetcher) versionPath(version string) string {
+	return f.getCache().GetFilePath("typescript", "versions", fmt.Sprintf("%s.md", cache.EntryName(version)))
+}
+
+func (f *TypeScriptFetcher) fetchTypeScriptVersion(version string) (*TypeScriptVersionInfo, error) {
 	// Normalize version (add 'v' prefix if missing for GitHub API)
 	githubVersion := version
 	if !strings.HasPrefix(version, "v") {
@@ -39,7 +69,7 @@ func (f *TypeScriptFetcher) FetchTypeScriptVersion(version string) (*TypeScriptV
 	}
 
 	// Check cache first
-	cachedPath := f.getCache().GetFilePath("typescript", "versions", fmt.Sprintf("%s.md", version))
+	cachedPath := f.versionPath(version)
 	versionInfo, err := f.loadVersionInfoFromMarkdown(cachedPath)
 	if err == nil && versionInfo != nil {
 		fmt.Fprintf(os.Stderr, "Loaded TypeScript version '%s' from cache\n", version)
@@ -79,42 +109,13 @@ func (f *TypeScriptFetcher) FetchTypeScriptVersion(version string) (*TypeScriptV
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse GitHub release data
-	var releaseData map[string]interface{}
-	if err := json.Unmarshal(body, &releaseData); err != nil {
-		return nil, fmt.Errorf("failed to parse release data: %w", err)
-	}
-
-	// Extract version information
-	versionInfo = &TypeScriptVersionInfo{
-		Version: version,
-	}
-
-	// Extract release URL
-	if htmlURL, ok := releaseData["html_url"].(string); ok {
-		versionInfo.ReleaseURL = htmlURL
-	}
-
-	// Extract release date
-	if publishedAt, ok := releaseData["published_at"].(string); ok {
-		// Parse and format the date
-		if t, err := time.Parse(time.RFC3339, publishedAt); err == nil {
-			versionInfo.ReleaseDate = t.Format("2006-01-02")
-		} else {
-			versionInfo.ReleaseDate = publishedAt
-		}
-	}
-
-	// Extract release notes from body
-	releaseNotes := ""
-	if body, ok := releaseData["body"].(string); ok {
-		releaseNotes = body
+	versionInfo, err = renderReleaseVersion(version, body, f.buildVersionContent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build content
-	versionInfo.Content = f.buildVersionContent(versionInfo, releaseNotes)
-
-	// Cache the result
+	// Cache the result with the payload it was rendered from
+	f.saveRawPayload(cachedPath, body)
 	if err := f.saveVersionInfoAsMarkdown(cachedPath, versionInfo); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
 	}
@@ -168,17 +169,12 @@ func (f *TypeScriptFetcher) buildVersionContent(info *TypeScriptVersionInfo, rel
 }
 
 func (f *TypeScriptFetcher) saveVersionInfoAsMarkdown(filePath string, info *TypeScriptVersionInfo) error {
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	var content strings.Builder
 
 	// YAML frontmatter
 	content.WriteString("---\n")
 	fmt.Fprintf(&content, "version: \"%s\"\n", info.Version)
+	fmt.Fprintf(&content, "template: %d\n", templateVersion)
 	if info.ReleaseDate != "" {
 		fmt.Fprintf(&content, "releaseDate: \"%s\"\n", info.ReleaseDate)
 	}
@@ -190,16 +186,16 @@ func (f *TypeScriptFetcher) saveVersionInfoAsMarkdown(filePath string, info *Typ
 	// Markdown content
 	content.WriteString(info.Content)
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return f.getCache().WriteFile(filePath, []byte(content.String()))
 }
 
 func (f *TypeScriptFetcher) loadVersionInfoFromMarkdown(filePath string) (*TypeScriptVersionInfo, error) {
-	expired, err := f.getCache().IsExpired(filePath)
+	expired, err := f.getCache().IsExpired(f.agePath(filePath))
 	if err != nil || expired {
 		return nil, fmt.Errorf("file not found or expired")
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := f.getCache().ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -214,12 +210,23 @@ func (f *TypeScriptFetcher) loadVersionInfoFromMarkdown(filePath string) (*TypeS
 		Version     string `yaml:"version"`
 		ReleaseDate string `yaml:"releaseDate"`
 		ReleaseURL  string `yaml:"releaseURL"`
+		Template    int    `yaml:"template"`
 	}
 
 	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
+	if payload, ok := f.stalePayload(filePath, meta.Template); ok {
+		if info, err := renderReleaseVersion(meta.Version, payload, f.buildVersionContent); err == nil {
+			fmt.Fprintf(os.Stderr, "Re-rendered TypeScript version '%s' with current templates\n", meta.Version)
+			if err := f.saveVersionInfoAsMarkdown(filePath, info); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache version info: %v\n", err)
+			}
+			return info, nil
+		}
+	}
+
 	return &TypeScriptVersionInfo{
 		Version:     meta.Version,
 		ReleaseDate: meta.ReleaseDate,
@@ -227,3 +234,289 @@ func (f *TypeScriptFetcher) loadVersionInfoFromMarkdown(filePath string) (*TypeS
 		Content:     strings.TrimSpace(parts[2]),
 	}, nil
 }
+
+// FetchTypeScriptFeature finds the TypeScript version that introduced a language feature
+func (f *TypeScriptFetcher) FetchTypeScriptFeature(name string) (*TypeScriptFeatureInfo, error) {
+	return shareFetch(f.flights, flightKey("FetchTypeScriptFeature", name), "", func() (*TypeScriptFeatureInfo, error) {
+		return f.fetchTypeScriptFeature(name)
+	})
+}
+
+func (f *TypeScriptFetcher) fetchTypeScriptFeature(name string) (*TypeScriptFeatureInfo, error) {
+	index, err := f.fetchFeatureIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := matchTypeScriptFeatures(index, name)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("TypeScript feature %q not found in release notes", name)
+	}
+
+	info := &TypeScriptFeatureInfo{
+		Query:   name,
+		Matches: matches,
+	}
+	info.Content = f.buildFeatureContent(info)
+
+	return info, nil
+}
+
+// fetchFeatureIndex returns the feature index, building it from the TypeScript-Website
+// release notes when it is not cached yet
+func (f *TypeScriptFetcher) fetchFeatureIndex() ([]TypeScriptFeature, error) {
+	indexPath := f.getCache().GetFilePath("typescript", "features.json")
+
+	var index []TypeScriptFeature
+	if loaded, err := f.getCache().Load(indexPath, &index); err == nil && loaded && len(index) > 0 {
+		fmt.Fprintf(os.Stderr, "Loaded TypeScript feature index from cache\n")
+		return index, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Building TypeScript feature index from the handbook release notes...\n")
+
+	body, err := f.get(typeScriptReleaseNotesAPI, "application/vnd.github.v3+json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list TypeScript release notes: %w", err)
+	}
+
+	var files []struct {
+		Name        string `json:"name"`
+		DownloadURL string `json:"download_url"`
+	}
+	if err := json.Unmarshal(body, &files); err != nil {
+		return nil, fmt.Errorf("failed to parse release notes listing: %w", err)
+	}
+
+	for _, file := range files {
+		m := typeScriptReleaseNotesFile.FindStringSubmatch(file.Name)
+		if m == nil || file.DownloadURL == "" {
+			continue
+		}
+
+		notes, err := f.get(file.DownloadURL, "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s: %v\n", file.Name, err)
+			continue
+		}
+		index = append(index, parseTypeScriptReleaseNotes(m[1], string(notes))...)
+	}
+
+	if len(index) == 0 {
+		return nil, fmt.Errorf("no TypeScript features found in release notes")
+	}
+
+	if err := f.getCache().Save(indexPath, index); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache feature index: %v\n", err)
+	}
+
+	return index, nil
+}
+
+func (f *TypeScriptFetcher) get(url, accept string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// parseTypeScriptReleaseNotes extracts feature headings from a release notes markdown file
+func parseTypeScriptReleaseNotes(version, notes string) []TypeScriptFeature {
+	pageURL := fmt.Sprintf("%s/typescript-%s.html", typeScriptReleaseNotesURL, strings.ReplaceAll(version, ".", "-"))
+
+	// Strip frontmatter, picking up the canonical permalink when present
+	if strings.HasPrefix(notes, "---") {
+		if parts := strings.SplitN(notes, "---", 3); len(parts) == 3 {
+			var meta struct {
+				Permalink string `yaml:"permalink"`
+			}
+			if err := yaml.Unmarshal([]byte(parts[1]), &meta); err == nil && meta.Permalink != "" {
+				pageURL = "https://www.typescriptlang.org" + meta.Permalink
+			}
+			notes = parts[2]
+		}
+	}
+
+	var features []TypeScriptFeature
+	inCode := false
+	for _, line := range strings.Split(notes, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		var heading string
+		switch {
+		case strings.HasPrefix(trimmed, "## "):
+			heading = trimmed[3:]
+		case strings.HasPrefix(trimmed, "### "):
+			heading = trimmed[4:]
+		default:
+			continue
+		}
+
+		heading = cleanMarkdownHeading(heading)
+		if heading == "" {
+			continue
+		}
+
+		features = append(features, TypeScriptFeature{
+			Name:    heading,
+			Version: version,
+			URL:     fmt.Sprintf("%s#%s", pageURL, headingAnchor(heading)),
+		})
+	}
+
+	return features
+}
+
+var markdownLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+// cleanMarkdownHeading removes inline markdown from a heading
+func cleanMarkdownHeading(heading string) string {
+	heading = markdownLinkPattern.ReplaceAllString(heading, "$1")
+	heading = strings.ReplaceAll(heading, "`", "")
+	heading = strings.ReplaceAll(heading, "*", "")
+	return strings.TrimSpace(heading)
+}
+
+// headingAnchor builds the GitHub-style anchor slug the handbook uses for headings
+func headingAnchor(heading string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			slug.WriteRune(r)
+		case r == ' ':
+			slug.WriteRune('-')
+		}
+	}
+	return slug.String()
+}
+
+// matchTypeScriptFeatures returns index entries whose names contain every query word
+// (as a word prefix), best and earliest matches first
+func matchTypeScriptFeatures(index []TypeScriptFeature, query string) []TypeScriptFeature {
+	queryWords := strings.Fields(strings.ToLower(cleanMarkdownHeading(query)))
+	if len(queryWords) == 0 {
+		return nil
+	}
+
+	type scored struct {
+		feature TypeScriptFeature
+		extra   int
+	}
+
+	var results []scored
+	for _, feature := range index {
+		nameWords := strings.Fields(strings.ToLower(feature.Name))
+
+		matched := true
+		for _, qw := range queryWords {
+			found := false
+			// Drop a plural "s" so abbreviations like "params" still match "parameters"
+			stem := qw
+			if len(stem) > 3 {
+				stem = strings.TrimSuffix(stem, "s")
+			}
+			for _, nw := range nameWords {
+				if strings.HasPrefix(nw, stem) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			results = append(results, scored{feature: feature, extra: len(nameWords) - len(queryWords)})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].extra != results[j].extra {
+			return results[i].extra < results[j].extra
+		}
+		return typeScriptVersionLess(results[i].feature.Version, results[j].feature.Version)
+	})
+
+	const maxMatches = 10
+	matches := make([]TypeScriptFeature, 0, maxMatches)
+	for _, r := range results {
+		if len(matches) == maxMatches {
+			break
+		}
+		matches = append(matches, r.feature)
+	}
+
+	return matches
+}
+
+// typeScriptVersionLess compares "major.minor" versions numerically
+func typeScriptVersionLess(a, b string) bool {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		an, _ := strconv.Atoi(aParts[i])
+		bn, _ := strconv.Atoi(bParts[i])
+		if an != bn {
+			return an < bn
+		}
+	}
+	return len(aParts) < len(bParts)
+}
+
+func (f *TypeScriptFetcher) buildFeatureContent(info *TypeScriptFeatureInfo) string {
+	var content strings.Builder
+
+	best := info.Matches[0]
+
+	fmt.Fprintf(&content, "# TypeScript Feature: %s\n\n", best.Name)
+	fmt.Fprintf(&content, "**Introduced in:** TypeScript %s\n\n", best.Version)
+	fmt.Fprintf(&content, "**Handbook:** [%s](%s)\n\n", best.Name, best.URL)
+
+	content.WriteString("## Installation\n\n")
+	content.WriteString("```bash\n")
+	fmt.Fprintf(&content, "npm install --save-dev typescript@\">=%s\"\n", best.Version)
+	content.WriteString("```\n\n")
+
+	if len(info.Matches) > 1 {
+		content.WriteString("## Related Features\n\n")
+		content.WriteString("| Feature | Version |\n")
+		content.WriteString("|---------|---------|\n")
+		for _, m := range info.Matches[1:] {
+			fmt.Fprintf(&content, "| [%s](%s) | %s |\n", m.Name, m.URL, m.Version)
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Documentation\n\n")
+	fmt.Fprintf(&content, "- [TypeScript %s Release Notes](%s)\n", best.Version, strings.SplitN(best.URL, "#", 2)[0])
+	content.WriteString("- [TypeScript Release Notes](https://www.typescriptlang.org/docs/handbook/release-notes/overview.html)\n")
+
+	return content.String()
+}
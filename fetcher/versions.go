@@ -0,0 +1,260 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/semver"
+)
+
+// VersionsFetcher lists the published versions of a package from its registry.
+// Version lists change with every release, so they are always fetched live.
+type VersionsFetcher struct {
+	*BaseFetcher
+}
+
+func NewVersionsFetcher(cacheDir string) *VersionsFetcher {
+	return &VersionsFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+var errVersionsNotFound = errors.New("not found")
+
+// ListVersions returns the versions of a package, newest first
+func (f *VersionsFetcher) ListVersions(ecosystem, name string) ([]string, error) {
+	return shareFetch(f.flights, flightKey("ListVersions", ecosystem, name), "", func() ([]string, error) {
+		return f.listVersions(ecosystem, name)
+	})
+}
+
+func (f *VersionsFetcher) listVersions(ecosystem, name string) ([]string, error) {
+	canonical, err := NormalizeEcosystem(ecosystem)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("package name is required")
+	}
+
+	var versions []string
+	switch canonical {
+	case "npm":
+		versions, err = f.npmVersions(name)
+	case "python":
+		versions, err = f.pypiVersions(name)
+	case "rust":
+		versions, err = f.crateVersions(name)
+	case "go":
+		versions, err = f.goModuleVersions(name)
+	case "ruby":
+		versions, err = f.gemVersions(name)
+	case "hex":
+		versions, err = f.hexVersions(name)
+	case "cocoapods":
+		versions, err = f.podVersions(name)
+	case "conan":
+		versions, err = f.conanVersions(name)
+	}
+	if errors.Is(err, errVersionsNotFound) {
+		return nil, fmt.Errorf("%s package %s not found", canonical, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions: %w", err)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare(versions[i], versions[j]) > 0
+	})
+	return versions, nil
+}
+
+func (f *VersionsFetcher) npmVersions(name string) ([]string, error) {
+	var data struct {
+		Versions map[string]json.RawMessage `json:"versions"`
+	}
+	// The abbreviated metadata document is much smaller than the full packument
+	if err := f.getJSON(fmt.Sprintf("https://registry.npmjs.org/%s", name), "application/vnd.npm.install-v1+json", &data); err != nil {
+		return nil, err
+	}
+	return mapKeys(data.Versions), nil
+}
+
+func (f *VersionsFetcher) pypiVersions(name string) ([]string, error) {
+	var data struct {
+		Releases map[string][]json.RawMessage `json:"releases"`
+	}
+	if err := f.getJSON(fmt.Sprintf("https://pypi.org/pypi/%s/json", name), "application/json", &data); err != nil {
+		return nil, err
+	}
+	var versions []string
+	for v, files := range data.Releases {
+		// Releases without files were never actually published
+		if len(files) > 0 {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+func (f *VersionsFetcher) crateVersions(name string) ([]string, error) {
+	var data struct {
+		Versions []struct {
+			Num    string `json:"num"`
+			Yanked bool   `json:"yanked"`
+		} `json:"versions"`
+	}
+	if err := f.getJSON(fmt.Sprintf("https://crates.io/api/v1/crates/%s/versions", name), "application/json", &data); err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, v := range data.Versions {
+		if !v.Yanked {
+			versions = append(versions, v.Num)
+		}
+	}
+	return versions, nil
+}
+
+func (f *VersionsFetcher) goModuleVersions(module string) ([]string, error) {
+	body, err := f.get(fmt.Sprintf("https://proxy.golang.org/%s/@v/list", escapeModulePath(module)), "text/plain")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(body)), nil
+}
+
+// escapeModulePath applies the module proxy case encoding ("Azure" -> "!azure")
+func escapeModulePath(module string) string {
+	var b strings.Builder
+	for _, r := range module {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('!')
+			b.WriteRune(r + ('a' - 'A'))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (f *VersionsFetcher) gemVersions(name string) ([]string, error) {
+	var data []struct {
+		Number string `json:"number"`
+	}
+	if err := f.getJSON(fmt.Sprintf("https://rubygems.org/api/v1/versions/%s.json", name), "application/json", &data); err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(data))
+	for _, v := range data {
+		versions = append(versions, v.Number)
+	}
+	return versions, nil
+}
+
+func (f *VersionsFetcher) hexVersions(name string) ([]string, error) {
+	var data struct {
+		Releases []struct {
+			Version string `json:"version"`
+		} `json:"releases"`
+	}
+	if err := f.getJSON(fmt.Sprintf("https://hex.pm/api/packages/%s", name), "application/json", &data); err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(data.Releases))
+	for _, r := range data.Releases {
+		versions = append(versions, r.Version)
+	}
+	return versions, nil
+}
+
+func (f *VersionsFetcher) podVersions(name string) ([]string, error) {
+	rootName := strings.SplitN(name, "/", 2)[0]
+	shard := cocoaPodsShard(rootName)
+
+	body, err := f.get(fmt.Sprintf("%s/all_pods_versions_%s.txt", cocoaPodsCDNURL, strings.Join(shard, "_")), "text/plain")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(body), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "/")
+		if len(fields) > 1 && fields[0] == rootName {
+			return fields[1:], nil
+		}
+	}
+	return nil, errVersionsNotFound
+}
+
+func (f *VersionsFetcher) conanVersions(name string) ([]string, error) {
+	body, err := f.get(fmt.Sprintf("%s/%s/config.yml", conanCenterIndexURL, strings.ToLower(name)), "text/plain")
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Versions map[string]interface{} `yaml:"versions"`
+	}
+	if err := yaml.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse recipe config: %w", err)
+	}
+	return mapKeys(config.Versions), nil
+}
+
+func (f *VersionsFetcher) getJSON(url, accept string, v interface{}) error {
+	body, err := f.get(url, accept)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse registry response: %w", err)
+	}
+	return nil
+}
+
+func (f *VersionsFetcher) get(rawURL, accept string) ([]byte, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Accept", accept)
+
+	resp, err := f.getClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// The Go module proxy answers 404 or 410 for unknown modules
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, errVersionsNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		u, _ := url.Parse(rawURL)
+		return nil, fmt.Errorf("%s returned status %d", u.Host, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return body, nil
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
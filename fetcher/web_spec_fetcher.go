@@ -0,0 +1,430 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/incu6us/open-context/markdown"
+)
+
+const (
+	cssDraftsURL = "https://drafts.csswg.org"
+
+	// maxWebSpecChars keeps a section, such as HTML's parsing algorithm,
+	// to a reference-sized answer
+	maxWebSpecChars = 40000
+)
+
+// webSpec is a standard get_web_spec serves. WHATWG's HTML standard is
+// split into one page per chapter; the others are single pages.
+type webSpec struct {
+	title     string
+	url       string
+	multipage bool
+	license   string
+}
+
+const (
+	whatwgLicense = "© WHATWG, [CC BY 4.0](https://creativecommons.org/licenses/by/4.0/)"
+	w3cLicense    = "© W3C, [W3C Software and Document License](https://www.w3.org/copyright/software-license/)"
+)
+
+var webSpecs = map[string]webSpec{
+	"html":      {"HTML Living Standard", "https://html.spec.whatwg.org/multipage/", true, whatwgLicense},
+	"dom":       {"DOM Living Standard", "https://dom.spec.whatwg.org/", false, whatwgLicense},
+	"fetch":     {"Fetch Living Standard", "https://fetch.spec.whatwg.org/", false, whatwgLicense},
+	"url":       {"URL Living Standard", "https://url.spec.whatwg.org/", false, whatwgLicense},
+	"infra":     {"Infra Living Standard", "https://infra.spec.whatwg.org/", false, whatwgLicense},
+	"streams":   {"Streams Living Standard", "https://streams.spec.whatwg.org/", false, whatwgLicense},
+	"encoding":  {"Encoding Living Standard", "https://encoding.spec.whatwg.org/", false, whatwgLicense},
+	"mimesniff": {"MIME Sniffing Living Standard", "https://mimesniff.spec.whatwg.org/", false, whatwgLicense},
+	"xhr":       {"XMLHttpRequest Living Standard", "https://xhr.spec.whatwg.org/", false, whatwgLicense},
+	"webidl":    {"Web IDL Living Standard", "https://webidl.spec.whatwg.org/", false, whatwgLicense},
+	"storage":   {"Storage Living Standard", "https://storage.spec.whatwg.org/", false, whatwgLicense},
+	"console":   {"Console Living Standard", "https://console.spec.whatwg.org/", false, whatwgLicense},
+}
+
+// cssSpecPattern matches the short names of CSS Working Group drafts, such
+// as css-grid, css-grid-2, cssom-view, selectors-4, and mediaqueries-5
+var cssSpecPattern = regexp.MustCompile(`^(?:css[a-z0-9-]*|selectors(?:-\d+)?|mediaqueries(?:-\d+)?|compositing(?:-\d+)?|filter-effects(?:-\d+)?)$`)
+
+// webSpecTOCEntry is an entry of a standard's table of contents
+type webSpecTOCEntry struct {
+	number string
+	title  string
+	// page is the file of a multipage standard the section is on, and
+	// empty for single-page standards
+	page string
+	id   string
+}
+
+// WebSpecInfo is a section, or the contents, of a web standard
+type WebSpecInfo struct {
+	Spec    string
+	Title   string
+	URL     string
+	Content string
+}
+
+type WebSpecFetcher struct {
+	*BaseFetcher
+}
+
+func NewWebSpecFetcher(cacheDir string) *WebSpecFetcher {
+	return &WebSpecFetcher{
+		BaseFetcher: NewBaseFetcher(cacheDir),
+	}
+}
+
+// FetchWebSpec fetches a section of a WHATWG standard (html, dom, fetch,
+// url, ...) or CSS Working Group draft (css-grid, selectors-4) as markdown.
+// The section is an anchor ("the-form-element", "concept-url-parser"), a
+// section number ("4.10.3"), or a title; an anchor that names a definition
+// returns the section that defines it. Without a section, the table of
+// contents is returned.
+func (f *WebSpecFetcher) FetchWebSpec(spec, section string) (*WebSpecInfo, error) {
+	name := strings.ToLower(strings.TrimSpace(spec))
+	s, ok := webSpecs[name]
+	if !ok {
+		if !cssSpecPattern.MatchString(name) {
+			return nil, fmt.Errorf("unsupported spec %q (supported: %s, or a CSS draft such as css-grid or selectors-4)", spec, strings.Join(webSpecNames(), ", "))
+		}
+		s = webSpec{url: fmt.Sprintf("%s/%s/", cssDraftsURL, name), license: w3cLicense}
+	}
+	section = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(section), "#"), "§")
+	return shareFetch(f.flights, flightKey("FetchWebSpec", name, section), "", func() (*WebSpecInfo, error) {
+		return f.fetchWebSpec(name, s, strings.TrimSpace(section))
+	})
+}
+
+func webSpecNames() []string {
+	names := make([]string, 0, len(webSpecs))
+	for name := range webSpecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *WebSpecFetcher) fetchWebSpec(name string, spec webSpec, section string) (*WebSpecInfo, error) {
+	index, err := f.fetchPage(name, spec.url)
+	if err != nil {
+		return nil, err
+	}
+	if spec.title == "" {
+		spec.title = webSpecTitle(index)
+	}
+	toc := parseWebSpecTOC(index, spec.multipage)
+	degraded := checkShape("web-spec", spec.url, shapeCheck{"toc", len(toc) > 0})
+	expireDegraded(f.getCache(), f.pagePath(name, spec.url), degraded)
+
+	info := &WebSpecInfo{Spec: name, Title: spec.title, URL: spec.url}
+	if section == "" {
+		info.Content = buildWebSpecContents(spec, toc)
+		return info, nil
+	}
+
+	// Sections by anchor, number, or title first, then definitions by
+	// anchor, and last sections whose title contains the query
+	page, id, found := "", section, false
+	if entry := matchWebSpecTOC(toc, section, false); entry != nil {
+		page, id, found = entry.page, entry.id, true
+	} else if spec.multipage {
+		page = f.multipageFragment(name, spec.url, id)
+		found = page != ""
+	} else {
+		found = findNode(index, func(n *html.Node) bool { return hasAttr(n, "id", id) }) != nil
+	}
+	if !found {
+		entry := matchWebSpecTOC(toc, section, true)
+		if entry == nil {
+			return nil, fmt.Errorf("section %q not found in %s", section, spec.title)
+		}
+		page, id = entry.page, entry.id
+	}
+
+	doc := index
+	pageURL := spec.url
+	if page != "" {
+		pageURL = spec.url + page
+		if doc, err = f.fetchPage(name, pageURL); err != nil {
+			return nil, err
+		}
+	}
+
+	target := findNode(doc, func(n *html.Node) bool { return hasAttr(n, "id", id) })
+	if target == nil {
+		return nil, fmt.Errorf("section %q not found in %s", section, spec.title)
+	}
+	heading := webSpecHeading(target)
+	if heading == nil {
+		return nil, fmt.Errorf("no section encloses %q in %s", section, spec.title)
+	}
+
+	headingID := attrValue(heading, "id")
+	info.Title = fmt.Sprintf("%s: %s", spec.title, collapseSpace(getText(heading)))
+	info.URL = pageURL + "#" + headingID
+
+	body, err := webSpecSectionMarkdown(heading, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# %s\n\n", info.Title)
+	fmt.Fprintf(&content, "**Section:** [%s](%s)\n\n", collapseSpace(getText(heading)), info.URL)
+	fmt.Fprintf(&content, "**Standard:** [%s](%s)\n\n", spec.title, spec.url)
+	if id != headingID {
+		fmt.Fprintf(&content, "[`%s`](%s#%s) is defined in this section.\n\n", id, pageURL, id)
+	}
+	content.WriteString(strings.TrimSpace(truncateMarkdown(body, maxWebSpecChars, "The section is truncated; see the standard for the rest.")))
+	content.WriteString("\n\n")
+	fmt.Fprintf(&content, "_From the %s, %s._\n", spec.title, spec.license)
+	info.Content = content.String()
+	return info, nil
+}
+
+// fetchPage returns the parsed page at pageURL, cached under web/specs
+func (f *WebSpecFetcher) fetchPage(name, pageURL string) (*html.Node, error) {
+	body, found, err := f.fetchCached(f.pagePath(name, pageURL), pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("failed to fetch %s: not found", pageURL)
+	}
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pageURL, err)
+	}
+	return doc, nil
+}
+
+// pagePath returns the cache path of a page of a standard
+func (f *WebSpecFetcher) pagePath(name, pageURL string) string {
+	file := path.Base(pageURL)
+	if strings.HasSuffix(pageURL, "/") {
+		file = "index.html"
+	}
+	return f.getCache().GetFilePath("web", "specs", name, file)
+}
+
+// multipageFragment looks up the page of the HTML standard an anchor is
+// on, for anchors that are not sections, or returns ""
+func (f *WebSpecFetcher) multipageFragment(name, specURL, id string) string {
+	body, found, err := f.fetchCached(f.getCache().GetFilePath("web", "specs", name, "fragment-links.json"), specURL+"fragment-links.json")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch the fragment index of %s: %v\n", specURL, err)
+		return ""
+	}
+	if !found {
+		return ""
+	}
+	var fragments map[string]string
+	if err := json.Unmarshal(body, &fragments); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to parse the fragment index of %s: %v\n", specURL, err)
+		return ""
+	}
+	if page := fragments[id]; page != "" {
+		return page + ".html"
+	}
+	return ""
+}
+
+func webSpecTitle(doc *html.Node) string {
+	if h1 := findElement(doc, "h1"); h1 != nil {
+		return collapseSpace(getText(h1))
+	}
+	if title := findElement(doc, "title"); title != nil {
+		return collapseSpace(getText(title))
+	}
+	return ""
+}
+
+// parseWebSpecTOC reads the table of contents, whose links carry each
+// section's number in a "secno" span
+func parseWebSpecTOC(doc *html.Node, multipage bool) []webSpecTOCEntry {
+	toc := findNode(doc, func(n *html.Node) bool {
+		return (n.Data == "ol" || n.Data == "ul") && hasClassToken(n, "toc")
+	})
+	if toc == nil {
+		return nil
+	}
+
+	var entries []webSpecTOCEntry
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			href := attrValue(n, "href")
+			page, id, _ := strings.Cut(href, "#")
+			if id == "" {
+				return
+			}
+			if !multipage {
+				page = ""
+			}
+			entry := webSpecTOCEntry{page: page, id: id}
+			var title strings.Builder
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && hasClassToken(c, "secno") {
+					entry.number = strings.TrimSuffix(collapseSpace(getText(c)), ".")
+					continue
+				}
+				title.WriteString(getText(c))
+			}
+			entry.title = collapseSpace(title.String())
+			entries = append(entries, entry)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(toc)
+	return entries
+}
+
+// matchWebSpecTOC finds a section by anchor, number, or title, or with
+// partial, by a title containing the query
+func matchWebSpecTOC(toc []webSpecTOCEntry, section string, partial bool) *webSpecTOCEntry {
+	if partial {
+		lower := strings.ToLower(section)
+		for i, e := range toc {
+			if strings.Contains(strings.ToLower(e.title), lower) {
+				return &toc[i]
+			}
+		}
+		return nil
+	}
+	number := strings.TrimSuffix(section, ".")
+	for i, e := range toc {
+		if e.id == section || e.number != "" && e.number == number {
+			return &toc[i]
+		}
+	}
+	for i, e := range toc {
+		if strings.EqualFold(e.title, section) {
+			return &toc[i]
+		}
+	}
+	return nil
+}
+
+func buildWebSpecContents(spec webSpec, toc []webSpecTOCEntry) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "# %s\n\n", spec.title)
+	fmt.Fprintf(&content, "**Standard:** [%s](%s)\n\n", spec.title, spec.url)
+	content.WriteString("## Contents\n\n")
+	if len(toc) == 0 {
+		content.WriteString("No table of contents found; ask for a section by its anchor.\n")
+		return content.String()
+	}
+	content.WriteString("Ask for a section by its number, title, or anchor.\n\n")
+	for _, e := range toc {
+		depth := strings.Count(e.number, ".")
+		if depth > 1 {
+			continue
+		}
+		label := e.title
+		if e.number != "" {
+			label = e.number + " " + e.title
+		}
+		fmt.Fprintf(&content, "%s- [%s](%s%s#%s) (`%s`)\n", strings.Repeat("  ", depth), label, spec.url, e.page, e.id, e.id)
+	}
+	return content.String()
+}
+
+// webSpecHeading returns the heading of the section a node is in: the node
+// itself, the heading it is part of, or the closest heading before it
+func webSpecHeading(n *html.Node) *html.Node {
+	for a := n; a != nil; a = a.Parent {
+		if headingLevel(a) > 0 {
+			return a
+		}
+	}
+	for cur := n; cur != nil; cur = cur.Parent {
+		for s := cur.PrevSibling; s != nil; s = s.PrevSibling {
+			if headingLevel(s) > 0 {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// headingLevel is 2 for an h2 and so on, and 0 for other nodes
+func headingLevel(n *html.Node) int {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return int(n.Data[1] - '0')
+	}
+	return 0
+}
+
+// webSpecSectionMarkdown converts a heading and what follows it, up to the
+// next heading of the same or a higher level, to markdown. The section's
+// heading becomes a level 2 heading, and subsections follow it.
+func webSpecSectionMarkdown(heading *html.Node, pageURL string) (string, error) {
+	level := headingLevel(heading)
+	nodes := []*html.Node{heading}
+	// Some builds wrap each section in a <section>
+	if p := heading.Parent; p != nil && p.DataAtom == atom.Section {
+		nodes = []*html.Node{p}
+	} else {
+		for s := heading.NextSibling; s != nil; s = s.NextSibling {
+			if l := headingLevel(s); l > 0 && l <= level {
+				break
+			}
+			nodes = append(nodes, s)
+		}
+	}
+
+	// Browser support boxes, test listings, and permalinks
+	noise := func(e *html.Node) bool {
+		return hasClassToken(e, "self-link") || hasClassToken(e, "mdn-anno") || hasClassToken(e, "wpt-tests-block") ||
+			hasClassToken(e, "annotation") || hasClassToken(e, "dfn-panel")
+	}
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		if n.Type == html.ElementNode && noise(n) {
+			continue
+		}
+		removeElements(n, noise)
+		shiftHeadings(n, 2-level)
+		if err := html.Render(&buf, n); err != nil {
+			return "", fmt.Errorf("failed to render section: %w", err)
+		}
+	}
+	return markdown.FromHTML(buf.String(), pageURL), nil
+}
+
+// shiftHeadings moves the headings under n by delta levels, within h1 to h6
+func shiftHeadings(n *html.Node, delta int) {
+	if l := headingLevel(n); l > 0 {
+		l = min(max(l+delta, 1), 6)
+		n.Data = fmt.Sprintf("h%d", l)
+		n.DataAtom = atom.Lookup([]byte(n.Data))
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		shiftHeadings(c, delta)
+	}
+}
+
+func attrValue(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
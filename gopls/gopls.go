@@ -0,0 +1,244 @@
+// Package gopls answers questions about symbols in a local Go workspace by
+// shelling out to the gopls command, falling back to `go doc` when gopls is
+// not installed.
+package gopls
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// commandTimeout bounds a single gopls or go invocation; the first call in a
+	// workspace may have to load every package
+	commandTimeout = 60 * time.Second
+)
+
+// Symbol is the hover-style documentation of a symbol in the workspace
+type Symbol struct {
+	Name string
+	// Location is "file:line:column", relative to the workspace when possible
+	Location string
+	// Documentation is markdown: the declaration in a go code block followed by its doc comment
+	Documentation string
+}
+
+// Client runs gopls against a single workspace directory
+type Client struct {
+	workspace string
+}
+
+// NewClient creates a client for the Go workspace (module or go.work root) at dir
+func NewClient(dir string) *Client {
+	return &Client{workspace: dir}
+}
+
+// Workspace returns the workspace directory
+func (c *Client) Workspace() string {
+	return c.workspace
+}
+
+// Hover returns the documentation of the symbol at a 1-based line and column of a file
+func (c *Client) Hover(file string, line, column int) (*Symbol, error) {
+	if _, err := exec.LookPath("gopls"); err != nil {
+		return nil, fmt.Errorf("gopls is not installed (go install golang.org/x/tools/gopls@latest)")
+	}
+
+	position := fmt.Sprintf("%s:%d:%d", c.absPath(file), line, column)
+	out, err := c.run("gopls", "definition", "-json", position)
+	if err != nil {
+		return nil, err
+	}
+
+	var def struct {
+		Span struct {
+			URI   string `json:"uri"`
+			Start struct {
+				Line   int `json:"line"`
+				Column int `json:"column"`
+			} `json:"start"`
+		} `json:"span"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(out, &def); err != nil {
+		return nil, fmt.Errorf("failed to parse gopls output: %w", err)
+	}
+
+	return &Symbol{
+		Name:          declarationName(def.Description),
+		Location:      fmt.Sprintf("%s:%d:%d", c.relPath(uriPath(def.Span.URI)), def.Span.Start.Line, def.Span.Start.Column),
+		Documentation: strings.TrimSpace(def.Description),
+	}, nil
+}
+
+// Lookup finds a symbol by name ("Client", "Client.Hover", or "pkg.Func") and
+// returns its documentation
+func (c *Client) Lookup(name string) (*Symbol, error) {
+	if _, err := exec.LookPath("gopls"); err != nil {
+		return c.goDoc(name)
+	}
+
+	out, err := c.run("gopls", "workspace_symbol", "-matcher=fastfuzzy", name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, line, column, ok := bestSymbolMatch(string(out), name)
+	if !ok {
+		return nil, fmt.Errorf("symbol %s not found in workspace", name)
+	}
+
+	// workspace_symbol points at the declaration; hover there for its docs
+	return c.Hover(file, line, column)
+}
+
+// goDoc answers name lookups with `go doc`, which resolves symbols of the
+// packages in the workspace's main module
+func (c *Client) goDoc(name string) (*Symbol, error) {
+	out, err := c.run("go", "doc", "-short=false", name)
+	if err != nil {
+		return nil, fmt.Errorf("symbol %s not found (gopls is not installed, go doc failed: %w)", name, err)
+	}
+
+	doc := strings.TrimSpace(string(out))
+	decl, comment, _ := strings.Cut(doc, "\n\n")
+	return &Symbol{
+		Name:          name,
+		Documentation: strings.TrimSpace(fmt.Sprintf("```go\n%s\n```\n\n%s", decl, comment)),
+	}, nil
+}
+
+func (c *Client) run(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = c.workspace
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s timed out after %v", name, commandTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", name, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+func (c *Client) absPath(file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(c.workspace, file)
+}
+
+func (c *Client) relPath(file string) string {
+	if rel, err := filepath.Rel(c.workspace, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return file
+}
+
+// bestSymbolMatch picks a result from `gopls workspace_symbol` output, whose
+// lines look like "/abs/file.go:12:6-12 Client.Hover Method". Exact name
+// matches win over fuzzy ones.
+func bestSymbolMatch(output, name string) (string, int, int, bool) {
+	type match struct {
+		file         string
+		line, column int
+	}
+	var first *match
+
+	for _, entry := range strings.Split(output, "\n") {
+		fields := strings.Fields(entry)
+		if len(fields) < 2 {
+			continue
+		}
+
+		file, line, column, ok := parsePosition(fields[0])
+		if !ok {
+			continue
+		}
+		m := &match{file, line, column}
+
+		symbol := fields[1]
+		if symbol == name || strings.HasSuffix(symbol, "."+name) || strings.HasSuffix(name, "."+symbol) {
+			return m.file, m.line, m.column, true
+		}
+		if first == nil {
+			first = m
+		}
+	}
+
+	if first == nil {
+		return "", 0, 0, false
+	}
+	return first.file, first.line, first.column, true
+}
+
+var positionRe = regexp.MustCompile(`^(.+):(\d+):(\d+)(?:-[\d:]+)?$`)
+
+// parsePosition splits "file:line:col", optionally followed by an end position ("-col" or "-line:col")
+func parsePosition(pos string) (string, int, int, bool) {
+	m := positionRe.FindStringSubmatch(pos)
+	if m == nil {
+		return "", 0, 0, false
+	}
+	line, _ := strconv.Atoi(m[2])
+	column, _ := strconv.Atoi(m[3])
+	return m[1], line, column, true
+}
+
+func uriPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// declarationName extracts the declared identifier from a hover's go code block
+func declarationName(description string) string {
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "```") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "func":
+			// Methods: func (r *T) Name(...)
+			rest := strings.TrimPrefix(line, "func ")
+			if strings.HasPrefix(rest, "(") {
+				if idx := strings.Index(rest, ")"); idx >= 0 {
+					rest = strings.TrimSpace(rest[idx+1:])
+				}
+			}
+			return strings.SplitN(rest, "(", 2)[0]
+		case "type", "var", "const", "field", "package":
+			if len(fields) > 1 {
+				// Drop type parameters: "Set[T"
+				return strings.SplitN(fields[1], "[", 2)[0]
+			}
+		}
+		return fields[0]
+	}
+	return ""
+}
@@ -0,0 +1,256 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// The tests below call the server with grpc-go, decoding its messages with
+// the protobuf runtime against the schema of opencontext.proto, so the hand
+// written framing and encoding are checked against a real client.
+
+type fakeBackend struct {
+	doc string
+}
+
+func (b *fakeBackend) Search(req *SearchRequest) (*SearchResponse, error) {
+	return &SearchResponse{Results: []SearchResult{
+		{ID: "go/context", Title: "Context", Documentation: req.Language, Score: 2.5},
+		{ID: "go/errors", Title: "Errors"},
+	}}, nil
+}
+
+func (b *fakeBackend) GetDoc(req *GetDocRequest) (string, error) {
+	if req.ID != "big" {
+		return "", Errorf(NotFound, "topic %s not found", req.ID)
+	}
+	return b.doc, nil
+}
+
+func (b *fakeBackend) FetchPackage(req *FetchPackageRequest) (*Package, error) {
+	return &Package{Ecosystem: req.Ecosystem, Name: req.Name, Version: "4.18.2", License: "MIT"}, nil
+}
+
+func (b *fakeBackend) ListVersions(req *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return &ListVersionsResponse{Versions: []string{"2.0.0", "", "1.0.0"}}, nil
+}
+
+// schema returns the messages of opencontext.proto by name
+func schema(t *testing.T) map[string]protoreflect.MessageDescriptor {
+	t.Helper()
+
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	double := descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum()
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	field := func(name string, number int32, typ *descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{Name: proto.String(name), Number: proto.Int32(number), Type: typ, Label: optional}
+	}
+	message := func(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+	}
+
+	results := field("results", 1, msg)
+	results.Label, results.TypeName = repeated, proto.String(".opencontext.v1.SearchResult")
+	versions := field("versions", 1, str)
+	versions.Label = repeated
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("opencontext.proto"),
+		Package: proto.String("opencontext.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			message("SearchRequest", field("query", 1, str), field("language", 2, str)),
+			message("SearchResult", field("id", 1, str), field("title", 2, str), field("description", 3, str),
+				field("documentation", 4, str), field("score", 5, double)),
+			message("SearchResponse", results),
+			message("GetDocRequest", field("id", 1, str), field("language", 2, str), field("topic", 3, str)),
+			message("DocChunk", field("content", 1, str)),
+			message("FetchPackageRequest", field("ecosystem", 1, str), field("name", 2, str), field("version", 3, str)),
+			message("Package", field("ecosystem", 1, str), field("name", 2, str), field("version", 3, str),
+				field("description", 4, str), field("license", 5, str), field("repository", 6, str), field("content", 7, str)),
+			message("ListVersionsRequest", field("ecosystem", 1, str), field("name", 2, str)),
+			message("ListVersionsResponse", versions),
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := make(map[string]protoreflect.MessageDescriptor)
+	for i := 0; i < file.Messages().Len(); i++ {
+		m := file.Messages().Get(i)
+		messages[string(m.Name())] = m
+	}
+	return messages
+}
+
+// newMessage returns a message of the schema with the given string fields set
+func newMessage(desc protoreflect.MessageDescriptor, fields map[string]string) *dynamicpb.Message {
+	m := dynamicpb.NewMessage(desc)
+	for name, value := range fields {
+		m.Set(desc.Fields().ByName(protoreflect.Name(name)), protoreflect.ValueOfString(value))
+	}
+	return m
+}
+
+func getString(m *dynamicpb.Message, name string) string {
+	return m.Get(m.Descriptor().Fields().ByName(protoreflect.Name(name))).String()
+}
+
+// dial starts a server for backend and returns a grpc-go connection to it
+func dial(t *testing.T, backend Backend) *grpc.ClientConn {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newServer(backend)
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	conn, err := grpc.NewClient("passthrough:///"+l.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestInteropUnary(t *testing.T) {
+	messages := schema(t)
+	conn := dial(t, &fakeBackend{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	search := dynamicpb.NewMessage(messages["SearchResponse"])
+	req := newMessage(messages["SearchRequest"], map[string]string{"query": "context", "language": "go"})
+	if err := conn.Invoke(ctx, servicePath+"Search", req, search); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	results := search.Get(messages["SearchResponse"].Fields().ByName("results")).List()
+	if results.Len() != 2 {
+		t.Fatalf("Search returned %d results, want 2", results.Len())
+	}
+	first := results.Get(0).Message()
+	if id := first.Get(first.Descriptor().Fields().ByName("id")).String(); id != "go/context" {
+		t.Errorf("first result id = %q", id)
+	}
+	if doc := first.Get(first.Descriptor().Fields().ByName("documentation")).String(); doc != "go" {
+		t.Errorf("first result documentation = %q, want the request's language", doc)
+	}
+	if score := first.Get(first.Descriptor().Fields().ByName("score")).Float(); score != 2.5 {
+		t.Errorf("first result score = %v", score)
+	}
+
+	pkg := dynamicpb.NewMessage(messages["Package"])
+	req = newMessage(messages["FetchPackageRequest"], map[string]string{"ecosystem": "npm", "name": "express"})
+	if err := conn.Invoke(ctx, servicePath+"FetchPackage", req, pkg); err != nil {
+		t.Fatalf("FetchPackage: %v", err)
+	}
+	if getString(pkg, "name") != "express" || getString(pkg, "version") != "4.18.2" || getString(pkg, "license") != "MIT" {
+		t.Errorf("FetchPackage returned %v", pkg)
+	}
+
+	list := dynamicpb.NewMessage(messages["ListVersionsResponse"])
+	req = newMessage(messages["ListVersionsRequest"], map[string]string{"ecosystem": "npm", "name": "express"})
+	if err := conn.Invoke(ctx, servicePath+"ListVersions", req, list); err != nil {
+		t.Fatalf("ListVersions: %v", err)
+	}
+	versions := list.Get(messages["ListVersionsResponse"].Fields().ByName("versions")).List()
+	if versions.Len() != 3 || versions.Get(0).String() != "2.0.0" || versions.Get(1).String() != "" {
+		t.Errorf("ListVersions returned %v", list)
+	}
+}
+
+func TestInteropGetDocStream(t *testing.T) {
+	messages := schema(t)
+
+	// A line of two-byte characters longer than a chunk, after one byte, so
+	// the first chunk cannot end at a newline and its size falls inside a
+	// character
+	doc := "x" + strings.Repeat("é", docChunkSize) + "\nend\n"
+	conn := dial(t, &fakeBackend{doc: doc})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, servicePath+"GetDoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.SendMsg(newMessage(messages["GetDocRequest"], map[string]string{"id": "big"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got strings.Builder
+	chunks := 0
+	for {
+		chunk := dynamicpb.NewMessage(messages["DocChunk"])
+		err := stream.RecvMsg(chunk)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("chunk %d: %v", chunks, err)
+		}
+		got.WriteString(getString(chunk, "content"))
+		chunks++
+	}
+	if chunks < 2 {
+		t.Errorf("got %d chunks, want the document split", chunks)
+	}
+	if got.String() != doc {
+		t.Error("the chunks do not add up to the document")
+	}
+}
+
+func TestInteropStatus(t *testing.T) {
+	messages := schema(t)
+	conn := dial(t, &fakeBackend{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, servicePath+"GetDoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.SendMsg(newMessage(messages["GetDocRequest"], map[string]string{"id": "missing topic"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	err = stream.RecvMsg(dynamicpb.NewMessage(messages["DocChunk"]))
+	if status.Code(err) != codes.NotFound || status.Convert(err).Message() != "topic missing topic not found" {
+		t.Errorf("GetDoc of a missing topic = %v, want NotFound with the message decoded", err)
+	}
+
+	resp := dynamicpb.NewMessage(messages["ListVersionsResponse"])
+	req := newMessage(messages["ListVersionsRequest"], nil)
+	err = conn.Invoke(ctx, "/opencontext.v1.Documentation/Unknown", req, resp)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("unknown method = %v, want Unimplemented", err)
+	}
+}
@@ -0,0 +1,214 @@
+package grpcapi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Protocol buffer wire types used by the messages in opencontext.proto
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// SearchRequest mirrors opencontext.v1.SearchRequest
+type SearchRequest struct {
+	Query    string
+	Language string
+}
+
+func (m *SearchRequest) unmarshal(b []byte) error {
+	return decodeStrings(b, map[int]*string{1: &m.Query, 2: &m.Language})
+}
+
+// SearchResult mirrors opencontext.v1.SearchResult
+type SearchResult struct {
+	ID            string
+	Title         string
+	Description   string
+	Documentation string
+	Score         float64
+}
+
+func (m *SearchResult) marshal() []byte {
+	var e encoder
+	e.string(1, m.ID)
+	e.string(2, m.Title)
+	e.string(3, m.Description)
+	e.string(4, m.Documentation)
+	e.double(5, m.Score)
+	return e.buf
+}
+
+// SearchResponse mirrors opencontext.v1.SearchResponse
+type SearchResponse struct {
+	Results []SearchResult
+}
+
+func (m *SearchResponse) marshal() []byte {
+	var e encoder
+	for i := range m.Results {
+		e.message(1, m.Results[i].marshal())
+	}
+	return e.buf
+}
+
+// GetDocRequest mirrors opencontext.v1.GetDocRequest
+type GetDocRequest struct {
+	ID       string
+	Language string
+	Topic    string
+}
+
+func (m *GetDocRequest) unmarshal(b []byte) error {
+	return decodeStrings(b, map[int]*string{1: &m.ID, 2: &m.Language, 3: &m.Topic})
+}
+
+// DocChunk mirrors opencontext.v1.DocChunk
+type DocChunk struct {
+	Content string
+}
+
+func (m *DocChunk) marshal() []byte {
+	var e encoder
+	e.string(1, m.Content)
+	return e.buf
+}
+
+// FetchPackageRequest mirrors opencontext.v1.FetchPackageRequest
+type FetchPackageRequest struct {
+	Ecosystem string
+	Name      string
+	Version   string
+}
+
+func (m *FetchPackageRequest) unmarshal(b []byte) error {
+	return decodeStrings(b, map[int]*string{1: &m.Ecosystem, 2: &m.Name, 3: &m.Version})
+}
+
+// Package mirrors opencontext.v1.Package
+type Package struct {
+	Ecosystem   string
+	Name        string
+	Version     string
+	Description string
+	License     string
+	Repository  string
+	Content     string
+}
+
+func (m *Package) marshal() []byte {
+	var e encoder
+	e.string(1, m.Ecosystem)
+	e.string(2, m.Name)
+	e.string(3, m.Version)
+	e.string(4, m.Description)
+	e.string(5, m.License)
+	e.string(6, m.Repository)
+	e.string(7, m.Content)
+	return e.buf
+}
+
+// ListVersionsRequest mirrors opencontext.v1.ListVersionsRequest
+type ListVersionsRequest struct {
+	Ecosystem string
+	Name      string
+}
+
+func (m *ListVersionsRequest) unmarshal(b []byte) error {
+	return decodeStrings(b, map[int]*string{1: &m.Ecosystem, 2: &m.Name})
+}
+
+// ListVersionsResponse mirrors opencontext.v1.ListVersionsResponse
+type ListVersionsResponse struct {
+	Versions []string
+}
+
+func (m *ListVersionsResponse) marshal() []byte {
+	var e encoder
+	for _, v := range m.Versions {
+		// Repeated strings keep empty elements, unlike singular fields
+		e.bytes(1, []byte(v))
+	}
+	return e.buf
+}
+
+// encoder appends protobuf fields to buf
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) tag(field, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wireType))
+}
+
+func (e *encoder) bytes(field int, b []byte) {
+	e.tag(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// string writes a proto3 singular string, which is omitted when empty
+func (e *encoder) string(field int, s string) {
+	if s != "" {
+		e.bytes(field, []byte(s))
+	}
+}
+
+func (e *encoder) double(field int, v float64) {
+	if v != 0 {
+		e.tag(field, wireFixed64)
+		e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(v))
+	}
+}
+
+func (e *encoder) message(field int, m []byte) {
+	e.bytes(field, m)
+}
+
+// decodeStrings reads a message whose known fields are all strings, skipping
+// unknown fields as the protobuf spec requires
+func decodeStrings(b []byte, fields map[int]*string) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return fmt.Errorf("invalid field tag")
+		}
+		b = b[n:]
+		field, wireType := int(key>>3), int(key&7)
+
+		switch wireType {
+		case wireVarint:
+			if _, n = binary.Uvarint(b); n <= 0 {
+				return fmt.Errorf("invalid varint in field %d", field)
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return fmt.Errorf("truncated field %d", field)
+			}
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return fmt.Errorf("truncated field %d", field)
+			}
+			b = b[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return fmt.Errorf("truncated field %d", field)
+			}
+			value := b[n : n+int(length)]
+			b = b[n+int(length):]
+			if dst, ok := fields[field]; ok {
+				*dst = string(value)
+			}
+		default:
+			return fmt.Errorf("unsupported wire type %d in field %d", wireType, field)
+		}
+	}
+	return nil
+}
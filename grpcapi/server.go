@@ -0,0 +1,268 @@
+// Package grpcapi serves the documentation service defined in
+// opencontext.proto over gRPC, so other backend services can use open-context
+// as a documentation microservice.
+//
+// The gRPC protocol is implemented directly on net/http (HTTP/2 without TLS),
+// which keeps the server free of generated code and extra dependencies.
+// interop_test.go checks it against the grpc-go client.
+package grpcapi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	servicePath = "/opencontext.v1.Documentation/"
+
+	// maxMessageSize matches the default receive limit of gRPC clients
+	maxMessageSize = 4 << 20
+
+	// docChunkSize is the target size of the chunks GetDoc streams
+	docChunkSize = 32 << 10
+)
+
+// Code is a gRPC status code
+type Code int
+
+const (
+	OK              Code = 0
+	InvalidArgument Code = 3
+	NotFound        Code = 5
+	Unimplemented   Code = 12
+	Internal        Code = 13
+	Unavailable     Code = 14
+)
+
+// Error is an error carrying a gRPC status code
+type Error struct {
+	Code    Code
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errorf returns an error with the given status code
+func Errorf(code Code, format string, args ...interface{}) error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Backend implements the Documentation service. Errors that are not an *Error
+// are reported as Internal.
+type Backend interface {
+	Search(req *SearchRequest) (*SearchResponse, error)
+	GetDoc(req *GetDocRequest) (string, error)
+	FetchPackage(req *FetchPackageRequest) (*Package, error)
+	ListVersions(req *ListVersionsRequest) (*ListVersionsResponse, error)
+}
+
+type handler struct {
+	backend Backend
+}
+
+// NewHandler returns an http.Handler serving the Documentation service
+func NewHandler(backend Backend) http.Handler {
+	return &handler{backend: backend}
+}
+
+// ListenAndServe serves the Documentation service on addr
+func ListenAndServe(addr string, backend Backend) error {
+	srv := newServer(backend)
+	srv.Addr = addr
+
+	log.Printf("gRPC server listening on %s", addr)
+	return srv.ListenAndServe()
+}
+
+// newServer returns a server for the Documentation service. gRPC needs
+// HTTP/2, so unencrypted HTTP/2 (h2c with prior knowledge) is enabled.
+func newServer(backend Backend) *http.Server {
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	return &http.Server{
+		Handler:           NewHandler(backend),
+		Protocols:         &protocols,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ProtoMajor != 2 ||
+		!strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests must be HTTP/2 POSTs with content-type application/grpc", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+
+	// A panic fails this call alone
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("Panic in gRPC call %s: %v\n%s", r.URL.Path, p, debug.Stack())
+			writeStatus(w, Errorf(Internal, "internal error: %v", p))
+		}
+	}()
+
+	method, ok := strings.CutPrefix(r.URL.Path, servicePath)
+	if !ok {
+		writeStatus(w, Errorf(Unimplemented, "unknown service in %s", r.URL.Path))
+		return
+	}
+
+	payload, err := readMessage(r.Body)
+	if err != nil {
+		writeStatus(w, err)
+		return
+	}
+
+	switch method {
+	case "Search":
+		var req SearchRequest
+		if err := req.unmarshal(payload); err != nil {
+			writeStatus(w, Errorf(InvalidArgument, "invalid SearchRequest: %v", err))
+			return
+		}
+		resp, err := h.backend.Search(&req)
+		if err == nil {
+			err = writeMessage(w, resp.marshal())
+		}
+		writeStatus(w, err)
+
+	case "GetDoc":
+		var req GetDocRequest
+		if err := req.unmarshal(payload); err != nil {
+			writeStatus(w, Errorf(InvalidArgument, "invalid GetDocRequest: %v", err))
+			return
+		}
+		doc, err := h.backend.GetDoc(&req)
+		if err == nil {
+			err = streamDoc(w, doc)
+		}
+		writeStatus(w, err)
+
+	case "FetchPackage":
+		var req FetchPackageRequest
+		if err := req.unmarshal(payload); err != nil {
+			writeStatus(w, Errorf(InvalidArgument, "invalid FetchPackageRequest: %v", err))
+			return
+		}
+		resp, err := h.backend.FetchPackage(&req)
+		if err == nil {
+			err = writeMessage(w, resp.marshal())
+		}
+		writeStatus(w, err)
+
+	case "ListVersions":
+		var req ListVersionsRequest
+		if err := req.unmarshal(payload); err != nil {
+			writeStatus(w, Errorf(InvalidArgument, "invalid ListVersionsRequest: %v", err))
+			return
+		}
+		resp, err := h.backend.ListVersions(&req)
+		if err == nil {
+			err = writeMessage(w, resp.marshal())
+		}
+		writeStatus(w, err)
+
+	default:
+		writeStatus(w, Errorf(Unimplemented, "unknown method %s", method))
+	}
+}
+
+// readMessage reads the single length-prefixed request message of a unary or
+// server-streaming call
+func readMessage(body io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, Errorf(InvalidArgument, "failed to read request message: %v", err)
+	}
+	if prefix[0] != 0 {
+		return nil, Errorf(Unimplemented, "compressed messages are not supported")
+	}
+
+	length := binary.BigEndian.Uint32(prefix[1:])
+	if length > maxMessageSize {
+		return nil, Errorf(InvalidArgument, "request message exceeds %d bytes", maxMessageSize)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(body, payload); err != nil {
+		return nil, Errorf(InvalidArgument, "failed to read request message: %v", err)
+	}
+	return payload, nil
+}
+
+func writeMessage(w http.ResponseWriter, payload []byte) error {
+	frame := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	if _, err := w.Write(append(frame, payload...)); err != nil {
+		return err
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// streamDoc sends a document as a series of DocChunk messages, splitting on
+// line boundaries where possible so markdown blocks stay intact, and between
+// characters otherwise
+func streamDoc(w http.ResponseWriter, doc string) error {
+	for len(doc) > 0 {
+		n := len(doc)
+		if n > docChunkSize {
+			n = docChunkSize
+			if idx := strings.LastIndexByte(doc[:n], '\n'); idx > 0 {
+				n = idx + 1
+			}
+			// Clients reject string fields that are not valid UTF-8, so
+			// never cut a character in two
+			for n > 0 && !utf8.RuneStart(doc[n]) {
+				n--
+			}
+			if n == 0 {
+				n = docChunkSize
+			}
+		}
+
+		chunk := DocChunk{Content: doc[:n]}
+		if err := writeMessage(w, chunk.marshal()); err != nil {
+			return err
+		}
+		doc = doc[n:]
+	}
+	return nil
+}
+
+// writeStatus ends the call by setting the grpc-status and grpc-message trailers
+func writeStatus(w http.ResponseWriter, err error) {
+	code, message := OK, ""
+	if err != nil {
+		var grpcErr *Error
+		if errors.As(err, &grpcErr) {
+			code, message = grpcErr.Code, grpcErr.Message
+		} else {
+			code, message = Internal, err.Error()
+		}
+	}
+
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(int(code)))
+	if message != "" {
+		// grpc-message is percent-encoded (gRPC over HTTP/2 spec)
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", url.PathEscape(message))
+	}
+}
@@ -178,29 +178,12 @@ download_and_install() {
     print_success "Installed to: $BINARY_PATH"
 }
 
-# Create config directory
-create_config_dir() {
-    if [ ! -d "$CONFIG_DIR" ]; then
-        mkdir -p "$CONFIG_DIR"
-        print_success "Created configuration directory: $CONFIG_DIR"
-    else
-        print_info "Configuration directory already exists: $CONFIG_DIR"
-    fi
-}
-
-# Create default config file
-create_default_config() {
+# Point to the setup wizard when there is no config file yet
+check_config() {
     CONFIG_FILE="$CONFIG_DIR/config.yaml"
 
     if [ ! -f "$CONFIG_FILE" ]; then
-        cat > "$CONFIG_FILE" << 'EOF'
-# Open Context Configuration
-# Cache TTL (time to live) - how long cached data remains valid
-# Format: duration string (e.g., "24h", "7d", "168h")
-# Default: 168h (7 days)
-cache_ttl: 168h
-EOF
-        print_success "Created default configuration: $CONFIG_FILE"
+        print_info "No configuration file yet; run '$BINARY_NAME init' to create one"
     else
         print_info "Configuration file already exists: $CONFIG_FILE"
     fi
@@ -363,6 +346,7 @@ print_usage() {
     echo "========================================"
     echo ""
     echo "Usage:"
+    echo "  $BINARY_NAME init                # Create a config file"
     echo "  $BINARY_NAME --help              # Show help"
     echo ""
     echo "Configuration:"
@@ -388,9 +372,8 @@ main() {
     # Check if update is needed
     if [ -n "$INSTALLED_VERSION" ]; then
         if compare_versions; then
-            # Already up to date, but ensure config exists
-            create_config_dir
-            create_default_config
+            # Already up to date
+            check_config
             check_path
 
             # Offer Claude MCP setup even if already installed
@@ -406,8 +389,7 @@ main() {
     download_and_install
 
     # Setup configuration
-    create_config_dir
-    create_default_config
+    check_config
 
     # Verify
     verify_installation
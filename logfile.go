@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/incu6us/open-context/config"
+)
+
+// maxLogSize is the size past which the log file is moved to
+// open-context.log.1 when the server starts, so at most two files are kept
+const maxLogSize = 5 << 20
+
+// teeStderr copies what the server writes to stderr, the log package's
+// output and the fetchers' progress and warnings alike, to the log file
+// open-context report reads. Each line of the file is prefixed with the time
+// it was written. Nothing leaves this machine. The returned function
+// writes out what is still buffered and restores stderr; call it before
+// exiting.
+func teeStderr() (func(), error) {
+	path, err := config.GetLogPath()
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxLogSize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return nil, fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to create log pipe: %w", err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	log.SetOutput(w)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		copyLog(r, stderr, file)
+	}()
+
+	return func() {
+		os.Stderr = stderr
+		log.SetOutput(stderr)
+		_ = w.Close()
+		<-done
+		_ = r.Close()
+		_ = file.Close()
+	}, nil
+}
+
+// copyLog writes each line read from r to stderr unchanged and to file with
+// a timestamp
+func copyLog(r io.Reader, stderr, file io.Writer) {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			_, _ = io.WriteString(stderr, line)
+			_, _ = fmt.Fprintf(file, "%s %s", time.Now().UTC().Format(time.RFC3339), line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -1,16 +1,21 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	cli "github.com/urfave/cli/v3"
 
+	"github.com/incu6us/open-context/cache"
 	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/fetcher"
 	"github.com/incu6us/open-context/server"
+	"github.com/incu6us/open-context/site"
 )
 
 // Project build specific vars, set during build time via ldflags
@@ -41,7 +46,7 @@ func main() {
 			&cli.StringFlag{
 				Name:    "host",
 				Aliases: []string{"H"},
-				Usage:   "Host address for HTTP transport (e.g., '0.0.0.0' or 'localhost')",
+				Usage:   "Host address for HTTP transport (e.g., 'localhost', or '0.0.0.0' with --allow-unauthenticated)",
 				Value:   "localhost",
 			},
 			&cli.IntFlag{
@@ -50,6 +55,245 @@ func main() {
 				Usage:   "Port for HTTP transport",
 				Value:   9011,
 			},
+			&cli.BoolFlag{
+				Name:    "allow-unauthenticated",
+				Aliases: []string{"allow-public-bind"},
+				Usage:   "Listen on a non-loopback host, exposing the HTTP and gRPC transports, which anyone who can reach them may use",
+			},
+			&cli.IntFlag{
+				Name:  "grpc-port",
+				Usage: "Also serve the gRPC documentation API on this port (0 disables it)",
+				Value: 0,
+			},
+			&cli.StringFlag{
+				Name:  "fixtures",
+				Usage: "Answer every tool call from the canned responses in this directory, for deterministic end-to-end tests",
+			},
+			&cli.StringFlag{
+				Name:  "record-fixtures",
+				Usage: "Save the response of every tool call to this directory, for later use with --fixtures",
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name:  "init",
+				Usage: "Create a config file interactively and print the MCP client configuration",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return runInit(os.Stdin, os.Stdout)
+				},
+			},
+			{
+				Name:      "import",
+				Usage:     "Index a local directory of markdown files as a searchable documentation set",
+				ArgsUsage: "<directory>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "name",
+						Aliases: []string{"n"},
+						Usage:   "Documentation name, searchable as 'local-<name>' (defaults to the directory's name)",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 1 {
+						return fmt.Errorf("usage: open-context import [--name NAME] <directory>")
+					}
+					return importDocs(cmd.Args().First(), cmd.String("name"))
+				},
+			},
+			{
+				Name:      "import-docset",
+				Usage:     "Index a Dash or Zeal docset as a searchable documentation set",
+				ArgsUsage: "<path/to.docset>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "name",
+						Aliases: []string{"n"},
+						Usage:   "Documentation name, searchable as 'docset-<name>' (defaults to the docset's name)",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 1 {
+						return fmt.Errorf("usage: open-context import-docset [--name NAME] <path/to.docset>")
+					}
+					return importDocset(cmd.Args().First(), cmd.String("name"))
+				},
+			},
+			{
+				Name:  "topic",
+				Usage: "Add, remove, and list topics of a custom documentation",
+				Commands: []*cli.Command{
+					{
+						Name:  "add",
+						Usage: "Write a markdown file as a topic",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "language",
+								Aliases:  []string{"l"},
+								Usage:    "Documentation the topic belongs to (e.g., 'myteam')",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "file",
+								Aliases:  []string{"f"},
+								Usage:    "Markdown file with the topic's content",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "title",
+								Usage: "Topic title (defaults to the front matter title or first heading)",
+							},
+							&cli.StringFlag{
+								Name:  "id",
+								Usage: "Topic ID (defaults to a slug of the title)",
+							},
+							&cli.StringFlag{
+								Name:  "description",
+								Usage: "Topic description (defaults to the front matter description or first paragraph)",
+							},
+							&cli.StringSliceFlag{
+								Name:    "keyword",
+								Aliases: []string{"k"},
+								Usage:   "Search keyword, in addition to the title, headings, and front matter tags (repeatable)",
+							},
+							topicDirFlag,
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return addTopic(cmd.String("dir"), cmd.String("language"), fetcher.TopicOptions{
+								File:        cmd.String("file"),
+								ID:          cmd.String("id"),
+								Title:       cmd.String("title"),
+								Description: cmd.String("description"),
+								Keywords:    cmd.StringSlice("keyword"),
+							})
+						},
+					},
+					{
+						Name:      "rm",
+						Usage:     "Remove a topic",
+						ArgsUsage: "<id>",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "language",
+								Aliases:  []string{"l"},
+								Usage:    "Documentation the topic belongs to",
+								Required: true,
+							},
+							topicDirFlag,
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							if cmd.Args().Len() != 1 {
+								return fmt.Errorf("usage: open-context topic rm --language LANGUAGE <id>")
+							}
+							return removeTopic(cmd.String("dir"), cmd.String("language"), cmd.Args().First())
+						},
+					},
+					{
+						Name:  "list",
+						Usage: "List the topics of a documentation",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "language",
+								Aliases:  []string{"l"},
+								Usage:    "Documentation to list",
+								Required: true,
+							},
+							topicDirFlag,
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return listTopics(cmd.String("dir"), cmd.String("language"))
+						},
+					},
+				},
+			},
+			{
+				Name:  "cache",
+				Usage: "Seed the cache from another server, and list and restore entries moved to the trash on expiry",
+				Commands: []*cli.Command{
+					{
+						Name:      "pull",
+						Usage:     "Copy cached namespaces from another server's admin API (e.g., https://docs.example.com/admin/export?ns=npm,python)",
+						ArgsUsage: "<export-url>",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "token",
+								Usage: "The other server's admin token (defaults to OPEN_CONTEXT_ADMIN_TOKEN)",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							if cmd.Args().Len() != 1 {
+								return fmt.Errorf("usage: open-context cache pull [--token TOKEN] <export-url>")
+							}
+							return pullCache(ctx, cmd.Args().First(), cmp.Or(cmd.String("token"), os.Getenv("OPEN_CONTEXT_ADMIN_TOKEN")))
+						},
+					},
+					{
+						Name:  "trash",
+						Usage: "List the entries in the cache trash",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return listTrash()
+						},
+					},
+					{
+						Name:      "restore",
+						Usage:     "Move trashed entries back into the cache, all of them or those under the given keys (e.g., 'npm/packages')",
+						ArgsUsage: "[key-prefix...]",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return restoreTrash(cmd.Args().Slice())
+						},
+					},
+				},
+			},
+			{
+				Name:  "export-tools",
+				Usage: "Print the tool definitions as function-calling schemas for non-MCP integrations",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Schema format: 'openai' or 'anthropic'",
+						Value:   "openai",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return exportTools(cmd.String("format"))
+				},
+			},
+			{
+				Name:  "report",
+				Usage: "Bundle the version, redacted config, cache statistics, and recent log lines and errors into an archive to attach to a bug report",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "out",
+						Aliases: []string{"o"},
+						Usage:   "Archive to write (defaults to open-context-report-<time>.tar.gz)",
+					},
+					&cli.IntFlag{
+						Name:    "lines",
+						Aliases: []string{"n"},
+						Usage:   "Number of log lines to include",
+						Value:   500,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					setBuildInfo()
+					return writeReport(cmd.String("out"), cmd.Int("lines"))
+				},
+			},
+			{
+				Name:  "export-site",
+				Usage: "Render the cached documentation as a static HTML website",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "out",
+						Aliases: []string{"o"},
+						Usage:   "Output directory",
+						Value:   "./site",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return exportSite(cmd.String("out"))
+				},
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			// Check if clear-cache flag is set
@@ -61,9 +305,20 @@ func main() {
 			transport := cmd.String("transport")
 			host := cmd.String("host")
 			port := cmd.Int("port")
+			grpcPort := cmd.Int("grpc-port")
+
+			// The network transports have no authentication, so exposing them
+			// beyond this machine must be deliberate
+			if (transport == "http" || grpcPort > 0) && !server.IsLoopbackHost(host) && !cmd.Bool("allow-unauthenticated") {
+				return fmt.Errorf("refusing to listen on non-loopback host %q: the HTTP and gRPC transports have no authentication, so anyone who can reach them may use them; bind to localhost behind an authenticating proxy, or pass --allow-unauthenticated", host)
+			}
+
+			if cmd.String("fixtures") != "" && cmd.String("record-fixtures") != "" {
+				return fmt.Errorf("--fixtures and --record-fixtures cannot be used together")
+			}
 
 			// Run the MCP server with specified transport
-			return runServer(transport, host, port)
+			return runServer(transport, host, port, grpcPort, cmd.String("fixtures"), cmd.String("record-fixtures"))
 		},
 	}
 
@@ -72,12 +327,62 @@ func main() {
 	}
 }
 
-func runServer(transport, host string, port int) error {
+// setBuildInfo passes the build metadata set through ldflags to the server
+func setBuildInfo() {
+	server.SetBuildInfo(server.BuildInfo{
+		Version:   strings.TrimPrefix(Tag, "v"),
+		Commit:    Commit,
+		GoVersion: GoVersion,
+		SourceURL: SourceURL,
+	})
+}
+
+func runServer(transport, host string, port, grpcPort int, fixturesDir, recordDir string) error {
+	setBuildInfo()
+	if restoreStderr, err := teeStderr(); err != nil {
+		log.Printf("Warning: not writing a log file: %v", err)
+	} else {
+		defer restoreStderr()
+	}
+
 	mcpServer, err := server.NewMCPServer()
 	if err != nil {
 		return err
 	}
 
+	switch {
+	case fixturesDir != "":
+		if _, err := os.Stat(fixturesDir); err != nil {
+			return fmt.Errorf("fixtures directory: %w", err)
+		}
+		log.Printf("Serving tool calls from fixtures in %s", fixturesDir)
+		mcpServer.ServeFixtures(fixturesDir)
+	case recordDir != "":
+		log.Printf("Recording tool calls as fixtures in %s", recordDir)
+		mcpServer.RecordFixtures(recordDir)
+	}
+
+	// Replayed calls never reach the fetchers, so there is nothing to prefetch
+	if cfg, err := config.Load(); err == nil && fixturesDir == "" {
+		if len(cfg.WatchManifests) > 0 {
+			log.Printf("Watching %d manifest path(s) for new dependencies", len(cfg.WatchManifests))
+			mcpServer.WatchManifests(cfg.WatchManifests)
+		}
+		if len(cfg.DevDocs) > 0 {
+			log.Printf("Indexing %d DevDocs docset(s) in the background", len(cfg.DevDocs))
+			mcpServer.SyncDevDocs(cfg.DevDocs)
+		}
+	}
+
+	if grpcPort > 0 {
+		grpcAddr := fmt.Sprintf("%s:%d", host, grpcPort)
+		go func() {
+			if err := mcpServer.ServeGRPC(grpcAddr); err != nil {
+				log.Fatalf("gRPC server failed: %v", err)
+			}
+		}()
+	}
+
 	switch transport {
 	case "stdio":
 		log.Println("Starting MCP server with stdio transport")
@@ -94,6 +399,187 @@ func runServer(transport, host string, port int) error {
 	}
 }
 
+func exportTools(format string) error {
+	data, err := server.ExportTools(format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func importDocs(dir, name string) error {
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	info, err := fetcher.NewLocalDocsFetcher(cacheDir).ImportDocs(dir, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(info.Content)
+	fmt.Printf("Search it with language '%s'; running servers pick it up after a restart.\n", info.Documentation)
+	return nil
+}
+
+func importDocset(path, name string) error {
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	info, err := fetcher.NewDocsetFetcher(cacheDir).ImportDocset(path, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(info.Content)
+	fmt.Printf("Search it with language '%s'; running servers pick it up after a restart.\n", info.Documentation)
+	return nil
+}
+
+// topicDirFlag selects the docs directory topic commands write to
+var topicDirFlag = &cli.StringFlag{
+	Name:    "dir",
+	Aliases: []string{"d"},
+	Usage:   "Docs directory holding one directory per documentation (defaults to the cache directory)",
+}
+
+// topicsDir returns dir, or the cache directory the server loads
+// documentation from if dir is empty
+func topicsDir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return cacheDir, nil
+}
+
+func addTopic(dir, language string, opts fetcher.TopicOptions) error {
+	dir, err := topicsDir(dir)
+	if err != nil {
+		return err
+	}
+
+	topic, err := fetcher.AddTopic(dir, language, opts)
+	if err != nil {
+		return err
+	}
+
+	action := "Added"
+	if topic.Replaced {
+		action = "Replaced"
+	}
+	fmt.Printf("%s topic '%s' (%s) in %s\n", action, topic.ID, topic.Title, topic.Path)
+	fmt.Printf("Keywords: %s\n", strings.Join(topic.Keywords, ", "))
+	fmt.Printf("Read it with language '%s'; running servers pick it up after a restart.\n", language)
+	return nil
+}
+
+func removeTopic(dir, language, id string) error {
+	dir, err := topicsDir(dir)
+	if err != nil {
+		return err
+	}
+
+	topic, err := fetcher.RemoveTopic(dir, language, id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed topic '%s' (%s)\n", topic.ID, topic.Title)
+	return nil
+}
+
+func listTopics(dir, language string) error {
+	dir, err := topicsDir(dir)
+	if err != nil {
+		return err
+	}
+
+	topics, err := fetcher.ListTopics(dir, language)
+	if err != nil {
+		return err
+	}
+	if len(topics) == 0 {
+		fmt.Printf("No topics in %s\n", language)
+		return nil
+	}
+	for _, t := range topics {
+		fmt.Printf("%s\t%s\n", t.ID, t.Title)
+	}
+	return nil
+}
+
+func exportSite(outDir string) error {
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	stats, err := site.Export(cacheDir, outDir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d entries in %d namespaces to %s\n", stats.Entries, stats.Namespaces, outDir)
+	return nil
+}
+
+func listTrash() error {
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	entries, err := cache.ListTrash(cacheDir)
+	if err != nil {
+		return fmt.Errorf("failed to read the cache trash: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("The cache trash is empty")
+		return nil
+	}
+
+	var retention time.Duration
+	if cfg, err := config.Load(); err == nil {
+		retention = cfg.CacheTrash.Duration
+	}
+	for _, e := range entries {
+		purge := "-"
+		if retention > 0 {
+			purge = e.TrashedAt.Add(retention).Format(time.DateTime)
+		}
+		fmt.Printf("%s\ttrashed %s\tpurged after %s\n", e.Key, e.TrashedAt.Format(time.DateTime), purge)
+	}
+	return nil
+}
+
+func restoreTrash(prefixes []string) error {
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	restored, err := cache.RestoreTrash(cacheDir, prefixes)
+	for _, key := range restored {
+		fmt.Printf("Restored %s\n", key)
+	}
+	if err != nil {
+		return err
+	}
+	if len(restored) == 0 {
+		fmt.Println("No trashed entries to restore")
+		return nil
+	}
+	fmt.Printf("✓ Restored %d cache entries\n", len(restored))
+	return nil
+}
+
 func clearCache() error {
 	// Get cache directory
 	cacheDir, err := config.GetCacheDir()
@@ -101,6 +587,18 @@ func clearCache() error {
 		return fmt.Errorf("failed to get cache directory: %w", err)
 	}
 
+	// A shared cache backend is cleared for every replica using it
+	if cfg, err := config.Load(); err == nil && cfg.Cache.Backend != "" && cfg.Cache.Backend != "disk" {
+		store, err := cache.NewStore(cfg.Cache, cacheDir)
+		if err != nil {
+			return fmt.Errorf("failed to open %s cache: %w", cfg.Cache.Backend, err)
+		}
+		fmt.Printf("Removing cached objects from %s bucket: %s\n", cfg.Cache.Backend, cfg.Cache.Bucket)
+		if err := store.DeletePrefix(""); err != nil {
+			return fmt.Errorf("failed to clear %s cache: %w", cfg.Cache.Backend, err)
+		}
+	}
+
 	// Check if cache directory exists
 	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
 		fmt.Printf("Cache directory does not exist: %s\n", cacheDir)
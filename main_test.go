@@ -232,21 +232,71 @@ func TestMCPServerToolsList(t *testing.T) {
 		"open-context_search_docs",
 		"open-context_get_docs",
 		"open-context_list_docs",
+		"open-context_smart_docs",
+		"open-context_get_code_examples",
 		"open-context_get_go_info",
+		"open-context_get_go_vulns",
+		"open-context_get_go_dependencies",
 		"open-context_get_npm_info",
 		"open-context_get_python_info",
+		"open-context_get_python_version",
 		"open-context_get_rust_info",
+		"open-context_get_gem_info",
+		"open-context_get_hex_info",
+		"open-context_get_cocoapod_info",
+		"open-context_get_conan_info",
+		"open-context_generate_dependency_snippet",
+		"open-context_get_packages_info",
 		"open-context_get_node_info",
+		"open-context_get_node_schedule",
+		"open-context_get_java_info",
+		"open-context_get_deno_info",
+		"open-context_get_bun_info",
 		"open-context_get_typescript_info",
+		"open-context_get_typescript_feature",
 		"open-context_get_nextjs_info",
+		"open-context_get_nextjs_docs",
 		"open-context_get_react_info",
+		"open-context_get_react_api",
 		"open-context_get_ansible_info",
+		"open-context_get_ansible_collection",
 		"open-context_get_terraform_info",
+		"open-context_find_terraform_examples",
+		"open-context_get_terraform_schema",
+		"open-context_get_pulumi_info",
+		"open-context_get_cdk_info",
 		"open-context_get_jenkins_info",
+		"open-context_get_jenkins_plugin",
 		"open-context_get_kubernetes_info",
 		"open-context_get_helm_info",
+		"open-context_get_helm_chart",
+		"open-context_get_nginx_info",
+		"open-context_compare_versions",
+		"open-context_get_git_info",
+		"open-context_get_docker_engine_info",
 		"open-context_get_docker_image",
 		"open-context_get_github_action",
+		"open-context_get_github_readme",
+		"open-context_get_github_release",
+		"open-context_get_gitlab_project",
+		"open-context_get_so_answers",
+		"open-context_explain_error",
+		"open-context_get_rust_error",
+		"open-context_get_ts_diagnostic",
+		"open-context_get_http_reference",
+		"open-context_get_rfc",
+		"open-context_get_web_spec",
+		"open-context_get_posix_util",
+		"open-context_get_man_page",
+		"open-context_get_tldr",
+		"open-context_get_buf_module",
+		"open-context_get_license",
+		"open-context_compare_sql_feature",
+		"open-context_get_devdocs",
+		"open-context_get_llms_txt",
+		"open-context_fetch_site",
+		"open-context_import_docs",
+		"open-context_get_local_symbol",
 	}
 
 	toolNames := make(map[string]bool)
@@ -352,6 +402,15 @@ func TestMCPServerAllFetchers(t *testing.T) {
 			expectInContent: []string{"serde", "cargo add"},
 			timeout:         defaultTimeout,
 		},
+		{
+			name:     "RubyGems fetcher",
+			toolName: "open-context_get_gem_info",
+			arguments: map[string]interface{}{
+				"gemName": "rake",
+			},
+			expectInContent: []string{"rake", "gem install"},
+			timeout:         defaultTimeout,
+		},
 		{
 			name:     "React fetcher",
 			toolName: "open-context_get_react_info",
@@ -543,6 +602,39 @@ func TestMCPServerErrorHandling(t *testing.T) {
 			expectedError: true,
 			errorContains: "version parameter is required",
 		},
+		{
+			name: "Unsupported ecosystem",
+			request: map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"method":  "tools/call",
+				"params": map[string]interface{}{
+					"name": "open-context_generate_dependency_snippet",
+					"arguments": map[string]interface{}{
+						"ecosystem": "cobol",
+						"package":   "anything",
+					},
+				},
+			},
+			expectedError: true,
+			errorContains: "unsupported ecosystem",
+		},
+		{
+			name: "Go workspace not configured",
+			request: map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"method":  "tools/call",
+				"params": map[string]interface{}{
+					"name": "open-context_get_local_symbol",
+					"arguments": map[string]interface{}{
+						"name": "Server",
+					},
+				},
+			},
+			expectedError: true,
+			errorContains: "go_workspace is not configured",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -742,3 +834,105 @@ func TestMCPServerFullWorkflow(t *testing.T) {
 
 	t.Log("✓ Full MCP workflow completed successfully")
 }
+
+// TestMCPServerFixtures records tool calls with --record-fixtures and
+// replays them with --fixtures from an empty cache
+func TestMCPServerFixtures(t *testing.T) {
+	fixturesDir := t.TempDir()
+
+	calls := []map[string]interface{}{
+		{
+			"name": "open-context_compare_sql_feature",
+			"arguments": map[string]interface{}{
+				"feature": "upsert",
+			},
+		},
+		{
+			"name":      "open-context_get_node_info",
+			"arguments": map[string]interface{}{},
+		},
+	}
+
+	// run serves the calls and returns their results and error messages
+	run := func(flag string, calls []map[string]interface{}) []string {
+		cmd := exec.Command("go", "run", ".", flag, fixturesDir)
+		// Isolate GOMODCACHE to prevent permission issues during cleanup
+		gomodcache := filepath.Join(os.TempDir(), "open-context-test-gomodcache")
+		// Each run gets an empty cache, so replays cannot come from it
+		home := t.TempDir()
+		cmd.Env = append(os.Environ(), "HOME="+home, "USERPROFILE="+home, "GOMODCACHE="+gomodcache)
+
+		stdin, _ := cmd.StdinPipe()
+		stdout, _ := cmd.StdoutPipe()
+		cmd.Stderr = io.Discard
+
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("Failed to start command: %v", err)
+		}
+
+		decoder := json.NewDecoder(stdout)
+		var outputs []string
+		for i, params := range calls {
+			request := map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      i + 1,
+				"method":  "tools/call",
+				"params":  params,
+			}
+			requestJSON, _ := json.Marshal(request)
+			_, _ = stdin.Write(append(requestJSON, '\n'))
+
+			var response struct {
+				Result struct {
+					Content []struct {
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"result"`
+				Error *struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := decoder.Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response %d: %v", i+1, err)
+			}
+			switch {
+			case response.Error != nil:
+				outputs = append(outputs, "error: "+response.Error.Message)
+			case len(response.Result.Content) > 0:
+				outputs = append(outputs, response.Result.Content[0].Text)
+			default:
+				outputs = append(outputs, "")
+			}
+		}
+
+		_ = stdin.Close()
+		_ = cmd.Wait()
+		return outputs
+	}
+
+	recorded := run("--record-fixtures", calls)
+	if !strings.Contains(recorded[0], "ON CONFLICT") {
+		t.Fatalf("Recorded result missing expected content: %.200s", recorded[0])
+	}
+	if !strings.Contains(recorded[1], "version parameter is required") {
+		t.Fatalf("Recorded error unexpected: %s", recorded[1])
+	}
+
+	missing := map[string]interface{}{
+		"name": "open-context_compare_sql_feature",
+		"arguments": map[string]interface{}{
+			"feature": "merge",
+		},
+	}
+	replayed := run("--fixtures", append(calls, missing))
+	for i := range calls {
+		if replayed[i] != recorded[i] {
+			t.Errorf("Replayed call %d differs from its recording:\n%.200s\nvs\n%.200s", i+1, replayed[i], recorded[i])
+		}
+	}
+	if !strings.Contains(replayed[2], "no fixture for open-context_compare_sql_feature") {
+		t.Errorf("Expected a missing fixture error, got: %.200s", replayed[2])
+	}
+
+	t.Log("✓ Fixtures recorded and replayed correctly")
+}
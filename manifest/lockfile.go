@@ -0,0 +1,265 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/semver"
+)
+
+// lockedVersions maps a package name to every version a lockfile resolved it to
+type lockedVersions map[string][]string
+
+type lockParseFunc func(data []byte) (lockedVersions, error)
+
+// lockfileParsers lists, per ecosystem, the lockfiles that pin exact versions
+// in order of preference
+var lockfileParsers = map[string][]struct {
+	name  string
+	parse lockParseFunc
+}{
+	"go": {
+		{"go.sum", parseGoSum},
+	},
+	"npm": {
+		{"package-lock.json", parsePackageLock},
+		{"npm-shrinkwrap.json", parsePackageLock},
+		{"pnpm-lock.yaml", parsePnpmLock},
+	},
+	"python": {
+		{"poetry.lock", parseTomlPackages},
+	},
+	"rust": {
+		{"Cargo.lock", parseTomlPackages},
+	},
+}
+
+// Lockfiles returns the existing lockfiles next to a manifest that Resolve reads
+func Lockfiles(manifestPath string) []string {
+	ecosystem := manifestEcosystem(filepath.Base(manifestPath))
+	dir := filepath.Dir(manifestPath)
+
+	var files []string
+	for _, lock := range lockfileParsers[ecosystem] {
+		path := filepath.Join(dir, lock.name)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// Resolve replaces manifest ranges with the exact versions recorded in the
+// lockfile next to the manifest. Dependencies the lockfile does not mention
+// are returned unchanged; a manifest without a lockfile is not an error.
+func Resolve(manifestPath string, deps []Dependency) ([]Dependency, error) {
+	ecosystem := manifestEcosystem(filepath.Base(manifestPath))
+	dir := filepath.Dir(manifestPath)
+
+	for _, lock := range lockfileParsers[ecosystem] {
+		data, err := os.ReadFile(filepath.Join(dir, lock.name))
+		if err != nil {
+			continue
+		}
+
+		locked, err := lock.parse(data)
+		if err != nil {
+			return deps, fmt.Errorf("failed to parse %s: %w", lock.name, err)
+		}
+
+		byKey := make(lockedVersions, len(locked))
+		for name, versions := range locked {
+			key := lockKey(ecosystem, name)
+			byKey[key] = append(byKey[key], versions...)
+		}
+
+		resolved := make([]Dependency, len(deps))
+		for i, dep := range deps {
+			if version := pickLockedVersion(dep, byKey[lockKey(ecosystem, dep.Name)]); version != "" {
+				dep.Version = version
+			}
+			resolved[i] = dep
+		}
+		return resolved, nil
+	}
+
+	return deps, nil
+}
+
+// manifestEcosystem returns the ecosystem of the dependencies a manifest declares
+func manifestEcosystem(base string) string {
+	switch {
+	case base == "package.json":
+		return "npm"
+	case base == "go.mod":
+		return "go"
+	case base == "Cargo.toml":
+		return "rust"
+	case base == "pyproject.toml", strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"):
+		return "python"
+	}
+	return ""
+}
+
+// lockKey normalizes names that lockfiles spell differently from manifests
+// (PEP 503 for Python: "Flask_SQLAlchemy" and "flask-sqlalchemy" are the same package)
+func lockKey(ecosystem, name string) string {
+	if ecosystem == "python" {
+		return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+	}
+	return name
+}
+
+// pickLockedVersion chooses among the versions a lockfile holds for a package.
+// A manifest pin that the lockfile confirms wins; otherwise the highest version
+// compatible with the manifest constraint, falling back to the highest overall.
+func pickLockedVersion(dep Dependency, versions []string) string {
+	if len(versions) == 0 {
+		return ""
+	}
+	for _, v := range versions {
+		if v == dep.Version {
+			return v
+		}
+	}
+
+	sorted := append([]string(nil), versions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return semver.Compare(sorted[i], sorted[j]) > 0
+	})
+	for _, v := range sorted {
+		if sameReleaseLine(dep.Constraint, v) {
+			return v
+		}
+	}
+	return sorted[0]
+}
+
+var leadingVersionRe = regexp.MustCompile(`v?(\d+)(?:\.(\d+))?`)
+
+// sameReleaseLine reports whether a version shares the major version (or the
+// minor version, for 0.x) of the first version mentioned in a constraint
+func sameReleaseLine(constraint, version string) bool {
+	c := leadingVersionRe.FindStringSubmatch(constraint)
+	v := leadingVersionRe.FindStringSubmatch(version)
+	if c == nil || v == nil {
+		return false
+	}
+	if c[1] != v[1] {
+		return false
+	}
+	return c[1] != "0" || c[2] == "" || c[2] == v[2]
+}
+
+func parseGoSum(data []byte) (lockedVersions, error) {
+	locked := make(lockedVersions)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		// Lines ending in /go.mod only hash the module's go.mod, not a downloaded version
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		locked[fields[0]] = append(locked[fields[0]], fields[1])
+	}
+	return locked, nil
+}
+
+func parsePackageLock(data []byte) (lockedVersions, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	locked := make(lockedVersions)
+	// lockfileVersion 2 and 3 key packages by install path; only top-level
+	// installs belong to the project's direct dependencies
+	for path, pkg := range lock.Packages {
+		name, ok := strings.CutPrefix(path, "node_modules/")
+		if !ok || strings.Contains(name, "/node_modules/") || pkg.Version == "" {
+			continue
+		}
+		locked[name] = append(locked[name], pkg.Version)
+	}
+	// lockfileVersion 1
+	if len(locked) == 0 {
+		for name, pkg := range lock.Dependencies {
+			if pkg.Version != "" {
+				locked[name] = append(locked[name], pkg.Version)
+			}
+		}
+	}
+	return locked, nil
+}
+
+func parsePnpmLock(data []byte) (lockedVersions, error) {
+	type pnpmDeps map[string]interface{}
+	var lock struct {
+		Importers map[string]struct {
+			Dependencies    pnpmDeps `yaml:"dependencies"`
+			DevDependencies pnpmDeps `yaml:"devDependencies"`
+		} `yaml:"importers"`
+		Dependencies    pnpmDeps `yaml:"dependencies"`
+		DevDependencies pnpmDeps `yaml:"devDependencies"`
+	}
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	sets := []pnpmDeps{lock.Dependencies, lock.DevDependencies}
+	if root, ok := lock.Importers["."]; ok {
+		sets = []pnpmDeps{root.Dependencies, root.DevDependencies}
+	}
+
+	locked := make(lockedVersions)
+	for _, set := range sets {
+		for name, value := range set {
+			// Older lockfiles map names to versions; newer ones to {specifier, version}
+			version, _ := value.(string)
+			if entry, ok := value.(map[string]interface{}); ok {
+				version, _ = entry["version"].(string)
+			}
+			// Drop the peer dependency suffix: "18.2.0(react@18.2.0)" or "18.2.0_react@18.2.0"
+			version = strings.SplitN(strings.SplitN(version, "(", 2)[0], "_", 2)[0]
+			if version == "" || strings.Contains(version, ":") {
+				continue
+			}
+			locked[name] = append(locked[name], version)
+		}
+	}
+	return locked, nil
+}
+
+// parseTomlPackages reads the [[package]] tables of poetry.lock and Cargo.lock
+func parseTomlPackages(data []byte) (lockedVersions, error) {
+	locked := make(lockedVersions)
+	name := ""
+	for _, entry := range tomlEntries(data) {
+		if entry.Section != "package" {
+			continue
+		}
+		switch entry.Key {
+		case "name":
+			name, _ = tomlString(entry.Value)
+		case "version":
+			if version, ok := tomlString(entry.Value); ok && name != "" {
+				locked[name] = append(locked[name], version)
+				name = ""
+			}
+		}
+	}
+	return locked, nil
+}
@@ -0,0 +1,435 @@
+// Package manifest extracts declared dependencies from project manifests
+// (package.json, go.mod, Cargo.toml, requirements.txt, and friends).
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Dependency is a single dependency declared in a manifest
+type Dependency struct {
+	// Ecosystem is the canonical ecosystem name (npm, go, rust, python, ruby, hex, cocoapods)
+	Ecosystem string
+	Name      string
+	// Version is the exact version when the manifest pins one, empty otherwise
+	Version string
+	// Constraint is the version requirement as written in the manifest
+	Constraint string
+	Dev        bool
+}
+
+type parseFunc func(data []byte) ([]Dependency, error)
+
+// parsers maps manifest file names to their parser
+var parsers = map[string]parseFunc{
+	"package.json":   parsePackageJSON,
+	"go.mod":         parseGoMod,
+	"Cargo.toml":     parseCargoToml,
+	"pyproject.toml": parsePyproject,
+	"Gemfile":        parseGemfile,
+	"mix.exs":        parseMixExs,
+	"Podfile":        parsePodfile,
+}
+
+// Files returns the manifest file names recognized by Parse, excluding the
+// requirements*.txt family which is matched by pattern
+func Files() []string {
+	names := make([]string, 0, len(parsers)+1)
+	for name := range parsers {
+		names = append(names, name)
+	}
+	names = append(names, "requirements.txt")
+	sort.Strings(names)
+	return names
+}
+
+// IsManifest reports whether a path names a manifest that Parse understands
+func IsManifest(path string) bool {
+	return parserFor(filepath.Base(path)) != nil
+}
+
+// Parse reads a manifest and returns its dependencies, deduplicated by name
+func Parse(path string) ([]Dependency, error) {
+	parse := parserFor(filepath.Base(path))
+	if parse == nil {
+		return nil, fmt.Errorf("unsupported manifest: %s", filepath.Base(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	deps, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	return dedupe(deps), nil
+}
+
+func parserFor(base string) parseFunc {
+	if parse, ok := parsers[base]; ok {
+		return parse
+	}
+	if strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt") {
+		return parseRequirements
+	}
+	return nil
+}
+
+func dedupe(deps []Dependency) []Dependency {
+	seen := make(map[string]bool)
+	result := make([]Dependency, 0, len(deps))
+	for _, dep := range deps {
+		key := dep.Ecosystem + "/" + dep.Name
+		if dep.Name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, dep)
+	}
+	return result
+}
+
+func parsePackageJSON(data []byte) ([]Dependency, error) {
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	add := func(specs map[string]string, dev bool) {
+		names := make([]string, 0, len(specs))
+		for name := range specs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			spec := strings.TrimSpace(specs[name])
+			// Skip git, file, workspace, alias, and tarball specs
+			if strings.Contains(spec, ":") || strings.Contains(spec, "/") {
+				continue
+			}
+			deps = append(deps, Dependency{
+				Ecosystem:  "npm",
+				Name:       name,
+				Version:    npmExactVersion(spec),
+				Constraint: spec,
+				Dev:        dev,
+			})
+		}
+	}
+	add(pkg.Dependencies, false)
+	add(pkg.DevDependencies, true)
+
+	return deps, nil
+}
+
+var npmExactRe = regexp.MustCompile(`^=?v?(\d+\.\d+\.\d+(?:[-+][0-9A-Za-z.-]+)?)$`)
+
+func npmExactVersion(spec string) string {
+	if m := npmExactRe.FindStringSubmatch(spec); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func parseGoMod(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	inRequire := false
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		indirect := strings.Contains(line, "// indirect")
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		switch {
+		case line == "require (":
+			inRequire = true
+			continue
+		case inRequire && line == ")":
+			inRequire = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require"))
+		case !inRequire:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || indirect {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Ecosystem:  "go",
+			Name:       fields[0],
+			Version:    fields[1],
+			Constraint: fields[1],
+		})
+	}
+
+	return deps, nil
+}
+
+var (
+	cargoSectionRe    = regexp.MustCompile(`^(?:target\..+\.)?((?:dev-|build-)?dependencies)$`)
+	cargoDepSectionRe = regexp.MustCompile(`^(?:target\..+\.)?((?:dev-|build-)?dependencies)\.([A-Za-z0-9_-]+)$`)
+)
+
+func parseCargoToml(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+
+	// Dependencies declared as [dependencies.name] tables collect their keys here
+	tables := make(map[string]*Dependency)
+	var tableOrder []string
+
+	for _, entry := range tomlEntries(data) {
+		if m := cargoDepSectionRe.FindStringSubmatch(entry.Section); m != nil {
+			dep, ok := tables[entry.Section]
+			if !ok {
+				dep = &Dependency{Ecosystem: "rust", Name: m[2], Dev: m[1] == "dev-dependencies"}
+				tables[entry.Section] = dep
+				tableOrder = append(tableOrder, entry.Section)
+			}
+			switch entry.Key {
+			case "version":
+				dep.Constraint, _ = tomlString(entry.Value)
+			case "package":
+				dep.Name, _ = tomlString(entry.Value)
+			case "path", "git":
+				dep.Name = ""
+			}
+			continue
+		}
+
+		m := cargoSectionRe.FindStringSubmatch(entry.Section)
+		if m == nil {
+			continue
+		}
+
+		dep := Dependency{Ecosystem: "rust", Name: entry.Key, Dev: m[1] == "dev-dependencies"}
+		if constraint, ok := tomlString(entry.Value); ok {
+			dep.Constraint = constraint
+		} else {
+			if tomlInlineField(entry.Value, "path") != "" || tomlInlineField(entry.Value, "git") != "" {
+				continue
+			}
+			dep.Constraint = tomlInlineField(entry.Value, "version")
+			if pkg := tomlInlineField(entry.Value, "package"); pkg != "" {
+				dep.Name = pkg
+			}
+		}
+		dep.Version = cargoExactVersion(dep.Constraint)
+		deps = append(deps, dep)
+	}
+
+	for _, section := range tableOrder {
+		dep := tables[section]
+		dep.Version = cargoExactVersion(dep.Constraint)
+		deps = append(deps, *dep)
+	}
+
+	return deps, nil
+}
+
+// cargoExactVersion returns the version of an "=x.y.z" requirement; a bare
+// "x.y.z" in Cargo means a caret range
+func cargoExactVersion(constraint string) string {
+	constraint = strings.TrimSpace(constraint)
+	if !strings.HasPrefix(constraint, "=") {
+		return ""
+	}
+	return npmExactVersion(constraint)
+}
+
+func parsePyproject(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+
+	for _, entry := range tomlEntries(data) {
+		switch {
+		case entry.Section == "project" && entry.Key == "dependencies":
+			for _, req := range tomlArrayStrings(entry.Value) {
+				if dep, ok := parseRequirement(req); ok {
+					deps = append(deps, dep)
+				}
+			}
+
+		case entry.Section == "tool.poetry.dependencies" ||
+			entry.Section == "tool.poetry.dev-dependencies" ||
+			(strings.HasPrefix(entry.Section, "tool.poetry.group.") && strings.HasSuffix(entry.Section, ".dependencies")):
+			if entry.Key == "python" {
+				continue
+			}
+			constraint, ok := tomlString(entry.Value)
+			if !ok {
+				constraint = tomlInlineField(entry.Value, "version")
+			}
+			dev := entry.Section != "tool.poetry.dependencies" && entry.Section != "tool.poetry.group.main.dependencies"
+			deps = append(deps, Dependency{
+				Ecosystem:  "python",
+				Name:       entry.Key,
+				Version:    npmExactVersion(constraint),
+				Constraint: constraint,
+				Dev:        dev,
+			})
+		}
+	}
+
+	return deps, nil
+}
+
+func parseRequirements(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	for _, line := range strings.Split(string(data), "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		// Skip options (-r, -e, --index-url) and direct URLs
+		if line == "" || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+		if dep, ok := parseRequirement(line); ok {
+			deps = append(deps, dep)
+		}
+	}
+	return deps, nil
+}
+
+var requirementRe = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(.*)$`)
+
+// parseRequirement parses a PEP 508 requirement such as "requests[socks]>=2.31; python_version>'3.8'"
+func parseRequirement(req string) (Dependency, bool) {
+	if idx := strings.Index(req, ";"); idx >= 0 {
+		req = req[:idx]
+	}
+	m := requirementRe.FindStringSubmatch(strings.TrimSpace(req))
+	if m == nil || strings.Contains(m[2], "@") {
+		return Dependency{}, false
+	}
+
+	constraint := strings.TrimSpace(strings.Trim(strings.TrimSpace(m[2]), "()"))
+	dep := Dependency{Ecosystem: "python", Name: m[1], Constraint: constraint}
+	if strings.HasPrefix(constraint, "==") && !strings.ContainsAny(constraint, ",*") {
+		dep.Version = strings.TrimSpace(strings.TrimPrefix(constraint, "=="))
+	}
+	return dep, true
+}
+
+var (
+	gemRe       = regexp.MustCompile(`^gem\s+['"]([^'"]+)['"]((?:\s*,\s*['"][^'"]*['"])*)`)
+	quotedRe    = regexp.MustCompile(`['"]([^'"]*)['"]`)
+	blockOpenRe = regexp.MustCompile(`\bdo\s*(?:\|[^|]*\|)?$`)
+)
+
+func parseGemfile(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	// Each open block records whether it is a development/test group
+	var blocks []bool
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		inDev := len(blocks) > 0 && blocks[len(blocks)-1]
+
+		switch {
+		case blockOpenRe.MatchString(line):
+			dev := inDev || (strings.HasPrefix(line, "group") &&
+				(strings.Contains(line, ":development") || strings.Contains(line, ":test")))
+			blocks = append(blocks, dev)
+			continue
+		case line == "end":
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+
+		m := gemRe.FindStringSubmatch(line)
+		if m == nil || strings.Contains(line, "path:") || strings.Contains(line, "git:") || strings.Contains(line, "github:") {
+			continue
+		}
+
+		var constraints []string
+		for _, q := range quotedRe.FindAllStringSubmatch(m[2], -1) {
+			constraints = append(constraints, q[1])
+		}
+		dep := Dependency{
+			Ecosystem:  "ruby",
+			Name:       m[1],
+			Constraint: strings.Join(constraints, ", "),
+			Dev:        inDev || strings.Contains(line, ":development") || strings.Contains(line, ":test"),
+		}
+		if len(constraints) == 1 {
+			dep.Version = npmExactVersion(strings.ReplaceAll(constraints[0], " ", ""))
+		}
+		deps = append(deps, dep)
+	}
+
+	return deps, nil
+}
+
+var mixDepRe = regexp.MustCompile(`\{:([a-z0-9_]+)\s*,\s*"([^"]*)"([^}]*)\}`)
+
+func parseMixExs(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	for _, m := range mixDepRe.FindAllStringSubmatch(string(data), -1) {
+		only := ""
+		if idx := strings.Index(m[3], "only:"); idx >= 0 {
+			only = m[3][idx:]
+		}
+		dep := Dependency{
+			Ecosystem:  "hex",
+			Name:       m[1],
+			Constraint: m[2],
+			Dev:        only != "" && !strings.Contains(only, ":prod"),
+		}
+		if strings.HasPrefix(m[2], "==") {
+			dep.Version = strings.TrimSpace(strings.TrimPrefix(m[2], "=="))
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+var podRe = regexp.MustCompile(`^pod\s+['"]([^'"]+)['"](?:\s*,\s*['"]([^'"]+)['"])?`)
+
+func parsePodfile(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	for _, line := range strings.Split(string(data), "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+
+		m := podRe.FindStringSubmatch(line)
+		if m == nil || strings.Contains(line, ":path") || strings.Contains(line, ":git") || strings.Contains(line, ":podspec") {
+			continue
+		}
+
+		// Subspecs ("Firebase/Analytics") are published with their root pod
+		name := strings.SplitN(m[1], "/", 2)[0]
+		deps = append(deps, Dependency{
+			Ecosystem:  "cocoapods",
+			Name:       name,
+			Version:    npmExactVersion(strings.ReplaceAll(m[2], " ", "")),
+			Constraint: m[2],
+		})
+	}
+	return deps, nil
+}
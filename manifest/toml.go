@@ -0,0 +1,117 @@
+package manifest
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tomlEntry is a key/value pair from a TOML document together with the table it belongs to
+type tomlEntry struct {
+	Section string
+	Key     string
+	Value   string
+}
+
+// tomlEntries is a minimal line-based TOML reader covering what dependency
+// manifests use: [tables], key = value pairs, and arrays or inline tables
+// that span several lines. Values are returned unparsed.
+func tomlEntries(data []byte) []tomlEntry {
+	var entries []tomlEntry
+	section := ""
+
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := stripTomlComment(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.Trim(line, "[] ")
+			section = strings.ReplaceAll(section, `"`, "")
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		value = strings.TrimSpace(value)
+
+		// Join multi-line arrays and inline tables
+		for depth := tomlNesting(value); depth > 0 && i+1 < len(lines); depth = tomlNesting(value) {
+			i++
+			value += " " + stripTomlComment(lines[i])
+		}
+
+		entries = append(entries, tomlEntry{Section: section, Key: key, Value: value})
+	}
+
+	return entries
+}
+
+// stripTomlComment removes a trailing comment that is not inside a string
+func stripTomlComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// tomlNesting returns the number of unclosed brackets and braces in a value
+func tomlNesting(value string) int {
+	depth := 0
+	var quote rune
+	for _, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// tomlString unquotes a basic or literal string value
+func tomlString(value string) (string, bool) {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1], true
+	}
+	return "", false
+}
+
+// tomlInlineField returns a string field from an inline table such as { version = "1.0", features = [...] }
+func tomlInlineField(value, field string) string {
+	re := regexp.MustCompile(`(?:^|[{,\s])` + regexp.QuoteMeta(field) + `\s*=\s*["']([^"']*)["']`)
+	if m := re.FindStringSubmatch(value); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// tomlArrayStrings returns the string items of an array value
+func tomlArrayStrings(value string) []string {
+	var items []string
+	for _, m := range quotedRe.FindAllStringSubmatch(value, -1) {
+		items = append(items, m[1])
+	}
+	return items
+}
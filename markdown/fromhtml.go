@@ -0,0 +1,371 @@
+package markdown
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	htmlSpacePattern    = regexp.MustCompile(`[ \t\r\n\f]+`)
+	htmlLanguagePattern = regexp.MustCompile(`(?:^|\s)(?:language|lang|highlight-source)-([\w+#-]+)`)
+)
+
+// FromHTML converts a rendered documentation page into markdown: headings,
+// paragraphs, code blocks, lists, block quotes, tables, definition lists,
+// and inline code, emphasis, and links. Links are resolved against baseURL
+// when it is set; relative links are otherwise reduced to their text.
+// Scripts, styles, images, and forms are dropped.
+func FromHTML(src, baseURL string) string {
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		return ""
+	}
+
+	c := &htmlConverter{}
+	if baseURL != "" {
+		if base, err := url.Parse(baseURL); err == nil && base.IsAbs() {
+			c.base = base
+		}
+	}
+	return strings.TrimSpace(c.blocks(doc)) + "\n"
+}
+
+type htmlConverter struct {
+	base *url.URL
+}
+
+// blocks renders the children of n as markdown blocks separated by blank
+// lines. Runs of inline content between block elements become paragraphs.
+func (c *htmlConverter) blocks(n *html.Node) string {
+	var out []string
+	var inline strings.Builder
+	flush := func() {
+		if text := cleanInline(inline.String()); text != "" {
+			out = append(out, text)
+		}
+		inline.Reset()
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if !isBlockElement(child) {
+			inline.WriteString(c.inline(child))
+			continue
+		}
+		flush()
+		if block := c.block(child); strings.TrimSpace(block) != "" {
+			out = append(out, block)
+		}
+	}
+	flush()
+	return strings.Join(out, "\n\n")
+}
+
+func (c *htmlConverter) block(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := collapseSpaces(c.inline(n))
+		if text == "" {
+			return ""
+		}
+		level := int(n.Data[1] - '0')
+		return strings.Repeat("#", level) + " " + text
+	case atom.Pre:
+		return codeFence(htmlText(n), codeLanguage(n))
+	case atom.Ul, atom.Ol:
+		return c.list(n)
+	case atom.Blockquote:
+		return prefixLines(c.blocks(n), "> ", ">")
+	case atom.Table:
+		return c.table(n)
+	case atom.Dl:
+		return c.definitions(n)
+	case atom.Hr:
+		return "---"
+	case atom.Head, atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Svg, atom.Form, atom.Iframe, atom.Button:
+		return ""
+	default:
+		return c.blocks(n)
+	}
+}
+
+// inline renders n as inline markdown. Whitespace is collapsed here and
+// trimmed when the surrounding paragraph is flushed.
+func (c *htmlConverter) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return htmlSpacePattern.ReplaceAllString(n.Data, " ")
+	case html.ElementNode, html.DocumentNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Svg, atom.Img, atom.Button, atom.Input:
+		return ""
+	case atom.Br:
+		return "\n"
+	case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+		return inlineCode(collapseSpaces(htmlText(n)))
+	case atom.Strong, atom.B:
+		return wrapInline(c.children(n), "**")
+	case atom.Em, atom.I, atom.Var:
+		return wrapInline(c.children(n), "*")
+	case atom.A:
+		text := c.children(n)
+		href := c.resolve(attr(n, "href"))
+		if href == "" || strings.TrimSpace(text) == "" {
+			return text
+		}
+		return "[" + strings.TrimSpace(text) + "](" + href + ")"
+	default:
+		return c.children(n)
+	}
+}
+
+func (c *htmlConverter) children(n *html.Node) string {
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(c.inline(child))
+	}
+	return b.String()
+}
+
+// resolve returns an absolute http(s) link for href, or "" when it has
+// none: fragments, scripts, and relative links without a base URL
+func (c *htmlConverter) resolve(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if !u.IsAbs() {
+		if c.base == nil {
+			return ""
+		}
+		u = c.base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return strings.ReplaceAll(u.String(), " ", "%20")
+}
+
+func (c *htmlConverter) list(n *html.Node) string {
+	ordered := n.DataAtom == atom.Ol
+	number := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil && ordered {
+		number = start
+	}
+
+	var items []string
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		body := c.blocks(li)
+		indented := prefixLines(body, strings.Repeat(" ", len(marker)), "")
+		items = append(items, marker+strings.TrimLeft(indented, " "))
+	}
+	return strings.Join(items, "\n")
+}
+
+func (c *htmlConverter) table(n *html.Node) string {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch child.DataAtom {
+			case atom.Thead, atom.Tbody, atom.Tfoot:
+				walk(child)
+			case atom.Tr:
+				var row []string
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom == atom.Th || cell.DataAtom == atom.Td {
+						text := collapseSpaces(c.inline(cell))
+						row = append(row, strings.ReplaceAll(text, "|", `\|`))
+					}
+				}
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	var b strings.Builder
+	for i, row := range rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// definitions renders a definition list as bold terms, each followed by
+// its definitions
+func (c *htmlConverter) definitions(n *html.Node) string {
+	var out []string
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		switch child.DataAtom {
+		case atom.Dt:
+			// A term that is bold already, as in <dt><b>-n</b></dt>, is
+			// not wrapped again
+			term := collapseSpaces(c.inline(child))
+			switch {
+			case term == "":
+			case strings.HasPrefix(term, "**") && strings.HasSuffix(term, "**") && strings.Count(term, "**") == 2:
+				out = append(out, term)
+			default:
+				out = append(out, "**"+term+"**")
+			}
+		case atom.Dd:
+			if body := c.blocks(child); body != "" {
+				out = append(out, body)
+			}
+		case atom.Div:
+			// HTML allows grouping dt/dd pairs in divs
+			if body := c.definitions(child); body != "" {
+				out = append(out, body)
+			}
+		}
+	}
+	return strings.Join(out, "\n\n")
+}
+
+func isBlockElement(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch n.DataAtom {
+	case atom.Html, atom.Head, atom.Body, atom.Main, atom.Article, atom.Section, atom.Header, atom.Footer,
+		atom.Nav, atom.Aside, atom.Div, atom.P, atom.Figure, atom.Figcaption, atom.Details, atom.Summary,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Pre, atom.Ul, atom.Ol, atom.Li,
+		atom.Blockquote, atom.Table, atom.Dl, atom.Dt, atom.Dd, atom.Hr,
+		atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Form, atom.Iframe:
+		return true
+	}
+	return false
+}
+
+// cleanInline trims a paragraph's lines and drops its blank ones
+func cleanInline(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// htmlText returns the text of n with its whitespace kept, as inside pre
+func htmlText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	if n.DataAtom == atom.Br {
+		return "\n"
+	}
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(htmlText(child))
+	}
+	return b.String()
+}
+
+// codeLanguage reads the language of a pre block from its data-language
+// attribute or a language-* class on it or its code element
+func codeLanguage(n *html.Node) string {
+	if lang := attr(n, "data-language"); lang != "" {
+		return lang
+	}
+	for _, node := range []*html.Node{n, n.FirstChild} {
+		if node == nil || node.Type != html.ElementNode {
+			continue
+		}
+		if m := htmlLanguagePattern.FindStringSubmatch(attr(node, "class")); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func codeFence(code, lang string) string {
+	code = strings.Trim(code, "\n")
+	if strings.TrimSpace(code) == "" {
+		return ""
+	}
+	fence := "```"
+	if strings.Contains(code, "```") {
+		fence = "~~~~"
+	}
+	return fence + strings.ToLower(lang) + "\n" + code + "\n" + fence
+}
+
+func inlineCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	if strings.Contains(text, "`") {
+		return "`` " + text + " ``"
+	}
+	return "`" + text + "`"
+}
+
+// wrapInline wraps text in an emphasis marker, keeping surrounding spaces
+// outside it so the markers stay adjacent to the text
+func wrapInline(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := strings.Index(text, trimmed)
+	return text[:start] + marker + trimmed + marker + text[start+len(trimmed):]
+}
+
+// prefixLines prefixes each line of s, using blank for empty lines
+func prefixLines(s, prefix, blank string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = blank
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
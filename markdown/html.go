@@ -0,0 +1,234 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingPattern      = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItemPattern     = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	tableDividerPattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	ruleLinePattern     = regexp.MustCompile(`^(-\s*){3,}$|^(\*\s*){3,}$|^(_\s*){3,}$`)
+
+	inlineCodePattern = regexp.MustCompile("`([^`]+)`")
+	imagePattern      = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	linkPattern       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	autoLinkPattern   = regexp.MustCompile(`&lt;(https?://[^\s&]+)&gt;`)
+	boldPattern       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	italicPattern     = regexp.MustCompile(`(^|[^\w*])\*([^*\s][^*]*?)\*`)
+	nonWordPattern    = regexp.MustCompile(`[^\w-]+`)
+)
+
+// ToHTML renders the markdown produced by the fetchers as HTML: ATX
+// headings, paragraphs, fenced code, nested lists, block quotes, tables,
+// rules, and inline code, emphasis, links, and images. Raw HTML in the
+// source is escaped, and links other than http(s), mailto, and relative
+// ones are dropped.
+func ToHTML(src string) string {
+	r := &htmlRenderer{}
+	r.render(strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n"))
+	return r.out.String()
+}
+
+type htmlRenderer struct {
+	out       strings.Builder
+	paragraph []string
+	// lists holds the open lists, innermost last
+	lists []openList
+}
+
+type openList struct {
+	indent  int
+	ordered bool
+}
+
+func (r *htmlRenderer) render(lines []string) {
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			r.flush()
+			fence := trimmed[:3]
+			lang := strings.Fields(strings.TrimLeft(trimmed, "`~"))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			if len(lang) > 0 {
+				r.out.WriteString(`<pre><code class="language-` + html.EscapeString(lang[0]) + `">`)
+			} else {
+				r.out.WriteString("<pre><code>")
+			}
+			r.out.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			r.out.WriteString("</code></pre>\n")
+
+		case trimmed == "":
+			r.flushParagraph()
+			// A blank line ends a list unless the list continues after it
+			if len(r.lists) > 0 && (i+1 >= len(lines) || !listItemPattern.MatchString(lines[i+1])) {
+				r.closeLists(-1)
+			}
+
+		case headingPattern.MatchString(trimmed):
+			r.flush()
+			m := headingPattern.FindStringSubmatch(trimmed)
+			level := string(rune('0' + len(m[1])))
+			id := strings.Trim(nonWordPattern.ReplaceAllString(strings.ToLower(m[2]), "-"), "-")
+			r.out.WriteString("<h" + level + ` id="` + id + `">` + inline(m[2]) + "</h" + level + ">\n")
+
+		case ruleLinePattern.MatchString(trimmed):
+			r.flush()
+			r.out.WriteString("<hr>\n")
+
+		case listItemPattern.MatchString(line):
+			r.flushParagraph()
+			m := listItemPattern.FindStringSubmatch(line)
+			r.listItem(len(strings.ReplaceAll(m[1], "\t", "    ")), m[2][0] >= '0' && m[2][0] <= '9', m[3])
+
+		case strings.HasPrefix(trimmed, ">"):
+			r.flush()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			i--
+			r.out.WriteString("<blockquote>\n" + ToHTML(strings.Join(quote, "\n")) + "</blockquote>\n")
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && tableDividerPattern.MatchString(lines[i+1]):
+			r.flush()
+			r.out.WriteString("<table>\n<thead><tr>")
+			for _, cell := range tableCells(trimmed) {
+				r.out.WriteString("<th>" + inline(cell) + "</th>")
+			}
+			r.out.WriteString("</tr></thead>\n<tbody>\n")
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				r.out.WriteString("<tr>")
+				for _, cell := range tableCells(strings.TrimSpace(lines[i])) {
+					r.out.WriteString("<td>" + inline(cell) + "</td>")
+				}
+				r.out.WriteString("</tr>\n")
+			}
+			i--
+			r.out.WriteString("</tbody>\n</table>\n")
+
+		case len(r.lists) > 0 && strings.HasPrefix(line, " "):
+			// Continuation of the current list item
+			r.out.WriteString(" " + inline(trimmed))
+
+		default:
+			if len(r.lists) > 0 {
+				r.closeLists(-1)
+			}
+			r.paragraph = append(r.paragraph, trimmed)
+		}
+	}
+	r.flush()
+}
+
+func (r *htmlRenderer) listItem(indent int, ordered bool, text string) {
+	top := len(r.lists) - 1
+	switch {
+	case top < 0 || indent > r.lists[top].indent:
+		r.openList(indent, ordered)
+	default:
+		r.closeLists(indent)
+		top = len(r.lists) - 1
+		if top < 0 || r.lists[top].ordered != ordered {
+			r.closeLists(-1)
+			r.openList(indent, ordered)
+		} else {
+			r.out.WriteString("</li>\n")
+		}
+	}
+	r.out.WriteString("<li>" + inline(text))
+}
+
+func (r *htmlRenderer) openList(indent int, ordered bool) {
+	if ordered {
+		r.out.WriteString("\n<ol>\n")
+	} else {
+		r.out.WriteString("\n<ul>\n")
+	}
+	r.lists = append(r.lists, openList{indent: indent, ordered: ordered})
+}
+
+// closeLists closes lists nested deeper than indent (-1 closes them all)
+func (r *htmlRenderer) closeLists(indent int) {
+	for len(r.lists) > 0 && r.lists[len(r.lists)-1].indent > indent {
+		if r.lists[len(r.lists)-1].ordered {
+			r.out.WriteString("</li>\n</ol>\n")
+		} else {
+			r.out.WriteString("</li>\n</ul>\n")
+		}
+		r.lists = r.lists[:len(r.lists)-1]
+	}
+}
+
+func (r *htmlRenderer) flushParagraph() {
+	if len(r.paragraph) == 0 {
+		return
+	}
+	r.out.WriteString("<p>" + inline(strings.Join(r.paragraph, "\n")) + "</p>\n")
+	r.paragraph = nil
+}
+
+func (r *htmlRenderer) flush() {
+	r.flushParagraph()
+	r.closeLists(-1)
+}
+
+func tableCells(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	// Escaped pipes belong to the cell
+	row = strings.ReplaceAll(row, `\|`, "\x00")
+	cells := strings.Split(row, "|")
+	for i, cell := range cells {
+		cells[i] = strings.ReplaceAll(strings.TrimSpace(cell), "\x00", "|")
+	}
+	return cells
+}
+
+// inline renders inline markup. Code spans are set aside first so their
+// contents are not interpreted.
+func inline(text string) string {
+	var spans []string
+	text = inlineCodePattern.ReplaceAllStringFunc(text, func(m string) string {
+		spans = append(spans, "<code>"+html.EscapeString(m[1:len(m)-1])+"</code>")
+		return "\x00" + strconv.Itoa(len(spans)-1) + "\x00"
+	})
+
+	text = html.EscapeString(text)
+	text = imagePattern.ReplaceAllStringFunc(text, func(m string) string {
+		parts := imagePattern.FindStringSubmatch(m)
+		return `<img src="` + safeURL(parts[2]) + `" alt="` + parts[1] + `">`
+	})
+	text = linkPattern.ReplaceAllStringFunc(text, func(m string) string {
+		parts := linkPattern.FindStringSubmatch(m)
+		return `<a href="` + safeURL(parts[2]) + `">` + parts[1] + `</a>`
+	})
+	text = autoLinkPattern.ReplaceAllString(text, `<a href="$1">$1</a>`)
+	text = boldPattern.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = italicPattern.ReplaceAllString(text, "$1<em>$2</em>")
+
+	for i, span := range spans {
+		text = strings.Replace(text, "\x00"+strconv.Itoa(i)+"\x00", span, 1)
+	}
+	return text
+}
+
+// safeURL keeps http(s), mailto, and relative URLs; anything else (such as
+// javascript:) becomes "#". The URL is already HTML-escaped.
+func safeURL(u string) string {
+	lower := strings.ToLower(u)
+	if i := strings.IndexAny(lower, ":/?#"); i >= 0 && lower[i] == ':' {
+		if !strings.HasPrefix(lower, "http:") && !strings.HasPrefix(lower, "https:") && !strings.HasPrefix(lower, "mailto:") {
+			return "#"
+		}
+	}
+	return u
+}
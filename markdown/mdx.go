@@ -0,0 +1,158 @@
+// Package markdown converts upstream documentation sources into plain markdown
+// suitable for caching and returning from MCP tools.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Document is a converted documentation page
+type Document struct {
+	Title       string
+	Description string
+	Body        string
+}
+
+// MDXOptions controls how MDX sources are converted
+type MDXOptions struct {
+	// BaseURL is prepended to site-relative links (e.g., "https://react.dev")
+	BaseURL string
+	// DropBlocks lists JSX components whose whole block is removed (e.g., "PagesOnly")
+	DropBlocks []string
+}
+
+// mdxCallouts maps admonition components to the label rendered in their place
+var mdxCallouts = map[string]string{
+	"Pitfall":    "Pitfall",
+	"Note":       "Note",
+	"Wip":        "Work in progress",
+	"Canary":     "Canary",
+	"Deprecated": "Deprecated",
+	"DeepDive":   "Deep Dive",
+	"Gotcha":     "Gotcha",
+}
+
+var (
+	mdxCommentPattern   = regexp.MustCompile(`\{/\*.*?\*/\}`)
+	mdxBlockTagPattern  = regexp.MustCompile(`^\s*<(/?)([A-Z][\w.]*)\b[^>]*?(/?)>\s*$`)
+	mdxOpenTagPattern   = regexp.MustCompile(`^\s*<([A-Z][\w.]*)\b[^>]*$`)
+	mdxInlineTagPattern = regexp.MustCompile(`</?[A-Z][\w.]*\b[^>]*?/?>`)
+	mdxRelLinkPattern   = regexp.MustCompile(`\]\((/[^)]*)\)`)
+	mdxImportPattern    = regexp.MustCompile(`^(import|export)\s`)
+	blankLinesPattern   = regexp.MustCompile(`\n{3,}`)
+)
+
+// FromMDX converts an MDX page (markdown with frontmatter and JSX components)
+// into plain markdown. Admonition components become bold labels, other
+// components are unwrapped, and code fences keep only their language.
+func FromMDX(src string, opts MDXOptions) Document {
+	var doc Document
+
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	if strings.HasPrefix(src, "---") {
+		if parts := strings.SplitN(src, "---", 3); len(parts) == 3 {
+			var meta struct {
+				Title       string `yaml:"title"`
+				Description string `yaml:"description"`
+			}
+			if err := yaml.Unmarshal([]byte(parts[1]), &meta); err == nil {
+				doc.Title = meta.Title
+				doc.Description = meta.Description
+			}
+			src = parts[2]
+		}
+	}
+
+	drop := make(map[string]bool, len(opts.DropBlocks))
+	for _, name := range opts.DropBlocks {
+		drop[name] = true
+	}
+
+	var out strings.Builder
+	inCode := false
+	dropDepth := 0
+	inOpenTag := false
+
+	for _, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if dropDepth == 0 {
+				if !inCode {
+					// Keep only the language, dropping meta such as filenames or line highlights
+					lang := strings.Fields(strings.TrimPrefix(trimmed, "```"))
+					out.WriteString("```")
+					if len(lang) > 0 {
+						out.WriteString(strings.SplitN(lang[0], "{", 2)[0])
+					}
+					out.WriteString("\n")
+				} else {
+					out.WriteString("```\n")
+				}
+			}
+			inCode = !inCode
+			continue
+		}
+
+		if inCode {
+			if dropDepth == 0 {
+				out.WriteString(line)
+				out.WriteString("\n")
+			}
+			continue
+		}
+
+		// Skip the remainder of a multi-line component tag
+		if inOpenTag {
+			if strings.Contains(line, ">") {
+				inOpenTag = false
+			}
+			continue
+		}
+
+		if m := mdxBlockTagPattern.FindStringSubmatch(line); m != nil {
+			closing, name, selfClosing := m[1] == "/", m[2], m[3] == "/"
+			switch {
+			case drop[name] && !selfClosing && !closing:
+				dropDepth++
+			case drop[name] && closing:
+				if dropDepth > 0 {
+					dropDepth--
+				}
+			case dropDepth == 0 && !closing && !selfClosing:
+				if label, ok := mdxCallouts[name]; ok {
+					out.WriteString("\n**" + label + ":**\n\n")
+				}
+			}
+			continue
+		}
+
+		if m := mdxOpenTagPattern.FindStringSubmatch(line); m != nil {
+			inOpenTag = true
+			continue
+		}
+
+		if dropDepth > 0 {
+			continue
+		}
+
+		if mdxImportPattern.MatchString(line) {
+			continue
+		}
+
+		line = mdxCommentPattern.ReplaceAllString(line, "")
+		line = mdxInlineTagPattern.ReplaceAllString(line, "")
+		if opts.BaseURL != "" {
+			line = mdxRelLinkPattern.ReplaceAllString(line, "]("+strings.TrimSuffix(opts.BaseURL, "/")+"$1)")
+		}
+
+		out.WriteString(strings.TrimRight(line, " \t"))
+		out.WriteString("\n")
+	}
+
+	doc.Body = strings.TrimSpace(blankLinesPattern.ReplaceAllString(out.String(), "\n\n"))
+	return doc
+}
@@ -0,0 +1,258 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	rstDirectivePattern    = regexp.MustCompile(`^\.\.\s+(?:\|([^|]+)\|\s+)?([A-Za-z][\w-]*)::\s*(.*)$`)
+	rstTargetPattern       = regexp.MustCompile(`^\.\.\s+_([^:]+):\s*(\S*)\s*$`)
+	rstEnumeratedPattern   = regexp.MustCompile(`^(\s*)(?:#|\d+)\.\s+`)
+	rstLiteralPattern      = regexp.MustCompile("``([^`]+)``")
+	rstNamedLinkPattern    = regexp.MustCompile("`([^`<]+?)\\s*<([^>`]+)>`__?")
+	rstReferencePattern    = regexp.MustCompile("`([^`]+)`__?")
+	rstWordRefPattern      = regexp.MustCompile(`\b([\w-]+)__?\b`)
+	rstRolePattern         = regexp.MustCompile(":[\\w:.-]+:`([^`]+)`")
+	rstRoleTitlePattern    = regexp.MustCompile(`^(.+?)\s*<[^>]+>$`)
+	rstSubstitutionPattern = regexp.MustCompile(`\|([^|\s][^|]*)\|_{0,2}`)
+)
+
+// rstCallouts maps admonition directives to the label rendered in their place
+var rstCallouts = map[string]string{
+	"note":           "Note",
+	"tip":            "Tip",
+	"hint":           "Hint",
+	"important":      "Important",
+	"warning":        "Warning",
+	"caution":        "Caution",
+	"danger":         "Danger",
+	"attention":      "Attention",
+	"seealso":        "See also",
+	"deprecated":     "Deprecated",
+	"versionadded":   "Added in version",
+	"versionchanged": "Changed in version",
+}
+
+// FromRST converts the reStructuredText common in package READMEs into
+// markdown: section titles become headings by order of appearance, literal
+// and code blocks become fences, admonitions become bold labels, and
+// inline literals, links, and roles become their markdown forms. Images,
+// comments, and other directives are dropped.
+func FromRST(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	// Named targets (".. _Django: https://...") resolve `Django`_ references
+	targets := make(map[string]string)
+	for _, line := range lines {
+		if m := rstTargetPattern.FindStringSubmatch(line); m != nil && m[2] != "" {
+			targets[strings.ToLower(strings.TrimSpace(m[1]))] = m[2]
+		}
+	}
+
+	var out []string
+	// levels holds the title adornment styles in order of first use
+	var levels []string
+	literalNext := false
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		// An indented block after "::" or a code directive is code
+		if literalNext && trimmed != "" {
+			literalNext = false
+			if indentOf(line) > 0 {
+				block, next := rstIndentedBlock(lines, i)
+				out = append(out, "```", strings.Join(block, "\n"), "```", "")
+				i = next - 1
+				continue
+			}
+		}
+
+		// Section title with an overline: ===\nTitle\n===
+		if isRSTAdornment(trimmed) && i+2 < len(lines) &&
+			strings.TrimSpace(lines[i+1]) != "" && strings.TrimSpace(lines[i+2]) == trimmed {
+			title := strings.TrimSpace(lines[i+1])
+			out = append(out, rstHeading(&levels, "over"+trimmed[:1], title), "")
+			i += 2
+			continue
+		}
+
+		// Section title with an underline: Title\n=====
+		if trimmed != "" && indentOf(line) == 0 && i+1 < len(lines) {
+			under := strings.TrimSpace(lines[i+1])
+			if isRSTAdornment(under) && len(under) >= len([]rune(trimmed)) && !isRSTAdornment(trimmed) {
+				out = append(out, rstHeading(&levels, under[:1], rstInline(trimmed, targets)), "")
+				i++
+				continue
+			}
+		}
+
+		if strings.HasPrefix(trimmed, "..") && indentOf(line) == 0 {
+			m := rstDirectivePattern.FindStringSubmatch(trimmed)
+			block, next := rstIndentedBlock(lines, i+1)
+			i = next - 1
+
+			if m == nil || m[1] != "" {
+				// Comments, link targets, and substitution definitions
+				continue
+			}
+
+			name, arg := strings.ToLower(m[2]), strings.TrimSpace(m[3])
+			switch {
+			case name == "code-block" || name == "code" || name == "sourcecode":
+				code := rstDirectiveBody(block)
+				out = append(out, "```"+arg, strings.Join(code, "\n"), "```", "")
+			case rstCallouts[name] != "":
+				text := strings.TrimSpace(arg + " " + strings.Join(rstDirectiveBody(block), " "))
+				out = append(out, "**"+rstCallouts[name]+":** "+rstInline(collapseSpaces(text), targets), "")
+			}
+			continue
+		}
+
+		if strings.HasSuffix(trimmed, "::") {
+			literalNext = true
+			if trimmed == "::" {
+				continue
+			}
+			line = strings.TrimSuffix(line, ":")
+			if strings.HasSuffix(strings.TrimSpace(line), " :") {
+				line = strings.TrimSuffix(strings.TrimSpace(line), " :")
+			}
+		}
+
+		line = rstEnumeratedPattern.ReplaceAllString(line, "${1}1. ")
+		out = append(out, rstInline(line, targets))
+	}
+
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(out, "\n"), "\n\n")) + "\n"
+}
+
+// rstHeading returns a markdown heading for a title whose adornment style
+// is first seen at the next level
+func rstHeading(levels *[]string, style, title string) string {
+	level := 0
+	for level < len(*levels) && (*levels)[level] != style {
+		level++
+	}
+	if level == len(*levels) {
+		*levels = append(*levels, style)
+	}
+	return strings.Repeat("#", min(level+1, 6)) + " " + title
+}
+
+// rstIndentedBlock returns the indented lines starting at start, dedented,
+// and the index of the first line after them. Blank lines inside the block
+// are kept; trailing ones are not.
+func rstIndentedBlock(lines []string, start int) ([]string, int) {
+	end := start
+	for end < len(lines) && (strings.TrimSpace(lines[end]) == "" || indentOf(lines[end]) > 0) {
+		end++
+	}
+	block := lines[start:end]
+	for len(block) > 0 && strings.TrimSpace(block[len(block)-1]) == "" {
+		block = block[:len(block)-1]
+	}
+
+	indent := -1
+	for _, l := range block {
+		if strings.TrimSpace(l) != "" && (indent < 0 || indentOf(l) < indent) {
+			indent = indentOf(l)
+		}
+	}
+	dedented := make([]string, len(block))
+	for i, l := range block {
+		if len(l) >= indent && indent > 0 {
+			l = l[indent:]
+		}
+		dedented[i] = strings.TrimRight(l, " \t")
+	}
+	return dedented, end
+}
+
+// rstDirectiveBody drops a directive's leading options (":linenos:") and
+// the blank lines around its content
+func rstDirectiveBody(block []string) []string {
+	for len(block) > 0 && (strings.TrimSpace(block[0]) == "" || strings.HasPrefix(strings.TrimSpace(block[0]), ":")) {
+		block = block[1:]
+	}
+	return block
+}
+
+// rstInline converts inline markup: double-backquoted literals,
+// `text <url>`_ links, `name`_ references, and :role:`text`. Substitution
+// references (|name|) are dropped since they are nearly always badges.
+func rstInline(line string, targets map[string]string) string {
+	line = rstLiteralPattern.ReplaceAllString(line, "`$1`")
+	line = rstNamedLinkPattern.ReplaceAllString(line, "[$1]($2)")
+	line = rstRolePattern.ReplaceAllStringFunc(line, func(m string) string {
+		text := rstRolePattern.FindStringSubmatch(m)[1]
+		if t := rstRoleTitlePattern.FindStringSubmatch(text); t != nil {
+			text = t[1]
+		}
+		return "`" + strings.TrimPrefix(text, "~") + "`"
+	})
+	line = rstReferencePattern.ReplaceAllStringFunc(line, func(m string) string {
+		if !strings.HasSuffix(m, "_") {
+			// Interpreted text without a role, usually code
+			return m
+		}
+		text := rstReferencePattern.FindStringSubmatch(m)[1]
+		if url := targets[strings.ToLower(text)]; url != "" {
+			return "[" + text + "](" + url + ")"
+		}
+		return text
+	})
+	line = rstWordRefPattern.ReplaceAllStringFunc(line, func(m string) string {
+		word := strings.TrimRight(m, "_")
+		if url := targets[strings.ToLower(word)]; url != "" {
+			return "[" + word + "](" + url + ")"
+		}
+		return m
+	})
+	stripped := rstSubstitutionPattern.ReplaceAllString(line, "")
+	if indentOf(line) == 0 {
+		stripped = strings.TrimLeft(stripped, " ")
+	}
+	return stripped
+}
+
+// isRSTAdornment reports whether line is a title over- or underline: one
+// punctuation character repeated at least three times
+func isRSTAdornment(line string) bool {
+	line = strings.TrimSpace(line)
+	if len(line) < 3 || !strings.ContainsRune("=-~^\"'*+#_:.`", rune(line[0])) {
+		return false
+	}
+	return strings.Count(line, line[:1]) == len(line)
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// DemoteHeadings pushes every ATX heading outside code fences down by
+// levels, so a README's "# Title" can sit under a page's own headings
+func DemoteHeadings(src string, levels int) string {
+	lines := strings.Split(src, "\n")
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			lines[i] = strings.Repeat("#", min(len(m[1])+levels, 6)) + " " + m[2]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
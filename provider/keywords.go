@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxExtractedKeywords caps the keywords extracted from one topic
+const maxExtractedKeywords = 64
+
+var (
+	headingPattern    = regexp.MustCompile(`(?m)^#{1,6}\s+(.+?)\s*#*$`)
+	inlineCodePattern = regexp.MustCompile("`([^`\n]{3,60})`")
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*(?:\(\))?$`)
+	importPathPattern = regexp.MustCompile(`\b[a-z0-9-]+(?:\.[a-z0-9-]+)*\.[a-z]{2,}(?:/[A-Za-z0-9._~-]+)+`)
+	// Declarations and imports in Go, Python, JavaScript, and Rust code
+	declarationPattern = regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?:pub\s+)?(?:func|type|def|class|function|interface|struct|enum|trait|fn)\s+(?:\([^)]*\)\s*)?([A-Za-z_][A-Za-z0-9_]*)`)
+	importPattern      = regexp.MustCompile(`(?m)(?:^\s*import\s+(?:[A-Za-z_.]+\s+)?"([^"]+)"|^\s*"([^"\s]+)"$|^\s*(?:from|import)\s+([A-Za-z_][A-Za-z0-9_.]*)|require\(\s*['"]([^'"]+)['"]\s*\)|from\s+['"]([^'"]+)['"]|^\s*use\s+([A-Za-z_][A-Za-z0-9_:]*))`)
+	codeFencePattern   = regexp.MustCompile("(?s)```[^\n]*\n(.*?)```")
+)
+
+// ExtractKeywords picks search keywords out of markdown content:
+// its headings, identifiers in inline code, declared and imported names in
+// code blocks, and module import paths. Keywords are lowercased and unique.
+func ExtractKeywords(content string) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	add := func(k string) {
+		k = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(k, "()")))
+		if len(k) < 3 || seen[k] || len(keywords) >= maxExtractedKeywords {
+			return
+		}
+		seen[k] = true
+		keywords = append(keywords, k)
+	}
+
+	for _, m := range headingPattern.FindAllStringSubmatch(content, -1) {
+		add(strings.Trim(m[1], "`*_"))
+	}
+
+	for _, m := range importPathPattern.FindAllString(content, -1) {
+		add(strings.TrimRight(m, ".,)"))
+	}
+
+	for _, block := range codeFencePattern.FindAllStringSubmatch(content, -1) {
+		for _, m := range declarationPattern.FindAllStringSubmatch(block[1], -1) {
+			add(m[1])
+		}
+		for _, m := range importPattern.FindAllStringSubmatch(block[1], -1) {
+			for _, group := range m[1:] {
+				if group != "" {
+					add(group)
+				}
+			}
+		}
+	}
+
+	// Inline code outside fences, e.g. `http.HandleFunc`
+	prose := codeFencePattern.ReplaceAllString(content, "")
+	for _, m := range inlineCodePattern.FindAllStringSubmatch(prose, -1) {
+		if identifierPattern.MatchString(m[1]) {
+			add(m[1])
+		}
+	}
+
+	return keywords
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 type Documentation struct {
@@ -22,6 +23,17 @@ type Topic struct {
 	Content       string   `json:"content"`
 	Keywords      []string `json:"keywords"`
 	Documentation string   `json:"documentation"`
+	// Version is the release of the product the topic describes, if any;
+	// newer versions of a documentation rank higher in search
+	Version string `json:"version,omitempty"`
+
+	// extracted are keywords picked out of Content when the topic is loaded
+	extracted []string
+	// recency ranks Version among the documentation's versions, from 0 for
+	// the oldest to 1 for the newest
+	recency float64
+	// superseded hides an old version from search (see SetVersionRetention)
+	superseded bool
 }
 
 type SearchResult struct {
@@ -30,17 +42,30 @@ type SearchResult struct {
 	Description   string  `json:"description"`
 	Documentation string  `json:"documentation"`
 	Score         float64 `json:"score"`
+	// Related are lower-ranked results about the same subject from other
+	// documentations, folded in by GroupResults
+	Related []SearchResult `json:"related,omitempty"`
 }
 
 type Provider struct {
+	mu             sync.RWMutex
 	documentations map[string]*Documentation
 	cacheDir       string
+	// weights scale the search scores of documentations (see SetWeights)
+	weights map[string]float64
+	// recencyBoost raises the scores of newer versions (see SetRecencyBoost)
+	recencyBoost float64
+	// versionRetention is how many versions of each documentation are
+	// searchable (see SetVersionRetention)
+	versionRetention int
 }
 
 func NewProvider(cacheDir string) (*Provider, error) {
 	p := &Provider{
-		documentations: make(map[string]*Documentation),
-		cacheDir:       cacheDir,
+		documentations:   make(map[string]*Documentation),
+		cacheDir:         cacheDir,
+		recencyBoost:     DefaultRecencyBoost,
+		versionRetention: DefaultVersionRetention,
 	}
 
 	// Load all documentation from cache directory
@@ -70,87 +95,134 @@ func (p *Provider) loadDocumentation() error {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		// Hidden directories, such as the cache trash, are not documentation
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
-		docName := entry.Name()
-		docDir := filepath.Join(dataDir, docName)
+		documentation, err := p.readDocumentation(entry.Name())
+		if err != nil {
+			return err
+		}
+		p.indexVersions(documentation)
+		p.documentations[entry.Name()] = documentation
+	}
 
-		// Load Documentation metadata
-		metadataPath := filepath.Join(docDir, "metadata.json")
-		var documentation Documentation
+	// Info message if no documentations were loaded
+	if len(p.documentations) == 0 {
+		fmt.Fprintf(os.Stderr, "Info: No documentation loaded. Use 'open-context_get_go_info' tool for on-demand fetching.\n")
+	}
 
-		if data, err := os.ReadFile(metadataPath); err == nil {
-			if err := json.Unmarshal(data, &documentation); err != nil {
-				return fmt.Errorf("failed to parse metadata for %s: %w", docName, err)
-			}
-		} else {
-			// Default metadata if file doesn't exist
-			displayName := docName
-			if len(docName) > 0 {
-				displayName = strings.ToUpper(docName[:1]) + docName[1:]
-			}
-			documentation = Documentation{
-				Name:        docName,
-				DisplayName: displayName,
-				Description: fmt.Sprintf("Documentation for %s", docName),
-			}
-		}
+	return nil
+}
+
+// Reload reads one documentation from the cache directory again, adding it
+// if it is new, so documentation fetched while the server runs becomes
+// searchable without a restart
+func (p *Provider) Reload(docName string) error {
+	documentation, err := p.readDocumentation(docName)
+	if err != nil {
+		return err
+	}
 
-		// Ensure Topics map is initialized
-		if documentation.Topics == nil {
-			documentation.Topics = make(map[string]*Topic)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.indexVersions(documentation)
+	p.documentations[docName] = documentation
+	return nil
+}
+
+// readDocumentation reads the metadata and topics of one documentation
+// directory
+func (p *Provider) readDocumentation(docName string) (*Documentation, error) {
+	docDir := filepath.Join(p.cacheDir, docName)
+
+	// Load Documentation metadata
+	metadataPath := filepath.Join(docDir, "metadata.json")
+	var documentation Documentation
+
+	if data, err := os.ReadFile(metadataPath); err == nil {
+		if err := json.Unmarshal(data, &documentation); err != nil {
+			return nil, fmt.Errorf("failed to parse metadata for %s: %w", docName, err)
+		}
+	} else {
+		// Default metadata if file doesn't exist
+		displayName := docName
+		if len(docName) > 0 {
+			displayName = strings.ToUpper(docName[:1]) + docName[1:]
 		}
+		documentation = Documentation{
+			Name:        docName,
+			DisplayName: displayName,
+			Description: fmt.Sprintf("Documentation for %s", docName),
+		}
+	}
 
-		// Load topics
-		topicsDir := filepath.Join(docDir, "topics")
-		if topicEntries, err := os.ReadDir(topicsDir); err == nil {
-			for _, topicEntry := range topicEntries {
-				if topicEntry.IsDir() || !strings.HasSuffix(topicEntry.Name(), ".json") {
-					continue
-				}
+	// Ensure Topics map is initialized
+	if documentation.Topics == nil {
+		documentation.Topics = make(map[string]*Topic)
+	}
 
-				topicPath := filepath.Join(topicsDir, topicEntry.Name())
-				data, err := os.ReadFile(topicPath)
-				if err != nil {
-					continue
-				}
+	// Load topics
+	topicsDir := filepath.Join(docDir, "topics")
+	if topicEntries, err := os.ReadDir(topicsDir); err == nil {
+		for _, topicEntry := range topicEntries {
+			if topicEntry.IsDir() || !strings.HasSuffix(topicEntry.Name(), ".json") {
+				continue
+			}
 
-				var topic Topic
-				if err := json.Unmarshal(data, &topic); err != nil {
-					continue
-				}
+			topicPath := filepath.Join(topicsDir, topicEntry.Name())
+			data, err := os.ReadFile(topicPath)
+			if err != nil {
+				continue
+			}
 
-				topic.Documentation = docName
-				documentation.Topics[topic.ID] = &topic
+			var topic Topic
+			if err := json.Unmarshal(data, &topic); err != nil {
+				continue
 			}
-		}
 
-		p.documentations[docName] = &documentation
+			topic.Documentation = docName
+			topic.extracted = ExtractKeywords(topic.Content)
+			documentation.Topics[topic.ID] = &topic
+		}
 	}
 
-	// Info message if no documentations were loaded
-	if len(p.documentations) == 0 {
-		fmt.Fprintf(os.Stderr, "Info: No documentation loaded. Use 'open-context_get_go_info' tool for on-demand fetching.\n")
+	for id, topic := range loadVersionTopics(docDir, docName, documentation.DisplayName) {
+		if _, ok := documentation.Topics[id]; !ok {
+			documentation.Topics[id] = topic
+		}
 	}
 
-	return nil
+	return &documentation, nil
 }
 
+// Search ranks the topics matching query. documentation optionally limits
+// the search to a comma-separated list of documentation names or prefix
+// patterns (e.g., "go,local-*"). Scores are scaled by the documentation's
+// weight (see SetWeights) and, for versioned topics, by how recent their
+// version is (see SetRecencyBoost).
 func (p *Provider) Search(query string, documentation string) []SearchResult {
 	query = strings.ToLower(query)
+	names := SplitDocumentations(documentation)
 	var results []SearchResult
 
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	for docName, doc := range p.documentations {
 		// Skip if documentation filter is specified and doesn't match
-		if documentation != "" && docName != documentation {
+		if !MatchesDocumentation(names, docName) {
 			continue
 		}
 
 		for _, topic := range doc.Topics {
+			if topic.superseded {
+				continue
+			}
 			score := p.calculateScore(query, topic)
 			if score > 0 {
+				score *= p.topicWeight(topic)
 				results = append(results, SearchResult{
 					ID:            topic.ID,
 					Title:         topic.Title,
@@ -174,6 +246,36 @@ func (p *Provider) Search(query string, documentation string) []SearchResult {
 	return results
 }
 
+// GroupResults folds results about the same subject into the best-ranked one,
+// so that "net/http" from the Go standard library and from a custom
+// documentation come back as one result with the other as related, rather
+// than as interleaved duplicates. Results must be sorted by score.
+func GroupResults(results []SearchResult) []SearchResult {
+	parents := make(map[string]int)
+	var grouped []SearchResult
+	for _, r := range results {
+		key := subjectKey(r.Title)
+		if i, ok := parents[key]; ok && key != "" && grouped[i].Documentation != r.Documentation {
+			grouped[i].Related = append(grouped[i].Related, r)
+			continue
+		}
+		if _, ok := parents[key]; !ok {
+			parents[key] = len(grouped)
+		}
+		grouped = append(grouped, r)
+	}
+	return grouped
+}
+
+// subjectKey names what a topic title is about: "Go Package: net/http" and
+// "net/http" are both "net/http"
+func subjectKey(title string) string {
+	if i := strings.LastIndex(title, ": "); i >= 0 {
+		title = title[i+2:]
+	}
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
 func (p *Provider) calculateScore(query string, topic *Topic) float64 {
 	score := 0.0
 
@@ -189,6 +291,14 @@ func (p *Provider) calculateScore(query string, topic *Topic) float64 {
 		}
 	}
 
+	// Check keywords extracted from the content, once
+	for _, keyword := range topic.extracted {
+		if strings.Contains(keyword, query) {
+			score += 2.0
+			break
+		}
+	}
+
 	// Check description
 	if strings.Contains(strings.ToLower(topic.Description), query) {
 		score += 3.0
@@ -203,6 +313,9 @@ func (p *Provider) calculateScore(query string, topic *Topic) float64 {
 }
 
 func (p *Provider) GetDoc(id, doc, topic string) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	// If ID is provided, use it directly
 	if id != "" {
 		for docName, documentation := range p.documentations {
@@ -236,8 +349,26 @@ func (p *Provider) GetDoc(id, doc, topic string) (string, error) {
 	return "", fmt.Errorf("either id or topic must be provided")
 }
 
+// Topics returns every searchable topic, leaving out the versions
+// SetVersionRetention hides
+func (p *Provider) Topics() []*Topic {
+	var topics []*Topic
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, documentation := range p.documentations {
+		for _, topic := range documentation.Topics {
+			if !topic.superseded {
+				topics = append(topics, topic)
+			}
+		}
+	}
+	return topics
+}
+
 func (p *Provider) ListDocumentations() []Documentation {
 	var documentations []Documentation
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 
 	for _, documentation := range p.documentations {
 		// Create a copy without the full topic content
@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/semver"
+)
+
+// DefaultRecencyBoost is how much more the newest version of a product
+// scores than its oldest one: ×1.5, with the versions between scaled
+// linearly
+const DefaultRecencyBoost = 0.5
+
+// DefaultVersionRetention is how many of the newest versions of each
+// documentation stay searchable; older ones stay indexed for GetDoc
+const DefaultVersionRetention = 5
+
+// loadVersionTopics reads the version documents release tools cache under a
+// documentation's versions directory (e.g. terraform/versions/1.9.0.md),
+// taking each one's version from its YAML frontmatter. Documents without a
+// version are skipped.
+func loadVersionTopics(docDir, docName, displayName string) map[string]*Topic {
+	entries, err := os.ReadDir(filepath.Join(docDir, "versions"))
+	if err != nil {
+		return nil
+	}
+
+	topics := make(map[string]*Topic)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(docDir, "versions", entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		version, body, ok := splitVersionFrontMatter(string(data))
+		if !ok {
+			continue
+		}
+		id := fmt.Sprintf("%s/versions/%s", docName, version)
+		topics[id] = &Topic{
+			ID:            id,
+			Title:         fmt.Sprintf("%s %s", displayName, version),
+			Description:   fmt.Sprintf("Release notes of %s %s", displayName, version),
+			Content:       body,
+			Keywords:      []string{docName, version, "release notes"},
+			Version:       version,
+			Documentation: docName,
+			extracted:     ExtractKeywords(body),
+		}
+	}
+	return topics
+}
+
+// splitVersionFrontMatter returns the version field of a cached version
+// document and the markdown after its frontmatter
+func splitVersionFrontMatter(src string) (version, body string, ok bool) {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	if !strings.HasPrefix(src, "---\n") {
+		return "", "", false
+	}
+	front, body, found := strings.Cut(src[4:], "\n---")
+	if !found {
+		return "", "", false
+	}
+	var meta struct {
+		Version string `yaml:"version"`
+	}
+	if err := yaml.Unmarshal([]byte(front), &meta); err != nil || strings.TrimSpace(meta.Version) == "" {
+		return "", "", false
+	}
+	return strings.TrimSpace(meta.Version), strings.TrimSpace(body), true
+}
+
+// indexVersions hides the versions of a documentation older than the
+// newest p.versionRetention from search, then ranks the rest by recency.
+// Callers hold p.mu or own the documentation.
+func (p *Provider) indexVersions(documentation *Documentation) {
+	supersedeVersions(documentation, p.versionRetention)
+	assignRecency(documentation)
+}
+
+// supersedeVersions marks the topics of every version but the newest keep
+// of a documentation superseded, and clears the mark from the rest; a keep
+// of 0 or less marks none. Superseded topics stay in the index, so GetDoc
+// still returns them, but Search skips them.
+func supersedeVersions(documentation *Documentation, keep int) {
+	for _, topic := range documentation.Topics {
+		topic.superseded = false
+	}
+	versions := sortedVersions(documentation)
+	if keep <= 0 || len(versions) <= keep {
+		return
+	}
+
+	superseded := make(map[string]bool, len(versions)-keep)
+	for _, v := range versions[:len(versions)-keep] {
+		superseded[v] = true
+	}
+	for _, topic := range documentation.Topics {
+		topic.superseded = superseded[topic.Version]
+	}
+}
+
+// sortedVersions lists the versions of a documentation's searchable
+// topics, oldest first
+func sortedVersions(documentation *Documentation) []string {
+	var versions []string
+	seen := make(map[string]bool)
+	for _, topic := range documentation.Topics {
+		if topic.Version != "" && !topic.superseded && !seen[topic.Version] {
+			seen[topic.Version] = true
+			versions = append(versions, topic.Version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare(versionNumber(versions[i]), versionNumber(versions[j])) < 0
+	})
+	return versions
+}
+
+// assignRecency ranks the searchable versioned topics of a documentation
+// from oldest, 0, to newest, 1. Topics of one version share its rank, and a
+// documentation with a single version leaves its topics unboosted.
+func assignRecency(documentation *Documentation) {
+	for _, topic := range documentation.Topics {
+		topic.recency = 0
+	}
+	versions := sortedVersions(documentation)
+	if len(versions) < 2 {
+		return
+	}
+
+	rank := make(map[string]float64, len(versions))
+	for i, v := range versions {
+		rank[v] = float64(i) / float64(len(versions)-1)
+	}
+	for _, topic := range documentation.Topics {
+		if topic.Version != "" {
+			topic.recency = rank[topic.Version]
+		}
+	}
+}
+
+// versionNumber drops a product prefix such as "go" in "go1.22", "v" in
+// "v1.9.0", or "bun-v" in "bun-v1.1.38", which semver.Compare would read as
+// 0 or as a pre-release. It keeps everything from the first digit on.
+func versionNumber(version string) string {
+	if i := strings.IndexAny(version, "0123456789"); i > 0 {
+		return version[i:]
+	}
+	return version
+}
+
+// SetRecencyBoost sets how much more the newest version of a product scores
+// than its oldest one (see DefaultRecencyBoost); 0 ranks versions by their
+// match alone
+func (p *Provider) SetRecencyBoost(boost float64) {
+	if boost < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring recency boost %v: it must not be negative\n", boost)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recencyBoost = boost
+}
+
+// SetVersionRetention keeps the newest keep versions of each documentation
+// searchable and hides the older ones from Search, so release notes of every
+// version ever fetched do not crowd out other results. Hidden versions stay
+// in the index for GetDoc. A keep of 0 or less keeps every version
+// searchable.
+func (p *Provider) SetVersionRetention(keep int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.versionRetention = keep
+	for _, documentation := range p.documentations {
+		p.indexVersions(documentation)
+	}
+}
+
+// TopicWeight returns the factor the search scores of a topic are
+// multiplied by: its documentation's weight, raised for newer versions
+func (p *Provider) TopicWeight(topic *Topic) float64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.topicWeight(topic)
+}
+
+// topicWeight is TopicWeight for callers holding p.mu
+func (p *Provider) topicWeight(topic *Topic) float64 {
+	return p.weight(topic.Documentation) * (1 + p.recencyBoost*topic.recency)
+}
@@ -0,0 +1,80 @@
+package provider
+
+import "testing"
+
+// TestAssignRecency checks that versions with product prefixes are ranked
+// by their numbers, not by the prefixes
+func TestAssignRecency(t *testing.T) {
+	documentation := &Documentation{Topics: make(map[string]*Topic)}
+	for _, version := range []string{"go1.22", "bun-v1.10.0", "v1.9.0", "bun-v1.2.0"} {
+		documentation.Topics[version] = &Topic{ID: version, Version: version}
+	}
+	documentation.Topics["overview"] = &Topic{ID: "overview"}
+
+	assignRecency(documentation)
+
+	want := map[string]float64{
+		"bun-v1.2.0":  0,
+		"v1.9.0":      1.0 / 3,
+		"bun-v1.10.0": 2.0 / 3,
+		"go1.22":      1,
+		"overview":    0,
+	}
+	for id, recency := range want {
+		if got := documentation.Topics[id].recency; got != recency {
+			t.Errorf("recency of %s = %v, want %v", id, got, recency)
+		}
+	}
+}
+
+func TestVersionNumber(t *testing.T) {
+	tests := map[string]string{
+		"go1.22":      "1.22",
+		"v1.9.0":      "1.9.0",
+		"bun-v1.1.38": "1.1.38",
+		"1.2.3":       "1.2.3",
+		"latest":      "latest",
+	}
+	for version, want := range tests {
+		if got := versionNumber(version); got != want {
+			t.Errorf("versionNumber(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
+
+// TestVersionRetention checks that versions past the retention are left out
+// of search but still returned by GetDoc, and come back when the retention
+// grows
+func TestVersionRetention(t *testing.T) {
+	documentation := &Documentation{Name: "terraform", Topics: make(map[string]*Topic)}
+	for _, version := range []string{"1.3.0", "1.9.0", "1.10.0"} {
+		id := "terraform/versions/" + version
+		documentation.Topics[id] = &Topic{
+			ID:            id,
+			Title:         "Terraform " + version,
+			Content:       "Release notes of " + version,
+			Version:       version,
+			Documentation: "terraform",
+		}
+	}
+	p := &Provider{documentations: map[string]*Documentation{"terraform": documentation}}
+	p.SetVersionRetention(2)
+
+	results := p.Search("terraform", "")
+	for _, result := range results {
+		if result.ID == "terraform/versions/1.3.0" {
+			t.Error("Search returned a version past the retention")
+		}
+	}
+	if len(results) != 2 {
+		t.Errorf("Search returned %d versions, want 2", len(results))
+	}
+	if content, err := p.GetDoc("terraform/versions/1.3.0", "", ""); err != nil || content != "Release notes of 1.3.0" {
+		t.Errorf("GetDoc of a version past the retention = %q, %v", content, err)
+	}
+
+	p.SetVersionRetention(0)
+	if results := p.Search("terraform", ""); len(results) != 3 {
+		t.Errorf("Search returned %d versions with every version kept, want 3", len(results))
+	}
+}
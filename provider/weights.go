@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SplitDocumentations parses a search filter such as "go, local-*" into the
+// documentation names or prefix patterns it lists
+func SplitDocumentations(filter string) []string {
+	var names []string
+	for _, name := range strings.Split(filter, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// MatchesDocumentation reports whether docName is one of names, where a name
+// ending in "*" matches documentations by prefix (e.g., "local-*"). An empty
+// list matches every documentation.
+func MatchesDocumentation(names []string, docName string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if matchDocumentation(name, docName) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchDocumentation(pattern, docName string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(docName, prefix)
+	}
+	return pattern == docName
+}
+
+// SetWeights sets the factors search scores of each documentation are
+// multiplied by, keyed by documentation name or prefix pattern such as
+// "local-*", so that, e.g., internal docs outrank public ones. An exact name
+// wins over patterns, and the longest matching pattern over shorter ones.
+// Documentations without a weight keep a factor of 1.
+func (p *Provider) SetWeights(weights map[string]float64) {
+	valid := make(map[string]float64, len(weights))
+	for name, weight := range weights {
+		if weight <= 0 {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring search weight %v for %s: weights must be positive\n", weight, name)
+			continue
+		}
+		valid[name] = weight
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.weights = valid
+}
+
+// Weight returns the factor search scores of docName are multiplied by
+func (p *Provider) Weight(docName string) float64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.weight(docName)
+}
+
+// weight is Weight for callers holding p.mu
+func (p *Provider) weight(docName string) float64 {
+	if weight, ok := p.weights[docName]; ok {
+		return weight
+	}
+
+	weight, longest := 1.0, -1
+	for pattern, w := range p.weights {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if ok && len(prefix) > longest && strings.HasPrefix(docName, prefix) {
+			weight, longest = w, len(prefix)
+		}
+	}
+	return weight
+}
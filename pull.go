@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/server"
+)
+
+// pullCache downloads an export of another server's cache and adds its
+// entries to the local cache, keeping local entries that are as new
+func pullCache(ctx context.Context, exportURL, token string) error {
+	u, err := url.Parse(exportURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid export URL %q (expected e.g. https://docs.example.com/admin/export?ns=npm)", exportURL)
+	}
+	if token == "" {
+		return fmt.Errorf("an admin token is required; pass --token or set OPEN_CONTEXT_ADMIN_TOKEN")
+	}
+	if u.Scheme == "http" && !server.IsLoopbackHost(u.Hostname()) {
+		fmt.Fprintf(os.Stderr, "Warning: sending the admin token to %s without TLS\n", u.Host)
+	}
+
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	if err := cache.Migrate(cacheDir); err != nil {
+		return fmt.Errorf("failed to upgrade the local cache: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", u.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned status %d: %s", u.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if schema := resp.Header.Get(server.CacheSchemaHeader); schema != strconv.Itoa(cache.SchemaVersion) {
+		return fmt.Errorf("%s has cache schema version %q and this build %d; upgrade the older of the two", u.Host, schema, cache.SchemaVersion)
+	}
+
+	fmt.Printf("Pulling cache from %s into %s\n", u.Host, cacheDir)
+	written, kept, err := cache.ReadArchive(resp.Body, cacheDir)
+	if err != nil {
+		return fmt.Errorf("failed to pull cache after %d entries: %w", written, err)
+	}
+
+	fmt.Printf("✓ Pulled %d cache entries (%d local entries were as new and kept)\n", written, kept)
+	fmt.Println("Restart running servers to search the pulled documentation.")
+	return nil
+}
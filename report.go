@@ -0,0 +1,271 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"cmp"
+	"compress/gzip"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/server"
+)
+
+// maxReportErrors caps the error and warning lines a report includes
+const maxReportErrors = 100
+
+// secretKeyPattern matches the config keys whose values a report redacts
+var secretKeyPattern = regexp.MustCompile(`(?i)(token|key|password|secret|credential)`)
+
+// errorLinePattern matches the log lines a report lists as recent errors
+var errorLinePattern = regexp.MustCompile(`(?i)(warning:|error|failed|panic)`)
+
+// reportEnvVars are the environment variables a report says are set or not,
+// never with their values
+var reportEnvVars = []string{"GITHUB_TOKEN", "GITLAB_TOKEN", "BUF_TOKEN", "STACKEXCHANGE_KEY", "OPEN_CONTEXT_ADMIN_TOKEN", "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY"}
+
+// writeReport gathers what a bug report needs into a gzipped tarball at
+// out: the build and platform, the config file with its secrets redacted,
+// cache statistics, the last lines of the server log, and the errors among
+// them. The report is only written to disk; nothing is sent anywhere.
+func writeReport(out string, lines int) error {
+	if out == "" {
+		out = fmt.Sprintf("open-context-report-%s.tar.gz", time.Now().Format("20060102-150405"))
+	}
+
+	logLines, errLines, logPath, err := readLog(lines)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read the log: %v\n", err)
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"version.txt", reportVersion()},
+		{"config.yaml", reportConfig()},
+		{"cache.txt", reportCache()},
+		{"log.txt", strings.Join(logLines, "\n") + "\n"},
+		{"errors.txt", strings.Join(errLines, "\n") + "\n"},
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, file := range files {
+		hdr := &tar.Header{
+			Name:    "open-context-report/" + file.name,
+			Mode:    0600,
+			Size:    int64(len(file.content)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		if _, err := tw.Write([]byte(file.content)); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	fmt.Printf("Wrote %s\n", out)
+	if logPath != "" {
+		fmt.Printf("Included the last %d lines of %s\n", len(logLines), logPath)
+	}
+	fmt.Println("Secrets in the config are redacted; review the files before attaching the report to an issue.")
+	return nil
+}
+
+func reportVersion() string {
+	build := server.Build()
+	var b strings.Builder
+	fmt.Fprintf(&b, "version: %s\n", build.Version)
+	if build.Commit != "" {
+		fmt.Fprintf(&b, "commit: %s\n", build.Commit)
+	}
+	fmt.Fprintf(&b, "go: %s\n", build.GoVersion)
+	fmt.Fprintf(&b, "platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "cpus: %d\n", runtime.NumCPU())
+	fmt.Fprintf(&b, "generated: %s\n", time.Now().UTC().Format(time.RFC3339))
+	b.WriteString("\nenvironment:\n")
+	for _, name := range reportEnvVars {
+		state := "unset"
+		if os.Getenv(name) != "" {
+			state = "set"
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", name, state)
+	}
+	return b.String()
+}
+
+// reportConfig returns the config file read at startup with the values of
+// secret keys, custom source headers, and URL credentials replaced
+func reportConfig() string {
+	path := config.LoadedPath()
+	if path == "" {
+		return "# No config file; the defaults are in use\n"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("# Failed to read %s: %v\n", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Sprintf("# Failed to parse %s: %v\n", path, err)
+	}
+	redactNode(&doc, false)
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return fmt.Sprintf("# Failed to render %s: %v\n", path, err)
+	}
+	return fmt.Sprintf("# %s\n%s", path, out)
+}
+
+// redactNode replaces the secrets below a YAML node; secret is true below a
+// key whose every value is secret, such as headers
+func redactNode(node *yaml.Node, secret bool) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			redactNode(child, secret)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			redactNode(node.Content[i+1], secret || key == "headers" || secretKeyPattern.MatchString(key))
+		}
+	case yaml.ScalarNode:
+		switch {
+		case node.Value == "":
+		case secret:
+			node.Value, node.Tag, node.Style = "REDACTED", "!!str", 0
+		case strings.Contains(node.Value, "://"):
+			if u, err := url.Parse(node.Value); err == nil && u.User != nil {
+				u.User = url.User("REDACTED")
+				node.Value = u.String()
+			}
+		}
+	}
+}
+
+// reportCache lists the files and bytes of each top-level cache directory
+func reportCache() string {
+	var b strings.Builder
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return fmt.Sprintf("Failed to get cache directory: %v\n", err)
+	}
+	fmt.Fprintf(&b, "directory: %s\n", cacheDir)
+	if cfg, err := config.Load(); err == nil {
+		fmt.Fprintf(&b, "ttl: %v\n", cfg.CacheTTL.Duration)
+		fmt.Fprintf(&b, "backend: %s\n", cmp.Or(cfg.Cache.Backend, "disk"))
+	}
+
+	type usage struct {
+		files  int
+		bytes  int64
+		newest time.Time
+	}
+	dirs := make(map[string]*usage)
+	var total usage
+	err = filepath.WalkDir(cacheDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(cacheDir, path)
+		top, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		u := dirs[top]
+		if u == nil {
+			u = &usage{}
+			dirs[top] = u
+		}
+		for _, u := range []*usage{u, &total} {
+			u.files++
+			u.bytes += info.Size()
+			if info.ModTime().After(u.newest) {
+				u.newest = info.ModTime()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(&b, "Failed to walk the cache: %v\n", err)
+	}
+	fmt.Fprintf(&b, "files: %d\nbytes: %d\n\n", total.files, total.bytes)
+
+	names := make([]string, 0, len(dirs))
+	for name := range dirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(&b, "%-32s %8s %12s  %s\n", "DIRECTORY", "FILES", "BYTES", "NEWEST")
+	for _, name := range names {
+		u := dirs[name]
+		fmt.Fprintf(&b, "%-32s %8d %12d  %s\n", name, u.files, u.bytes, u.newest.UTC().Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// readLog returns the last n lines of the server log and its last
+// maxReportErrors error and warning lines, reading the rotated file first
+func readLog(n int) (tail, errs []string, path string, err error) {
+	path, err = config.GetLogPath()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	for _, p := range []string{path + ".1", path} {
+		f, err := os.Open(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return tail, errs, path, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if tail = append(tail, line); len(tail) > n {
+				tail = tail[1:]
+			}
+			if errorLinePattern.MatchString(line) {
+				if errs = append(errs, line); len(errs) > maxReportErrors {
+					errs = errs[1:]
+				}
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return tail, errs, path, err
+		}
+	}
+	return tail, errs, path, nil
+}
@@ -0,0 +1,60 @@
+// Package semver compares the dotted version strings used by package registries.
+package semver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Compare compares dotted version strings numerically ("1.10.0" > "1.9.2"),
+// ignoring a leading "v". A pre-release suffix ("2.0.0-beta.1") sorts before the
+// release it precedes. It returns -1, 0, or 1.
+func Compare(a, b string) int {
+	aCore, aPre := splitPrerelease(strings.TrimPrefix(a, "v"))
+	bCore, bPre := splitPrerelease(strings.TrimPrefix(b, "v"))
+
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var an, bn int
+		if i < len(aParts) {
+			an, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bn, _ = strconv.Atoi(bParts[i])
+		}
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// IsPrerelease reports whether a version carries a pre-release suffix
+func IsPrerelease(version string) bool {
+	_, pre := splitPrerelease(strings.TrimPrefix(version, "v"))
+	return pre != ""
+}
+
+func splitPrerelease(version string) (string, string) {
+	version = strings.SplitN(version, "+", 2)[0]
+	if idx := strings.Index(version, "-"); idx >= 0 {
+		return version[:idx], version[idx+1:]
+	}
+	return version, ""
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/incu6us/open-context/cache"
+	"github.com/incu6us/open-context/config"
+)
+
+// CacheSchemaHeader carries the cache layout version of an export, so a
+// pulling machine can refuse entries its build would read differently
+const CacheSchemaHeader = "X-Open-Context-Cache-Schema"
+
+// requireAdmin refuses requests without the admin token as their bearer
+// token
+func (h *HTTPServer) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="open-context admin"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleAdminExport streams the local cache entries of the namespaces named
+// by ns (comma-separated or repeated; every namespace when absent) as a
+// gzipped tarball
+func (h *HTTPServer) handleAdminExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get cache directory: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var names []string
+	for _, ns := range r.URL.Query()["ns"] {
+		for _, name := range strings.Split(ns, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	namespaces, err := cache.Namespaces(cacheDir, names)
+	if errors.Is(err, cache.ErrNotExist) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Large caches take longer to send than the server's write timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="open-context-cache.tar.gz"`)
+	w.Header().Set(CacheSchemaHeader, strconv.Itoa(cache.SchemaVersion))
+	count, err := cache.WriteArchive(w, cacheDir, namespaces)
+	if err != nil {
+		// The response has started; the client sees a truncated archive
+		log.Printf("Warning: cache export failed after %d entries: %v", count, err)
+		return
+	}
+	log.Printf("Exported %d cache entries (%s) to %s", count, strings.Join(namespaces, ", "), r.RemoteAddr)
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/incu6us/open-context/config"
+)
+
+// defaultCORSHeaders are the request headers browsers may always send
+var defaultCORSHeaders = []string{"Content-Type", "Mcp-Session-Id", "Last-Event-ID"}
+
+// corsPolicy decides the CORS headers of the HTTP transport's responses
+type corsPolicy struct {
+	// anyOrigin allows every origin
+	anyOrigin bool
+	// origins are allowed origins, lowercased. An entry may start with
+	// "*." after the scheme to allow any subdomain.
+	origins     []string
+	credentials bool
+	headers     string
+}
+
+// newCORSPolicy builds the policy of cfg. Without allowed_origins, the
+// policy allows defaultOrigins.
+func newCORSPolicy(cfg config.CORSConfig, defaultOrigins ...string) *corsPolicy {
+	p := &corsPolicy{credentials: cfg.AllowCredentials}
+
+	origins := cfg.AllowedOrigins
+	if len(origins) == 0 {
+		origins = defaultOrigins
+	}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		if origin == "*" {
+			p.anyOrigin = true
+		} else if origin != "" {
+			p.origins = append(p.origins, origin)
+		}
+	}
+	if p.anyOrigin && p.credentials {
+		log.Printf("Warning: cors allows credentials from any origin; list allowed_origins to restrict it")
+	}
+
+	headers := append([]string{}, defaultCORSHeaders...)
+	for _, header := range cfg.AllowedHeaders {
+		if header = strings.TrimSpace(header); header != "" {
+			headers = append(headers, header)
+		}
+	}
+	p.headers = strings.Join(headers, ", ")
+	return p
+}
+
+// allowed reports whether requests from origin may read responses
+func (p *corsPolicy) allowed(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range p.origins {
+		if origin == allowed {
+			return true
+		}
+		// "https://*.example.com" matches "https://api.example.com"
+		if scheme, host, ok := strings.Cut(allowed, "://*."); ok {
+			if rest, ok := strings.CutPrefix(origin, scheme+"://"); ok && strings.HasSuffix(rest, "."+host) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wrap sets the CORS headers of a handler's responses and answers
+// preflight requests. Responses to origins that are not allowed carry no
+// CORS headers, so browsers keep them from the page; their preflight
+// requests are refused.
+func (p *corsPolicy) wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := origin == "" || p.allowed(origin)
+
+		if p.anyOrigin && !p.credentials {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			// The answer depends on the origin; credentialed responses
+			// must name it rather than "*"
+			w.Header().Add("Vary", "Origin")
+			if origin != "" && allowed {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if p.credentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+		}
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", p.headers)
+			// Pages read the session ID an initialize response issues
+			w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+		}
+
+		if r.Method == "OPTIONS" {
+			if !allowed {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/crawler"
+	"github.com/incu6us/open-context/fetcher"
+)
+
+// customTool is a tool materialized from a custom_sources entry of
+// config.yaml
+type customTool struct {
+	info    ToolInfo
+	fetcher *fetcher.CustomSourceFetcher
+}
+
+// newCustomTools builds the tools of the configured custom sources. Invalid
+// sources and names taken by another tool are skipped with a warning, so a
+// config mistake does not keep the server from starting.
+func newCustomTools(sources []config.CustomSource, cacheDir string) []*customTool {
+	taken := make(map[string]bool)
+	for _, t := range Tools() {
+		taken[t.Name] = true
+	}
+
+	var tools []*customTool
+	for _, source := range sources {
+		f, err := fetcher.NewCustomSourceFetcher(cacheDir, source)
+		if err != nil {
+			log.Printf("Warning: skipping custom source: %v", err)
+			continue
+		}
+
+		name := "open-context_get_" + source.Name + "_info"
+		if taken[name] {
+			log.Printf("Warning: skipping custom source %s: tool %s already exists", source.Name, name)
+			continue
+		}
+		taken[name] = true
+
+		tools = append(tools, &customTool{
+			info:    ToolInfo{Name: name, Description: customToolDescription(f), InputSchema: customToolSchema(f)},
+			fetcher: f,
+		})
+	}
+	return tools
+}
+
+func customToolDescription(f *fetcher.CustomSourceFetcher) string {
+	source := f.Source()
+	if source.Description != "" {
+		return source.Description
+	}
+	switch source.Type {
+	case fetcher.CustomGitHubRelease:
+		return fmt.Sprintf("Get release notes of %s from its GitHub releases", source.Name)
+	case fetcher.CustomRegistry:
+		return fmt.Sprintf("Get package information from the %s registry", source.Name)
+	default:
+		return fmt.Sprintf("Crawl the %s documentation site and index it for open-context_search_docs", source.Name)
+	}
+}
+
+func customToolSchema(f *fetcher.CustomSourceFetcher) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+
+	switch f.Source().Type {
+	case fetcher.CustomGitHubRelease:
+		properties["version"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Release tag (e.g., 'v1.2.0'); defaults to the latest release",
+		}
+	case fetcher.CustomRegistry:
+		if f.NeedsName() {
+			properties["name"] = map[string]interface{}{
+				"type":        "string",
+				"description": "Package name",
+			}
+			required = append(required, "name")
+		}
+		properties["version"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Package version; defaults to the latest",
+		}
+	case fetcher.CustomSitemap:
+		maxPages := f.Source().MaxPages
+		if maxPages <= 0 {
+			maxPages = crawler.DefaultMaxPages
+		}
+		properties["maxPages"] = map[string]interface{}{
+			"type":        "integer",
+			"description": fmt.Sprintf("Pages to crawl, in sitemap order (default %d)", maxPages),
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (s *MCPServer) customTool(name string) *customTool {
+	for _, t := range s.customTools {
+		if t.info.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *MCPServer) callCustomTool(t *customTool, args map[string]interface{}) (string, error) {
+	name, _ := args["name"].(string)
+	version, _ := args["version"].(string)
+	if version == "" {
+		name, version = splitNameVersion(name)
+	}
+	maxPages := 0
+	if v, ok := args["maxPages"].(float64); ok {
+		maxPages = int(v)
+	}
+
+	source := t.fetcher.Source()
+	info, err := t.fetcher.Fetch(name, version, maxPages)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", source.Name, err)
+	}
+
+	if info.Documentation == "" {
+		return info.Content, nil
+	}
+	if err := s.loadIndexedDocumentation(info.Documentation, info.Updated); err != nil {
+		return "", fmt.Errorf("failed to load %s pages: %w", source.Name, err)
+	}
+	return info.Content + fmt.Sprintf("Search it with open-context_search_docs using language '%s'.\n", info.Documentation), nil
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// deprecatedTools maps the deprecated built-in tools by name
+var deprecatedTools = sync.OnceValue(func() map[string]ToolInfo {
+	tools := make(map[string]ToolInfo)
+	for _, t := range Tools() {
+		if t.Deprecated {
+			tools[t.Name] = t
+		}
+	}
+	return tools
+})
+
+// listedTools returns the tools offered in tools/list: those of tools(),
+// without the deprecated ones when hide_deprecated_tools is set
+func (s *MCPServer) listedTools() []ToolInfo {
+	if !s.hideDeprecated {
+		return s.tools()
+	}
+	var tools []ToolInfo
+	for _, t := range s.tools() {
+		if !t.Deprecated {
+			tools = append(tools, t)
+		}
+	}
+	return tools
+}
+
+// withDeprecationNotice leads the description of a deprecated tool with
+// what to use instead, since clients show models the description only
+func withDeprecationNotice(t ToolInfo) ToolInfo {
+	if !t.Deprecated {
+		return t
+	}
+	notice := "Deprecated"
+	if t.ReplacedBy != "" {
+		notice += fmt.Sprintf(": use %s instead", t.ReplacedBy)
+	}
+	t.Description = notice + ". " + t.Description
+	return t
+}
+
+// warnDeprecated logs the first call of a deprecated tool, so operators
+// learn which client configurations to update before it is removed
+func (s *MCPServer) warnDeprecated(name string) {
+	t, ok := deprecatedTools()[name]
+	if !ok {
+		return
+	}
+	if _, warned := s.deprecationWarned.LoadOrStore(name, true); warned {
+		return
+	}
+	if t.ReplacedBy != "" {
+		log.Printf("Warning: deprecated tool %s was called; use %s instead", name, t.ReplacedBy)
+	} else {
+		log.Printf("Warning: deprecated tool %s was called; it will be removed", name)
+	}
+}
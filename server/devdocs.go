@@ -0,0 +1,50 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// devDocsRefreshInterval is how often configured DevDocs docsets are
+	// checked for a newer DevDocs build
+	devDocsRefreshInterval = 24 * time.Hour
+)
+
+// SyncDevDocs indexes the given DevDocs docsets (e.g., "python~3.12",
+// "react") in the background, making them searchable without a
+// get_devdocs call, and checks them for a newer DevDocs build daily.
+// Docsets already indexed at the current build are not downloaded again.
+func (s *MCPServer) SyncDevDocs(slugs []string) {
+	if len(slugs) == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(devDocsRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			s.syncDevDocs(slugs)
+			<-ticker.C
+		}
+	}()
+}
+
+// syncDevDocs fetches each docset once, returning when done
+func (s *MCPServer) syncDevDocs(slugs []string) {
+	for _, slug := range slugs {
+		info, err := s.devDocsFetcher.FetchDocset(slug)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch DevDocs docset %s: %v\n", slug, err)
+			continue
+		}
+		if err := s.loadIndexedDocumentation(info.Documentation, info.Updated); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load DevDocs docset %s: %v\n", slug, err)
+			continue
+		}
+		if info.Updated {
+			fmt.Fprintf(os.Stderr, "Indexed DevDocs docset %s as '%s' (%d topics)\n", slug, info.Documentation, info.Topics)
+		}
+	}
+}
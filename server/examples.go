@@ -0,0 +1,198 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/incu6us/open-context/provider"
+)
+
+const (
+	// maxExampleTopics caps the topics a code example query reads
+	maxExampleTopics = 5
+
+	// maxCodeExamples caps the code blocks returned by get_code_examples
+	maxCodeExamples = 20
+)
+
+// codeLanguageAliases maps fence languages to one name, so a "js" filter
+// finds "javascript" blocks
+var codeLanguageAliases = map[string]string{
+	"golang":        "go",
+	"js":            "javascript",
+	"jsx":           "javascript",
+	"mjs":           "javascript",
+	"node":          "javascript",
+	"ts":            "typescript",
+	"tsx":           "typescript",
+	"py":            "python",
+	"python3":       "python",
+	"pycon":         "python",
+	"rs":            "rust",
+	"rb":            "ruby",
+	"sh":            "shell",
+	"bash":          "shell",
+	"zsh":           "shell",
+	"console":       "shell",
+	"shell-session": "shell",
+	"yml":           "yaml",
+	"tf":            "hcl",
+	"terraform":     "hcl",
+	"dockerfile":    "docker",
+	"c++":           "cpp",
+	"cs":            "csharp",
+	"kt":            "kotlin",
+}
+
+// codeBlock is one fenced code block of a document
+type codeBlock struct {
+	language string
+	code     string
+	// section is the heading the block sits under, if any
+	section string
+}
+
+func normalizeCodeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if alias, ok := codeLanguageAliases[lang]; ok {
+		return alias
+	}
+	return lang
+}
+
+// extractCodeBlocks returns the fenced code blocks of markdown content with
+// the heading each sits under
+func extractCodeBlocks(content string) []codeBlock {
+	var blocks []codeBlock
+	var section, fence string
+	var current *codeBlock
+	var code []string
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if current != nil {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				current.code = strings.Join(code, "\n")
+				if strings.TrimSpace(current.code) != "" {
+					blocks = append(blocks, *current)
+				}
+				current, code = nil, nil
+				continue
+			}
+			code = append(code, line)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			marker := trimmed[:1]
+			n := len(trimmed) - len(strings.TrimLeft(trimmed, marker))
+			fence = strings.Repeat(marker, n)
+			// The info string may carry attributes: ```go title="main.go"
+			info := strings.Fields(strings.TrimLeft(trimmed, marker))
+			lang := ""
+			if len(info) > 0 {
+				lang = strings.Trim(info[0], "{}.")
+			}
+			current = &codeBlock{language: lang, section: section}
+		case strings.HasPrefix(trimmed, "#"):
+			section = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		}
+	}
+
+	return blocks
+}
+
+func (s *MCPServer) getCodeExamples(args map[string]interface{}) (string, error) {
+	query, _ := args["query"].(string)
+	id, _ := args["id"].(string)
+	documentation, _ := args["documentation"].(string)
+	language, _ := args["language"].(string)
+
+	if strings.TrimSpace(query) == "" && id == "" {
+		return "", fmt.Errorf("query or id parameter is required")
+	}
+
+	var topics []provider.SearchResult
+	if id != "" {
+		topics = []provider.SearchResult{{ID: id, Title: id, Documentation: documentation}}
+	} else {
+		topics = s.docProvider.Search(query, documentation)
+		if len(topics) > maxExampleTopics {
+			topics = topics[:maxExampleTopics]
+		}
+	}
+
+	subject := query
+	if id != "" {
+		subject = id
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# Code Examples: %s\n\n", subject)
+	if language != "" {
+		fmt.Fprintf(&content, "**Language:** %s\n\n", language)
+	}
+
+	want := normalizeCodeLanguage(language)
+	count, truncated := 0, false
+	for _, topic := range topics {
+		if truncated {
+			break
+		}
+		doc, err := s.docProvider.GetDoc(topic.ID, topic.Documentation, "")
+		if err != nil {
+			if id != "" {
+				return "", err
+			}
+			continue
+		}
+
+		var blocks []codeBlock
+		for _, block := range extractCodeBlocks(doc) {
+			if want == "" || normalizeCodeLanguage(block.language) == want {
+				blocks = append(blocks, block)
+			}
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&content, "## %s", topic.Title)
+		if topic.Documentation != "" {
+			fmt.Fprintf(&content, " (`%s`, id `%s`)", topic.Documentation, topic.ID)
+		}
+		content.WriteString("\n\n")
+
+		for _, block := range blocks {
+			if count == maxCodeExamples {
+				truncated = true
+				break
+			}
+			count++
+			if block.section != "" {
+				fmt.Fprintf(&content, "**%s:**\n\n", block.section)
+			}
+			// A block showing markdown may itself contain fences
+			fence := "```"
+			if strings.Contains(block.code, fence) {
+				fence = "````"
+			}
+			fmt.Fprintf(&content, "%s%s\n%s\n%s\n\n", fence, block.language, strings.Trim(block.code, "\n"), fence)
+		}
+	}
+	if truncated {
+		fmt.Fprintf(&content, "Only the first %d code blocks are shown; narrow the query or pass an id for more.\n", maxCodeExamples)
+	}
+
+	if count == 0 {
+		content.WriteString("No code examples found")
+		if language != "" {
+			fmt.Fprintf(&content, " in %s", language)
+		}
+		content.WriteString(". Try `open-context_search_docs` to find topics, or a package tool such as `open-context_get_go_info` to cache more documentation.\n")
+	}
+
+	return content.String(), nil
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ExportTools renders the tool definitions as function-calling schemas for
+// non-MCP integrations. Calls made with these schemas can be executed by
+// posting a tools/call request to the HTTP transport's /message endpoint.
+func ExportTools(format string) ([]byte, error) {
+	var tools []ToolInfo
+	for _, tool := range Tools() {
+		tools = append(tools, withDeprecationNotice(tool))
+	}
+
+	var defs []interface{}
+	switch format {
+	case "openai":
+		for _, tool := range tools {
+			defs = append(defs, map[string]interface{}{
+				"type": "function",
+				"function": map[string]interface{}{
+					"name":        tool.Name,
+					"description": tool.Description,
+					"parameters":  tool.InputSchema,
+				},
+			})
+		}
+
+	case "anthropic":
+		for _, tool := range tools {
+			defs = append(defs, map[string]interface{}{
+				"name":         tool.Name,
+				"description":  tool.Description,
+				"input_schema": tool.InputSchema,
+			})
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported format: %s (must be 'openai' or 'anthropic')", format)
+	}
+
+	data, err := json.MarshalIndent(defs, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tool definitions: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// fixtureStore holds canned tool responses, one JSON file per call at
+// <dir>/<tool>/<key>.json, where key hashes the call's arguments
+type fixtureStore struct {
+	dir string
+	// record saves the response of every call instead of replaying
+	record bool
+}
+
+// fixture is a recorded tool call. Exactly one of Result and Error is set.
+type fixture struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Result    string                 `json:"result,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// ServeFixtures answers every tool call from the fixtures recorded in dir
+// instead of calling the tool, so clients can run deterministic end-to-end
+// tests without network access. A call without a fixture fails, naming the
+// file it looked for.
+func (s *MCPServer) ServeFixtures(dir string) {
+	s.fixtures = &fixtureStore{dir: dir}
+}
+
+// RecordFixtures saves the response of every tool call to dir, in the
+// layout ServeFixtures reads. Calls that panic are not recorded.
+func (s *MCPServer) RecordFixtures(dir string) {
+	s.fixtures = &fixtureStore{dir: dir, record: true}
+}
+
+// fixturePath returns the file of a call: the arguments are hashed as JSON,
+// whose object keys encoding/json sorts, so equal arguments share a file
+func (f *fixtureStore) fixturePath(tool string, args map[string]interface{}) (string, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return filepath.Join(f.dir, tool, hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// replay returns the recorded response of a call
+func (f *fixtureStore) replay(tool string, args map[string]interface{}) (string, error) {
+	path, err := f.fixturePath(tool, args)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		encoded, _ := json.Marshal(args)
+		return "", fmt.Errorf("no fixture for %s with arguments %s (expected %s)", tool, encoded, path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fx fixture
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return "", fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if fx.Error != "" {
+		return "", errors.New(fx.Error)
+	}
+	return fx.Result, nil
+}
+
+// save records the response of a call, replacing an earlier recording
+func (f *fixtureStore) save(tool string, args map[string]interface{}, result string, callErr error) {
+	path, err := f.fixturePath(tool, args)
+	if err != nil {
+		log.Printf("Warning: not recording %s: %v", tool, err)
+		return
+	}
+	fx := fixture{Tool: tool, Arguments: args, Result: result}
+	if callErr != nil {
+		fx.Result, fx.Error = "", callErr.Error()
+	}
+	data, err := json.MarshalIndent(fx, "", "  ")
+	if err != nil {
+		log.Printf("Warning: not recording %s: %v", tool, err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Printf("Warning: failed to record %s: %v", tool, err)
+		return
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		log.Printf("Warning: failed to record %s: %v", tool, err)
+	}
+}
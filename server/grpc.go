@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/incu6us/open-context/grpcapi"
+	"github.com/incu6us/open-context/provider"
+)
+
+// grpcBackend adapts MCPServer to the gRPC Documentation service. Search and
+// GetDoc call search_docs and get_docs, and the registry methods run under
+// get_packages_info, so enabled_tools and fixtures apply as they do over MCP.
+type grpcBackend struct {
+	mcp *MCPServer
+}
+
+// ServeGRPC serves the gRPC Documentation service on addr until it fails
+func (s *MCPServer) ServeGRPC(addr string) error {
+	return grpcapi.ListenAndServe(addr, &grpcBackend{mcp: s})
+}
+
+func (b *grpcBackend) Search(req *grpcapi.SearchRequest) (*grpcapi.SearchResponse, error) {
+	if req.Query == "" {
+		return nil, grpcapi.Errorf(grpcapi.InvalidArgument, "query is required")
+	}
+
+	data, err := b.mcp.callEnabledTool("open-context_search_docs", map[string]interface{}{
+		"query":    req.Query,
+		"language": req.Language,
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	var results []provider.SearchResult
+	if err := json.Unmarshal([]byte(data), &results); err != nil {
+		return nil, grpcError(err)
+	}
+
+	// SearchResult has no related results, so they follow their group
+	resp := &grpcapi.SearchResponse{}
+	var add func(rs []provider.SearchResult)
+	add = func(rs []provider.SearchResult) {
+		for _, r := range rs {
+			resp.Results = append(resp.Results, grpcapi.SearchResult{
+				ID:            r.ID,
+				Title:         r.Title,
+				Description:   r.Description,
+				Documentation: r.Documentation,
+				Score:         r.Score,
+			})
+			add(r.Related)
+		}
+	}
+	add(results)
+	return resp, nil
+}
+
+func (b *grpcBackend) GetDoc(req *grpcapi.GetDocRequest) (string, error) {
+	doc, err := b.mcp.callEnabledTool("open-context_get_docs", map[string]interface{}{
+		"id":       req.ID,
+		"language": req.Language,
+		"topic":    req.Topic,
+	})
+	if err != nil {
+		return "", grpcError(err)
+	}
+	return doc, nil
+}
+
+func (b *grpcBackend) FetchPackage(req *grpcapi.FetchPackageRequest) (*grpcapi.Package, error) {
+	if req.Ecosystem == "" || req.Name == "" {
+		return nil, grpcapi.Errorf(grpcapi.InvalidArgument, "ecosystem and name are required")
+	}
+
+	args := map[string]interface{}{"ecosystem": req.Ecosystem, "package": req.Name, "version": req.Version}
+	var pkg grpcapi.Package
+	err := b.callJSON("FetchPackage", args, &pkg, func() (interface{}, error) {
+		summary, err := b.mcp.fetchPackage(req.Ecosystem, req.Name, req.Version)
+		if err != nil {
+			return nil, err
+		}
+		return &grpcapi.Package{
+			Ecosystem:   summary.Ecosystem,
+			Name:        summary.Name,
+			Version:     summary.Version,
+			Description: summary.Description,
+			License:     summary.License,
+			Repository:  summary.Repository,
+			Content:     summary.Content,
+		}, nil
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &pkg, nil
+}
+
+func (b *grpcBackend) ListVersions(req *grpcapi.ListVersionsRequest) (*grpcapi.ListVersionsResponse, error) {
+	if req.Ecosystem == "" || req.Name == "" {
+		return nil, grpcapi.Errorf(grpcapi.InvalidArgument, "ecosystem and name are required")
+	}
+
+	args := map[string]interface{}{"ecosystem": req.Ecosystem, "package": req.Name}
+	var versions []string
+	err := b.callJSON("ListVersions", args, &versions, func() (interface{}, error) {
+		return b.mcp.versionsFetcher.ListVersions(req.Ecosystem, req.Name)
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &grpcapi.ListVersionsResponse{Versions: versions}, nil
+}
+
+// callJSON runs a registry method under get_packages_info, recording its
+// result as JSON in fixtures named after the method, and decodes it into v
+func (b *grpcBackend) callJSON(method string, args map[string]interface{}, v interface{}, call func() (interface{}, error)) error {
+	data, err := b.mcp.callEnabled("open-context_get_packages_info", "grpc_"+method, args, func() (string, error) {
+		result, err := call()
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(result)
+		return string(data), err
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), v)
+}
+
+// grpcError classifies a fetcher error the same way the REST facade does.
+// A tool left out by enabled_tools makes its methods unimplemented.
+func grpcError(err error) error {
+	if errors.Is(err, errUnknownTool) {
+		return grpcapi.Errorf(grpcapi.Unimplemented, "%v", err)
+	}
+	switch restStatus(err) {
+	case http.StatusBadRequest:
+		return grpcapi.Errorf(grpcapi.InvalidArgument, "%v", err)
+	case http.StatusNotFound:
+		return grpcapi.Errorf(grpcapi.NotFound, "%v", err)
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return grpcapi.Errorf(grpcapi.Unavailable, "%v", err)
+	default:
+		return grpcapi.Errorf(grpcapi.Internal, "%v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IsLoopbackHost reports whether a listen host only accepts connections
+// from this machine. An empty host listens on every interface.
+func IsLoopbackHost(host string) bool {
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// hostPolicy decides which Host headers the HTTP transport answers. A
+// server listening on localhost only answers loopback names unless others
+// are allowed, so a web page cannot reach it through DNS rebinding.
+type hostPolicy struct {
+	// hosts are allowed host names, lowercased and without ports. An entry
+	// may start with "*." to allow any subdomain.
+	hosts []string
+	// loopbackOnly limits an empty hosts list to loopback names
+	loopbackOnly bool
+}
+
+func newHostPolicy(allowed []string, addr string) *hostPolicy {
+	bindHost, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		bindHost = addr
+	}
+
+	p := &hostPolicy{loopbackOnly: IsLoopbackHost(bindHost)}
+	for _, host := range allowed {
+		if host = normalizeHost(host); host != "" {
+			p.hosts = append(p.hosts, host)
+		}
+	}
+	return p
+}
+
+// acceptsAnyHost reports whether the policy answers every Host header: a
+// server listening on a non-loopback address with no allowed hosts is not
+// protected against DNS rebinding
+func (p *hostPolicy) acceptsAnyHost() bool {
+	return !p.loopbackOnly && len(p.hosts) == 0
+}
+
+// allowed reports whether a request's Host header names this server.
+// Loopback names are always allowed, so local clients and health checks
+// keep working.
+func (p *hostPolicy) allowed(hostHeader string) bool {
+	host := normalizeHost(hostHeader)
+	if IsLoopbackHost(host) {
+		return true
+	}
+	if len(p.hosts) == 0 {
+		return !p.loopbackOnly
+	}
+	for _, allowed := range p.hosts {
+		if host == allowed {
+			return true
+		}
+		// "*.example.com" matches "docs.example.com"
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok && strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap refuses requests whose Host header is not allowed
+func (p *hostPolicy) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !p.allowed(r.Host) {
+			http.Error(w, "invalid Host header", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// normalizeHost lowercases a host and strips its port and brackets
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+}
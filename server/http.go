@@ -1,32 +1,63 @@
 package server
 
 import (
+	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"sync"
 	"time"
+
+	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/fetcher"
 )
 
 // HTTPServer wraps MCPServer to provide HTTP/SSE transport
 type HTTPServer struct {
-	mcp     *MCPServer
-	clients map[string]*sseClient
-	mu      sync.RWMutex
-}
-
-type sseClient struct {
-	id       string
-	messages chan []byte
-	done     chan struct{}
+	mcp      *MCPServer
+	clients  map[string]*sseClient
+	sessions *sessionStore
+	mu       sync.RWMutex
+
+	// queueLimits bound the undelivered events of each SSE client
+	queueLimits sseQueueLimits
+	metrics     sseMetrics
+
+	cors *corsPolicy
+	// restCORS is the CORS policy of the REST facade, which only allows
+	// the origins configured
+	restCORS *corsPolicy
+	// allowedHosts are the Host headers answered besides loopback names
+	allowedHosts []string
+	// adminToken enables the admin API; empty leaves it unserved
+	adminToken string
 }
 
 func NewHTTPServer(mcp *MCPServer) *HTTPServer {
+	var sseConfig config.SSEConfig
+	var corsConfig config.CORSConfig
+	var allowedHosts []string
+	adminToken := os.Getenv("OPEN_CONTEXT_ADMIN_TOKEN")
+	if cfg, err := config.Load(); err == nil {
+		sseConfig = cfg.SSE
+		corsConfig = cfg.CORS
+		allowedHosts = cfg.AllowedHosts
+		adminToken = cmp.Or(cfg.AdminToken, adminToken)
+	}
+
 	return &HTTPServer{
-		mcp:     mcp,
-		clients: make(map[string]*sseClient),
+		mcp:          mcp,
+		clients:      make(map[string]*sseClient),
+		sessions:     newSessionStore(),
+		queueLimits:  newSSEQueueLimits(sseConfig),
+		cors:         newCORSPolicy(corsConfig, "*"),
+		restCORS:     newCORSPolicy(corsConfig),
+		allowedHosts: allowedHosts,
+		adminToken:   adminToken,
 	}
 }
 
@@ -35,34 +66,41 @@ func (h *HTTPServer) ServeHTTP(addr string) error {
 	mux := http.NewServeMux()
 
 	// CORS middleware
-	corsHandler := func(next http.HandlerFunc) http.HandlerFunc {
-		return func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-
-			next(w, r)
-		}
-	}
+	corsHandler := h.cors.wrap
 
 	// Health check endpoint
 	mux.HandleFunc("/health", corsHandler(h.handleHealth))
 
+	// Build metadata of the running server
+	mux.HandleFunc("/version", corsHandler(h.handleVersion))
+
+	// SSE queue metrics in the Prometheus text format
+	mux.HandleFunc("/metrics", h.handleMetrics)
+
 	// MCP message endpoint
 	mux.HandleFunc("/message", corsHandler(h.handleMessage))
 
 	// SSE endpoint for streaming responses
 	mux.HandleFunc("/sse", corsHandler(h.handleSSE))
 
+	// Plain REST facade over the tools
+	mux.HandleFunc("/api/v1/", h.restCORS.wrap(h.handleREST))
+
+	// Cache export for open-context cache pull, only with an admin token
+	// and never to browsers
+	if h.adminToken != "" {
+		mux.HandleFunc("/admin/export", h.requireAdmin(h.handleAdminExport))
+	}
+
+	policy := newHostPolicy(h.allowedHosts, addr)
+	if policy.acceptsAnyHost() {
+		log.Printf("Warning: listening on %s with allowed_hosts empty; any Host header is accepted, so DNS-rebinding protection is off", addr)
+	}
+
 	log.Printf("Starting HTTP server on %s", addr)
 	server := &http.Server{
 		Addr:         addr,
-		Handler:      mux,
+		Handler:      policy.wrap(mux),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  120 * time.Second,
@@ -74,7 +112,70 @@ func (h *HTTPServer) ServeHTTP(addr string) error {
 func (h *HTTPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(`{"status":"ok","server":"open-context","version":"0.1.0"}`))
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status":  "ok",
+		"server":  "open-context",
+		"version": buildInfo.Version,
+	})
+}
+
+func (h *HTTPServer) handleVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(struct {
+		Server string `json:"server"`
+		BuildInfo
+	}{"open-context", buildInfo})
+}
+
+func (h *HTTPServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	clients := make([]*sseClient, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	var connected, disconnected, events, bytes int
+	for _, client := range clients {
+		pending, pendingBytes, attached := client.queued()
+		if attached {
+			connected++
+		} else {
+			disconnected++
+		}
+		events += pending
+		bytes += pendingBytes
+	}
+
+	var degraded []string
+	for _, f := range fetcher.ShapeFailures() {
+		degraded = append(degraded, fmt.Sprintf(`{fetcher=%q,check=%q} %d`, f.Fetcher, f.Check, f.Count))
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	metrics := []struct {
+		name, kind, help string
+		values           []string
+	}{
+		{"opencontext_sse_clients", "gauge", "SSE clients, connected or waiting to reconnect.", []string{
+			fmt.Sprintf(`{state="connected"} %d`, connected),
+			fmt.Sprintf(`{state="disconnected"} %d`, disconnected),
+		}},
+		{"opencontext_sse_queued_events", "gauge", "Events queued for SSE clients that have not received them.", []string{fmt.Sprintf(" %d", events)}},
+		{"opencontext_sse_queued_bytes", "gauge", "Size of the events queued for SSE clients.", []string{fmt.Sprintf(" %d", bytes)}},
+		{"opencontext_sse_events_queued_total", "counter", "Events queued for SSE clients.", []string{fmt.Sprintf(" %d", h.metrics.queued.Load())}},
+		{"opencontext_sse_events_delivered_total", "counter", "Events written to SSE streams, replays included.", []string{fmt.Sprintf(" %d", h.metrics.delivered.Load())}},
+		{"opencontext_sse_events_dropped_total", "counter", "Events dropped because a client queue was full.", []string{fmt.Sprintf(" %d", h.metrics.dropped.Load())}},
+		{"opencontext_sse_overflow_disconnects_total", "counter", "SSE clients closed by the disconnect drop policy.", []string{fmt.Sprintf(" %d", h.metrics.overflows.Load())}},
+		{"opencontext_fetch_degraded_total", "counter", "Fetched entries that failed a content check, a sign the upstream page changed.", degraded},
+	}
+	for _, m := range metrics {
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, value := range m.values {
+			_, _ = fmt.Fprintf(w, "%s%s\n", m.name, value)
+		}
+	}
 }
 
 func (h *HTTPServer) handleMessage(w http.ResponseWriter, r *http.Request) {
@@ -98,8 +199,56 @@ func (h *HTTPServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Conversation state follows the session the server issued in the
+	// Mcp-Session-Id header of an initialize response, or the SSE client that
+	// sent the message; requests with neither are stateless. An ID the server
+	// did not issue, or has dropped, gets a 404 so the client initializes
+	// again.
+	var sess *session
+	if sessionID := r.Header.Get("Mcp-Session-Id"); sessionID != "" {
+		var ok bool
+		if sess, ok = h.sessions.get(sessionID); !ok {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+	} else if clientID := r.URL.Query().Get("clientId"); clientID != "" {
+		if h.client(clientID) == nil {
+			http.Error(w, "Client not found", http.StatusNotFound)
+			return
+		}
+		sess = h.sessions.attach(clientID)
+	} else if req.Method == "initialize" {
+		var sessionID string
+		sessionID, sess = h.sessions.open()
+		w.Header().Set("Mcp-Session-Id", sessionID)
+	}
+
 	// Handle the request
-	resp := h.mcp.handleRequest(req)
+	resp := h.mcp.handleRequestSafely(req, sess)
+
+	// Large tool results go to the sender's SSE stream in chunks, once the
+	// tool has returned all of the text
+	if client := h.client(r.URL.Query().Get("clientId")); client != nil {
+		if text, ok := streamableText(resp); ok && len(text) > streamThreshold {
+			chunks := chunkText(text, streamChunkSize)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			if err := json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc":  "2.0",
+				"id":       req.ID,
+				"streamed": true,
+				"chunks":   len(chunks),
+			}); err != nil {
+				log.Printf("Error encoding response: %v", err)
+			}
+			go func() {
+				if err := h.streamResponse(client, req.ID, chunks); err != nil {
+					log.Printf("Error streaming response to client %s: %v", client.id, err)
+				}
+			}()
+			return
+		}
+	}
 
 	// Send response
 	w.Header().Set("Content-Type", "application/json")
@@ -115,74 +264,156 @@ func (h *HTTPServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
 		return
 	}
-
-	// Create client
-	clientID := fmt.Sprintf("client-%d", time.Now().UnixNano())
-	client := &sseClient{
-		id:       clientID,
-		messages: make(chan []byte, 10),
-		done:     make(chan struct{}),
+	rc := http.NewResponseController(w)
+
+	// Resume the client named by the Last-Event-ID header or clientId
+	// parameter, or start a new one
+	var conn <-chan struct{}
+	resumed := false
+	client, after := h.resumeClient(r)
+	if client != nil {
+		conn, resumed = client.attach(after)
 	}
+	if !resumed {
+		client, after = h.newClient(), 0
+		conn, _ = client.attach(0)
+	}
+	defer client.detach(conn, func() { h.expireClient(client) })
 
-	h.mu.Lock()
-	h.clients[clientID] = client
-	h.mu.Unlock()
-
-	defer func() {
-		h.mu.Lock()
-		delete(h.clients, clientID)
-		h.mu.Unlock()
-		close(client.done)
-	}()
+	events, lost, wake := client.since(after)
 
-	// Send initial connection message
-	if _, err := fmt.Fprintf(w, "event: connected\ndata: {\"clientId\":\"%s\"}\n\n", clientID); err != nil {
+	// Send initial connection message. Its id lets a client that has not
+	// received an event yet resume.
+	connected, err := json.Marshal(map[string]interface{}{
+		"clientId": client.id,
+		"resumed":  resumed,
+		"lost":     lost,
+	})
+	if err != nil {
+		log.Printf("Error encoding connected event: %v", err)
+		return
+	}
+	_ = rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+	if _, err := fmt.Fprintf(w, "retry: %d\nid: %s\nevent: connected\ndata: %s\n\n", sseRetry.Milliseconds(), client.eventID(after), connected); err != nil {
 		log.Printf("Error sending connected event: %v", err)
 		return
 	}
 	flusher.Flush()
+	lost = 0
 
 	// Keep connection alive and send messages
-	ticker := time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(sseHeartbeatInterval)
 	defer ticker.Stop()
 
 	for {
+		if len(events) > 0 {
+			_ = rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+			if lost > 0 {
+				// Events were dropped from the queue while this connection
+				// was behind
+				dropped := sseEvent{name: "lost", data: []byte(fmt.Sprintf(`{"lost":%d}`, lost))}
+				if err := writeSSEEvent(w, client, dropped); err != nil {
+					log.Printf("Error sending lost event: %v", err)
+					return
+				}
+			}
+			for _, ev := range events {
+				if err := writeSSEEvent(w, client, ev); err != nil {
+					log.Printf("Error sending %s event: %v", ev.name, err)
+					return
+				}
+			}
+			flusher.Flush()
+			after = events[len(events)-1].seq
+			client.markDelivered(after)
+			h.metrics.delivered.Add(uint64(len(events)))
+		}
+
 		select {
 		case <-r.Context().Done():
 			return
+		case <-conn:
+			// The client reconnected on another connection
+			return
 		case <-client.done:
 			return
-		case msg := <-client.messages:
-			if _, err := fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg); err != nil {
-				log.Printf("Error sending message event: %v", err)
-				return
-			}
-			flusher.Flush()
+		case <-wake:
 		case <-ticker.C:
 			// Send heartbeat
-			if _, err := fmt.Fprintf(w, "event: heartbeat\ndata: {\"timestamp\":%d}\n\n", time.Now().Unix()); err != nil {
+			_ = rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+			heartbeat := sseEvent{name: "heartbeat", data: []byte(fmt.Sprintf(`{"timestamp":%d}`, time.Now().Unix()))}
+			if err := writeSSEEvent(w, client, heartbeat); err != nil {
 				log.Printf("Error sending heartbeat event: %v", err)
 				return
 			}
 			flusher.Flush()
 		}
+		events, lost, wake = client.since(after)
 	}
 }
 
-// SendToClient sends a message to a specific client via SSE
-func (h *HTTPServer) SendToClient(clientID string, message interface{}) error {
+// resumeClient finds the client a reconnecting SSE request names, and the
+// last event it received. EventSource sends the ID of the last event in the
+// Last-Event-ID header; other clients may pass clientId instead, to receive
+// every event still kept.
+func (h *HTTPServer) resumeClient(r *http.Request) (*sseClient, uint64) {
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		if clientID, seq, ok := parseEventID(lastID); ok {
+			return h.client(clientID), seq
+		}
+		return nil, 0
+	}
+	return h.client(r.URL.Query().Get("clientId")), 0
+}
+
+// newClient registers a new SSE client
+func (h *HTTPServer) newClient() *sseClient {
+	client := newSSEClient(h.queueLimits)
+	h.mu.Lock()
+	h.clients[client.id] = client
+	h.mu.Unlock()
+	return client
+}
+
+// expireClient drops a client that did not reconnect within
+// sseResumeWindow, with its session
+func (h *HTTPServer) expireClient(client *sseClient) {
+	if client.expire() {
+		h.removeClient(client)
+	}
+}
+
+// removeClient forgets a closed client and its session
+func (h *HTTPServer) removeClient(client *sseClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[client.id] == client {
+		delete(h.clients, client.id)
+	}
+	h.sessions.remove(client.id)
+}
+
+// client returns the SSE client with the given ID, connected or waiting to
+// reconnect, or nil
+func (h *HTTPServer) client(clientID string) *sseClient {
+	if clientID == "" {
+		return nil
+	}
 	h.mu.RLock()
-	client, ok := h.clients[clientID]
-	h.mu.RUnlock()
+	defer h.mu.RUnlock()
+	return h.clients[clientID]
+}
 
-	if !ok {
+// SendToClient sends a message to a specific client via SSE
+func (h *HTTPServer) SendToClient(clientID string, message interface{}) error {
+	client := h.client(clientID)
+	if client == nil {
 		return fmt.Errorf("client not found: %s", clientID)
 	}
 
@@ -191,12 +422,28 @@ func (h *HTTPServer) SendToClient(clientID string, message interface{}) error {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	select {
-	case client.messages <- data:
-		return nil
-	case <-time.After(5 * time.Second):
-		return fmt.Errorf("timeout sending to client %s", clientID)
+	return h.sendEvent(client, sseEvent{name: "message", data: data})
+}
+
+// sendEvent queues an event for a client without blocking. Events queued
+// while the client is disconnected are sent when it resumes; a full queue
+// is handled by the drop policy.
+func (h *HTTPServer) sendEvent(client *sseClient, ev sseEvent) error {
+	dropped, err := client.push(ev)
+	if err == nil {
+		h.metrics.queued.Add(1)
+	}
+	if dropped > 0 {
+		h.metrics.dropped.Add(uint64(dropped))
+		if client.startDropping() {
+			log.Printf("Warning: SSE client %s is not keeping up; applying %s", client.id, h.queueLimits.policy)
+		}
+	}
+	if errors.Is(err, errSSEOverflow) {
+		h.metrics.overflows.Add(1)
+		h.removeClient(client)
 	}
+	return err
 }
 
 // BroadcastToAll sends a message to all connected clients
@@ -207,14 +454,15 @@ func (h *HTTPServer) BroadcastToAll(message interface{}) error {
 	}
 
 	h.mu.RLock()
-	defer h.mu.RUnlock()
-
+	clients := make([]*sseClient, 0, len(h.clients))
 	for _, client := range h.clients {
-		select {
-		case client.messages <- data:
-		case <-time.After(1 * time.Second):
-			log.Printf("Warning: timeout sending to client %s", client.id)
-		}
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	// Queuing never blocks, so a stalled client does not hold up the others
+	for _, client := range clients {
+		_ = h.sendEvent(client, sseEvent{name: "message", data: data})
 	}
 
 	return nil
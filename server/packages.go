@@ -0,0 +1,325 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/incu6us/open-context/fetcher"
+)
+
+const (
+	// maxBatchPackages caps the number of entries accepted by get_packages_info
+	maxBatchPackages = 20
+)
+
+// PackageSummary is the registry-independent view of a package returned by the package tools
+type PackageSummary struct {
+	Ecosystem        string            `json:"ecosystem"`
+	Name             string            `json:"name"`
+	Version          string            `json:"version"`
+	Description      string            `json:"description,omitempty"`
+	License          string            `json:"license,omitempty"`
+	Repository       string            `json:"repository,omitempty"`
+	PeerDependencies map[string]string `json:"peerDependencies,omitempty"`
+	Content          string            `json:"-"`
+}
+
+// fetchPackage looks up a package with the fetcher for its ecosystem
+func (s *MCPServer) fetchPackage(ecosystem, name, version string) (summary *PackageSummary, err error) {
+	// Batch lookups and prefetches run this on goroutines of their own,
+	// where a panic would not reach a request's recovery
+	defer recoverPanic(&err, ecosystem+" package "+name)
+
+	canonical, err := fetcher.NormalizeEcosystem(ecosystem)
+	if err != nil {
+		return nil, err
+	}
+
+	switch canonical {
+	case "npm":
+		info, err := s.npmFetcher.FetchPackageInfo(name, version)
+		if err != nil {
+			return nil, err
+		}
+		return &PackageSummary{
+			Ecosystem:        canonical,
+			Name:             info.Name,
+			Version:          info.Version,
+			Description:      info.Description,
+			License:          info.License,
+			Repository:       info.Repository,
+			PeerDependencies: info.PeerDependencies,
+			Content:          info.Content,
+		}, nil
+
+	case "python":
+		info, err := s.pythonFetcher.FetchPackageInfo(name, version)
+		if err != nil {
+			return nil, err
+		}
+		return &PackageSummary{
+			Ecosystem:   canonical,
+			Name:        info.Name,
+			Version:     info.Version,
+			Description: info.Summary,
+			License:     info.License,
+			Repository:  info.Repository,
+			Content:     info.Content,
+		}, nil
+
+	case "rust":
+		info, err := s.rustFetcher.FetchCrateInfo(name, version)
+		if err != nil {
+			return nil, err
+		}
+		return &PackageSummary{
+			Ecosystem:   canonical,
+			Name:        info.Name,
+			Version:     info.Version,
+			Description: info.Description,
+			License:     info.License,
+			Repository:  info.Repository,
+			Content:     info.Content,
+		}, nil
+
+	case "go":
+		info, err := s.goFetcher.FetchLibraryInfo(name, version)
+		if err != nil {
+			return nil, err
+		}
+		return &PackageSummary{
+			Ecosystem:   canonical,
+			Name:        info.ImportPath,
+			Version:     info.Version,
+			Description: info.Synopsis,
+			License:     info.License,
+			Repository:  info.Repository,
+			Content:     info.Description,
+		}, nil
+
+	case "ruby":
+		info, err := s.rubyGemsFetcher.FetchGemInfo(name, version)
+		if err != nil {
+			return nil, err
+		}
+		return &PackageSummary{
+			Ecosystem:   canonical,
+			Name:        info.Name,
+			Version:     info.Version,
+			Description: info.Summary,
+			License:     info.License,
+			Repository:  info.Repository,
+			Content:     info.Content,
+		}, nil
+
+	case "hex":
+		info, err := s.hexFetcher.FetchPackageInfo(name, version)
+		if err != nil {
+			return nil, err
+		}
+		return &PackageSummary{
+			Ecosystem:   canonical,
+			Name:        info.Name,
+			Version:     info.Version,
+			Description: info.Description,
+			License:     info.License,
+			Repository:  info.Repository,
+			Content:     info.Content,
+		}, nil
+
+	case "cocoapods":
+		info, err := s.cocoaPodsFetcher.FetchPodInfo(name, version)
+		if err != nil {
+			return nil, err
+		}
+		return &PackageSummary{
+			Ecosystem:   canonical,
+			Name:        info.Name,
+			Version:     info.Version,
+			Description: info.Summary,
+			License:     info.License,
+			Repository:  info.Repository,
+			Content:     info.Content,
+		}, nil
+
+	case "conan":
+		info, err := s.conanFetcher.FetchPackageInfo(name, version)
+		if err != nil {
+			return nil, err
+		}
+		return &PackageSummary{
+			Ecosystem:   canonical,
+			Name:        info.Name,
+			Version:     info.Version,
+			Description: info.Description,
+			License:     info.License,
+			Repository:  info.Homepage,
+			Content:     info.Content,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported ecosystem: %s", ecosystem)
+}
+
+func (s *MCPServer) generateDependencySnippet(args map[string]interface{}) (string, error) {
+	ecosystem, ok := args["ecosystem"].(string)
+	if !ok || ecosystem == "" {
+		return "", fmt.Errorf("ecosystem parameter is required")
+	}
+
+	packageName, ok := args["package"].(string)
+	if !ok || packageName == "" {
+		return "", fmt.Errorf("package parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	var opts fetcher.DependencyOptions
+	if o, ok := args["options"].(map[string]interface{}); ok {
+		opts.Dev, _ = o["dev"].(bool)
+		opts.NoDefaultFeatures, _ = o["noDefaultFeatures"].(bool)
+		opts.Features = stringSlice(o["features"])
+		opts.Extras = stringSlice(o["extras"])
+	}
+
+	// Resolve the version (and npm peer dependencies) from the registry so the
+	// snippet pins something that exists
+	pkg, err := s.fetchPackage(ecosystem, packageName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve package: %w", err)
+	}
+	opts.PeerDependencies = pkg.PeerDependencies
+
+	name := packageName
+	if pkg.Name != "" {
+		name = pkg.Name
+	}
+
+	return fetcher.DependencySnippet(ecosystem, name, pkg.Version, opts)
+}
+
+// stringSlice converts a JSON array argument into a string slice, ignoring non-string items
+func stringSlice(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// packageBatchResult is the structured per-entry outcome of get_packages_info
+type packageBatchResult struct {
+	Ecosystem string          `json:"ecosystem"`
+	Package   string          `json:"package"`
+	Version   string          `json:"version,omitempty"`
+	Info      *PackageSummary `json:"info,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+func (s *MCPServer) getPackagesInfo(args map[string]interface{}) (string, error) {
+	entries, ok := args["entries"].([]interface{})
+	if !ok || len(entries) == 0 {
+		return "", fmt.Errorf("entries parameter is required")
+	}
+	if len(entries) > maxBatchPackages {
+		return "", fmt.Errorf("too many entries: %d (maximum is %d)", len(entries), maxBatchPackages)
+	}
+
+	results := make([]packageBatchResult, len(entries))
+	for i, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("entry %d must be an object with ecosystem and package", i)
+		}
+		results[i].Ecosystem, _ = entry["ecosystem"].(string)
+		results[i].Package, _ = entry["package"].(string)
+		results[i].Version, _ = entry["version"].(string)
+		if results[i].Ecosystem == "" || results[i].Package == "" {
+			return "", fmt.Errorf("entry %d: ecosystem and package are required", i)
+		}
+	}
+
+	// Fetch in parallel; the fetchers' shared transport limits concurrency per host
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(r *packageBatchResult) {
+			defer wg.Done()
+			info, err := s.fetchPackage(r.Ecosystem, r.Package, r.Version)
+			if err != nil {
+				r.Error = err.Error()
+				return
+			}
+			r.Info = info
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return buildPackagesSummary(results)
+}
+
+func buildPackagesSummary(results []packageBatchResult) (string, error) {
+	var content strings.Builder
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+
+	content.WriteString("# Packages Summary\n\n")
+	fmt.Fprintf(&content, "**Packages:** %d (%d failed)\n\n", len(results), failed)
+
+	content.WriteString("| Ecosystem | Package | Version | License | Description |\n")
+	content.WriteString("|-----------|---------|---------|---------|-------------|\n")
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Fprintf(&content, "| %s | %s | %s | - | Error: %s |\n", r.Ecosystem, r.Package, orDash(r.Version), tableCell(r.Error))
+			continue
+		}
+		fmt.Fprintf(&content, "| %s | %s | %s | %s | %s |\n", r.Info.Ecosystem, r.Info.Name, r.Info.Version, orDash(r.Info.License), tableCell(r.Info.Description))
+	}
+	content.WriteString("\n")
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal results: %w", err)
+	}
+
+	content.WriteString("## Structured Data\n\n")
+	content.WriteString("```json\n")
+	content.Write(data)
+	content.WriteString("\n```\n")
+
+	return content.String(), nil
+}
+
+// tableCell flattens text so it fits in a single markdown table cell
+func tableCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.ReplaceAll(s, "|", "\\|")
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
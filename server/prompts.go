@@ -0,0 +1,299 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/incu6us/open-context/fetcher"
+	"github.com/incu6us/open-context/manifest"
+)
+
+const (
+	// maxPromptDependencies caps the dependencies a project prompt lists
+	maxPromptDependencies = 100
+)
+
+// packageToolArgs names the package argument of each ecosystem's info tool
+var packageToolArgs = map[string]string{
+	"npm":       "packageName",
+	"python":    "packageName",
+	"rust":      "crateName",
+	"go":        "importPath",
+	"ruby":      "gemName",
+	"hex":       "packageName",
+	"cocoapods": "podName",
+	"conan":     "packageName",
+}
+
+// packageToolCall renders the info tool call for a package, e.g.
+// `open-context_get_npm_info` {"packageName":"express","version":"4.18.2"}
+func packageToolCall(ecosystem, name, version string) string {
+	args := map[string]interface{}{packageToolArgs[ecosystem]: name}
+	if ecosystem == "go" {
+		args["type"] = "library"
+	}
+	if version != "" {
+		args["version"] = version
+	}
+	return toolCall(packageTools[ecosystem], args)
+}
+
+func toolCall(tool string, args map[string]interface{}) string {
+	data, _ := json.Marshal(args)
+	return fmt.Sprintf("`%s` `%s`", tool, data)
+}
+
+// upgradeAssistantPrompt guides the upgrade of one package, or of every
+// dependency of a project
+func (s *MCPServer) upgradeAssistantPrompt(args map[string]interface{}) (string, error) {
+	project, _ := args["project"].(string)
+	ecosystem, _ := args["ecosystem"].(string)
+	name, _ := args["package"].(string)
+	from, _ := args["from"].(string)
+	to, _ := args["to"].(string)
+
+	if project != "" {
+		deps, manifests, err := projectDependencies(project)
+		if err != nil {
+			return "", err
+		}
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "Help me upgrade the dependencies of `%s` (%s).\n\n", project, strings.Join(manifests, ", "))
+		writeDependencyTable(&text, deps)
+		text.WriteString("Work through these steps with the open-context tools:\n\n")
+		text.WriteString("1. **Find the latest versions.** Call:\n\n")
+		writeBatchCalls(&text, deps, false)
+		text.WriteString("2. **Pick upgrades.** List the dependencies whose latest version is newer than the one in use, grouped into patch, minor, and major upgrades.\n")
+		text.WriteString("3. **Check each major upgrade.** Read the package info for the current and latest versions, search `open-context_search_docs` for the package's migration guide, and note breaking changes that affect this project.\n")
+		text.WriteString("4. **Apply.** Call `open-context_generate_dependency_snippet` for each upgrade to get the manifest edit and install command.\n\n")
+		text.WriteString("Finish with an upgrade plan ordered by risk: safe patch and minor upgrades first, then each major upgrade with its required code changes.")
+		return text.String(), nil
+	}
+
+	if ecosystem == "" || name == "" {
+		return "", fmt.Errorf("upgrade-assistant needs either project, or ecosystem and package")
+	}
+	canonical, err := fetcher.NormalizeEcosystem(ecosystem)
+	if err != nil {
+		return "", err
+	}
+
+	target := to
+	if target == "" {
+		target = "the latest version"
+	}
+	current := from
+	if current == "" {
+		current = "the version in use"
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Help me upgrade the %s package **%s** from %s to %s.\n\n", canonical, name, current, target)
+	text.WriteString("Work through these steps with the open-context tools:\n\n")
+
+	text.WriteString("1. **Releases.** Call ")
+	if from != "" {
+		fmt.Fprintf(&text, "%s and ", packageToolCall(canonical, name, from))
+	}
+	fmt.Fprintf(&text, "%s", packageToolCall(canonical, name, to))
+	if to == "" {
+		text.WriteString(" (no version resolves the latest release)")
+	}
+	text.WriteString(".\n")
+
+	fmt.Fprintf(&text, "2. **What changed.** Compare the two releases: breaking changes, deprecations, and new runtime or peer dependency requirements. Look for a migration guide with %s.\n",
+		toolCall("open-context_search_docs", map[string]interface{}{"query": name + " migration"}))
+
+	text.WriteString("3. **Security.** ")
+	s.writeVulnerabilityStep(&text, canonical, name, from, to)
+
+	args = map[string]interface{}{"ecosystem": canonical, "package": name}
+	if to != "" {
+		args["version"] = to
+	}
+	fmt.Fprintf(&text, "4. **Apply.** Call %s for the manifest edit and install command.\n\n", toolCall("open-context_generate_dependency_snippet", args))
+
+	text.WriteString("Finish with a risk rating (low, medium, or high), the breaking changes that affect my code, and the edit to apply.")
+	return text.String(), nil
+}
+
+// writeVulnerabilityStep explains how to check both versions of a package
+// for known vulnerabilities with the tools available for its ecosystem
+func (s *MCPServer) writeVulnerabilityStep(text *strings.Builder, ecosystem, name, from, to string) {
+	switch {
+	case ecosystem == "go":
+		text.WriteString("Call ")
+		if from != "" {
+			fmt.Fprintf(text, "%s and ", toolCall("open-context_get_go_vulns", map[string]interface{}{"module": name, "version": from}))
+		}
+		args := map[string]interface{}{"module": name}
+		if to != "" {
+			args["version"] = to
+		}
+		fmt.Fprintf(text, "%s, and say which vulnerabilities the upgrade fixes or introduces.\n", toolCall("open-context_get_go_vulns", args))
+	case s.advisories && (ecosystem == "npm" || ecosystem == "python" || ecosystem == "rust"):
+		text.WriteString("The package info from step 1 ends with a **Known Advisories** section for each version; say which advisories the upgrade fixes.\n")
+	default:
+		text.WriteString("open-context has no vulnerability data for this package")
+		if ecosystem == "npm" || ecosystem == "python" || ecosystem == "rust" {
+			text.WriteString(" (set `security_advisories: true` in config.yaml to add GitHub advisories to package info)")
+		}
+		text.WriteString("; mention any security fixes the release notes call out.\n")
+	}
+}
+
+// dependencyReviewPrompt guides a review of a project's dependencies for
+// outdated versions and known vulnerabilities
+func (s *MCPServer) dependencyReviewPrompt(args map[string]interface{}) (string, error) {
+	project, _ := args["project"].(string)
+	if project == "" {
+		return "", fmt.Errorf("project argument is required")
+	}
+
+	deps, manifests, err := projectDependencies(project)
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Review the dependencies of `%s` (%s) for outdated versions and known vulnerabilities.\n\n", project, strings.Join(manifests, ", "))
+	writeDependencyTable(&text, deps)
+	text.WriteString("Work through these steps with the open-context tools:\n\n")
+
+	text.WriteString("1. **Outdated.** Call the following to get the latest versions, and compare them with the versions above:\n\n")
+	writeBatchCalls(&text, deps, false)
+
+	text.WriteString("2. **Vulnerabilities.**\n\n")
+	var goCalls []string
+	var unpinned int
+	for _, dep := range deps {
+		if dep.Version == "" {
+			unpinned++
+			continue
+		}
+		if dep.Ecosystem == "go" {
+			goCalls = append(goCalls, toolCall("open-context_get_go_vulns", map[string]interface{}{"module": dep.Name, "version": dep.Version}))
+		}
+	}
+	if len(goCalls) > 0 {
+		text.WriteString("   For the Go modules, call:\n\n")
+		for _, call := range goCalls {
+			fmt.Fprintf(&text, "   - %s\n", call)
+		}
+		text.WriteString("\n")
+	}
+	if s.advisories {
+		text.WriteString("   For npm, Python, and Rust packages, fetch the versions in use; their package info ends with a **Known Advisories** section:\n\n")
+		writeBatchCalls(&text, deps, true)
+	} else {
+		text.WriteString("   Security advisories for npm, Python, and Rust packages are disabled; suggest setting `security_advisories: true` in config.yaml if the project uses them.\n\n")
+	}
+	if unpinned > 0 {
+		fmt.Fprintf(&text, "   Dependencies declared with a version range (%d) cannot be checked precisely; say so in the report.\n\n", unpinned)
+	}
+
+	text.WriteString("3. **Report.** Summarize in a table with package, version in use, latest version, how far behind (patch, minor, or major), known vulnerabilities, and a recommendation. List vulnerable dependencies first.")
+	return text.String(), nil
+}
+
+// projectDependencies parses the manifests of a project directory or a
+// single manifest, preferring lockfile versions, and returns the
+// dependencies sorted by ecosystem and name along with the manifest paths
+func projectDependencies(project string) ([]manifest.Dependency, []string, error) {
+	path := expandHome(project)
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, fmt.Errorf("project not found: %s", project)
+	}
+
+	files := findManifests([]string{path})
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no dependency manifests found in %s", project)
+	}
+
+	seen := make(map[string]bool)
+	var deps []manifest.Dependency
+	for _, file := range files {
+		parsed, err := manifest.Parse(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		if resolved, err := manifest.Resolve(file, parsed); err == nil {
+			parsed = resolved
+		}
+		for _, dep := range parsed {
+			key := dep.Ecosystem + "/" + dep.Name
+			if !seen[key] {
+				seen[key] = true
+				deps = append(deps, dep)
+			}
+		}
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Ecosystem != deps[j].Ecosystem {
+			return deps[i].Ecosystem < deps[j].Ecosystem
+		}
+		return deps[i].Name < deps[j].Name
+	})
+	return deps, files, nil
+}
+
+func writeDependencyTable(text *strings.Builder, deps []manifest.Dependency) {
+	if len(deps) == 0 {
+		text.WriteString("The manifests declare no dependencies.\n\n")
+		return
+	}
+
+	text.WriteString("| Ecosystem | Package | Version | Dev |\n")
+	text.WriteString("|-----------|---------|---------|-----|\n")
+	for i, dep := range deps {
+		if i == maxPromptDependencies {
+			fmt.Fprintf(text, "\n%d more dependencies are not listed.\n", len(deps)-maxPromptDependencies)
+			break
+		}
+		version := dep.Version
+		if version == "" {
+			version = dep.Constraint
+		}
+		dev := ""
+		if dep.Dev {
+			dev = "yes"
+		}
+		fmt.Fprintf(text, "| %s | %s | %s | %s |\n", dep.Ecosystem, dep.Name, tableCell(version), orDash(dev))
+	}
+	text.WriteString("\n")
+}
+
+// writeBatchCalls lists get_packages_info calls covering the dependencies,
+// maxBatchPackages entries each. With pinned, only dependencies with an exact
+// version in an ecosystem with advisories are included, at that version.
+func writeBatchCalls(text *strings.Builder, deps []manifest.Dependency, pinned bool) {
+	var entries []map[string]interface{}
+	for i, dep := range deps {
+		if i == maxPromptDependencies {
+			break
+		}
+		entry := map[string]interface{}{"ecosystem": dep.Ecosystem, "package": dep.Name}
+		if pinned {
+			if dep.Version == "" || (dep.Ecosystem != "npm" && dep.Ecosystem != "python" && dep.Ecosystem != "rust") {
+				continue
+			}
+			entry["version"] = dep.Version
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		text.WriteString("   (no dependencies to check)\n\n")
+		return
+	}
+	for start := 0; start < len(entries); start += maxBatchPackages {
+		batch := entries[start:min(start+maxBatchPackages, len(entries))]
+		fmt.Fprintf(text, "   - %s\n", toolCall("open-context_get_packages_info", map[string]interface{}{"entries": batch}))
+	}
+	text.WriteString("\n")
+}
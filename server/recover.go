@@ -0,0 +1,46 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// errInternal marks a request that failed because its handler panicked
+var errInternal = errors.New("internal error")
+
+// recoverPanic, when deferred, turns a panic into an errInternal error in
+// *err and logs its stack trace, so one malformed upstream payload fails a
+// single request instead of taking down every session
+func recoverPanic(err *error, what string) {
+	if p := recover(); p != nil {
+		log.Printf("Panic in %s: %v\n%s", what, p, debug.Stack())
+		*err = fmt.Errorf("%w: %s panicked: %v", errInternal, what, p)
+	}
+}
+
+// handleRequestSafely is handleRequest for the transport loops: a panic
+// while handling the request is answered with a JSON-RPC internal error
+func (s *MCPServer) handleRequestSafely(req Request, sess *session) (resp Response) {
+	var err error
+	defer func() {
+		if err != nil {
+			resp = internalErrorResponse(req.ID, err)
+		}
+	}()
+	defer recoverPanic(&err, req.Method)
+
+	return s.handleRequest(req, sess)
+}
+
+func internalErrorResponse(id interface{}, err error) Response {
+	return Response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &Error{
+			Code:    -32603,
+			Message: err.Error(),
+		},
+	}
+}
@@ -0,0 +1,338 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// restRoute maps a REST resource to the tool that serves it
+type restRoute struct {
+	tool string
+	// args builds the tool arguments from the path ("express") and its
+	// optional "@version" suffix ("4.18.2")
+	args func(name, version string) map[string]interface{}
+}
+
+// restRoutes maps the first path segment under /api/v1/ to a tool, e.g.
+// GET /api/v1/npm/express@4.18.2 or GET /api/v1/go/github.com/gin-gonic/gin@v1.9.1
+var restRoutes = map[string]restRoute{
+	"go":                 {"open-context_get_go_info", goArgs},
+	"go-vulns":           {"open-context_get_go_vulns", nameArgs("module")},
+	"go-deps":            {"open-context_get_go_dependencies", nameArgs("module")},
+	"npm":                {"open-context_get_npm_info", nameArgs("packageName")},
+	"python":             {"open-context_get_python_info", nameArgs("packageName")},
+	"pypi":               {"open-context_get_python_info", nameArgs("packageName")},
+	"python-version":     {"open-context_get_python_version", versionArgs},
+	"rust":               {"open-context_get_rust_info", nameArgs("crateName")},
+	"rust-error":         {"open-context_get_rust_error", pathArgs("code")},
+	"crates":             {"open-context_get_rust_info", nameArgs("crateName")},
+	"gems":               {"open-context_get_gem_info", nameArgs("gemName")},
+	"hex":                {"open-context_get_hex_info", nameArgs("packageName")},
+	"cocoapods":          {"open-context_get_cocoapod_info", nameArgs("podName")},
+	"conan":              {"open-context_get_conan_info", nameArgs("packageName")},
+	"node":               {"open-context_get_node_info", versionArgs},
+	"node-schedule":      {"open-context_get_node_schedule", versionArgs},
+	"java":               {"open-context_get_java_info", versionArgs},
+	"typescript":         {"open-context_get_typescript_info", versionArgs},
+	"typescript-feature": {"open-context_get_typescript_feature", pathArgs("name")},
+	"ts-diagnostic":      {"open-context_get_ts_diagnostic", pathArgs("code")},
+	"http":               {"open-context_get_http_reference", pathArgs("name")},
+	"rfc":                {"open-context_get_rfc", pathArgs("document")},
+	"web-spec":           {"open-context_get_web_spec", pathArgs("spec")},
+	"posix":              {"open-context_get_posix_util", pathArgs("name")},
+	"man":                {"open-context_get_man_page", pathArgs("name")},
+	"tldr":               {"open-context_get_tldr", pathArgs("command")},
+	"buf":                {"open-context_get_buf_module", nameArgs("module")},
+	"license":            {"open-context_get_license", pathArgs("license")},
+	"sql-feature":        {"open-context_compare_sql_feature", pathArgs("feature")},
+	"react":              {"open-context_get_react_info", versionArgs},
+	"react-api":          {"open-context_get_react_api", pathArgs("symbol")},
+	"nextjs":             {"open-context_get_nextjs_info", versionArgs},
+	"nextjs-docs":        {"open-context_get_nextjs_docs", nameArgs("path")},
+	"ansible":            {"open-context_get_ansible_info", versionArgs},
+	"ansible-collection": {"open-context_get_ansible_collection", nameArgs("collection")},
+	"terraform":          {"open-context_get_terraform_info", versionArgs},
+	"terraform-examples": {"open-context_find_terraform_examples", pathArgs("resource")},
+	"terraform-schema":   {"open-context_get_terraform_schema", pathArgs("resource")},
+	"pulumi":             {"open-context_get_pulumi_info", nameArgs("package")},
+	"cdk":                {"open-context_get_cdk_info", versionArgs},
+	"deno":               {"open-context_get_deno_info", versionArgs},
+	"bun":                {"open-context_get_bun_info", versionArgs},
+	"jenkins":            {"open-context_get_jenkins_info", versionArgs},
+	"jenkins-plugin":     {"open-context_get_jenkins_plugin", nameArgs("plugin")},
+	"kubernetes":         {"open-context_get_kubernetes_info", versionArgs},
+	"helm":               {"open-context_get_helm_info", versionArgs},
+	"helm-chart":         {"open-context_get_helm_chart", nameArgs("chart")},
+	"nginx":              {"open-context_get_nginx_info", versionArgs},
+	"docker":             {"open-context_get_docker_image", dockerArgs},
+	"docker-engine":      {"open-context_get_docker_engine_info", versionArgs},
+	"git":                {"open-context_get_git_info", versionArgs},
+	"github-action":      {"open-context_get_github_action", nameArgs("repository")},
+	"github-readme":      {"open-context_get_github_readme", githubReadmeArgs},
+	"github-release":     {"open-context_get_github_release", githubReleaseArgs},
+	"gitlab":             {"open-context_get_gitlab_project", nameArgs("project")},
+	"devdocs":            {"open-context_get_devdocs", pathArgs("docset")},
+	"llms-txt":           {"open-context_get_llms_txt", pathArgs("url")},
+	"site":               {"open-context_fetch_site", pathArgs("url")},
+	"changelog":          {"open-context_compare_versions", changelogArgs},
+}
+
+// restReadOnlyTools are the tools GET /api/v1/tools/{tool} runs besides
+// those of restRoutes. Any web page can make the user's browser send a GET,
+// so tools that act on the server's machine, such as
+// open-context_import_docs, are left out.
+var restReadOnlyTools = map[string]bool{
+	"open-context_search_docs":                 true,
+	"open-context_get_docs":                    true,
+	"open-context_list_docs":                   true,
+	"open-context_smart_docs":                  true,
+	"open-context_get_code_examples":           true,
+	"open-context_generate_dependency_snippet": true,
+	"open-context_get_packages_info":           true,
+	"open-context_get_so_answers":              true,
+	"open-context_explain_error":               true,
+	"open-context_get_local_symbol":            true,
+}
+
+// restGetAllowed reports whether GET /api/v1/tools/{tool} may run tool
+func restGetAllowed(tool string) bool {
+	if restReadOnlyTools[tool] {
+		return true
+	}
+	for _, route := range restRoutes {
+		if route.tool == tool {
+			return true
+		}
+	}
+	return false
+}
+
+func nameArgs(nameArg string) func(name, version string) map[string]interface{} {
+	return func(name, version string) map[string]interface{} {
+		args := map[string]interface{}{nameArg: name}
+		if version != "" {
+			args["version"] = version
+		}
+		return args
+	}
+}
+
+// pathArgs passes the whole path as one argument for tools that take no version
+func pathArgs(arg string) func(name, version string) map[string]interface{} {
+	return func(name, version string) map[string]interface{} {
+		if version != "" {
+			name += "@" + version
+		}
+		return map[string]interface{}{arg: name}
+	}
+}
+
+func versionArgs(name, version string) map[string]interface{} {
+	return map[string]interface{}{"version": name}
+}
+
+// goArgs serves Go releases (/api/v1/go/1.22) and modules (/api/v1/go/github.com/gin-gonic/gin@v1.9.1)
+func goArgs(name, version string) map[string]interface{} {
+	if !strings.Contains(name, "/") && name != "" && name[0] >= '0' && name[0] <= '9' {
+		return map[string]interface{}{"type": "version", "version": name}
+	}
+	args := map[string]interface{}{"type": "library", "importPath": name}
+	if version != "" {
+		args["version"] = version
+	}
+	return args
+}
+
+// githubReadmeArgs serves /api/v1/github-readme/{owner}/{repo}[@ref]
+func githubReadmeArgs(name, version string) map[string]interface{} {
+	args := map[string]interface{}{"repository": name}
+	if version != "" {
+		args["ref"] = version
+	}
+	return args
+}
+
+// githubReleaseArgs serves /api/v1/github-release/{owner}/{repo}[@tag]
+func githubReleaseArgs(name, version string) map[string]interface{} {
+	args := map[string]interface{}{"repository": name}
+	if version != "" {
+		args["tag"] = version
+	}
+	return args
+}
+
+// changelogArgs serves /api/v1/changelog/{product}@{from}?to=...
+func changelogArgs(name, version string) map[string]interface{} {
+	return map[string]interface{}{"product": name, "from": version}
+}
+
+func dockerArgs(name, version string) map[string]interface{} {
+	if version == "" {
+		version = "latest"
+	}
+	return map[string]interface{}{"image": name, "tag": version}
+}
+
+// restResponse is the JSON body returned by the REST endpoints
+type restResponse struct {
+	Tool    string `json:"tool,omitempty"`
+	Content string `json:"content,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// handleREST serves the plain REST facade under /api/v1/:
+//
+//	GET /api/v1/search?q=...&language=...
+//	GET /api/v1/docs and /api/v1/docs/{language}/{topic}
+//	GET /api/v1/{resource}/{name}[@version] (see restRoutes)
+//	GET /api/v1/tools/{tool}?arg=value for read-only tools
+//
+// Query parameters are passed to the tool as extra arguments.
+func (h *HTTPServer) handleREST(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeREST(w, http.StatusMethodNotAllowed, restResponse{Error: "method not allowed"})
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/")
+	resource, rest, _ := strings.Cut(path, "/")
+
+	var tool string
+	var args map[string]interface{}
+
+	switch resource {
+	case "search":
+		tool = "open-context_search_docs"
+		args = map[string]interface{}{"query": r.URL.Query().Get("q")}
+
+	case "docs":
+		if rest == "" {
+			tool = "open-context_list_docs"
+			break
+		}
+		language, topic, _ := strings.Cut(rest, "/")
+		tool = "open-context_get_docs"
+		args = map[string]interface{}{"language": language, "topic": topic}
+
+	case "tools":
+		tool = rest
+		if !restGetAllowed(tool) {
+			writeREST(w, http.StatusForbidden, restResponse{Tool: tool, Error: tool + " is not available over REST; call it through MCP"})
+			return
+		}
+
+	default:
+		route, ok := restRoutes[resource]
+		if !ok || rest == "" {
+			writeREST(w, http.StatusNotFound, restResponse{Error: "unknown endpoint: " + r.URL.Path})
+			return
+		}
+		tool = route.tool
+		args = route.args(splitNameVersion(rest))
+	}
+
+	args = mergeQueryArgs(h.mcp.tools(), tool, args, r.URL.Query())
+
+	content, err := h.mcp.callEnabledTool(tool, args)
+	if err != nil {
+		writeREST(w, restStatus(err), restResponse{Tool: tool, Error: err.Error()})
+		return
+	}
+
+	writeREST(w, http.StatusOK, restResponse{Tool: tool, Content: content})
+}
+
+// splitNameVersion splits "express@4.18.2" and "@types/node@20.1.0" at the version separator
+func splitNameVersion(s string) (string, string) {
+	if idx := strings.LastIndex(s, "@"); idx > 0 {
+		return s[:idx], s[idx+1:]
+	}
+	return s, ""
+}
+
+// mergeQueryArgs adds query parameters to args, converted to the types the
+// tool's input schema declares. Path-derived arguments take precedence.
+func mergeQueryArgs(tools []ToolInfo, tool string, args map[string]interface{}, query url.Values) map[string]interface{} {
+	if args == nil {
+		args = make(map[string]interface{})
+	}
+
+	properties := map[string]interface{}{}
+	for _, t := range tools {
+		if t.Name != tool {
+			continue
+		}
+		if schema, ok := t.InputSchema.(map[string]interface{}); ok {
+			properties, _ = schema["properties"].(map[string]interface{})
+		}
+	}
+
+	for key, values := range query {
+		if _, exists := args[key]; exists || len(values) == 0 {
+			continue
+		}
+
+		kind := ""
+		if prop, ok := properties[key].(map[string]interface{}); ok {
+			kind, _ = prop["type"].(string)
+		}
+
+		switch kind {
+		case "integer", "number":
+			if n, err := strconv.ParseFloat(values[0], 64); err == nil {
+				args[key] = n
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(values[0]); err == nil {
+				args[key] = b
+			}
+		case "array":
+			items := make([]interface{}, len(values))
+			for i, v := range values {
+				items[i] = v
+			}
+			args[key] = items
+		case "object":
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(values[0]), &obj); err == nil {
+				args[key] = obj
+			}
+		default:
+			args[key] = values[0]
+		}
+	}
+
+	return args
+}
+
+// restStatus maps a tool error to an HTTP status code
+func restStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, errInternal):
+		return http.StatusInternalServerError
+	case errors.Is(err, errUnknownTool):
+		return http.StatusNotFound
+	case strings.Contains(msg, "parameter is required"), strings.Contains(msg, "unsupported"):
+		return http.StatusBadRequest
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "not configured"):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func writeREST(w http.ResponseWriter, status int, body restResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
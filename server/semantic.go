@@ -0,0 +1,124 @@
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/embeddings"
+	"github.com/incu6us/open-context/provider"
+)
+
+const (
+	// minSimilarity is the cosine similarity below which a topic is not
+	// considered related to the query
+	minSimilarity = 0.35
+
+	// semanticWeight scales similarity to the keyword scores (a title match
+	// is worth 10)
+	semanticWeight = 10.0
+)
+
+// semanticIndex ranks documentation topics by embedding similarity to the
+// query. Topic embeddings are cached on disk by content hash, so only new or
+// changed topics are sent to the embeddings API when the index is rebuilt.
+type semanticIndex struct {
+	embedder embeddings.Embedder
+
+	mu      sync.Mutex
+	topics  []*provider.Topic
+	vectors [][]float32
+}
+
+func newSemanticIndex(cfg config.SemanticSearch, cacheDir string) (*semanticIndex, error) {
+	client, err := embeddings.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(cacheDir, "embeddings")
+	return &semanticIndex{
+		embedder: embeddings.NewCache(client, dir, client.Model(), cfg.BatchSize),
+	}, nil
+}
+
+// index embeds topics not yet indexed
+func (idx *semanticIndex) index(topics []*provider.Topic) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if len(idx.topics) == len(topics) {
+		return nil
+	}
+
+	texts := make([]string, len(topics))
+	for i, t := range topics {
+		texts[i] = topicText(t)
+	}
+	vectors, err := idx.embedder.Embed(texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed documentation: %w", err)
+	}
+
+	idx.topics = topics
+	idx.vectors = vectors
+	return nil
+}
+
+// rank merges semantic matches into keyword results, limited to the
+// documentations listed in documentation as provider.Search is and scaled by
+// weight
+func (idx *semanticIndex) rank(query, documentation string, topics []*provider.Topic, results []provider.SearchResult, weight func(*provider.Topic) float64) ([]provider.SearchResult, error) {
+	if err := idx.index(topics); err != nil {
+		return nil, err
+	}
+
+	queryVectors, err := idx.embedder.Embed([]string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	byID := make(map[string]int, len(results))
+	for i, r := range results {
+		byID[r.Documentation+"/"+r.ID] = i
+	}
+
+	names := provider.SplitDocumentations(documentation)
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for i, t := range idx.topics {
+		if !provider.MatchesDocumentation(names, t.Documentation) {
+			continue
+		}
+		sim := embeddings.Cosine(queryVectors[0], idx.vectors[i])
+		if sim < minSimilarity {
+			continue
+		}
+		score := sim * semanticWeight * weight(t)
+
+		key := t.Documentation + "/" + t.ID
+		if j, ok := byID[key]; ok {
+			results[j].Score += score
+			continue
+		}
+		byID[key] = len(results)
+		results = append(results, provider.SearchResult{
+			ID:            t.ID,
+			Title:         t.Title,
+			Description:   t.Description,
+			Documentation: t.Documentation,
+			Score:         score,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results, nil
+}
+
+func topicText(t *provider.Topic) string {
+	return strings.Join([]string{t.Title, t.Description, strings.Join(t.Keywords, ", "), t.Content}, "\n")
+}
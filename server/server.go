@@ -3,32 +3,97 @@ package server
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/incu6us/open-context/cache"
 	"github.com/incu6us/open-context/config"
 	"github.com/incu6us/open-context/fetcher"
+	"github.com/incu6us/open-context/gopls"
 	"github.com/incu6us/open-context/provider"
+	"github.com/incu6us/open-context/summarize"
 )
 
 type MCPServer struct {
 	docProvider          *provider.Provider
 	goFetcher            *fetcher.GoFetcher
+	goVulnFetcher        *fetcher.GoVulnFetcher
+	goDepsFetcher        *fetcher.GoDepsFetcher
 	npmFetcher           *fetcher.NPMFetcher
 	pythonFetcher        *fetcher.PythonFetcher
 	rustFetcher          *fetcher.RustFetcher
+	rubyGemsFetcher      *fetcher.RubyGemsFetcher
+	hexFetcher           *fetcher.HexFetcher
+	cocoaPodsFetcher     *fetcher.CocoaPodsFetcher
+	conanFetcher         *fetcher.ConanFetcher
 	nodeFetcher          *fetcher.NodeFetcher
+	javaFetcher          *fetcher.JavaFetcher
+	denoFetcher          *fetcher.DenoFetcher
+	bunFetcher           *fetcher.BunFetcher
 	typescriptFetcher    *fetcher.TypeScriptFetcher
 	nextjsFetcher        *fetcher.NextJSFetcher
 	reactFetcher         *fetcher.ReactFetcher
 	ansibleFetcher       *fetcher.AnsibleFetcher
 	terraformFetcher     *fetcher.TerraformFetcher
+	pulumiFetcher        *fetcher.PulumiFetcher
+	cdkFetcher           *fetcher.CDKFetcher
+	dockerEngineFetcher  *fetcher.DockerEngineFetcher
+	gitFetcher           *fetcher.GitFetcher
 	jenkinsFetcher       *fetcher.JenkinsFetcher
 	kubernetesFetcher    *fetcher.KubernetesFetcher
 	helmFetcher          *fetcher.HelmFetcher
+	nginxFetcher         *fetcher.NginxFetcher
 	dockerFetcher        *fetcher.DockerImageFetcher
 	githubActionsFetcher *fetcher.GitHubActionsFetcher
+	githubReadmeFetcher  *fetcher.GitHubReadmeFetcher
+	githubReleaseFetcher *fetcher.GitHubReleaseFetcher
+	gitlabFetcher        *fetcher.GitLabFetcher
+	stackExchangeFetcher *fetcher.StackExchangeFetcher
+	errorFetcher         *fetcher.ErrorFetcher
+	httpReferenceFetcher *fetcher.HTTPReferenceFetcher
+	rfcFetcher           *fetcher.RFCFetcher
+	webSpecFetcher       *fetcher.WebSpecFetcher
+	posixFetcher         *fetcher.PosixFetcher
+	manFetcher           *fetcher.ManFetcher
+	tldrFetcher          *fetcher.TldrFetcher
+	bufFetcher           *fetcher.BufFetcher
+	licenseFetcher       *fetcher.LicenseFetcher
+	changelogFetcher     *fetcher.ChangelogFetcher
+	versionsFetcher      *fetcher.VersionsFetcher
+	devDocsFetcher       *fetcher.DevDocsFetcher
+	llmsTxtFetcher       *fetcher.LLMsTxtFetcher
+	siteFetcher          *fetcher.SiteFetcher
+	localDocsFetcher     *fetcher.LocalDocsFetcher
+	// importRoots are the directories import_docs may import
+	importRoots []string
+	// goplsClient is nil unless go_workspace is configured
+	goplsClient *gopls.Client
+	// searchCache is nil unless redis is configured
+	searchCache    *cache.RedisClient
+	searchCacheTTL time.Duration
+	// semantic is nil unless semantic_search is configured
+	semantic *semanticIndex
+	// summarizer is nil unless summarizer is configured; get_docs then
+	// summarizes extractively
+	summarizer summarize.Summarizer
+	// advisories mirrors security_advisories, which adds advisories to package info
+	advisories bool
+	// customTools are the tools of custom_sources
+	customTools []*customTool
+	// enabledTools is nil unless enabled_tools limits the tools offered
+	enabledTools map[string]bool
+	// hideDeprecated mirrors hide_deprecated_tools
+	hideDeprecated bool
+	// deprecationWarned records the deprecated tools whose use was logged
+	deprecationWarned sync.Map
+	// fixtures is nil unless tool calls are replayed from or recorded to
+	// fixtures
+	fixtures *fixtureStore
 }
 
 func NewMCPServer() (*MCPServer, error) {
@@ -38,29 +103,131 @@ func NewMCPServer() (*MCPServer, error) {
 		return nil, fmt.Errorf("failed to initialize cache directory: %w", err)
 	}
 
+	// Upgrade caches written by older releases before anything reads them
+	if err := cache.Migrate(cacheDir); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
 	// Create doc provider with cache directory
 	docProvider, err := provider.NewProvider(cacheDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize doc provider: %w", err)
 	}
 
+	var goplsClient *gopls.Client
+	var importRoots []string
+	var searchCache *cache.RedisClient
+	var searchCacheTTL time.Duration
+	var semantic *semanticIndex
+	var summarizer summarize.Summarizer
+	var advisories bool
+	var customTools []*customTool
+	var enabledTools map[string]bool
+	var hideDeprecated bool
+	if cfg, err := config.Load(); err == nil {
+		advisories = cfg.SecurityAdvisories
+		hideDeprecated = cfg.HideDeprecatedTools
+		customTools = newCustomTools(cfg.CustomSources, cacheDir)
+		if len(cfg.EnabledTools) > 0 {
+			enabledTools = make(map[string]bool, len(cfg.EnabledTools))
+			for _, name := range cfg.EnabledTools {
+				enabledTools[name] = true
+			}
+		}
+		if cfg.GoWorkspace != "" {
+			goplsClient = gopls.NewClient(expandHome(cfg.GoWorkspace))
+		}
+		for _, root := range cfg.ImportRoots {
+			importRoots = append(importRoots, expandHome(root))
+		}
+		if cfg.Redis.Addr != "" {
+			searchCache = cache.NewRedisClient(cfg.Redis)
+			searchCacheTTL = cfg.Redis.HotTTL.Duration
+			if searchCacheTTL <= 0 {
+				searchCacheTTL = time.Hour
+			}
+		}
+		if len(cfg.SearchWeights) > 0 {
+			docProvider.SetWeights(cfg.SearchWeights)
+		}
+		if cfg.DisableRecencyBoost {
+			docProvider.SetRecencyBoost(0)
+		}
+		if cfg.SearchVersionRetention != 0 {
+			docProvider.SetVersionRetention(cfg.SearchVersionRetention)
+		}
+		if cfg.Summarizer.Provider != "" {
+			if summarizer, err = newSummarizer(cfg.Summarizer, cacheDir); err != nil {
+				log.Printf("Warning: LLM summaries disabled: %v", err)
+			}
+		}
+		if cfg.SemanticSearch.Provider != "" {
+			if semantic, err = newSemanticIndex(cfg.SemanticSearch, cacheDir); err != nil {
+				log.Printf("Warning: semantic search disabled: %v", err)
+			}
+		}
+	}
+
 	return &MCPServer{
 		docProvider:          docProvider,
 		goFetcher:            fetcher.NewGoFetcher(cacheDir),
+		goVulnFetcher:        fetcher.NewGoVulnFetcher(cacheDir),
+		goDepsFetcher:        fetcher.NewGoDepsFetcher(cacheDir),
 		npmFetcher:           fetcher.NewNPMFetcher(cacheDir),
 		pythonFetcher:        fetcher.NewPythonFetcher(cacheDir),
 		rustFetcher:          fetcher.NewRustFetcher(cacheDir),
+		rubyGemsFetcher:      fetcher.NewRubyGemsFetcher(cacheDir),
+		hexFetcher:           fetcher.NewHexFetcher(cacheDir),
+		cocoaPodsFetcher:     fetcher.NewCocoaPodsFetcher(cacheDir),
+		conanFetcher:         fetcher.NewConanFetcher(cacheDir),
 		nodeFetcher:          fetcher.NewNodeFetcher(cacheDir),
+		javaFetcher:          fetcher.NewJavaFetcher(cacheDir),
+		denoFetcher:          fetcher.NewDenoFetcher(cacheDir),
+		bunFetcher:           fetcher.NewBunFetcher(cacheDir),
 		typescriptFetcher:    fetcher.NewTypeScriptFetcher(cacheDir),
 		nextjsFetcher:        fetcher.NewNextJSFetcher(cacheDir),
 		reactFetcher:         fetcher.NewReactFetcher(cacheDir),
 		ansibleFetcher:       fetcher.NewAnsibleFetcher(cacheDir),
 		terraformFetcher:     fetcher.NewTerraformFetcher(cacheDir),
+		pulumiFetcher:        fetcher.NewPulumiFetcher(cacheDir),
+		cdkFetcher:           fetcher.NewCDKFetcher(cacheDir),
+		dockerEngineFetcher:  fetcher.NewDockerEngineFetcher(cacheDir),
+		gitFetcher:           fetcher.NewGitFetcher(cacheDir),
 		jenkinsFetcher:       fetcher.NewJenkinsFetcher(cacheDir),
 		kubernetesFetcher:    fetcher.NewKubernetesFetcher(cacheDir),
 		helmFetcher:          fetcher.NewHelmFetcher(cacheDir),
+		nginxFetcher:         fetcher.NewNginxFetcher(cacheDir),
 		dockerFetcher:        fetcher.NewDockerImageFetcher(cacheDir),
 		githubActionsFetcher: fetcher.NewGitHubActionsFetcher(cacheDir),
+		githubReadmeFetcher:  fetcher.NewGitHubReadmeFetcher(cacheDir),
+		githubReleaseFetcher: fetcher.NewGitHubReleaseFetcher(cacheDir),
+		gitlabFetcher:        fetcher.NewGitLabFetcher(cacheDir),
+		stackExchangeFetcher: fetcher.NewStackExchangeFetcher(cacheDir),
+		errorFetcher:         fetcher.NewErrorFetcher(cacheDir),
+		httpReferenceFetcher: fetcher.NewHTTPReferenceFetcher(cacheDir),
+		rfcFetcher:           fetcher.NewRFCFetcher(cacheDir),
+		webSpecFetcher:       fetcher.NewWebSpecFetcher(cacheDir),
+		posixFetcher:         fetcher.NewPosixFetcher(cacheDir),
+		manFetcher:           fetcher.NewManFetcher(cacheDir),
+		tldrFetcher:          fetcher.NewTldrFetcher(cacheDir),
+		bufFetcher:           fetcher.NewBufFetcher(cacheDir),
+		licenseFetcher:       fetcher.NewLicenseFetcher(cacheDir),
+		changelogFetcher:     fetcher.NewChangelogFetcher(cacheDir),
+		versionsFetcher:      fetcher.NewVersionsFetcher(cacheDir),
+		devDocsFetcher:       fetcher.NewDevDocsFetcher(cacheDir),
+		llmsTxtFetcher:       fetcher.NewLLMsTxtFetcher(cacheDir),
+		siteFetcher:          fetcher.NewSiteFetcher(cacheDir),
+		localDocsFetcher:     fetcher.NewLocalDocsFetcher(cacheDir),
+		importRoots:          importRoots,
+		goplsClient:          goplsClient,
+		searchCache:          searchCache,
+		searchCacheTTL:       searchCacheTTL,
+		semantic:             semantic,
+		summarizer:           summarizer,
+		advisories:           advisories,
+		customTools:          customTools,
+		enabledTools:         enabledTools,
+		hideDeprecated:       hideDeprecated,
 	}, nil
 }
 
@@ -89,11 +256,17 @@ type ToolInfo struct {
 	Name        string      `json:"name"`
 	Description string      `json:"description"`
 	InputSchema interface{} `json:"inputSchema"`
+	// Deprecated tools keep working until they are removed; ReplacedBy
+	// names the tool to use instead
+	Deprecated bool   `json:"deprecated,omitempty"`
+	ReplacedBy string `json:"replacedBy,omitempty"`
 }
 
 type ServerInfo struct {
-	Name    string `json:"name"`
-	Version string `json:"version"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	GoVersion string `json:"goVersion,omitempty"`
 }
 
 type InitializeResult struct {
@@ -110,6 +283,8 @@ type Capabilities struct {
 func (s *MCPServer) Serve(stdin io.Reader, stdout, stderr io.Writer) error {
 	scanner := bufio.NewScanner(stdin)
 	encoder := json.NewEncoder(stdout)
+	// A stdio connection is a single conversation
+	sess := &session{}
 
 	for scanner.Scan() {
 		line := scanner.Bytes()
@@ -126,7 +301,7 @@ func (s *MCPServer) Serve(stdin io.Reader, stdout, stderr io.Writer) error {
 			continue
 		}
 
-		resp := s.handleRequest(req)
+		resp := s.handleRequestSafely(req, sess)
 		if err := encoder.Encode(resp); err != nil {
 			log.Printf("Error encoding response: %v", err)
 			return err
@@ -136,18 +311,20 @@ func (s *MCPServer) Serve(stdin io.Reader, stdout, stderr io.Writer) error {
 	return scanner.Err()
 }
 
-func (s *MCPServer) handleRequest(req Request) Response {
+// handleRequest dispatches a JSON-RPC request. sess carries the state of the
+// caller's conversation and may be nil for stateless callers.
+func (s *MCPServer) handleRequest(req Request, sess *session) Response {
 	switch req.Method {
 	case "initialize":
 		return s.handleInitialize(req)
 	case "tools/list":
 		return s.handleToolsList(req)
 	case "tools/call":
-		return s.handleToolCall(req)
+		return s.handleToolCall(req, sess)
 	case "prompts/list":
 		return s.handlePromptsList(req)
 	case "prompts/get":
-		return s.handlePromptsGet(req)
+		return s.handlePromptsGet(req, sess)
 	default:
 		return Response{
 			JSONRPC: "2.0",
@@ -171,15 +348,44 @@ func (s *MCPServer) handleInitialize(req Request) Response {
 				Prompts: map[string]interface{}{"listChanged": false},
 			},
 			ServerInfo: ServerInfo{
-				Name:    "open-context",
-				Version: "0.1.0",
+				Name:      "open-context",
+				Version:   buildInfo.Version,
+				Commit:    buildInfo.Commit,
+				GoVersion: buildInfo.GoVersion,
 			},
 		},
 	}
 }
 
 func (s *MCPServer) handleToolsList(req Request) Response {
-	tools := []ToolInfo{
+	return Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"tools": s.listedTools(),
+		},
+	}
+}
+
+// tools returns the enabled built-in tools followed by the custom ones
+func (s *MCPServer) tools() []ToolInfo {
+	var tools []ToolInfo
+	for _, t := range Tools() {
+		if s.toolEnabled(t.Name) {
+			tools = append(tools, withDeprecationNotice(t))
+		}
+	}
+	for _, t := range s.customTools {
+		if s.toolEnabled(t.info.Name) {
+			tools = append(tools, t.info)
+		}
+	}
+	return tools
+}
+
+// Tools returns the definitions of every built-in tool
+func Tools() []ToolInfo {
+	return []ToolInfo{
 		{
 			Name:        "open-context_search_docs",
 			Description: "Search for documentation topics across all available documentation sources",
@@ -192,7 +398,7 @@ func (s *MCPServer) handleToolsList(req Request) Response {
 					},
 					"language": map[string]interface{}{
 						"type":        "string",
-						"description": "Filter by documentation name (e.g., 'go', 'typescript')",
+						"description": "Filter by documentation name; separate several with commas, and end a name with '*' to match by prefix (e.g., 'go', 'go,local-*')",
 					},
 				},
 				"required": []string{"query"},
@@ -216,6 +422,10 @@ func (s *MCPServer) handleToolsList(req Request) Response {
 						"type":        "string",
 						"description": "Topic name (alternative to ID)",
 					},
+					"summary": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return a short summary instead of the full topic (default: false)",
+					},
 				},
 			},
 		},
@@ -226,6 +436,49 @@ func (s *MCPServer) handleToolsList(req Request) Response {
 				"type": "object",
 			},
 		},
+		{
+			Name:        "open-context_smart_docs",
+			Description: "Answer a free-form documentation question with one call: package lookups, release questions, and concept searches are routed to the matching tool and merged with related documentation topics",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Question or lookup (e.g., 'express npm', 'github.com/gin-gonic/gin@v1.9.1', 'what is new in Go 1.22', 'goroutines')",
+					},
+					"ecosystem": map[string]interface{}{
+						"type":        "string",
+						"description": "Treat the query as a package lookup in this ecosystem (optional: npm, python, rust, go, ruby, hex, cocoapods, conan)",
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			Name:        "open-context_get_code_examples",
+			Description: "Return only the fenced code blocks of the documentation topics matching a query or topic ID, optionally filtered by code language",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Search query selecting the topics (e.g., 'http server', 'goroutines')",
+					},
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "Topic ID from search results, instead of a query",
+					},
+					"documentation": map[string]interface{}{
+						"type":        "string",
+						"description": "Only read topics of this documentation (optional, e.g., 'go')",
+					},
+					"language": map[string]interface{}{
+						"type":        "string",
+						"description": "Only return code blocks in this language (optional, e.g., 'go', 'python', 'js', 'bash')",
+					},
+				},
+			},
+		},
 		{
 			Name:        "open-context_get_go_info",
 			Description: "Fetch and cache information about specific Go versions or Go libraries from official sources",
@@ -249,6 +502,46 @@ func (s *MCPServer) handleToolsList(req Request) Response {
 				"required": []string{"type"},
 			},
 		},
+		{
+			Name:        "open-context_get_go_vulns",
+			Description: "Fetch and cache known vulnerabilities in a Go module from the official Go vulnerability database (vuln.go.dev), with affected symbols and fixed versions",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"module": map[string]interface{}{
+						"type":        "string",
+						"description": "Module path (e.g., 'golang.org/x/net', 'github.com/gin-gonic/gin'), or 'stdlib' for the standard library",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Only report vulnerabilities affecting this version (e.g., 'v0.17.0', or 'go1.21.3' for stdlib; optional, defaults to all)",
+					},
+				},
+				"required": []string{"module"},
+			},
+		},
+		{
+			Name:        "open-context_get_go_dependencies",
+			Description: "Fetch and cache the requirements of a Go module version from its go.mod on the module proxy, as a tree of direct and indirect dependencies with documentation links",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"module": map[string]interface{}{
+						"type":        "string",
+						"description": "Module path (e.g., 'github.com/gin-gonic/gin'), optionally with '@version'",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Module version (e.g., 'v1.9.1'; optional, defaults to the latest)",
+					},
+					"depth": map[string]interface{}{
+						"type":        "integer",
+						"description": "Levels of requirements to expand, 1 to 3 (optional, defaults to 1: the module's own requirements)",
+					},
+				},
+				"required": []string{"module"},
+			},
+		},
 		{
 			Name:        "open-context_get_npm_info",
 			Description: "Fetch and cache information about npm packages from the npm registry",
@@ -263,6 +556,14 @@ func (s *MCPServer) handleToolsList(req Request) Response {
 						"type":        "string",
 						"description": "Specific version of the package (optional, defaults to latest)",
 					},
+					"listVersions": map[string]interface{}{
+						"type":        "boolean",
+						"description": "List the dist-tags and most recently published versions with their publish dates instead of the package details (optional)",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "How many recent versions to list with listVersions (optional, default 20, max 100)",
+					},
 				},
 				"required": []string{"packageName"},
 			},
@@ -285,6 +586,20 @@ func (s *MCPServer) handleToolsList(req Request) Response {
 				"required": []string{"packageName"},
 			},
 		},
+		{
+			Name:        "open-context_get_python_version",
+			Description: "Fetch and cache the \"What's New in Python X.Y\" release notes of a CPython version from docs.python.org, with the dates of its releases",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Python version to fetch (e.g., '3.12', '3.11.4'); a patch release adds its release date to the notes of its feature release",
+					},
+				},
+				"required": []string{"version"},
+			},
+		},
 		{
 			Name:        "open-context_get_rust_info",
 			Description: "Fetch and cache information about Rust crates from crates.io",
@@ -303,6 +618,159 @@ func (s *MCPServer) handleToolsList(req Request) Response {
 				"required": []string{"crateName"},
 			},
 		},
+		{
+			Name:        "open-context_get_gem_info",
+			Description: "Fetch and cache information about Ruby gems from rubygems.org",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"gemName": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the Ruby gem (e.g., 'rails', 'rspec', 'nokogiri')",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Specific version of the gem (optional, defaults to latest)",
+					},
+				},
+				"required": []string{"gemName"},
+			},
+		},
+		{
+			Name:        "open-context_get_hex_info",
+			Description: "Fetch and cache information about Elixir/Erlang packages from hex.pm, including mix.exs dependency snippets",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"packageName": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the Hex package (e.g., 'phoenix', 'ecto', 'jason')",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Specific version of the package (optional, defaults to latest stable)",
+					},
+				},
+				"required": []string{"packageName"},
+			},
+		},
+		{
+			Name:        "open-context_get_cocoapod_info",
+			Description: "Fetch and cache information about CocoaPods pods (iOS/macOS libraries), including Podfile usage",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"podName": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the pod (e.g., 'Alamofire', 'SDWebImage', 'Firebase/Auth')",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Specific version of the pod (optional, defaults to latest stable)",
+					},
+				},
+				"required": []string{"podName"},
+			},
+		},
+		{
+			Name:        "open-context_get_conan_info",
+			Description: "Fetch and cache information about C/C++ packages from Conan Center, including conanfile.txt and CMake integration snippets",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"packageName": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the Conan Center package (e.g., 'zlib', 'fmt', 'boost')",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Specific version of the package (optional, defaults to latest stable)",
+					},
+				},
+				"required": []string{"packageName"},
+			},
+		},
+		{
+			Name:        "open-context_generate_dependency_snippet",
+			Description: "Generate the exact manifest edit (package.json, requirements.txt/pyproject.toml, Cargo.toml, go.mod, Gemfile, mix.exs, Podfile) that adds a dependency, as a diff-style snippet",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"ecosystem": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"npm", "python", "rust", "go", "ruby", "hex", "cocoapods", "conan"},
+						"description": "Package ecosystem",
+					},
+					"package": map[string]interface{}{
+						"type":        "string",
+						"description": "Package name (e.g., 'react', 'requests', 'serde', 'github.com/gin-gonic/gin')",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Version to pin (optional, defaults to latest from the registry)",
+					},
+					"options": map[string]interface{}{
+						"type":        "object",
+						"description": "Ecosystem-specific options",
+						"properties": map[string]interface{}{
+							"dev": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Add as a development/test dependency",
+							},
+							"features": map[string]interface{}{
+								"type":        "array",
+								"items":       map[string]interface{}{"type": "string"},
+								"description": "Cargo features to enable (rust)",
+							},
+							"noDefaultFeatures": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Disable default Cargo features (rust)",
+							},
+							"extras": map[string]interface{}{
+								"type":        "array",
+								"items":       map[string]interface{}{"type": "string"},
+								"description": "Optional dependency groups to install (python)",
+							},
+						},
+					},
+				},
+				"required": []string{"ecosystem", "package"},
+			},
+		},
+		{
+			Name:        "open-context_get_packages_info",
+			Description: "Fetch information about several packages across ecosystems in one call, returning a combined summary and per-package structured data",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"entries": map[string]interface{}{
+						"type":        "array",
+						"maxItems":    maxBatchPackages,
+						"description": "Packages to look up",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"ecosystem": map[string]interface{}{
+									"type":        "string",
+									"enum":        []string{"npm", "python", "rust", "go", "ruby", "hex", "cocoapods", "conan"},
+									"description": "Package ecosystem",
+								},
+								"package": map[string]interface{}{
+									"type":        "string",
+									"description": "Package name",
+								},
+								"version": map[string]interface{}{
+									"type":        "string",
+									"description": "Specific version (optional, defaults to latest)",
+								},
+							},
+							"required": []string{"ecosystem", "package"},
+						},
+					},
+				},
+				"required": []string{"entries"},
+			},
+		},
 		{
 			Name:        "open-context_get_node_info",
 			Description: "Fetch and cache information about Node.js versions from nodejs.org",
@@ -318,572 +786,2257 @@ func (s *MCPServer) handleToolsList(req Request) Response {
 			},
 		},
 		{
-			Name:        "open-context_get_typescript_info",
-			Description: "Fetch and cache information about TypeScript versions from GitHub releases",
+			Name:        "open-context_get_node_schedule",
+			Description: "Get the Node.js release schedule with LTS, maintenance, and end-of-life dates to help choose a runtime version",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "TypeScript version to fetch (e.g., '5.0.0', '4.9.5')",
+						"description": "Node.js release line or version to describe (e.g., '20', 'v22.1.0'). Leave empty for all supported release lines",
 					},
 				},
-				"required": []string{"version"},
 			},
 		},
 		{
-			Name:        "open-context_get_nextjs_info",
-			Description: "Fetch and cache information about Next.js versions from GitHub releases",
+			Name:        "open-context_get_java_info",
+			Description: "Get a Java (JDK) release line's LTS status, support dates, latest update, delivered JEPs, and install commands (SDKMAN!, apt, Homebrew, Docker); without a version, list the supported release lines",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "Next.js version to fetch (e.g., '13.0.0', '14.0.0')",
+						"description": "JDK release line or version (e.g., '21', '17.0.9', '1.8'). Leave empty for all supported release lines",
 					},
 				},
-				"required": []string{"version"},
 			},
 		},
 		{
-			Name:        "open-context_get_react_info",
-			Description: "Fetch and cache information about React versions from GitHub releases",
+			Name:        "open-context_get_deno_info",
+			Description: "Fetch and cache a Deno runtime version's release notes from GitHub releases, with install commands (install script, deno upgrade, Cargo, Docker)",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "React version to fetch (e.g., '18.0.0', '19.0.0')",
+						"description": "Deno version to fetch (e.g., '2.1.4', 'v1.46.3'). Leave empty for the latest release",
 					},
 				},
-				"required": []string{"version"},
 			},
 		},
 		{
-			Name:        "open-context_get_ansible_info",
-			Description: "Fetch and cache information about Ansible versions from GitHub releases",
+			Name:        "open-context_get_bun_info",
+			Description: "Fetch and cache a Bun runtime version's release notes from GitHub releases, with the highlights of its blog post and install commands (install script, npm, Docker)",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "Ansible version to fetch (e.g., '2.15.0', '2.16.0')",
+						"description": "Bun version to fetch (e.g., '1.1.38', 'bun-v1.1.0'). Leave empty for the latest release",
 					},
 				},
-				"required": []string{"version"},
 			},
 		},
 		{
-			Name:        "open-context_get_terraform_info",
-			Description: "Fetch and cache information about Terraform versions from GitHub releases",
+			Name:        "open-context_get_typescript_info",
+			Description: "Fetch and cache information about TypeScript versions from GitHub releases",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "Terraform version to fetch (e.g., '1.5.0', '1.6.0')",
+						"description": "TypeScript version to fetch (e.g., '5.0.0', '4.9.5')",
 					},
 				},
 				"required": []string{"version"},
 			},
 		},
 		{
-			Name:        "open-context_get_jenkins_info",
-			Description: "Fetch and cache information about Jenkins versions from GitHub releases",
+			Name:        "open-context_get_typescript_feature",
+			Description: "Find the TypeScript version that introduced a language feature and link its handbook section",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Feature name (e.g., 'satisfies operator', 'const type parameters', 'decorators')",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "open-context_get_nextjs_info",
+			Description: "Fetch and cache information about Next.js versions from GitHub releases",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "Jenkins version to fetch (e.g., '2.440.3', '2.450.0')",
+						"description": "Next.js version to fetch (e.g., '13.0.0', '14.0.0')",
 					},
 				},
 				"required": []string{"version"},
 			},
 		},
 		{
-			Name:        "open-context_get_kubernetes_info",
-			Description: "Fetch and cache information about Kubernetes versions from GitHub releases",
+			Name:        "open-context_get_nextjs_docs",
+			Description: "Fetch a nextjs.org docs page for a specific router (App Router or Pages Router) and Next.js version",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Docs page path relative to the router (e.g., 'api-reference/functions/use-router', 'building-your-application/routing'). Full nextjs.org URLs are accepted",
+					},
+					"router": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"app", "pages"},
+						"description": "Router paradigm the page should describe (optional, defaults to 'app')",
+					},
 					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "Kubernetes version to fetch (e.g., '1.28.0', '1.29.0')",
+						"description": "Next.js version whose docs to fetch (optional, e.g., '14.2.0'; defaults to the latest canary docs)",
 					},
 				},
-				"required": []string{"version"},
+				"required": []string{"path"},
 			},
 		},
 		{
-			Name:        "open-context_get_helm_info",
-			Description: "Fetch and cache information about Helm versions from GitHub releases",
+			Name:        "open-context_get_react_info",
+			Description: "Fetch and cache information about React versions from GitHub releases",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "Helm version to fetch (e.g., '3.12.0', '3.13.0')",
+						"description": "React version to fetch (e.g., '18.0.0', '19.0.0')",
 					},
 				},
 				"required": []string{"version"},
 			},
 		},
 		{
-			Name:        "open-context_get_docker_image",
-			Description: "Fetch and cache information about Docker images from Docker Hub, including available tags and image details",
+			Name:        "open-context_get_react_api",
+			Description: "Fetch React API reference pages from react.dev (hooks, components, react-dom and Server Components APIs) as markdown",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"image": map[string]interface{}{
+					"symbol": map[string]interface{}{
 						"type":        "string",
-						"description": "Docker image name (e.g., 'golang', 'node', 'nginx', 'myuser/myapp')",
+						"description": "React API name (e.g., 'useEffect', 'Suspense', 'createRoot', 'use client', 'react-dom/client/hydrateRoot')",
 					},
-					"tag": map[string]interface{}{
+				},
+				"required": []string{"symbol"},
+			},
+		},
+		{
+			Name:        "open-context_get_ansible_info",
+			Description: "Fetch and cache information about Ansible versions from GitHub releases",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "Docker image tag (e.g., '1.23.4-bookworm', 'latest', '20-alpine')",
+						"description": "Ansible version to fetch (e.g., '2.15.0', '2.16.0')",
 					},
 				},
-				"required": []string{"image", "tag"},
+				"required": []string{"version"},
 			},
 		},
 		{
-			Name:        "open-context_get_github_action",
-			Description: "Fetch and cache information about GitHub Actions from GitHub API, including action metadata, inputs, and outputs",
+			Name:        "open-context_get_ansible_collection",
+			Description: "Fetch and cache information about Ansible collections from Ansible Galaxy, including versions, included modules and plugins, and ansible-galaxy install usage",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"repository": map[string]interface{}{
+					"collection": map[string]interface{}{
 						"type":        "string",
-						"description": "GitHub repository in format 'owner/repo' (e.g., 'actions/checkout', 'docker/setup-buildx-action')",
+						"description": "Collection name in 'namespace.name' format (e.g., 'community.general', 'amazon.aws')",
 					},
 					"version": map[string]interface{}{
 						"type":        "string",
-						"description": "Specific version/tag of the action (optional, defaults to latest release)",
+						"description": "Specific version of the collection (optional, defaults to latest stable)",
 					},
 				},
-				"required": []string{"repository"},
+				"required": []string{"collection"},
 			},
 		},
-	}
-
-	return Response{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result: map[string]interface{}{
-			"tools": tools,
+		{
+			Name:        "open-context_get_terraform_info",
+			Description: "Fetch and cache information about Terraform versions from GitHub releases",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Terraform version to fetch (e.g., '1.5.0', '1.6.0')",
+					},
+				},
+				"required": []string{"version"},
+			},
+		},
+		{
+			Name:        "open-context_find_terraform_examples",
+			Description: "Find usage examples of a Terraform resource type in the Example Usage of its Terraform Registry documentation and in the examples directory of the provider's GitHub repository, returning the best-matching HCL snippets with links to their sources",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"resource": map[string]interface{}{
+						"type":        "string",
+						"description": "Resource type (e.g., 'aws_s3_bucket', 'google_compute_instance', 'azurerm_storage_account')",
+					},
+					"provider": map[string]interface{}{
+						"type":        "string",
+						"description": "Registry provider as 'namespace/name' (e.g., 'integrations/github'). Defaults to the resource type's prefix under hashicorp",
+					},
+				},
+				"required": []string{"resource"},
+			},
+		},
+		{
+			Name:        "open-context_get_terraform_schema",
+			Description: "Render the arguments, read-only attributes, and nested blocks of a Terraform resource type or data source from its provider's schema, with each argument's type and whether it is required, and an HCL skeleton of its required configuration",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"resource": map[string]interface{}{
+						"type":        "string",
+						"description": "Resource or data source type (e.g., 'aws_s3_bucket', 'google_compute_instance')",
+					},
+					"provider": map[string]interface{}{
+						"type":        "string",
+						"description": "Registry provider as 'namespace/name' (e.g., 'integrations/github'). Defaults to the type's prefix under hashicorp",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Provider version (e.g., '5.80.0'). Defaults to the latest release",
+					},
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "'resource' (default) or 'data' for a data source",
+						"enum":        []string{"resource", "data"},
+					},
+				},
+				"required": []string{"resource"},
+			},
+		},
+		{
+			Name:        "open-context_get_pulumi_info",
+			Description: "Fetch and cache a release of the Pulumi CLI or of a Pulumi provider package (e.g., @pulumi/aws): its GitHub release notes, the matching npm and PyPI versions, and install commands for Node.js, Python, Go, and .NET",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"package": map[string]interface{}{
+						"type":        "string",
+						"description": "Provider package (e.g., 'aws', '@pulumi/aws', 'pulumi-gcp'). Leave empty or use 'cli' for the Pulumi CLI",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Version to fetch (e.g., '3.140.0', 'v6.60.0'). Leave empty for the latest release",
+					},
+				},
+			},
+		},
+		{
+			Name:        "open-context_get_cdk_info",
+			Description: "Fetch and cache an AWS CDK version's release notes from GitHub releases, with the aws-cdk-lib construct library versions on npm and PyPI, the constructs, Node.js, and Python versions it requires, and npm/pip install commands",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "AWS CDK version to fetch (e.g., '2.170.0', 'v2.150.0'). Leave empty for the latest release",
+					},
+				},
+			},
+		},
+		{
+			Name:        "open-context_get_jenkins_info",
+			Description: "Fetch and cache information about Jenkins versions from GitHub releases",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Jenkins version to fetch (e.g., '2.440.3', '2.450.0')",
+					},
+				},
+				"required": []string{"version"},
+			},
+		},
+		{
+			Name:        "open-context_get_jenkins_plugin",
+			Description: "Fetch and cache information about Jenkins plugins from plugins.jenkins.io, including versions, required Jenkins core version, dependencies, security warnings, and Pipeline step usage",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"plugin": map[string]interface{}{
+						"type":        "string",
+						"description": "Plugin ID (e.g., 'git', 'workflow-aggregator', 'kubernetes')",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Specific plugin version (optional, defaults to latest)",
+					},
+				},
+				"required": []string{"plugin"},
+			},
+		},
+		{
+			Name:        "open-context_get_kubernetes_info",
+			Description: "Fetch and cache information about Kubernetes versions from GitHub releases",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes version to fetch (e.g., '1.28.0', '1.29.0')",
+					},
+				},
+				"required": []string{"version"},
+			},
+		},
+		{
+			Name:        "open-context_get_helm_info",
+			Description: "Fetch and cache information about Helm versions from GitHub releases",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Helm version to fetch (e.g., '3.12.0', '3.13.0')",
+					},
+				},
+				"required": []string{"version"},
+			},
+		},
+		{
+			Name:        "open-context_get_nginx_info",
+			Description: "Fetch and cache an nginx version's changes from nginx.org, with its channel (mainline or stable), the current version of each channel, and install commands (nginx.org packages, Docker, source)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "nginx version to fetch (e.g., '1.27.3', '1.26.2'), or 'mainline' or 'stable' for the current version of a channel. Leave empty for the current mainline version",
+					},
+				},
+			},
+		},
+		{
+			Name:        "open-context_compare_versions",
+			Description: "Collect the GitHub release notes of every release between two versions of a product into one upgrade document (Terraform, Helm, Kubernetes, React, Next.js, TypeScript, Ansible, Jenkins, Node.js, or any GitHub repository)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"product": map[string]interface{}{
+						"type":        "string",
+						"description": "Product (terraform, helm, kubernetes, react, nextjs, typescript, ansible, jenkins, node) or a GitHub repository as 'owner/repo'",
+					},
+					"from": map[string]interface{}{
+						"type":        "string",
+						"description": "Version in use; its own release is not included (e.g., '1.5.0')",
+					},
+					"to": map[string]interface{}{
+						"type":        "string",
+						"description": "Target version, included (optional, defaults to the latest release)",
+					},
+				},
+				"required": []string{"product", "from"},
+			},
+		},
+		{
+			Name:        "open-context_get_helm_chart",
+			Description: "Fetch and cache information about Helm charts from Artifact Hub, including chart and app versions, maintainers, a summary of default values, and helm repo add/install commands",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"chart": map[string]interface{}{
+						"type":        "string",
+						"description": "Chart in 'repo/chart' format (e.g., 'bitnami/nginx', 'prometheus-community/kube-prometheus-stack'); a bare chart name resolves to the most popular repository",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Specific chart version (optional, defaults to latest)",
+					},
+				},
+				"required": []string{"chart"},
+			},
+		},
+		{
+			Name:        "open-context_get_git_info",
+			Description: "Fetch and cache a Git version's release notes from the official git/git repository, with its release date and install commands for each platform (source tarball, Homebrew, apt, dnf, winget)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Git version to fetch (e.g., '2.47.0', 'v2.46.1', '2.45'). Leave empty for the latest release",
+					},
+				},
+			},
+		},
+		{
+			Name:        "open-context_get_docker_engine_info",
+			Description: "Fetch and cache a Docker Engine and Docker CLI version's release notes from the moby/moby and docker/cli GitHub releases, with the Engine API version it serves and install commands (convenience script, apt, dnf, static binaries, Docker in Docker). For container images use open-context_get_docker_image",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Docker Engine version to fetch (e.g., '27.3.1', 'v28.0.0'). Leave empty for the latest release",
+					},
+				},
+			},
+		},
+		{
+			Name:        "open-context_get_docker_image",
+			Description: "Fetch and cache information about Docker images from Docker Hub or OCI registries such as ghcr.io, quay.io, and registry.k8s.io, including available tags, image configuration (entrypoint, cmd, env, exposed ports), and layer history",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"image": map[string]interface{}{
+						"type":        "string",
+						"description": "Docker image name, prefixed with the registry host for images outside Docker Hub (e.g., 'golang', 'nginx', 'myuser/myapp', 'ghcr.io/owner/image', 'registry.k8s.io/pause')",
+					},
+					"tag": map[string]interface{}{
+						"type":        "string",
+						"description": "Docker image tag (e.g., '1.23.4-bookworm', 'latest', '20-alpine')",
+					},
+				},
+				"required": []string{"image", "tag"},
+			},
+		},
+		{
+			Name:        "open-context_get_github_action",
+			Description: "Fetch and cache information about GitHub Actions from GitHub API, including action metadata, inputs, and outputs",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"repository": map[string]interface{}{
+						"type":        "string",
+						"description": "GitHub repository in format 'owner/repo' (e.g., 'actions/checkout', 'docker/setup-buildx-action')",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Specific version/tag of the action (optional, defaults to latest release)",
+					},
+				},
+				"required": []string{"repository"},
+			},
+		},
+		{
+			Name:        "open-context_get_github_readme",
+			Description: "Fetch and cache the README of any GitHub repository at a branch, tag, or commit via the GitHub API, converted to markdown, for projects not covered by a package registry",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"repository": map[string]interface{}{
+						"type":        "string",
+						"description": "GitHub repository in format 'owner/repo' (e.g., 'junegunn/fzf') or its URL",
+					},
+					"ref": map[string]interface{}{
+						"type":        "string",
+						"description": "Branch, tag, or commit SHA (optional, defaults to the default branch)",
+					},
+				},
+				"required": []string{"repository"},
+			},
+		},
+		{
+			Name:        "open-context_get_github_release",
+			Description: "Fetch and cache the notes, date, and assets of a release of any GitHub repository, by tag or the latest one, for projects without a dedicated release tool",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"repository": map[string]interface{}{
+						"type":        "string",
+						"description": "GitHub repository in format 'owner/repo' (e.g., 'cli/cli'), its URL, or 'owner/repo@tag'",
+					},
+					"tag": map[string]interface{}{
+						"type":        "string",
+						"description": "Release tag, with or without its 'v' prefix (optional, defaults to the latest release)",
+					},
+				},
+				"required": []string{"repository"},
+			},
+		},
+		{
+			Name:        "open-context_get_gitlab_project",
+			Description: "Fetch and cache a GitLab project's metadata, latest release notes, and recent tags from gitlab.com or the configured self-hosted instance",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"project": map[string]interface{}{
+						"type":        "string",
+						"description": "Project path (e.g., 'gitlab-org/gitlab-runner') or its URL, which may name a self-hosted instance",
+					},
+				},
+				"required": []string{"project"},
+			},
 		},
+		{
+			Name:        "open-context_get_so_answers",
+			Description: "Search Stack Overflow (or another Stack Exchange site) for an error message or question and return the accepted answers of the most relevant questions, with their score and CC BY-SA attribution; cached for 6 hours",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Error message or question to search for (e.g., 'cannot use nil as type string in return argument')",
+					},
+					"tag": map[string]interface{}{
+						"type":        "string",
+						"description": "Tag the questions must have (optional, e.g., 'go', 'python', 'reactjs')",
+					},
+					"site": map[string]interface{}{
+						"type":        "string",
+						"description": "Stack Exchange site (optional, default 'stackoverflow'; e.g., 'serverfault', 'superuser', 'unix')",
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			Name:        "open-context_explain_error",
+			Description: "Explain a compiler or tool error from its official catalog: Go type checker error codes, go vet analyzers, TypeScript TSxxxx diagnostics, the rustc Exxxx error index, and common kubectl errors",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"tool": map[string]interface{}{
+						"type":        "string",
+						"description": "Tool that printed the error: 'go', 'go vet', 'typescript', 'rust', or 'kubectl' (optional; told from the message when left out)",
+					},
+					"message": map[string]interface{}{
+						"type":        "string",
+						"description": "Error message as printed (e.g., 'error[E0382]: borrow of moved value', 'x declared and not used') or a diagnostic code (e.g., 'TS2322', 'UnusedVar', 'copylocks')",
+					},
+				},
+				"required": []string{"message"},
+			},
+		},
+		{
+			Name:        "open-context_get_rust_error",
+			Description: "Fetch and cache the official explanation of a rustc error code (e.g. E0382) from the Rust error index, with its erroneous and fixed examples",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"code": map[string]interface{}{
+						"type":        "string",
+						"description": "Error code (e.g., 'E0382', 'E0499', or '382')",
+					},
+				},
+				"required": []string{"code"},
+			},
+		},
+		{
+			Name:        "open-context_get_ts_diagnostic",
+			Description: "Resolve a TypeScript diagnostic code (e.g. TS2322) to its message template and category from the TypeScript repository, with a plain-English explanation from the community where one exists and links to further docs",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"code": map[string]interface{}{
+						"type":        "string",
+						"description": "Diagnostic code (e.g., 'TS2322', 'TS7006', or '2322')",
+					},
+				},
+				"required": []string{"code"},
+			},
+		},
+		{
+			Name:        "open-context_get_http_reference",
+			Description: "Fetch and cache MDN's reference for an HTTP status code, request or response header, or method, with links to the RFC sections that specify it",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "What to look up: 'status', 'header', or 'method' (optional; told from the name when left out)",
+						"enum":        []string{"status", "header", "method"},
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Status code, header, or method (e.g., '429', 'Content-Security-Policy', 'PATCH')",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "open-context_get_rfc",
+			Description: "Fetch and cache an IETF RFC or Internet-Draft from rfc-editor.org or the IETF archive as markdown with section anchors, whole or one section at a time",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"document": map[string]interface{}{
+						"type":        "string",
+						"description": "RFC number (e.g., '9110', 'RFC 8446') or Internet-Draft name (e.g., 'draft-ietf-httpbis-resumable-upload', optionally with a revision such as '-05')",
+					},
+					"section": map[string]interface{}{
+						"type":        "string",
+						"description": "Section to return with its subsections: a number ('8.3', 'A.1'), an anchor ('section-8.3', 'appendix-A'), or a title ('Security Considerations'). Leave empty for the whole document, or its contents when it is too long",
+					},
+				},
+				"required": []string{"document"},
+			},
+		},
+		{
+			Name:        "open-context_get_web_spec",
+			Description: "Fetch and cache a section of a web standard as markdown: the WHATWG HTML (multipage), DOM, Fetch, URL, and other living standards, or a CSS Working Group draft. Complements MDN for standards-level questions",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"type":        "string",
+						"description": "Standard: 'html', 'dom', 'fetch', 'url', 'infra', 'streams', 'encoding', 'mimesniff', 'xhr', 'webidl', 'storage', 'console', or a CSS draft's short name (e.g., 'css-grid-2', 'css-flexbox-1', 'selectors-4')",
+					},
+					"section": map[string]interface{}{
+						"type":        "string",
+						"description": "Section anchor ('the-form-element', 'concept-url-parser'), number ('4.10.3'), or title ('URL parsing'). An anchor of a definition returns the section defining it. Leave empty for the table of contents",
+					},
+				},
+				"required": []string{"spec"},
+			},
+		},
+		{
+			Name:        "open-context_get_posix_util",
+			Description: "Fetch and cache the reference of a shell utility (e.g., awk, sed, find, xargs) as markdown: its POSIX specification page, or its GNU coreutils manual. Use it to check a flag exists before writing it into a script",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Utility name (e.g., 'awk', 'sed', 'find', 'sort', 'sha256sum')",
+					},
+					"source": map[string]interface{}{
+						"type":        "string",
+						"description": "'posix' for the specification, 'gnu' for the GNU coreutils manual. Defaults to POSIX, falling back to coreutils for utilities POSIX does not specify",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "open-context_get_man_page",
+			Description: "Fetch and cache a Linux manual page (e.g., ls, open(2), epoll(7), systemd.unit(5)) as markdown from the Debian manpages service. Use it for command options, system calls, C library functions, and file formats",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Page name, optionally with its section (e.g., 'tar', 'open(2)', 'printf(3)')",
+					},
+					"section": map[string]interface{}{
+						"type":        "string",
+						"description": "Manual section (e.g., '1' commands, '2' system calls, '3' library functions, '5' file formats, '7' overviews, '8' administration). Defaults to the page man shows first",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "open-context_get_tldr",
+			Description: "Fetch and cache the tldr page of a command (e.g., tar, git commit, ffmpeg) as markdown: a one-line summary and its most common invocations as copyable examples. Use it for quick command usage; use get_man_page for every option",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"command": map[string]interface{}{
+						"type":        "string",
+						"description": "Command name (e.g., 'tar', 'git commit', 'docker-compose')",
+					},
+					"platform": map[string]interface{}{
+						"type":        "string",
+						"description": "'linux', 'osx', 'windows', 'freebsd', 'android', ... Pages common to every platform are the fallback. Defaults to the first platform with a page",
+					},
+					"language": map[string]interface{}{
+						"type":        "string",
+						"description": "Translation language (e.g., 'de', 'pt_BR'). Defaults to English, which is also the fallback",
+					},
+				},
+				"required": []string{"command"},
+			},
+		},
+		{
+			Name:        "open-context_get_buf_module",
+			Description: "Fetch and cache a Protobuf module from the Buf Schema Registry (e.g., bufbuild/protovalidate, googleapis/googleapis) at a label or commit: its README, its services with their RPC signatures, and its messages and enums by package, with how to depend on it in buf.yaml",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"module": map[string]interface{}{
+						"type":        "string",
+						"description": "Module as 'owner/module' or 'buf.build/owner/module' (e.g., 'bufbuild/protovalidate', 'googleapis/googleapis')",
+					},
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Label (e.g., 'main', 'v0.8.0') or commit ID. Defaults to the module's default label",
+					},
+				},
+				"required": []string{"module"},
+			},
+		},
+		{
+			Name:        "open-context_get_license",
+			Description: "Fetch and cache the full text of a license by its SPDX identifier (e.g., MIT, Apache-2.0, GPL-3.0-only), with its OSI and FSF status and a summary of its permissions, conditions, and limitations. Use it to expand the license field of a package returned by the other tools",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"license": map[string]interface{}{
+						"type":        "string",
+						"description": "SPDX license identifier, in any case, or license name (e.g., 'MIT', 'apache-2.0', 'GPL-3.0-or-later', 'Mozilla Public License 2.0')",
+					},
+				},
+				"required": []string{"license"},
+			},
+		},
+		{
+			Name:        "open-context_compare_sql_feature",
+			Description: "Compare how a SQL feature (e.g., UPSERT, window functions, JSON operators, RETURNING, pagination) is written in PostgreSQL, MySQL, SQLite, and SQL Server, with the release each dialect gained it, its pitfalls, and links to each manual",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"feature": map[string]interface{}{
+						"type":        "string",
+						"description": "Feature: 'upsert', 'merge', 'window functions', 'json', 'cte', 'returning', 'pagination', 'string aggregation', 'auto increment', 'boolean', 'regex', 'full-text search', 'lateral', or 'generated columns'. Common spellings such as 'ON CONFLICT', 'GROUP_CONCAT', or 'CROSS APPLY' also work",
+					},
+					"dialects": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Dialects to compare: 'postgresql', 'mysql', 'sqlite', 'sqlserver'. Defaults to all four",
+					},
+				},
+				"required": []string{"feature"},
+			},
+		},
+		{
+			Name:        "open-context_get_devdocs",
+			Description: "Download a DevDocs.io docset for any technology (e.g., Python, Rust, React, PostgreSQL) and index its pages as topics, making them searchable with open-context_search_docs and readable with open-context_get_docs",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"docset": map[string]interface{}{
+						"type":        "string",
+						"description": "DevDocs docset slug, optionally with a version (e.g., 'python~3.12', 'rust', 'react', 'postgresql~16'); without a version the newest one is used",
+					},
+				},
+				"required": []string{"docset"},
+			},
+		},
+		{
+			Name:        "open-context_get_llms_txt",
+			Description: "Fetch a site's llms.txt (or llms-full.txt) LLM-oriented documentation index and index the pages it references as topics, making them searchable with open-context_search_docs and readable with open-context_get_docs",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"url": map[string]interface{}{
+						"type":        "string",
+						"description": "Site or docs URL (e.g., 'svelte.dev', 'https://docs.example.com/guide'), or the URL of an llms.txt or llms-full.txt file",
+					},
+				},
+				"required": []string{"url"},
+			},
+		},
+		{
+			Name:        "open-context_fetch_site",
+			Description: "Crawl a documentation site through its sitemap.xml, convert its pages to markdown, and index them as a new documentation set, searchable with open-context_search_docs and readable with open-context_get_docs; for sites without a dedicated tool, DevDocs docset, or llms.txt",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"url": map[string]interface{}{
+						"type":        "string",
+						"description": "Base URL of the documentation; only pages under it are crawled (e.g., 'https://docs.example.com', 'example.com/docs')",
+					},
+					"maxPages": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of pages to crawl (optional, default 100, at most 1000)",
+					},
+				},
+				"required": []string{"url"},
+			},
+		},
+		{
+			Name:        "open-context_import_docs",
+			Description: "Import a local directory of markdown files (e.g., an internal team handbook) as a documentation set named 'local-<name>', with titles, descriptions, and keywords generated from each page, making it searchable with open-context_search_docs and readable with open-context_get_docs; importing again replaces the previous import",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory on the server's machine whose .md, .markdown, and .mdx files are imported, including subdirectories; it must be within the server's import_roots",
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the documentation set, in lowercase letters, digits, '-', and '_' (optional, defaults to the directory's name)",
+					},
+				},
+				"required": []string{"path"},
+			},
+		},
+		{
+			Name:        "open-context_get_local_symbol",
+			Description: "Get hover-style documentation for a symbol in the configured local Go workspace (go_workspace), by name or by file position, using gopls",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Symbol name (e.g., 'Server', 'Server.Start', 'config.Load'); used when no position is given",
+					},
+					"file": map[string]interface{}{
+						"type":        "string",
+						"description": "File path, absolute or relative to the workspace (e.g., 'internal/server/server.go')",
+					},
+					"line": map[string]interface{}{
+						"type":        "integer",
+						"description": "1-based line of the symbol in file",
+					},
+					"column": map[string]interface{}{
+						"type":        "integer",
+						"description": "1-based column of the symbol in file",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *MCPServer) handleToolCall(req Request, sess *session) Response {
+	var params struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &Error{
+				Code:    -32602,
+				Message: fmt.Sprintf("Invalid params: %v", err),
+			},
+		}
+	}
+
+	result, err := s.callEnabledTool(params.Name, applyPinnedDocs(sess, params.Name, params.Arguments))
+	if errors.Is(err, errInternal) {
+		return internalErrorResponse(req.ID, err)
+	}
+	if errors.Is(err, errUnknownTool) {
+		return Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &Error{
+				Code:    -32601,
+				Message: fmt.Sprintf("Unknown tool: %s", params.Name),
+			},
+		}
+	}
+
+	if err != nil {
+		return Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &Error{
+				Code:    -32000,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	return Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": result,
+				},
+			},
+		},
+	}
+}
+
+// errUnknownTool is returned by callTool for names that are not in s.tools()
+var errUnknownTool = errors.New("unknown tool")
+
+// callEnabledTool calls a tool for a client, refusing tools that
+// enabled_tools leaves out as unknown. Tools calling other tools, such as
+// smart_docs, use callTool and are not limited. A panic in the tool is
+// returned as errInternal. With fixtures, calls are replayed or recorded
+// here, so a tool calling others is recorded as one call.
+func (s *MCPServer) callEnabledTool(name string, args map[string]interface{}) (string, error) {
+	return s.callEnabled(name, name, args, func() (string, error) {
+		return s.callTool(name, args)
+	})
+}
+
+// callEnabled runs call on behalf of tool, as callEnabledTool runs the tool
+// itself, for callers that need the data behind a tool rather than its
+// text. Fixtures record and replay the calls under the name fixture.
+func (s *MCPServer) callEnabled(tool, fixture string, args map[string]interface{}, call func() (string, error)) (result string, err error) {
+	if !s.toolEnabled(tool) {
+		return "", fmt.Errorf("%w: %s", errUnknownTool, tool)
+	}
+	s.warnDeprecated(tool)
+	if s.fixtures != nil && !s.fixtures.record {
+		return s.fixtures.replay(fixture, args)
+	}
+	defer recoverPanic(&err, tool)
+
+	result, err = call()
+	if s.fixtures != nil {
+		s.fixtures.save(fixture, args, result, err)
+	}
+	return result, err
+}
+
+// toolEnabled reports whether enabled_tools, when set, lists a tool
+func (s *MCPServer) toolEnabled(name string) bool {
+	return s.enabledTools == nil || s.enabledTools[name]
+}
+
+// callTool dispatches a tool call to its handler
+func (s *MCPServer) callTool(name string, args map[string]interface{}) (string, error) {
+	switch name {
+	case "open-context_search_docs":
+		return s.searchDocs(args)
+	case "open-context_get_docs":
+		return s.getDocs(args)
+	case "open-context_list_docs":
+		return s.listDocs()
+	case "open-context_smart_docs":
+		return s.smartDocs(args)
+	case "open-context_get_code_examples":
+		return s.getCodeExamples(args)
+	case "open-context_get_go_info":
+		return s.getGoInfo(args)
+	case "open-context_get_go_vulns":
+		return s.getGoVulns(args)
+	case "open-context_get_go_dependencies":
+		return s.getGoDependencies(args)
+	case "open-context_get_npm_info":
+		return s.getNPMInfo(args)
+	case "open-context_get_python_info":
+		return s.getPythonInfo(args)
+	case "open-context_get_python_version":
+		return s.getPythonVersion(args)
+	case "open-context_get_rust_info":
+		return s.getRustInfo(args)
+	case "open-context_get_gem_info":
+		return s.getGemInfo(args)
+	case "open-context_get_hex_info":
+		return s.getHexInfo(args)
+	case "open-context_get_cocoapod_info":
+		return s.getCocoaPodInfo(args)
+	case "open-context_get_conan_info":
+		return s.getConanInfo(args)
+	case "open-context_generate_dependency_snippet":
+		return s.generateDependencySnippet(args)
+	case "open-context_get_packages_info":
+		return s.getPackagesInfo(args)
+	case "open-context_get_node_info":
+		return s.getNodeInfo(args)
+	case "open-context_get_node_schedule":
+		return s.getNodeSchedule(args)
+	case "open-context_get_java_info":
+		return s.getJavaInfo(args)
+	case "open-context_get_deno_info":
+		return s.getDenoInfo(args)
+	case "open-context_get_bun_info":
+		return s.getBunInfo(args)
+	case "open-context_get_typescript_info":
+		return s.getTypeScriptInfo(args)
+	case "open-context_get_typescript_feature":
+		return s.getTypeScriptFeature(args)
+	case "open-context_get_nextjs_info":
+		return s.getNextJSInfo(args)
+	case "open-context_get_nextjs_docs":
+		return s.getNextJSDocs(args)
+	case "open-context_get_react_info":
+		return s.getReactInfo(args)
+	case "open-context_get_react_api":
+		return s.getReactAPI(args)
+	case "open-context_get_ansible_info":
+		return s.getAnsibleInfo(args)
+	case "open-context_get_ansible_collection":
+		return s.getAnsibleCollection(args)
+	case "open-context_get_terraform_info":
+		return s.getTerraformInfo(args)
+	case "open-context_find_terraform_examples":
+		return s.findTerraformExamples(args)
+	case "open-context_get_terraform_schema":
+		return s.getTerraformSchema(args)
+	case "open-context_get_pulumi_info":
+		return s.getPulumiInfo(args)
+	case "open-context_get_cdk_info":
+		return s.getCDKInfo(args)
+	case "open-context_get_jenkins_info":
+		return s.getJenkinsInfo(args)
+	case "open-context_get_jenkins_plugin":
+		return s.getJenkinsPlugin(args)
+	case "open-context_get_kubernetes_info":
+		return s.getKubernetesInfo(args)
+	case "open-context_get_helm_info":
+		return s.getHelmInfo(args)
+	case "open-context_get_nginx_info":
+		return s.getNginxInfo(args)
+	case "open-context_get_helm_chart":
+		return s.getHelmChart(args)
+	case "open-context_compare_versions":
+		return s.compareVersions(args)
+	case "open-context_get_git_info":
+		return s.getGitInfo(args)
+	case "open-context_get_docker_engine_info":
+		return s.getDockerEngineInfo(args)
+	case "open-context_get_docker_image":
+		return s.getDockerImage(args)
+	case "open-context_get_github_action":
+		return s.getGitHubAction(args)
+	case "open-context_get_github_readme":
+		return s.getGitHubReadme(args)
+	case "open-context_get_github_release":
+		return s.getGitHubRelease(args)
+	case "open-context_get_gitlab_project":
+		return s.getGitLabProject(args)
+	case "open-context_get_so_answers":
+		return s.getSOAnswers(args)
+	case "open-context_explain_error":
+		return s.explainError(args)
+	case "open-context_get_rust_error":
+		return s.getRustError(args)
+	case "open-context_get_ts_diagnostic":
+		return s.getTSDiagnostic(args)
+	case "open-context_get_http_reference":
+		return s.getHTTPReference(args)
+	case "open-context_get_rfc":
+		return s.getRFC(args)
+	case "open-context_get_web_spec":
+		return s.getWebSpec(args)
+	case "open-context_get_posix_util":
+		return s.getPosixUtil(args)
+	case "open-context_get_man_page":
+		return s.getManPage(args)
+	case "open-context_get_tldr":
+		return s.getTldr(args)
+	case "open-context_get_buf_module":
+		return s.getBufModule(args)
+	case "open-context_get_license":
+		return s.getLicense(args)
+	case "open-context_compare_sql_feature":
+		return s.compareSQLFeature(args)
+	case "open-context_get_devdocs":
+		return s.getDevDocs(args)
+	case "open-context_get_llms_txt":
+		return s.getLLMsTxt(args)
+	case "open-context_fetch_site":
+		return s.fetchSite(args)
+	case "open-context_import_docs":
+		return s.importDocs(args)
+	case "open-context_get_local_symbol":
+		return s.getLocalSymbol(args)
+	}
+
+	if t := s.customTool(name); t != nil {
+		return s.callCustomTool(t, args)
+	}
+
+	return "", fmt.Errorf("%w: %s", errUnknownTool, name)
+}
+
+func (s *MCPServer) searchDocs(args map[string]interface{}) (string, error) {
+	query, ok := args["query"].(string)
+	if !ok {
+		return "", fmt.Errorf("query parameter is required")
+	}
+
+	documentation := documentationsArg(args["language"])
+
+	cacheKey := fmt.Sprintf("search:%s:%s", documentation, strings.ToLower(query))
+	if s.searchCache != nil {
+		if data, err := s.searchCache.Get(cacheKey); err == nil && data != nil {
+			return string(data), nil
+		}
+	}
+
+	results := s.docProvider.Search(query, documentation)
+	if s.semantic != nil {
+		if ranked, err := s.semantic.rank(query, documentation, s.docProvider.Topics(), results, s.docProvider.TopicWeight); err == nil {
+			results = ranked
+		} else {
+			log.Printf("Warning: semantic search failed, using keyword results: %v", err)
+		}
+	}
+	results = provider.GroupResults(results)
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if s.searchCache != nil {
+		if err := s.searchCache.Set(cacheKey, data, s.searchCacheTTL); err != nil {
+			log.Printf("Error caching search results: %v", err)
+		}
+	}
+
+	return string(data), nil
+}
+
+// documentationsArg normalizes the language argument of search_docs, a
+// comma-separated string or an array of documentation names, to the
+// comma-separated filter provider.Search takes
+func documentationsArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case string:
+		return strings.Join(provider.SplitDocumentations(v), ",")
+	case []interface{}:
+		var names []string
+		for _, item := range v {
+			if name, ok := item.(string); ok {
+				names = append(names, provider.SplitDocumentations(name)...)
+			}
+		}
+		return strings.Join(names, ",")
+	}
+	return ""
+}
+
+func (s *MCPServer) getDocs(args map[string]interface{}) (string, error) {
+	var id, documentation, topic string
+
+	if v, ok := args["id"].(string); ok {
+		id = v
+	}
+	if v, ok := args["language"].(string); ok {
+		documentation = v
+	}
+	if v, ok := args["topic"].(string); ok {
+		topic = v
+	}
+
+	doc, err := s.docProvider.GetDoc(id, documentation, topic)
+	if err != nil {
+		return "", err
+	}
+
+	if summary, _ := args["summary"].(bool); summary {
+		return s.summarizeDoc(doc)
+	}
+	return doc, nil
+}
+
+func (s *MCPServer) listDocs() (string, error) {
+	languages := s.docProvider.ListDocumentations()
+
+	data, err := json.MarshalIndent(languages, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (s *MCPServer) getGoInfo(args map[string]interface{}) (string, error) {
+	infoType, ok := args["type"].(string)
+	if !ok {
+		return "", fmt.Errorf("type parameter is required (must be 'version' or 'library')")
+	}
+
+	switch infoType {
+	case "version":
+		version, ok := args["version"].(string)
+		if !ok || version == "" {
+			return "", fmt.Errorf("version parameter is required when type is 'version'")
+		}
+
+		versionInfo, err := s.goFetcher.FetchGoVersion(version)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch Go version info: %w", err)
+		}
+
+		return versionInfo.Content, nil
+
+	case "library":
+		importPath, ok := args["importPath"].(string)
+		if !ok || importPath == "" {
+			return "", fmt.Errorf("importPath parameter is required when type is 'library'")
+		}
+
+		version := ""
+		if v, ok := args["version"].(string); ok {
+			version = v
+		}
+
+		libInfo, err := s.goFetcher.FetchLibraryInfo(importPath, version)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch library info: %w", err)
+		}
+
+		return libInfo.Description, nil
+
+	default:
+		return "", fmt.Errorf("invalid type: %s (must be 'version' or 'library')", infoType)
+	}
+}
+
+func (s *MCPServer) getGoVulns(args map[string]interface{}) (string, error) {
+	module, ok := args["module"].(string)
+	if !ok || module == "" {
+		return "", fmt.Errorf("module parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	report, err := s.goVulnFetcher.FetchModuleVulns(module, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Go vulnerabilities: %w", err)
+	}
+
+	return report.Content, nil
+}
+
+func (s *MCPServer) getGoDependencies(args map[string]interface{}) (string, error) {
+	module, ok := args["module"].(string)
+	if !ok || module == "" {
+		return "", fmt.Errorf("module parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+	if version == "" {
+		module, version, _ = strings.Cut(module, "@")
+	}
+
+	depth := 1
+	if v, ok := args["depth"].(float64); ok {
+		depth = int(v)
+	}
+
+	report, err := s.goDepsFetcher.FetchModuleDeps(module, version, depth)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Go dependencies: %w", err)
+	}
+
+	return report.Content, nil
+}
+
+func (s *MCPServer) getNPMInfo(args map[string]interface{}) (string, error) {
+	packageName, ok := args["packageName"].(string)
+	if !ok || packageName == "" {
+		return "", fmt.Errorf("packageName parameter is required")
+	}
+
+	if list, _ := args["listVersions"].(bool); list {
+		limit := 0
+		if v, ok := args["limit"].(float64); ok {
+			limit = int(v)
+		}
+		versions, err := s.npmFetcher.FetchVersions(packageName, limit)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch npm package versions: %w", err)
+		}
+		return versions.Content, nil
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	pkgInfo, err := s.npmFetcher.FetchPackageInfo(packageName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch npm package info: %w", err)
+	}
+
+	return pkgInfo.Content, nil
+}
+
+func (s *MCPServer) getPythonInfo(args map[string]interface{}) (string, error) {
+	packageName, ok := args["packageName"].(string)
+	if !ok || packageName == "" {
+		return "", fmt.Errorf("packageName parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	pkgInfo, err := s.pythonFetcher.FetchPackageInfo(packageName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Python package info: %w", err)
+	}
+
+	return pkgInfo.Content, nil
+}
+
+func (s *MCPServer) getRustInfo(args map[string]interface{}) (string, error) {
+	crateName, ok := args["crateName"].(string)
+	if !ok || crateName == "" {
+		return "", fmt.Errorf("crateName parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	crateInfo, err := s.rustFetcher.FetchCrateInfo(crateName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Rust crate info: %w", err)
+	}
+
+	return crateInfo.Content, nil
+}
+
+func (s *MCPServer) getGemInfo(args map[string]interface{}) (string, error) {
+	gemName, ok := args["gemName"].(string)
+	if !ok || gemName == "" {
+		return "", fmt.Errorf("gemName parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	gemInfo, err := s.rubyGemsFetcher.FetchGemInfo(gemName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Ruby gem info: %w", err)
+	}
+
+	return gemInfo.Content, nil
+}
+
+func (s *MCPServer) getHexInfo(args map[string]interface{}) (string, error) {
+	packageName, ok := args["packageName"].(string)
+	if !ok || packageName == "" {
+		return "", fmt.Errorf("packageName parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	pkgInfo, err := s.hexFetcher.FetchPackageInfo(packageName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Hex package info: %w", err)
+	}
+
+	return pkgInfo.Content, nil
+}
+
+func (s *MCPServer) getCocoaPodInfo(args map[string]interface{}) (string, error) {
+	podName, ok := args["podName"].(string)
+	if !ok || podName == "" {
+		return "", fmt.Errorf("podName parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	podInfo, err := s.cocoaPodsFetcher.FetchPodInfo(podName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch CocoaPod info: %w", err)
+	}
+
+	return podInfo.Content, nil
+}
+
+func (s *MCPServer) getConanInfo(args map[string]interface{}) (string, error) {
+	packageName, ok := args["packageName"].(string)
+	if !ok || packageName == "" {
+		return "", fmt.Errorf("packageName parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	pkgInfo, err := s.conanFetcher.FetchPackageInfo(packageName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Conan package info: %w", err)
+	}
+
+	return pkgInfo.Content, nil
+}
+
+func (s *MCPServer) getPythonVersion(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
+	}
+
+	versionInfo, err := s.pythonFetcher.FetchPythonVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Python version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getNodeInfo(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
+	}
+
+	versionInfo, err := s.nodeFetcher.FetchNodeVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Node.js version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getJavaInfo(args map[string]interface{}) (string, error) {
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	javaInfo, err := s.javaFetcher.FetchJavaInfo(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Java info: %w", err)
+	}
+
+	return javaInfo.Content, nil
+}
+
+func (s *MCPServer) getDenoInfo(args map[string]interface{}) (string, error) {
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	versionInfo, err := s.denoFetcher.FetchDenoVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Deno version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getBunInfo(args map[string]interface{}) (string, error) {
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	versionInfo, err := s.bunFetcher.FetchBunVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Bun version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getNodeSchedule(args map[string]interface{}) (string, error) {
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	scheduleInfo, err := s.nodeFetcher.FetchNodeSchedule(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Node.js release schedule: %w", err)
+	}
+
+	return scheduleInfo.Content, nil
+}
+
+func (s *MCPServer) getTypeScriptInfo(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
+	}
+
+	versionInfo, err := s.typescriptFetcher.FetchTypeScriptVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch TypeScript version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getTypeScriptFeature(args map[string]interface{}) (string, error) {
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return "", fmt.Errorf("name parameter is required")
+	}
+
+	featureInfo, err := s.typescriptFetcher.FetchTypeScriptFeature(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch TypeScript feature info: %w", err)
+	}
+
+	return featureInfo.Content, nil
+}
+
+func (s *MCPServer) getNextJSInfo(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
+	}
+
+	versionInfo, err := s.nextjsFetcher.FetchNextJSVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Next.js version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getNextJSDocs(args map[string]interface{}) (string, error) {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+
+	router := ""
+	if r, ok := args["router"].(string); ok {
+		router = r
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	docInfo, err := s.nextjsFetcher.FetchNextJSDocs(path, router, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Next.js docs: %w", err)
+	}
+
+	return docInfo.Content, nil
+}
+
+func (s *MCPServer) getReactInfo(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
+	}
+
+	versionInfo, err := s.reactFetcher.FetchReactVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch React version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getReactAPI(args map[string]interface{}) (string, error) {
+	symbol, ok := args["symbol"].(string)
+	if !ok || symbol == "" {
+		return "", fmt.Errorf("symbol parameter is required")
+	}
+
+	apiInfo, err := s.reactFetcher.FetchReactAPI(symbol)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch React API reference: %w", err)
+	}
+
+	return apiInfo.Content, nil
+}
+
+func (s *MCPServer) getAnsibleInfo(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
+	}
+
+	versionInfo, err := s.ansibleFetcher.FetchAnsibleVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Ansible version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getAnsibleCollection(args map[string]interface{}) (string, error) {
+	collection, ok := args["collection"].(string)
+	if !ok || collection == "" {
+		return "", fmt.Errorf("collection parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	collectionInfo, err := s.ansibleFetcher.FetchCollection(collection, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Ansible collection info: %w", err)
+	}
+
+	return collectionInfo.Content, nil
+}
+
+func (s *MCPServer) getTerraformInfo(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
+	}
+
+	versionInfo, err := s.terraformFetcher.FetchTerraformVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Terraform version info: %w", err)
 	}
+
+	return versionInfo.Content, nil
 }
 
-func (s *MCPServer) handleToolCall(req Request) Response {
-	var params struct {
-		Name      string                 `json:"name"`
-		Arguments map[string]interface{} `json:"arguments"`
+func (s *MCPServer) findTerraformExamples(args map[string]interface{}) (string, error) {
+	resource, ok := args["resource"].(string)
+	if !ok || resource == "" {
+		return "", fmt.Errorf("resource parameter is required")
 	}
+	provider, _ := args["provider"].(string)
 
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return Response{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &Error{
-				Code:    -32602,
-				Message: fmt.Sprintf("Invalid params: %v", err),
-			},
-		}
+	info, err := s.terraformFetcher.FindTerraformExamples(resource, provider)
+	if err != nil {
+		return "", fmt.Errorf("failed to find Terraform examples: %w", err)
 	}
 
-	var result interface{}
-	var err error
+	return info.Content, nil
+}
 
-	switch params.Name {
-	case "open-context_search_docs":
-		result, err = s.searchDocs(params.Arguments)
-	case "open-context_get_docs":
-		result, err = s.getDocs(params.Arguments)
-	case "open-context_list_docs":
-		result, err = s.listDocs()
-	case "open-context_get_go_info":
-		result, err = s.getGoInfo(params.Arguments)
-	case "open-context_get_npm_info":
-		result, err = s.getNPMInfo(params.Arguments)
-	case "open-context_get_python_info":
-		result, err = s.getPythonInfo(params.Arguments)
-	case "open-context_get_rust_info":
-		result, err = s.getRustInfo(params.Arguments)
-	case "open-context_get_node_info":
-		result, err = s.getNodeInfo(params.Arguments)
-	case "open-context_get_typescript_info":
-		result, err = s.getTypeScriptInfo(params.Arguments)
-	case "open-context_get_nextjs_info":
-		result, err = s.getNextJSInfo(params.Arguments)
-	case "open-context_get_react_info":
-		result, err = s.getReactInfo(params.Arguments)
-	case "open-context_get_ansible_info":
-		result, err = s.getAnsibleInfo(params.Arguments)
-	case "open-context_get_terraform_info":
-		result, err = s.getTerraformInfo(params.Arguments)
-	case "open-context_get_jenkins_info":
-		result, err = s.getJenkinsInfo(params.Arguments)
-	case "open-context_get_kubernetes_info":
-		result, err = s.getKubernetesInfo(params.Arguments)
-	case "open-context_get_helm_info":
-		result, err = s.getHelmInfo(params.Arguments)
-	case "open-context_get_docker_image":
-		result, err = s.getDockerImage(params.Arguments)
-	case "open-context_get_github_action":
-		result, err = s.getGitHubAction(params.Arguments)
-	default:
-		return Response{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &Error{
-				Code:    -32601,
-				Message: fmt.Sprintf("Unknown tool: %s", params.Name),
-			},
-		}
+func (s *MCPServer) getTerraformSchema(args map[string]interface{}) (string, error) {
+	resource, ok := args["resource"].(string)
+	if !ok || resource == "" {
+		return "", fmt.Errorf("resource parameter is required")
 	}
+	provider, _ := args["provider"].(string)
+	version, _ := args["version"].(string)
+	kind, _ := args["kind"].(string)
 
+	info, err := s.terraformFetcher.FetchTerraformSchema(resource, provider, version, kind)
 	if err != nil {
-		return Response{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &Error{
-				Code:    -32000,
-				Message: err.Error(),
-			},
-		}
+		return "", fmt.Errorf("failed to fetch Terraform schema: %w", err)
 	}
 
-	return Response{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result: map[string]interface{}{
-			"content": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": result,
-				},
-			},
-		},
+	return info.Content, nil
+}
+
+func (s *MCPServer) getPulumiInfo(args map[string]interface{}) (string, error) {
+	pkg, _ := args["package"].(string)
+	version, _ := args["version"].(string)
+
+	info, err := s.pulumiFetcher.FetchPulumiInfo(pkg, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Pulumi info: %w", err)
 	}
+
+	return info.Content, nil
 }
 
-func (s *MCPServer) searchDocs(args map[string]interface{}) (string, error) {
-	query, ok := args["query"].(string)
-	if !ok {
-		return "", fmt.Errorf("query parameter is required")
+func (s *MCPServer) getCDKInfo(args map[string]interface{}) (string, error) {
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
 	}
 
-	documentation := ""
-	if langArg, ok := args["language"].(string); ok {
-		documentation = langArg
+	versionInfo, err := s.cdkFetcher.FetchCDKVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch AWS CDK version info: %w", err)
 	}
 
-	results := s.docProvider.Search(query, documentation)
+	return versionInfo.Content, nil
+}
 
-	data, err := json.MarshalIndent(results, "", "  ")
+func (s *MCPServer) getJenkinsInfo(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
+	}
+
+	versionInfo, err := s.jenkinsFetcher.FetchJenkinsVersion(version)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch Jenkins version info: %w", err)
 	}
 
-	return string(data), nil
+	return versionInfo.Content, nil
 }
 
-func (s *MCPServer) getDocs(args map[string]interface{}) (string, error) {
-	var id, documentation, topic string
+func (s *MCPServer) getJenkinsPlugin(args map[string]interface{}) (string, error) {
+	plugin, ok := args["plugin"].(string)
+	if !ok || plugin == "" {
+		return "", fmt.Errorf("plugin parameter is required")
+	}
 
-	if v, ok := args["id"].(string); ok {
-		id = v
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
 	}
-	if v, ok := args["language"].(string); ok {
-		documentation = v
+
+	pluginInfo, err := s.jenkinsFetcher.FetchPlugin(plugin, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Jenkins plugin info: %w", err)
 	}
-	if v, ok := args["topic"].(string); ok {
-		topic = v
+
+	return pluginInfo.Content, nil
+}
+
+func (s *MCPServer) getKubernetesInfo(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
 	}
 
-	doc, err := s.docProvider.GetDoc(id, documentation, topic)
+	versionInfo, err := s.kubernetesFetcher.FetchKubernetesVersion(version)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch Kubernetes version info: %w", err)
 	}
 
-	return doc, nil
+	return versionInfo.Content, nil
 }
 
-func (s *MCPServer) listDocs() (string, error) {
-	languages := s.docProvider.ListDocumentations()
+func (s *MCPServer) getHelmInfo(args map[string]interface{}) (string, error) {
+	version, ok := args["version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("version parameter is required")
+	}
 
-	data, err := json.MarshalIndent(languages, "", "  ")
+	versionInfo, err := s.helmFetcher.FetchHelmVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Helm version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getNginxInfo(args map[string]interface{}) (string, error) {
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	versionInfo, err := s.nginxFetcher.FetchNginxVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch nginx version info: %w", err)
+	}
+
+	return versionInfo.Content, nil
+}
+
+func (s *MCPServer) getHelmChart(args map[string]interface{}) (string, error) {
+	chart, ok := args["chart"].(string)
+	if !ok || chart == "" {
+		return "", fmt.Errorf("chart parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	chartInfo, err := s.helmFetcher.FetchChart(chart, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Helm chart info: %w", err)
+	}
+
+	return chartInfo.Content, nil
+}
+
+func (s *MCPServer) getGitInfo(args map[string]interface{}) (string, error) {
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	info, err := s.gitFetcher.FetchGitVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Git version info: %w", err)
+	}
+
+	return info.Content, nil
+}
+
+func (s *MCPServer) getDockerEngineInfo(args map[string]interface{}) (string, error) {
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	info, err := s.dockerEngineFetcher.FetchDockerEngineVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Docker Engine version info: %w", err)
+	}
+
+	return info.Content, nil
+}
+
+func (s *MCPServer) getDockerImage(args map[string]interface{}) (string, error) {
+	image, ok := args["image"].(string)
+	if !ok || image == "" {
+		return "", fmt.Errorf("image parameter is required")
+	}
+
+	tag, ok := args["tag"].(string)
+	if !ok || tag == "" {
+		return "", fmt.Errorf("tag parameter is required")
+	}
+
+	imageInfo, err := s.dockerFetcher.FetchDockerImage(image, tag)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Docker image info: %w", err)
+	}
+
+	return imageInfo.Content, nil
+}
+
+func (s *MCPServer) getDevDocs(args map[string]interface{}) (string, error) {
+	docset, ok := args["docset"].(string)
+	if !ok || docset == "" {
+		return "", fmt.Errorf("docset parameter is required")
+	}
+
+	info, err := s.devDocsFetcher.FetchDocset(docset)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch DevDocs docset: %w", err)
+	}
+
+	if err := s.loadIndexedDocumentation(info.Documentation, info.Updated); err != nil {
+		return "", fmt.Errorf("failed to load DevDocs docset: %w", err)
+	}
+
+	return info.Content + fmt.Sprintf("\nSearch it with open-context_search_docs using language '%s'.\n", info.Documentation), nil
+}
+
+func (s *MCPServer) getLLMsTxt(args map[string]interface{}) (string, error) {
+	site, ok := args["url"].(string)
+	if !ok || site == "" {
+		return "", fmt.Errorf("url parameter is required")
+	}
+
+	info, err := s.llmsTxtFetcher.FetchLLMsTxt(site)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch llms.txt: %w", err)
+	}
+
+	if err := s.loadIndexedDocumentation(info.Documentation, info.Updated); err != nil {
+		return "", fmt.Errorf("failed to load llms.txt pages: %w", err)
+	}
+
+	return info.Content + fmt.Sprintf("Search it with open-context_search_docs using language '%s'.\n", info.Documentation), nil
+}
+
+func (s *MCPServer) fetchSite(args map[string]interface{}) (string, error) {
+	siteURL, ok := args["url"].(string)
+	if !ok || siteURL == "" {
+		return "", fmt.Errorf("url parameter is required")
+	}
+
+	maxPages := 0
+	if v, ok := args["maxPages"].(float64); ok {
+		maxPages = int(v)
+	}
+
+	info, err := s.siteFetcher.FetchSite(siteURL, maxPages)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch site: %w", err)
+	}
+
+	if err := s.loadIndexedDocumentation(info.Documentation, info.Updated); err != nil {
+		return "", fmt.Errorf("failed to load crawled pages: %w", err)
+	}
+
+	return info.Content + fmt.Sprintf("Search it with open-context_search_docs using language '%s'.\n", info.Documentation), nil
+}
+
+func (s *MCPServer) importDocs(args map[string]interface{}) (string, error) {
+	dir, ok := args["path"].(string)
+	if !ok || dir == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+	name, _ := args["name"].(string)
+
+	// Clients may only import what config.yaml allows, checked before the
+	// directory is read
+	dir, err := fetcher.ResolveImportDir(expandHome(dir), s.importRoots)
 	if err != nil {
 		return "", err
 	}
 
-	return string(data), nil
+	info, err := s.localDocsFetcher.ImportDocs(dir, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to import docs: %w", err)
+	}
+
+	if err := s.loadIndexedDocumentation(info.Documentation, info.Updated); err != nil {
+		return "", fmt.Errorf("failed to load imported pages: %w", err)
+	}
+
+	return info.Content + fmt.Sprintf("Search it with open-context_search_docs using language '%s'.\n", info.Documentation), nil
+}
+
+// loadIndexedDocumentation makes documentation a fetcher wrote to the cache
+// directory searchable without a restart and, when it changed, drops
+// search results cached before it was indexed
+func (s *MCPServer) loadIndexedDocumentation(name string, updated bool) error {
+	if err := s.docProvider.Reload(name); err != nil {
+		return err
+	}
+	if updated && s.searchCache != nil {
+		if err := s.searchCache.DelPrefix("search:"); err != nil {
+			log.Printf("Error clearing cached search results: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *MCPServer) getGitHubAction(args map[string]interface{}) (string, error) {
+	repository, ok := args["repository"].(string)
+	if !ok || repository == "" {
+		return "", fmt.Errorf("repository parameter is required")
+	}
+
+	version := ""
+	if v, ok := args["version"].(string); ok {
+		version = v
+	}
+
+	actionInfo, err := s.githubActionsFetcher.FetchActionInfo(repository, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch GitHub Action info: %w", err)
+	}
+
+	return actionInfo.Content, nil
 }
 
-func (s *MCPServer) getGoInfo(args map[string]interface{}) (string, error) {
-	infoType, ok := args["type"].(string)
-	if !ok {
-		return "", fmt.Errorf("type parameter is required (must be 'version' or 'library')")
+func (s *MCPServer) compareVersions(args map[string]interface{}) (string, error) {
+	product, ok := args["product"].(string)
+	if !ok || product == "" {
+		return "", fmt.Errorf("product parameter is required")
 	}
 
-	switch infoType {
-	case "version":
-		version, ok := args["version"].(string)
-		if !ok || version == "" {
-			return "", fmt.Errorf("version parameter is required when type is 'version'")
-		}
-
-		versionInfo, err := s.goFetcher.FetchGoVersion(version)
-		if err != nil {
-			return "", fmt.Errorf("failed to fetch Go version info: %w", err)
-		}
+	from, ok := args["from"].(string)
+	if !ok || from == "" {
+		return "", fmt.Errorf("from parameter is required")
+	}
 
-		return versionInfo.Content, nil
+	to := ""
+	if v, ok := args["to"].(string); ok {
+		to = v
+	}
 
-	case "library":
-		importPath, ok := args["importPath"].(string)
-		if !ok || importPath == "" {
-			return "", fmt.Errorf("importPath parameter is required when type is 'library'")
-		}
+	changelog, err := s.changelogFetcher.FetchChangelog(product, from, to)
+	if err != nil {
+		return "", fmt.Errorf("failed to compare versions: %w", err)
+	}
 
-		version := ""
-		if v, ok := args["version"].(string); ok {
-			version = v
-		}
+	return changelog.Content, nil
+}
 
-		libInfo, err := s.goFetcher.FetchLibraryInfo(importPath, version)
-		if err != nil {
-			return "", fmt.Errorf("failed to fetch library info: %w", err)
-		}
+func (s *MCPServer) getGitHubReadme(args map[string]interface{}) (string, error) {
+	repository, ok := args["repository"].(string)
+	if !ok || repository == "" {
+		return "", fmt.Errorf("repository parameter is required")
+	}
 
-		return libInfo.Description, nil
+	ref := ""
+	if v, ok := args["ref"].(string); ok {
+		ref = v
+	}
 
-	default:
-		return "", fmt.Errorf("invalid type: %s (must be 'version' or 'library')", infoType)
+	readme, err := s.githubReadmeFetcher.FetchReadme(repository, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch GitHub README: %w", err)
 	}
+
+	return readme.Content, nil
 }
 
-func (s *MCPServer) getNPMInfo(args map[string]interface{}) (string, error) {
-	packageName, ok := args["packageName"].(string)
-	if !ok || packageName == "" {
-		return "", fmt.Errorf("packageName parameter is required")
+func (s *MCPServer) getGitHubRelease(args map[string]interface{}) (string, error) {
+	repository, ok := args["repository"].(string)
+	if !ok || repository == "" {
+		return "", fmt.Errorf("repository parameter is required")
 	}
 
-	version := ""
-	if v, ok := args["version"].(string); ok {
-		version = v
+	tag := ""
+	if v, ok := args["tag"].(string); ok {
+		tag = v
+	}
+	if repo, at, found := strings.Cut(repository, "@"); found && tag == "" {
+		repository, tag = repo, at
 	}
 
-	pkgInfo, err := s.npmFetcher.FetchPackageInfo(packageName, version)
+	release, err := s.githubReleaseFetcher.FetchRelease(repository, tag)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch npm package info: %w", err)
+		return "", fmt.Errorf("failed to fetch GitHub release: %w", err)
 	}
 
-	return pkgInfo.Content, nil
+	return release.Content, nil
 }
 
-func (s *MCPServer) getPythonInfo(args map[string]interface{}) (string, error) {
-	packageName, ok := args["packageName"].(string)
-	if !ok || packageName == "" {
-		return "", fmt.Errorf("packageName parameter is required")
+func (s *MCPServer) getGitLabProject(args map[string]interface{}) (string, error) {
+	project, ok := args["project"].(string)
+	if !ok || project == "" {
+		return "", fmt.Errorf("project parameter is required")
 	}
 
-	version := ""
-	if v, ok := args["version"].(string); ok {
-		version = v
+	info, err := s.gitlabFetcher.FetchProject(project)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch GitLab project: %w", err)
 	}
 
-	pkgInfo, err := s.pythonFetcher.FetchPackageInfo(packageName, version)
+	return info.Content, nil
+}
+
+func (s *MCPServer) getSOAnswers(args map[string]interface{}) (string, error) {
+	query, ok := args["query"].(string)
+	if !ok || query == "" {
+		return "", fmt.Errorf("query parameter is required")
+	}
+	tag, _ := args["tag"].(string)
+	site, _ := args["site"].(string)
+
+	answers, err := s.stackExchangeFetcher.FetchAnswers(query, tag, site)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Python package info: %w", err)
+		return "", fmt.Errorf("failed to fetch Stack Exchange answers: %w", err)
 	}
 
-	return pkgInfo.Content, nil
+	return answers.Content, nil
 }
 
-func (s *MCPServer) getRustInfo(args map[string]interface{}) (string, error) {
-	crateName, ok := args["crateName"].(string)
-	if !ok || crateName == "" {
-		return "", fmt.Errorf("crateName parameter is required")
+func (s *MCPServer) explainError(args map[string]interface{}) (string, error) {
+	message, ok := args["message"].(string)
+	if !ok || message == "" {
+		return "", fmt.Errorf("message parameter is required")
 	}
+	tool, _ := args["tool"].(string)
 
-	version := ""
-	if v, ok := args["version"].(string); ok {
-		version = v
+	explanation, err := s.errorFetcher.ExplainError(tool, message)
+	if err != nil {
+		return "", fmt.Errorf("failed to explain error: %w", err)
 	}
 
-	crateInfo, err := s.rustFetcher.FetchCrateInfo(crateName, version)
+	return explanation.Content, nil
+}
+
+func (s *MCPServer) getRustError(args map[string]interface{}) (string, error) {
+	code, ok := args["code"].(string)
+	if !ok || code == "" {
+		return "", fmt.Errorf("code parameter is required")
+	}
+
+	explanation, err := s.errorFetcher.FetchRustError(code)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Rust crate info: %w", err)
+		return "", fmt.Errorf("failed to fetch Rust error: %w", err)
 	}
 
-	return crateInfo.Content, nil
+	return explanation.Content, nil
 }
 
-func (s *MCPServer) getNodeInfo(args map[string]interface{}) (string, error) {
-	version, ok := args["version"].(string)
-	if !ok || version == "" {
-		return "", fmt.Errorf("version parameter is required")
+func (s *MCPServer) getTSDiagnostic(args map[string]interface{}) (string, error) {
+	code, ok := args["code"].(string)
+	if !ok || code == "" {
+		return "", fmt.Errorf("code parameter is required")
 	}
 
-	versionInfo, err := s.nodeFetcher.FetchNodeVersion(version)
+	explanation, err := s.errorFetcher.FetchTypeScriptDiagnostic(code)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Node.js version info: %w", err)
+		return "", fmt.Errorf("failed to fetch TypeScript diagnostic: %w", err)
 	}
 
-	return versionInfo.Content, nil
+	return explanation.Content, nil
 }
 
-func (s *MCPServer) getTypeScriptInfo(args map[string]interface{}) (string, error) {
-	version, ok := args["version"].(string)
-	if !ok || version == "" {
-		return "", fmt.Errorf("version parameter is required")
+func (s *MCPServer) getHTTPReference(args map[string]interface{}) (string, error) {
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return "", fmt.Errorf("name parameter is required")
 	}
+	kind, _ := args["kind"].(string)
 
-	versionInfo, err := s.typescriptFetcher.FetchTypeScriptVersion(version)
+	info, err := s.httpReferenceFetcher.FetchHTTPReference(kind, name)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch TypeScript version info: %w", err)
+		return "", fmt.Errorf("failed to fetch HTTP reference: %w", err)
 	}
 
-	return versionInfo.Content, nil
+	return info.Content, nil
 }
 
-func (s *MCPServer) getNextJSInfo(args map[string]interface{}) (string, error) {
-	version, ok := args["version"].(string)
-	if !ok || version == "" {
-		return "", fmt.Errorf("version parameter is required")
+func (s *MCPServer) getRFC(args map[string]interface{}) (string, error) {
+	document, ok := args["document"].(string)
+	if !ok || document == "" {
+		return "", fmt.Errorf("document parameter is required")
 	}
+	section, _ := args["section"].(string)
 
-	versionInfo, err := s.nextjsFetcher.FetchNextJSVersion(version)
+	info, err := s.rfcFetcher.FetchRFC(document, section)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Next.js version info: %w", err)
+		return "", fmt.Errorf("failed to fetch RFC: %w", err)
 	}
 
-	return versionInfo.Content, nil
+	return info.Content, nil
 }
 
-func (s *MCPServer) getReactInfo(args map[string]interface{}) (string, error) {
-	version, ok := args["version"].(string)
-	if !ok || version == "" {
-		return "", fmt.Errorf("version parameter is required")
+func (s *MCPServer) getWebSpec(args map[string]interface{}) (string, error) {
+	spec, ok := args["spec"].(string)
+	if !ok || spec == "" {
+		return "", fmt.Errorf("spec parameter is required")
 	}
+	section, _ := args["section"].(string)
 
-	versionInfo, err := s.reactFetcher.FetchReactVersion(version)
+	info, err := s.webSpecFetcher.FetchWebSpec(spec, section)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch React version info: %w", err)
+		return "", fmt.Errorf("failed to fetch web spec: %w", err)
 	}
 
-	return versionInfo.Content, nil
+	return info.Content, nil
 }
 
-func (s *MCPServer) getAnsibleInfo(args map[string]interface{}) (string, error) {
-	version, ok := args["version"].(string)
-	if !ok || version == "" {
-		return "", fmt.Errorf("version parameter is required")
+func (s *MCPServer) getPosixUtil(args map[string]interface{}) (string, error) {
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return "", fmt.Errorf("name parameter is required")
 	}
+	source, _ := args["source"].(string)
 
-	versionInfo, err := s.ansibleFetcher.FetchAnsibleVersion(version)
+	info, err := s.posixFetcher.FetchPosixUtil(name, source)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Ansible version info: %w", err)
+		return "", fmt.Errorf("failed to fetch utility reference: %w", err)
 	}
 
-	return versionInfo.Content, nil
+	return info.Content, nil
 }
 
-func (s *MCPServer) getTerraformInfo(args map[string]interface{}) (string, error) {
-	version, ok := args["version"].(string)
-	if !ok || version == "" {
-		return "", fmt.Errorf("version parameter is required")
+func (s *MCPServer) getManPage(args map[string]interface{}) (string, error) {
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return "", fmt.Errorf("name parameter is required")
 	}
+	section, _ := args["section"].(string)
 
-	versionInfo, err := s.terraformFetcher.FetchTerraformVersion(version)
+	info, err := s.manFetcher.FetchManPage(name, section)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Terraform version info: %w", err)
+		return "", fmt.Errorf("failed to fetch manual page: %w", err)
 	}
 
-	return versionInfo.Content, nil
+	return info.Content, nil
 }
 
-func (s *MCPServer) getJenkinsInfo(args map[string]interface{}) (string, error) {
-	version, ok := args["version"].(string)
-	if !ok || version == "" {
-		return "", fmt.Errorf("version parameter is required")
+func (s *MCPServer) getTldr(args map[string]interface{}) (string, error) {
+	command, ok := args["command"].(string)
+	if !ok || command == "" {
+		return "", fmt.Errorf("command parameter is required")
 	}
+	platform, _ := args["platform"].(string)
+	language, _ := args["language"].(string)
 
-	versionInfo, err := s.jenkinsFetcher.FetchJenkinsVersion(version)
+	info, err := s.tldrFetcher.FetchTldrPage(command, platform, language)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Jenkins version info: %w", err)
+		return "", fmt.Errorf("failed to fetch tldr page: %w", err)
 	}
 
-	return versionInfo.Content, nil
+	return info.Content, nil
 }
 
-func (s *MCPServer) getKubernetesInfo(args map[string]interface{}) (string, error) {
-	version, ok := args["version"].(string)
-	if !ok || version == "" {
-		return "", fmt.Errorf("version parameter is required")
+func (s *MCPServer) getBufModule(args map[string]interface{}) (string, error) {
+	module, ok := args["module"].(string)
+	if !ok || module == "" {
+		return "", fmt.Errorf("module parameter is required")
 	}
+	version, _ := args["version"].(string)
 
-	versionInfo, err := s.kubernetesFetcher.FetchKubernetesVersion(version)
+	info, err := s.bufFetcher.FetchBufModule(module, version)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Kubernetes version info: %w", err)
+		return "", fmt.Errorf("failed to fetch Buf module: %w", err)
 	}
 
-	return versionInfo.Content, nil
+	return info.Content, nil
 }
 
-func (s *MCPServer) getHelmInfo(args map[string]interface{}) (string, error) {
-	version, ok := args["version"].(string)
-	if !ok || version == "" {
-		return "", fmt.Errorf("version parameter is required")
+func (s *MCPServer) getLicense(args map[string]interface{}) (string, error) {
+	license, ok := args["license"].(string)
+	if !ok || license == "" {
+		return "", fmt.Errorf("license parameter is required")
 	}
 
-	versionInfo, err := s.helmFetcher.FetchHelmVersion(version)
+	info, err := s.licenseFetcher.FetchLicense(license)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Helm version info: %w", err)
+		return "", fmt.Errorf("failed to fetch license: %w", err)
 	}
 
-	return versionInfo.Content, nil
+	return info.Content, nil
 }
 
-func (s *MCPServer) getDockerImage(args map[string]interface{}) (string, error) {
-	image, ok := args["image"].(string)
-	if !ok || image == "" {
-		return "", fmt.Errorf("image parameter is required")
+func (s *MCPServer) compareSQLFeature(args map[string]interface{}) (string, error) {
+	feature, ok := args["feature"].(string)
+	if !ok || feature == "" {
+		return "", fmt.Errorf("feature parameter is required")
 	}
 
-	tag, ok := args["tag"].(string)
-	if !ok || tag == "" {
-		return "", fmt.Errorf("tag parameter is required")
+	// Over REST, dialects arrive as one comma-separated string
+	var dialects []string
+	switch v := args["dialects"].(type) {
+	case string:
+		dialects = strings.Split(v, ",")
+	case []interface{}:
+		for _, item := range v {
+			if d, ok := item.(string); ok {
+				dialects = append(dialects, d)
+			}
+		}
 	}
 
-	imageInfo, err := s.dockerFetcher.FetchDockerImage(image, tag)
+	comparison, err := fetcher.CompareSQLFeature(feature, dialects)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Docker image info: %w", err)
+		return "", fmt.Errorf("failed to compare SQL feature: %w", err)
 	}
 
-	return imageInfo.Content, nil
+	return comparison.Content, nil
 }
 
-func (s *MCPServer) getGitHubAction(args map[string]interface{}) (string, error) {
-	repository, ok := args["repository"].(string)
-	if !ok || repository == "" {
-		return "", fmt.Errorf("repository parameter is required")
+func (s *MCPServer) getLocalSymbol(args map[string]interface{}) (string, error) {
+	if s.goplsClient == nil {
+		return "", fmt.Errorf("go_workspace is not configured; set it in config.yaml to a local Go module")
 	}
 
-	version := ""
-	if v, ok := args["version"].(string); ok {
-		version = v
-	}
+	name, _ := args["name"].(string)
+	file, _ := args["file"].(string)
+	line, _ := args["line"].(float64)
+	column, _ := args["column"].(float64)
 
-	actionInfo, err := s.githubActionsFetcher.FetchActionInfo(repository, version)
+	var symbol *gopls.Symbol
+	var err error
+	switch {
+	case file != "" && line > 0 && column > 0:
+		symbol, err = s.goplsClient.Hover(file, int(line), int(column))
+	case name != "":
+		symbol, err = s.goplsClient.Lookup(name)
+	default:
+		return "", fmt.Errorf("name parameter (or file, line, and column) is required")
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch GitHub Action info: %w", err)
+		return "", fmt.Errorf("failed to look up symbol: %w", err)
 	}
 
-	return actionInfo.Content, nil
+	var content strings.Builder
+	title := symbol.Name
+	if title == "" {
+		title = name
+	}
+	fmt.Fprintf(&content, "# %s\n\n", title)
+	fmt.Fprintf(&content, "**Workspace:** %s\n\n", s.goplsClient.Workspace())
+	if symbol.Location != "" {
+		fmt.Fprintf(&content, "**Location:** %s\n\n", symbol.Location)
+	}
+	content.WriteString(symbol.Documentation)
+	content.WriteString("\n")
+
+	return content.String(), nil
 }
 
 func (s *MCPServer) handlePromptsList(req Request) Response {
@@ -899,6 +3052,48 @@ func (s *MCPServer) handlePromptsList(req Request) Response {
 				},
 			},
 		},
+		{
+			"name":        "upgrade-assistant",
+			"description": "Guided upgrade of one package, or of every dependency in a project, with release notes, vulnerabilities, and the manifest edit",
+			"arguments": []map[string]interface{}{
+				{
+					"name":        "project",
+					"description": "Project directory or manifest file to upgrade (alternative to package)",
+					"required":    false,
+				},
+				{
+					"name":        "ecosystem",
+					"description": "Package ecosystem (npm, python, rust, go, ruby, hex, cocoapods, conan)",
+					"required":    false,
+				},
+				{
+					"name":        "package",
+					"description": "Package to upgrade",
+					"required":    false,
+				},
+				{
+					"name":        "from",
+					"description": "Version currently in use",
+					"required":    false,
+				},
+				{
+					"name":        "to",
+					"description": "Target version (defaults to the latest)",
+					"required":    false,
+				},
+			},
+		},
+		{
+			"name":        "dependency-review",
+			"description": "Review a project's dependencies for outdated versions and known vulnerabilities",
+			"arguments": []map[string]interface{}{
+				{
+					"name":        "project",
+					"description": "Project directory or manifest file to review",
+					"required":    true,
+				},
+			},
+		},
 	}
 
 	return Response{
@@ -910,7 +3105,7 @@ func (s *MCPServer) handlePromptsList(req Request) Response {
 	}
 }
 
-func (s *MCPServer) handlePromptsGet(req Request) Response {
+func (s *MCPServer) handlePromptsGet(req Request, sess *session) Response {
 	var params struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments,omitempty"`
@@ -927,25 +3122,63 @@ func (s *MCPServer) handlePromptsGet(req Request) Response {
 		}
 	}
 
-	if params.Name != "use-docs" {
+	var description, promptText string
+	var err error
+	switch params.Name {
+	case "use-docs":
+		description = "Use open-context documentation"
+		promptText, err = s.useDocsPrompt(params.Arguments, sess)
+	case "upgrade-assistant":
+		description = "Upgrade dependencies with open-context"
+		promptText, err = s.upgradeAssistantPrompt(params.Arguments)
+	case "dependency-review":
+		description = "Review dependencies with open-context"
+		promptText, err = s.dependencyReviewPrompt(params.Arguments)
+	default:
+		err = fmt.Errorf("Unknown prompt: %s", params.Name)
+	}
+
+	if err != nil {
 		return Response{
 			JSONRPC: "2.0",
 			ID:      req.ID,
 			Error: &Error{
 				Code:    -32602,
-				Message: fmt.Sprintf("Unknown prompt: %s", params.Name),
+				Message: err.Error(),
 			},
 		}
 	}
 
+	return Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"description": description,
+			"messages": []map[string]interface{}{
+				{
+					"role": "user",
+					"content": map[string]interface{}{
+						"type": "text",
+						"text": promptText,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *MCPServer) useDocsPrompt(args map[string]interface{}, sess *session) (string, error) {
 	// Get documentation parameter if provided
-	documentation := ""
-	if params.Arguments != nil {
-		if doc, ok := params.Arguments["documentation"].(string); ok {
-			documentation = doc
-		}
+	documentation, _ := args["documentation"].(string)
+
+	if documentation != "" && !s.hasDocumentation(documentation) {
+		return "", fmt.Errorf("Unknown documentation: %s", documentation)
 	}
 
+	// Selecting a documentation pins it for the rest of the conversation;
+	// invoking the prompt without one clears the pin
+	sess.pin(documentation)
+
 	// Build the prompt message
 	var promptText string
 	if documentation == "" {
@@ -982,24 +3215,23 @@ func (s *MCPServer) handlePromptsGet(req Request) Response {
 			promptText += "- Ask about standard library: \"How does net/http work?\"\n"
 		}
 
+		if sess != nil {
+			promptText += fmt.Sprintf("\nSearches and topic lookups in this conversation now default to the **%s** documentation. Pass another `language` to look elsewhere.\n", documentation)
+		}
+
 		promptText += "\nI will automatically use these tools to provide accurate, up-to-date information from the official documentation.\n\n"
 		promptText += "What would you like to know?"
 	}
 
-	return Response{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result: map[string]interface{}{
-			"description": "Use open-context documentation",
-			"messages": []map[string]interface{}{
-				{
-					"role": "user",
-					"content": map[string]interface{}{
-						"type": "text",
-						"text": promptText,
-					},
-				},
-			},
-		},
+	return promptText, nil
+}
+
+// hasDocumentation reports whether a documentation with the given name is loaded
+func (s *MCPServer) hasDocumentation(name string) bool {
+	for _, doc := range s.docProvider.ListDocumentations() {
+		if doc.Name == name {
+			return true
+		}
 	}
+	return false
 }
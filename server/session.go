@@ -0,0 +1,169 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// sessionIdleTimeout is how long an HTTP session keeps its state without requests
+const sessionIdleTimeout = time.Hour
+
+// maxSessions caps the HTTP sessions kept at once; opening one more drops
+// the least recently used
+const maxSessions = 10000
+
+// session holds the state of one conversation. The stdio transport serves a
+// single conversation per process; over HTTP a session is named by the
+// Mcp-Session-Id the server issued on initialize or the clientId of an SSE
+// connection. A nil session keeps no state.
+type session struct {
+	mu sync.Mutex
+	// pinnedDocs is the documentation selected with the use-docs prompt;
+	// search_docs and get_docs default to it
+	pinnedDocs string
+	lastUsed   time.Time
+}
+
+func (s *session) pinned() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pinnedDocs
+}
+
+func (s *session) pin(documentation string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pinnedDocs = documentation
+}
+
+// applyPinnedDocs defaults the language argument of search_docs and get_docs
+// to the pinned documentation. An explicit language wins, and get_docs by ID
+// is left alone since the ID may come from another documentation.
+func applyPinnedDocs(sess *session, tool string, args map[string]interface{}) map[string]interface{} {
+	pinned := sess.pinned()
+	if pinned == "" {
+		return args
+	}
+
+	switch tool {
+	case "open-context_search_docs":
+	case "open-context_get_docs":
+		if id, _ := args["id"].(string); id != "" {
+			return args
+		}
+	default:
+		return args
+	}
+
+	if documentationsArg(args["language"]) != "" {
+		return args
+	}
+
+	withDefault := make(map[string]interface{}, len(args)+1)
+	for k, v := range args {
+		withDefault[k] = v
+	}
+	withDefault["language"] = pinned
+	return withDefault
+}
+
+// sessionStore tracks HTTP sessions. Only the server names them, so clients
+// cannot make it keep state under IDs of their own. Sessions idle for longer
+// than sessionIdleTimeout are dropped, and at most maxSessions are kept.
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{sessions: make(map[string]*session)}
+}
+
+// open starts a session under a new random ID, for the Mcp-Session-Id
+// header of an initialize response
+func (st *sessionStore) open() (string, *session) {
+	id := newSessionID()
+	return id, st.attach(id)
+}
+
+// attach returns the session of an SSE client, starting it on the client's
+// first message. The caller checks that the server issued id.
+func (st *sessionStore) attach(id string) *session {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	sess, ok := st.sessions[id]
+	if !ok {
+		st.makeRoom()
+		sess = &session{}
+		st.sessions[id] = sess
+	}
+	sess.touch()
+	return sess
+}
+
+// get returns the open session named id. It reports false when the server
+// did not issue id or has since dropped the session.
+func (st *sessionStore) get(id string) (*session, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	sess, ok := st.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	sess.touch()
+	return sess, true
+}
+
+// makeRoom drops idle sessions, then the least recently used ones while
+// maxSessions are open. Callers hold st.mu.
+func (st *sessionStore) makeRoom() {
+	now := time.Now()
+	var oldestID string
+	var oldest time.Time
+	for id, sess := range st.sessions {
+		sess.mu.Lock()
+		lastUsed := sess.lastUsed
+		sess.mu.Unlock()
+		if now.Sub(lastUsed) > sessionIdleTimeout {
+			delete(st.sessions, id)
+		} else if oldestID == "" || lastUsed.Before(oldest) {
+			oldestID, oldest = id, lastUsed
+		}
+	}
+	if len(st.sessions) >= maxSessions {
+		delete(st.sessions, oldestID)
+	}
+}
+
+func (s *session) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastUsed = time.Now()
+}
+
+// newSessionID returns a random session ID. Knowing an ID is enough to use
+// its session, so IDs must not be guessable.
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("session-%d", time.Now().UnixNano())
+	}
+	return "session-" + hex.EncodeToString(b)
+}
+
+func (st *sessionStore) remove(id string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.sessions, id)
+}
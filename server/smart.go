@@ -0,0 +1,316 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/incu6us/open-context/fetcher"
+	"github.com/incu6us/open-context/provider"
+)
+
+const (
+	// smartSearchResults caps the related topics listed by smart_docs
+	smartSearchResults = 5
+)
+
+// Query intents recognized by smart_docs
+const (
+	intentPackage = "package lookup"
+	intentVersion = "version question"
+	intentConcept = "concept search"
+)
+
+var (
+	versionTokenPattern = regexp.MustCompile(`^(?:go|v)?(\d+(?:\.\d+){0,2}(?:[-+][0-9A-Za-z.-]+)?)$`)
+	goImportPathPattern = regexp.MustCompile(`^[a-z0-9.-]+\.[a-z]{2,}(/[A-Za-z0-9._~-]+)+$`)
+	npmScopedPattern    = regexp.MustCompile(`^@[a-z0-9._-]+/[a-z0-9._-]+$`)
+)
+
+// registryKeywords name a package registry; "python", "go", and "node" are
+// left out since they usually name a language or runtime, not a registry
+var registryKeywords = map[string]string{
+	"npm":       "npm",
+	"pypi":      "python",
+	"pip":       "python",
+	"crate":     "rust",
+	"crates":    "rust",
+	"cargo":     "rust",
+	"gem":       "ruby",
+	"rubygems":  "ruby",
+	"hex":       "hex",
+	"cocoapods": "cocoapods",
+	"pod":       "cocoapods",
+	"conan":     "conan",
+}
+
+// versionTools maps the products with release tools to the tool describing
+// one release
+var versionTools = map[string]string{
+	"go":         "open-context_get_go_info",
+	"golang":     "open-context_get_go_info",
+	"node":       "open-context_get_node_info",
+	"nodejs":     "open-context_get_node_info",
+	"node.js":    "open-context_get_node_info",
+	"typescript": "open-context_get_typescript_info",
+	"ts":         "open-context_get_typescript_info",
+	"react":      "open-context_get_react_info",
+	"next":       "open-context_get_nextjs_info",
+	"nextjs":     "open-context_get_nextjs_info",
+	"next.js":    "open-context_get_nextjs_info",
+	"kubernetes": "open-context_get_kubernetes_info",
+	"k8s":        "open-context_get_kubernetes_info",
+	"terraform":  "open-context_get_terraform_info",
+	"ansible":    "open-context_get_ansible_info",
+	"jenkins":    "open-context_get_jenkins_info",
+	"helm":       "open-context_get_helm_info",
+}
+
+// queryStopwords are dropped when picking a package name or search terms
+var queryStopwords = map[string]bool{
+	"a": true, "about": true, "an": true, "and": true, "are": true, "changed": true,
+	"changes": true, "do": true, "docs": true, "documentation": true, "does": true,
+	"for": true, "get": true, "how": true, "i": true, "in": true, "info": true,
+	"install": true, "is": true, "latest": true, "library": true, "me": true,
+	"module": true, "new": true, "of": true, "on": true, "package": true,
+	"notes": true, "release": true, "show": true, "tell": true, "the": true, "to": true,
+	"use": true, "using": true, "version": true, "what": true, "what's": true,
+	"whats": true, "with": true, "work": true, "works": true,
+}
+
+// smartRoute is the outcome of classifying a smart_docs query
+type smartRoute struct {
+	intent string
+	// For package lookups
+	ecosystem, name, version string
+	// For version questions
+	tool string
+	// terms are the significant words, used for concept search
+	terms []string
+}
+
+// classifyQuery decides whether a query asks about a package, a release of
+// a language or tool, or a concept. An ecosystem hint forces a package
+// lookup when the query names a package.
+func classifyQuery(query, ecosystemHint string) smartRoute {
+	var tokens []string
+	for _, t := range strings.Fields(query) {
+		t = strings.Trim(t, "?!,;:\"'()`")
+		t = strings.TrimSuffix(t, ".")
+		if t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+
+	route := smartRoute{intent: intentConcept}
+	var ecosystem, product, version string
+	var candidates []string
+
+	for _, t := range tokens {
+		// "express@4.18.2" carries its version
+		if i := strings.LastIndex(t, "@"); i > 0 && versionTokenPattern.MatchString(t[i+1:]) {
+			t, version = t[:i], versionTokenPattern.FindStringSubmatch(t[i+1:])[1]
+		}
+		lower := strings.ToLower(t)
+
+		// "npm:express" and "pypi:requests" name the registry explicitly
+		if prefix, name, ok := strings.Cut(lower, ":"); ok && registryKeywords[prefix] != "" && name != "" {
+			ecosystem = registryKeywords[prefix]
+			candidates = append([]string{name}, candidates...)
+			continue
+		}
+
+		switch {
+		case registryKeywords[lower] != "":
+			ecosystem = registryKeywords[lower]
+		case versionTools[lower] != "":
+			product = lower
+		case strings.HasPrefix(lower, "go1.") && versionTokenPattern.MatchString(lower):
+			product, version = "go", versionTokenPattern.FindStringSubmatch(lower)[1]
+		case versionTokenPattern.MatchString(lower):
+			version = versionTokenPattern.FindStringSubmatch(lower)[1]
+		case goImportPathPattern.MatchString(lower):
+			ecosystem = "go"
+			candidates = append([]string{t}, candidates...)
+		case npmScopedPattern.MatchString(lower):
+			if ecosystem == "" {
+				ecosystem = "npm"
+			}
+			candidates = append([]string{lower}, candidates...)
+		case !queryStopwords[lower]:
+			candidates = append(candidates, lower)
+			route.terms = append(route.terms, lower)
+		}
+	}
+
+	if ecosystemHint != "" {
+		ecosystem = ecosystemHint
+		// With a hint the product keyword is the package ("react" on npm)
+		if product != "" {
+			candidates = append([]string{product}, candidates...)
+		}
+	}
+
+	switch {
+	case ecosystem != "" && len(candidates) > 0:
+		route.intent = intentPackage
+		route.ecosystem, route.name = ecosystem, candidates[0]
+		route.version = version
+		if ecosystem == "go" && version != "" && !strings.HasPrefix(version, "v") {
+			route.version = "v" + version
+		}
+	case product != "" && version != "":
+		route.intent = intentVersion
+		route.tool = versionTools[product]
+		route.version = version
+	}
+
+	if product != "" {
+		route.terms = append(route.terms, product)
+	}
+	return route
+}
+
+func (s *MCPServer) smartDocs(args map[string]interface{}) (string, error) {
+	query, ok := args["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return "", fmt.Errorf("query parameter is required")
+	}
+
+	hint := ""
+	if v, ok := args["ecosystem"].(string); ok && v != "" {
+		canonical, err := fetcher.NormalizeEcosystem(v)
+		if err != nil {
+			return "", err
+		}
+		hint = canonical
+	}
+
+	route := classifyQuery(query, hint)
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# %s\n\n", strings.TrimSpace(query))
+
+	primary, source, err := s.answerRoute(route)
+	if err != nil {
+		fmt.Fprintf(&content, "**Note:** the %s failed (%v), so these are documentation search results instead.\n\n", route.intent, err)
+		route.intent = intentConcept
+	} else if route.intent != intentConcept {
+		fmt.Fprintf(&content, "**Intent:** %s via `%s`\n\n", route.intent, source)
+		content.WriteString(strings.TrimSpace(primary))
+		content.WriteString("\n\n")
+	}
+
+	// Related topics from the documentation cache round out every answer;
+	// for concept searches they are the answer
+	results := s.smartSearch(route.terms)
+	if route.intent == intentConcept {
+		fmt.Fprintf(&content, "**Intent:** %s via `open-context_search_docs`\n\n", intentConcept)
+		if len(results) == 0 {
+			content.WriteString("No matching documentation topics found. Try `open-context_list_docs` to see what is available.\n")
+			return content.String(), nil
+		}
+
+		top := results[0]
+		doc, err := s.docProvider.GetDoc(top.ID, top.Documentation, "")
+		if err == nil {
+			content.WriteString(strings.TrimSpace(doc))
+			content.WriteString("\n\n")
+			results = results[1:]
+		}
+	}
+
+	if len(results) > 0 {
+		content.WriteString("## Related Documentation\n\n")
+		for _, r := range results[:min(len(results), smartSearchResults)] {
+			fmt.Fprintf(&content, "- **%s** (`%s`, id `%s`)", r.Title, r.Documentation, r.ID)
+			if r.Description != "" {
+				fmt.Fprintf(&content, ": %s", r.Description)
+			}
+			content.WriteString("\n")
+			for _, related := range r.Related {
+				fmt.Fprintf(&content, "  - also in `%s`, id `%s`\n", related.Documentation, related.ID)
+			}
+		}
+	}
+
+	return content.String(), nil
+}
+
+// answerRoute runs the tool chosen for a package lookup or version question
+// and returns its output and the tool name
+func (s *MCPServer) answerRoute(route smartRoute) (string, string, error) {
+	switch route.intent {
+	case intentPackage:
+		pkg, err := s.fetchPackage(route.ecosystem, route.name, route.version)
+		if err != nil {
+			return "", "", err
+		}
+		return pkg.Content, packageTools[pkg.Ecosystem], nil
+
+	case intentVersion:
+		args := map[string]interface{}{"version": route.version}
+		if route.tool == "open-context_get_go_info" {
+			args["type"] = "version"
+		}
+		result, err := s.callTool(route.tool, args)
+		return result, route.tool, err
+	}
+	return "", "", nil
+}
+
+// packageTools names the tool behind each ecosystem handled by fetchPackage
+var packageTools = map[string]string{
+	"npm":       "open-context_get_npm_info",
+	"python":    "open-context_get_python_info",
+	"rust":      "open-context_get_rust_info",
+	"go":        "open-context_get_go_info",
+	"ruby":      "open-context_get_gem_info",
+	"hex":       "open-context_get_hex_info",
+	"cocoapods": "open-context_get_cocoapod_info",
+	"conan":     "open-context_get_conan_info",
+}
+
+// smartSearch searches the documentation for the query terms. The keyword
+// search matches whole phrases, so when the phrase finds nothing each term
+// is searched on its own and topics matching more terms rank higher.
+func (s *MCPServer) smartSearch(terms []string) []provider.SearchResult {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	phrase := strings.Join(terms, " ")
+	results := s.docProvider.Search(phrase, "")
+	if len(results) == 0 && len(terms) > 1 {
+		byTopic := make(map[string]*provider.SearchResult)
+		var order []string
+		for _, term := range terms {
+			if len(term) < 3 {
+				continue
+			}
+			for _, r := range s.docProvider.Search(term, "") {
+				key := r.Documentation + "\x00" + r.ID
+				if existing, ok := byTopic[key]; ok {
+					existing.Score += r.Score
+					continue
+				}
+				byTopic[key] = &r
+				order = append(order, key)
+			}
+		}
+		for _, key := range order {
+			results = append(results, *byTopic[key])
+		}
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Score > results[j].Score
+		})
+	}
+
+	if s.semantic != nil {
+		if ranked, err := s.semantic.rank(phrase, "", s.docProvider.Topics(), results, s.docProvider.TopicWeight); err == nil {
+			results = ranked
+		}
+	}
+	return provider.GroupResults(results)
+}
@@ -0,0 +1,395 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/incu6us/open-context/config"
+)
+
+const (
+	// sseHeartbeatInterval is how often an SSE stream gets a heartbeat
+	// event, so proxies do not close it as idle
+	sseHeartbeatInterval = 15 * time.Second
+
+	// sseResumeWindow is how long a disconnected SSE client is kept, with
+	// the events it has not received, for it to reconnect
+	sseResumeWindow = 5 * time.Minute
+
+	// sseReplayLimit is how many delivered events a client keeps for a
+	// reconnect that names an earlier event
+	sseReplayLimit = 256
+
+	// sseRetry is the reconnect delay suggested to EventSource clients
+	sseRetry = 3 * time.Second
+
+	// sseWriteTimeout bounds each write to an SSE stream, in place of the
+	// server's WriteTimeout, which would end every stream after 30 seconds
+	sseWriteTimeout = 10 * time.Second
+
+	// sseQueueEvents and sseQueueBytes are the default bounds on the
+	// undelivered events of a client
+	sseQueueEvents = 1024
+	sseQueueBytes  = 16 << 20
+)
+
+// Drop policies for an event that does not fit a client's queue
+const (
+	sseDropOldest = "drop_oldest"
+	sseDropNewest = "drop_newest"
+	sseDisconnect = "disconnect"
+)
+
+var (
+	// errSSEQueueFull is returned for an event dropped by drop_newest, or
+	// one larger than the whole queue
+	errSSEQueueFull = errors.New("queue full")
+	// errSSEOverflow is returned when the disconnect policy closed the client
+	errSSEOverflow = errors.New("queue overflow")
+)
+
+// sseQueueLimits bounds the undelivered events of each client
+type sseQueueLimits struct {
+	events int
+	bytes  int
+	policy string
+}
+
+func newSSEQueueLimits(cfg config.SSEConfig) sseQueueLimits {
+	limits := sseQueueLimits{events: cfg.QueueEvents, bytes: cfg.QueueBytes, policy: cfg.DropPolicy}
+	if limits.events <= 0 {
+		limits.events = sseQueueEvents
+	}
+	if limits.bytes <= 0 {
+		limits.bytes = sseQueueBytes
+	}
+	switch limits.policy {
+	case sseDropOldest, sseDropNewest, sseDisconnect:
+	case "":
+		limits.policy = sseDropOldest
+	default:
+		log.Printf("Warning: unknown sse drop_policy %q, using %s", limits.policy, sseDropOldest)
+		limits.policy = sseDropOldest
+	}
+	return limits
+}
+
+// sseMetrics counts SSE events across clients since the server started
+type sseMetrics struct {
+	queued    atomic.Uint64
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	// overflows counts clients closed by the disconnect policy
+	overflows atomic.Uint64
+}
+
+// sseClient is an SSE session. It outlives its connection by
+// sseResumeWindow: events queued while it is disconnected are sent when it
+// reconnects.
+type sseClient struct {
+	id     string
+	limits sseQueueLimits
+	// done is closed when the client expires
+	done chan struct{}
+
+	mu sync.Mutex
+	// seq is the sequence number of the last queued event
+	seq uint64
+	// delivered is the sequence number of the last event written to the
+	// current connection
+	delivered uint64
+	// events are the undelivered events and up to sseReplayLimit
+	// delivered ones, oldest first
+	events []sseEvent
+	// pending and pendingBytes count the undelivered events and their size
+	pending      int
+	pendingBytes int
+	// dropping is set from the first dropped event until the queue drains,
+	// so a stalled client is logged once
+	dropping bool
+	// wake is closed and replaced when an event is queued
+	wake chan struct{}
+	// conn is closed when the current connection is replaced; nil while
+	// the client is disconnected
+	conn   chan struct{}
+	expiry *time.Timer
+	closed bool
+}
+
+// sseEvent is one event written to an SSE stream
+type sseEvent struct {
+	name string
+	// seq numbers the queued events of a client, starting at 1. Events
+	// with seq 0, such as heartbeats, are not replayed.
+	seq  uint64
+	data []byte
+}
+
+func newSSEClient(limits sseQueueLimits) *sseClient {
+	return &sseClient{
+		id:     newSSEClientID(),
+		limits: limits,
+		done:   make(chan struct{}),
+		wake:   make(chan struct{}),
+	}
+}
+
+// newSSEClientID returns a random client ID. Knowing an ID is enough to
+// resume its stream, so IDs must not be guessable.
+func newSSEClientID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("client-%d", time.Now().UnixNano())
+	}
+	return "client-" + hex.EncodeToString(b)
+}
+
+// eventID is the SSE id of an event: the client ID and the event's
+// sequence number, so a reconnecting EventSource names both in its
+// Last-Event-ID header
+func (c *sseClient) eventID(seq uint64) string {
+	return fmt.Sprintf("%s:%d", c.id, seq)
+}
+
+// parseEventID splits an event ID made by eventID
+func parseEventID(id string) (clientID string, seq uint64, ok bool) {
+	i := strings.LastIndexByte(id, ':')
+	if i < 0 {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseUint(id[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return id[:i], seq, true
+}
+
+// push queues an event for the client's current or next connection. When
+// the queue is full, the drop policy decides; push returns how many events
+// were dropped, and errSSEQueueFull or errSSEOverflow if the new event was
+// not queued.
+func (c *sseClient) push(ev sseEvent) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, fmt.Errorf("client %s disconnected", c.id)
+	}
+	if len(ev.data) > c.limits.bytes {
+		// The event could never fit
+		return 1, errSSEQueueFull
+	}
+
+	var dropped int
+	if !c.fits(len(ev.data)) {
+		switch c.limits.policy {
+		case sseDropNewest:
+			return 1, errSSEQueueFull
+		case sseDisconnect:
+			dropped = c.pending + 1
+			c.closeLocked()
+			return dropped, errSSEOverflow
+		default:
+			dropped = c.dropOldest(len(ev.data))
+		}
+	}
+
+	c.seq++
+	ev.seq = c.seq
+	c.events = append(c.events, ev)
+	c.pending++
+	c.pendingBytes += len(ev.data)
+	c.trim()
+
+	close(c.wake)
+	c.wake = make(chan struct{})
+	return dropped, nil
+}
+
+// fits reports whether an event of size bytes fits the queue. Callers hold
+// c.mu.
+func (c *sseClient) fits(size int) bool {
+	return c.pending+1 <= c.limits.events && c.pendingBytes+size <= c.limits.bytes
+}
+
+// dropOldest drops the oldest undelivered events until an event of size
+// bytes fits, returning how many it dropped. Callers hold c.mu.
+func (c *sseClient) dropOldest(size int) int {
+	start := len(c.events) - c.pending
+	end := start
+	for end < len(c.events) && !c.fits(size) {
+		c.pending--
+		c.pendingBytes -= len(c.events[end].data)
+		end++
+	}
+	c.events = append(c.events[:start], c.events[end:]...)
+	return end - start
+}
+
+// trim drops the oldest delivered events beyond sseReplayLimit. Callers
+// hold c.mu.
+func (c *sseClient) trim() {
+	var delivered int
+	for _, ev := range c.events {
+		if ev.seq > c.delivered {
+			break
+		}
+		delivered++
+	}
+	if drop := delivered - sseReplayLimit; drop > 0 {
+		c.events = c.events[drop:]
+	}
+}
+
+// since returns the queued events after seq, how many events after seq
+// are no longer kept, and a channel closed when the next event is queued
+func (c *sseClient) since(seq uint64) ([]sseEvent, uint64, <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var lost uint64
+	start := len(c.events)
+	for i, ev := range c.events {
+		if ev.seq > seq {
+			start = i
+			if ev.seq > seq+1 {
+				lost = ev.seq - seq - 1
+			}
+			break
+		}
+	}
+	if start == len(c.events) && c.seq > seq {
+		lost = c.seq - seq
+	}
+
+	events := make([]sseEvent, len(c.events)-start)
+	copy(events, c.events[start:])
+	return events, lost, c.wake
+}
+
+// markDelivered records that the events up to seq were written
+func (c *sseClient) markDelivered(seq uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.delivered = seq
+	c.recount()
+	c.trim()
+}
+
+// recount counts the undelivered events after delivered changed. Callers
+// hold c.mu.
+func (c *sseClient) recount() {
+	c.pending, c.pendingBytes = 0, 0
+	for _, ev := range c.events {
+		if ev.seq > c.delivered {
+			c.pending++
+			c.pendingBytes += len(ev.data)
+		}
+	}
+	if c.pending == 0 {
+		c.dropping = false
+	}
+}
+
+// startDropping reports whether the client has just started dropping
+// events, for logging
+func (c *sseClient) startDropping() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dropping {
+		return false
+	}
+	c.dropping = true
+	return true
+}
+
+// queued returns the number and size of the undelivered events, and
+// whether a connection is attached
+func (c *sseClient) queued() (int, int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending, c.pendingBytes, c.conn != nil
+}
+
+// attach makes a new connection, resuming after event seq, the client's
+// current one; a connection it replaces is told to stop. It fails once the
+// client has expired.
+func (c *sseClient) attach(seq uint64) (<-chan struct{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, false
+	}
+	if c.conn != nil {
+		close(c.conn)
+	}
+	if c.expiry != nil {
+		c.expiry.Stop()
+		c.expiry = nil
+	}
+	c.conn = make(chan struct{})
+	c.delivered = seq
+	c.recount()
+	return c.conn, true
+}
+
+// detach ends a connection. If it is still the current one, the client
+// expires after sseResumeWindow unless it reconnects.
+func (c *sseClient) detach(conn <-chan struct{}, expire func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || c.conn == nil || (<-chan struct{})(c.conn) != conn {
+		return
+	}
+	c.conn = nil
+	c.expiry = time.AfterFunc(sseResumeWindow, expire)
+}
+
+// expire closes a client that has stayed disconnected, reporting whether it
+// did; a client that reconnected in the meantime is left alone
+func (c *sseClient) expire() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || c.conn != nil {
+		return false
+	}
+	c.closeLocked()
+	return true
+}
+
+// closeLocked closes the client and releases its events. Callers hold c.mu.
+func (c *sseClient) closeLocked() {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.events = nil
+	c.pending, c.pendingBytes = 0, 0
+	if c.expiry != nil {
+		c.expiry.Stop()
+	}
+	close(c.done)
+}
+
+// writeSSEEvent writes an event in the text/event-stream format
+func writeSSEEvent(w io.Writer, client *sseClient, ev sseEvent) error {
+	if ev.seq > 0 {
+		if _, err := fmt.Fprintf(w, "id: %s\n", client.eventID(ev.seq)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.name, ev.data)
+	return err
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// streamThreshold is the tool result size above which a request sent
+	// with the clientId of an SSE connection is answered over that
+	// connection in chunks instead of in the POST response. Tools return
+	// their whole text, so this bounds the size of each message rather than
+	// the time to the first one.
+	streamThreshold = 64 * 1024
+
+	// streamChunkSize bounds the text of one chunk
+	streamChunkSize = 16 * 1024
+
+	// streamChunkMethod is the method of the notifications carrying chunks
+	streamChunkMethod = "notifications/chunk"
+)
+
+// streamChunk is one part of a tool result's text, tied to the request it
+// answers
+type streamChunk struct {
+	ID    interface{} `json:"id"`
+	Index int         `json:"index"`
+	Total int         `json:"total"`
+	Text  string      `json:"text"`
+}
+
+// streamNotification is the JSON-RPC notification a chunk is sent in, so
+// chunks are message events like the rest of the stream
+type streamNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  streamChunk `json:"params"`
+}
+
+// streamableText returns the text of a successful tool call response with
+// a single text content item
+func streamableText(resp Response) (string, bool) {
+	result, ok := resp.Result.(map[string]interface{})
+	if !ok || resp.Error != nil {
+		return "", false
+	}
+	content, ok := result["content"].([]map[string]interface{})
+	if !ok || len(content) != 1 || content[0]["type"] != "text" {
+		return "", false
+	}
+	text, ok := content[0]["text"].(string)
+	return text, ok
+}
+
+// chunkText splits text into parts of at most size bytes, preferring to
+// cut after a newline in the second half of a part and never inside a
+// UTF-8 sequence
+func chunkText(text string, size int) []string {
+	var chunks []string
+	for len(text) > size {
+		end := size
+		if nl := strings.LastIndexByte(text[:end], '\n'); nl >= size/2 {
+			end = nl + 1
+		}
+		for end > 0 && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		if end == 0 {
+			end = size
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+// streamResponse sends chunks of a tool result to an SSE client as message
+// events; the last one has index total-1
+func (h *HTTPServer) streamResponse(client *sseClient, id interface{}, chunks []string) error {
+	for i, text := range chunks {
+		data, err := json.Marshal(streamNotification{
+			JSONRPC: "2.0",
+			Method:  streamChunkMethod,
+			Params:  streamChunk{ID: id, Index: i, Total: len(chunks), Text: text},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal chunk: %w", err)
+		}
+		if err := h.sendEvent(client, sseEvent{name: "message", data: data}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
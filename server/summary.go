@@ -0,0 +1,39 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/summarize"
+)
+
+// newSummarizer creates the LLM summarizer of the summarizer config section.
+// Summaries are cached on disk by content hash, so a topic is sent to the
+// API again only after it changes.
+func newSummarizer(cfg config.Summarizer, cacheDir string) (summarize.Summarizer, error) {
+	client, err := summarize.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return summarize.NewCache(client, filepath.Join(cacheDir, "summaries"), client.Model()), nil
+}
+
+// summarizeDoc shortens a documentation topic with the configured LLM,
+// falling back to an extractive summary when none is configured or it fails
+func (s *MCPServer) summarizeDoc(content string) (string, error) {
+	if s.summarizer != nil {
+		summary, err := s.summarizer.Summarize(content)
+		if err == nil {
+			return summary, nil
+		}
+		log.Printf("Warning: LLM summary failed, using extractive summary: %v", err)
+	}
+
+	summary, err := summarize.Extractive{}.Summarize(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize documentation: %w", err)
+	}
+	return summary, nil
+}
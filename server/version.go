@@ -0,0 +1,60 @@
+package server
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// BuildInfo identifies the running build
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	GoVersion string `json:"goVersion"`
+	SourceURL string `json:"sourceURL,omitempty"`
+}
+
+// buildInfo is reported by initialize, /health, and /version
+var buildInfo = defaultBuildInfo()
+
+// SetBuildInfo records the build metadata set through ldflags at release
+// time. Empty fields keep what the Go toolchain embedded in the binary.
+func SetBuildInfo(info BuildInfo) {
+	if info.Version != "" {
+		buildInfo.Version = info.Version
+	}
+	if info.Commit != "" {
+		buildInfo.Commit = info.Commit
+	}
+	if info.GoVersion != "" {
+		buildInfo.GoVersion = info.GoVersion
+	}
+	if info.SourceURL != "" {
+		buildInfo.SourceURL = info.SourceURL
+	}
+}
+
+// Build returns the metadata of the running build
+func Build() BuildInfo {
+	return buildInfo
+}
+
+// defaultBuildInfo reads the module version and VCS revision the Go
+// toolchain embeds, so builds made with go install or go build without
+// ldflags still identify themselves
+func defaultBuildInfo() BuildInfo {
+	info := BuildInfo{Version: "dev", GoVersion: runtime.Version()}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if v := bi.Main.Version; v != "" && v != "(devel)" {
+		info.Version = strings.TrimPrefix(v, "v")
+	}
+	for _, setting := range bi.Settings {
+		if setting.Key == "vcs.revision" {
+			info.Commit = setting.Value
+		}
+	}
+	return info
+}
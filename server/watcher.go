@@ -0,0 +1,164 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/incu6us/open-context/manifest"
+)
+
+const (
+	// manifestPollInterval is how often watched manifests are checked for changes
+	manifestPollInterval = 5 * time.Second
+)
+
+// manifestWatcher polls dependency manifests and prefetches docs for
+// dependencies it has not seen before
+type manifestWatcher struct {
+	server   *MCPServer
+	paths    []string
+	modTimes map[string]time.Time
+	seen     map[string]bool
+}
+
+// WatchManifests starts watching the given manifests (or project directories
+// containing them) in the background. Docs for every dependency are prefetched
+// on the first scan, and for newly added dependencies whenever a manifest changes.
+func (s *MCPServer) WatchManifests(paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	go newManifestWatcher(s, paths).run()
+}
+
+// PrefetchManifests fetches docs for every dependency of the given
+// manifests (or project directories containing them) once, returning when
+// done
+func (s *MCPServer) PrefetchManifests(paths []string) {
+	newManifestWatcher(s, paths).scan()
+}
+
+func newManifestWatcher(s *MCPServer, paths []string) *manifestWatcher {
+	return &manifestWatcher{
+		server:   s,
+		paths:    paths,
+		modTimes: make(map[string]time.Time),
+		seen:     make(map[string]bool),
+	}
+}
+
+func (w *manifestWatcher) run() {
+	ticker := time.NewTicker(manifestPollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.scan()
+		<-ticker.C
+	}
+}
+
+// scan re-parses manifests whose modification time (or that of their lockfile)
+// changed and prefetches new dependencies
+func (w *manifestWatcher) scan() {
+	for _, file := range findManifests(w.paths) {
+		modTime, err := latestModTime(append([]string{file}, manifest.Lockfiles(file)...))
+		if err != nil {
+			continue
+		}
+		if last, ok := w.modTimes[file]; ok && last.Equal(modTime) {
+			continue
+		}
+		w.modTimes[file] = modTime
+
+		deps, err := manifest.Parse(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to parse manifest %s: %v\n", file, err)
+			continue
+		}
+
+		// Prefer the exact versions a lockfile resolved over manifest ranges
+		deps, err = manifest.Resolve(file, deps)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read lockfile for %s: %v\n", file, err)
+		}
+
+		for _, dep := range deps {
+			key := dep.Ecosystem + "/" + dep.Name + "@" + dep.Version
+			if w.seen[key] {
+				continue
+			}
+			w.seen[key] = true
+			w.prefetch(dep)
+		}
+	}
+}
+
+func (w *manifestWatcher) prefetch(dep manifest.Dependency) {
+	if _, err := w.server.fetchPackage(dep.Ecosystem, dep.Name, dep.Version); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to prefetch %s package %s: %v\n", dep.Ecosystem, dep.Name, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Prefetched %s package %s\n", dep.Ecosystem, dep.Name)
+}
+
+// findManifests expands paths into manifest files; directories are searched
+// (non-recursively) for known manifest names
+func findManifests(paths []string) []string {
+	var files []string
+	for _, path := range paths {
+		path = expandHome(path)
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !stat.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && manifest.IsManifest(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	return files
+}
+
+// latestModTime returns the most recent modification time among files; the
+// first file must exist
+func latestModTime(files []string) (time.Time, error) {
+	var latest time.Time
+	for i, file := range files {
+		stat, err := os.Stat(file)
+		if err != nil {
+			if i == 0 {
+				return time.Time{}, err
+			}
+			continue
+		}
+		if stat.ModTime().After(latest) {
+			latest = stat.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/incu6us/open-context/config"
+	"github.com/incu6us/open-context/server"
+)
+
+// setupCoreTools are always enabled, since fetched documentation is read
+// through them
+var setupCoreTools = []string{"open-context_search_docs", "open-context_get_docs", "open-context_list_docs"}
+
+// setupAnswers are the settings open-context init asks for
+type setupAnswers struct {
+	cacheTTL     string
+	cacheDir     string
+	githubToken  string
+	gitlabToken  string
+	enabledTools []string
+	project      string
+	watch        bool
+}
+
+// prompter asks questions on a terminal. At the end of the input every
+// question takes its default, so init also runs unattended.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask returns the answer to a question, or def when it is left empty
+func (p *prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if errors.Is(err, io.EOF) {
+		fmt.Fprintln(p.out)
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// confirm asks a yes/no question
+func (p *prompter) confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		answer, err := p.ask(question+" ("+hint+")", "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "Please answer y or n.")
+	}
+}
+
+// runInit asks for the main settings, writes them to
+// ~/.open-context/config.yaml, optionally prefetches the docs of a
+// project's dependencies, and prints the MCP client configuration
+func runInit(in io.Reader, out io.Writer) error {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return err
+	}
+	p := &prompter{in: bufio.NewReader(in), out: out}
+
+	fmt.Fprintf(out, "This creates %s. Press Enter to keep the [default].\n\n", path)
+	if _, err := os.Stat(path); err == nil {
+		overwrite, err := p.confirm(path+" already exists. Replace it?", false)
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			fmt.Fprintln(out, "Keeping the existing config.")
+			printClientConfig(out)
+			return nil
+		}
+	}
+
+	answers, err := askSetup(p)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	// The file may hold tokens
+	if err := os.WriteFile(path, []byte(renderSetupConfig(answers)), 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	fmt.Fprintf(out, "\nWrote %s\n", path)
+	if _, err := os.Stat("config.yaml"); err == nil {
+		fmt.Fprintln(out, "Note: config.yaml in the current directory takes precedence when the server starts here.")
+	}
+
+	if answers.project != "" {
+		fmt.Fprintf(out, "\nPrefetching documentation for the dependencies in %s...\n", answers.project)
+		mcpServer, err := server.NewMCPServer()
+		if err != nil {
+			return err
+		}
+		mcpServer.PrefetchManifests([]string{answers.project})
+	}
+
+	printClientConfig(out)
+	return nil
+}
+
+func askSetup(p *prompter) (*setupAnswers, error) {
+	answers := &setupAnswers{}
+	var err error
+
+	for {
+		if answers.cacheTTL, err = p.ask(`How long to cache documentation ("24h", "7d", "1w"; "0" never expires)`, "7d"); err != nil {
+			return nil, err
+		}
+		if _, err := config.ParseDuration(answers.cacheTTL); err == nil {
+			break
+		}
+		fmt.Fprintf(p.out, "%q is not a duration.\n", answers.cacheTTL)
+	}
+
+	if answers.cacheDir, err = p.ask("Cache directory", "~/.open-context/cache"); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(answers.cacheDir, "~/") {
+		if abs, err := filepath.Abs(answers.cacheDir); err == nil {
+			answers.cacheDir = abs
+		}
+	}
+
+	fmt.Fprintln(p.out, "\nTokens raise API rate limits and reach private projects. They are stored in the config file; leave them empty to use the GITHUB_TOKEN and GITLAB_TOKEN environment variables.")
+	if answers.githubToken, err = p.ask("GitHub token", ""); err != nil {
+		return nil, err
+	}
+	if answers.gitlabToken, err = p.ask("GitLab token", ""); err != nil {
+		return nil, err
+	}
+
+	tools := server.Tools()
+	fmt.Fprintln(p.out)
+	all, err := p.confirm(fmt.Sprintf("Enable all %d tools?", len(tools)), true)
+	if err != nil {
+		return nil, err
+	}
+	if !all {
+		if answers.enabledTools, err = askTools(p, tools); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Fprintln(p.out)
+	for {
+		if answers.project, err = p.ask("Project directory to prefetch dependency docs for (empty skips)", ""); err != nil {
+			return nil, err
+		}
+		if answers.project == "" {
+			break
+		}
+		if abs, err := filepath.Abs(answers.project); err == nil {
+			answers.project = abs
+		}
+		if stat, err := os.Stat(answers.project); err == nil && stat.IsDir() {
+			break
+		}
+		fmt.Fprintf(p.out, "%s is not a directory.\n", answers.project)
+	}
+	if answers.project != "" {
+		if answers.watch, err = p.confirm("Keep watching it for new dependencies while the server runs?", true); err != nil {
+			return nil, err
+		}
+	}
+
+	return answers, nil
+}
+
+// askTools asks which tools to enable, by number or name. The core
+// documentation tools are always enabled.
+func askTools(p *prompter, tools []server.ToolInfo) ([]string, error) {
+	fmt.Fprintln(p.out)
+	for i, tool := range tools {
+		switch {
+		case tool.Deprecated && tool.ReplacedBy != "":
+			fmt.Fprintf(p.out, "%3d. %s (deprecated, use %s)\n", i+1, tool.Name, tool.ReplacedBy)
+		case tool.Deprecated:
+			fmt.Fprintf(p.out, "%3d. %s (deprecated)\n", i+1, tool.Name)
+		default:
+			fmt.Fprintf(p.out, "%3d. %s\n", i+1, tool.Name)
+		}
+	}
+
+	for {
+		answer, err := p.ask("Tools to enable (numbers or names, comma-separated; search_docs, get_docs, and list_docs are always on)", "")
+		if err != nil {
+			return nil, err
+		}
+
+		enabled := append([]string{}, setupCoreTools...)
+		var unknown []string
+		for _, field := range strings.Split(answer, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			name := ""
+			if n, err := strconv.Atoi(field); err == nil && n >= 1 && n <= len(tools) {
+				name = tools[n-1].Name
+			} else {
+				for _, tool := range tools {
+					if tool.Name == field || tool.Name == "open-context_"+field {
+						name = tool.Name
+					}
+				}
+			}
+			if name == "" {
+				unknown = append(unknown, field)
+			} else if !slices.Contains(enabled, name) {
+				enabled = append(enabled, name)
+			}
+		}
+
+		if len(unknown) == 0 {
+			return enabled, nil
+		}
+		fmt.Fprintf(p.out, "Unknown tools: %s\n", strings.Join(unknown, ", "))
+	}
+}
+
+// renderSetupConfig writes the answers as config.yaml, with the settings
+// left at their defaults commented out
+func renderSetupConfig(a *setupAnswers) string {
+	var b strings.Builder
+	b.WriteString("# Open Context configuration, created by open-context init.\n")
+	b.WriteString("# See config.yaml.example for every setting.\n\n")
+
+	b.WriteString("# How long fetched documentation is cached: \"24h\", \"7d\", \"1w\", or \"0\" to never expire\n")
+	fmt.Fprintf(&b, "cache_ttl: %s\n\n", a.cacheTTL)
+
+	b.WriteString("# Where fetched documentation is stored\n")
+	fmt.Fprintf(&b, "cache_dir: %s\n\n", strconv.Quote(a.cacheDir))
+
+	b.WriteString("# API tokens; empty values fall back to GITHUB_TOKEN and GITLAB_TOKEN\n")
+	writeSetting(&b, "github_token", a.githubToken)
+	writeSetting(&b, "gitlab_token", a.gitlabToken)
+
+	if len(a.enabledTools) > 0 {
+		b.WriteString("\n# Tools offered to MCP clients and the REST API\nenabled_tools:\n")
+		for _, tool := range a.enabledTools {
+			fmt.Fprintf(&b, "  - %s\n", tool)
+		}
+	}
+
+	if a.watch {
+		b.WriteString("\n# Projects whose new dependencies get their docs prefetched\nwatch_manifests:\n")
+		fmt.Fprintf(&b, "  - %s\n", strconv.Quote(a.project))
+	}
+	return b.String()
+}
+
+func writeSetting(b *strings.Builder, key, value string) {
+	if value == "" {
+		fmt.Fprintf(b, "# %s: \"\"\n", key)
+		return
+	}
+	fmt.Fprintf(b, "%s: %s\n", key, strconv.Quote(value))
+}
+
+// printClientConfig prints how to add this binary to MCP clients
+func printClientConfig(out io.Writer) {
+	command, err := os.Executable()
+	if err != nil {
+		command = "/path/to/open-context"
+	}
+
+	snippet, _ := json.MarshalIndent(map[string]interface{}{
+		"mcpServers": map[string]interface{}{
+			"open-context": map[string]interface{}{"command": command},
+		},
+	}, "", "  ")
+
+	fmt.Fprintln(out, "\nAdd open-context to your MCP client.")
+	fmt.Fprintln(out, "\nClaude Desktop, Cursor, and other clients reading mcpServers:")
+	fmt.Fprintf(out, "\n%s\n", snippet)
+	fmt.Fprintln(out, "\nClaude Code:")
+	fmt.Fprintf(out, "\n  claude mcp add open-context -- %s\n", command)
+}
@@ -0,0 +1,292 @@
+// Package site renders the documentation cache as a static HTML website with
+// an index, a page per namespace, a page per entry, and client-side search.
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/incu6us/open-context/markdown"
+	"github.com/incu6us/open-context/provider"
+)
+
+// searchTextLimit bounds how much of each entry's text goes into the search
+// index, keeping it small enough to load with every page
+const searchTextLimit = 2000
+
+// Entry is one cached document
+type Entry struct {
+	Namespace   string
+	Title       string
+	Description string
+	// Path is the page location relative to the site root, slash-separated
+	Path string
+	Body string
+}
+
+// Namespace groups the entries cached for one language or tool
+type Namespace struct {
+	Name    string
+	Entries []*Entry
+}
+
+// Stats summarizes an export
+type Stats struct {
+	Namespaces int
+	Entries    int
+}
+
+// Export renders every entry under cacheDir into outDir
+func Export(cacheDir, outDir string) (Stats, error) {
+	namespaces, err := collect(cacheDir)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return Stats{}, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	w := &writer{outDir: outDir}
+	stats := Stats{Namespaces: len(namespaces)}
+
+	w.page("index.html", "index", map[string]interface{}{"Namespaces": namespaces})
+	for _, ns := range namespaces {
+		w.page(path.Join(ns.Name, "index.html"), "namespace", map[string]interface{}{"Namespace": ns})
+		for _, e := range ns.Entries {
+			w.page(e.Path, "entry", map[string]interface{}{
+				"Entry": e,
+				"HTML":  template.HTML(markdown.ToHTML(e.Body)),
+			})
+			stats.Entries++
+		}
+	}
+
+	w.file("style.css", []byte(styleCSS))
+	w.file("search.js", []byte(searchJS))
+	w.searchIndex(namespaces)
+
+	return stats, w.err
+}
+
+// collect reads the cache: markdown entries with YAML frontmatter and the
+// JSON topics of bundled documentation
+func collect(cacheDir string) ([]*Namespace, error) {
+	byName := make(map[string]*Namespace)
+
+	err := filepath.WalkDir(cacheDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Hidden directories, such as the cache trash, are not documentation
+			if p != cacheDir && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(cacheDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		nsName, _, ok := strings.Cut(rel, "/")
+		if !ok {
+			// Files at the cache root (index.json) are not documentation
+			return nil
+		}
+
+		var entry *Entry
+		switch {
+		case strings.HasSuffix(rel, ".md"):
+			entry, err = readMarkdownEntry(p)
+		case strings.HasSuffix(rel, ".json") && path.Base(path.Dir(rel)) == "topics":
+			entry, err = readTopicEntry(p)
+		default:
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", rel, err)
+			return nil
+		}
+
+		entry.Namespace = nsName
+		entry.Path = strings.TrimSuffix(rel, path.Ext(rel)) + ".html"
+		if entry.Title == "" {
+			entry.Title = strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+		}
+
+		ns := byName[nsName]
+		if ns == nil {
+			ns = &Namespace{Name: nsName}
+			byName[nsName] = ns
+		}
+		ns.Entries = append(ns.Entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	namespaces := make([]*Namespace, 0, len(byName))
+	for _, ns := range byName {
+		sort.Slice(ns.Entries, func(i, j int) bool {
+			return strings.ToLower(ns.Entries[i].Title) < strings.ToLower(ns.Entries[j].Title)
+		})
+		namespaces = append(namespaces, ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces, nil
+}
+
+// titleKeys are the frontmatter fields fetchers use to name an entry
+var titleKeys = []string{"title", "name", "importPath", "repository", "collection", "symbol", "path", "version"}
+
+func readMarkdownEntry(p string) (*Entry, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{Body: string(data)}
+	parts := strings.SplitN(string(data), "---", 3)
+	if strings.HasPrefix(string(data), "---") && len(parts) == 3 {
+		var meta map[string]interface{}
+		if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+			return nil, fmt.Errorf("invalid frontmatter: %w", err)
+		}
+		entry.Body = strings.TrimSpace(parts[2])
+
+		for _, key := range titleKeys {
+			if v, ok := meta[key].(string); ok && v != "" {
+				entry.Title = v
+				break
+			}
+		}
+		for _, key := range []string{"description", "summary", "synopsis"} {
+			if v, ok := meta[key].(string); ok && v != "" {
+				entry.Description = v
+				break
+			}
+		}
+	}
+
+	// The first heading names the entry better than a bare version
+	for _, line := range strings.SplitN(entry.Body, "\n", 5) {
+		if strings.HasPrefix(line, "# ") {
+			entry.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			break
+		}
+	}
+
+	return entry, nil
+}
+
+func readTopicEntry(p string) (*Entry, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var topic struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Content     string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &topic); err != nil {
+		return nil, err
+	}
+
+	body := topic.Content
+	if !strings.HasPrefix(strings.TrimSpace(body), "# ") && topic.Title != "" {
+		body = "# " + topic.Title + "\n\n" + body
+	}
+	return &Entry{Title: topic.Title, Description: topic.Description, Body: body}, nil
+}
+
+// writer writes site files, keeping the first error
+type writer struct {
+	outDir string
+	err    error
+}
+
+func (w *writer) file(rel string, data []byte) {
+	if w.err != nil {
+		return
+	}
+	target := filepath.Join(w.outDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		w.err = fmt.Errorf("failed to create directory for %s: %w", rel, err)
+		return
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		w.err = fmt.Errorf("failed to write %s: %w", rel, err)
+	}
+}
+
+func (w *writer) page(rel, name string, data map[string]interface{}) {
+	if w.err != nil {
+		return
+	}
+	// Links are relative so the site works from any path, including file://
+	data["Root"] = strings.Repeat("../", strings.Count(rel, "/"))
+
+	var b strings.Builder
+	if err := templates.ExecuteTemplate(&b, name, data); err != nil {
+		w.err = fmt.Errorf("failed to render %s: %w", rel, err)
+		return
+	}
+	w.file(rel, []byte(b.String()))
+}
+
+// searchIndex writes the index as a script rather than JSON, since browsers
+// refuse to fetch local JSON files when the site is opened from disk
+func (w *writer) searchIndex(namespaces []*Namespace) {
+	type item struct {
+		Title       string `json:"t"`
+		Namespace   string `json:"n"`
+		Path        string `json:"p"`
+		Description string `json:"d,omitempty"`
+		Text        string `json:"x"`
+		// Keywords come from the whole body, so they also cover text past
+		// searchTextLimit
+		Keywords []string `json:"k,omitempty"`
+	}
+
+	var items []item
+	for _, ns := range namespaces {
+		for _, e := range ns.Entries {
+			text := strings.Join(strings.Fields(e.Body), " ")
+			if len(text) > searchTextLimit {
+				text = strings.ToValidUTF8(text[:searchTextLimit], "")
+			}
+			items = append(items, item{
+				Title:       e.Title,
+				Namespace:   e.Namespace,
+				Path:        e.Path,
+				Description: e.Description,
+				Text:        text,
+				Keywords:    provider.ExtractKeywords(e.Body),
+			})
+		}
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.file("search-index.js", []byte("window.SEARCH_INDEX = "+string(data)+";\n"))
+}
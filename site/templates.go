@@ -0,0 +1,131 @@
+package site
+
+import "html/template"
+
+var templates = template.Must(template.New("site").Parse(`
+{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.}} · Open Context</title>
+{{end}}
+
+{{define "header"}}<link rel="stylesheet" href="{{.Root}}style.css">
+<script src="{{.Root}}search-index.js" defer></script>
+<script src="{{.Root}}search.js" defer></script>
+</head>
+<body data-root="{{.Root}}">
+<header>
+<a class="home" href="{{.Root}}index.html">Open Context</a>
+<input id="search" type="search" placeholder="Search documentation" autocomplete="off">
+</header>
+<ul id="results" hidden></ul>
+<main>
+{{end}}
+
+{{define "footer"}}</main>
+</body>
+</html>
+{{end}}
+
+{{define "index"}}{{template "head" "Documentation"}}{{template "header" .}}
+<h1>Documentation</h1>
+{{if not .Namespaces}}<p>The cache is empty. Fetch some documentation with the MCP tools, then export again.</p>{{end}}
+<ul class="namespaces">
+{{range .Namespaces}}<li><a href="{{.Name}}/index.html">{{.Name}}</a> <span class="count">{{len .Entries}}</span></li>
+{{end}}</ul>
+{{template "footer"}}{{end}}
+
+{{define "namespace"}}{{template "head" .Namespace.Name}}{{template "header" .}}
+<nav><a href="{{.Root}}index.html">Documentation</a> / {{.Namespace.Name}}</nav>
+<h1>{{.Namespace.Name}}</h1>
+<ul class="entries">
+{{$root := .Root}}{{range .Namespace.Entries}}<li><a href="{{$root}}{{.Path}}">{{.Title}}</a>{{if .Description}} <span class="description">{{.Description}}</span>{{end}}</li>
+{{end}}</ul>
+{{template "footer"}}{{end}}
+
+{{define "entry"}}{{template "head" .Entry.Title}}{{template "header" .}}
+<nav><a href="{{.Root}}index.html">Documentation</a> / <a href="{{.Root}}{{.Entry.Namespace}}/index.html">{{.Entry.Namespace}}</a></nav>
+<article>
+{{.HTML}}
+</article>
+{{template "footer"}}{{end}}
+`))
+
+const styleCSS = `:root { color-scheme: light dark; --accent: #2563eb; --muted: #6b7280; --border: #d1d5db; }
+body { margin: 0; font: 16px/1.6 system-ui, sans-serif; }
+header { display: flex; gap: 1rem; align-items: center; padding: .75rem 1.5rem; border-bottom: 1px solid var(--border); position: sticky; top: 0; background: Canvas; }
+header .home { font-weight: 600; text-decoration: none; color: inherit; }
+#search { flex: 1; max-width: 32rem; padding: .4rem .6rem; border: 1px solid var(--border); border-radius: 6px; font: inherit; }
+#results { list-style: none; margin: 0; padding: .5rem 1.5rem; border-bottom: 1px solid var(--border); max-height: 60vh; overflow: auto; }
+#results li { padding: .3rem 0; }
+main { max-width: 60rem; margin: 0 auto; padding: 1rem 1.5rem 3rem; }
+nav { color: var(--muted); font-size: .9rem; }
+a { color: var(--accent); }
+.count, .description, .namespace { color: var(--muted); font-size: .9rem; }
+.namespaces { columns: 3 12rem; }
+pre { overflow: auto; padding: .75rem; border-radius: 6px; background: color-mix(in srgb, CanvasText 6%, Canvas); }
+code { font: .9em ui-monospace, monospace; }
+table { border-collapse: collapse; display: block; overflow: auto; }
+th, td { border: 1px solid var(--border); padding: .3rem .6rem; text-align: left; vertical-align: top; }
+blockquote { margin: 0; padding-left: 1rem; border-left: 3px solid var(--border); color: var(--muted); }
+`
+
+const searchJS = `(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var root = document.body.dataset.root || "";
+  var index = window.SEARCH_INDEX || [];
+
+  function score(item, terms) {
+    var title = item.t.toLowerCase(), desc = (item.d || "").toLowerCase(), text = item.x.toLowerCase();
+    var keywords = (item.k || []).join(" ");
+    var total = 0;
+    for (var i = 0; i < terms.length; i++) {
+      var s = 0;
+      if (title.indexOf(terms[i]) >= 0) s += 10;
+      if (item.n.indexOf(terms[i]) >= 0) s += 5;
+      if (desc.indexOf(terms[i]) >= 0) s += 3;
+      if (keywords.indexOf(terms[i]) >= 0) s += 2;
+      if (text.indexOf(terms[i]) >= 0) s += 1;
+      if (s === 0) return 0;
+      total += s;
+    }
+    return total;
+  }
+
+  input.addEventListener("input", function () {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.textContent = "";
+    results.hidden = terms.length === 0;
+    if (results.hidden) return;
+
+    var matches = [];
+    for (var i = 0; i < index.length; i++) {
+      var s = score(index[i], terms);
+      if (s > 0) matches.push({ item: index[i], score: s });
+    }
+    matches.sort(function (a, b) { return b.score - a.score; });
+
+    if (matches.length === 0) {
+      var none = document.createElement("li");
+      none.textContent = "No results";
+      results.appendChild(none);
+      return;
+    }
+    matches.slice(0, 50).forEach(function (m) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = root + m.item.p;
+      a.textContent = m.item.t;
+      var ns = document.createElement("span");
+      ns.className = "namespace";
+      ns.textContent = " " + m.item.n;
+      li.appendChild(a);
+      li.appendChild(ns);
+      results.appendChild(li);
+    });
+  });
+})();
+`
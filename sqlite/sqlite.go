@@ -0,0 +1,435 @@
+// Package sqlite reads the rows of tables in SQLite database files. It
+// understands as much of the file format as read-only indexes such as those
+// of Dash docsets need: table b-trees, overflow pages, and UTF-8 text.
+// Indexes, WAL files, and writing are not supported.
+package sqlite
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	headerSize = 100
+	magic      = "SQLite format 3\x00"
+
+	pageInteriorTable = 0x05
+	pageLeafTable     = 0x0d
+)
+
+// Row maps the column names of a table to the values of one row: int64,
+// float64, string, []byte, or nil
+type Row map[string]interface{}
+
+// DB is an SQLite database file opened for reading
+type DB struct {
+	f        *os.File
+	pageSize int
+	// usable is the page size less the bytes reserved at the end of each
+	// page
+	usable int
+	pages  int
+}
+
+// column is a column of a table; rowid is set for an INTEGER PRIMARY KEY,
+// which the file stores as the row ID rather than in the record
+type column struct {
+	name  string
+	rowid bool
+}
+
+// Open opens the database file at path
+func Open(path string) (*DB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(f, header); err != nil || string(header[:16]) != magic {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s is not an SQLite database", path)
+	}
+	if header[56+3] > 1 {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s uses UTF-16 text, which is not supported", path)
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(header[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s has an invalid page size %d", path, pageSize)
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &DB{
+		f:        f,
+		pageSize: pageSize,
+		usable:   pageSize - int(header[20]),
+		pages:    int(stat.Size() / int64(pageSize)),
+	}, nil
+}
+
+// Close closes the database file
+func (db *DB) Close() error {
+	return db.f.Close()
+}
+
+// Tables returns the names of the tables in the database
+func (db *DB) Tables() ([]string, error) {
+	var names []string
+	err := db.scanSchema(func(kind, name string, _ int, _ string) {
+		if kind == "table" {
+			names = append(names, name)
+		}
+	})
+	return names, err
+}
+
+// Scan calls fn with each row of table, in row ID order, stopping at the
+// first error fn returns
+func (db *DB) Scan(table string, fn func(Row) error) error {
+	root, columns, err := db.table(table)
+	if err != nil {
+		return err
+	}
+
+	return db.walk(root, 0, func(rowid int64, payload []byte) error {
+		values, err := decodeRecord(payload)
+		if err != nil {
+			return fmt.Errorf("failed to decode row %d of %s: %w", rowid, table, err)
+		}
+		row := make(Row, len(columns))
+		for i, c := range columns {
+			switch {
+			case c.rowid:
+				row[c.name] = rowid
+			case i < len(values):
+				row[c.name] = values[i]
+			default:
+				// Columns added after the row was written
+				row[c.name] = nil
+			}
+		}
+		return fn(row)
+	})
+}
+
+// table finds the root page and columns of a table in sqlite_master
+func (db *DB) table(name string) (int, []column, error) {
+	root, sql := 0, ""
+	err := db.scanSchema(func(kind, tableName string, rootPage int, tableSQL string) {
+		if kind == "table" && strings.EqualFold(tableName, name) {
+			root, sql = rootPage, tableSQL
+		}
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	if root == 0 {
+		return 0, nil, fmt.Errorf("table %s not found", name)
+	}
+
+	columns := parseColumns(sql)
+	if len(columns) == 0 {
+		return 0, nil, fmt.Errorf("failed to parse the columns of %s", name)
+	}
+	return root, columns, nil
+}
+
+// scanSchema calls fn with the type, name, root page, and SQL of each
+// object in sqlite_master, which is rooted at page 1
+func (db *DB) scanSchema(fn func(kind, name string, rootPage int, sql string)) error {
+	return db.walk(1, 0, func(_ int64, payload []byte) error {
+		values, err := decodeRecord(payload)
+		if err != nil {
+			return fmt.Errorf("failed to decode schema: %w", err)
+		}
+		if len(values) < 5 {
+			return nil
+		}
+		kind, _ := values[0].(string)
+		name, _ := values[1].(string)
+		root, _ := values[3].(int64)
+		sql, _ := values[4].(string)
+		fn(kind, name, int(root), sql)
+		return nil
+	})
+}
+
+// walk visits the rows of the table b-tree rooted at page in order
+func (db *DB) walk(page, depth int, fn func(rowid int64, payload []byte) error) error {
+	// A b-tree deeper than this is a page cycle in a corrupt file
+	if depth > 64 {
+		return errors.New("table b-tree is too deep")
+	}
+
+	data, err := db.page(page)
+	if err != nil {
+		return err
+	}
+	header := data
+	if page == 1 {
+		header = data[headerSize:]
+	}
+
+	cells := int(binary.BigEndian.Uint16(header[3:5]))
+	switch header[0] {
+	case pageLeafTable:
+		pointers := header[8:]
+		for i := 0; i < cells; i++ {
+			offset := int(binary.BigEndian.Uint16(pointers[i*2:]))
+			rowid, payload, err := db.leafCell(data, offset)
+			if err != nil {
+				return fmt.Errorf("page %d: %w", page, err)
+			}
+			if err := fn(rowid, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case pageInteriorTable:
+		pointers := header[12:]
+		for i := 0; i < cells; i++ {
+			offset := int(binary.BigEndian.Uint16(pointers[i*2:]))
+			if offset+4 > len(data) {
+				return fmt.Errorf("page %d: cell out of bounds", page)
+			}
+			child := int(binary.BigEndian.Uint32(data[offset:]))
+			if err := db.walk(child, depth+1, fn); err != nil {
+				return err
+			}
+		}
+		return db.walk(int(binary.BigEndian.Uint32(header[8:12])), depth+1, fn)
+
+	default:
+		return fmt.Errorf("page %d is not a table b-tree page (type %#x)", page, header[0])
+	}
+}
+
+// leafCell reads the row ID and payload of a table leaf cell, following
+// overflow pages for payloads that do not fit the page
+func (db *DB) leafCell(data []byte, offset int) (int64, []byte, error) {
+	if offset >= len(data) {
+		return 0, nil, errors.New("cell out of bounds")
+	}
+	size, n := varint(data[offset:])
+	offset += n
+	rowid, n := varint(data[offset:])
+	offset += n
+
+	total := int(size)
+	local := db.localPayload(total)
+	if total < 0 || offset+local > len(data) {
+		return 0, nil, errors.New("payload out of bounds")
+	}
+	payload := make([]byte, 0, total)
+	payload = append(payload, data[offset:offset+local]...)
+	if local == total {
+		return int64(rowid), payload, nil
+	}
+
+	if offset+local+4 > len(data) {
+		return 0, nil, errors.New("overflow pointer out of bounds")
+	}
+	next := int(binary.BigEndian.Uint32(data[offset+local:]))
+	for visited := 0; len(payload) < total; visited++ {
+		if next == 0 || visited > db.pages {
+			return 0, nil, errors.New("overflow chain is broken")
+		}
+		overflow, err := db.page(next)
+		if err != nil {
+			return 0, nil, err
+		}
+		next = int(binary.BigEndian.Uint32(overflow))
+		chunk := overflow[4:db.usable]
+		if remaining := total - len(payload); len(chunk) > remaining {
+			chunk = chunk[:remaining]
+		}
+		payload = append(payload, chunk...)
+	}
+	return int64(rowid), payload, nil
+}
+
+// localPayload is how many bytes of a table leaf payload of size total are
+// stored on the page, as set out in the file format's b-tree section
+func (db *DB) localPayload(total int) int {
+	maxLocal := db.usable - 35
+	if total <= maxLocal {
+		return total
+	}
+	minLocal := (db.usable-12)*32/255 - 23
+	local := minLocal + (total-minLocal)%(db.usable-4)
+	if local <= maxLocal {
+		return local
+	}
+	return minLocal
+}
+
+// page reads page n, numbered from 1
+func (db *DB) page(n int) ([]byte, error) {
+	if n < 1 || n > db.pages {
+		return nil, fmt.Errorf("page %d out of range", n)
+	}
+	data := make([]byte, db.pageSize)
+	if _, err := db.f.ReadAt(data, int64(n-1)*int64(db.pageSize)); err != nil {
+		return nil, fmt.Errorf("failed to read page %d: %w", n, err)
+	}
+	return data, nil
+}
+
+// decodeRecord decodes the values of a record
+func decodeRecord(payload []byte) ([]interface{}, error) {
+	headerLen, n := varint(payload)
+	if n == 0 || int(headerLen) > len(payload) {
+		return nil, errors.New("invalid record header")
+	}
+
+	var types []uint64
+	for offset := n; offset < int(headerLen); {
+		t, n := varint(payload[offset:headerLen])
+		if n == 0 {
+			return nil, errors.New("invalid record header")
+		}
+		types = append(types, t)
+		offset += n
+	}
+
+	values := make([]interface{}, len(types))
+	body := payload[headerLen:]
+	for i, t := range types {
+		size := serialSize(t)
+		if size > len(body) {
+			return nil, errors.New("record is truncated")
+		}
+		field := body[:size]
+		body = body[size:]
+
+		switch {
+		case t == 0:
+			values[i] = nil
+		case t <= 6:
+			values[i] = bigEndianInt(field)
+		case t == 7:
+			values[i] = math.Float64frombits(binary.BigEndian.Uint64(field))
+		case t == 8:
+			values[i] = int64(0)
+		case t == 9:
+			values[i] = int64(1)
+		case t >= 12 && t%2 == 0:
+			values[i] = append([]byte(nil), field...)
+		case t >= 13:
+			values[i] = string(field)
+		default:
+			return nil, fmt.Errorf("unknown serial type %d", t)
+		}
+	}
+	return values, nil
+}
+
+// serialSize is the size in bytes of a value of serial type t
+func serialSize(t uint64) int {
+	switch {
+	case t <= 4:
+		return [...]int{0, 1, 2, 3, 4}[t]
+	case t == 5:
+		return 6
+	case t == 6, t == 7:
+		return 8
+	case t < 12:
+		return 0
+	default:
+		return int((t - 12) / 2)
+	}
+}
+
+// bigEndianInt decodes a signed big-endian integer of 1 to 8 bytes
+func bigEndianInt(b []byte) int64 {
+	var v int64
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v = -1
+	}
+	for _, c := range b {
+		v = v<<8 | int64(c)
+	}
+	return v
+}
+
+// varint decodes an SQLite varint, returning it and its length, or a length
+// of 0 if b is too short
+func varint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(b) {
+			return 0, 0
+		}
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, 9
+}
+
+// parseColumns reads the column names of a CREATE TABLE statement
+func parseColumns(sql string) []column {
+	start, end := strings.Index(sql, "("), strings.LastIndex(sql, ")")
+	if start < 0 || end <= start {
+		return nil
+	}
+
+	var columns []column
+	for _, def := range splitTopLevel(sql[start+1 : end]) {
+		fields := strings.Fields(def)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+			continue
+		}
+		upper := strings.ToUpper(strings.Join(fields, " "))
+		columns = append(columns, column{
+			name:  strings.Trim(fields[0], "\"'`[]"),
+			rowid: strings.Contains(upper, "INTEGER PRIMARY KEY"),
+		})
+	}
+	return columns
+}
+
+// splitTopLevel splits s at commas outside parentheses
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
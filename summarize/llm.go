@@ -0,0 +1,205 @@
+package summarize
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/incu6us/open-context/config"
+)
+
+const (
+	requestTimeout = 120 * time.Second
+
+	// defaultMaxTokens bounds the length of an LLM summary
+	defaultMaxTokens = 512
+
+	// maxInputChars truncates long topics before they are sent
+	maxInputChars = 100000
+
+	prompt = "Summarize the following documentation for a developer who needs a short answer. " +
+		"Keep the key facts, API names, versions, and caveats. Reply in markdown, without a preamble.\n\n"
+)
+
+// providers are the supported remote APIs
+var providers = map[string]struct {
+	endpoint string
+	model    string
+	keyEnv   string
+}{
+	"anthropic": {"https://api.anthropic.com/v1/messages", "claude-haiku-4-5", "ANTHROPIC_API_KEY"},
+	"openai":    {"https://api.openai.com/v1/chat/completions", "gpt-4o-mini", "OPENAI_API_KEY"},
+}
+
+// Client summarizes with a remote LLM API
+type Client struct {
+	client    *http.Client
+	provider  string
+	endpoint  string
+	model     string
+	apiKey    string
+	maxTokens int
+}
+
+// NewClient creates a client from the summarizer section of config.yaml
+func NewClient(cfg config.Summarizer) (*Client, error) {
+	p, ok := providers[cfg.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported summarizer provider: %s (must be 'anthropic' or 'openai')", cfg.Provider)
+	}
+
+	c := &Client{
+		client:    &http.Client{Timeout: requestTimeout},
+		provider:  cfg.Provider,
+		endpoint:  firstNonEmpty(cfg.Endpoint, p.endpoint),
+		model:     firstNonEmpty(cfg.Model, p.model),
+		apiKey:    firstNonEmpty(cfg.APIKey, os.Getenv(p.keyEnv)),
+		maxTokens: cfg.MaxTokens,
+	}
+	if c.maxTokens <= 0 {
+		c.maxTokens = defaultMaxTokens
+	}
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("an API key is required for %s summaries (set api_key or %s)", cfg.Provider, p.keyEnv)
+	}
+	return c, nil
+}
+
+// Model returns the LLM model name
+func (c *Client) Model() string {
+	return c.model
+}
+
+// Summarize asks the LLM for a summary of content
+func (c *Client) Summarize(content string) (string, error) {
+	if len(content) > maxInputChars {
+		content = strings.ToValidUTF8(content[:maxInputChars], "")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model":      c.model,
+		"max_tokens": c.maxTokens,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt + content},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "open-context-mcp-server")
+	if c.provider == "anthropic" {
+		req.Header.Set("x-api-key", c.apiKey)
+		req.Header.Set("anthropic-version", "2023-06-01")
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request summary: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("summarizer API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	// Anthropic returns content blocks, OpenAI-compatible APIs choices
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse summary: %w", err)
+	}
+
+	var summary strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			summary.WriteString(block.Text)
+		}
+	}
+	for _, choice := range result.Choices {
+		summary.WriteString(choice.Message.Content)
+	}
+	if strings.TrimSpace(summary.String()) == "" {
+		return "", errors.New("summarizer API returned an empty summary")
+	}
+	return strings.TrimSpace(summary.String()) + "\n", nil
+}
+
+// Cache stores summaries on disk under the hash of the model and content,
+// so a topic is only summarized again after it changes
+type Cache struct {
+	summarizer Summarizer
+	dir        string
+	model      string
+}
+
+// NewCache wraps summarizer with a cache under dir. Summaries from
+// different models are kept apart.
+func NewCache(summarizer Summarizer, dir, model string) *Cache {
+	return &Cache{summarizer: summarizer, dir: dir, model: model}
+}
+
+// Summarize returns the cached summary of content, summarizing it if there
+// is none
+func (c *Cache) Summarize(content string) (string, error) {
+	path := c.path(content)
+	if data, err := os.ReadFile(path); err == nil {
+		return string(data), nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring unreadable cached summary: %v\n", err)
+	}
+
+	summary, err := c.summarizer.Summarize(content)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache summary: %v\n", err)
+	} else if err := os.WriteFile(path, []byte(summary), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache summary: %v\n", err)
+	}
+	return summary, nil
+}
+
+func (c *Cache) path(content string) string {
+	sum := sha256.Sum256([]byte(c.model + "\x00" + content))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key[:2], key+".md")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,172 @@
+// Package summarize shortens long documentation topics, either extractively
+// with heuristics or with a remote LLM.
+package summarize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// defaultMaxChars bounds an extractive summary
+	defaultMaxChars = 4000
+
+	// maxParagraphChars bounds the paragraph kept from one section
+	maxParagraphChars = 400
+
+	// maxBullets is how many items of a list-heavy section are kept
+	maxBullets = 5
+
+	// minBulletDensity is the share of a section's lines that must be list
+	// items for its list to be kept instead of its first paragraph
+	minBulletDensity = 0.5
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*$`)
+	bulletPattern   = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+`)
+	sentencePattern = regexp.MustCompile(`[.!?](\s|$)`)
+)
+
+// Summarizer shortens the markdown content of a documentation topic
+type Summarizer interface {
+	Summarize(content string) (string, error)
+}
+
+// Extractive summarizes markdown without a remote service. It keeps every
+// heading, the first paragraph of each section, and the leading items of
+// sections that are mostly lists, leaving out code blocks and tables.
+type Extractive struct {
+	// MaxChars bounds the summary (default 4000); sections past it are
+	// left out and counted in a closing note
+	MaxChars int
+}
+
+// section is a heading and the lines up to the next one
+type section struct {
+	heading string
+	lines   []string
+}
+
+// Summarize returns an extractive summary of content
+func (e Extractive) Summarize(content string) (string, error) {
+	maxChars := e.MaxChars
+	if maxChars <= 0 {
+		maxChars = defaultMaxChars
+	}
+
+	var b strings.Builder
+	sections := splitSections(content)
+	for i, s := range sections {
+		part := s.summary()
+		if part == "" {
+			continue
+		}
+		if b.Len() > 0 && b.Len()+len(part) > maxChars {
+			fmt.Fprintf(&b, "_%d more sections left out of the summary._\n", len(sections)-i)
+			break
+		}
+		b.WriteString(part)
+	}
+	return strings.TrimSpace(b.String()) + "\n", nil
+}
+
+// splitSections splits markdown at its headings, dropping fenced code blocks
+func splitSections(content string) []section {
+	sections := []section{{}}
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if headingPattern.MatchString(trimmed) {
+			sections = append(sections, section{heading: trimmed})
+			continue
+		}
+		last := &sections[len(sections)-1]
+		last.lines = append(last.lines, line)
+	}
+	return sections
+}
+
+// summary renders the heading of a section and what is kept of its body
+func (s section) summary() string {
+	var b strings.Builder
+	if s.heading != "" {
+		b.WriteString(s.heading + "\n\n")
+	}
+
+	var bullets []string
+	text := 0
+	for _, line := range s.lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		text++
+		// Only top-level items; nested ones are details
+		if m := bulletPattern.FindStringSubmatch(line); m != nil && m[1] == "" {
+			bullets = append(bullets, trimmed)
+		}
+	}
+
+	if text > 0 && float64(len(bullets))/float64(text) >= minBulletDensity {
+		for i, bullet := range bullets {
+			if i == maxBullets {
+				fmt.Fprintf(&b, "- _%d more items_\n", len(bullets)-maxBullets)
+				break
+			}
+			b.WriteString(bullet + "\n")
+		}
+		b.WriteString("\n")
+	} else if paragraph := firstParagraph(s.lines); paragraph != "" {
+		b.WriteString(paragraph + "\n\n")
+	}
+
+	if b.Len() == 0 {
+		return ""
+	}
+	return b.String()
+}
+
+// firstParagraph returns the first prose paragraph of lines. One longer
+// than maxParagraphChars is cut at a sentence end past half that length.
+func firstParagraph(lines []string) string {
+	var paragraph []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		// Tables, quotes, images, HTML, and lists are not prose
+		if (len(paragraph) == 0 && strings.ContainsAny(trimmed[:1], "|><!")) || bulletPattern.MatchString(trimmed) {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+
+	text := strings.Join(paragraph, " ")
+	if len(text) <= maxParagraphChars {
+		return text
+	}
+	if loc := sentencePattern.FindStringIndex(text[maxParagraphChars/2:]); loc != nil && maxParagraphChars/2+loc[0] < maxParagraphChars*2 {
+		return text[:maxParagraphChars/2+loc[0]+1]
+	}
+	cut := strings.LastIndex(text[:maxParagraphChars], " ")
+	if cut <= 0 {
+		cut = maxParagraphChars
+	}
+	return strings.ToValidUTF8(text[:cut], "") + "..."
+}